@@ -0,0 +1,123 @@
+import React, { useCallback, useState } from 'react'
+
+import { useLocation } from 'react-router-dom-v5-compat'
+
+import { asError, logger } from '@sourcegraph/common'
+import { Label, Button, LoadingSpinner, Text, Input, Form } from '@sourcegraph/wildcard'
+
+import { AuthProvider, SourcegraphContext } from '../jscontext'
+import { eventLogger } from '../tracking/eventLogger'
+
+import { getReturnTo, PasswordInput } from './SignInSignUpCommon'
+
+interface Props {
+    /** The LDAP auth provider to sign in with. */
+    provider: AuthProvider
+    /** Distinguishes the form's fields when there are multiple sign-in forms on the page. */
+    idPrefix: string
+    onAuthError: (error: Error | null) => void
+    context: Pick<SourcegraphContext, 'xhrHeaders'>
+}
+
+/**
+ * The form for signing in with a username and password that are checked against an LDAP directory.
+ */
+export const LdapSignInForm: React.FunctionComponent<React.PropsWithChildren<Props>> = ({
+    provider,
+    idPrefix,
+    onAuthError,
+    context,
+}) => {
+    const location = useLocation()
+    const [username, setUsername] = useState('')
+    const [password, setPassword] = useState('')
+    const [loading, setLoading] = useState(false)
+
+    const onUsernameFieldChange = useCallback((event: React.ChangeEvent<HTMLInputElement>): void => {
+        setUsername(event.target.value)
+    }, [])
+
+    const onPasswordFieldChange = useCallback((event: React.ChangeEvent<HTMLInputElement>): void => {
+        setPassword(event.target.value)
+    }, [])
+
+    const handleSubmit = useCallback(
+        (event: React.FormEvent<HTMLFormElement>): void => {
+            event.preventDefault()
+            if (loading) {
+                return
+            }
+
+            setLoading(true)
+            eventLogger.log('InitiateSignIn')
+            fetch(provider.authenticationURL, {
+                credentials: 'same-origin',
+                method: 'POST',
+                headers: {
+                    ...context.xhrHeaders,
+                    Accept: 'application/json',
+                    'Content-Type': 'application/json',
+                },
+                body: JSON.stringify({ username, password }),
+            })
+                .then(async response => {
+                    if (response.status === 200) {
+                        if (new URLSearchParams(location.search).get('close') === 'true') {
+                            window.close()
+                        } else {
+                            const returnTo = getReturnTo(location)
+                            window.location.replace(returnTo)
+                        }
+                    } else if (response.status === 401) {
+                        throw new Error('User or password was incorrect')
+                    } else {
+                        const message = (await response.text()).trim()
+                        throw new Error(message || 'Unknown Error')
+                    }
+                })
+                .catch(error => {
+                    logger.error('Auth error:', error)
+                    setLoading(false)
+                    onAuthError(asError(error))
+                })
+        },
+        [provider, username, loading, location, password, onAuthError, context]
+    )
+
+    return (
+        <Form onSubmit={handleSubmit}>
+            <Input
+                id={`${idPrefix}-username`}
+                label={<Text alignment="left">{provider.displayName} username</Text>}
+                onChange={onUsernameFieldChange}
+                required={true}
+                value={username}
+                disabled={loading}
+                autoCapitalize="off"
+                className="form-group"
+                autoComplete="username"
+            />
+
+            <div className="form-group d-flex flex-column align-content-start">
+                <Label htmlFor={`${idPrefix}-password`} className="align-self-start">
+                    Password
+                </Label>
+                <PasswordInput
+                    id={`${idPrefix}-password`}
+                    onChange={onPasswordFieldChange}
+                    value={password}
+                    required={true}
+                    disabled={loading}
+                    autoComplete="current-password"
+                    placeholder=" "
+                />
+            </div>
+
+            <div className="form-group">
+                <Button display="block" type="submit" disabled={loading} variant="primary">
+                    {loading ? <LoadingSpinner /> : `Sign in with ${provider.displayName}`}
+                </Button>
+            </div>
+        </Form>
+    )
+}
@@ -14,6 +14,7 @@ import { AuthProvider, SourcegraphContext } from '../jscontext'
 import { eventLogger } from '../tracking/eventLogger'
 
 import { SourcegraphIcon } from './icons'
+import { LdapSignInForm } from './LdapSignInForm'
 import { OrDivider } from './OrDivider'
 import { getReturnTo } from './SignInSignUpCommon'
 import { UsernamePasswordSignInForm } from './UsernamePasswordSignInForm'
@@ -54,10 +55,14 @@ export const SignInPage: React.FunctionComponent<React.PropsWithChildren<SignInP
         return true
     }
 
-    const thirdPartyAuthProviders = nonBuiltinAuthProviders.filter(provider => shouldShowProvider(provider))
+    // LDAP providers take a username and password, so they get their own form rather than a button.
+    const [ldapAuthProviders, thirdPartyAuthProviders] = partition(
+        nonBuiltinAuthProviders.filter(provider => shouldShowProvider(provider)),
+        provider => provider.serviceType === 'ldap'
+    )
 
     const body =
-        !builtInAuthProvider && thirdPartyAuthProviders.length === 0 ? (
+        !builtInAuthProvider && ldapAuthProviders.length === 0 && thirdPartyAuthProviders.length === 0 ? (
             <Alert className="mt-3" variant="info">
                 No authentication providers are available. Contact a site administrator for help.
             </Alert>
@@ -75,10 +80,28 @@ export const SignInPage: React.FunctionComponent<React.PropsWithChildren<SignInP
                         <UsernamePasswordSignInForm
                             {...props}
                             onAuthError={setError}
-                            noThirdPartyProviders={thirdPartyAuthProviders.length === 0}
+                            noThirdPartyProviders={
+                                ldapAuthProviders.length === 0 && thirdPartyAuthProviders.length === 0
+                            }
                         />
                     )}
-                    {builtInAuthProvider && thirdPartyAuthProviders.length > 0 && <OrDivider className="mb-3 py-1" />}
+                    {ldapAuthProviders.map((provider, index) => (
+                        // Use index as key because display name may not be unique. This is OK
+                        // here because this list will not be updated during this component's lifetime.
+                        /* eslint-disable react/no-array-index-key */
+                        <React.Fragment key={index}>
+                            {(builtInAuthProvider || index > 0) && <OrDivider className="mb-3 py-1" />}
+                            <LdapSignInForm
+                                provider={provider}
+                                idPrefix={`ldap-${index}`}
+                                onAuthError={setError}
+                                context={props.context}
+                            />
+                        </React.Fragment>
+                    ))}
+                    {(builtInAuthProvider || ldapAuthProviders.length > 0) && thirdPartyAuthProviders.length > 0 && (
+                        <OrDivider className="mb-3 py-1" />
+                    )}
                     {thirdPartyAuthProviders.map((provider, index) => (
                         // Use index as key because display name may not be unique. This is OK
                         // here because this list will not be updated during this component's lifetime.
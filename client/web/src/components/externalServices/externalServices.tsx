@@ -6,12 +6,15 @@ import BitbucketIcon from 'mdi-react/BitbucketIcon'
 import GithubIcon from 'mdi-react/GithubIcon'
 import GitIcon from 'mdi-react/GitIcon'
 import GitLabIcon from 'mdi-react/GitlabIcon'
+import LanguageCsharpIcon from 'mdi-react/LanguageCsharpIcon'
 import LanguageGoIcon from 'mdi-react/LanguageGoIcon'
 import LanguageJavaIcon from 'mdi-react/LanguageJavaIcon'
+import LanguagePhpIcon from 'mdi-react/LanguagePhpIcon'
 import LanguagePythonIcon from 'mdi-react/LanguagePythonIcon'
 import LanguageRubyIcon from 'mdi-react/LanguageRubyIcon'
 import LanguageRustIcon from 'mdi-react/LanguageRustIcon'
 import NpmIcon from 'mdi-react/NpmIcon'
+import SourceRepositoryIcon from 'mdi-react/SourceRepositoryIcon'
 
 import { hasProperty } from '@sourcegraph/common'
 import { PerforceIcon, PhabricatorIcon } from '@sourcegraph/shared/src/components/icons'
@@ -21,6 +24,7 @@ import awsCodeCommitSchemaJSON from '../../../../../schema/aws_codecommit.schema
 import azureDevOpsSchemaJSON from '../../../../../schema/azuredevops.schema.json'
 import bitbucketCloudSchemaJSON from '../../../../../schema/bitbucket_cloud.schema.json'
 import bitbucketServerSchemaJSON from '../../../../../schema/bitbucket_server.schema.json'
+import dotnetPackagesSchemaJSON from '../../../../../schema/dotnet-packages.schema.json'
 import gerritSchemaJSON from '../../../../../schema/gerrit.schema.json'
 import githubSchemaJSON from '../../../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../../../schema/gitlab.schema.json'
@@ -32,9 +36,11 @@ import otherExternalServiceSchemaJSON from '../../../../../schema/other_external
 import pagureSchemaJSON from '../../../../../schema/pagure.schema.json'
 import perforceSchemaJSON from '../../../../../schema/perforce.schema.json'
 import phabricatorSchemaJSON from '../../../../../schema/phabricator.schema.json'
+import phpPackagesSchemaJSON from '../../../../../schema/php-packages.schema.json'
 import pythonPackagesJSON from '../../../../../schema/python-packages.schema.json'
 import rubyPackagesSchemaJSON from '../../../../../schema/ruby-packages.schema.json'
 import rustPackagesJSON from '../../../../../schema/rust-packages.schema.json'
+import subversionSchemaJSON from '../../../../../schema/subversion.schema.json'
 import {
     ExternalRepositoryFields,
     ExternalServiceFields,
@@ -1147,6 +1153,64 @@ const GENERIC_GIT: AddExternalServiceOptions = {
         },
     ],
 }
+const SUBVERSION: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.SUBVERSION,
+    title: 'Subversion',
+    icon: SourceRepositoryIcon,
+    jsonSchema: subversionSchemaJSON,
+    defaultDisplayName: 'Subversion',
+    defaultConfig: `{
+  "url": "https://svn.example.com/repos",
+  "username": "<username>",
+  "password": "<password>",
+  "repos": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to be the base URL of your Subversion server.
+                </li>
+                <li>
+                    Add the paths of the repositories you wish to index to the <Field>repos</Field> field. These will be
+                    appended to the base URL to obtain the repository URLs.
+                </li>
+                <li>
+                    If your repositories do not use the standard <Code>trunk</Code>, <Code>branches</Code> and{' '}
+                    <Code>tags</Code> layout, set <Field>layout</Field> accordingly.
+                </li>
+            </ol>
+            <Text>
+                See{' '}
+                <Link rel="noopener noreferrer" target="_blank" to="/help/admin/repo/subversion#configuration">
+                    the docs for more advanced options
+                </Link>
+                , or try one of the buttons below.
+            </Text>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'addRepo',
+            label: 'Add a repository',
+            run: (config: string) => {
+                const value = 'path/to/repository'
+                const edits = modify(config, ['repos', -1], value, defaultModificationOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'setAuthorsFile',
+            label: 'Map Subversion authors',
+            run: (config: string) => {
+                const value = 'svnuser = Full Name <user@example.com>'
+                const edits = modify(config, ['authorsFile'], value, defaultModificationOptions)
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
+
 const PERFORCE: AddExternalServiceOptions = {
     kind: ExternalServiceKind.PERFORCE,
     title: 'Perforce',
@@ -1473,6 +1537,70 @@ const RUBY_PACKAGES: AddExternalServiceOptions = {
     editorActions: [],
 }
 
+const DOTNET_PACKAGES: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.DOTNETPACKAGES,
+    title: '.NET Dependencies',
+    icon: LanguageCsharpIcon,
+    jsonSchema: dotnetPackagesSchemaJSON,
+    defaultDisplayName: '.NET Dependencies',
+    defaultConfig: `{
+  "repository": "https://api.nuget.org/v3/index.json",
+  "dependencies": ["Newtonsoft.Json@13.0.1"]
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    The NuGet service index https://api.nuget.org/v3/index.json is used if the field
+                    <Code>"repository"</Code> is empty.
+                </li>
+                <li>
+                    Use the syntax <Code>"PACKAGE_ID@PACKAGE_VERSION"</Code> to list a dependency for the{' '}
+                    <Code>"dependencies"</Code> field.
+                </li>
+                <li>
+                    The field <Code>"repository"</Code> is redacted because it can include <Code>admin:password</Code>{' '}
+                    credentials.
+                </li>
+            </ol>
+            <Text>⚠️ .NET package repositories are visible by all users of the Sourcegraph instance.</Text>
+        </div>
+    ),
+    editorActions: [],
+}
+
+const PHP_PACKAGES: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.PHPPACKAGES,
+    title: 'PHP Dependencies',
+    icon: LanguagePhpIcon,
+    jsonSchema: phpPackagesSchemaJSON,
+    defaultDisplayName: 'PHP Dependencies',
+    defaultConfig: `{
+  "repository": "https://repo.packagist.org",
+  "dependencies": ["monolog/monolog:3.2.0"]
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    The Composer repository https://repo.packagist.org is used if the field
+                    <Code>"repository"</Code> is empty.
+                </li>
+                <li>
+                    Use the syntax <Code>"VENDOR/PACKAGE:VERSION"</Code> to list a dependency for the{' '}
+                    <Code>"dependencies"</Code> field.
+                </li>
+                <li>
+                    The field <Code>"repository"</Code> is redacted because it can include <Code>admin:password</Code>{' '}
+                    credentials.
+                </li>
+            </ol>
+            <Text>⚠️ PHP package repositories are visible by all users of the Sourcegraph instance.</Text>
+        </div>
+    ),
+    editorActions: [],
+}
+
 export const codeHostExternalServices: Record<string, AddExternalServiceOptions> = {
     github: GITHUB_DOTCOM,
     ghe: GITHUB_ENTERPRISE,
@@ -1487,10 +1615,13 @@ export const codeHostExternalServices: Record<string, AddExternalServiceOptions>
     ...(window.context?.experimentalFeatures?.pythonPackages === 'enabled' ? { pythonPackages: PYTHON_PACKAGES } : {}),
     ...(window.context?.experimentalFeatures?.rustPackages === 'enabled' ? { rustPackages: RUST_PACKAGES } : {}),
     ...(window.context?.experimentalFeatures?.rubyPackages === 'enabled' ? { rubyPackages: RUBY_PACKAGES } : {}),
+    ...(window.context?.experimentalFeatures?.dotnetPackages === 'enabled' ? { dotnetPackages: DOTNET_PACKAGES } : {}),
+    ...(window.context?.experimentalFeatures?.phpPackages === 'enabled' ? { phpPackages: PHP_PACKAGES } : {}),
     ...(window.context?.experimentalFeatures?.goPackages === 'enabled' ? { goModules: GO_MODULES } : {}),
     ...(window.context?.experimentalFeatures?.jvmPackages === 'enabled' ? { jvmPackages: JVM_PACKAGES } : {}),
     ...(window.context?.experimentalFeatures?.npmPackages === 'enabled' ? { npmPackages: NPM_PACKAGES } : {}),
     ...(window.context?.experimentalFeatures?.perforce === 'enabled' ? { perforce: PERFORCE } : {}),
+    ...(window.context?.experimentalFeatures?.subversion === 'enabled' ? { subversion: SUBVERSION } : {}),
     ...(window.context?.experimentalFeatures?.pagure === 'enabled' ? { pagure: PAGURE } : {}),
     ...(window.context?.experimentalFeatures?.gerrit === 'enabled' ? { gerrit: GERRIT } : {}),
     ...(window.context?.experimentalFeatures?.azureDevOps === 'enabled' ? { azuredevops: AZUREDEVOPS } : {}),
@@ -1516,6 +1647,7 @@ export const defaultExternalServices: Record<ExternalServiceKind, AddExternalSer
     [ExternalServiceKind.OTHER]: GENERIC_GIT,
     [ExternalServiceKind.AWSCODECOMMIT]: AWS_CODE_COMMIT,
     [ExternalServiceKind.PERFORCE]: PERFORCE,
+    [ExternalServiceKind.SUBVERSION]: SUBVERSION,
     [ExternalServiceKind.GERRIT]: GERRIT,
     [ExternalServiceKind.PAGURE]: PAGURE,
     [ExternalServiceKind.GOMODULES]: GO_MODULES,
@@ -1524,6 +1656,8 @@ export const defaultExternalServices: Record<ExternalServiceKind, AddExternalSer
     [ExternalServiceKind.PYTHONPACKAGES]: PYTHON_PACKAGES,
     [ExternalServiceKind.RUSTPACKAGES]: RUST_PACKAGES,
     [ExternalServiceKind.RUBYPACKAGES]: RUBY_PACKAGES,
+    [ExternalServiceKind.DOTNETPACKAGES]: DOTNET_PACKAGES,
+    [ExternalServiceKind.PHPPACKAGES]: PHP_PACKAGES,
 }
 
 export const externalRepoIcon = (
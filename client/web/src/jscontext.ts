@@ -15,6 +15,7 @@ export interface AuthProvider {
         | 'openidconnect'
         | 'sourcegraph-operator'
         | 'saml'
+        | 'ldap'
         | 'builtin'
     displayName: string
     isBuiltin: boolean
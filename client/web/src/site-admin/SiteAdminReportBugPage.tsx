@@ -12,6 +12,7 @@ import awsCodeCommitJSON from '../../../../schema/aws_codecommit.schema.json'
 import azureDevOpsJSON from '../../../../schema/azuredevops.schema.json'
 import bitbucketCloudSchemaJSON from '../../../../schema/bitbucket_cloud.schema.json'
 import bitbucketServerSchemaJSON from '../../../../schema/bitbucket_server.schema.json'
+import dotnetPackagesSchemaJSON from '../../../../schema/dotnet-packages.schema.json'
 import gerritSchemaJSON from '../../../../schema/gerrit.schema.json'
 import githubSchemaJSON from '../../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../../schema/gitlab.schema.json'
@@ -23,11 +24,13 @@ import otherExternalServiceSchemaJSON from '../../../../schema/other_external_se
 import pagureSchemaJSON from '../../../../schema/pagure.schema.json'
 import perforceSchemaJSON from '../../../../schema/perforce.schema.json'
 import phabricatorSchemaJSON from '../../../../schema/phabricator.schema.json'
+import phpPackagesSchemaJSON from '../../../../schema/php-packages.schema.json'
 import pythonPackagesSchemaJSON from '../../../../schema/python-packages.schema.json'
 import rubyPackagesSchemaJSON from '../../../../schema/ruby-packages.schema.json'
 import rustPackagesSchemaJSON from '../../../../schema/rust-packages.schema.json'
 import settingsSchemaJSON from '../../../../schema/settings.schema.json'
 import siteSchemaJSON from '../../../../schema/site.schema.json'
+import subversionSchemaJSON from '../../../../schema/subversion.schema.json'
 import { PageTitle } from '../components/PageTitle'
 import { DynamicallyImportedMonacoSettingsEditor } from '../settings/DynamicallyImportedMonacoSettingsEditor'
 
@@ -57,10 +60,13 @@ const externalServices: Record<ExternalServiceKind, JSONSchema> = {
     PYTHONPACKAGES: pythonPackagesSchemaJSON,
     RUSTPACKAGES: rustPackagesSchemaJSON,
     RUBYPACKAGES: rubyPackagesSchemaJSON,
+    DOTNETPACKAGES: dotnetPackagesSchemaJSON,
+    PHPPACKAGES: phpPackagesSchemaJSON,
     OTHER: otherExternalServiceSchemaJSON,
     PERFORCE: perforceSchemaJSON,
     PHABRICATOR: phabricatorSchemaJSON,
     PAGURE: pagureSchemaJSON,
+    SUBVERSION: subversionSchemaJSON,
 }
 
 const allConfigSchema = {
@@ -39,12 +39,16 @@ type Services struct {
 	// Handler for exporting code insights data.
 	CodeInsightsDataExportHandler http.Handler
 
+	// Handler for following the logs of executor jobs while they run.
+	ExecutorLogStreamHandler http.Handler
+
 	PermissionsGitHubWebhook    webhooks.Registerer
 	NewCodeIntelUploadHandler   NewCodeIntelUploadHandler
 	RankingService              RankingService
 	NewExecutorProxyHandler     NewExecutorProxyHandler
 	NewGitHubAppSetupHandler    NewGitHubAppSetupHandler
 	NewComputeStreamHandler     NewComputeStreamHandler
+	NewSCIMHandler              NewSCIMHandler
 	AuthzResolver               graphqlbackend.AuthzResolver
 	BatchChangesResolver        graphqlbackend.BatchChangesResolver
 	CodeIntelResolver           graphqlbackend.CodeIntelResolver
@@ -82,6 +86,10 @@ type NewGitHubAppSetupHandler func() http.Handler
 // NewComputeStreamHandler creates a new handler for the Sourcegraph Compute streaming endpoint.
 type NewComputeStreamHandler func() http.Handler
 
+// NewSCIMHandler creates a new handler for the SCIM 2.0 user and group provisioning endpoint.
+// This handler is protected via a bearer token configured in the site configuration.
+type NewSCIMHandler func() http.Handler
+
 // DefaultServices creates a new Services value that has default implementations for all services.
 func DefaultServices() Services {
 	return Services{
@@ -102,7 +110,9 @@ func DefaultServices() Services {
 		NewExecutorProxyHandler:         func() http.Handler { return makeNotFoundHandler("executor proxy") },
 		NewGitHubAppSetupHandler:        func() http.Handler { return makeNotFoundHandler("Sourcegraph GitHub App setup") },
 		NewComputeStreamHandler:         func() http.Handler { return makeNotFoundHandler("compute streaming endpoint") },
+		NewSCIMHandler:                  func() http.Handler { return makeNotFoundHandler("SCIM provisioning endpoint") },
 		CodeInsightsDataExportHandler:   makeNotFoundHandler("code insights data export handler"),
+		ExecutorLogStreamHandler:        makeNotFoundHandler("executor log stream handler"),
 	}
 }
 
@@ -786,6 +786,9 @@ type ExternalChangesetResolver interface {
 	ReviewState(context.Context) *string
 	// CheckState returns a value of type *btypes.ChangesetCheckState.
 	CheckState() *string
+	RequestedReviewers() []string
+	BlockedByDependencies() bool
+	DependsOn(ctx context.Context) ([]ChangesetResolver, error)
 	Repository(ctx context.Context) *RepositoryResolver
 
 	Events(ctx context.Context, args *ChangesetEventsConnectionArgs) (ChangesetEventsConnectionResolver, error)
@@ -795,6 +798,7 @@ type ExternalChangesetResolver interface {
 
 	Error() *string
 	SyncerError() *string
+	MergeError() *string
 	ScheduleEstimateAt(ctx context.Context) (*gqlutil.DateTime, error)
 
 	CurrentSpec(ctx context.Context) (VisibleChangesetSpecResolver, error)
@@ -0,0 +1,283 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// deadLetterStores holds the stores whose failed records site admins can inspect and recover,
+// keyed by store name.
+var deadLetterStores = struct {
+	sync.RWMutex
+	m map[string]dbworkerstore.DeadLetterStore
+}{m: map[string]dbworkerstore.DeadLetterStore{}}
+
+// RegisterDeadLetterStore makes the failed records of the given store available via the dead
+// letter queue API. Registering a store with the same name again replaces the previous store.
+func RegisterDeadLetterStore(store dbworkerstore.DeadLetterStore) {
+	deadLetterStores.Lock()
+	defer deadLetterStores.Unlock()
+
+	deadLetterStores.m[store.Name()] = store
+}
+
+func getDeadLetterStore(name string) (dbworkerstore.DeadLetterStore, bool) {
+	deadLetterStores.RLock()
+	defer deadLetterStores.RUnlock()
+
+	store, ok := deadLetterStores.m[name]
+	return store, ok
+}
+
+func listDeadLetterStores() []dbworkerstore.DeadLetterStore {
+	deadLetterStores.RLock()
+	defer deadLetterStores.RUnlock()
+
+	stores := make([]dbworkerstore.DeadLetterStore, 0, len(deadLetterStores.m))
+	for _, store := range deadLetterStores.m {
+		stores = append(stores, store)
+	}
+	sort.Slice(stores, func(i, j int) bool { return stores[i].Name() < stores[j].Name() })
+
+	return stores
+}
+
+type DeadLetterFilterInput struct {
+	FailureMessage *string
+	IDs            *[]int32
+	FinishedAfter  *gqlutil.DateTime
+	FinishedBefore *gqlutil.DateTime
+}
+
+func (f *DeadLetterFilterInput) toFilter() dbworkerstore.DeadLetterFilter {
+	var filter dbworkerstore.DeadLetterFilter
+	if f == nil {
+		return filter
+	}
+
+	filter.FailureMessage = f.FailureMessage
+	if f.IDs != nil {
+		filter.IDs = make([]int, 0, len(*f.IDs))
+		for _, id := range *f.IDs {
+			filter.IDs = append(filter.IDs, int(id))
+		}
+	}
+	if f.FinishedAfter != nil {
+		filter.FinishedAfter = &f.FinishedAfter.Time
+	}
+	if f.FinishedBefore != nil {
+		filter.FinishedBefore = &f.FinishedBefore.Time
+	}
+
+	return filter
+}
+
+type DeadLetterQueueArgs struct {
+	Name string
+}
+
+type DeadLetterRecordsArgs struct {
+	First  int32
+	After  *string
+	Filter *DeadLetterFilterInput
+}
+
+type DeadLetterFailureGroupsArgs struct {
+	Filter *DeadLetterFilterInput
+}
+
+type DeadLetterBulkOperationArgs struct {
+	Queue  string
+	Filter *DeadLetterFilterInput
+}
+
+func (r *schemaResolver) DeadLetterQueues(ctx context.Context) ([]*deadLetterQueueResolver, error) {
+	// 🚨 SECURITY: Only site admins may view failed records of background workers.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	stores := listDeadLetterStores()
+	resolvers := make([]*deadLetterQueueResolver, 0, len(stores))
+	for _, store := range stores {
+		resolvers = append(resolvers, &deadLetterQueueResolver{db: r.db, store: store})
+	}
+
+	return resolvers, nil
+}
+
+func (r *schemaResolver) DeadLetterQueue(ctx context.Context, args *DeadLetterQueueArgs) (*deadLetterQueueResolver, error) {
+	// 🚨 SECURITY: Only site admins may view failed records of background workers.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	store, ok := getDeadLetterStore(args.Name)
+	if !ok {
+		return nil, nil
+	}
+
+	return &deadLetterQueueResolver{db: r.db, store: store}, nil
+}
+
+func (r *schemaResolver) RequeueDeadLetterRecords(ctx context.Context, args *DeadLetterBulkOperationArgs) (int32, error) {
+	// 🚨 SECURITY: Only site admins may requeue failed records of background workers.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return 0, err
+	}
+
+	store, ok := getDeadLetterStore(args.Queue)
+	if !ok {
+		return 0, errors.Newf("unknown dead letter queue %q", args.Queue)
+	}
+
+	count, err := store.RequeueFailed(ctx, args.Filter.toFilter())
+	return int32(count), err
+}
+
+func (r *schemaResolver) DeleteDeadLetterRecords(ctx context.Context, args *DeadLetterBulkOperationArgs) (int32, error) {
+	// 🚨 SECURITY: Only site admins may delete failed records of background workers.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return 0, err
+	}
+
+	store, ok := getDeadLetterStore(args.Queue)
+	if !ok {
+		return 0, errors.Newf("unknown dead letter queue %q", args.Queue)
+	}
+
+	if !store.CanDelete() {
+		return 0, errors.Newf("the failed records of dead letter queue %q can't be deleted, only requeued", args.Queue)
+	}
+
+	count, err := store.DeleteFailed(ctx, args.Filter.toFilter())
+	return int32(count), err
+}
+
+type deadLetterQueueResolver struct {
+	db    database.DB
+	store dbworkerstore.DeadLetterStore
+}
+
+func (r *deadLetterQueueResolver) Name() string {
+	return r.store.Name()
+}
+
+func (r *deadLetterQueueResolver) CanDelete() bool {
+	return r.store.CanDelete()
+}
+
+func (r *deadLetterQueueResolver) FailureGroups(ctx context.Context, args *DeadLetterFailureGroupsArgs) ([]*deadLetterFailureGroupResolver, error) {
+	groups, err := r.store.FailureGroups(ctx, args.Filter.toFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*deadLetterFailureGroupResolver, 0, len(groups))
+	for _, group := range groups {
+		resolvers = append(resolvers, &deadLetterFailureGroupResolver{group: group})
+	}
+
+	return resolvers, nil
+}
+
+func (r *deadLetterQueueResolver) Records(ctx context.Context, args *DeadLetterRecordsArgs) (*deadLetterRecordConnectionResolver, error) {
+	if args.First < 0 {
+		return nil, errors.Newf("first must not be negative, got %d", args.First)
+	}
+	limit := (&graphqlutil.ConnectionResolverOptions{}).ApplyMaxPageSize(&args.First)
+
+	offset := 0
+	if args.After != nil {
+		var err error
+		if offset, err = strconv.Atoi(*args.After); err != nil {
+			return nil, errors.Newf("cannot parse offset %q", *args.After)
+		}
+		if offset < 0 {
+			return nil, errors.Newf("offset must not be negative, got %d", offset)
+		}
+	}
+
+	records, totalCount, err := r.store.FailedRecords(ctx, args.Filter.toFilter(), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deadLetterRecordConnectionResolver{
+		db:         r.db,
+		records:    records,
+		totalCount: totalCount,
+		offset:     offset,
+	}, nil
+}
+
+type deadLetterFailureGroupResolver struct {
+	group dbworkerstore.FailureGroup
+}
+
+func (r *deadLetterFailureGroupResolver) FailureMessage() string { return r.group.FailureMessage }
+func (r *deadLetterFailureGroupResolver) Count() int32           { return int32(r.group.Count) }
+func (r *deadLetterFailureGroupResolver) FirstFailedAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.group.FirstFailedAt)
+}
+func (r *deadLetterFailureGroupResolver) LastFailedAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.group.LastFailedAt)
+}
+
+type deadLetterRecordConnectionResolver struct {
+	db         database.DB
+	records    []dbworkerstore.FailedRecord
+	totalCount int
+	offset     int
+}
+
+func (r *deadLetterRecordConnectionResolver) Nodes() []*deadLetterRecordResolver {
+	resolvers := make([]*deadLetterRecordResolver, 0, len(r.records))
+	for _, record := range r.records {
+		resolvers = append(resolvers, &deadLetterRecordResolver{db: r.db, record: record})
+	}
+
+	return resolvers
+}
+
+func (r *deadLetterRecordConnectionResolver) TotalCount() int32 {
+	return int32(r.totalCount)
+}
+
+func (r *deadLetterRecordConnectionResolver) PageInfo() *graphqlutil.PageInfo {
+	if next := r.offset + len(r.records); next < r.totalCount {
+		return graphqlutil.NextPageCursor(strconv.Itoa(next))
+	}
+	return graphqlutil.HasNextPage(false)
+}
+
+type deadLetterRecordResolver struct {
+	db     database.DB
+	record dbworkerstore.FailedRecord
+}
+
+func (r *deadLetterRecordResolver) ID() int32               { return int32(r.record.ID) }
+func (r *deadLetterRecordResolver) FailureMessage() *string { return r.record.FailureMessage }
+func (r *deadLetterRecordResolver) NumFailures() int32      { return int32(r.record.NumFailures) }
+func (r *deadLetterRecordResolver) NumResets() int32        { return int32(r.record.NumResets) }
+func (r *deadLetterRecordResolver) FinishedAt() *gqlutil.DateTime {
+	return gqlutil.DateTimeOrNil(r.record.FinishedAt)
+}
+
+func (r *deadLetterRecordResolver) ExecutionLogs() []ExecutionLogEntryResolver {
+	resolvers := make([]ExecutionLogEntryResolver, 0, len(r.record.ExecutionLogs))
+	for _, entry := range r.record.ExecutionLogs {
+		resolvers = append(resolvers, NewExecutionLogEntryResolver(r.db, entry))
+	}
+
+	return resolvers
+}
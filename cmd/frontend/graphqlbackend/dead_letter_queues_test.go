@@ -0,0 +1,303 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/executor"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+type fakeDeadLetterStore struct {
+	name      string
+	groups    []dbworkerstore.FailureGroup
+	records   []dbworkerstore.FailedRecord
+	canDelete bool
+
+	limits []int
+
+	requeueFilters []dbworkerstore.DeadLetterFilter
+	deleteFilters  []dbworkerstore.DeadLetterFilter
+}
+
+func (s *fakeDeadLetterStore) Name() string { return s.name }
+
+func (s *fakeDeadLetterStore) FailureGroups(ctx context.Context, filter dbworkerstore.DeadLetterFilter) ([]dbworkerstore.FailureGroup, error) {
+	return s.groups, nil
+}
+
+func (s *fakeDeadLetterStore) FailedRecords(ctx context.Context, filter dbworkerstore.DeadLetterFilter, limit, offset int) ([]dbworkerstore.FailedRecord, int, error) {
+	s.limits = append(s.limits, limit)
+	records := s.records[offset:]
+	if len(records) > limit {
+		records = records[:limit]
+	}
+	return records, len(s.records), nil
+}
+
+func (s *fakeDeadLetterStore) RequeueFailed(ctx context.Context, filter dbworkerstore.DeadLetterFilter) (int, error) {
+	s.requeueFilters = append(s.requeueFilters, filter)
+	return 2, nil
+}
+
+func (s *fakeDeadLetterStore) CanDelete() bool { return s.canDelete }
+
+func (s *fakeDeadLetterStore) DeleteFailed(ctx context.Context, filter dbworkerstore.DeadLetterFilter) (int, error) {
+	s.deleteFilters = append(s.deleteFilters, filter)
+	return 1, nil
+}
+
+func TestDeadLetterQueues(t *testing.T) {
+	failedAt := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	failureMessage := "failed to clone repository 42"
+	exitCode := 1
+
+	store := &fakeDeadLetterStore{
+		name:      "test_dead_letter_queue",
+		canDelete: true,
+		groups: []dbworkerstore.FailureGroup{
+			{FailureMessage: "failed to clone repository <n>", Count: 2, FirstFailedAt: &failedAt, LastFailedAt: &failedAt},
+		},
+		records: []dbworkerstore.FailedRecord{
+			{ID: 1, FailureMessage: &failureMessage, NumFailures: 3, FinishedAt: &failedAt, ExecutionLogs: []executor.ExecutionLogEntry{
+				{Key: "step.0", Command: []string{"git", "clone"}, StartTime: failedAt, ExitCode: &exitCode, Out: "fatal"},
+			}},
+			{ID: 2, FailureMessage: &failureMessage, NumResets: 1, FinishedAt: &failedAt},
+		},
+	}
+	RegisterDeadLetterStore(store)
+
+	t.Run("not site admin", func(t *testing.T) {
+		db := database.NewMockDB()
+		ctx, _, _ := fakeUser(t, context.Background(), db, false)
+
+		runMustBeSiteAdminTest(t, []any{"deadLetterQueue"}, &Test{
+			Context: ctx,
+			Schema:  mustParseGraphQLSchema(t, db),
+			Query: `
+				{
+					deadLetterQueue(name: "test_dead_letter_queue") {
+						name
+					}
+				}
+			`,
+		})
+	})
+
+	t.Run("records", func(t *testing.T) {
+		db := database.NewMockDB()
+		ctx, _, _ := fakeUser(t, context.Background(), db, true)
+
+		RunTest(t, &Test{
+			Context: ctx,
+			Schema:  mustParseGraphQLSchema(t, db),
+			Query: `
+				{
+					deadLetterQueue(name: "test_dead_letter_queue") {
+						name
+						failureGroups {
+							failureMessage
+							count
+							lastFailedAt
+						}
+						records(first: 1) {
+							nodes {
+								id
+								failureMessage
+								numFailures
+								numResets
+								finishedAt
+								executionLogs {
+									key
+									exitCode
+									out
+								}
+							}
+							totalCount
+							pageInfo {
+								hasNextPage
+								endCursor
+							}
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"deadLetterQueue": {
+						"name": "test_dead_letter_queue",
+						"failureGroups": [
+							{
+								"failureMessage": "failed to clone repository <n>",
+								"count": 2,
+								"lastFailedAt": "2023-01-02T03:04:05Z"
+							}
+						],
+						"records": {
+							"nodes": [
+								{
+									"id": 1,
+									"failureMessage": "failed to clone repository 42",
+									"numFailures": 3,
+									"numResets": 0,
+									"finishedAt": "2023-01-02T03:04:05Z",
+									"executionLogs": [
+										{
+											"key": "step.0",
+											"exitCode": 1,
+											"out": "fatal"
+										}
+									]
+								}
+							],
+							"totalCount": 2,
+							"pageInfo": {
+								"hasNextPage": true,
+								"endCursor": "1"
+							}
+						}
+					}
+				}
+			`,
+		})
+	})
+
+	t.Run("page size", func(t *testing.T) {
+		db := database.NewMockDB()
+		ctx, _, _ := fakeUser(t, context.Background(), db, true)
+		store.limits = nil
+
+		RunTest(t, &Test{
+			Context: ctx,
+			Schema:  mustParseGraphQLSchema(t, db),
+			Query: `
+				{
+					deadLetterQueue(name: "test_dead_letter_queue") {
+						canDelete
+						records(first: 100000) {
+							totalCount
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"deadLetterQueue": {
+						"canDelete": true,
+						"records": {
+							"totalCount": 2
+						}
+					}
+				}
+			`,
+		})
+
+		if diff := cmp.Diff([]int{100}, store.limits); diff != "" {
+			t.Errorf("unexpected limits (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("negative paging arguments", func(t *testing.T) {
+		db := database.NewMockDB()
+		ctx, _, _ := fakeUser(t, context.Background(), db, true)
+
+		for arguments, message := range map[string]string{
+			`first: -1`:   "first must not be negative, got -1",
+			`after: "-1"`: "offset must not be negative, got -1",
+		} {
+			RunTest(t, &Test{
+				Context: ctx,
+				Schema:  mustParseGraphQLSchema(t, db),
+				Query: `
+					{
+						deadLetterQueue(name: "test_dead_letter_queue") {
+							records(` + arguments + `) {
+								totalCount
+							}
+						}
+					}
+				`,
+				ExpectedResult: `{"deadLetterQueue": null}`,
+				ExpectedErrors: []*gqlerrors.QueryError{{
+					Message: message,
+					Path:    []any{"deadLetterQueue", "records"},
+				}},
+			})
+		}
+	})
+
+	t.Run("requeue", func(t *testing.T) {
+		db := database.NewMockDB()
+		ctx, _, _ := fakeUser(t, context.Background(), db, true)
+
+		RunTest(t, &Test{
+			Context: ctx,
+			Schema:  mustParseGraphQLSchema(t, db),
+			Query: `
+				mutation {
+					requeueDeadLetterRecords(queue: "test_dead_letter_queue", filter: {failureMessage: "failed to clone repository <n>", ids: [1, 2]})
+				}
+			`,
+			ExpectedResult: `{"requeueDeadLetterRecords": 2}`,
+		})
+
+		expectedFailureMessage := "failed to clone repository <n>"
+		expected := []dbworkerstore.DeadLetterFilter{{FailureMessage: &expectedFailureMessage, IDs: []int{1, 2}}}
+		if diff := cmp.Diff(expected, store.requeueFilters); diff != "" {
+			t.Errorf("unexpected requeue filters (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		db := database.NewMockDB()
+		ctx, _, _ := fakeUser(t, context.Background(), db, true)
+
+		RunTest(t, &Test{
+			Context: ctx,
+			Schema:  mustParseGraphQLSchema(t, db),
+			Query: `
+				mutation {
+					deleteDeadLetterRecords(queue: "test_dead_letter_queue", filter: {finishedBefore: "2023-01-02T03:04:05Z"})
+				}
+			`,
+			ExpectedResult: `{"deleteDeadLetterRecords": 1}`,
+		})
+
+		expected := []dbworkerstore.DeadLetterFilter{{FinishedBefore: &failedAt}}
+		if diff := cmp.Diff(expected, store.deleteFilters); diff != "" {
+			t.Errorf("unexpected delete filters (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestDeleteDeadLetterRecordsUnsupported(t *testing.T) {
+	store := &fakeDeadLetterStore{name: "test_requeue_only_dead_letter_queue"}
+	RegisterDeadLetterStore(store)
+
+	db := database.NewMockDB()
+	ctx, _, _ := fakeUser(t, context.Background(), db, true)
+
+	RunTest(t, &Test{
+		Context: ctx,
+		Schema:  mustParseGraphQLSchema(t, db),
+		Query: `
+			mutation {
+				deleteDeadLetterRecords(queue: "test_requeue_only_dead_letter_queue")
+			}
+		`,
+		ExpectedResult: `null`,
+		ExpectedErrors: []*gqlerrors.QueryError{{
+			Message: `the failed records of dead letter queue "test_requeue_only_dead_letter_queue" can't be deleted, only requeued`,
+			Path:    []any{"deleteDeadLetterRecords"},
+		}},
+	})
+
+	if len(store.deleteFilters) != 0 {
+		t.Errorf("expected no records to be deleted, have %d calls", len(store.deleteFilters))
+	}
+}
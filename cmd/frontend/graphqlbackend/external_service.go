@@ -189,6 +189,29 @@ func (r *externalServiceResolver) RepoCount(ctx context.Context) (int32, error)
 	return r.db.ExternalServices().RepoCount(ctx, r.externalService.ID)
 }
 
+func (r *externalServiceResolver) DiskUsageBytes(ctx context.Context) (BigInt, error) {
+	usage, err := r.db.ExternalServices().DiskUsage(ctx, r.externalService.ID)
+	return BigInt(usage), err
+}
+
+func (r *externalServiceResolver) DiskQuotaBytes() (*BigInt, error) {
+	quotas, err := extsvc.ExtractDiskQuotas(conf.Get().CodeHostDiskQuotas, r.externalService.ID)
+	if err != nil || quotas.Total == 0 {
+		return nil, err
+	}
+	total := BigInt(quotas.Total)
+	return &total, nil
+}
+
+func (r *externalServiceResolver) RepoDiskQuotaBytes() (*BigInt, error) {
+	quotas, err := extsvc.ExtractDiskQuotas(conf.Get().CodeHostDiskQuotas, r.externalService.ID)
+	if err != nil || quotas.PerRepo == 0 {
+		return nil, err
+	}
+	perRepo := BigInt(quotas.PerRepo)
+	return &perRepo, nil
+}
+
 func (r *externalServiceResolver) LastSyncAt() *gqlutil.DateTime {
 	if r.externalService.LastSyncAt.IsZero() {
 		return nil
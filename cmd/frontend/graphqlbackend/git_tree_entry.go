@@ -312,6 +312,26 @@ func (r *GitTreeEntryResolver) LSIF(ctx context.Context, args *struct{ ToolName
 	})
 }
 
+func (r *GitTreeEntryResolver) DocumentSymbols(ctx context.Context, args *struct{ ToolName *string }) (resolverstubs.DocumentSymbolsResolver, error) {
+	var toolName string
+	if args.ToolName != nil {
+		toolName = *args.ToolName
+	}
+
+	repo, err := r.commit.repoResolver.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return EnterpriseResolvers.codeIntelResolver.GitBlobDocumentSymbols(ctx, &resolverstubs.GitBlobLSIFDataArgs{
+		Repo:      repo,
+		Commit:    api.CommitID(r.Commit().OID()),
+		Path:      r.Path(),
+		ExactPath: true,
+		ToolName:  toolName,
+	})
+}
+
 func (r *GitTreeEntryResolver) CodeIntelSupport(ctx context.Context) (resolverstubs.GitBlobCodeIntelSupportResolver, error) {
 	repo, err := r.commit.repoResolver.repo(ctx)
 	if err != nil {
@@ -424,7 +424,7 @@ func NewSchema(
 	webhooksResolver WebhooksResolver,
 ) (*graphql.Schema, error) {
 	resolver := newSchemaResolver(db, gitserverClient)
-	schemas := []string{mainSchema, outboundWebhooksSchema}
+	schemas := []string{mainSchema, deadLetterQueuesSchema, outboundWebhooksSchema}
 
 	if batchChanges != nil {
 		EnterpriseResolvers.batchChangesResolver = batchChanges
@@ -424,6 +424,32 @@ func (r *RepositoryResolver) PreviewGitObjectFilter(ctx context.Context, args *r
 	return EnterpriseResolvers.codeIntelResolver.PreviewGitObjectFilter(ctx, r.ID(), args)
 }
 
+func (r *RepositoryResolver) CodeIntelAPIDiff(ctx context.Context, args *struct{ Base, Head string }) (resolverstubs.CodeIntelAPIDiffResolver, error) {
+	repo, err := r.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	repos := backend.NewRepos(r.logger, r.db, r.gitserverClient)
+	commitIDs := make([]api.CommitID, 0, 2)
+	for _, rev := range []string{args.Base, args.Head} {
+		commitID, err := repos.ResolveRev(ctx, repo, rev)
+		if err != nil {
+			if errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		commitIDs = append(commitIDs, commitID)
+	}
+
+	return EnterpriseResolvers.codeIntelResolver.CodeIntelAPIDiff(ctx, &resolverstubs.CodeIntelAPIDiffArgs{
+		Repo: repo,
+		Base: commitIDs[0],
+		Head: commitIDs[1],
+	})
+}
+
 type AuthorizedUserArgs struct {
 	RepositoryID graphql.ID
 	Permission   string
@@ -64,6 +64,11 @@ var notebooksSchema string
 //go:embed insights_aggregations.graphql
 var insightsAggregationsSchema string
 
+// deadLetterQueuesSchema is the dead letter queue raw GraphQL schema.
+//
+//go:embed dead_letter_queues.graphql
+var deadLetterQueuesSchema string
+
 // outboundWebhooksSchema is the outbound webhook raw GraphQL schema.
 //
 //go:embed outbound_webhooks.graphql
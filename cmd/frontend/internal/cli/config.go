@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/gomodule/redigo/redis"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/sourcegraph/log"
@@ -32,6 +33,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
 	"github.com/sourcegraph/sourcegraph/internal/symbols"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -443,29 +445,93 @@ func watchPaths(ctx context.Context, paths ...string) (<-chan error, error) {
 
 func newConfigurationSource(logger log.Logger, db database.DB) *configurationSource {
 	return &configurationSource{
-		logger: logger.Scoped("configurationSource", ""),
-		db:     db,
+		logger:          logger.Scoped("configurationSource", ""),
+		db:              db,
+		latestRebalance: database.GitserverRebalancesWith(db).Latest,
+		layoutVersion:   gitserverLayoutVersion,
 	}
 }
 
 type configurationSource struct {
 	logger log.Logger
 	db     database.DB
+
+	// Read is called for every configuration poll of every service, so the gitserver
+	// routing is cached. It is reloaded from the gitserver_rebalances table when the
+	// rebalancer signals a change through database.GitserverRebalanceVersionKey, or
+	// after routingMaxAge in case a signal was lost.
+	latestRebalance func(context.Context) (*database.GitserverRebalance, error)
+	layoutVersion   func() (string, error)
+
+	routingMu       sync.Mutex
+	routing         *conftypes.GitServerRouting
+	routingVersion  string
+	routingLoadedAt time.Time
 }
 
+// gitserverLayoutVersion returns the value of the counter that the rebalancer
+// increments when it changes the gitserver layout. A missing counter, e.g. before the
+// first change, is returned as the empty version.
+func gitserverLayoutVersion() (string, error) {
+	version, err := redispool.Store.Get(database.GitserverRebalanceVersionKey).String()
+	if errors.Is(err, redis.ErrNil) {
+		return "", nil
+	}
+	return version, err
+}
+
+// routingMaxAge is how long the cached gitserver routing is used without a change
+// signal before it is reloaded.
+const routingMaxAge = 10 * time.Minute
+
 func (c *configurationSource) Read(ctx context.Context) (conftypes.RawUnified, error) {
 	site, err := c.db.Conf().SiteGetLatest(ctx)
 	if err != nil {
 		return conftypes.RawUnified{}, errors.Wrap(err, "ConfStore.SiteGetLatest")
 	}
 
+	connections := serviceConnections(c.logger)
+	connections.GitServerRouting = c.gitServerRouting(ctx)
+
 	return conftypes.RawUnified{
 		ID:                 site.ID,
 		Site:               site.Contents,
-		ServiceConnections: serviceConnections(c.logger),
+		ServiceConnections: connections,
 	}, nil
 }
 
+// gitServerRouting returns the routing of the latest gitserver rebalance, or nil if
+// there is none. If it cannot be loaded, the previous routing is returned so that
+// configuration polls keep working.
+func (c *configurationSource) gitServerRouting(ctx context.Context) *conftypes.GitServerRouting {
+	c.routingMu.Lock()
+	defer c.routingMu.Unlock()
+
+	version, err := c.layoutVersion()
+	if err != nil {
+		// Keep using the cached routing until it is too old.
+		c.logger.Warn("failed to read gitserver layout version", log.Error(err))
+		version = c.routingVersion
+	}
+	if !c.routingLoadedAt.IsZero() && version == c.routingVersion && time.Since(c.routingLoadedAt) < routingMaxAge {
+		return c.routing
+	}
+
+	rebalance, err := c.latestRebalance(ctx)
+	if err != nil {
+		c.logger.Error("failed to load gitserver routing, using the previous routing", log.Error(err))
+		return c.routing
+	}
+
+	c.routing = nil
+	if rebalance != nil {
+		c.routing = rebalance.Routing()
+	}
+	c.routingVersion = version
+	c.routingLoadedAt = time.Now()
+	return c.routing
+}
+
 func (c *configurationSource) Write(ctx context.Context, input conftypes.RawUnified, lastID int32, authorUserID int32) error {
 	return c.WriteWithOverride(ctx, input, lastID, authorUserID, false)
 }
@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/log/logtest"
@@ -64,6 +65,54 @@ func TestWriteSiteConfig(t *testing.T) {
 	})
 }
 
+func TestConfigurationSourceGitServerRouting(t *testing.T) {
+	var (
+		loads     int
+		rebalance = &database.GitserverRebalance{DestAddrs: []string{"gitserver-0"}, DestSharding: "modulo", FinishedAt: &time.Time{}}
+		loadErr   error
+		version   = "1"
+	)
+	cs := newConfigurationSource(logtest.Scoped(t), database.NewMockDB())
+	cs.latestRebalance = func(context.Context) (*database.GitserverRebalance, error) {
+		loads++
+		return rebalance, loadErr
+	}
+	cs.layoutVersion = func() (string, error) { return version, nil }
+
+	read := func() *conftypes.GitServerRouting {
+		return cs.gitServerRouting(context.Background())
+	}
+
+	want := &conftypes.GitServerRouting{Addresses: []string{"gitserver-0"}, Sharding: "modulo"}
+	for i := 0; i < 3; i++ {
+		if diff := cmp.Diff(want, read()); diff != "" {
+			t.Fatalf("unexpected routing (-want +got):\n%s", diff)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("want the routing to be loaded once, got %d loads", loads)
+	}
+
+	// A failure to load a new layout keeps the previous routing.
+	version, loadErr = "2", errors.New("database is gone")
+	if diff := cmp.Diff(want, read()); diff != "" {
+		t.Fatalf("unexpected routing (-want +got):\n%s", diff)
+	}
+
+	// Once it can be loaded, the new layout is used.
+	rebalance = &database.GitserverRebalance{DestAddrs: []string{"gitserver-0", "gitserver-1"}, DestSharding: "rendezvous", FinishedAt: &time.Time{}}
+	loadErr = nil
+	want = &conftypes.GitServerRouting{Addresses: []string{"gitserver-0", "gitserver-1"}, Sharding: "rendezvous"}
+	if diff := cmp.Diff(want, read()); diff != "" {
+		t.Fatalf("unexpected routing (-want +got):\n%s", diff)
+	}
+	loads = 0
+	read()
+	if loads != 0 {
+		t.Fatalf("want the routing to be cached, got %d loads", loads)
+	}
+}
+
 func TestReadSiteConfigFile(t *testing.T) {
 	dir := t.TempDir()
 
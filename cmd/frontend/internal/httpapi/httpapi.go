@@ -65,6 +65,9 @@ type Handlers struct {
 
 	// Code Insights
 	CodeInsightsDataExportHandler http.Handler
+
+	// Executors
+	ExecutorLogStreamHandler http.Handler
 }
 
 // NewHandler returns a new API handler that uses the provided API
@@ -142,6 +145,8 @@ func NewHandler(
 
 	m.Get(apirouter.CodeInsightsDataExport).Handler(trace.Route(handlers.CodeInsightsDataExportHandler))
 
+	m.Get(apirouter.ExecutorLogStream).Handler(trace.Route(handlers.ExecutorLogStreamHandler))
+
 	if envvar.SourcegraphDotComMode() {
 		m.Path("/updates").Methods("GET", "POST").Name("updatecheck").Handler(trace.Route(http.HandlerFunc(updatecheck.HandlerWithLog(logger))))
 	}
@@ -39,6 +39,8 @@ const (
 
 	CodeInsightsDataExport = "insights.data.export"
 
+	ExecutorLogStream = "executors.logs.stream"
+
 	ExternalURL            = "internal.app-url"
 	SendEmail              = "internal.send-email"
 	GitInfoRefs            = "internal.git.info-refs"
@@ -81,6 +83,7 @@ func New(base *mux.Router) *mux.Router {
 	base.Path("/src-cli/versions/{rest:.*}").Methods("GET", "POST").Name(SrcCliVersionCache)
 	base.Path("/src-cli/{rest:.*}").Methods("GET").Name(SrcCli)
 	base.Path("/insights/export/{id}").Methods("GET").Name(CodeInsightsDataExport)
+	base.Path("/executors/{queueName}/jobs/{jobID:[0-9]+}/logs/stream").Methods("GET").Name(ExecutorLogStream)
 
 	// repo contains routes that are NOT specific to a revision. In these routes, the URL may not contain a revspec after the repo (that is, no "github.com/foo/bar@myrevspec").
 	repoPath := `/repos/` + routevar.Repo
@@ -233,7 +233,7 @@ func (s *Server) cleanupRepos(ctx context.Context, gitServerAddrs gitserver.GitS
 			return
 		}
 
-		if !s.hostnameMatch(addr) {
+		if !s.hostnameMatch(addr) && !s.ownsAfterRebalance(bCtx, name, gitServerAddrs) && !s.isReplica(bCtx, name, gitServerAddrs) {
 			wrongShardRepoCount++
 			wrongShardRepoSize += size
 
@@ -228,6 +228,42 @@ func TestCleanupWrongShard(t *testing.T) {
 			t.Error("expected repoD assigned to different shard to be removed")
 		}
 	})
+	t.Run("rebalanceInProgress", func(t *testing.T) {
+		root := t.TempDir()
+		// should be allocated to shard gitserver-1, but is moving to gitserver-0
+		testRepoD := "testrepo-D"
+
+		repoA := path.Join(root, testRepoA, ".git")
+		cmd := exec.Command("git", "--bare", "init", repoA)
+		if err := cmd.Run(); err != nil {
+			t.Fatal(err)
+		}
+		repoD := path.Join(root, testRepoD, ".git")
+		cmdD := exec.Command("git", "--bare", "init", repoD)
+		if err := cmdD.Run(); err != nil {
+			t.Fatal(err)
+		}
+
+		s := &Server{
+			ReposDir:       root,
+			Logger:         logtest.Scoped(t),
+			ObservationCtx: observation.TestContextTB(t),
+			DB:             database.NewMockDB(),
+		}
+		s.testSetup(t)
+		s.Hostname = "gitserver-0"
+		s.cleanupRepos(context.Background(), gitserver.GitServerAddresses{
+			Addresses: []string{"gitserver-0", "gitserver-1"},
+			Next:      &gitserver.GitServerAddresses{Addresses: []string{"gitserver-0"}},
+		})
+
+		if _, err := os.Stat(repoA); err != nil {
+			t.Error("expected repoA not to be removed")
+		}
+		if _, err := os.Stat(repoD); err != nil {
+			t.Error("expected repoD moving to this shard not to be removed")
+		}
+	})
 	t.Run("cleanupDisabled", func(t *testing.T) {
 		root := t.TempDir()
 		// should be allocated to shard gitserver-1
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// diskQuotaExceededError is returned when a repository is not cloned or fetched
+// because it exceeds a disk quota of a code host connection it is synced by.
+type diskQuotaExceededError struct {
+	repo    api.RepoName
+	service string
+	perRepo bool
+	size    int64
+	quota   int64
+}
+
+func (e *diskQuotaExceededError) Error() string {
+	if e.perRepo {
+		return fmt.Sprintf("repository %s uses %s of disk space, which exceeds the repository disk quota of %s of code host connection %q",
+			e.repo, humanize.IBytes(uint64(e.size)), humanize.IBytes(uint64(e.quota)), e.service)
+	}
+	return fmt.Sprintf("repositories of code host connection %q use %s of disk space with repository %s, which exceeds its disk quota of %s",
+		e.service, humanize.IBytes(uint64(e.size)), e.repo, humanize.IBytes(uint64(e.quota)))
+}
+
+// checkDiskQuotas returns a diskQuotaExceededError if repo exceeds the disk
+// quotas of one of the code host connections it is synced by when it uses size
+// bytes on disk. If size is nil, the size last recorded for repo is used, so
+// that fetches don't have to walk the repo.
+//
+// The quotas are read from the site configuration, so repos are only looked up
+// in the database if a quota is set.
+func (s *Server) checkDiskQuotas(ctx context.Context, repo api.RepoName, size *int64) error {
+	quotas := conf.Get().CodeHostDiskQuotas
+	if len(quotas) == 0 {
+		return nil
+	}
+
+	// The quotas apply to every repo, regardless of who triggered the clone or
+	// fetch.
+	ctx = actor.WithInternalActor(ctx)
+
+	r, err := s.DB.Repos().GetByName(ctx, repo)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "get repo")
+	}
+
+	var (
+		repoSize     int64
+		recordedSize *int64
+	)
+	for _, id := range r.ExternalServiceIDs() {
+		dq, err := extsvc.ExtractDiskQuotas(quotas, id)
+		if err != nil {
+			return errors.Wrapf(err, "disk quotas of external service %d", id)
+		}
+		if dq.PerRepo == 0 && dq.Total == 0 {
+			continue
+		}
+
+		if recordedSize == nil {
+			recorded, err := s.recordedRepoSize(ctx, repo)
+			if err != nil {
+				return err
+			}
+			recordedSize = &recorded
+			repoSize = recorded
+			if size != nil {
+				repoSize = *size
+			}
+		}
+
+		if dq.PerRepo > 0 && repoSize > dq.PerRepo {
+			return s.diskQuotaExceeded(ctx, &diskQuotaExceededError{repo: repo, perRepo: true, size: repoSize, quota: dq.PerRepo}, id)
+		}
+		if dq.Total > 0 {
+			usage, err := s.DB.ExternalServices().DiskUsage(ctx, id)
+			if err != nil {
+				return errors.Wrapf(err, "disk usage of external service %d", id)
+			}
+			// The usage includes the size of repo when it was last recorded.
+			usage += repoSize - *recordedSize
+			if usage > dq.Total {
+				return s.diskQuotaExceeded(ctx, &diskQuotaExceededError{repo: repo, size: usage, quota: dq.Total}, id)
+			}
+		}
+	}
+	return nil
+}
+
+// diskQuotaExceeded sets the name of the code host connection with the given
+// id in err and returns it.
+func (s *Server) diskQuotaExceeded(ctx context.Context, err *diskQuotaExceededError, id int64) error {
+	err.service = strconv.FormatInt(id, 10)
+	if svc, getErr := s.DB.ExternalServices().GetByID(ctx, id); getErr == nil {
+		err.service = svc.DisplayName
+	}
+	return err
+}
+
+// recordedRepoSize returns the size of repo included in the disk usage of its
+// code host connections, which is zero unless it is cloned.
+func (s *Server) recordedRepoSize(ctx context.Context, repo api.RepoName) (int64, error) {
+	gr, err := s.DB.GitserverRepos().GetByName(ctx, repo)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, errors.Wrap(err, "get gitserver repo")
+	}
+	if gr.CloneStatus != types.CloneStatusCloned {
+		return 0, nil
+	}
+	return gr.RepoSizeBytes, nil
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestCheckDiskQuotas(t *testing.T) {
+	ctx := context.Background()
+	repo := api.RepoName("github.com/foo/data")
+
+	newServer := func(quotas map[string]schema.DiskQuota, usage int64, recorded *types.GitserverRepo) *Server {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{CodeHostDiskQuotas: quotas}})
+		t.Cleanup(func() { conf.Mock(nil) })
+
+		repos := database.NewMockRepoStore()
+		repos.GetByNameFunc.SetDefaultReturn(&types.Repo{
+			Name: repo,
+			Sources: map[string]*types.SourceInfo{
+				extsvc.URN(extsvc.KindGitHub, 1): {},
+			},
+		}, nil)
+
+		externalServices := database.NewMockExternalServiceStore()
+		externalServices.GetByIDFunc.SetDefaultReturn(&types.ExternalService{ID: 1, Kind: extsvc.KindGitHub, DisplayName: "GitHub"}, nil)
+		externalServices.DiskUsageFunc.SetDefaultReturn(usage, nil)
+
+		gitserverRepos := database.NewMockGitserverRepoStore()
+		if recorded != nil {
+			gitserverRepos.GetByNameFunc.SetDefaultReturn(recorded, nil)
+		} else {
+			gitserverRepos.GetByNameFunc.SetDefaultReturn(nil, &database.RepoNotFoundErr{Name: repo})
+		}
+
+		db := database.NewMockDB()
+		db.ReposFunc.SetDefaultReturn(repos)
+		db.ExternalServicesFunc.SetDefaultReturn(externalServices)
+		db.GitserverReposFunc.SetDefaultReturn(gitserverRepos)
+		return &Server{DB: db}
+	}
+	size := func(n int64) *int64 {
+		return &n
+	}
+
+	t.Run("no quotas", func(t *testing.T) {
+		s := newServer(nil, 0, nil)
+		if err := s.checkDiskQuotas(ctx, repo, size(1<<40)); err != nil {
+			t.Fatal(err)
+		}
+		if calls := len(s.DB.Repos().(*database.MockRepoStore).GetByNameFunc.History()); calls != 0 {
+			t.Fatalf("want no repo lookups without quotas, got %d", calls)
+		}
+	})
+
+	t.Run("quotas of another connection", func(t *testing.T) {
+		s := newServer(map[string]schema.DiskQuota{"2": {PerRepo: "1k"}}, 0, nil)
+		if err := s.checkDiskQuotas(ctx, repo, size(1<<40)); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("repo quota", func(t *testing.T) {
+		s := newServer(map[string]schema.DiskQuota{"1": {PerRepo: "1k"}}, 0, nil)
+		if err := s.checkDiskQuotas(ctx, repo, size(1024)); err != nil {
+			t.Fatal(err)
+		}
+
+		err := s.checkDiskQuotas(ctx, repo, size(1025))
+		var quotaErr *diskQuotaExceededError
+		if !errors.As(err, &quotaErr) || !quotaErr.perRepo || quotaErr.service != "GitHub" {
+			t.Fatalf("want repo disk quota error, got %v", err)
+		}
+	})
+
+	t.Run("total quota", func(t *testing.T) {
+		s := newServer(map[string]schema.DiskQuota{"1": {Total: "1k"}}, 1000, nil)
+		if err := s.checkDiskQuotas(ctx, repo, size(24)); err != nil {
+			t.Fatal(err)
+		}
+
+		err := s.checkDiskQuotas(ctx, repo, size(25))
+		var quotaErr *diskQuotaExceededError
+		if !errors.As(err, &quotaErr) || quotaErr.perRepo || quotaErr.size != 1025 {
+			t.Fatalf("want disk quota error, got %v", err)
+		}
+	})
+
+	t.Run("total quota counts the repo once", func(t *testing.T) {
+		recorded := &types.GitserverRepo{RepoID: 1, CloneStatus: types.CloneStatusCloned, RepoSizeBytes: 500}
+		s := newServer(map[string]schema.DiskQuota{"1": {Total: "1k"}}, 1000, recorded)
+		if err := s.checkDiskQuotas(ctx, repo, size(524)); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.checkDiskQuotas(ctx, repo, size(525)); err == nil {
+			t.Fatal("want disk quota error")
+		}
+	})
+	t.Run("fetch uses the recorded size", func(t *testing.T) {
+		recorded := &types.GitserverRepo{RepoID: 1, CloneStatus: types.CloneStatusCloned, RepoSizeBytes: 2048}
+		s := newServer(map[string]schema.DiskQuota{"1": {PerRepo: "1k"}}, 0, recorded)
+		var quotaErr *diskQuotaExceededError
+		if err := s.checkDiskQuotas(ctx, repo, nil); !errors.As(err, &quotaErr) || quotaErr.size != 2048 {
+			t.Fatalf("want repo disk quota error for the recorded size, got %v", err)
+		}
+	})
+}
@@ -80,9 +80,23 @@ func (s *Server) gitServiceHandler() *gitservice.Handler {
 			return string(s.dir(api.RepoName(d)))
 		},
 
-		// Limit rate of stdout from git.
 		CommandHook: func(cmd *exec.Cmd) {
+			// Limit rate of stdout from git.
 			cmd.Stdout = flowrateWriter(logger, cmd.Stdout)
+
+			// Replicas of a partial clone fetch it with the same filter, but
+			// upload-pack may still have to read a missing object. The GIT_DIR
+			// is the last argument of the command.
+			dir := GitDir(cmd.Args[len(cmd.Args)-1])
+			if !isPartialClone(dir) {
+				return
+			}
+			remoteURL, err := s.promisorRemoteURL(s.ctx, s.name(dir))
+			if err != nil {
+				logger.Warn("failed to get remote URL of partial clone", log.String("dir", string(dir)), log.Error(err))
+				return
+			}
+			cmd.Env = append(cmd.Env, promisorRemoteEnv(remoteURL, tlsExternal())...)
 		},
 
 		Trace: func(ctx context.Context, svc, repo, protocol string) func(error) {
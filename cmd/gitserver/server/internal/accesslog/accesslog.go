@@ -9,6 +9,7 @@ import (
 
 	"github.com/sourcegraph/log"
 	"go.uber.org/atomic"
+	"google.golang.org/grpc"
 
 	"github.com/sourcegraph/sourcegraph/internal/audit"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
@@ -64,9 +65,15 @@ const (
 func (a *accessLogger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Prepare the context to hold the params which the handler is going to set.
 	ctx := r.Context()
-	r = r.WithContext(withContext(ctx, &paramsContext{}))
+	pc := &paramsContext{}
+	r = r.WithContext(withContext(ctx, pc))
 	a.next(w, r)
 
+	a.log(ctx, pc)
+}
+
+// log logs the access recorded in pc if access logging is enabled.
+func (a *accessLogger) log(ctx context.Context, paramsCtx *paramsContext) {
 	// If access logging is not enabled, we are done
 	if !a.logEnabled.Load() {
 		return
@@ -77,7 +84,6 @@ func (a *accessLogger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Now we've gone through the handler, we can get the params that the handler
 	// got from the request body.
-	paramsCtx := fromContext(r.Context())
 	if paramsCtx == nil {
 		return
 	}
@@ -99,9 +105,7 @@ func (a *accessLogger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// HTTPMiddleware will extract actor information and params collected by Record that has
-// been stored in the context, in order to log a trace of the access.
-func HTTPMiddleware(logger log.Logger, watcher conftypes.WatchableSiteConfig, next http.HandlerFunc) http.HandlerFunc {
+func newAccessLogger(logger log.Logger, watcher conftypes.WatchableSiteConfig, next http.HandlerFunc) *accessLogger {
 	handler := &accessLogger{
 		logger:     logger,
 		next:       next,
@@ -124,5 +128,36 @@ func HTTPMiddleware(logger log.Logger, watcher conftypes.WatchableSiteConfig, ne
 		}
 	})
 
-	return handler.ServeHTTP
+	return handler
+}
+
+// HTTPMiddleware will extract actor information and params collected by Record that has
+// been stored in the context, in order to log a trace of the access.
+func HTTPMiddleware(logger log.Logger, watcher conftypes.WatchableSiteConfig, next http.HandlerFunc) http.HandlerFunc {
+	return newAccessLogger(logger, watcher, next).ServeHTTP
+}
+
+// StreamServerInterceptor is the equivalent of HTTPMiddleware for streaming gRPC
+// methods.
+func StreamServerInterceptor(logger log.Logger, watcher conftypes.WatchableSiteConfig) grpc.StreamServerInterceptor {
+	a := newAccessLogger(logger, watcher, nil)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		// Prepare the context to hold the params which the handler is going to set.
+		ctx := ss.Context()
+		pc := &paramsContext{}
+		err := handler(srv, &serverStreamWithContext{ServerStream: ss, ctx: withContext(ctx, pc)})
+
+		a.log(ctx, pc)
+		return err
+	}
+}
+
+// serverStreamWithContext overrides the context of a grpc.ServerStream.
+type serverStreamWithContext struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStreamWithContext) Context() context.Context {
+	return s.ctx
 }
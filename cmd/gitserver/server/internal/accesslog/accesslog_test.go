@@ -10,6 +10,7 @@ import (
 	"github.com/sourcegraph/log/logtest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/requestclient"
@@ -143,3 +144,31 @@ func TestHTTPMiddleware(t *testing.T) {
 		assert.Contains(t, logs[1].Message, accessEventMessage)
 	})
 }
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func TestStreamServerInterceptor(t *testing.T) {
+	logger, exportLogs := logtest.Captured(t)
+	interceptor := StreamServerInterceptor(logger, &accessLogConf{})
+
+	ctx := requestclient.WithClient(context.Background(), &requestclient.Client{IP: "192.168.1.1"})
+	err := interceptor(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/gitserver.v1.GitserverService/Exec"}, func(_ any, ss grpc.ServerStream) error {
+		Record(ss.Context(), "github.com/foo/bar", log.String("cmd", "git"), log.String("args", "grep foo"))
+		return nil
+	})
+	require.NoError(t, err)
+
+	logs := exportLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, accessLoggingEnabledMessage, logs[0].Message)
+	assert.Contains(t, logs[1].Message, accessEventMessage)
+	assert.Equal(t, "github.com/foo/bar", logs[1].Fields["params"].(map[string]any)["repo"])
+
+	actorFields := logs[1].Fields["audit"].(map[string]any)["actor"].(map[string]any)
+	assert.Equal(t, "192.168.1.1", actorFields["ip"])
+}
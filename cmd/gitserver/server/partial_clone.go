@@ -0,0 +1,151 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// promisorRemote is the name of the remote from which partial clones fetch
+// missing objects. Only its options are stored in the repository, its URL is
+// passed to git in the environment so that credentials never end up on disk.
+const promisorRemote = "origin"
+
+// partialCloneFilter returns the object filter with which repo is cloned and
+// fetched according to experimentalFeatures.gitServerPartialClones, or "" if
+// repo is cloned in full. An exact match of the name wins over patterns, and the
+// longest matching pattern is used otherwise.
+func partialCloneFilter(repo api.RepoName) string {
+	limits := conf.ExperimentalFeatures().GitServerPartialClones
+
+	limit, ok := limits[string(repo)]
+	if !ok {
+		best := ""
+		for pattern, l := range limits {
+			if len(pattern) < len(best) || (len(pattern) == len(best) && pattern > best) {
+				continue
+			}
+			if gitserver.MatchRepoPattern(pattern, repo) {
+				best, limit = pattern, l
+			}
+		}
+	}
+	if limit == "" {
+		return ""
+	}
+	return "blob:limit=" + limit
+}
+
+// withPartialCloneFilter returns the syncer to use for repo. Git repositories
+// that are configured as partial clones get a GitRepoSyncer with their filter,
+// other syncers are returned as is.
+func withPartialCloneFilter(syncer VCSSyncer, repo api.RepoName) VCSSyncer {
+	if _, ok := syncer.(*GitRepoSyncer); !ok {
+		return syncer
+	}
+	if filter := partialCloneFilter(repo); filter != "" {
+		return &GitRepoSyncer{PartialCloneFilter: filter}
+	}
+	return syncer
+}
+
+// configurePartialClone turns the repository at dir into a partial clone whose
+// missing objects are fetched from promisorRemote. Fetches with filter then
+// leave out the objects it excludes.
+func configurePartialClone(dir GitDir, filter string) error {
+	for _, kv := range [][2]string{
+		{"core.repositoryformatversion", "1"},
+		{"extensions.partialClone", promisorRemote},
+		{"remote." + promisorRemote + ".promisor", "true"},
+		{"remote." + promisorRemote + ".partialclonefilter", filter},
+	} {
+		if err := gitConfigSet(dir, kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var partialCloneConfigRegex = lazyregexp.New(`(?mi)^\s*partialclone\s*=`)
+
+// isPartialClone returns true if the repository at dir is a partial clone, and
+// may have to fetch objects from promisorRemote when they are read.
+func isPartialClone(dir GitDir) bool {
+	b, err := os.ReadFile(dir.Path("config"))
+	if err != nil {
+		return false
+	}
+	return partialCloneConfigRegex.Match(b)
+}
+
+// promisorRemoteEnv returns the environment variables that let git fetch from
+// promisorRemote at remoteURL, with the same options configureRemoteGitCommand
+// applies. Git fetches missing objects of a partial clone on its own, so the
+// options are passed in the environment rather than as arguments.
+func promisorRemoteEnv(remoteURL *vcs.URL, tlsConf *tlsConfig) []string {
+	env := remoteGitEnv(tlsConf)
+	return append(env,
+		"GIT_CONFIG_COUNT=3",
+		"GIT_CONFIG_KEY_0=remote."+promisorRemote+".url",
+		"GIT_CONFIG_VALUE_0="+remoteURL.String(),
+		// Unset credential helper because the command is non-interactive.
+		"GIT_CONFIG_KEY_1=credential.helper",
+		"GIT_CONFIG_VALUE_1=",
+		"GIT_CONFIG_KEY_2=protocol.version",
+		"GIT_CONFIG_VALUE_2=2",
+	)
+}
+
+// promisorRemoteURL returns the URL from which the partial clone of repo
+// fetches missing objects. That is always the code host, even on replicas,
+// because the gitserver owning repo does not have the missing objects either.
+func (s *Server) promisorRemoteURL(ctx context.Context, repo api.RepoName) (*vcs.URL, error) {
+	// We may be fetching from a private repo so we need an internal actor.
+	return s.getRemoteURL(actor.WithInternalActor(ctx), repo)
+}
+
+// prefetchMissingBlobs fetches the blobs of the tree treeish that are missing
+// from the partial clone at dir in a single request. Without it, git archive
+// fetches every missing blob on its own.
+func prefetchMissingBlobs(ctx context.Context, dir GitDir, remoteURL *vcs.URL, treeish string) error {
+	cmd := exec.CommandContext(ctx, "git", "rev-list", "--objects", "--missing=print", treeish+"^{tree}")
+	dir.Set(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return errors.Wrap(wrapCmdError(cmd, err), "listing missing objects")
+	}
+
+	var missing bytes.Buffer
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "?") {
+			missing.WriteString(line[1:])
+			missing.WriteByte('\n')
+		}
+	}
+	if missing.Len() == 0 {
+		return nil
+	}
+
+	// This is the fetch git runs to get missing objects of a partial clone,
+	// batched for all of them.
+	cmd = exec.CommandContext(ctx, "git", "-c", "fetch.negotiationAlgorithm=noop", "fetch",
+		"--no-tags", "--no-write-fetch-head", "--recurse-submodules=no", "--filter=blob:none",
+		"--stdin", promisorRemote)
+	cmd.Env = append(os.Environ(), promisorRemoteEnv(remoteURL, tlsExternal())...)
+	dir.Set(cmd)
+	cmd.Stdin = &missing
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrap(&GitCommandError{Err: err, Output: newURLRedactor(remoteURL).redact(string(out))}, "fetching missing objects")
+	}
+	return nil
+}
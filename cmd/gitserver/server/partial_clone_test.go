@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestPartialCloneFilter(t *testing.T) {
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{
+				GitServerPartialClones: map[string]string{
+					"github.com/foo/assets": "1m",
+					"github.com/foo/*":      "10m",
+					"github.com/*/*":        "100m",
+				},
+			},
+		},
+	})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	for repo, want := range map[api.RepoName]string{
+		"github.com/foo/assets":  "blob:limit=1m",
+		"github.com/foo/code":    "blob:limit=10m",
+		"github.com/bar/code":    "blob:limit=100m",
+		"gitlab.com/foo/code":    "",
+		"github.com/foo/sub/dir": "",
+	} {
+		if got := partialCloneFilter(repo); got != want {
+			t.Errorf("partialCloneFilter(%q) = %q, want %q", repo, got, want)
+		}
+	}
+
+	if _, ok := withPartialCloneFilter(&GitRepoSyncer{}, "gitlab.com/foo/code").(*GitRepoSyncer); !ok {
+		t.Fatal("want GitRepoSyncer")
+	}
+	if s := withPartialCloneFilter(&GitRepoSyncer{}, "github.com/foo/code").(*GitRepoSyncer); s.PartialCloneFilter != "blob:limit=10m" {
+		t.Fatalf("unexpected filter %q", s.PartialCloneFilter)
+	}
+	if s := withPartialCloneFilter(&PerforceDepotSyncer{}, "github.com/foo/code"); s.Type() != "perforce" {
+		t.Fatalf("unexpected syncer %q", s.Type())
+	}
+}
+
+func TestGitRepoSyncer_PartialClone(t *testing.T) {
+	ctx := context.Background()
+
+	remote := t.TempDir()
+	cmd := func(name string, arg ...string) string {
+		return runCmd(t, remote, name, arg...)
+	}
+	cmd("git", "init", ".")
+	cmd("git", "config", "uploadpack.allowFilter", "true")
+	cmd("git", "config", "uploadpack.allowAnySHA1InWant", "true")
+	cmd("sh", "-c", "echo hello world > small.txt")
+	cmd("sh", "-c", "head -c 4096 /dev/zero > large.bin")
+	cmd("git", "add", "small.txt", "large.bin")
+	cmd("git", "commit", "-m", "assets")
+
+	remoteURL, err := vcs.ParseURL("file://" + remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncer := &GitRepoSyncer{PartialCloneFilter: "blob:limit=1k"}
+
+	clone := func(t *testing.T) GitDir {
+		dir := GitDir(filepath.Join(t.TempDir(), ".git"))
+		c, err := syncer.CloneCommand(ctx, remoteURL, string(dir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out, err := runWith(ctx, c, true, nil); err != nil {
+			t.Fatalf("clone failed: %s: %s", err, out)
+		}
+		if !isPartialClone(dir) {
+			t.Fatal("want partial clone")
+		}
+		return dir
+	}
+
+	missingObjects := func(t *testing.T, dir GitDir) int {
+		c := exec.Command("git", "rev-list", "--objects", "--missing=print", "HEAD")
+		dir.Set(c)
+		out, err := c.Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Count(string(out), "\n?")
+	}
+
+	t.Run("lazy fetch", func(t *testing.T) {
+		dir := clone(t)
+		if n := missingObjects(t, dir); n != 1 {
+			t.Fatalf("want 1 missing object, got %d", n)
+		}
+
+		c := exec.Command("git", "cat-file", "-s", "HEAD:large.bin")
+		dir.Set(c)
+		c.Env = append(c.Env, promisorRemoteEnv(remoteURL, &tlsConfig{})...)
+		out, err := c.CombinedOutput()
+		if err != nil {
+			t.Fatalf("cat-file failed: %s: %s", err, out)
+		}
+		if got := strings.TrimSpace(string(out)); got != "4096" {
+			t.Fatalf("unexpected size %q", got)
+		}
+
+		if err := syncer.Fetch(ctx, remoteURL, dir, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("prefetch", func(t *testing.T) {
+		dir := clone(t)
+		if err := prefetchMissingBlobs(ctx, dir, remoteURL, "HEAD"); err != nil {
+			t.Fatal(err)
+		}
+		if n := missingObjects(t, dir); n != 0 {
+			t.Fatalf("want no missing objects, got %d", n)
+		}
+	})
+
+	t.Run("full clone", func(t *testing.T) {
+		dir := GitDir(filepath.Join(t.TempDir(), ".git"))
+		c, err := (&GitRepoSyncer{}).CloneCommand(ctx, remoteURL, string(dir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out, err := runWith(ctx, c, true, nil); err != nil {
+			t.Fatalf("clone failed: %s: %s", err, out)
+		}
+		if isPartialClone(dir) {
+			t.Fatal("want full clone")
+		}
+	})
+}
+
+func TestArchiveTreeish(t *testing.T) {
+	for _, tc := range []struct {
+		args    []string
+		treeish string
+		ok      bool
+	}{
+		{archiveExecRequest("repo", "HEAD", "zip", nil).Args, "HEAD", true},
+		{archiveExecRequest("repo", "HEAD", "tar", []string{"dir"}).Args, "", false},
+		{[]string{"archive", "--format=tar", "-o", "--"}, "", false},
+		{[]string{"log", "HEAD", "--"}, "", false},
+	} {
+		treeish, ok := archiveTreeish(tc.args)
+		if treeish != tc.treeish || ok != tc.ok {
+			t.Errorf("archiveTreeish(%q) = %q, %v, want %q, %v", tc.args, treeish, ok, tc.treeish, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// replicaAddrs returns the addresses of the gitservers holding a copy of repo,
+// starting with its owner.
+func (s *Server) replicaAddrs(ctx context.Context, repo api.RepoName, gitServerAddrs gitserver.GitServerAddresses) ([]string, error) {
+	return gitserver.ReplicaAddrsForRepo(ctx, filepath.Base(os.Args[0]), repo, gitServerAddrs)
+}
+
+// isReplica returns true if this gitserver holds a replica of repo, but does not
+// own it.
+func (s *Server) isReplica(ctx context.Context, repo api.RepoName, gitServerAddrs gitserver.GitServerAddresses) bool {
+	_, ok := s.replicaOf(ctx, repo, gitServerAddrs)
+	return ok
+}
+
+// replicaOf returns the address of the owner of repo and true if this gitserver
+// holds a replica of repo. Replicas clone and fetch repo from its owner.
+func (s *Server) replicaOf(ctx context.Context, repo api.RepoName, gitServerAddrs gitserver.GitServerAddresses) (string, bool) {
+	addrs, err := s.replicaAddrs(ctx, repo, gitServerAddrs)
+	if err != nil || len(addrs) <= 1 || s.hostnameMatch(addrs[0]) {
+		return "", false
+	}
+	for _, addr := range addrs[1:] {
+		if s.hostnameMatch(addr) {
+			return addrs[0], true
+		}
+	}
+	return "", false
+}
+
+// replicaLacksRevision returns true if this gitserver holds a replica of repo
+// that does not have rev, even after ensureRevision fetched from the owner. The
+// owner may have fetched rev from the code host since the replica was last
+// updated, so requests for rev are answered as if the replica had not cloned
+// repo, which makes clients retry them on the owner.
+func (s *Server) replicaLacksRevision(ctx context.Context, repo api.RepoName, rev string, dir GitDir, gitServerAddrs gitserver.GitServerAddresses) bool {
+	if rev == "" || rev == "HEAD" {
+		return false
+	}
+	return s.isReplica(ctx, repo, gitServerAddrs) && !revisionExists(dir, rev)
+}
+
+// shardRemoteURL returns the URL from which repo can be cloned or fetched from
+// the gitserver at shard.
+func shardRemoteURL(shard string, repo api.RepoName) (*vcs.URL, error) {
+	remoteURL, err := vcs.ParseURL(shard)
+	if err != nil {
+		return nil, err
+	}
+	return remoteURL.JoinPath("git", string(repo)), nil
+}
+
+// requestReplicaUpdate asks the gitserver at replica to clone or fetch repo from
+// the gitserver at owner. It is replaced in tests.
+var requestReplicaUpdate = func(ctx context.Context, repo api.RepoName, owner, replica string) error {
+	resp, err := gitserver.NewClient().RequestRepoMigrate(ctx, repo, owner, replica)
+	if err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
+
+// syncReplicas asks the replicas of repo, if any, to fetch it from this
+// gitserver. It is called by the owner of repo after it has been cloned or
+// updated, and does not block.
+func (s *Server) syncReplicas(ctx context.Context, repo api.RepoName) {
+	addrs, err := s.replicaAddrs(ctx, repo, currentGitserverAddresses())
+	if err != nil || len(addrs) <= 1 || !s.hostnameMatch(addrs[0]) {
+		return
+	}
+
+	logger := s.Logger.Scoped("syncReplicas", "updates the replicas of a repository").With(log.String("repo", string(repo)))
+	for _, replica := range addrs[1:] {
+		replica := replica
+		go func() {
+			ctx, cancel := s.serverContext()
+			defer cancel()
+			ctx, cancel2 := context.WithTimeout(ctx, conf.GitLongCommandTimeout())
+			defer cancel2()
+
+			if err := requestReplicaUpdate(ctx, repo, addrs[0], replica); err != nil {
+				logger.Warn("failed to update replica", log.String("replica", replica), log.Error(err))
+			}
+		}()
+	}
+}
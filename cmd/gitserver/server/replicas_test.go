@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+func TestReplicaOf(t *testing.T) {
+	ctx := context.Background()
+	repo := api.RepoName("github.com/sourcegraph/monorepo")
+	addrs := gitserver.GitServerAddresses{
+		Addresses:       []string{"gitserver-0:3178", "gitserver-1:3178", "gitserver-2:3178"},
+		Sharding:        gitserver.ShardingRendezvous,
+		ReplicatedRepos: map[string]int{string(repo): 2},
+	}
+	replicas, err := gitserver.ReplicaAddrsForRepo(ctx, "test", repo, addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replicas) != 2 {
+		t.Fatalf("want 2 replicas, got %q", replicas)
+	}
+
+	for _, addr := range addrs.Addresses {
+		s := &Server{Hostname: addr[:len("gitserver-0")]}
+		owner, ok := s.replicaOf(ctx, repo, addrs)
+		switch addr {
+		case replicas[0]:
+			if ok {
+				t.Fatalf("owner %q is not a replica", addr)
+			}
+		case replicas[1]:
+			if !ok || owner != replicas[0] {
+				t.Fatalf("want %q to replicate %q, got %q, %v", addr, replicas[0], owner, ok)
+			}
+		default:
+			if ok {
+				t.Fatalf("%q is not a replica", addr)
+			}
+		}
+	}
+
+	// Repositories without a replication factor have no replicas.
+	s := &Server{Hostname: "gitserver-0"}
+	if s.isReplica(ctx, "github.com/sourcegraph/other", addrs) {
+		t.Fatal("want no replicas")
+	}
+}
+
+func TestReplicaLacksRevision(t *testing.T) {
+	ctx := context.Background()
+	repo := api.RepoName("github.com/sourcegraph/monorepo")
+	addrs := gitserver.GitServerAddresses{
+		Addresses:       []string{"gitserver-0:3178", "gitserver-1:3178"},
+		Sharding:        gitserver.ShardingRendezvous,
+		ReplicatedRepos: map[string]int{string(repo): 2},
+	}
+	replicas, err := gitserver.ReplicaAddrsForRepo(ctx, "test", repo, addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := &Server{Hostname: replicas[0][:len("gitserver-0")]}
+	replica := &Server{Hostname: replicas[1][:len("gitserver-0")]}
+
+	dir := t.TempDir()
+	cmd := func(name string, arg ...string) string {
+		return runCmd(t, dir, name, arg...)
+	}
+	head := strings.TrimSpace(makeSingleCommitRepo(cmd))
+	gitDir := GitDir(filepath.Join(dir, ".git"))
+
+	for _, tc := range []struct {
+		name string
+		s    *Server
+		rev  string
+		want bool
+	}{
+		{name: "replica has revision", s: replica, rev: head},
+		{name: "HEAD", s: replica, rev: "HEAD"},
+		{name: "replica lacks commit", s: replica, rev: strings.Repeat("a", 40), want: true},
+		{name: "replica lacks branch", s: replica, rev: "refs/heads/missing", want: true},
+		{name: "owner lacks commit", s: owner, rev: strings.Repeat("a", 40)},
+		{name: "no revision", s: replica, rev: ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if have := tc.s.replicaLacksRevision(ctx, repo, tc.rev, gitDir, addrs); have != tc.want {
+				t.Fatalf("want %v, got %v", tc.want, have)
+			}
+		})
+	}
+}
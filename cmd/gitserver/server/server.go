@@ -33,6 +33,8 @@ import (
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
 	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 
 	"github.com/sourcegraph/log"
 
@@ -46,8 +48,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/adapters"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/proto"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/search"
+	internalgrpc "github.com/sourcegraph/sourcegraph/internal/grpc"
+	"github.com/sourcegraph/sourcegraph/internal/grpc/defaults"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
@@ -548,8 +553,19 @@ func (s *Server) Handler() http.Handler {
 			handleGetObject(s.Logger.Scoped("commands/get-object", "handles get object"), getObjectFunc),
 		)))
 
-	// 🚨 SECURITY: This must be wrapped in headerXRequestedWithMiddleware.
-	return headerXRequestedWithMiddleware(mux)
+	grpcServer := grpc.NewServer(append(
+		defaults.ServerOptions(s.Logger),
+		grpc.ChainStreamInterceptor(accesslog.StreamServerInterceptor(
+			s.Logger.Scoped("grpc.accesslog", "gRPC access log"),
+			conf.DefaultClient(),
+		)),
+	)...)
+	reflection.Register(grpcServer)
+	proto.RegisterGitserverServiceServer(grpcServer, &GRPCServer{Server: s})
+
+	// 🚨 SECURITY: The HTTP handlers must be wrapped in headerXRequestedWithMiddleware.
+	// gRPC requests cannot be made by browsers, so they are exempt.
+	return internalgrpc.MultiplexHandlers(grpcServer, headerXRequestedWithMiddleware(mux))
 }
 
 // Janitor does clean up tasks over s.ReposDir and is expected to run in a
@@ -572,9 +588,10 @@ func (s *Server) SyncRepoState(interval time.Duration, batchSize, perSecond int)
 	for {
 		gitServerAddrs := currentGitserverAddresses()
 		addrs := gitServerAddrs.Addresses
-		// We turn addrs into a string here for easy comparison and storage of previous
-		// addresses since we'd need to take a copy of the slice anyway.
-		currentAddrs := strings.Join(addrs, ",")
+		// We turn addrs and the sharding scheme into a string here for easy comparison
+		// and storage of previous addresses since we'd need to take a copy of the slice
+		// anyway.
+		currentAddrs := gitServerAddrs.Sharding + ":" + strings.Join(addrs, ",")
 		fullSync := currentAddrs != previousAddrs
 		previousAddrs = currentAddrs
 
@@ -601,16 +618,32 @@ func (s *Server) addrForRepo(ctx context.Context, repoName api.RepoName, gitServ
 	return gitserver.AddrForRepo(ctx, filepath.Base(os.Args[0]), repoName, gitServerAddrs)
 }
 
+// ownsAfterRebalance returns true if a rebalance is in progress and the repo has been
+// moved to this shard by it.
+func (s *Server) ownsAfterRebalance(ctx context.Context, repoName api.RepoName, gitServerAddrs gitserver.GitServerAddresses) bool {
+	if gitServerAddrs.Next == nil {
+		return false
+	}
+	addr, err := s.addrForRepo(ctx, repoName, *gitServerAddrs.Next)
+	return err == nil && s.hostnameMatch(addr)
+}
+
 func currentGitserverAddresses() gitserver.GitServerAddresses {
 	cfg := conf.Get()
-	gitServerAddrs := gitserver.GitServerAddresses{
-		Addresses: cfg.ServiceConnectionConfig.GitServers,
-	}
+	var pinned map[string]string
 	if cfg.ExperimentalFeatures != nil {
-		gitServerAddrs.PinnedServers = cfg.ExperimentalFeatures.GitServerPinnedRepos
+		pinned = cfg.ExperimentalFeatures.GitServerPinnedRepos
 	}
 
-	return gitServerAddrs
+	addrs := gitserver.NewGitServerAddresses(
+		cfg.ServiceConnectionConfig.GitServers,
+		cfg.ServiceConnectionConfig.GitServerRouting,
+		pinned,
+	)
+	if cfg.ExperimentalFeatures != nil {
+		addrs.ReplicatedRepos = cfg.ExperimentalFeatures.GitServerReplicatedRepos
+	}
+	return addrs
 }
 
 // StartClonePipeline clones repos asynchronously. It creates a producer-consumer
@@ -744,6 +777,14 @@ func (s *Server) syncRepoState(gitServerAddrs gitserver.GitServerAddresses, batc
 		}
 	}
 	if !found {
+		// A shard that is being added by a rebalance does not own any repos yet.
+		if next := gitServerAddrs.Next; next != nil {
+			for _, a := range next.Addresses {
+				if s.hostnameMatch(a) {
+					return nil
+				}
+			}
+		}
 		return errors.Errorf("gitserver hostname, %q, not found in list", s.Hostname)
 	}
 
@@ -1136,8 +1177,14 @@ func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.exec(w, r, archiveExecRequest(api.RepoName(repo), treeish, format, pathspecs))
+}
+
+// archiveExecRequest returns the request to run git archive for the given tree of
+// repo.
+func archiveExecRequest(repo api.RepoName, treeish, format string, pathspecs []string) *protocol.ExecRequest {
 	req := &protocol.ExecRequest{
-		Repo: api.RepoName(repo),
+		Repo: repo,
 		Args: []string{
 			"archive",
 
@@ -1162,7 +1209,20 @@ func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
 	req.Args = append(req.Args, treeish, "--")
 	req.Args = append(req.Args, pathspecs...)
 
-	s.exec(w, r, req)
+	return req
+}
+
+// archiveTreeish returns the tree archived by args, a git archive command built
+// by archiveExecRequest, if it archives the whole tree.
+func archiveTreeish(args []string) (string, bool) {
+	if len(args) < 2 || args[0] != "archive" || args[len(args)-1] != "--" {
+		return "", false
+	}
+	treeish := args[len(args)-2]
+	if checkSpecArgSafety(treeish) != nil {
+		return "", false
+	}
+	return treeish, true
 }
 
 func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
@@ -1177,21 +1237,6 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	tr.SetAttributes(
-		attribute.String("repo", string(args.Repo)),
-		attribute.Bool("include_diff", args.IncludeDiff),
-		attribute.String("query", args.Query.String()),
-		attribute.Int("limit", args.Limit),
-		attribute.Bool("include_modified_files", args.IncludeModifiedFiles),
-	)
-
-	searchStart := time.Now()
-	searchRunning.Inc()
-	defer searchRunning.Dec()
-
-	observeLatency := syncx.OnceFunc(func() {
-		searchLatency.Observe(time.Since(searchStart).Seconds())
-	})
 
 	eventWriter, err := streamhttp.NewWriter(w)
 	if err != nil {
@@ -1202,54 +1247,106 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 
 	matchesBuf := streamhttp.NewJSONArrayBuf(8*1024, func(data []byte) error {
 		tr.AddEvent("flushing data", attribute.Int("data.len", len(data)))
-		observeLatency()
 		return eventWriter.EventBytes("matches", data)
 	})
 
 	// Run the search
-	limitHit, searchErr := s.search(ctx, &args, matchesBuf)
+	limitHit, searchErr := s.searchWithObservability(ctx, tr, &args, matchesBuf)
 	if writeErr := eventWriter.Event("done", protocol.NewSearchEventDone(limitHit, searchErr)); writeErr != nil {
 		if !errors.Is(writeErr, syscall.EPIPE) {
 			logger.Error("failed to send done event", log.Error(writeErr))
 		}
 	}
-	tr.AddEvent("done", attribute.Bool("limit_hit", limitHit))
-	tr.SetError(searchErr)
-	searchDuration.
-		WithLabelValues(strconv.FormatBool(searchErr != nil)).
-		Observe(time.Since(searchStart).Seconds())
+}
 
-	if honey.Enabled() || traceLogs {
-		act := actor.FromContext(ctx)
-		ev := honey.NewEvent("gitserver-search")
-		ev.SetSampleRate(honeySampleRate("", act))
-		ev.AddField("repo", args.Repo)
-		ev.AddField("revisions", args.Revisions)
-		ev.AddField("include_diff", args.IncludeDiff)
-		ev.AddField("include_modified_files", args.IncludeModifiedFiles)
-		ev.AddField("actor", act.UIDString())
-		ev.AddField("query", args.Query.String())
-		ev.AddField("limit", args.Limit)
-		ev.AddField("duration_ms", time.Since(searchStart).Milliseconds())
-		if searchErr != nil {
-			ev.AddField("error", searchErr.Error())
-		}
-		if traceID := trace.ID(ctx); traceID != "" {
-			ev.AddField("traceID", traceID)
-			ev.AddField("trace", trace.URL(traceID, conf.DefaultClient()))
-		}
-		if honey.Enabled() {
-			_ = ev.Send()
-		}
-		if traceLogs {
-			logger.Debug("TRACE gitserver search", log.Object("ev.Fields", mapToLoggerField(ev.Fields())...))
-		}
+// searchMatchesBuf buffers the matches of a search until they are flushed to the
+// client.
+type searchMatchesBuf interface {
+	// Append adds a *protocol.CommitMatch to the buffer. It may flush the buffer.
+	Append(any) error
+	// Flush sends the buffered matches to the client.
+	Flush() error
+	// Len returns the size of the buffered matches.
+	Len() int
+}
+
+var _ searchMatchesBuf = &streamhttp.JSONArrayBuf{}
+
+// latencyObservingBuf calls observe before the first non-empty flush of the
+// underlying buffer.
+type latencyObservingBuf struct {
+	searchMatchesBuf
+	observe func()
+}
+
+func (b *latencyObservingBuf) Flush() error {
+	if b.Len() > 0 {
+		b.observe()
 	}
+	return b.searchMatchesBuf.Flush()
+}
+
+// searchWithObservability runs a search with tracing, metrics and event logging.
+// It is shared by the HTTP and gRPC search endpoints.
+func (s *Server) searchWithObservability(ctx context.Context, tr *trace.Trace, args *protocol.SearchRequest, matchesBuf searchMatchesBuf) (limitHit bool, err error) {
+	logger := s.Logger.Scoped("searchWithObservability", "search with instrumentation")
+	tr.SetAttributes(
+		attribute.String("repo", string(args.Repo)),
+		attribute.Bool("include_diff", args.IncludeDiff),
+		attribute.String("query", args.Query.String()),
+		attribute.Int("limit", args.Limit),
+		attribute.Bool("include_modified_files", args.IncludeModifiedFiles),
+	)
+
+	searchStart := time.Now()
+	searchRunning.Inc()
+	defer searchRunning.Dec()
+
+	observeLatency := syncx.OnceFunc(func() {
+		searchLatency.Observe(time.Since(searchStart).Seconds())
+	})
+
+	defer func() {
+		tr.AddEvent("done", attribute.Bool("limit_hit", limitHit))
+		tr.SetError(err)
+		searchDuration.
+			WithLabelValues(strconv.FormatBool(err != nil)).
+			Observe(time.Since(searchStart).Seconds())
+
+		if honey.Enabled() || traceLogs {
+			act := actor.FromContext(ctx)
+			ev := honey.NewEvent("gitserver-search")
+			ev.SetSampleRate(honeySampleRate("", act))
+			ev.AddField("repo", args.Repo)
+			ev.AddField("revisions", args.Revisions)
+			ev.AddField("include_diff", args.IncludeDiff)
+			ev.AddField("include_modified_files", args.IncludeModifiedFiles)
+			ev.AddField("actor", act.UIDString())
+			ev.AddField("query", args.Query.String())
+			ev.AddField("limit", args.Limit)
+			ev.AddField("duration_ms", time.Since(searchStart).Milliseconds())
+			if err != nil {
+				ev.AddField("error", err.Error())
+			}
+			if traceID := trace.ID(ctx); traceID != "" {
+				ev.AddField("traceID", traceID)
+				ev.AddField("trace", trace.URL(traceID, conf.DefaultClient()))
+			}
+			if honey.Enabled() {
+				_ = ev.Send()
+			}
+			if traceLogs {
+				logger.Debug("TRACE gitserver search", log.Object("ev.Fields", mapToLoggerField(ev.Fields())...))
+			}
+		}
+	}()
+
+	return s.search(ctx, args, &latencyObservingBuf{searchMatchesBuf: matchesBuf, observe: observeLatency})
 }
 
 // search handles the core logic of the search. It is passed a matchesBuf so it doesn't need to
 // concern itself with event types, and all instrumentation is handled in the calling function.
-func (s *Server) search(ctx context.Context, args *protocol.SearchRequest, matchesBuf *streamhttp.JSONArrayBuf) (limitHit bool, err error) {
+func (s *Server) search(ctx context.Context, args *protocol.SearchRequest, matchesBuf searchMatchesBuf) (limitHit bool, err error) {
 	args.Repo = protocol.NormalizeRepo(args.Repo)
 	if args.Limit == 0 {
 		args.Limit = math.MaxInt32
@@ -1293,6 +1390,9 @@ func (s *Server) search(ctx context.Context, args *protocol.SearchRequest, match
 		// TODO add result to trace
 		if rev.RevSpec != "" {
 			_ = s.ensureRevision(ctx, args.Repo, rev.RevSpec, dir)
+			if s.replicaLacksRevision(ctx, args.Repo, rev.RevSpec, dir, currentGitserverAddresses()) {
+				return false, &gitdomain.RepoNotExistError{Repo: args.Repo}
+			}
 		} else if rev.RefGlob != "" {
 			_ = s.ensureRevision(ctx, args.Repo, rev.RefGlob, dir)
 		}
@@ -1332,6 +1432,14 @@ func (s *Server) search(ctx context.Context, args *protocol.SearchRequest, match
 			IncludeDiff:          args.IncludeDiff,
 			IncludeModifiedFiles: args.IncludeModifiedFiles || hasDiffModifiesFile,
 		}
+		if isPartialClone(dir) {
+			// Diffs read blobs that partial clones may have to fetch.
+			remoteURL, err := s.promisorRemoteURL(ctx, args.Repo)
+			if err != nil {
+				return err
+			}
+			searcher.GitEnv = append(os.Environ(), promisorRemoteEnv(remoteURL, tlsExternal())...)
+		}
 
 		return searcher.Search(ctx, func(match *protocol.CommitMatch) {
 			select {
@@ -1401,6 +1509,43 @@ func (s *Server) handleBatchLog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Read request body
+	var req protocol.BatchLogRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Results are collected so that they are written in the order of the request.
+	results := make([]protocol.BatchLogResult, len(req.RepoCommits))
+	err := s.batchGitLog(r.Context(), req, func(i int, result protocol.BatchLogResult) error {
+		// Each goroutine of batchGitLog writes to a unique index exactly once, so
+		// there are no data races.
+		results[i] = result
+		return nil
+	})
+
+	// Handle unexpected error conditions. Results collected so far are dropped as
+	// nothing has been written to the client yet.
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, errInvalidBatchLogFormat) {
+			statusCode = http.StatusUnprocessableEntity
+		}
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+
+	// Write payload to client: implicitly writes 200 OK
+	_ = json.NewEncoder(w).Encode(protocol.BatchLogResponse{Results: results})
+}
+
+var errInvalidBatchLogFormat = errors.New("format parameter expected to be of the form `--format=<git log format>`")
+
+// batchGitLog runs git log for each repository and commit pair of req and invokes
+// onResult with the index of the pair in req and its result. onResult is called
+// concurrently. It is shared by the HTTP and gRPC batch log endpoints.
+func (s *Server) batchGitLog(ctx context.Context, req protocol.BatchLogRequest, onResult func(int, protocol.BatchLogResult) error) (err error) {
 	operations := s.ensureOperations()
 
 	// Run git log for a single repository.
@@ -1444,94 +1589,60 @@ func (s *Server) handleBatchLog(w http.ResponseWriter, r *http.Request) {
 		return buf.String(), true, nil
 	}
 
-	// Handles the /batch-log route
-	instrumentedHandler := func(ctx context.Context) (statusCodeOnError int, err error) {
-		ctx, logger, endObservation := operations.batchLog.With(ctx, &err, observation.Args{})
-		defer func() {
-			endObservation(1, observation.Args{LogFields: []otlog.Field{
-				otlog.Int("statusCodeOnError", statusCodeOnError),
-			}})
-		}()
+	ctx, logger, endObservation := operations.batchLog.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+	logger.AddEvent("read request", req.SpanAttributes()...)
 
-		// Read request body
-		var req protocol.BatchLogRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			return http.StatusBadRequest, err
-		}
-		logger.AddEvent("read request.body", req.SpanAttributes()...)
+	// Validate request parameters
+	if len(req.RepoCommits) == 0 {
+		return nil
+	}
+	if !strings.HasPrefix(req.Format, "--format=") {
+		return errInvalidBatchLogFormat
+	}
 
-		// Validate request parameters
-		if len(req.RepoCommits) == 0 {
-			// Early exit: implicitly writes 200 OK
-			_ = json.NewEncoder(w).Encode(protocol.BatchLogResponse{Results: []protocol.BatchLogResult{}})
-			return 0, nil
-		}
-		if !strings.HasPrefix(req.Format, "--format=") {
-			return http.StatusUnprocessableEntity, errors.New("format parameter expected to be of the form `--format=<git log format>`")
-		}
+	// Perform requests in each repository in the input batch. We perform these commands
+	// concurrently, but only allow for so many commands to be in-flight at a time so that
+	// we don't overwhelm a shard with either a large request or too many concurrent batch
+	// requests.
 
-		// Perform requests in each repository in the input batch. We perform these commands
-		// concurrently, but only allow for so many commands to be in-flight at a time so that
-		// we don't overwhelm a shard with either a large request or too many concurrent batch
-		// requests.
+	g, ctx := errgroup.WithContext(ctx)
+
+	if s.GlobalBatchLogSemaphore == nil {
+		return errors.New("s.GlobalBatchLogSemaphore not initialized")
+	}
 
-		g, ctx := errgroup.WithContext(ctx)
-		results := make([]protocol.BatchLogResult, len(req.RepoCommits))
+	for i, repoCommit := range req.RepoCommits {
+		// Avoid capture of loop variables
+		i, repoCommit := i, repoCommit
 
-		if s.GlobalBatchLogSemaphore == nil {
-			return http.StatusInternalServerError, errors.New("s.GlobalBatchLogSemaphore not initialized")
+		start := time.Now()
+		if err := s.GlobalBatchLogSemaphore.Acquire(ctx, 1); err != nil {
+			return err
 		}
+		s.operations.batchLogSemaphoreWait.Observe(time.Since(start).Seconds())
 
-		for i, repoCommit := range req.RepoCommits {
-			// Avoid capture of loop variables
-			i, repoCommit := i, repoCommit
+		g.Go(func() error {
+			defer s.GlobalBatchLogSemaphore.Release(1)
 
-			start := time.Now()
-			if err := s.GlobalBatchLogSemaphore.Acquire(ctx, 1); err != nil {
-				return http.StatusInternalServerError, err
+			output, isRepoCloned, err := performGitLogCommand(ctx, repoCommit, req.Format)
+			if err == nil && !isRepoCloned {
+				err = errors.Newf("repo not found")
+			}
+			var errMessage string
+			if err != nil {
+				errMessage = err.Error()
 			}
-			s.operations.batchLogSemaphoreWait.Observe(time.Since(start).Seconds())
-
-			g.Go(func() error {
-				defer s.GlobalBatchLogSemaphore.Release(1)
-
-				output, isRepoCloned, err := performGitLogCommand(ctx, repoCommit, req.Format)
-				if err == nil && !isRepoCloned {
-					err = errors.Newf("repo not found")
-				}
-				var errMessage string
-				if err != nil {
-					errMessage = err.Error()
-				}
-
-				// Concurrently write results to shared slice. This slice is already properly
-				// sized, and each goroutine writes to a unique index exactly once. There should
-				// be no data race conditions possible here.
 
-				results[i] = protocol.BatchLogResult{
-					RepoCommit:    repoCommit,
-					CommandOutput: output,
-					CommandError:  errMessage,
-				}
-				return nil
+			return onResult(i, protocol.BatchLogResult{
+				RepoCommit:    repoCommit,
+				CommandOutput: output,
+				CommandError:  errMessage,
 			})
-		}
-
-		if err := g.Wait(); err != nil {
-			return http.StatusInternalServerError, err
-		}
-
-		// Write payload to client: implicitly writes 200 OK
-		_ = json.NewEncoder(w).Encode(protocol.BatchLogResponse{Results: results})
-		return 0, nil
+		})
 	}
 
-	// Handle unexpected error conditions. We expect the instrumented handler to not
-	// have written the status code or any of the body if this error value is non-nil.
-	if statusCodeOnError, err := instrumentedHandler(r.Context()); err != nil {
-		http.Error(w, err.Error(), statusCodeOnError)
-		return
-	}
+	return g.Wait()
 }
 
 // ensureOperations returns the non-nil operations value supplied to this server
@@ -1589,19 +1700,74 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 		defer fw.Close()
 	}
 
+	status, err := s.execute(r.Context(), logger, req, r.UserAgent(), w, func() {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+
+		w.Header().Set("Trailer", "X-Exec-Error")
+		w.Header().Add("Trailer", "X-Exec-Exit-Status")
+		w.Header().Add("Trailer", "X-Exec-Stderr")
+		w.WriteHeader(http.StatusOK)
+	})
+	if err != nil {
+		var notFound *execNotFoundError
+		switch {
+		case errors.Is(err, errInvalidCommand):
+			logger.Warn("exec: bad command", log.String("RemoteAddr", r.RemoteAddr))
+
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("invalid command"))
+		case errors.As(err, &notFound):
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(notFound.payload)
+		}
+		return
+	}
+
+	// write trailer
+	w.Header().Set("X-Exec-Error", errorString(status.Err))
+	w.Header().Set("X-Exec-Exit-Status", strconv.Itoa(status.ExitStatus))
+	w.Header().Set("X-Exec-Stderr", status.Stderr)
+}
+
+// errInvalidCommand is returned by execute if the command is not allowed.
+var errInvalidCommand = errors.New("invalid command")
+
+// execNotFoundError is returned by execute if the repository is not cloned, or if
+// this gitserver is a replica of it that lacks the requested revision.
+type execNotFoundError struct {
+	payload *protocol.NotFoundPayload
+}
+
+func (e *execNotFoundError) Error() string {
+	if e.payload.CloneInProgress {
+		return "repository clone in progress"
+	}
+	return "repository not found"
+}
+
+// execStatus is the status of a command run by execute.
+type execStatus struct {
+	ExitStatus int
+	Stderr     string
+	Err        error
+}
+
+// execute runs the git command of req in its repository and writes the standard
+// output of the command to stdout. onStart is called once the repository is
+// available, before anything is written to stdout. The returned error is only
+// non-nil if the command was not run; failures of the command itself are
+// reported in the returned status.
+//
+// execute is shared by the HTTP and gRPC exec and archive endpoints.
+func (s *Server) execute(ctx context.Context, logger log.Logger, req *protocol.ExecRequest, userAgent string, stdout io.Writer, onStart func()) (execStatus, error) {
 	// 🚨 SECURITY: Ensure that only commands in the allowed list are executed.
 	// See https://github.com/sourcegraph/security-issues/issues/213.
 	if !gitdomain.IsAllowedGitCmd(logger, req.Args) {
 		blockedCommandExecutedCounter.Inc()
-		logger.Warn("exec: bad command", log.String("RemoteAddr", r.RemoteAddr))
-
-		w.WriteHeader(http.StatusBadRequest)
-		_, _ = w.Write([]byte("invalid command"))
-		return
+		return execStatus{}, errInvalidCommand
 	}
 
-	ctx := r.Context()
-
 	if !req.NoTimeout {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, shortGitCommandTimeout(req.Args))
@@ -1669,7 +1835,7 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 				ev.AddField("actor", act.UIDString())
 				ev.AddField("ensure_revision", req.EnsureRevision)
 				ev.AddField("ensure_revision_status", ensureRevisionStatus)
-				ev.AddField("client", r.UserAgent())
+				ev.AddField("client", userAgent)
 				ev.AddField("duration_ms", duration.Milliseconds())
 				ev.AddField("stdin_size", len(req.Stdin))
 				ev.AddField("stdout_size", stdoutN)
@@ -1712,34 +1878,27 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 		} else {
 			status = "repo-not-found"
 		}
-		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(notFoundPayload)
-		return
+		return execStatus{}, &execNotFoundError{payload: notFoundPayload}
 	}
 
 	dir := s.dir(req.Repo)
 	if s.ensureRevision(ctx, req.Repo, req.EnsureRevision, dir) {
 		ensureRevisionStatus = "fetched"
 	}
+	if s.replicaLacksRevision(ctx, req.Repo, req.EnsureRevision, dir, currentGitserverAddresses()) {
+		status = "revision-not-on-replica"
+		return execStatus{}, &execNotFoundError{payload: &protocol.NotFoundPayload{}}
+	}
 
-	w.Header().Set("Content-Type", "application/octet-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-
-	w.Header().Set("Trailer", "X-Exec-Error")
-	w.Header().Add("Trailer", "X-Exec-Exit-Status")
-	w.Header().Add("Trailer", "X-Exec-Stderr")
-	w.WriteHeader(http.StatusOK)
+	onStart()
 
 	// Special-case `git rev-parse HEAD` requests. These are invoked by search queries for every repo in scope.
 	// For searches over large repo sets (> 1k), this leads to too many child process execs, which can lead
 	// to a persistent failure mode where every exec takes > 10s, which is disastrous for gitserver performance.
 	if len(req.Args) == 2 && req.Args[0] == "rev-parse" && req.Args[1] == "HEAD" {
 		if resolved, err := quickRevParseHead(dir); err == nil && isAbsoluteRevision(resolved) {
-			_, _ = w.Write([]byte(resolved))
-			w.Header().Set("X-Exec-Error", "")
-			w.Header().Set("X-Exec-Exit-Status", "0")
-			w.Header().Set("X-Exec-Stderr", "")
-			return
+			_, _ = stdout.Write([]byte(resolved))
+			return execStatus{}, nil
 		}
 	}
 	// Special-case `git symbolic-ref HEAD` requests. These are invoked by resolvers determining the default branch of a repo.
@@ -1747,21 +1906,36 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 	// to a persistent failure mode where every exec takes > 10s, which is disastrous for gitserver performance.
 	if len(req.Args) == 2 && req.Args[0] == "symbolic-ref" && req.Args[1] == "HEAD" {
 		if resolved, err := quickSymbolicRefHead(dir); err == nil {
-			_, _ = w.Write([]byte(resolved))
-			w.Header().Set("X-Exec-Error", "")
-			w.Header().Set("X-Exec-Exit-Status", "0")
-			w.Header().Set("X-Exec-Stderr", "")
-			return
+			_, _ = stdout.Write([]byte(resolved))
+			return execStatus{}, nil
 		}
 	}
 
 	var stderrBuf bytes.Buffer
-	stdoutW := &writeCounter{w: w}
+	stdoutW := &writeCounter{w: stdout}
 	stderrW := &writeCounter{w: &limitWriter{W: &stderrBuf, N: 1024}}
 
+	// Partial clones fetch the blobs they are missing from the code host while
+	// the command runs.
+	var remoteURL *vcs.URL
+	if isPartialClone(dir) {
+		var err error
+		remoteURL, err = s.promisorRemoteURL(ctx, req.Repo)
+		if err != nil {
+			logger.Warn("failed to get remote URL of partial clone", log.Error(err))
+		} else if treeish, ok := archiveTreeish(req.Args); ok {
+			if err := prefetchMissingBlobs(ctx, dir, remoteURL, treeish); err != nil {
+				logger.Warn("failed to prefetch missing blobs", log.Error(err))
+			}
+		}
+	}
+
 	cmdStart = time.Now()
 	cmd := exec.CommandContext(ctx, "git", req.Args...)
 	dir.Set(cmd)
+	if remoteURL != nil {
+		cmd.Env = append(cmd.Env, promisorRemoteEnv(remoteURL, tlsExternal())...)
+	}
 	cmd.Stdout = stdoutW
 	cmd.Stderr = stderrW
 	cmd.Stdin = bytes.NewReader(req.Stdin)
@@ -1773,12 +1947,16 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 	stderrN = stderrW.n
 
 	stderr := stderrBuf.String()
+	if remoteURL != nil {
+		stderr = newURLRedactor(remoteURL).redact(stderr)
+	}
 	s.logIfCorrupt(ctx, req.Repo, dir, stderr)
 
-	// write trailer
-	w.Header().Set("X-Exec-Error", errorString(execErr))
-	w.Header().Set("X-Exec-Exit-Status", status)
-	w.Header().Set("X-Exec-Stderr", stderr)
+	return execStatus{
+		ExitStatus: exitStatus,
+		Stderr:     stderr,
+		Err:        execErr,
+	}, nil
 }
 
 func (s *Server) handleP4Exec(w http.ResponseWriter, r *http.Request) {
@@ -2058,8 +2236,24 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 		return "This will never finish cloning", nil
 	}
 
-	// We always want to store whether there was an error cloning the repo
+	// Replicas clone the repo from the gitserver that owns it.
+	if opts == nil || opts.CloneFromShard == "" {
+		if owner, ok := s.replicaOf(ctx, repo, currentGitserverAddresses()); ok {
+			replicaOpts := cloneOptions{CloneFromShard: "http://" + owner}
+			if opts != nil {
+				replicaOpts.Block = opts.Block
+				replicaOpts.Overwrite = opts.Overwrite
+			}
+			opts = &replicaOpts
+		}
+	}
+
+	// We always want to store whether there was an error cloning the repo, unless we
+	// are cloning it from the shard that still owns it.
 	defer func() {
+		if opts != nil && opts.CloneFromShard != "" {
+			return
+		}
 		// Use a different context in case we failed because the original context failed.
 		s.setLastErrorNonFatal(s.ctx, repo, err)
 	}()
@@ -2076,6 +2270,7 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 	if err != nil {
 		return "", errors.Wrap(err, "get VCS syncer")
 	}
+	syncer = withPartialCloneFilter(syncer, repo)
 
 	var remoteURL *vcs.URL
 	if opts != nil && opts.CloneFromShard != "" {
@@ -2084,11 +2279,10 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 			return "", errors.Errorf("cannot clone from the same gitserver instance")
 		}
 
-		remoteURL, err = vcs.ParseURL(opts.CloneFromShard)
+		remoteURL, err = shardRemoteURL(opts.CloneFromShard, repo)
 		if err != nil {
 			return "", err
 		}
-		remoteURL = remoteURL.JoinPath("git", string(repo))
 	} else {
 		// We may be attempting to clone a private repo so we need an internal actor.
 		remoteURL, err = s.getRemoteURL(actor.WithInternalActor(ctx), repo)
@@ -2200,15 +2394,31 @@ func (s *Server) doClone(ctx context.Context, repo api.RepoName, dir GitDir, syn
 	tmpPath = filepath.Join(tmpPath, ".git")
 	tmp := GitDir(tmpPath)
 
+	// When cloning from another shard, that shard still owns the repo and keeps
+	// serving it, so we leave its clone status alone.
+	migrating := opts != nil && opts.CloneFromShard != ""
+
 	// It may already be cloned
-	if !repoCloned(dir) {
+	if !repoCloned(dir) && !migrating {
 		s.setCloneStatusNonFatal(ctx, repo, types.CloneStatusCloning)
 	}
 	defer func() {
+		if migrating {
+			return
+		}
 		// Use a background context to ensure we still update the DB even if we time out
 		s.setCloneStatusNonFatal(context.Background(), repo, cloneStatus(repoCloned(dir), false))
 	}()
 
+	// Replicas and repos moved between gitservers are already accounted for by
+	// the gitserver that owns them.
+	if !migrating {
+		var size int64
+		if err := s.checkDiskQuotas(ctx, repo, &size); err != nil {
+			return err
+		}
+	}
+
 	cmd, err := syncer.CloneCommand(ctx, remoteURL, tmpPath)
 	if err != nil {
 		return errors.Wrap(err, "get clone command")
@@ -2236,6 +2446,15 @@ func (s *Server) doClone(ctx context.Context, repo api.RepoName, dir GitDir, syn
 
 	removeBadRefs(ctx, tmp)
 
+	// The size is recorded once the repo is cloned, so we only walk it once.
+	var size int64
+	if !migrating {
+		size = dirSize(tmp.Path("."))
+		if err := s.checkDiskQuotas(ctx, repo, &size); err != nil {
+			return err
+		}
+	}
+
 	if err := setHEAD(ctx, logger, tmp, syncer, remoteURL); err != nil {
 		logger.Warn("Failed to ensure HEAD exists", log.Error(err))
 		return errors.Wrap(err, "failed to ensure HEAD exists")
@@ -2275,20 +2494,30 @@ func (s *Server) doClone(ctx context.Context, repo api.RepoName, dir GitDir, syn
 		return err
 	}
 
+	if migrating {
+		// The repo's state in the DB is recorded by this shard once it becomes the
+		// owner, see syncRepoState.
+		logger.Info("repo cloned from another shard", log.String("shard", opts.CloneFromShard))
+		repoClonedCounter.Inc()
+		return nil
+	}
+
 	// Successfully updated, best-effort updating of db fetch state based on
 	// disk state.
 	if err := s.setLastFetched(ctx, repo); err != nil {
 		logger.Warn("failed setting last fetch in DB", log.Error(err))
 	}
 
-	// Successfully updated, best-effort calculation of the repo size.
-	if err := s.setRepoSize(ctx, repo); err != nil {
+	// Successfully updated, best-effort recording of the repo size.
+	if err := s.DB.GitserverRepos().SetRepoSize(ctx, repo, size, s.Hostname); err != nil {
 		logger.Warn("failed setting repo size", log.Error(err))
 	}
 
 	logger.Info("repo cloned")
 	repoClonedCounter.Inc()
 
+	s.syncReplicas(ctx, repo)
+
 	return nil
 }
 
@@ -2541,7 +2770,10 @@ func (s *Server) doRepoUpdate(ctx context.Context, repo api.RepoName, revspec st
 					s.logIfCorrupt(ctx, repo, s.dir(repo), gitErr.Output)
 				}
 			}
-			s.setLastErrorNonFatal(s.ctx, repo, err)
+			// The DB records the state of the repo on the gitserver that owns it.
+			if !s.isReplica(ctx, repo, currentGitserverAddresses()) {
+				s.setLastErrorNonFatal(s.ctx, repo, err)
+			}
 		})
 	}()
 
@@ -2586,15 +2818,35 @@ func (s *Server) doBackgroundRepoUpdate(repo api.RepoName, revspec string) error
 	repo = protocol.NormalizeRepo(repo)
 	dir := s.dir(repo)
 
-	remoteURL, err := s.getRemoteURL(ctx, repo)
-	if err != nil {
-		return errors.Wrap(err, "failed to determine Git remote URL")
-	}
+	var (
+		remoteURL *vcs.URL
+		syncer    VCSSyncer
+	)
+	owner, replica := s.replicaOf(ctx, repo, currentGitserverAddresses())
+	if replica {
+		// Replicas fetch from the gitserver that owns the repo, which has already
+		// converted it to git.
+		remoteURL, err = shardRemoteURL("http://"+owner, repo)
+		if err != nil {
+			return err
+		}
+		syncer = &GitRepoSyncer{}
+	} else {
+		remoteURL, err = s.getRemoteURL(ctx, repo)
+		if err != nil {
+			return errors.Wrap(err, "failed to determine Git remote URL")
+		}
 
-	syncer, err := s.GetVCSSyncer(ctx, repo)
-	if err != nil {
-		return errors.Wrap(err, "get VCS syncer")
+		syncer, err = s.GetVCSSyncer(ctx, repo)
+		if err != nil {
+			return errors.Wrap(err, "get VCS syncer")
+		}
+
+		if err := s.checkDiskQuotas(ctx, repo, nil); err != nil {
+			return err
+		}
 	}
+	syncer = withPartialCloneFilter(syncer, repo)
 
 	// drop temporary pack files after a fetch. this function won't
 	// return until this fetch has completed or definitely-failed,
@@ -2613,15 +2865,22 @@ func (s *Server) doBackgroundRepoUpdate(repo api.RepoName, revspec string) error
 		return errors.Wrapf(err, "failed to ensure HEAD exists for repo %q", repo)
 	}
 
-	if err := setRepositoryType(dir, syncer.Type()); err != nil {
-		return errors.Wrapf(err, "failed to set repository type for repo %q", repo)
-	}
-
 	// Update the last-changed stamp on disk.
 	if err := setLastChanged(logger, dir); err != nil {
 		logger.Warn("failed to update last changed time", log.Error(err))
 	}
 
+	if replica {
+		// The DB records the state of the repo on the gitserver that owns it.
+		return nil
+	}
+
+	if err := setRepositoryType(dir, syncer.Type()); err != nil {
+		return errors.Wrapf(err, "failed to set repository type for repo %q", repo)
+	}
+
+	s.syncReplicas(ctx, repo)
+
 	// Successfully updated, best-effort updating of db fetch state based on
 	// disk state.
 	if err := s.setLastFetched(ctx, repo); err != nil {
@@ -2876,17 +3135,13 @@ func (s *Server) ensureRevision(ctx context.Context, repo api.RepoName, rev stri
 		return false
 	}
 
-	// rev-parse on an OID does not check if the commit actually exists, so it always
-	// works. So we append ^0 to force the check
-	if isAbsoluteRevision(rev) {
-		rev = rev + "^0"
-	}
-	cmd := exec.Command("git", "rev-parse", rev, "--")
-	repoDir.Set(cmd)
-	if err := cmd.Run(); err == nil {
+	if revisionExists(repoDir, rev) {
 		return false
 	}
 	// Revision not found, update before returning.
+	if isAbsoluteRevision(rev) {
+		rev = rev + "^0"
+	}
 	err := s.doRepoUpdate(ctx, repo, rev)
 	if err != nil {
 		s.Logger.Warn("failed to perform background repo update", log.Error(err), log.String("repo", string(repo)), log.String("rev", rev))
@@ -2894,6 +3149,19 @@ func (s *Server) ensureRevision(ctx context.Context, repo api.RepoName, rev stri
 	return true
 }
 
+// revisionExists returns true if rev resolves to an object in the repository at
+// repoDir.
+func revisionExists(repoDir GitDir, rev string) bool {
+	// rev-parse on an OID does not check if the commit actually exists, so it always
+	// works. So we append ^0 to force the check
+	if isAbsoluteRevision(rev) {
+		rev = rev + "^0"
+	}
+	cmd := exec.Command("git", "rev-parse", rev, "--")
+	repoDir.Set(cmd)
+	return cmd.Run() == nil
+}
+
 const headFileRefPrefix = "ref: "
 
 // quickSymbolicRefHead best-effort mimics the execution of `git symbolic-ref HEAD`, but doesn't exec a child process.
@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sourcegraph/log"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	gproto "google.golang.org/protobuf/proto"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server/internal/accesslog"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/proto"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// GRPCServer serves the gRPC API of gitserver. It shares its implementation with
+// the equivalent HTTP endpoints of Server.
+type GRPCServer struct {
+	Server *Server
+	proto.UnimplementedGitserverServiceServer
+}
+
+func (gs *GRPCServer) Exec(req *proto.ExecRequest, ss proto.GitserverService_ExecServer) error {
+	var internalReq protocol.ExecRequest
+	internalReq.FromProto(req)
+
+	// Log which actor is accessing the repo.
+	args := internalReq.Args
+	cmd := ""
+	if len(args) > 0 {
+		cmd = args[0]
+		args = args[1:]
+	}
+	accesslog.Record(ss.Context(), string(internalReq.Repo),
+		log.String("cmd", cmd),
+		log.Strings("args", args),
+	)
+
+	return gs.doExec(ss.Context(), &internalReq, ss.Send)
+}
+
+func (gs *GRPCServer) Archive(req *proto.ArchiveRequest, ss proto.GitserverService_ArchiveServer) error {
+	// Log which which actor is accessing the repo.
+	accesslog.Record(ss.Context(), req.GetRepo(),
+		log.String("treeish", req.GetTreeish()),
+		log.String("format", req.GetFormat()),
+		log.Strings("path", req.GetPathspecs()),
+	)
+
+	if err := checkSpecArgSafety(req.GetTreeish()); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.GetRepo() == "" || req.GetFormat() == "" {
+		return status.Error(codes.InvalidArgument, "empty repo or format")
+	}
+
+	execReq := archiveExecRequest(api.RepoName(req.GetRepo()), req.GetTreeish(), req.GetFormat(), req.GetPathspecs())
+	return gs.doExec(ss.Context(), execReq, ss.Send)
+}
+
+// doExec runs the command of req, streaming its output and finally its status
+// with send.
+func (gs *GRPCServer) doExec(ctx context.Context, req *protocol.ExecRequest, send func(*proto.ExecResponse) error) error {
+	logger := gs.Server.Logger.Scoped("grpc.exec", "").With(log.Strings("req.Args", req.Args))
+
+	execStatus, err := gs.Server.execute(ctx, logger, req, userAgentFromContext(ctx), &execResponseWriter{send: send}, func() {})
+	if err != nil {
+		var notFound *execNotFoundError
+		switch {
+		case errors.Is(err, errInvalidCommand):
+			logger.Warn("exec: bad command", log.String("RemoteAddr", remoteAddrFromContext(ctx)))
+			return status.Error(codes.InvalidArgument, err.Error())
+		case errors.As(err, &notFound):
+			s, detailsErr := status.New(codes.NotFound, err.Error()).WithDetails(&proto.NotFoundPayload{
+				Repo:            string(req.Repo),
+				CloneInProgress: notFound.payload.CloneInProgress,
+				CloneProgress:   notFound.payload.CloneProgress,
+			})
+			if detailsErr != nil {
+				return detailsErr
+			}
+			return s.Err()
+		}
+		return err
+	}
+
+	return send(&proto.ExecResponse{
+		Message: &proto.ExecResponse_Status{
+			Status: &proto.ExecStatus{
+				ExitStatus: int32(execStatus.ExitStatus),
+				Stderr:     execStatus.Stderr,
+				Error:      errorString(execStatus.Err),
+			},
+		},
+	})
+}
+
+// execResponseWriter sends everything written to it as data messages of an exec
+// response stream.
+type execResponseWriter struct {
+	send func(*proto.ExecResponse) error
+}
+
+func (w *execResponseWriter) Write(p []byte) (int, error) {
+	// Send marshals the message before returning, so p is not retained.
+	if err := w.send(&proto.ExecResponse{Message: &proto.ExecResponse_Data{Data: p}}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (gs *GRPCServer) Search(req *proto.SearchRequest, ss proto.GitserverService_SearchServer) error {
+	var args protocol.SearchRequest
+	if err := args.FromProto(req); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	tr, ctx := trace.New(ss.Context(), "search", "")
+	defer tr.Finish()
+
+	matchesBuf := &protoMatchesBuf{
+		flushSize: 32 * 1024,
+		send: func(matches []*proto.CommitMatch) error {
+			tr.AddEvent("flushing data", attribute.Int("matches.len", len(matches)))
+			return ss.Send(&proto.SearchResponse{
+				Message: &proto.SearchResponse_Matches{
+					Matches: &proto.CommitMatches{Matches: matches},
+				},
+			})
+		},
+	}
+
+	limitHit, searchErr := gs.Server.searchWithObservability(ctx, tr, &args, matchesBuf)
+	return ss.Send(&proto.SearchResponse{
+		Message: &proto.SearchResponse_Done{
+			Done: protocol.NewSearchEventDone(limitHit, searchErr).ToProto(),
+		},
+	})
+}
+
+// protoMatchesBuf buffers commit matches and sends them in batches of roughly
+// flushSize bytes.
+type protoMatchesBuf struct {
+	flushSize int
+	send      func([]*proto.CommitMatch) error
+
+	matches []*proto.CommitMatch
+	size    int
+}
+
+var _ searchMatchesBuf = &protoMatchesBuf{}
+
+func (b *protoMatchesBuf) Append(v any) error {
+	match, ok := v.(*protocol.CommitMatch)
+	if !ok {
+		return errors.Newf("unexpected match type %T", v)
+	}
+
+	m := match.ToProto()
+	b.matches = append(b.matches, m)
+	b.size += gproto.Size(m)
+	if b.size >= b.flushSize {
+		return b.Flush()
+	}
+	return nil
+}
+
+func (b *protoMatchesBuf) Flush() error {
+	if len(b.matches) == 0 {
+		return nil
+	}
+	err := b.send(b.matches)
+	b.matches, b.size = nil, 0
+	return err
+}
+
+func (b *protoMatchesBuf) Len() int {
+	return b.size
+}
+
+func (gs *GRPCServer) BatchLog(req *proto.BatchLogRequest, ss proto.GitserverService_BatchLogServer) error {
+	var internalReq protocol.BatchLogRequest
+	internalReq.FromProto(req)
+
+	// Results are sent as soon as they are available. Send must not be called
+	// concurrently.
+	var mu sync.Mutex
+	err := gs.Server.batchGitLog(ss.Context(), internalReq, func(_ int, result protocol.BatchLogResult) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return ss.Send(result.ToProto())
+	})
+	if errors.Is(err, errInvalidBatchLogFormat) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return err
+}
+
+// userAgentFromContext returns the user agent of the client of a gRPC call.
+func userAgentFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get("user-agent"); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// remoteAddrFromContext returns the address of the client of a gRPC call.
+func remoteAddrFromContext(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"os/exec"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	gproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/proto"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type fakeExecServer struct {
+	proto.GitserverService_ExecServer
+	responses []*proto.ExecResponse
+}
+
+func (s *fakeExecServer) Context() context.Context { return context.Background() }
+
+func (s *fakeExecServer) Send(r *proto.ExecResponse) error {
+	// The data of r may be reused once Send returns.
+	s.responses = append(s.responses, gproto.Clone(r).(*proto.ExecResponse))
+	return nil
+}
+
+func TestGRPCServer_Exec(t *testing.T) {
+	db := database.NewMockDB()
+	gr := database.NewMockGitserverRepoStore()
+	db.GitserverReposFunc.SetDefaultReturn(gr)
+	s := &Server{
+		Logger:            logtest.Scoped(t),
+		ObservationCtx:    observation.TestContextTB(t),
+		ReposDir:          "/testroot",
+		skipCloneForTests: true,
+		GetRemoteURLFunc: func(ctx context.Context, name api.RepoName) (string, error) {
+			return "", errors.New("not cloneable")
+		},
+		DB: db,
+	}
+	_ = s.Handler()
+	gs := &GRPCServer{Server: s}
+
+	origRepoCloned := repoCloned
+	repoCloned = func(dir GitDir) bool {
+		return dir == s.dir("github.com/gorilla/mux")
+	}
+	t.Cleanup(func() { repoCloned = origRepoCloned })
+
+	runCommandMock = func(ctx context.Context, cmd *exec.Cmd) (int, error) {
+		_, _ = cmd.Stdout.Write([]byte("teststdout"))
+		_, _ = cmd.Stderr.Write([]byte("teststderr"))
+		return 42, nil
+	}
+	t.Cleanup(func() { runCommandMock = nil })
+
+	t.Run("Command", func(t *testing.T) {
+		ss := &fakeExecServer{}
+		err := gs.Exec(&proto.ExecRequest{Repo: "github.com/gorilla/mux", Args: []string{"testcommand"}}, ss)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []*proto.ExecResponse{
+			{Message: &proto.ExecResponse_Data{Data: []byte("teststdout")}},
+			{Message: &proto.ExecResponse_Status{Status: &proto.ExecStatus{ExitStatus: 42, Stderr: "teststderr"}}},
+		}
+		if diff := cmp.Diff(want, ss.responses, protocmp.Transform()); diff != "" {
+			t.Fatalf("unexpected responses (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("NonexistingRepo", func(t *testing.T) {
+		err := gs.Exec(&proto.ExecRequest{Repo: "github.com/gorilla/doesnotexist", Args: []string{"testcommand"}}, &fakeExecServer{})
+		st, ok := status.FromError(err)
+		if !ok || st.Code() != codes.NotFound {
+			t.Fatalf("want NotFound status, got %v", err)
+		}
+		if len(st.Details()) != 1 {
+			t.Fatalf("want not found payload, got %v", st.Details())
+		}
+		payload, ok := st.Details()[0].(*proto.NotFoundPayload)
+		if !ok || payload.GetRepo() != "github.com/gorilla/doesnotexist" || payload.GetCloneInProgress() {
+			t.Fatalf("unexpected not found payload %v", st.Details()[0])
+		}
+	})
+
+	t.Run("BadCommand", func(t *testing.T) {
+		err := gs.Exec(&proto.ExecRequest{Repo: "github.com/gorilla/mux", Args: []string{"invalid-command"}}, &fakeExecServer{})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("want InvalidArgument status, got %v", err)
+		}
+	})
+}
+
+type fakeBatchLogServer struct {
+	proto.GitserverService_BatchLogServer
+	responses []*proto.BatchLogResponse
+}
+
+func (s *fakeBatchLogServer) Context() context.Context { return context.Background() }
+
+func (s *fakeBatchLogServer) Send(r *proto.BatchLogResponse) error {
+	s.responses = append(s.responses, r)
+	return nil
+}
+
+func TestGRPCServer_BatchLog(t *testing.T) {
+	originalRepoCloned := repoCloned
+	repoCloned = func(dir GitDir) bool {
+		return dir == "github.com/foo/bar/.git"
+	}
+	t.Cleanup(func() { repoCloned = originalRepoCloned })
+
+	runCommandMock = func(ctx context.Context, cmd *exec.Cmd) (int, error) {
+		_, _ = cmd.Stdout.Write([]byte("stdout<" + strings.Join(cmd.Args, " ") + ">"))
+		return 0, nil
+	}
+	t.Cleanup(func() { runCommandMock = nil })
+
+	gs := &GRPCServer{Server: &Server{
+		Logger:                  logtest.Scoped(t),
+		ObservationCtx:          observation.TestContextTB(t),
+		GlobalBatchLogSemaphore: semaphore.NewWeighted(8),
+		DB:                      database.NewMockDB(),
+	}}
+
+	t.Run("invalid format", func(t *testing.T) {
+		err := gs.BatchLog(&proto.BatchLogRequest{
+			RepoCommits: []*proto.RepoCommit{{Repo: "github.com/foo/bar", Commit: "deadbeef1"}},
+			Format:      "%H",
+		}, &fakeBatchLogServer{})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("want InvalidArgument status, got %v", err)
+		}
+	})
+
+	t.Run("partially resolved", func(t *testing.T) {
+		ss := &fakeBatchLogServer{}
+		err := gs.BatchLog(&proto.BatchLogRequest{
+			RepoCommits: []*proto.RepoCommit{
+				{Repo: "github.com/foo/bar", Commit: "deadbeef1"},
+				{Repo: "github.com/foo/honk", Commit: "deadbeef2"},
+			},
+			Format: "--format=test",
+		}, ss)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Results are sent in the order they complete.
+		sort.Slice(ss.responses, func(i, j int) bool {
+			return ss.responses[i].GetRepoCommit().GetRepo() < ss.responses[j].GetRepoCommit().GetRepo()
+		})
+		want := []*proto.BatchLogResponse{
+			{
+				RepoCommit:    &proto.RepoCommit{Repo: "github.com/foo/bar", Commit: "deadbeef1"},
+				CommandOutput: "stdout<git log -n 1 --name-only --format=test deadbeef1>",
+			},
+			{
+				RepoCommit:   &proto.RepoCommit{Repo: "github.com/foo/honk", Commit: "deadbeef2"},
+				CommandError: "repo not found",
+			},
+		}
+		if diff := cmp.Diff(want, ss.responses, protocmp.Transform()); diff != "" {
+			t.Fatalf("unexpected responses (-want +got):\n%s", diff)
+		}
+	})
+}
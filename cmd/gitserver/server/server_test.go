@@ -545,6 +545,9 @@ func makeTestServer(ctx context.Context, t *testing.T, repoDir, remote string, d
 		mDB := database.NewMockDB()
 		gr := database.NewMockGitserverRepoStore()
 		mDB.GitserverReposFunc.SetDefaultReturn(gr)
+		repos := database.NewMockRepoStore()
+		repos.GetByNameFunc.SetDefaultReturn(&types.Repo{}, nil)
+		mDB.ReposFunc.SetDefaultReturn(repos)
 		db = mDB
 	}
 	s := &Server{
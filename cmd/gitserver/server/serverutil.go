@@ -214,24 +214,7 @@ func configureRemoteGitCommand(cmd *exec.Cmd, tlsConf *tlsConfig) {
 		panic(fmt.Sprintf("Only git or p4-fusion commands are supported, got %q", executable))
 	}
 
-	cmd.Env = append(cmd.Env, "GIT_ASKPASS=true") // disable password prompt
-
-	// Suppress asking to add SSH host key to known_hosts (which will hang because
-	// the command is non-interactive).
-	//
-	// And set a timeout to avoid indefinite hangs if the server is unreachable.
-	cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes -o ConnectTimeout=30")
-
-	// Identify HTTP requests with a user agent. Please keep the git/ prefix because GitHub breaks the protocol v2
-	// negotiation of clone URLs without a `.git` suffix (which we use) without it. Don't ask.
-	cmd.Env = append(cmd.Env, "GIT_HTTP_USER_AGENT=git/Sourcegraph-Bot")
-
-	if tlsConf.SSLNoVerify {
-		cmd.Env = append(cmd.Env, "GIT_SSL_NO_VERIFY=true")
-	}
-	if tlsConf.SSLCAInfo != "" {
-		cmd.Env = append(cmd.Env, "GIT_SSL_CAINFO="+tlsConf.SSLCAInfo)
-	}
+	cmd.Env = append(cmd.Env, remoteGitEnv(tlsConf)...)
 
 	extraArgs := []string{
 		// Unset credential helper because the command is non-interactive.
@@ -251,6 +234,30 @@ func configureRemoteGitCommand(cmd *exec.Cmd, tlsConf *tlsConfig) {
 	cmd.Args = append(cmd.Args[:1], append(extraArgs, cmd.Args[1:]...)...)
 }
 
+// remoteGitEnv returns the environment variables configureRemoteGitCommand sets
+// for git commands that talk to a remote.
+func remoteGitEnv(tlsConf *tlsConfig) []string {
+	env := []string{"GIT_ASKPASS=true"} // disable password prompt
+
+	// Suppress asking to add SSH host key to known_hosts (which will hang because
+	// the command is non-interactive).
+	//
+	// And set a timeout to avoid indefinite hangs if the server is unreachable.
+	env = append(env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes -o ConnectTimeout=30")
+
+	// Identify HTTP requests with a user agent. Please keep the git/ prefix because GitHub breaks the protocol v2
+	// negotiation of clone URLs without a `.git` suffix (which we use) without it. Don't ask.
+	env = append(env, "GIT_HTTP_USER_AGENT=git/Sourcegraph-Bot")
+
+	if tlsConf.SSLNoVerify {
+		env = append(env, "GIT_SSL_NO_VERIFY=true")
+	}
+	if tlsConf.SSLCAInfo != "" {
+		env = append(env, "GIT_SSL_CAINFO="+tlsConf.SSLCAInfo)
+	}
+	return env
+}
+
 // removeUnsupportedP4Args removes all -c arguments as `p4-fusion` command doesn't
 // support -c argument and passing this causes warning logs.
 func removeUnsupportedP4Args(args []string) []string {
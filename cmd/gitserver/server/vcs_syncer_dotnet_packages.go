@@ -0,0 +1,108 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/nuget"
+	"github.com/sourcegraph/sourcegraph/internal/unpack"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func NewDotnetPackagesSyncer(
+	connection *schema.DotnetPackagesConnection,
+	svc *dependencies.Service,
+	client *nuget.Client,
+) VCSSyncer {
+	return &vcsPackagesSyncer{
+		logger:      log.Scoped("DotnetPackagesSyncer", "sync .NET packages"),
+		typ:         "dotnet_packages",
+		scheme:      dependencies.DotnetPackagesScheme,
+		placeholder: reposource.NewDotnetVersionedPackage("sourcegraph.placeholder", "0.0.0"),
+		svc:         svc,
+		configDeps:  connection.Dependencies,
+		source:      &dotnetDependencySource{client: client},
+	}
+}
+
+type dotnetDependencySource struct {
+	client *nuget.Client
+}
+
+func (dotnetDependencySource) ParseVersionedPackageFromNameAndVersion(name reposource.PackageName, version string) (reposource.VersionedPackage, error) {
+	return reposource.NewDotnetVersionedPackage(name, version), nil
+}
+
+func (dotnetDependencySource) ParseVersionedPackageFromConfiguration(dep string) (reposource.VersionedPackage, error) {
+	return reposource.ParseDotnetVersionedPackage(dep)
+}
+
+func (dotnetDependencySource) ParsePackageFromName(name reposource.PackageName) (reposource.Package, error) {
+	return reposource.ParseDotnetPackageFromName(name)
+}
+
+func (dotnetDependencySource) ParsePackageFromRepoName(repoName api.RepoName) (reposource.Package, error) {
+	return reposource.ParseDotnetPackageFromRepoName(repoName)
+}
+
+func (s *dotnetDependencySource) Download(ctx context.Context, dir string, dep reposource.VersionedPackage) error {
+	pkgContents, packageURL, err := s.client.GetPackageContents(ctx, dep)
+	if err != nil {
+		return errors.Wrapf(err, "error downloading NuGet package with URL '%s'", packageURL)
+	}
+	defer pkgContents.Close()
+
+	if err = unpackDotnetPackage(pkgContents, dir); err != nil {
+		return errors.Wrapf(err, "failed to unzip NuGet package from URL %s", packageURL)
+	}
+
+	return nil
+}
+
+// unpackDotnetPackage unpacks the given .nupkg archive into workDir, skipping
+// the Open Packaging Conventions bookkeeping files NuGet adds to every package
+// as well as any files that are too large or potentially malicious.
+func unpackDotnetPackage(pkg io.Reader, workDir string) error {
+	pkgBytes, err := io.ReadAll(pkg)
+	if err != nil {
+		return err
+	}
+
+	opts := unpack.Opts{
+		SkipInvalid:    true,
+		SkipDuplicates: true,
+		Filter: func(path string, file fs.FileInfo) bool {
+			if isNupkgMetadataFile(path) {
+				return false
+			}
+
+			size := file.Size()
+
+			const sizeLimit = 15 * 1024 * 1024
+			if size >= sizeLimit {
+				return false
+			}
+
+			malicious := isPotentiallyMaliciousFilepathInArchive(path, workDir)
+			return !malicious
+		},
+	}
+
+	return unpack.Zip(bytes.NewReader(pkgBytes), int64(len(pkgBytes)), workDir, opts)
+}
+
+func isNupkgMetadataFile(path string) bool {
+	return path == "[Content_Types].xml" ||
+		path == ".signature.p7s" ||
+		strings.HasPrefix(path, "_rels/") ||
+		strings.HasPrefix(path, "package/")
+}
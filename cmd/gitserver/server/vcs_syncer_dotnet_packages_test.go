@@ -0,0 +1,76 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnpackDotnetPackage(t *testing.T) {
+	pkg := createZip(t, []fileInfo{
+		{path: "Newtonsoft.Json.nuspec", contents: []byte("<package/>")},
+		{path: "lib/net6.0/Newtonsoft.Json.xml", contents: []byte("<doc/>")},
+		{path: "src/JsonConvert.cs", contents: []byte("class JsonConvert {}")},
+		{path: "[Content_Types].xml", contents: []byte("filter me")},
+		{path: "_rels/.rels", contents: []byte("filter me")},
+		{path: "package/services/metadata/core-properties/1.psmdcp", contents: []byte("filter me")},
+		{path: ".signature.p7s", contents: []byte("filter me")},
+		{path: ".git/index", contents: []byte("filter me")},
+	})
+
+	tmp := t.TempDir()
+	if err := unpackDotnetPackage(bytes.NewReader(pkg), tmp); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"/Newtonsoft.Json.nuspec", "/lib/net6.0/Newtonsoft.Json.xml", "/src/JsonConvert.cs"}
+	if d := cmp.Diff(want, listFiles(t, tmp)); d != "" {
+		t.Fatalf("-want,+got\n%s", d)
+	}
+}
+
+func createZip(t *testing.T, files []fileInfo) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		fw, err := zw.Create(f.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(f.contents); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// listFiles returns the sorted paths of all files in dir, relative to dir.
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	var files []string
+	if err := filepath.Walk(dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			files = append(files, strings.TrimPrefix(path, dir))
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	return files
+}
@@ -27,7 +27,13 @@ func (e *GitCommandError) Unwrap() error {
 }
 
 // GitRepoSyncer is a syncer for Git repositories.
-type GitRepoSyncer struct{}
+type GitRepoSyncer struct {
+	// PartialCloneFilter, if set, makes the syncer clone and fetch the
+	// repository as a partial clone that leaves out the objects excluded by the
+	// filter, e.g. "blob:limit=1m". Missing objects are fetched when they are
+	// read.
+	PartialCloneFilter string
+}
 
 func (s *GitRepoSyncer) Type() string {
 	return "git"
@@ -72,6 +78,12 @@ func (s *GitRepoSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tm
 		return nil, errors.Wrapf(&GitCommandError{Err: err}, "clone setup failed")
 	}
 
+	if s.PartialCloneFilter != "" {
+		if err := configurePartialClone(GitDir(tmpPath), s.PartialCloneFilter); err != nil {
+			return nil, errors.Wrapf(err, "clone setup failed")
+		}
+	}
+
 	cmd, _ = s.fetchCommand(ctx, remoteURL)
 	cmd.Dir = tmpPath
 	return cmd, nil
@@ -79,6 +91,14 @@ func (s *GitRepoSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tm
 
 // Fetch tries to fetch updates of a Git repository.
 func (s *GitRepoSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir, revspec string) error {
+	if s.PartialCloneFilter != "" {
+		// This also converts existing full clones, which stop fetching the
+		// objects excluded by the filter from now on.
+		if err := configurePartialClone(dir, s.PartialCloneFilter); err != nil {
+			return errors.Wrap(err, "failed to configure partial clone")
+		}
+	}
+
 	cmd, configRemoteOpts := s.fetchCommand(ctx, remoteURL)
 	dir.Set(cmd)
 	if output, err := runWith(ctx, cmd, configRemoteOpts, nil); err != nil {
@@ -104,7 +124,16 @@ func (s *GitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) (c
 			// We already have janitor jobs that run git gc. We disable git gc here to avoid
 			// a possible corruption of repositories by competing gc processes.
 			"--no-auto-gc",
-			"--progress", "--prune", remoteURL.String(),
+			"--progress", "--prune")
+		if s.PartialCloneFilter != "" {
+			// Partial clones can only fetch from their promisor remote, whose URL
+			// is passed in the environment.
+			cmd.Args = append(cmd.Args, "--filter="+s.PartialCloneFilter, promisorRemote)
+			cmd.Env = append(os.Environ(), promisorRemoteEnv(remoteURL, tlsExternal())...)
+		} else {
+			cmd.Args = append(cmd.Args, remoteURL.String())
+		}
+		cmd.Args = append(cmd.Args,
 			// Normal git refs
 			"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*",
 			// GitHub pull requests
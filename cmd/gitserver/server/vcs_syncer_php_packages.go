@@ -0,0 +1,118 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/packagist"
+	"github.com/sourcegraph/sourcegraph/internal/unpack"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func NewPHPPackagesSyncer(
+	connection *schema.PHPPackagesConnection,
+	svc *dependencies.Service,
+	client *packagist.Client,
+) VCSSyncer {
+	return &vcsPackagesSyncer{
+		logger:      log.Scoped("PHPPackagesSyncer", "sync PHP packages"),
+		typ:         "php_packages",
+		scheme:      dependencies.PHPPackagesScheme,
+		placeholder: reposource.NewPHPVersionedPackage("sourcegraph/placeholder", "0.0.0"),
+		svc:         svc,
+		configDeps:  connection.Dependencies,
+		source:      &phpDependencySource{client: client},
+	}
+}
+
+type phpDependencySource struct {
+	client *packagist.Client
+}
+
+func (phpDependencySource) ParseVersionedPackageFromNameAndVersion(name reposource.PackageName, version string) (reposource.VersionedPackage, error) {
+	return reposource.ParsePHPVersionedPackage(string(name) + ":" + version)
+}
+
+func (phpDependencySource) ParseVersionedPackageFromConfiguration(dep string) (reposource.VersionedPackage, error) {
+	return reposource.ParsePHPVersionedPackage(dep)
+}
+
+func (phpDependencySource) ParsePackageFromName(name reposource.PackageName) (reposource.Package, error) {
+	return reposource.ParsePHPPackageFromName(name)
+}
+
+func (phpDependencySource) ParsePackageFromRepoName(repoName api.RepoName) (reposource.Package, error) {
+	return reposource.ParsePHPPackageFromRepoName(repoName)
+}
+
+func (s *phpDependencySource) Download(ctx context.Context, dir string, dep reposource.VersionedPackage) error {
+	version, err := s.client.Version(ctx, dep.PackageSyntax(), dep.PackageVersion())
+	if err != nil {
+		return err
+	}
+
+	pkgContents, err := s.client.Download(ctx, version.Dist.URL)
+	if err != nil {
+		return errors.Wrapf(err, "error downloading Composer package with URL '%s'", version.Dist.URL)
+	}
+	defer pkgContents.Close()
+
+	if err = unpackPHPPackage(pkgContents, version.Dist.Type, dir); err != nil {
+		return errors.Wrapf(err, "failed to unpack Composer package from URL %s", version.Dist.URL)
+	}
+
+	return nil
+}
+
+// unpackPHPPackage unpacks the given Composer dist archive of the given type
+// into workDir, skipping any files that aren't valid or that are potentially
+// malicious.
+func unpackPHPPackage(pkg io.Reader, distType, workDir string) error {
+	opts := unpack.Opts{
+		SkipInvalid:    true,
+		SkipDuplicates: true,
+		Filter: func(path string, file fs.FileInfo) bool {
+			size := file.Size()
+
+			const sizeLimit = 15 * 1024 * 1024
+			if size >= sizeLimit {
+				return false
+			}
+
+			malicious := isPotentiallyMaliciousFilepathInArchive(path, workDir)
+			return !malicious
+		},
+	}
+
+	var err error
+	switch distType {
+	case "zip":
+		var pkgBytes []byte
+		pkgBytes, err = io.ReadAll(pkg)
+		if err != nil {
+			break
+		}
+		err = unpack.Zip(bytes.NewReader(pkgBytes), int64(len(pkgBytes)), workDir, opts)
+	case "tar":
+		// Tar dists may or may not be compressed, which unpack.Tgz handles.
+		err = unpack.Tgz(pkg, workDir, opts)
+	default:
+		return errors.Errorf("unsupported Composer dist type %q", distType)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Archives of GitHub hosted packages contain a single top-level directory
+	// named after the repository and commit.
+	return stripSingleOutermostDirectory(workDir)
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnpackPHPPackage(t *testing.T) {
+	files := []fileInfo{
+		{path: "Seldaek-monolog-abc123/composer.json", contents: []byte("{}")},
+		{path: "Seldaek-monolog-abc123/src/Monolog/Logger.php", contents: []byte("<?php")},
+		{path: "Seldaek-monolog-abc123/.git/index", contents: []byte("filter me")},
+	}
+	want := []string{"/composer.json", "/src/Monolog/Logger.php"}
+
+	t.Run("zip", func(t *testing.T) {
+		tmp := t.TempDir()
+		if err := unpackPHPPackage(bytes.NewReader(createZip(t, files)), "zip", tmp); err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(want, listFiles(t, tmp)); d != "" {
+			t.Fatalf("-want,+got\n%s", d)
+		}
+	})
+
+	t.Run("tar", func(t *testing.T) {
+		tmp := t.TempDir()
+		if err := unpackPHPPackage(bytes.NewReader(createTgz(t, files)), "tar", tmp); err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(want, listFiles(t, tmp)); d != "" {
+			t.Fatalf("-want,+got\n%s", d)
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		if err := unpackPHPPackage(bytes.NewReader(nil), "git", t.TempDir()); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -0,0 +1,277 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// svnRefPrefix is the namespace git svn imports Subversion branches and tags
+// into. It is mirrored into refs/heads and refs/tags after every fetch.
+const svnRefPrefix = "refs/remotes/svn/"
+
+// SubversionSyncer is a syncer for Subversion repositories. Repositories are
+// converted into Git using git svn, which records the last converted revision
+// in the repository so that subsequent fetches only import new revisions.
+type SubversionSyncer struct {
+	// Layout is the branch layout of the repository: "standard" for the
+	// trunk/branches/tags convention, "custom" for the Trunk, Branches and Tags
+	// paths below, and "none" to import the repository root as a single branch.
+	Layout string
+
+	// Trunk, Branches and Tags are the paths used when Layout is "custom".
+	Trunk    string
+	Branches string
+	Tags     string
+
+	// AuthorsFile is the content of a git svn authors file used to map
+	// Subversion usernames to Git authors.
+	AuthorsFile string
+}
+
+func (s *SubversionSyncer) Type() string {
+	return "subversion"
+}
+
+// IsCloneable checks to see if the Subversion remote URL is cloneable.
+func (s *SubversionSyncer) IsCloneable(ctx context.Context, remoteURL *vcs.URL) error {
+	repoURL, username, password := decomposeSubversionRemoteURL(remoteURL)
+
+	args := []string{"info", "--non-interactive"}
+	if username != "" {
+		args = append(args, "--username", username, "--password-from-stdin")
+	}
+	args = append(args, repoURL)
+
+	ctx, cancel := context.WithTimeout(ctx, shortGitCommandTimeout(args))
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "svn", args...)
+	cmd.Stdin = strings.NewReader(password)
+	out, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		if ctxerr := ctx.Err(); ctxerr != nil {
+			err = ctxerr
+		}
+		if len(out) > 0 {
+			err = errors.Errorf("%s (output follows)\n\n%s", err, newURLRedactor(remoteURL).redact(string(out)))
+		}
+		return err
+	}
+	return nil
+}
+
+// CloneCommand initializes a bare Git repository at tmpPath that tracks the
+// Subversion repository, and imports its full history.
+//
+// Like vcsPackagesSyncer, the import happens within CloneCommand itself so
+// that it shares the credential handling and ref mapping of Fetch. The
+// returned command is a no-op.
+func (s *SubversionSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tmpPath string) (*exec.Cmd, error) {
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "init", "--bare", ".")
+	cmd.Dir = tmpPath
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(&GitCommandError{Err: err}, "clone setup failed")
+	}
+
+	repoURL, _, _ := decomposeSubversionRemoteURL(remoteURL)
+	cmd = exec.CommandContext(ctx, "git", s.initArgs(repoURL)...)
+	dir := GitDir(tmpPath)
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return nil, errors.Wrapf(&GitCommandError{Err: err, Output: string(output)}, "git svn init failed")
+	}
+
+	if err := s.Fetch(ctx, remoteURL, dir, ""); err != nil {
+		return nil, errors.Wrap(err, "failed to import Subversion history")
+	}
+
+	// no-op command to satisfy VCSSyncer interface, see docstring for more details.
+	return exec.CommandContext(ctx, "git", "--version"), nil
+}
+
+// Fetch imports all Subversion revisions newer than the last converted one and
+// updates the Git branches and tags accordingly.
+func (s *SubversionSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir, _ string) error {
+	_, username, password := decomposeSubversionRemoteURL(remoteURL)
+
+	tmpDir, err := os.MkdirTemp("", "subversion")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	args := []string{"svn", "fetch"}
+	if username != "" {
+		args = append(args, "--username", username)
+	}
+	if s.AuthorsFile != "" {
+		authorsPath := filepath.Join(tmpDir, "authors")
+		if err := os.WriteFile(authorsPath, []byte(s.AuthorsFile), 0o600); err != nil {
+			return errors.Wrap(err, "writing authors file")
+		}
+		args = append(args, "--authors-file", authorsPath)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = os.Environ()
+	if password != "" {
+		// git svn has no way to pass a password other than prompting for it,
+		// so we answer the prompt with a helper that echoes it from the
+		// environment.
+		askpass := filepath.Join(tmpDir, "askpass")
+		if err := os.WriteFile(askpass, []byte("#!/bin/sh\nprintf '%s\\n' \"$SRC_SVN_PASSWORD\"\n"), 0o700); err != nil {
+			return errors.Wrap(err, "writing askpass helper")
+		}
+		cmd.Env = append(cmd.Env, "GIT_ASKPASS="+askpass, "SSH_ASKPASS="+askpass, "SRC_SVN_PASSWORD="+password)
+	}
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(&GitCommandError{Err: err, Output: newURLRedactor(remoteURL).redact(string(output))}, "failed to update")
+	}
+
+	return updateSubversionRefs(ctx, dir)
+}
+
+// RemoteShowCommand returns the command to be executed for showing Git remote of a Subversion repository.
+func (s *SubversionSyncer) RemoteShowCommand(ctx context.Context, _ *vcs.URL) (cmd *exec.Cmd, err error) {
+	// Remote info is encoded as in the current repository
+	return exec.CommandContext(ctx, "git", "remote", "show", "./"), nil
+}
+
+// initArgs returns the git arguments to configure repoURL as the Subversion
+// remote of a repository.
+func (s *SubversionSyncer) initArgs(repoURL string) []string {
+	args := []string{"svn", "init", "--prefix=svn/"}
+	switch s.Layout {
+	case "none":
+	case "custom":
+		if s.Trunk != "" {
+			args = append(args, "--trunk", s.Trunk)
+		}
+		if s.Branches != "" {
+			args = append(args, "--branches", s.Branches)
+		}
+		if s.Tags != "" {
+			args = append(args, "--tags", s.Tags)
+		}
+	default:
+		args = append(args, "--stdlayout")
+	}
+	return append(args, repoURL)
+}
+
+// updateSubversionRefs mirrors the refs git svn imported under svnRefPrefix
+// into regular branches and tags.
+func updateSubversionRefs(ctx context.Context, dir GitDir) error {
+	cmd := exec.CommandContext(ctx, "git", "for-each-ref", "--format=%(objectname) %(refname)", svnRefPrefix, "refs/heads/", "refs/tags/")
+	dir.Set(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return errors.Wrap(&GitCommandError{Err: err}, "listing refs")
+	}
+
+	var refs []gitRef
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		oid, name, ok := strings.Cut(sc.Text(), " ")
+		if !ok {
+			continue
+		}
+		refs = append(refs, gitRef{name: name, oid: oid})
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+
+	updates := subversionRefUpdates(refs)
+	if len(updates) == 0 {
+		return nil
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "update-ref", "--stdin")
+	cmd.Stdin = strings.NewReader(strings.Join(updates, "\n") + "\n")
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrap(&GitCommandError{Err: err, Output: string(output)}, "updating refs")
+	}
+	return nil
+}
+
+type gitRef struct {
+	name string
+	oid  string
+}
+
+// subversionRefUpdates computes the `git update-ref --stdin` instructions to
+// make refs/heads and refs/tags match the refs imported by git svn:
+//
+//   - trunk (or git-svn when there is no layout) becomes the master branch
+//   - tags/<name> becomes the tag <name>
+//   - <name> becomes the branch <name>
+//
+// Refs git svn keeps for the history of deleted or replaced branches
+// (<name>@<revision>) are skipped. Branches and tags that no longer exist in
+// Subversion are deleted.
+func subversionRefUpdates(refs []gitRef) []string {
+	current := make(map[string]string)
+	want := make(map[string]string)
+	for _, ref := range refs {
+		if !strings.HasPrefix(ref.name, svnRefPrefix) {
+			current[ref.name] = ref.oid
+			continue
+		}
+
+		name := strings.TrimPrefix(ref.name, svnRefPrefix)
+		if strings.Contains(name, "@") {
+			continue
+		}
+		switch {
+		case name == "trunk" || name == "git-svn":
+			want["refs/heads/master"] = ref.oid
+		case strings.HasPrefix(name, "tags/"):
+			want["refs/tags/"+strings.TrimPrefix(name, "tags/")] = ref.oid
+		default:
+			want["refs/heads/"+name] = ref.oid
+		}
+	}
+
+	var updates []string
+	for name, oid := range want {
+		if current[name] != oid {
+			updates = append(updates, fmt.Sprintf("update %s %s", name, oid))
+		}
+	}
+	for name := range current {
+		if _, ok := want[name]; !ok {
+			updates = append(updates, "delete "+name)
+		}
+	}
+	sort.Strings(updates)
+	return updates
+}
+
+// decomposeSubversionRemoteURL splits the credentials from the URL of a
+// Subversion repository.
+func decomposeSubversionRemoteURL(remoteURL *vcs.URL) (repoURL, username, password string) {
+	u := *remoteURL
+	if u.User != nil {
+		username = u.User.Username()
+		password, _ = u.User.Password()
+	}
+	u.User = nil
+	return u.String(), username, password
+}
@@ -36,6 +36,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodproxy"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/npm"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/nuget"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/packagist"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/pypi"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/rubygems"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
@@ -470,6 +472,18 @@ func getVCSSyncer(
 			FusionConfig: configureFusionClient(c),
 			P4Home:       p4Home,
 		}, nil
+	case extsvc.TypeSubversion:
+		var c schema.SubversionConnection
+		if _, err := extractOptions(&c); err != nil {
+			return nil, err
+		}
+		return &server.SubversionSyncer{
+			Layout:      c.Layout,
+			Trunk:       c.Trunk,
+			Branches:    c.Branches,
+			Tags:        c.Tags,
+			AuthorsFile: c.AuthorsFile,
+		}, nil
 	case extsvc.TypeJVMPackages:
 		var c schema.JVMPackagesConnection
 		if _, err := extractOptions(&c); err != nil {
@@ -516,6 +530,22 @@ func getVCSSyncer(
 		}
 		cli := rubygems.NewClient(urn, c.Repository, httpcli.ExternalDoer)
 		return server.NewRubyPackagesSyncer(&c, depsSvc, cli), nil
+	case extsvc.TypeDotnetPackages:
+		var c schema.DotnetPackagesConnection
+		urn, err := extractOptions(&c)
+		if err != nil {
+			return nil, err
+		}
+		cli := nuget.NewClient(urn, c.Repository, httpcli.ExternalDoer)
+		return server.NewDotnetPackagesSyncer(&c, depsSvc, cli), nil
+	case extsvc.TypePHPPackages:
+		var c schema.PHPPackagesConnection
+		urn, err := extractOptions(&c)
+		if err != nil {
+			return nil, err
+		}
+		cli := packagist.NewClient(urn, c.Repository, httpcli.ExternalDoer)
+		return server.NewPHPPackagesSyncer(&c, depsSvc, cli), nil
 	}
 	return &server.GitRepoSyncer{}, nil
 }
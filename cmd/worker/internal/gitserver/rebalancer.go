@@ -0,0 +1,283 @@
+package gitserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type rebalancerJob struct{}
+
+// NewRebalancerJob returns a job that moves repositories between gitserver shards
+// when the set of gitservers or the sharding scheme changes. Requests keep being
+// routed to the current owner of a repository until it has been cloned on all of
+// the shards it moves to.
+func NewRebalancerJob() job.Job {
+	return &rebalancerJob{}
+}
+
+func (j *rebalancerJob) Description() string {
+	return "Moves repositories between gitserver shards when the shard layout changes"
+}
+
+func (j *rebalancerJob) Config() []env.Config {
+	return nil
+}
+
+func (j *rebalancerJob) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	observationCtx = observation.NewContext(observationCtx.Logger.Scoped("rebalancer", "gitserver shard rebalancer"))
+	ctx := actor.WithInternalActor(context.Background())
+
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, errors.Wrap(err, "initialising database")
+	}
+
+	workerStore := makeRelocatorStore(observationCtx, db.Handle())
+
+	return []goroutine.BackgroundRoutine{
+		goroutine.NewPeriodicGoroutine(ctx, "gitserver.rebalancer", "moves repositories between gitserver shards when the shard layout changes",
+			time.Minute, &rebalancer{
+				rebalances: database.GitserverRebalancesWith(db),
+				jobs:       db.GitserverLocalClone(),
+				repos:      db.GitserverRepos(),
+				layout:     layoutFromConfig,
+				signal:     signalLayoutChange,
+				logger:     observationCtx.Logger,
+			},
+		),
+		makeRelocatorWorker(ctx, observationCtx, workerStore, gitserver.NewClient()),
+		makeRelocatorResetter(observationCtx, workerStore),
+	}, nil
+}
+
+// layout describes the desired assignment of repositories to gitservers.
+type layout struct {
+	addrs    []string
+	sharding string
+	pinned   map[string]string
+}
+
+func layoutFromConfig() layout {
+	cfg := conf.Get()
+	l := layout{
+		addrs:    cfg.ServiceConnections().GitServers,
+		sharding: gitserver.ShardingModulo,
+	}
+	if cfg.ExperimentalFeatures != nil {
+		if cfg.ExperimentalFeatures.GitServerSharding != "" {
+			l.sharding = cfg.ExperimentalFeatures.GitServerSharding
+		}
+		l.pinned = cfg.ExperimentalFeatures.GitServerPinnedRepos
+	}
+	return l
+}
+
+type rebalancer struct {
+	rebalances database.GitserverRebalanceStore
+	jobs       database.GitserverLocalCloneStore
+	repos      database.GitserverRepoStore
+	layout     func() layout
+	// signal tells the frontend that the gitserver_rebalances table changed, so that
+	// it reloads the routing.
+	signal func() error
+	logger log.Logger
+}
+
+func signalLayoutChange() error {
+	return redispool.Store.Incr(database.GitserverRebalanceVersionKey)
+}
+
+// changed signals a change of the gitserver_rebalances table. Failing to do so only
+// delays the new routing until the frontend reloads it anyway.
+func (r *rebalancer) changed() {
+	if err := r.signal(); err != nil {
+		r.logger.Warn("failed to signal gitserver layout change", log.Error(err))
+	}
+}
+
+var (
+	_ goroutine.Handler      = &rebalancer{}
+	_ goroutine.ErrorHandler = &rebalancer{}
+)
+
+func (r *rebalancer) Handle(ctx context.Context) error {
+	desired := r.layout()
+	if len(desired.addrs) == 0 {
+		return nil
+	}
+
+	latest, err := r.rebalances.Latest(ctx)
+	if err != nil {
+		return errors.Wrap(err, "getting latest rebalance")
+	}
+
+	if latest == nil {
+		// Until the layout is first recorded, repositories are assigned to all
+		// gitservers with modulo sharding.
+		now := time.Now()
+		latest = &database.GitserverRebalance{
+			SourceAddrs:    desired.addrs,
+			SourceSharding: gitserver.ShardingModulo,
+			DestAddrs:      desired.addrs,
+			DestSharding:   gitserver.ShardingModulo,
+			FinishedAt:     &now,
+		}
+		if err := r.rebalances.Create(ctx, latest); err != nil {
+			return errors.Wrap(err, "recording initial gitserver layout")
+		}
+		r.changed()
+	}
+
+	if latest.FinishedAt != nil {
+		if sameLayout(latest.DestAddrs, latest.DestSharding, desired) {
+			return nil
+		}
+
+		// Repositories are routed with the layout of the last rebalance until this
+		// one finishes, minus the gitservers that are gone.
+		source := gitserver.NewGitServerAddresses(desired.addrs, latest.Routing(), nil)
+		latest = &database.GitserverRebalance{
+			SourceAddrs:    source.Addresses,
+			SourceSharding: source.Sharding,
+			DestAddrs:      desired.addrs,
+			DestSharding:   desired.sharding,
+		}
+		if err := r.rebalances.Create(ctx, latest); err != nil {
+			return errors.Wrap(err, "creating rebalance")
+		}
+		r.changed()
+		r.logger.Info("starting gitserver rebalance",
+			log.Int("id", latest.ID),
+			log.Strings("source", latest.SourceAddrs),
+			log.String("sourceSharding", latest.SourceSharding),
+			log.Strings("dest", latest.DestAddrs),
+			log.String("destSharding", latest.DestSharding),
+		)
+	} else if !sameLayout(latest.DestAddrs, latest.DestSharding, desired) {
+		// The layout changed again before the rebalance finished. Repositories that
+		// were already moved to a shard they no longer belong to are moved again.
+		if err := r.rebalances.UpdateDest(ctx, latest.ID, desired.addrs, desired.sharding); err != nil {
+			return errors.Wrap(err, "updating rebalance")
+		}
+		r.changed()
+		latest.DestAddrs, latest.DestSharding = desired.addrs, desired.sharding
+	}
+
+	return r.relocate(ctx, latest, desired)
+}
+
+func (r *rebalancer) HandleError(err error) {
+	r.logger.Error("error rebalancing gitserver shards", log.Error(err))
+}
+
+// relocate enqueues a relocator job for every cloned repository that moves to
+// another shard in the given rebalance, and finishes the rebalance once all of
+// them have been processed.
+func (r *rebalancer) relocate(ctx context.Context, rb *database.GitserverRebalance, desired layout) error {
+	addrs := gitserver.NewGitServerAddresses(desired.addrs, rb.Routing(), desired.pinned)
+
+	jobs, err := r.jobs.LatestJobs(ctx, rb.CreatedAt)
+	if err != nil {
+		return errors.Wrap(err, "listing relocator jobs")
+	}
+	latestJob := make(map[api.RepoID]*database.GitserverRelocatorJob, len(jobs))
+	for _, j := range jobs {
+		latestJob[j.RepoID] = j
+	}
+
+	var pending, moved, failed int
+	options := database.IterateRepoGitserverStatusOptions{BatchSize: 500}
+	for {
+		repos, nextCursor, err := r.repos.IterateRepoGitserverStatus(ctx, options)
+		if err != nil {
+			return errors.Wrap(err, "iterating repositories")
+		}
+		for _, repo := range repos {
+			if repo.GitserverRepo == nil || repo.CloneStatus != types.CloneStatusCloned {
+				continue
+			}
+
+			from, err := gitserver.AddrForRepo(ctx, "worker", repo.Name, addrs)
+			if err != nil {
+				return err
+			}
+			to, err := gitserver.AddrForRepo(ctx, "worker", repo.Name, *addrs.Next)
+			if err != nil {
+				return err
+			}
+			if from == to {
+				continue
+			}
+
+			switch j := latestJob[repo.ID]; {
+			case j == nil || j.SourceHostname != from || j.DestHostname != to:
+				if _, err := r.jobs.Enqueue(ctx, int(repo.ID), from, to, false); err != nil {
+					return errors.Wrapf(err, "enqueueing relocation of repo %q", repo.Name)
+				}
+				pending++
+			case j.State == "completed":
+				moved++
+			case j.State == "failed":
+				failed++
+			default:
+				pending++
+			}
+		}
+
+		if nextCursor == 0 {
+			break
+		}
+		options.NextCursor = nextCursor
+	}
+
+	if pending > 0 {
+		r.logger.Info("gitserver rebalance in progress",
+			log.Int("id", rb.ID),
+			log.Int("pending", pending),
+			log.Int("moved", moved),
+			log.Int("failed", failed),
+		)
+		return nil
+	}
+
+	if err := r.rebalances.Finish(ctx, rb.ID); err != nil {
+		return errors.Wrap(err, "finishing rebalance")
+	}
+	r.changed()
+	// Repositories that could not be moved are cloned from the code host by their
+	// new owner when they are next requested.
+	r.logger.Info("gitserver rebalance finished",
+		log.Int("id", rb.ID),
+		log.Int("moved", moved),
+		log.Int("failed", failed),
+	)
+	return nil
+}
+
+func sameLayout(addrs []string, sharding string, l layout) bool {
+	if sharding != l.sharding || len(addrs) != len(l.addrs) {
+		return false
+	}
+	for i := range addrs {
+		if addrs[i] != l.addrs[i] {
+			return false
+		}
+	}
+	return true
+}
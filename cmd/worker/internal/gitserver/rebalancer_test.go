@@ -0,0 +1,197 @@
+package gitserver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// fakeRebalanceStore keeps rebalances in memory.
+type fakeRebalanceStore struct {
+	database.GitserverRebalanceStore
+	rebalances []*database.GitserverRebalance
+}
+
+func (s *fakeRebalanceStore) Latest(context.Context) (*database.GitserverRebalance, error) {
+	if len(s.rebalances) == 0 {
+		return nil, nil
+	}
+	r := *s.rebalances[len(s.rebalances)-1]
+	return &r, nil
+}
+
+func (s *fakeRebalanceStore) Create(_ context.Context, r *database.GitserverRebalance) error {
+	r.ID = len(s.rebalances) + 1
+	r.CreatedAt = time.Now()
+	created := *r
+	s.rebalances = append(s.rebalances, &created)
+	return nil
+}
+
+func (s *fakeRebalanceStore) UpdateDest(_ context.Context, id int, addrs []string, sharding string) error {
+	s.rebalances[id-1].DestAddrs = addrs
+	s.rebalances[id-1].DestSharding = sharding
+	return nil
+}
+
+func (s *fakeRebalanceStore) Finish(_ context.Context, id int) error {
+	now := time.Now()
+	s.rebalances[id-1].FinishedAt = &now
+	return nil
+}
+
+func TestRebalancer(t *testing.T) {
+	ctx := context.Background()
+
+	var repos []types.RepoGitserverStatus
+	for i := 1; i <= 100; i++ {
+		status := types.CloneStatusCloned
+		if i%10 == 0 {
+			status = types.CloneStatusNotCloned
+		}
+		repos = append(repos, types.RepoGitserverStatus{
+			ID:            api.RepoID(i),
+			Name:          api.RepoName(fmt.Sprintf("github.com/sourcegraph/repo-%d", i)),
+			GitserverRepo: &types.GitserverRepo{RepoID: api.RepoID(i), CloneStatus: status},
+		})
+	}
+	repoStore := database.NewMockGitserverRepoStore()
+	repoStore.IterateRepoGitserverStatusFunc.SetDefaultHook(func(_ context.Context, opts database.IterateRepoGitserverStatusOptions) ([]types.RepoGitserverStatus, int, error) {
+		start := opts.NextCursor
+		end := start + opts.BatchSize
+		if end >= len(repos) {
+			return repos[start:], 0, nil
+		}
+		return repos[start:end], end, nil
+	})
+
+	jobStore := database.NewMockGitserverLocalCloneStore()
+	var jobs []*database.GitserverRelocatorJob
+	jobStore.EnqueueFunc.SetDefaultHook(func(_ context.Context, repoID int, source, dest string, _ bool) (int, error) {
+		jobs = append(jobs, &database.GitserverRelocatorJob{
+			ID:             len(jobs) + 1,
+			State:          "queued",
+			RepoID:         api.RepoID(repoID),
+			SourceHostname: source,
+			DestHostname:   dest,
+		})
+		return len(jobs), nil
+	})
+	jobStore.LatestJobsFunc.SetDefaultHook(func(context.Context, time.Time) ([]*database.GitserverRelocatorJob, error) {
+		return jobs, nil
+	})
+
+	rebalances := &fakeRebalanceStore{}
+	desired := layout{
+		addrs:    []string{"gitserver-0", "gitserver-1"},
+		sharding: gitserver.ShardingModulo,
+	}
+	var signals int
+	r := &rebalancer{
+		rebalances: rebalances,
+		jobs:       jobStore,
+		repos:      repoStore,
+		layout:     func() layout { return desired },
+		signal:     func() error { signals++; return nil },
+		logger:     logtest.Scoped(t),
+	}
+
+	// The first run records the current layout.
+	if err := r.Handle(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(rebalances.rebalances) != 1 || rebalances.rebalances[0].FinishedAt == nil {
+		t.Fatalf("want a finished initial rebalance, got %+v", rebalances.rebalances)
+	}
+	if len(jobs) != 0 {
+		t.Fatalf("want no relocator jobs, got %d", len(jobs))
+	}
+	if signals != 1 {
+		t.Fatalf("want the initial layout to be signaled, got %d signals", signals)
+	}
+
+	// Adding a gitserver and switching to rendezvous hashing starts a rebalance.
+	desired = layout{
+		addrs:    []string{"gitserver-0", "gitserver-1", "gitserver-2"},
+		sharding: gitserver.ShardingRendezvous,
+	}
+	if err := r.Handle(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(rebalances.rebalances) != 2 {
+		t.Fatalf("want a second rebalance, got %d", len(rebalances.rebalances))
+	}
+	rb := rebalances.rebalances[1]
+	if rb.FinishedAt != nil {
+		t.Fatal("want rebalance to be in progress")
+	}
+	if diff := cmp.Diff([]string{"gitserver-0", "gitserver-1"}, rb.SourceAddrs); diff != "" {
+		t.Fatalf("unexpected source addresses (-want +got):\n%s", diff)
+	}
+	if len(jobs) == 0 {
+		t.Fatal("want relocator jobs to be enqueued")
+	}
+	if signals != 2 {
+		t.Fatalf("want the rebalance to be signaled, got %d signals", signals)
+	}
+
+	source := gitserver.GitServerAddresses{Addresses: rb.SourceAddrs, Sharding: rb.SourceSharding}
+	dest := gitserver.GitServerAddresses{Addresses: rb.DestAddrs, Sharding: rb.DestSharding}
+	for _, j := range jobs {
+		repo := repos[j.RepoID-1]
+		if repo.CloneStatus != types.CloneStatusCloned {
+			t.Fatalf("repo %q is not cloned but was enqueued", repo.Name)
+		}
+		from, _ := gitserver.AddrForRepo(ctx, "test", repo.Name, source)
+		to, _ := gitserver.AddrForRepo(ctx, "test", repo.Name, dest)
+		if from == to || j.SourceHostname != from || j.DestHostname != to {
+			t.Fatalf("unexpected job for repo %q: %s -> %s", repo.Name, j.SourceHostname, j.DestHostname)
+		}
+	}
+
+	// Pending jobs are not enqueued again and keep the rebalance in progress.
+	numJobs := len(jobs)
+	if err := r.Handle(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != numJobs {
+		t.Fatalf("want %d jobs, got %d", numJobs, len(jobs))
+	}
+	if rebalances.rebalances[1].FinishedAt != nil {
+		t.Fatal("want rebalance to be in progress")
+	}
+
+	// Once every job has been processed, the rebalance finishes.
+	for i, j := range jobs {
+		j.State = "completed"
+		if i == 0 {
+			j.State = "failed"
+		}
+	}
+	if err := r.Handle(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if rebalances.rebalances[1].FinishedAt == nil {
+		t.Fatal("want rebalance to be finished")
+	}
+	if signals != 3 {
+		t.Fatalf("want the finished rebalance to be signaled, got %d signals", signals)
+	}
+
+	// Nothing happens while the layout stays the same.
+	if err := r.Handle(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(rebalances.rebalances) != 2 || len(jobs) != numJobs || signals != 3 {
+		t.Fatal("want no new rebalance, jobs or signals")
+	}
+}
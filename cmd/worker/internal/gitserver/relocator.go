@@ -0,0 +1,91 @@
+package gitserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func makeRelocatorStore(observationCtx *observation.Context, db basestore.TransactableHandle) store.Store[*database.GitserverRelocatorJob] {
+	return store.New(observationCtx, db, store.Options[*database.GitserverRelocatorJob]{
+		Name:              "gitserver_relocator_worker_store",
+		TableName:         "gitserver_relocator_jobs",
+		ViewName:          "gitserver_relocator_jobs_with_repo_name",
+		ColumnExpressions: database.GitserverRelocatorJobColumns,
+		Scan:              store.BuildWorkerScan(database.ScanGitserverRelocatorJob),
+		OrderByExpression: sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.id"),
+		MaxNumResets:      5,
+		MaxNumRetries:     3,
+		RetryAfter:        time.Minute,
+		StalledMaxAge:     time.Minute,
+	})
+}
+
+func makeRelocatorWorker(
+	ctx context.Context,
+	observationCtx *observation.Context,
+	workerStore store.Store[*database.GitserverRelocatorJob],
+	client gitserver.Client,
+) *workerutil.Worker[*database.GitserverRelocatorJob] {
+	return dbworker.NewWorker[*database.GitserverRelocatorJob](
+		ctx, workerStore, &relocator{client: client}, workerutil.WorkerOptions{
+			Name:              "gitserver_relocator_worker",
+			Interval:          5 * time.Second,
+			NumHandlers:       4,
+			HeartbeatInterval: 10 * time.Second,
+			Metrics:           workerutil.NewMetrics(observationCtx, "gitserver_relocator_worker"),
+		},
+	)
+}
+
+func makeRelocatorResetter(
+	observationCtx *observation.Context,
+	workerStore store.Store[*database.GitserverRelocatorJob],
+) *dbworker.Resetter[*database.GitserverRelocatorJob] {
+	return dbworker.NewResetter(
+		observationCtx.Logger, workerStore, dbworker.ResetterOptions{
+			Name:     "gitserver_relocator_resetter",
+			Interval: 5 * time.Minute,
+			Metrics:  dbworker.NewResetterMetrics(observationCtx, "gitserver_relocator_resetter"),
+		},
+	)
+}
+
+// relocator clones a repository on its new shard from the shard that currently
+// owns it.
+type relocator struct {
+	client gitserver.Client
+}
+
+var _ workerutil.Handler[*database.GitserverRelocatorJob] = &relocator{}
+
+func (h *relocator) Handle(ctx context.Context, logger log.Logger, job *database.GitserverRelocatorJob) error {
+	logger = logger.With(
+		log.Int("job.id", job.ID),
+		log.String("repo", string(job.RepoName)),
+		log.String("source", job.SourceHostname),
+		log.String("dest", job.DestHostname),
+	)
+
+	resp, err := h.client.RequestRepoMigrate(ctx, job.RepoName, job.SourceHostname, job.DestHostname)
+	if err != nil {
+		return errors.Wrap(err, "requesting repo migration")
+	}
+	if resp.Error != "" {
+		return errors.Newf("cloning repo from %s: %s", job.SourceHostname, resp.Error)
+	}
+
+	logger.Debug("repo relocated")
+	return nil
+}
@@ -55,6 +55,7 @@ func LoadConfig(additionalJobs map[string]job.Job, registerEnterpriseMigrators o
 		"out-of-band-migrations":    workermigrations.NewMigrator(registerMigrators),
 		"codeintel-crates-syncer":   codeintel.NewCratesSyncerJob(),
 		"gitserver-metrics":         gitserver.NewMetricsJob(),
+		"gitserver-rebalancer":      gitserver.NewRebalancerJob(),
 		"record-encrypter":          encryption.NewRecordEncrypterJob(),
 		"repo-statistics-compactor": repostatistics.NewCompactor(),
 		"zoekt-repos-updater":       zoektrepos.NewUpdater(),
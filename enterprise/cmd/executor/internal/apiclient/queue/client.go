@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -34,23 +35,33 @@ type Client struct {
 	logger          log.Logger
 	metricsGatherer prometheus.Gatherer
 	operations      *operations
+
+	// scheduler decides which queues to dequeue from. It is only set when
+	// processing multiple queues at once.
+	scheduler *scheduler
 }
 
 // Compile time validation.
 var _ workerutil.Store[executor.Job] = &Client{}
 var _ command.ExecutionLogEntryStore = &Client{}
+var _ command.ExecutionLogEntryStreamer = &Client{}
 
 func New(observationCtx *observation.Context, options Options, metricsGatherer prometheus.Gatherer) (*Client, error) {
 	client, err := apiclient.NewBaseClient(options.BaseClientOptions)
 	if err != nil {
 		return nil, err
 	}
+	var s *scheduler
+	if len(options.QueueNames) > 0 {
+		s = newScheduler(observationCtx, options.QueueNames, options.QueueWeights, options.QueueMaxJobs)
+	}
 	return &Client{
 		options:         options,
 		client:          client,
 		logger:          log.Scoped("executor-api-queue-client", "The API client adapter for executors to use dbworkers over HTTP"),
 		metricsGatherer: metricsGatherer,
 		operations:      newOperations(observationCtx),
+		scheduler:       s,
 	}, nil
 }
 
@@ -60,35 +71,53 @@ func (c *Client) QueuedCount(ctx context.Context) (int, error) {
 
 func (c *Client) Dequeue(ctx context.Context, workerHostname string, extraArguments any) (job executor.Job, _ bool, err error) {
 	ctx, _, endObservation := c.operations.dequeue.With(ctx, &err, observation.Args{LogFields: []otlog.Field{
-		otlog.String("queueName", c.options.QueueName),
+		otlog.String("queueName", c.queueNames()),
 	}})
 	defer endObservation(1, observation.Args{})
 
-	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/dequeue", c.options.QueueName), executor.DequeueRequest{
+	path := fmt.Sprintf("%s/dequeue", c.options.QueueName)
+	var queues []string
+	if c.scheduler != nil {
+		queues = c.scheduler.order()
+		if len(queues) == 0 {
+			// All queues are running their maximum number of jobs.
+			return job, false, nil
+		}
+		path = "dequeue"
+	}
+
+	req, err := c.client.NewJSONRequest(http.MethodPost, path, executor.DequeueRequest{
 		Version:      version.Version(),
 		ExecutorName: c.options.ExecutorName,
 		NumCPUs:      c.options.ResourceOptions.NumCPUs,
 		Memory:       c.options.ResourceOptions.Memory,
 		DiskSpace:    c.options.ResourceOptions.DiskSpace,
+		Queues:       queues,
 	})
 	if err != nil {
 		return job, false, err
 	}
 
 	decoded, err := c.client.DoAndDecode(ctx, req, &job)
+	if decoded && err == nil && c.scheduler != nil {
+		c.scheduler.started(job.Queue)
+	}
 	return job, decoded, err
 }
 
 func (c *Client) MarkComplete(ctx context.Context, id int) (_ bool, err error) {
+	queueName, jobID := c.queueForRecord(id)
+	defer c.finished(queueName)
+
 	ctx, _, endObservation := c.operations.markComplete.With(ctx, &err, observation.Args{LogFields: []otlog.Field{
-		otlog.String("queueName", c.options.QueueName),
-		otlog.Int("jobID", id),
+		otlog.String("queueName", queueName),
+		otlog.Int("jobID", jobID),
 	}})
 	defer endObservation(1, observation.Args{})
 
-	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/markComplete", c.options.QueueName), executor.MarkCompleteRequest{
+	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/markComplete", queueName), executor.MarkCompleteRequest{
 		ExecutorName: c.options.ExecutorName,
-		JobID:        id,
+		JobID:        jobID,
 	})
 	if err != nil {
 		return false, err
@@ -101,15 +130,18 @@ func (c *Client) MarkComplete(ctx context.Context, id int) (_ bool, err error) {
 }
 
 func (c *Client) MarkErrored(ctx context.Context, id int, failureMessage string) (_ bool, err error) {
+	queueName, jobID := c.queueForRecord(id)
+	defer c.finished(queueName)
+
 	ctx, _, endObservation := c.operations.markErrored.With(ctx, &err, observation.Args{LogFields: []otlog.Field{
-		otlog.String("queueName", c.options.QueueName),
-		otlog.Int("jobID", id),
+		otlog.String("queueName", queueName),
+		otlog.Int("jobID", jobID),
 	}})
 	defer endObservation(1, observation.Args{})
 
-	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/markErrored", c.options.QueueName), executor.MarkErroredRequest{
+	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/markErrored", queueName), executor.MarkErroredRequest{
 		ExecutorName: c.options.ExecutorName,
-		JobID:        id,
+		JobID:        jobID,
 		ErrorMessage: failureMessage,
 	})
 	if err != nil {
@@ -123,15 +155,18 @@ func (c *Client) MarkErrored(ctx context.Context, id int, failureMessage string)
 }
 
 func (c *Client) MarkFailed(ctx context.Context, id int, failureMessage string) (_ bool, err error) {
+	queueName, jobID := c.queueForRecord(id)
+	defer c.finished(queueName)
+
 	ctx, _, endObservation := c.operations.markFailed.With(ctx, &err, observation.Args{LogFields: []otlog.Field{
-		otlog.String("queueName", c.options.QueueName),
-		otlog.Int("jobID", id),
+		otlog.String("queueName", queueName),
+		otlog.Int("jobID", jobID),
 	}})
 	defer endObservation(1, observation.Args{})
 
-	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/markFailed", c.options.QueueName), executor.MarkErroredRequest{
+	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/markFailed", queueName), executor.MarkErroredRequest{
 		ExecutorName: c.options.ExecutorName,
-		JobID:        id,
+		JobID:        jobID,
 		ErrorMessage: failureMessage,
 	})
 	if err != nil {
@@ -146,7 +181,7 @@ func (c *Client) MarkFailed(ctx context.Context, id int, failureMessage string)
 
 func (c *Client) Heartbeat(ctx context.Context, jobIDs []int) (knownIDs, cancelIDs []int, err error) {
 	ctx, _, endObservation := c.operations.heartbeat.With(ctx, &err, observation.Args{LogFields: []otlog.Field{
-		otlog.String("queueName", c.options.QueueName),
+		otlog.String("queueName", c.queueNames()),
 		otlog.String("jobIDs", intsToString(jobIDs)),
 	}})
 	defer endObservation(1, observation.Args{})
@@ -157,23 +192,13 @@ func (c *Client) Heartbeat(ctx context.Context, jobIDs []int) (knownIDs, cancelI
 		// Continue, no metric errors should prevent heartbeats.
 	}
 
-	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/heartbeat", c.options.QueueName), executor.HeartbeatRequest{
-		// Request the new-fashioned payload.
-		Version: executor.ExecutorAPIVersion2,
-
-		ExecutorName: c.options.ExecutorName,
-		JobIDs:       jobIDs,
-
-		OS:              c.options.TelemetryOptions.OS,
-		Architecture:    c.options.TelemetryOptions.Architecture,
-		DockerVersion:   c.options.TelemetryOptions.DockerVersion,
-		ExecutorVersion: c.options.TelemetryOptions.ExecutorVersion,
-		GitVersion:      c.options.TelemetryOptions.GitVersion,
-		IgniteVersion:   c.options.TelemetryOptions.IgniteVersion,
-		SrcCliVersion:   c.options.TelemetryOptions.SrcCliVersion,
+	if c.scheduler != nil {
+		return c.heartbeatMultiple(ctx, jobIDs, metrics)
+	}
 
-		PrometheusMetrics: metrics,
-	})
+	payload := c.newHeartbeatRequest(metrics)
+	payload.JobIDs = jobIDs
+	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/heartbeat", c.options.QueueName), payload)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -217,6 +242,91 @@ func (c *Client) Heartbeat(ctx context.Context, jobIDs []int) (knownIDs, cancelI
 	return respV1, cancelIDs, nil
 }
 
+// heartbeatMultiple sends a heartbeat for the given jobs from multiple queues.
+func (c *Client) heartbeatMultiple(ctx context.Context, recordIDs []int, metrics string) (knownIDs, cancelIDs []int, err error) {
+	payload := c.newHeartbeatRequest(metrics)
+	payload.JobIDsByQueue = c.jobIDsByQueue(recordIDs)
+	req, err := c.client.NewJSONRequest(http.MethodPost, "heartbeat", payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resp executor.HeartbeatResponse
+	if _, err := c.client.DoAndDecode(ctx, req, &resp); err != nil {
+		return nil, nil, err
+	}
+
+	return recordIDsByQueue(resp.KnownIDsByQueue), recordIDsByQueue(resp.CancelIDsByQueue), nil
+}
+
+func (c *Client) newHeartbeatRequest(metrics string) executor.HeartbeatRequest {
+	return executor.HeartbeatRequest{
+		// Request the new-fashioned payload.
+		Version: executor.ExecutorAPIVersion2,
+
+		ExecutorName: c.options.ExecutorName,
+
+		OS:              c.options.TelemetryOptions.OS,
+		Architecture:    c.options.TelemetryOptions.Architecture,
+		DockerVersion:   c.options.TelemetryOptions.DockerVersion,
+		ExecutorVersion: c.options.TelemetryOptions.ExecutorVersion,
+		GitVersion:      c.options.TelemetryOptions.GitVersion,
+		IgniteVersion:   c.options.TelemetryOptions.IgniteVersion,
+		SrcCliVersion:   c.options.TelemetryOptions.SrcCliVersion,
+
+		PrometheusMetrics: metrics,
+	}
+}
+
+// jobIDsByQueue groups the IDs of the jobs with the given record IDs by queue. Every
+// queue the client processes is included, so that the executor is registered for
+// all of them.
+func (c *Client) jobIDsByQueue(recordIDs []int) map[string][]int {
+	jobIDsByQueue := make(map[string][]int, len(c.options.QueueNames))
+	for _, queueName := range c.options.QueueNames {
+		jobIDsByQueue[queueName] = []int{}
+	}
+	for _, recordID := range recordIDs {
+		queueName, jobID := executor.ParseRecordID(recordID)
+		jobIDsByQueue[queueName] = append(jobIDsByQueue[queueName], jobID)
+	}
+	return jobIDsByQueue
+}
+
+func recordIDsByQueue(jobIDsByQueue map[string][]int) []int {
+	var recordIDs []int
+	for queueName, jobIDs := range jobIDsByQueue {
+		for _, jobID := range jobIDs {
+			recordIDs = append(recordIDs, executor.Job{ID: jobID, Queue: queueName}.RecordID())
+		}
+	}
+	sort.Ints(recordIDs)
+	return recordIDs
+}
+
+// queueForRecord returns the name of the queue of the job with the given record ID and
+// the ID of the job within that queue.
+func (c *Client) queueForRecord(recordID int) (queueName string, jobID int) {
+	if c.scheduler == nil {
+		return c.options.QueueName, recordID
+	}
+	return executor.ParseRecordID(recordID)
+}
+
+// finished records that the job from the given queue is no longer running.
+func (c *Client) finished(queueName string) {
+	if c.scheduler != nil {
+		c.scheduler.finished(queueName)
+	}
+}
+
+func (c *Client) queueNames() string {
+	if c.scheduler == nil {
+		return c.options.QueueName
+	}
+	return strings.Join(c.options.QueueNames, ",")
+}
+
 func intsToString(ints []int) string {
 	segments := make([]string, 0, len(ints))
 	for _, id := range ints {
@@ -268,6 +378,18 @@ func (c *Client) CanceledJobs(ctx context.Context, queueName string, knownIDs []
 }
 
 func (c *Client) Ping(ctx context.Context, queueName string, jobIDs []int) (err error) {
+	if c.scheduler != nil {
+		req, err := c.client.NewJSONRequest(http.MethodPost, "heartbeat", executor.HeartbeatRequest{
+			ExecutorName:  c.options.ExecutorName,
+			JobIDsByQueue: c.jobIDsByQueue(nil),
+		})
+		if err != nil {
+			return err
+		}
+
+		return c.client.DoAndDrop(ctx, req)
+	}
+
 	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/heartbeat", c.options.QueueName), executor.HeartbeatRequest{
 		ExecutorName: c.options.ExecutorName,
 	})
@@ -278,14 +400,16 @@ func (c *Client) Ping(ctx context.Context, queueName string, jobIDs []int) (err
 	return c.client.DoAndDrop(ctx, req)
 }
 
-func (c *Client) AddExecutionLogEntry(ctx context.Context, jobID int, entry internalexecutor.ExecutionLogEntry) (entryID int, err error) {
+func (c *Client) AddExecutionLogEntry(ctx context.Context, recordID int, entry internalexecutor.ExecutionLogEntry) (entryID int, err error) {
+	queueName, jobID := c.queueForRecord(recordID)
+
 	ctx, _, endObservation := c.operations.addExecutionLogEntry.With(ctx, &err, observation.Args{LogFields: []otlog.Field{
-		otlog.String("queueName", c.options.QueueName),
+		otlog.String("queueName", queueName),
 		otlog.Int("jobID", jobID),
 	}})
 	defer endObservation(1, observation.Args{})
 
-	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/addExecutionLogEntry", c.options.QueueName), executor.AddExecutionLogEntryRequest{
+	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/addExecutionLogEntry", queueName), executor.AddExecutionLogEntryRequest{
 		ExecutorName:      c.options.ExecutorName,
 		JobID:             jobID,
 		ExecutionLogEntry: entry,
@@ -298,18 +422,45 @@ func (c *Client) AddExecutionLogEntry(ctx context.Context, jobID int, entry inte
 	return entryID, err
 }
 
-func (c *Client) UpdateExecutionLogEntry(ctx context.Context, jobID, entryID int, entry internalexecutor.ExecutionLogEntry) (err error) {
+func (c *Client) UpdateExecutionLogEntry(ctx context.Context, recordID, entryID int, entry internalexecutor.ExecutionLogEntry) (err error) {
+	queueName, jobID := c.queueForRecord(recordID)
+
 	ctx, _, endObservation := c.operations.updateExecutionLogEntry.With(ctx, &err, observation.Args{LogFields: []otlog.Field{
-		otlog.String("queueName", c.options.QueueName),
+		otlog.String("queueName", queueName),
+		otlog.Int("jobID", jobID),
+		otlog.Int("entryID", entryID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/updateExecutionLogEntry", queueName), executor.UpdateExecutionLogEntryRequest{
+		ExecutorName:      c.options.ExecutorName,
+		JobID:             jobID,
+		EntryID:           entryID,
+		ExecutionLogEntry: entry,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.client.DoAndDrop(ctx, req)
+}
+
+func (c *Client) StreamExecutionLogEntry(ctx context.Context, recordID, entryID, offset int, entry internalexecutor.ExecutionLogEntry) (err error) {
+	queueName, jobID := c.queueForRecord(recordID)
+
+	ctx, _, endObservation := c.operations.streamExecutionLogEntry.With(ctx, &err, observation.Args{LogFields: []otlog.Field{
+		otlog.String("queueName", queueName),
 		otlog.Int("jobID", jobID),
 		otlog.Int("entryID", entryID),
+		otlog.Int("offset", offset),
 	}})
 	defer endObservation(1, observation.Args{})
 
-	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/updateExecutionLogEntry", c.options.QueueName), executor.UpdateExecutionLogEntryRequest{
+	req, err := c.client.NewJSONRequest(http.MethodPost, fmt.Sprintf("%s/streamExecutionLogEntry", queueName), executor.StreamExecutionLogEntryRequest{
 		ExecutorName:      c.options.ExecutorName,
 		JobID:             jobID,
 		EntryID:           entryID,
+		Offset:            offset,
 		ExecutionLogEntry: entry,
 	})
 	if err != nil {
@@ -17,6 +17,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/apiclient"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/apiclient/queue"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 	internalexecutor "github.com/sourcegraph/sourcegraph/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 )
@@ -279,6 +280,80 @@ func TestHeartbeatBadResponse(t *testing.T) {
 	})
 }
 
+func TestDequeueMultipleQueues(t *testing.T) {
+	spec := routeSpec{
+		expectedMethod:   "POST",
+		expectedPath:     "/.executors/queue/dequeue",
+		expectedUsername: "test",
+		expectedToken:    "hunter2",
+		expectedPayload:  `{"executorName": "deadbeef", "version": "0.0.0+dev", "queues": ["batches", "codeintel"]}`,
+		responseStatus:   http.StatusOK,
+		responsePayload:  `{"id": 42, "queue": "codeintel"}`,
+	}
+
+	testMultiQueueRoute(t, spec, func(client *queue.Client) {
+		job, dequeued, err := client.Dequeue(context.Background(), "worker", nil)
+		if err != nil {
+			t.Fatalf("unexpected error dequeueing record: %s", err)
+		}
+		if !dequeued {
+			t.Fatalf("expected record to be dequeued")
+		}
+		if job.ID != 42 || job.Queue != "codeintel" {
+			t.Errorf("unexpected job. want=%d/%s have=%d/%s", 42, "codeintel", job.ID, job.Queue)
+		}
+		if queueName, id := executor.ParseRecordID(job.RecordID()); queueName != "codeintel" || id != 42 {
+			t.Errorf("unexpected record id. want=%d/%s have=%d/%s", 42, "codeintel", id, queueName)
+		}
+	})
+}
+
+func TestHeartbeatMultipleQueues(t *testing.T) {
+	spec := routeSpec{
+		expectedMethod:   "POST",
+		expectedPath:     "/.executors/queue/heartbeat",
+		expectedUsername: "test",
+		expectedToken:    "hunter2",
+		expectedPayload: `{
+			"executorName": "deadbeef",
+			"jobIds": null,
+			"jobIdsByQueue": {"batches": [1], "codeintel": [1, 2]},
+			"version": "V2",
+
+			"os": "test-os",
+			"architecture": "test-architecture",
+			"dockerVersion": "test-docker-version",
+			"executorVersion": "test-executor-version",
+			"gitVersion": "test-git-version",
+			"igniteVersion": "test-ignite-version",
+			"srcCliVersion": "test-src-cli-version",
+
+			"prometheusMetrics": ""
+		}`,
+		responseStatus:  http.StatusOK,
+		responsePayload: `{"knownIdsByQueue": {"batches": [1], "codeintel": [2]}, "cancelIdsByQueue": {"codeintel": [2]}}`,
+	}
+
+	batches1 := executor.Job{ID: 1, Queue: "batches"}.RecordID()
+	codeintel1 := executor.Job{ID: 1, Queue: "codeintel"}.RecordID()
+	codeintel2 := executor.Job{ID: 2, Queue: "codeintel"}.RecordID()
+
+	testMultiQueueRoute(t, spec, func(client *queue.Client) {
+		knownIDs, cancelIDs, err := client.Heartbeat(context.Background(), []int{batches1, codeintel1, codeintel2})
+		if err != nil {
+			t.Fatalf("unexpected error performing heartbeat: %s", err)
+		}
+
+		if diff := cmp.Diff([]int{batches1, codeintel2}, knownIDs); diff != "" {
+			t.Errorf("unexpected known ids (-want +got):\n%s", diff)
+		}
+
+		if diff := cmp.Diff([]int{codeintel2}, cancelIDs); diff != "" {
+			t.Errorf("unexpected cancel ids (-want +got):\n%s", diff)
+		}
+	})
+}
+
 func TestAddExecutionLogEntry(t *testing.T) {
 	entry := internalexecutor.ExecutionLogEntry{
 		Key:        "foo",
@@ -429,6 +504,40 @@ func TestUpdateExecutionLogEntryBadResponse(t *testing.T) {
 	})
 }
 
+func TestStreamExecutionLogEntry(t *testing.T) {
+	entry := internalexecutor.ExecutionLogEntry{
+		Key:       "foo",
+		Command:   []string{"ls", "-a"},
+		StartTime: time.Unix(1587396557, 0).UTC(),
+		Out:       "<log chunk>",
+	}
+
+	spec := routeSpec{
+		expectedMethod:   "POST",
+		expectedPath:     "/.executors/queue/test_queue/streamExecutionLogEntry",
+		expectedUsername: "test",
+		expectedToken:    "hunter2",
+		expectedPayload: `{
+			"executorName": "deadbeef",
+			"jobId": 42,
+			"entryId": 99,
+			"offset": 1024,
+			"key": "foo",
+			"command": ["ls", "-a"],
+			"startTime": "2020-04-20T15:29:17Z",
+			"out": "<log chunk>"
+		}`,
+		responseStatus:  http.StatusNoContent,
+		responsePayload: ``,
+	}
+
+	testRoute(t, spec, func(client *queue.Client) {
+		if err := client.StreamExecutionLogEntry(context.Background(), 42, 99, 1024, entry); err != nil {
+			t.Fatalf("unexpected error streaming log contents: %s", err)
+		}
+	})
+}
+
 type routeSpec struct {
 	expectedMethod   string
 	expectedPath     string
@@ -440,6 +549,17 @@ type routeSpec struct {
 }
 
 func testRoute(t *testing.T, spec routeSpec, f func(client *queue.Client)) {
+	testRouteWithOptions(t, spec, func(options *queue.Options) {}, f)
+}
+
+func testMultiQueueRoute(t *testing.T, spec routeSpec, f func(client *queue.Client)) {
+	testRouteWithOptions(t, spec, func(options *queue.Options) {
+		options.QueueName = ""
+		options.QueueNames = []string{"batches", "codeintel"}
+	}, f)
+}
+
+func testRouteWithOptions(t *testing.T, spec routeSpec, configure func(options *queue.Options), f func(client *queue.Client)) {
 	ts := testServer(t, spec)
 	defer ts.Close()
 
@@ -463,6 +583,7 @@ func testRoute(t *testing.T, spec routeSpec, f func(client *queue.Client)) {
 			SrcCliVersion:   "test-src-cli-version",
 		},
 	}
+	configure(&options)
 
 	client, err := queue.New(&observation.TestContext, options, prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) { return nil, nil }))
 	require.NoError(t, err)
@@ -11,6 +11,19 @@ type Options struct {
 	// QueueName is the name of the queue being processed.
 	QueueName string
 
+	// QueueNames are the names of the queues being processed, if the executor
+	// processes multiple queues at once. Exclusive with QueueName.
+	QueueNames []string
+
+	// QueueWeights maps queue names to their weight when processing multiple
+	// queues at once. Queues get a share of the running jobs proportional to
+	// their weight. Queues without a weight have a weight of 1.
+	QueueWeights map[string]int
+
+	// QueueMaxJobs maps queue names to the maximum number of jobs from that
+	// queue that can run at once, when processing multiple queues at once.
+	QueueMaxJobs map[string]int
+
 	// BaseClientOptions are the underlying HTTP client options.
 	BaseClientOptions apiclient.BaseClientOptions
 
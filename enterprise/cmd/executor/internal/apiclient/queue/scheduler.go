@@ -0,0 +1,98 @@
+package queue
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// scheduler decides which queues an executor processing multiple queues at once
+// dequeues from. Queues get a share of the running jobs proportional to their
+// weight, and queues running their maximum number of jobs are skipped.
+type scheduler struct {
+	queueNames []string
+	weights    map[string]int
+	maxJobs    map[string]int
+
+	mu      sync.Mutex
+	running map[string]int
+
+	runningJobs  *prometheus.GaugeVec
+	dequeuedJobs *prometheus.CounterVec
+}
+
+func newScheduler(observationCtx *observation.Context, queueNames []string, weights, maxJobs map[string]int) *scheduler {
+	runningJobs := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "src_executor_queue_running_jobs",
+		Help: "The number of jobs currently running per queue.",
+	}, []string{"queue"})
+	dequeuedJobs := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_executor_queue_dequeued_jobs_total",
+		Help: "The total number of jobs dequeued per queue.",
+	}, []string{"queue"})
+	observationCtx.Registerer.MustRegister(runningJobs, dequeuedJobs)
+
+	return &scheduler{
+		queueNames:   queueNames,
+		weights:      weights,
+		maxJobs:      maxJobs,
+		running:      make(map[string]int, len(queueNames)),
+		runningJobs:  runningJobs,
+		dequeuedJobs: dequeuedJobs,
+	}
+}
+
+// order returns the queues to dequeue the next job from, in order of preference.
+// Queues that would run the smallest number of jobs relative to their weight after
+// dequeueing the job are preferred. Ties are broken by the configured queue order.
+func (s *scheduler) order() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queueNames := make([]string, 0, len(s.queueNames))
+	for _, queueName := range s.queueNames {
+		if max, ok := s.maxJobs[queueName]; ok && s.running[queueName] >= max {
+			continue
+		}
+		queueNames = append(queueNames, queueName)
+	}
+
+	sort.SliceStable(queueNames, func(i, j int) bool {
+		a, b := queueNames[i], queueNames[j]
+		// Compare (running+1)/weight of both queues without dividing.
+		return (s.running[a]+1)*s.weight(b) < (s.running[b]+1)*s.weight(a)
+	})
+
+	return queueNames
+}
+
+// started records that a job from the given queue was dequeued.
+func (s *scheduler) started(queueName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running[queueName]++
+	s.runningJobs.WithLabelValues(queueName).Set(float64(s.running[queueName]))
+	s.dequeuedJobs.WithLabelValues(queueName).Inc()
+}
+
+// finished records that a job from the given queue is no longer running.
+func (s *scheduler) finished(queueName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running[queueName] > 0 {
+		s.running[queueName]--
+	}
+	s.runningJobs.WithLabelValues(queueName).Set(float64(s.running[queueName]))
+}
+
+func (s *scheduler) weight(queueName string) int {
+	if weight, ok := s.weights[queueName]; ok {
+		return weight
+	}
+	return 1
+}
@@ -0,0 +1,61 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestSchedulerOrder(t *testing.T) {
+	s := newScheduler(&observation.TestContext, []string{"batches", "codeintel"}, map[string]int{"codeintel": 3}, map[string]int{"batches": 1})
+
+	// Without running jobs, the queue with the higher weight is preferred.
+	if diff := cmp.Diff([]string{"codeintel", "batches"}, s.order()); diff != "" {
+		t.Errorf("unexpected order (-want +got):\n%s", diff)
+	}
+
+	// codeintel running 2 jobs with weight 3 is still ahead of batches with weight 1.
+	s.started("codeintel")
+	if diff := cmp.Diff([]string{"codeintel", "batches"}, s.order()); diff != "" {
+		t.Errorf("unexpected order (-want +got):\n%s", diff)
+	}
+	s.started("codeintel")
+	s.started("codeintel")
+	if diff := cmp.Diff([]string{"batches", "codeintel"}, s.order()); diff != "" {
+		t.Errorf("unexpected order (-want +got):\n%s", diff)
+	}
+
+	// batches is skipped while running its maximum number of jobs.
+	s.started("batches")
+	if diff := cmp.Diff([]string{"codeintel"}, s.order()); diff != "" {
+		t.Errorf("unexpected order (-want +got):\n%s", diff)
+	}
+
+	s.finished("batches")
+	if diff := cmp.Diff([]string{"batches", "codeintel"}, s.order()); diff != "" {
+		t.Errorf("unexpected order (-want +got):\n%s", diff)
+	}
+}
+
+func TestSchedulerOrderTies(t *testing.T) {
+	s := newScheduler(&observation.TestContext, []string{"codeintel", "batches"}, nil, nil)
+
+	// Ties are broken by the configured order.
+	if diff := cmp.Diff([]string{"codeintel", "batches"}, s.order()); diff != "" {
+		t.Errorf("unexpected order (-want +got):\n%s", diff)
+	}
+
+	s.started("codeintel")
+	if diff := cmp.Diff([]string{"batches", "codeintel"}, s.order()); diff != "" {
+		t.Errorf("unexpected order (-want +got):\n%s", diff)
+	}
+
+	// Finishing more jobs than were started does not make the count negative.
+	s.finished("codeintel")
+	s.finished("codeintel")
+	if diff := cmp.Diff([]string{"codeintel", "batches"}, s.order()); diff != "" {
+		t.Errorf("unexpected order (-want +got):\n%s", diff)
+	}
+}
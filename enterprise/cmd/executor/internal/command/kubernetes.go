@@ -0,0 +1,382 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	// kubernetesVolumeName is the name of the volume the workspace is mounted
+	// from in the pods of Kubernetes jobs.
+	kubernetesVolumeName = "sg-executor-job-volume"
+
+	// kubernetesContainerName is the name of the container running the step in
+	// the pods of Kubernetes jobs.
+	kubernetesContainerName = "sg-executor-job"
+
+	// kubernetesJobNameLabel is the label set on the pods of a job by the
+	// Kubernetes job controller.
+	kubernetesJobNameLabel = "job-name"
+
+	// kubernetesMaxNameLength is the maximum length of a label value.
+	kubernetesMaxNameLength = 63
+
+	// kubernetesNameHashLength is the number of hex characters of the hash
+	// appended to job names that have to be truncated.
+	kubernetesNameHashLength = 8
+)
+
+// kubernetesLabels are the labels set on all resources created by the
+// executor.
+var kubernetesLabels = map[string]string{"app.kubernetes.io/managed-by": "sourcegraph-executor"}
+
+// kubernetesPollInterval is the interval in which the state of the pod of a
+// Kubernetes job is checked. It can be lowered for testing.
+var kubernetesPollInterval = time.Second
+
+// kubernetesPodFailureReasons are the reasons for which a container of a pod
+// can be waiting that won't resolve without intervention, so the job fails
+// immediately instead of waiting for the job deadline.
+var kubernetesPodFailureReasons = map[string]struct{}{
+	"ErrImagePull":               {},
+	"ImagePullBackOff":           {},
+	"InvalidImageName":           {},
+	"CreateContainerConfigError": {},
+}
+
+// NewKubernetesClientset creates a client for the Kubernetes API. If configPath
+// is empty, the in-cluster configuration of the pod the executor is running in
+// is used.
+func NewKubernetesClientset(configPath string) (kubernetes.Interface, error) {
+	var (
+		config *rest.Config
+		err    error
+	)
+	if configPath != "" {
+		config, err = clientcmd.BuildConfigFromFlags("", configPath)
+	} else {
+		config, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "loading Kubernetes configuration")
+	}
+
+	return kubernetes.NewForConfig(config)
+}
+
+type kubernetesRunner struct {
+	dir       string
+	cmdLogger Logger
+	options   Options
+}
+
+var _ Runner = &kubernetesRunner{}
+
+// Setup creates the secret the pods of the jobs pull their images with, if
+// registry credentials are configured.
+func (r *kubernetesRunner) Setup(ctx context.Context) error {
+	secret, err := formatKubernetesRegistryAuthSecret(r.dir, r.options)
+	if err != nil || secret == nil {
+		return err
+	}
+
+	if _, err := r.options.KubernetesOptions.Clientset.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return errors.Wrap(err, "creating registry auth secret")
+	}
+	return nil
+}
+
+// Teardown deletes the secret created in Setup.
+func (r *kubernetesRunner) Teardown(ctx context.Context) error {
+	if len(r.options.DockerOptions.DockerAuthConfig.Auths) == 0 {
+		return nil
+	}
+
+	name := kubernetesRegistryAuthSecretName(r.dir, r.options.ExecutorName)
+	if err := r.options.KubernetesOptions.Clientset.CoreV1().Secrets(r.options.KubernetesOptions.Namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return errors.Wrap(err, "deleting registry auth secret")
+	}
+	return nil
+}
+
+func (r *kubernetesRunner) Run(ctx context.Context, spec CommandSpec) error {
+	// Commands without an image run in the executor pod itself, just like
+	// they run on the host with the docker runner.
+	if spec.Image == "" {
+		return runCommand(ctx, formatRawOrDockerCommand(spec, r.dir, r.options, ""), r.cmdLogger)
+	}
+
+	job := formatKubernetesJob(spec, r.dir, r.options)
+	return runKubernetesJob(ctx, r.options.KubernetesOptions.Clientset, job, spec.Operation, spec.Key, r.cmdLogger)
+}
+
+// formatKubernetesJob constructs the Kubernetes job that invokes the given
+// spec. The workspace is mounted from the persistent volume claim shared with
+// the executor, in which it resides in a directory of the same name, subject
+// to the resource limits specified in the given options.
+func formatKubernetesJob(spec CommandSpec, dir string, options Options) *batchv1.Job {
+	env := make([]corev1.EnvVar, 0, len(spec.Env))
+	for _, e := range spec.Env {
+		name, value, _ := strings.Cut(e, "=")
+		env = append(env, corev1.EnvVar{Name: name, Value: value})
+	}
+
+	var imagePullSecrets []corev1.LocalObjectReference
+	if len(options.DockerOptions.DockerAuthConfig.Auths) > 0 {
+		imagePullSecrets = append(imagePullSecrets, corev1.LocalObjectReference{
+			Name: kubernetesRegistryAuthSecretName(dir, options.ExecutorName),
+		})
+	}
+
+	backoffLimit := int32(0)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kubernetesJobName(options.ExecutorName, spec.Key),
+			Namespace: options.KubernetesOptions.Namespace,
+			Labels:    kubernetesLabels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: kubernetesLabels},
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: imagePullSecrets,
+					Containers: []corev1.Container{{
+						Name:       kubernetesContainerName,
+						Image:      spec.Image,
+						Command:    []string{"/bin/sh", path.Join("/data", ScriptsPath, spec.ScriptPath)},
+						WorkingDir: path.Join("/data", spec.Dir),
+						Env:        env,
+						Resources:  kubernetesResources(options.ResourceOptions),
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      kubernetesVolumeName,
+							MountPath: "/data",
+							SubPath:   filepath.Base(dir),
+						}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: kubernetesVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+								ClaimName: options.KubernetesOptions.PersistenceVolumeName,
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// formatKubernetesRegistryAuthSecret constructs the docker config secret the
+// pods of the jobs of the workspace in the given directory pull their images
+// with. No secret is returned if no registry credentials are configured.
+func formatKubernetesRegistryAuthSecret(dir string, options Options) (*corev1.Secret, error) {
+	if len(options.DockerOptions.DockerAuthConfig.Auths) == 0 {
+		return nil, nil
+	}
+
+	dockerConfig, err := json.Marshal(options.DockerOptions.DockerAuthConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling docker auth config")
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kubernetesRegistryAuthSecretName(dir, options.ExecutorName),
+			Namespace: options.KubernetesOptions.Namespace,
+			Labels:    kubernetesLabels,
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig},
+	}, nil
+}
+
+// kubernetesRegistryAuthSecretName returns the name of the registry auth secret
+// of the workspace in the given directory. Workspace directories are unique per
+// job, so concurrent jobs of the same executor don't share a secret.
+func kubernetesRegistryAuthSecretName(dir, executorName string) string {
+	return kubernetesJobName(executorName, filepath.Base(dir)+"-registry-auth")
+}
+
+var invalidKubernetesNameCharacters = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// kubernetesJobName returns a valid name for the job running the step with the
+// given key. Job names are used as label values, so they can't be longer than
+// 63 characters. Longer names are truncated and end in a hash of the full name
+// instead, so that steps whose keys only differ at the end get different names.
+func kubernetesJobName(executorName, key string) string {
+	full := executorName + "-" + key
+	name := invalidKubernetesNameCharacters.ReplaceAllString(strings.ToLower(full), "-")
+	if len(name) > kubernetesMaxNameLength {
+		sum := sha256.Sum256([]byte(full))
+		hash := hex.EncodeToString(sum[:])[:kubernetesNameHashLength]
+		name = strings.TrimRight(name[:kubernetesMaxNameLength-kubernetesNameHashLength-1], "-") + "-" + hash
+	}
+	return strings.Trim(name, "-")
+}
+
+func kubernetesResources(options ResourceOptions) corev1.ResourceRequirements {
+	resources := corev1.ResourceList{}
+	if options.NumCPUs != 0 {
+		resources[corev1.ResourceCPU] = resource.MustParse(strconv.Itoa(options.NumCPUs))
+	}
+	if options.Memory != "0" && options.Memory != "" {
+		if memory, err := resource.ParseQuantity(options.Memory); err == nil {
+			resources[corev1.ResourceMemory] = memory
+		}
+	}
+	if len(resources) == 0 {
+		return corev1.ResourceRequirements{}
+	}
+
+	return corev1.ResourceRequirements{Limits: resources, Requests: resources}
+}
+
+// runKubernetesJob creates the given job and waits for it to complete. The
+// output of the pod running the job is written to the given logger. The job is
+// deleted once it completed.
+func runKubernetesJob(ctx context.Context, clientset kubernetes.Interface, job *batchv1.Job, operation *observation.Operation, key string, logger Logger) (err error) {
+	ctx, _, endObservation := operation.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	jobs := clientset.BatchV1().Jobs(job.Namespace)
+	if _, err := jobs.Create(ctx, job, metav1.CreateOptions{}); err != nil {
+		return errors.Wrap(err, "creating job")
+	}
+	defer func() {
+		// Clean up outside of the job context, so that jobs of canceled or
+		// timed out steps are removed as well.
+		propagation := metav1.DeletePropagationBackground
+		if deleteErr := jobs.Delete(context.Background(), job.Name, metav1.DeleteOptions{PropagationPolicy: &propagation}); deleteErr != nil {
+			err = errors.Append(err, errors.Wrap(deleteErr, "deleting job"))
+		}
+	}()
+
+	container := job.Spec.Template.Spec.Containers[0]
+	handle := logger.Log(key, flatten("kubernetes", "job", job.Name, container.Image, container.Command))
+	defer handle.Close()
+
+	pod, err := waitForKubernetesPod(ctx, clientset, job, func(pod *corev1.Pod) bool {
+		return pod.Status.Phase != corev1.PodPending
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := streamKubernetesPodLogs(ctx, clientset, pod, handle); err != nil {
+		return err
+	}
+
+	pod, err = waitForKubernetesPod(ctx, clientset, job, func(pod *corev1.Pod) bool {
+		return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+	})
+	if err != nil {
+		return err
+	}
+
+	exitCode := kubernetesExitCode(pod)
+	handle.Finalize(exitCode)
+	if exitCode != 0 {
+		return errors.New("command failed")
+	}
+	return nil
+}
+
+// waitForKubernetesPod polls the pod of the given job until the given
+// condition is met. An error is returned if the pod can't be started.
+func waitForKubernetesPod(ctx context.Context, clientset kubernetes.Interface, job *batchv1.Job, cond func(*corev1.Pod) bool) (*corev1.Pod, error) {
+	ticker := time.NewTicker(kubernetesPollInterval)
+	defer ticker.Stop()
+
+	for {
+		pods, err := clientset.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", kubernetesJobNameLabel, job.Name),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "listing job pods")
+		}
+
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			for _, status := range pod.Status.ContainerStatuses {
+				if waiting := status.State.Waiting; waiting != nil {
+					if _, ok := kubernetesPodFailureReasons[waiting.Reason]; ok {
+						return nil, errors.Newf("pod %s cannot start: %s: %s", pod.Name, waiting.Reason, waiting.Message)
+					}
+				}
+			}
+			if cond(pod) {
+				return pod, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// streamKubernetesPodLogs writes the output of the given pod to the log entry
+// until the container exits. Kubernetes doesn't separate the output streams,
+// so all output is reported as stdout.
+func streamKubernetesPodLogs(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, handle LogEntry) error {
+	stream, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: kubernetesContainerName,
+		Follow:    true,
+	}).Stream(ctx)
+	if err != nil {
+		return errors.Wrap(err, "streaming pod logs")
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	// Allocate an initial buffer of 4k and allow tokens of up to 100M, as when
+	// reading the output of commands run on the host.
+	scanner.Buffer(make([]byte, 4*1024), 100*1024*1024)
+	for scanner.Scan() {
+		if _, err := fmt.Fprintf(handle, "stdout: %s\n", scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(scanner.Err(), "reading pod logs")
+}
+
+// kubernetesExitCode returns the exit code of the step container of the given
+// completed pod.
+func kubernetesExitCode(pod *corev1.Pod) int {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == kubernetesContainerName && status.State.Terminated != nil {
+			return int(status.State.Terminated.ExitCode)
+		}
+	}
+	if pod.Status.Phase == corev1.PodSucceeded {
+		return 0
+	}
+	return 1
+}
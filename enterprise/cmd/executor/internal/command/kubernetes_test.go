@@ -0,0 +1,313 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+)
+
+func TestFormatKubernetesJob(t *testing.T) {
+	job := formatKubernetesJob(
+		CommandSpec{
+			Key:        "step.0",
+			Image:      "alpine:latest",
+			ScriptPath: "myscript.sh",
+			Dir:        "subdir",
+			Env:        []string{"FOO=bar=baz"},
+		},
+		"/tmp/workspace-1234",
+		Options{
+			ExecutorName: "Executor_1",
+			KubernetesOptions: KubernetesOptions{
+				Enabled:               true,
+				Namespace:             "sourcegraph",
+				PersistenceVolumeName: "executor-pvc",
+			},
+			ResourceOptions: ResourceOptions{
+				NumCPUs: 4,
+				Memory:  "20G",
+			},
+		},
+	)
+
+	if job.Name != "executor-1-step-0" {
+		t.Errorf("unexpected job name %q", job.Name)
+	}
+	if job.Namespace != "sourcegraph" {
+		t.Errorf("unexpected namespace %q", job.Namespace)
+	}
+
+	spec := job.Spec.Template.Spec
+	if spec.Volumes[0].PersistentVolumeClaim.ClaimName != "executor-pvc" {
+		t.Errorf("unexpected volume claim %q", spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	}
+	if len(spec.ImagePullSecrets) != 0 {
+		t.Errorf("unexpected image pull secrets %v", spec.ImagePullSecrets)
+	}
+
+	container := spec.Containers[0]
+	expectedContainer := corev1.Container{
+		Name:       kubernetesContainerName,
+		Image:      "alpine:latest",
+		Command:    []string{"/bin/sh", "/data/.sourcegraph-executor/myscript.sh"},
+		WorkingDir: "/data/subdir",
+		Env:        []corev1.EnvVar{{Name: "FOO", Value: "bar=baz"}},
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("20G"),
+			},
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("20G"),
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      kubernetesVolumeName,
+			MountPath: "/data",
+			SubPath:   "workspace-1234",
+		}},
+	}
+	if diff := cmp.Diff(expectedContainer, container); diff != "" {
+		t.Errorf("unexpected container (-want +got):\n%s", diff)
+	}
+}
+
+func TestKubernetesJobName(t *testing.T) {
+	for input, expected := range map[string]string{
+		"step.0": "sourcegraph-executor-step-0",
+		"Step_1": "sourcegraph-executor-step-1",
+		"step-with-a-name-that-is-way-too-long-for-a-kubernetes-label-value": "sourcegraph-executor-step-with-a-name-that-is-way-too-94c7a178",
+	} {
+		if name := kubernetesJobName("sourcegraph-executor", input); name != expected {
+			t.Errorf("unexpected name for %q. want=%q have=%q", input, expected, name)
+		}
+	}
+
+	// Keys that only differ after the truncation point still get unique names.
+	long := "step-with-a-name-that-is-way-too-long-for-a-kubernetes-label-value"
+	if a, b := kubernetesJobName("sourcegraph-executor", long+".0"), kubernetesJobName("sourcegraph-executor", long+".1"); a == b {
+		t.Errorf("expected different names for different keys, have %q for both", a)
+	}
+}
+
+func TestKubernetesRunnerRun(t *testing.T) {
+	setKubernetesPollInterval(t, time.Millisecond)
+
+	for name, testCase := range map[string]struct {
+		exitCode  int32
+		expectErr bool
+	}{
+		"success": {exitCode: 0},
+		"failure": {exitCode: 1, expectErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			clientset := newFakeKubernetesClientset(t, testCase.exitCode)
+			logger := NewMockLogger()
+			logEntry := NewMockLogEntry()
+			logger.LogFunc.SetDefaultReturn(logEntry)
+
+			runner := NewRunner("/tmp/workspace-1234", logger, Options{
+				ExecutorName: "executor",
+				KubernetesOptions: KubernetesOptions{
+					Enabled:               true,
+					Namespace:             "default",
+					PersistenceVolumeName: "executor-pvc",
+					Clientset:             clientset,
+				},
+			}, nil)
+
+			err := runner.Run(context.Background(), CommandSpec{
+				Key:        "step.0",
+				Image:      "alpine:latest",
+				ScriptPath: "myscript.sh",
+				Operation:  makeTestOperation(),
+			})
+			if testCase.expectErr && err == nil {
+				t.Fatal("expected error")
+			} else if !testCase.expectErr && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(logEntry.FinalizeFunc.History()) != 1 {
+				t.Fatalf("expected log entry to be finalized once")
+			}
+			if exitCode := logEntry.FinalizeFunc.History()[0].Arg0; exitCode != int(testCase.exitCode) {
+				t.Errorf("unexpected exit code. want=%d have=%d", testCase.exitCode, exitCode)
+			}
+			if len(logEntry.WriteFunc.History()) == 0 {
+				t.Errorf("expected pod logs to be written")
+			}
+
+			jobs, err := clientset.BatchV1().Jobs("default").List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error listing jobs: %s", err)
+			}
+			if len(jobs.Items) != 0 {
+				t.Errorf("expected job to be deleted, found %d jobs", len(jobs.Items))
+			}
+		})
+	}
+}
+
+func TestKubernetesRunnerRegistryAuth(t *testing.T) {
+	setKubernetesPollInterval(t, time.Millisecond)
+
+	clientset := newFakeKubernetesClientset(t, 0)
+	var pullSecrets []corev1.LocalObjectReference
+	clientset.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pullSecrets = action.(k8stesting.CreateAction).GetObject().(*batchv1.Job).Spec.Template.Spec.ImagePullSecrets
+		return false, nil, nil
+	})
+
+	logger := NewMockLogger()
+	logger.LogFunc.SetDefaultReturn(NewMockLogEntry())
+
+	runner := NewRunner("/tmp/workspace-1234", logger, Options{
+		ExecutorName: "executor",
+		DockerOptions: DockerOptions{
+			DockerAuthConfig: executor.DockerAuthConfig{
+				Auths: executor.DockerAuthConfigAuths{
+					"index.docker.io": executor.DockerAuthConfigAuth{Auth: []byte("user:pass")},
+				},
+			},
+		},
+		KubernetesOptions: KubernetesOptions{
+			Enabled:   true,
+			Namespace: "default",
+			Clientset: clientset,
+		},
+	}, nil)
+
+	ctx := context.Background()
+	if err := runner.Setup(ctx); err != nil {
+		t.Fatalf("unexpected error setting up runner: %s", err)
+	}
+
+	secret, err := clientset.CoreV1().Secrets("default").Get(ctx, "executor-workspace-1234-registry-auth", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error getting secret: %s", err)
+	}
+	if secret.Type != corev1.SecretTypeDockerConfigJson {
+		t.Errorf("unexpected secret type %q", secret.Type)
+	}
+	expectedConfig := `{"auths":{"index.docker.io":{"auth":"dXNlcjpwYXNz"}}}`
+	if config := string(secret.Data[corev1.DockerConfigJsonKey]); config != expectedConfig {
+		t.Errorf("unexpected docker config. want=%s have=%s", expectedConfig, config)
+	}
+
+	if err := runner.Run(ctx, CommandSpec{
+		Key:        "step.0",
+		Image:      "private/image:latest",
+		ScriptPath: "myscript.sh",
+		Operation:  makeTestOperation(),
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]corev1.LocalObjectReference{{Name: secret.Name}}, pullSecrets); diff != "" {
+		t.Errorf("unexpected image pull secrets (-want +got):\n%s", diff)
+	}
+
+	if err := runner.Teardown(ctx); err != nil {
+		t.Fatalf("unexpected error tearing down runner: %s", err)
+	}
+	secrets, err := clientset.CoreV1().Secrets("default").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error listing secrets: %s", err)
+	}
+	if len(secrets.Items) != 0 {
+		t.Errorf("expected secret to be deleted, found %d secrets", len(secrets.Items))
+	}
+}
+
+func TestKubernetesRunnerRunImagePullFailure(t *testing.T) {
+	setKubernetesPollInterval(t, time.Millisecond)
+
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		job := action.(k8stesting.CreateAction).GetObject().(*batchv1.Job)
+		pod := newFakeJobPod(job, corev1.PodPending, corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: "ErrImagePull"},
+		})
+		return false, nil, clientset.Tracker().Add(pod)
+	})
+
+	logger := NewMockLogger()
+	logger.LogFunc.SetDefaultReturn(NewMockLogEntry())
+
+	runner := NewRunner("/tmp/workspace-1234", logger, Options{
+		ExecutorName: "executor",
+		KubernetesOptions: KubernetesOptions{
+			Enabled:   true,
+			Namespace: "default",
+			Clientset: clientset,
+		},
+	}, nil)
+
+	if err := runner.Run(context.Background(), CommandSpec{
+		Key:       "step.0",
+		Image:     "does-not-exist",
+		Operation: makeTestOperation(),
+	}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func setKubernetesPollInterval(t *testing.T, interval time.Duration) {
+	old := kubernetesPollInterval
+	kubernetesPollInterval = interval
+	t.Cleanup(func() { kubernetesPollInterval = old })
+}
+
+// newFakeKubernetesClientset returns a fake clientset that creates a completed
+// pod exiting with the given exit code for every created job.
+func newFakeKubernetesClientset(t *testing.T, exitCode int32) *fake.Clientset {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		job := action.(k8stesting.CreateAction).GetObject().(*batchv1.Job)
+
+		phase := corev1.PodSucceeded
+		if exitCode != 0 {
+			phase = corev1.PodFailed
+		}
+		pod := newFakeJobPod(job, phase, corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode},
+		})
+		if err := clientset.Tracker().Add(pod); err != nil {
+			t.Fatalf("unexpected error creating pod: %s", err)
+		}
+
+		// Let the default reactor store the job.
+		return false, nil, nil
+	})
+
+	return clientset
+}
+
+func newFakeJobPod(job *batchv1.Job, phase corev1.PodPhase, state corev1.ContainerState) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      job.Name + "-pod",
+			Namespace: job.Namespace,
+			Labels:    map[string]string{kubernetesJobNameLabel: job.Name},
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  kubernetesContainerName,
+				State: state,
+			}},
+		},
+	}
+}
@@ -88,14 +88,19 @@ func (h *entryHandle) currentLogEntry() internalexecutor.ExecutionLogEntry {
 }
 
 type logger struct {
-	store   ExecutionLogEntryStore
-	done    chan struct{}
-	handles chan *entryHandle
+	store ExecutionLogEntryStore
+	// streamer is set if the store can stream log output as it is written.
+	streamer ExecutionLogEntryStreamer
+	done     chan struct{}
+	handles  chan *entryHandle
 
 	job      executor.Job
 	recordID int
 
 	replacer *strings.Replacer
+	// secrets are the values redacted by the replacer, which are held back
+	// from streaming until they have been written completely.
+	secrets []string
 
 	errs   error
 	errsMu sync.Mutex
@@ -107,6 +112,14 @@ type ExecutionLogEntryStore interface {
 	UpdateExecutionLogEntry(ctx context.Context, id, entryID int, entry internalexecutor.ExecutionLogEntry) error
 }
 
+// ExecutionLogEntryStreamer is implemented by stores that can send the output of a
+// log entry as it is written, so that users can follow it before it is persisted.
+// Streaming is best-effort: the log entry is always persisted via the
+// ExecutionLogEntryStore as well.
+type ExecutionLogEntryStreamer interface {
+	StreamExecutionLogEntry(ctx context.Context, id, entryID, offset int, entry internalexecutor.ExecutionLogEntry) error
+}
+
 // logEntryBufSize is the maximum number of log entries that are logged by the
 // task execution but not yet written to the database.
 const logEntryBufsize = 50
@@ -116,11 +129,17 @@ const logEntryBufsize = 50
 // When the log messages are serialized, any occurrence of sensitive values are
 // replace with a non-sensitive value.
 // Each log message is written to the store in a goroutine. The Flush method
-// must be called to ensure all entries are written.
+// must be called to ensure all entries are written. If the store implements
+// ExecutionLogEntryStreamer, the output of each entry is also streamed while
+// it is written.
 func NewLogger(store ExecutionLogEntryStore, job executor.Job, recordID int, replacements map[string]string) Logger {
 	oldnew := make([]string, 0, len(replacements)*2)
+	secrets := make([]string, 0, len(replacements))
 	for k, v := range replacements {
 		oldnew = append(oldnew, k, v)
+		if k != "" {
+			secrets = append(secrets, k)
+		}
 	}
 
 	l := &logger{
@@ -130,8 +149,10 @@ func NewLogger(store ExecutionLogEntryStore, job executor.Job, recordID int, rep
 		done:     make(chan struct{}),
 		handles:  make(chan *entryHandle, logEntryBufsize),
 		replacer: strings.NewReplacer(oldnew...),
+		secrets:  secrets,
 		errs:     nil,
 	}
+	l.streamer, _ = store.(ExecutionLogEntryStreamer)
 
 	go l.writeEntries()
 
@@ -196,20 +217,41 @@ func (l *logger) writeEntries() {
 
 const syncLogEntryInterval = 1 * time.Second
 
+// streamLogEntryInterval is the interval in which new output of a log entry is
+// streamed, if the store supports streaming.
+const streamLogEntryInterval = 250 * time.Millisecond
+
 func (l *logger) syncLogEntry(handle *entryHandle, entryID int, old internalexecutor.ExecutionLogEntry) {
 	lastWrite := false
 
+	var stream *logEntryStream
+	interval := syncLogEntryInterval
+	if l.streamer != nil {
+		stream = &logEntryStream{}
+		interval = streamLogEntryInterval
+	}
+	lastSync := time.Now()
+
 	for !lastWrite {
 		select {
 		case <-handle.done:
 			lastWrite = true
-		case <-time.After(syncLogEntryInterval):
+		case <-time.After(interval):
+		}
+
+		if stream != nil {
+			l.streamLogEntry(stream, entryID, handle.currentLogEntry(), lastWrite)
 		}
 
 		current := handle.CurrentLogEntry()
+
+		if !lastWrite && time.Since(lastSync) < syncLogEntryInterval {
+			continue
+		}
 		if !entryWasUpdated(old, current) {
 			continue
 		}
+		lastSync = time.Now()
 
 		logArgs := make([]any, 0, 16)
 		logArgs = append(
@@ -254,6 +296,106 @@ func (l *logger) syncLogEntry(handle *entryHandle, entryID int, old internalexec
 	}
 }
 
+// logEntryStream tracks how much of the output of a log entry has been streamed.
+type logEntryStream struct {
+	// rawOffset is the offset in the unredacted output up to which it has been
+	// streamed. Redaction changes the length of the output, so this is the
+	// position to continue from.
+	rawOffset int
+	// offset is the length of the redacted output streamed so far.
+	offset   int
+	started  bool
+	finished bool
+	failed   bool
+}
+
+// maxStreamChunkSize is the maximum number of bytes of output sent with a single
+// stream request.
+const maxStreamChunkSize = 256 * 1024
+
+// streamLogEntry sends the output of the given unredacted log entry that has
+// not been streamed yet. Until the entry is finalized or closed, a tail as long
+// as the longest secret is held back, as it could be the beginning of a secret
+// that is only redacted once it has been written completely. Errors are logged
+// and stop the streaming of the entry, as the entry is persisted independently.
+func (l *logger) streamLogEntry(stream *logEntryStream, entryID int, raw internalexecutor.ExecutionLogEntry, closed bool) {
+	if stream.failed {
+		return
+	}
+
+	end := len(raw.Out)
+	if !closed && raw.ExitCode == nil {
+		for _, secret := range l.secrets {
+			if len(raw.Out)-len(secret) < end {
+				end = len(raw.Out) - len(secret)
+			}
+		}
+	}
+	if end < stream.rawOffset {
+		end = stream.rawOffset
+	}
+	end = l.secretBoundary(raw.Out, stream.rawOffset, end)
+
+	for {
+		chunkEnd := end
+		if chunkEnd-stream.rawOffset > maxStreamChunkSize {
+			chunkEnd = l.secretBoundary(raw.Out, stream.rawOffset, stream.rawOffset+maxStreamChunkSize)
+		}
+
+		finished := raw.ExitCode != nil && chunkEnd == len(raw.Out)
+		if stream.started && chunkEnd == stream.rawOffset && (!finished || stream.finished) {
+			return
+		}
+
+		chunk := raw
+		chunk.Out = raw.Out[stream.rawOffset:chunkEnd]
+		redact(&chunk, l.replacer)
+		if !finished {
+			chunk.ExitCode = nil
+			chunk.DurationMs = nil
+		}
+
+		if err := l.streamer.StreamExecutionLogEntry(context.Background(), l.recordID, entryID, stream.offset, chunk); err != nil {
+			log15.Debug("Failed to stream executor log entry for job", "jobID", l.job.ID, "entryID", entryID, "error", err)
+			stream.failed = true
+			return
+		}
+
+		stream.rawOffset = chunkEnd
+		stream.offset += len(chunk.Out)
+		stream.started = true
+		stream.finished = finished
+	}
+}
+
+// secretBoundary returns the largest offset of at most end and at least start
+// at which the given output can be cut without splitting a secret. Redaction
+// only replaces complete secrets, so the part of a split secret before the cut
+// would be streamed as is.
+func (l *logger) secretBoundary(out string, start, end int) int {
+	for moved := true; moved; {
+		moved = false
+		for _, secret := range l.secrets {
+			// A secret crossing end begins less than its length before end.
+			from, to := end-len(secret)+1, end+len(secret)-1
+			if from < start {
+				from = start
+			}
+			if to > len(out) {
+				to = len(out)
+			}
+			if from >= to {
+				continue
+			}
+			if i := strings.Index(out[from:to], secret); i >= 0 {
+				end = from + i
+				moved = true
+			}
+		}
+	}
+	return end
+}
+
 func (l *logger) appendError(err error) {
 	l.errsMu.Lock()
 	l.errs = errors.Append(l.errs, err)
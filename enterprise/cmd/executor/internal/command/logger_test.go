@@ -2,7 +2,10 @@ package command
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 	internalexecutor "github.com/sourcegraph/sourcegraph/internal/executor"
@@ -97,3 +100,163 @@ func TestLogger_Failure(t *testing.T) {
 		t.Fatalf("incorrect invokation count on UpdateExecutionLogEntry, want=%d have=%d", 1, len(s.UpdateExecutionLogEntryFunc.History()))
 	}
 }
+
+type streamingExecutionLogEntryStore struct {
+	*MockExecutionLogEntryStore
+
+	mu     sync.Mutex
+	chunks []streamedChunk
+}
+
+type streamedChunk struct {
+	entryID  int
+	offset   int
+	out      string
+	exitCode *int
+}
+
+func (s *streamingExecutionLogEntryStore) StreamExecutionLogEntry(_ context.Context, _, entryID, offset int, entry internalexecutor.ExecutionLogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chunks = append(s.chunks, streamedChunk{entryID: entryID, offset: offset, out: entry.Out, exitCode: entry.ExitCode})
+	return nil
+}
+
+func TestLogger_Stream(t *testing.T) {
+	s := &streamingExecutionLogEntryStore{MockExecutionLogEntryStore: NewMockExecutionLogEntryStore()}
+
+	doneAdding := make(chan struct{})
+	s.AddExecutionLogEntryFunc.SetDefaultHook(func(_ context.Context, _ int, _ internalexecutor.ExecutionLogEntry) (int, error) {
+		doneAdding <- struct{}{}
+		return 7, nil
+	})
+
+	job := executor.Job{}
+	l := NewLogger(s, job, 1, map[string]string{"secret": "******"})
+
+	e := l.Log("the_key", []string{"cmd", "arg1"})
+
+	flushDone := make(chan error)
+	go func() {
+		flushDone <- l.Flush()
+	}()
+
+	// Wait for AddExecutionLogEntry to have been called.
+	<-doneAdding
+	if _, err := e.Write([]byte("first line\n")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * streamLogEntryInterval)
+	if _, err := e.Write([]byte("the secret line\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	e.Finalize(1)
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-flushDone; err != nil {
+		t.Fatal(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out string
+	for _, chunk := range s.chunks {
+		if chunk.entryID != 7 {
+			t.Errorf("unexpected entry ID. want=%d have=%d", 7, chunk.entryID)
+		}
+		if chunk.offset != len(out) {
+			t.Fatalf("unexpected offset. want=%d have=%d", len(out), chunk.offset)
+		}
+		out += chunk.out
+	}
+	if want := "first line\nthe ****** line\n"; out != want {
+		t.Errorf("unexpected streamed output. want=%q have=%q", want, out)
+	}
+	if len(s.chunks) < 2 {
+		t.Fatalf("expected output to be streamed in multiple chunks, have %d", len(s.chunks))
+	}
+	if last := s.chunks[len(s.chunks)-1]; last.exitCode == nil || *last.exitCode != 1 {
+		t.Errorf("expected last chunk to carry the exit code")
+	}
+	for _, chunk := range s.chunks[:len(s.chunks)-1] {
+		if chunk.exitCode != nil {
+			t.Errorf("unexpected exit code in intermediate chunk")
+		}
+	}
+
+	// The entry is still persisted.
+	if len(s.UpdateExecutionLogEntryFunc.History()) != 1 {
+		t.Fatalf("incorrect invokation count on UpdateExecutionLogEntry, want=%d have=%d", 1, len(s.UpdateExecutionLogEntryFunc.History()))
+	}
+}
+
+func TestLogger_StreamSplitSecret(t *testing.T) {
+	s := &streamingExecutionLogEntryStore{MockExecutionLogEntryStore: NewMockExecutionLogEntryStore()}
+
+	doneAdding := make(chan struct{})
+	s.AddExecutionLogEntryFunc.SetDefaultHook(func(_ context.Context, _ int, _ internalexecutor.ExecutionLogEntry) (int, error) {
+		doneAdding <- struct{}{}
+		return 7, nil
+	})
+
+	job := executor.Job{}
+	// The replacement is shorter than the secret, so the redacted output is
+	// shorter than the written output.
+	l := NewLogger(s, job, 1, map[string]string{"supersecret": "***"})
+
+	e := l.Log("the_key", []string{"cmd", "arg1"})
+
+	flushDone := make(chan error)
+	go func() {
+		flushDone <- l.Flush()
+	}()
+
+	// Wait for AddExecutionLogEntry to have been called.
+	<-doneAdding
+	// The secret is written across two ticks of the stream.
+	if _, err := e.Write([]byte("first line\nthe super")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * streamLogEntryInterval)
+	if _, err := e.Write([]byte("secret line\n")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * streamLogEntryInterval)
+	if _, err := e.Write([]byte("last line\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	e.Finalize(0)
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-flushDone; err != nil {
+		t.Fatal(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out string
+	for _, chunk := range s.chunks {
+		if strings.Contains(chunk.out, "super") || strings.Contains(chunk.out, "secret") {
+			t.Errorf("streamed chunk contains part of the secret: %q", chunk.out)
+		}
+		if chunk.offset != len(out) {
+			t.Fatalf("unexpected offset. want=%d have=%d", len(out), chunk.offset)
+		}
+		out += chunk.out
+	}
+	if want := "first line\nthe *** line\nlast line\n"; out != want {
+		t.Errorf("unexpected streamed output. want=%q have=%q", want, out)
+	}
+	if len(s.chunks) < 3 {
+		t.Fatalf("expected output to be streamed in multiple chunks, have %d", len(s.chunks))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/sourcegraph/log"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -51,6 +52,9 @@ type Options struct {
 	// FirecrackerOptions configures the behavior of Firecracker virtual machine creation.
 	FirecrackerOptions FirecrackerOptions
 
+	// KubernetesOptions configures the behavior of Kubernetes job creation.
+	KubernetesOptions KubernetesOptions
+
 	// ResourceOptions configures the resource limits of docker container and Firecracker
 	// virtual machines running on the executor.
 	ResourceOptions ResourceOptions
@@ -91,6 +95,25 @@ type FirecrackerOptions struct {
 	DockerRegistryMirrorURLs []string
 }
 
+type KubernetesOptions struct {
+	// Enabled determines if commands will be run in Kubernetes jobs.
+	Enabled bool
+
+	// Namespace is the Kubernetes namespace jobs are created in.
+	Namespace string
+
+	// PersistenceVolumeName is the name of the persistent volume claim shared by the
+	// executor and the jobs it creates. Workspaces must be created on this volume.
+	PersistenceVolumeName string
+
+	// ConfigPath is the path to a kubeconfig file used to create the Clientset. If
+	// empty, the in-cluster configuration is used.
+	ConfigPath string
+
+	// Clientset is the client used to talk to the Kubernetes API.
+	Clientset kubernetes.Interface
+}
+
 type ResourceOptions struct {
 	// NumCPUs is the number of virtual CPUs a container or VM can use.
 	NumCPUs int
@@ -119,6 +142,14 @@ type ResourceOptions struct {
 
 // NewRunner creates a new runner with the given options.
 func NewRunner(dir string, logger Logger, options Options, operations *Operations) Runner {
+	if options.KubernetesOptions.Enabled {
+		return &kubernetesRunner{
+			dir:       dir,
+			cmdLogger: logger,
+			options:   options,
+		}
+	}
+
 	if !options.FirecrackerOptions.Enabled {
 		return &dockerRunner{
 			dir:       dir,
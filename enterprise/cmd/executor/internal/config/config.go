@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"runtime"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/c2h5oh/datasize"
 	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/conf/confdefaults"
@@ -23,6 +25,9 @@ type Config struct {
 	FrontendURL                    string
 	FrontendAuthorizationToken     string
 	QueueName                      string
+	QueueNames                     []string
+	QueueWeights                   map[string]int
+	QueueMaxJobs                   map[string]int
 	QueuePollInterval              time.Duration
 	MaximumNumJobs                 int
 	FirecrackerImage               string
@@ -33,6 +38,10 @@ type Config struct {
 	KeepWorkspaces                 bool
 	DockerHostMountPath            string
 	UseFirecracker                 bool
+	UseKubernetes                  bool
+	KubernetesNamespace            string
+	KubernetesPersistenceVolume    string
+	KubernetesConfigPath           string
 	JobNumCPUs                     int
 	JobMemory                      string
 	FirecrackerDiskSpace           string
@@ -59,10 +68,19 @@ func (c *Config) Load() {
 		// In single-program deployments, we respect the in-memory executor password only.
 		c.FrontendAuthorizationToken = confdefaults.SingleProgramInMemoryExecutorPassword
 	}
-	c.QueueName = c.Get("EXECUTOR_QUEUE_NAME", "", "The name of the queue to listen to.")
+	c.QueueName = c.GetOptional("EXECUTOR_QUEUE_NAME", "The name of the queue to listen to. Either this or EXECUTOR_QUEUE_NAMES must be set.")
+	if queueNames := c.GetOptional("EXECUTOR_QUEUE_NAMES", "A comma-separated list of the names of the queues to listen to. Either this or EXECUTOR_QUEUE_NAME must be set."); queueNames != "" {
+		c.QueueNames = strings.Split(queueNames, ",")
+	}
+	c.QueueWeights = c.getQueueValues("EXECUTOR_QUEUE_WEIGHTS", "A comma-separated list of queue=weight pairs. When listening to multiple queues, queues get a share of the running jobs proportional to their weight. The default weight is 1.")
+	c.QueueMaxJobs = c.getQueueValues("EXECUTOR_QUEUE_MAXIMUM_NUM_JOBS", "A comma-separated list of queue=count pairs. When listening to multiple queues, limits the number of jobs from a queue that can be running at once.")
 	c.QueuePollInterval = c.GetInterval("EXECUTOR_QUEUE_POLL_INTERVAL", "1s", "Interval between dequeue requests.")
 	c.MaximumNumJobs = c.GetInt("EXECUTOR_MAXIMUM_NUM_JOBS", "1", "Number of virtual machines or containers that can be running at once.")
 	c.UseFirecracker = c.GetBool("EXECUTOR_USE_FIRECRACKER", strconv.FormatBool(runtime.GOOS == "linux"), "Whether to isolate commands in virtual machines. Requires ignite and firecracker. Linux hosts only.")
+	c.UseKubernetes = c.GetBool("EXECUTOR_USE_KUBERNETES", "false", "Whether to run commands in Kubernetes jobs. Cannot be combined with EXECUTOR_USE_FIRECRACKER.")
+	c.KubernetesNamespace = c.Get("EXECUTOR_KUBERNETES_NAMESPACE", "default", "The namespace to run Kubernetes jobs in.")
+	c.KubernetesPersistenceVolume = c.GetOptional("EXECUTOR_KUBERNETES_PERSISTENCE_VOLUME_NAME", "The name of the persistent volume claim shared by the executor and its Kubernetes jobs. Workspaces are created on this volume, so TMPDIR must point into its mount.")
+	c.KubernetesConfigPath = c.GetOptional("EXECUTOR_KUBERNETES_CONFIG_PATH", "The path to a kubeconfig file. If not set, the in-cluster configuration is used.")
 	c.FirecrackerImage = c.Get("EXECUTOR_FIRECRACKER_IMAGE", DefaultFirecrackerImage, "The base image to use for virtual machines.")
 	c.FirecrackerKernelImage = c.Get("EXECUTOR_FIRECRACKER_KERNEL_IMAGE", DefaultFirecrackerKernelImage, "The base image containing the kernel binary to use for virtual machines.")
 	c.FirecrackerSandboxImage = c.Get("EXECUTOR_FIRECRACKER_SANDBOX_IMAGE", DefaultFirecrackerSandboxImage, "The OCI image for the ignite VM sandbox.")
@@ -98,6 +116,34 @@ func (c *Config) Validate() error {
 		c.AddError(errors.New("EXECUTOR_QUEUE_NAME must be set to 'batches' or 'codeintel'"))
 	}
 
+	if c.QueueName == "" && len(c.QueueNames) == 0 {
+		c.AddError(errors.New("either EXECUTOR_QUEUE_NAME or EXECUTOR_QUEUE_NAMES must be set"))
+	}
+	if c.QueueName != "" && len(c.QueueNames) > 0 {
+		c.AddError(errors.New("EXECUTOR_QUEUE_NAME and EXECUTOR_QUEUE_NAMES cannot both be set"))
+	}
+	for _, queueName := range c.QueueNames {
+		if !isValidQueueName(queueName) {
+			c.AddError(errors.Newf("invalid queue name %q in EXECUTOR_QUEUE_NAMES, must be 'batches' or 'codeintel'", queueName))
+		}
+	}
+	for queueName, weight := range c.QueueWeights {
+		if !slices.Contains(c.QueueNames, queueName) {
+			c.AddError(errors.Newf("EXECUTOR_QUEUE_WEIGHTS contains queue %q which is not in EXECUTOR_QUEUE_NAMES", queueName))
+		}
+		if weight < 1 {
+			c.AddError(errors.Newf("EXECUTOR_QUEUE_WEIGHTS must be at least 1 for queue %q", queueName))
+		}
+	}
+	for queueName, maxJobs := range c.QueueMaxJobs {
+		if !slices.Contains(c.QueueNames, queueName) {
+			c.AddError(errors.Newf("EXECUTOR_QUEUE_MAXIMUM_NUM_JOBS contains queue %q which is not in EXECUTOR_QUEUE_NAMES", queueName))
+		}
+		if maxJobs < 1 {
+			c.AddError(errors.Newf("EXECUTOR_QUEUE_MAXIMUM_NUM_JOBS must be at least 1 for queue %q", queueName))
+		}
+	}
+
 	if c.dockerAuthConfigUnmarshalError != nil {
 		c.AddError(errors.Wrap(c.dockerAuthConfigUnmarshalError, "invalid EXECUTOR_DOCKER_AUTH_CONFIG, failed to parse"))
 	}
@@ -123,5 +169,44 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.UseKubernetes {
+		if c.UseFirecracker {
+			c.AddError(errors.New("EXECUTOR_USE_KUBERNETES and EXECUTOR_USE_FIRECRACKER cannot both be enabled"))
+		}
+		if c.KubernetesPersistenceVolume == "" {
+			c.AddError(errors.New("EXECUTOR_KUBERNETES_PERSISTENCE_VOLUME_NAME must be set when EXECUTOR_USE_KUBERNETES is enabled"))
+		}
+	}
+
 	return c.BaseConfig.Validate()
 }
+
+// getQueueValues returns the value with the given name interpreted as a
+// comma-separated list of queue=value pairs.
+func (c *Config) getQueueValues(name, description string) map[string]int {
+	rawValue := c.GetOptional(name, description)
+	if rawValue == "" {
+		return nil
+	}
+
+	values := map[string]int{}
+	for _, pair := range strings.Split(rawValue, ",") {
+		queueName, rawCount, ok := strings.Cut(pair, "=")
+		if !ok {
+			c.AddError(errors.Errorf("invalid value %q for %s: expected queue=value", pair, name))
+			continue
+		}
+		value, err := strconv.Atoi(rawCount)
+		if err != nil {
+			c.AddError(errors.Errorf("invalid int %q for queue %q in %s: %s", rawCount, queueName, name, err))
+			continue
+		}
+		values[queueName] = value
+	}
+
+	return values
+}
+
+func isValidQueueName(queueName string) bool {
+	return slices.Contains(executor.ValidQueueNames, queueName)
+}
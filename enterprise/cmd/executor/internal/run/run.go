@@ -48,7 +48,7 @@ func StandaloneRunRun(ctx context.Context, logger log.Logger, cfg *config.Config
 	// TODO: This is too similar to the RunValidate func. Make it share even more code.
 	if runVerifyChecks {
 		// Then, validate all tools that are required are installed.
-		if err := validateToolsRequired(cfg.UseFirecracker); err != nil {
+		if err := validateToolsRequired(cfg.UseFirecracker, cfg.UseKubernetes); err != nil {
 			return err
 		}
 
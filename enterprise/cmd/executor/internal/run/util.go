@@ -101,6 +101,7 @@ func apiWorkerOptions(c *config.Config, queueTelemetryOptions queue.TelemetryOpt
 		WorkerOptions:      workerOptions(c),
 		DockerOptions:      dockerOptions(c),
 		FirecrackerOptions: firecrackerOptions(c),
+		KubernetesOptions:  kubernetesOptions(c),
 		ResourceOptions:    resourceOptions(c),
 		GitServicePath:     "/.executors/git",
 		QueueOptions:       queueOptions(c, queueTelemetryOptions),
@@ -118,11 +119,11 @@ func apiWorkerOptions(c *config.Config, queueTelemetryOptions queue.TelemetryOpt
 
 func workerOptions(c *config.Config) workerutil.WorkerOptions {
 	return workerutil.WorkerOptions{
-		Name:                 fmt.Sprintf("executor_%s_worker", c.QueueName),
+		Name:                 fmt.Sprintf("executor_%s_worker", queueLabel(c)),
 		NumHandlers:          c.MaximumNumJobs,
 		Interval:             c.QueuePollInterval,
 		HeartbeatInterval:    5 * time.Second,
-		Metrics:              makeWorkerMetrics(c.QueueName),
+		Metrics:              makeWorkerMetrics(queueLabel(c)),
 		NumTotalJobs:         c.NumTotalJobs,
 		MaxActiveTime:        c.MaxActiveTime,
 		WorkerHostname:       c.WorkerHostname,
@@ -156,6 +157,15 @@ func firecrackerOptions(c *config.Config) command.FirecrackerOptions {
 	}
 }
 
+func kubernetesOptions(c *config.Config) command.KubernetesOptions {
+	return command.KubernetesOptions{
+		Enabled:               c.UseKubernetes,
+		Namespace:             c.KubernetesNamespace,
+		PersistenceVolumeName: c.KubernetesPersistenceVolume,
+		ConfigPath:            c.KubernetesConfigPath,
+	}
+}
+
 func resourceOptions(c *config.Config) command.ResourceOptions {
 	return command.ResourceOptions{
 		NumCPUs:             c.JobNumCPUs,
@@ -171,6 +181,9 @@ func queueOptions(c *config.Config, telemetryOptions queue.TelemetryOptions) que
 	return queue.Options{
 		ExecutorName:      c.WorkerHostname,
 		QueueName:         c.QueueName,
+		QueueNames:        c.QueueNames,
+		QueueWeights:      c.QueueWeights,
+		QueueMaxJobs:      c.QueueMaxJobs,
 		BaseClientOptions: baseClientOptions(c, "/.executors/queue"),
 		TelemetryOptions:  telemetryOptions,
 		ResourceOptions: queue.ResourceOptions{
@@ -201,6 +214,15 @@ func endpointOptions(c *config.Config, pathPrefix string) apiclient.EndpointOpti
 	}
 }
 
+// queueLabel returns the name of the queue the executor processes, or the names of
+// all queues joined with underscores when it processes multiple queues.
+func queueLabel(c *config.Config) string {
+	if len(c.QueueNames) > 0 {
+		return strings.Join(c.QueueNames, "_")
+	}
+	return c.QueueName
+}
+
 func makeWorkerMetrics(queueName string) workerutil.WorkerObservability {
 	observationCtx := observation.NewContext(log.Scoped("executor_processor", "executor worker processor"))
 
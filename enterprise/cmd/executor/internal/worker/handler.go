@@ -120,6 +120,7 @@ func (h *handler) Handle(ctx context.Context, logger log.Logger, job executor.Jo
 		ExecutorName:       name,
 		DockerOptions:      h.options.DockerOptions,
 		FirecrackerOptions: h.options.FirecrackerOptions,
+		KubernetesOptions:  h.options.KubernetesOptions,
 		ResourceOptions:    h.options.ResourceOptions,
 	}
 	// If the job has docker auth config set, prioritize that over the env var.
@@ -65,6 +65,9 @@ type Options struct {
 	// FirecrackerOptions configures the behavior of Firecracker virtual machine creation.
 	FirecrackerOptions command.FirecrackerOptions
 
+	// KubernetesOptions configures the behavior of Kubernetes job creation.
+	KubernetesOptions command.KubernetesOptions
+
 	// ResourceOptions configures the resource limits of docker container and Firecracker
 	// virtual machines running on the executor.
 	ResourceOptions command.ResourceOptions
@@ -97,6 +100,14 @@ func NewWorker(observationCtx *observation.Context, nameSet *janitor.NameSet, op
 		return nil, errors.Wrap(err, "building files store")
 	}
 
+	if options.KubernetesOptions.Enabled && options.KubernetesOptions.Clientset == nil {
+		clientset, err := command.NewKubernetesClientset(options.KubernetesOptions.ConfigPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "building Kubernetes client")
+		}
+		options.KubernetesOptions.Clientset = clientset
+	}
+
 	h := &handler{
 		nameSet:       nameSet,
 		logStore:      queueClient,
@@ -16,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/githuboauth"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/gitlaboauth"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/httpheader"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/ldap"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/openidconnect"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/saml"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/sourcegraphoperator"
@@ -32,6 +33,7 @@ func Init(logger log.Logger, db database.DB) {
 	sourcegraphoperator.Init()
 	saml.Init()
 	httpheader.Init()
+	ldap.Init()
 	githuboauth.Init(logger, db)
 	gitlaboauth.Init(logger, db)
 	bitbucketcloudoauth.Init(logger, db)
@@ -42,6 +44,7 @@ func Init(logger log.Logger, db database.DB) {
 		sourcegraphoperator.Middleware(db),
 		saml.Middleware(db),
 		httpheader.Middleware(db),
+		ldap.Middleware(db),
 		githuboauth.Middleware(db),
 		gitlaboauth.Middleware(db),
 		bitbucketcloudoauth.Middleware(db),
@@ -78,6 +81,8 @@ func Init(logger log.Logger, db database.DB) {
 				name = "Bitbucket Cloud OAuth"
 			case p.HttpHeader != nil:
 				name = "HTTP header"
+			case p.Ldap != nil:
+				name = "LDAP"
 			case p.Openidconnect != nil:
 				name = "OpenID Connect"
 			case p.Saml != nil:
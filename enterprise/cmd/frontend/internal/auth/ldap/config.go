@@ -0,0 +1,146 @@
+package ldap
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const pkgName = "ldap"
+
+// getProvider looks up the registered LDAP auth provider with the given ID.
+func getProvider(pcID string) *provider {
+	p, _ := providers.GetProviderByConfigID(providers.ConfigID{Type: providerType, ID: pcID}).(*provider)
+	return p
+}
+
+func Init() {
+	conf.ContributeValidator(validateConfig)
+
+	logger := log.Scoped(pkgName, "LDAP config watch")
+	go func() {
+		conf.Watch(func() {
+			ps := getProviders()
+			if len(ps) == 0 {
+				providers.Update(pkgName, nil)
+				return
+			}
+
+			if err := licensing.Check(licensing.FeatureSSO); err != nil {
+				logger.Error("Check license for SSO (LDAP)", log.Error(err))
+				providers.Update(pkgName, nil)
+				return
+			}
+			providers.Update(pkgName, ps)
+		})
+	}()
+}
+
+func getProviders() []providers.Provider {
+	var cfgs []*schema.LDAPAuthProvider
+	for _, p := range conf.Get().AuthProviders {
+		if p.Ldap == nil {
+			continue
+		}
+		cfgs = append(cfgs, p.Ldap)
+	}
+	multiple := len(cfgs) >= 2
+	ps := make([]providers.Provider, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		ps = append(ps, &provider{config: *withConfigDefaults(cfg), multiple: multiple})
+	}
+	return ps
+}
+
+func validateConfig(c conftypes.SiteConfigQuerier) (problems conf.Problems) {
+	seen := map[string]int{}
+	for i, p := range c.SiteConfig().AuthProviders {
+		if p.Ldap == nil {
+			continue
+		}
+
+		u, err := url.Parse(p.Ldap.Url)
+		if err != nil {
+			problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("LDAP auth provider at index %d has an invalid url: %s", i, err)))
+		} else if p.Ldap.StartTLS && u.Scheme != "ldap" {
+			problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("LDAP auth provider at index %d: startTLS can only be used with ldap:// URLs", i)))
+		}
+		if p.Ldap.UserFilter != "" && !strings.Contains(p.Ldap.UserFilter, usernamePlaceholder) {
+			problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("LDAP auth provider at index %d: userFilter must contain the %s placeholder", i, usernamePlaceholder)))
+		}
+		if p.Ldap.BindDN != "" && p.Ldap.BindPassword == "" {
+			problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("LDAP auth provider at index %d: bindPassword must be set when bindDN is set", i)))
+		}
+
+		// we can ignore errors: converting to JSON must work, as we parsed from JSON before
+		bytes, _ := json.Marshal(*p.Ldap)
+		key := string(bytes)
+		if j, ok := seen[key]; ok {
+			problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("LDAP auth provider at index %d is duplicate of index %d, ignoring", i, j)))
+		} else {
+			seen[key] = i
+		}
+	}
+	return problems
+}
+
+// usernamePlaceholder is replaced with the escaped username in the userFilter.
+const usernamePlaceholder = "{username}"
+
+func withConfigDefaults(pc *schema.LDAPAuthProvider) *schema.LDAPAuthProvider {
+	tmp := *pc
+	if tmp.UserFilter == "" {
+		tmp.UserFilter = "(uid=" + usernamePlaceholder + ")"
+	}
+	if tmp.GroupAttribute == "" {
+		tmp.GroupAttribute = "memberOf"
+	}
+
+	attrs := schema.LDAPAttributes{}
+	if pc.Attributes != nil {
+		attrs = *pc.Attributes
+	}
+	if attrs.Username == "" {
+		attrs.Username = "uid"
+	}
+	if attrs.Email == "" {
+		attrs.Email = "mail"
+	}
+	if attrs.DisplayName == "" {
+		attrs.DisplayName = "cn"
+	}
+	tmp.Attributes = &attrs
+	return &tmp
+}
+
+// providerConfigID produces a semi-stable identifier for an LDAP auth provider config object. It
+// is used to tell multiple LDAP auth providers apart on the sign-in page. Its value is never
+// persisted, and it must be deterministic.
+//
+// If there is only a single LDAP auth provider, it returns the empty string because that
+// satisfies the requirements above.
+func providerConfigID(pc *schema.LDAPAuthProvider, multiple bool) string {
+	if pc.ConfigID != "" {
+		return pc.ConfigID
+	}
+	if !multiple {
+		return ""
+	}
+	data, err := json.Marshal(pc)
+	if err != nil {
+		panic(err)
+	}
+	b := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(b[:16])
+}
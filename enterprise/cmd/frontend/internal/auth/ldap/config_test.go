@@ -0,0 +1,62 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestValidateCustom(t *testing.T) {
+	tests := map[string]struct {
+		input        conf.Unified
+		wantProblems conf.Problems
+	}{
+		"valid": {
+			input: conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldap://ldap.example.com", StartTLS: true, BaseDN: "dc=example,dc=com"}},
+				},
+			}},
+			wantProblems: nil,
+		},
+		"startTLS with ldaps": {
+			input: conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldaps://ldap.example.com", StartTLS: true, BaseDN: "dc=example,dc=com"}},
+				},
+			}},
+			wantProblems: conf.NewSiteProblems("startTLS can only be used"),
+		},
+		"filter without placeholder": {
+			input: conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldap://ldap.example.com", BaseDN: "dc=example,dc=com", UserFilter: "(uid=%s)"}},
+				},
+			}},
+			wantProblems: conf.NewSiteProblems("userFilter must contain"),
+		},
+		"bindDN without password": {
+			input: conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldap://ldap.example.com", BaseDN: "dc=example,dc=com", BindDN: "cn=sourcegraph,dc=example,dc=com"}},
+				},
+			}},
+			wantProblems: conf.NewSiteProblems("bindPassword must be set"),
+		},
+		"duplicate": {
+			input: conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldap://ldap.example.com", BaseDN: "dc=example,dc=com"}},
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldap://ldap.example.com", BaseDN: "dc=example,dc=com"}},
+				},
+			}},
+			wantProblems: conf.NewSiteProblems("LDAP auth provider at index 1 is duplicate of index 0"),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			conf.TestValidator(t, test.input, validateConfig, test.wantProblems)
+		})
+	}
+}
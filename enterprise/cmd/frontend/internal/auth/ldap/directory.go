@@ -0,0 +1,189 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// conn is the subset of *goldap.Conn used to authenticate users. It exists so that tests can
+// run against an in-memory directory instead of a real LDAP server.
+type conn interface {
+	StartTLS(*tls.Config) error
+	Bind(username, password string) error
+	Search(*goldap.SearchRequest) (*goldap.SearchResult, error)
+	Close()
+}
+
+// timeout bounds how long a sign-in waits for the LDAP server to accept a connection and to answer
+// the user search.
+const timeout = 10 * time.Second
+
+// dial connects to the LDAP server at the given URL. It is a variable so that tests can replace it.
+var dial = func(rawURL string, tlsConfig *tls.Config) (conn, error) {
+	return goldap.DialURL(rawURL, goldap.DialWithTLSConfig(tlsConfig), goldap.DialWithDialer(&net.Dialer{Timeout: timeout}))
+}
+
+// errInvalidCredentials is returned when the username is unknown or the password is wrong. The two
+// cases are deliberately indistinguishable to callers.
+var errInvalidCredentials = errors.New("invalid username or password")
+
+// errNotInAllowedGroups is returned when the user authenticated successfully but is not a member
+// of any of the provider's allowGroups.
+var errNotInAllowedGroups = errors.New("user is not a member of an allowed group")
+
+// userInfo describes a user that successfully authenticated against the directory.
+type userInfo struct {
+	// id identifies the directory entry and does not change when the user is renamed or moved. See
+	// immutableID.
+	id          string
+	dn          string
+	username    string
+	email       string
+	displayName string
+	// groups holds the normalized DNs (see normalizeDN) of the groups the user belongs to.
+	groups map[string]bool
+}
+
+// authenticate verifies the username and password against the directory described by pc, which
+// must have had withConfigDefaults applied. It searches for the user with the service account
+// (or anonymously), then binds as the user to check the password.
+func authenticate(pc *schema.LDAPAuthProvider, username, password string) (*userInfo, error) {
+	// 🚨 SECURITY: An empty password results in an unauthenticated bind, which most LDAP servers
+	// accept for any DN. Never treat it as a successful sign-in.
+	if username == "" || password == "" {
+		return nil, errInvalidCredentials
+	}
+
+	u, err := url.Parse(pc.Url)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing LDAP URL")
+	}
+	tlsConfig := &tls.Config{
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: pc.InsecureSkipVerify,
+	}
+
+	c, err := dial(pc.Url, tlsConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "connecting to LDAP server")
+	}
+	defer c.Close()
+
+	if pc.StartTLS && u.Scheme == "ldap" {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return nil, errors.Wrap(err, "StartTLS")
+		}
+	}
+
+	if pc.BindDN != "" {
+		if err := c.Bind(pc.BindDN, pc.BindPassword); err != nil {
+			return nil, errors.Wrap(err, "binding as service account")
+		}
+	}
+
+	attrs := pc.Attributes
+	res, err := c.Search(goldap.NewSearchRequest(
+		pc.BaseDN,
+		goldap.ScopeWholeSubtree,
+		goldap.NeverDerefAliases,
+		2, // we only need to know whether there is more than one match
+		int(timeout.Seconds()),
+		false,
+		strings.ReplaceAll(pc.UserFilter, usernamePlaceholder, goldap.EscapeFilter(username)),
+		[]string{objectGUIDAttribute, entryUUIDAttribute, attrs.Username, attrs.Email, attrs.DisplayName, pc.GroupAttribute},
+		nil,
+	))
+	if err != nil && !goldap.IsErrorWithCode(err, goldap.LDAPResultSizeLimitExceeded) {
+		return nil, errors.Wrap(err, "searching for user")
+	}
+	if res == nil || len(res.Entries) != 1 {
+		// Either no such user, or the filter is ambiguous. Refuse rather than guess.
+		return nil, errInvalidCredentials
+	}
+	entry := res.Entries[0]
+
+	if err := c.Bind(entry.DN, password); err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+			return nil, errInvalidCredentials
+		}
+		return nil, errors.Wrap(err, "binding as user")
+	}
+
+	info := &userInfo{
+		id:          immutableID(entry),
+		dn:          entry.DN,
+		username:    entry.GetEqualFoldAttributeValue(attrs.Username),
+		email:       entry.GetEqualFoldAttributeValue(attrs.Email),
+		displayName: entry.GetEqualFoldAttributeValue(attrs.DisplayName),
+		groups:      make(map[string]bool),
+	}
+	if info.username == "" {
+		info.username = username
+	}
+	for _, g := range entry.GetEqualFoldAttributeValues(pc.GroupAttribute) {
+		info.groups[normalizeDN(g)] = true
+	}
+
+	if len(pc.AllowGroups) > 0 && !inAnyGroup(info.groups, pc.AllowGroups) {
+		return nil, errNotInAllowedGroups
+	}
+	return info, nil
+}
+
+const (
+	// objectGUIDAttribute is the binary, immutable identifier of Active Directory entries.
+	objectGUIDAttribute = "objectGUID"
+	// entryUUIDAttribute is the operational attribute of RFC 4530 that directories such as
+	// OpenLDAP use to identify entries.
+	entryUUIDAttribute = "entryUUID"
+)
+
+// immutableID returns an identifier for entry that survives changes to the username attribute:
+// its objectGUID or entryUUID, falling back to the normalized DN for directories that support
+// neither. The identifier is prefixed with its kind so that values of different kinds never
+// collide.
+func immutableID(entry *goldap.Entry) string {
+	if guid := entry.GetEqualFoldRawAttributeValue(objectGUIDAttribute); len(guid) > 0 {
+		return objectGUIDAttribute + ":" + hex.EncodeToString(guid)
+	}
+	if uuid := entry.GetEqualFoldAttributeValue(entryUUIDAttribute); uuid != "" {
+		return entryUUIDAttribute + ":" + strings.ToLower(uuid)
+	}
+	return "dn:" + normalizeDN(entry.DN)
+}
+
+func inAnyGroup(groups map[string]bool, dns []string) bool {
+	for _, dn := range dns {
+		if groups[normalizeDN(dn)] {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDN returns a canonical form of dn for case-insensitive comparison. Malformed DNs are
+// compared as lowercased strings.
+func normalizeDN(dn string) string {
+	parsed, err := goldap.ParseDN(dn)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(dn))
+	}
+	rdns := make([]string, 0, len(parsed.RDNs))
+	for _, rdn := range parsed.RDNs {
+		parts := make([]string, 0, len(rdn.Attributes))
+		for _, a := range rdn.Attributes {
+			parts = append(parts, strings.ToLower(a.Type)+"="+strings.ToLower(a.Value))
+		}
+		rdns = append(rdns, strings.Join(parts, "+"))
+	}
+	return strings.Join(rdns, ",")
+}
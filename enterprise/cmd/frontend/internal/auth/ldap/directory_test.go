@@ -0,0 +1,284 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"strings"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// fakeDirectory is an in-memory stand-in for an LDAP server. It supports simple binds and
+// subtree searches with AND, OR, NOT, equality and presence filters, which is all that
+// authenticate needs.
+type fakeDirectory struct {
+	entries   []*goldap.Entry
+	passwords map[string]string // by DN
+
+	// Recorded by the fake connection.
+	startTLS bool
+	binds    []string
+	closed   bool
+}
+
+func (d *fakeDirectory) dial(t *testing.T) {
+	t.Helper()
+	old := dial
+	dial = func(string, *tls.Config) (conn, error) { return d, nil }
+	t.Cleanup(func() { dial = old })
+}
+
+func (d *fakeDirectory) StartTLS(*tls.Config) error {
+	d.startTLS = true
+	return nil
+}
+
+func (d *fakeDirectory) Bind(dn, password string) error {
+	d.binds = append(d.binds, dn)
+	if want, ok := d.passwords[dn]; !ok || want != password {
+		return goldap.NewError(goldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+	}
+	return nil
+}
+
+func (d *fakeDirectory) Search(req *goldap.SearchRequest) (*goldap.SearchResult, error) {
+	filter, err := goldap.CompileFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	res := &goldap.SearchResult{}
+	for _, e := range d.entries {
+		if !strings.HasSuffix(strings.ToLower(e.DN), strings.ToLower(req.BaseDN)) || !matchFilter(filter, e) {
+			continue
+		}
+		if req.SizeLimit > 0 && len(res.Entries) == req.SizeLimit {
+			return res, goldap.NewError(goldap.LDAPResultSizeLimitExceeded, errors.New("size limit exceeded"))
+		}
+		res.Entries = append(res.Entries, e)
+	}
+	return res, nil
+}
+
+func (d *fakeDirectory) Close() { d.closed = true }
+
+func matchFilter(f *ber.Packet, e *goldap.Entry) bool {
+	switch f.Tag {
+	case goldap.FilterAnd:
+		for _, c := range f.Children {
+			if !matchFilter(c, e) {
+				return false
+			}
+		}
+		return true
+	case goldap.FilterOr:
+		for _, c := range f.Children {
+			if matchFilter(c, e) {
+				return true
+			}
+		}
+		return false
+	case goldap.FilterNot:
+		return !matchFilter(f.Children[0], e)
+	case goldap.FilterEqualityMatch:
+		attr, value := f.Children[0].Data.String(), f.Children[1].Data.String()
+		for _, v := range e.GetEqualFoldAttributeValues(attr) {
+			if strings.EqualFold(v, value) {
+				return true
+			}
+		}
+		return false
+	case goldap.FilterPresent:
+		return len(e.GetEqualFoldAttributeValues(f.Data.String())) > 0
+	}
+	return false
+}
+
+func newTestDirectory() *fakeDirectory {
+	return &fakeDirectory{
+		entries: []*goldap.Entry{
+			goldap.NewEntry("uid=alice,ou=people,dc=example,dc=com", map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"alice"},
+				"mail":        {"alice@example.com"},
+				"cn":          {"Alice Liddell"},
+				"memberOf":    {"CN=Engineering,OU=Groups,DC=example,DC=com", "cn=admins,ou=groups,dc=example,dc=com"},
+			}),
+			goldap.NewEntry("uid=bob,ou=people,dc=example,dc=com", map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"bob"},
+				"mail":        {"bob@example.com"},
+				"cn":          {"Bob"},
+			}),
+		},
+		passwords: map[string]string{
+			"cn=sourcegraph,dc=example,dc=com":      "service-secret",
+			"uid=alice,ou=people,dc=example,dc=com": "alice-secret",
+			"uid=bob,ou=people,dc=example,dc=com":   "bob-secret",
+		},
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	config := func(modify func(*schema.LDAPAuthProvider)) *schema.LDAPAuthProvider {
+		pc := &schema.LDAPAuthProvider{
+			Type:         "ldap",
+			Url:          "ldap://ldap.example.com",
+			BindDN:       "cn=sourcegraph,dc=example,dc=com",
+			BindPassword: "service-secret",
+			BaseDN:       "ou=people,dc=example,dc=com",
+		}
+		if modify != nil {
+			modify(pc)
+		}
+		return withConfigDefaults(pc)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		d := newTestDirectory()
+		d.dial(t)
+
+		info, err := authenticate(config(nil), "alice", "alice-secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &userInfo{
+			id:          "dn:uid=alice,ou=people,dc=example,dc=com",
+			dn:          "uid=alice,ou=people,dc=example,dc=com",
+			username:    "alice",
+			email:       "alice@example.com",
+			displayName: "Alice Liddell",
+			groups: map[string]bool{
+				"cn=engineering,ou=groups,dc=example,dc=com": true,
+				"cn=admins,ou=groups,dc=example,dc=com":      true,
+			},
+		}
+		if diff := cmp.Diff(want, info, cmp.AllowUnexported(userInfo{})); diff != "" {
+			t.Fatalf("unexpected user info (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{"cn=sourcegraph,dc=example,dc=com", "uid=alice,ou=people,dc=example,dc=com"}, d.binds); diff != "" {
+			t.Fatalf("unexpected binds (-want +got):\n%s", diff)
+		}
+		if d.startTLS {
+			t.Fatal("StartTLS used without being configured")
+		}
+		if !d.closed {
+			t.Fatal("connection not closed")
+		}
+	})
+
+	t.Run("startTLS", func(t *testing.T) {
+		d := newTestDirectory()
+		d.dial(t)
+
+		if _, err := authenticate(config(func(pc *schema.LDAPAuthProvider) { pc.StartTLS = true }), "alice", "alice-secret"); err != nil {
+			t.Fatal(err)
+		}
+		if !d.startTLS {
+			t.Fatal("StartTLS not used")
+		}
+	})
+
+	t.Run("custom attributes", func(t *testing.T) {
+		d := newTestDirectory()
+		d.dial(t)
+
+		pc := config(func(pc *schema.LDAPAuthProvider) {
+			pc.UserFilter = "(&(objectClass=person)(mail={username}))"
+			pc.Attributes = &schema.LDAPAttributes{Username: "mail", DisplayName: "uid"}
+		})
+		info, err := authenticate(pc, "bob@example.com", "bob-secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.username != "bob@example.com" || info.displayName != "bob" || info.email != "bob@example.com" {
+			t.Fatalf("unexpected user info: %+v", info)
+		}
+	})
+
+	for _, tc := range []struct {
+		name     string
+		pc       *schema.LDAPAuthProvider
+		username string
+		password string
+		wantErr  error
+	}{
+		{name: "wrong password", pc: config(nil), username: "alice", password: "bob-secret", wantErr: errInvalidCredentials},
+		{name: "unknown user", pc: config(nil), username: "carol", password: "alice-secret", wantErr: errInvalidCredentials},
+		{name: "empty password", pc: config(nil), username: "alice", password: "", wantErr: errInvalidCredentials},
+		// The username must be escaped, otherwise "*" would match every user.
+		{name: "filter injection", pc: config(nil), username: "*", password: "alice-secret", wantErr: errInvalidCredentials},
+		{name: "ambiguous filter", pc: config(func(pc *schema.LDAPAuthProvider) { pc.UserFilter = "(|(uid={username})(objectClass=person))" }), username: "alice", password: "alice-secret", wantErr: errInvalidCredentials},
+		{
+			name: "not in allowed group",
+			pc: config(func(pc *schema.LDAPAuthProvider) {
+				pc.AllowGroups = []string{"cn=engineering,ou=groups,dc=example,dc=com"}
+			}),
+			username: "bob",
+			password: "bob-secret",
+			wantErr:  errNotInAllowedGroups,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			newTestDirectory().dial(t)
+
+			_, err := authenticate(tc.pc, tc.username, tc.password)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+
+	t.Run("in allowed group", func(t *testing.T) {
+		newTestDirectory().dial(t)
+
+		pc := config(func(pc *schema.LDAPAuthProvider) {
+			pc.AllowGroups = []string{"cn=engineering, ou=groups, dc=example, dc=com"}
+		})
+		if _, err := authenticate(pc, "alice", "alice-secret"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("wrong service account password", func(t *testing.T) {
+		newTestDirectory().dial(t)
+
+		pc := config(func(pc *schema.LDAPAuthProvider) { pc.BindPassword = "nope" })
+		_, err := authenticate(pc, "alice", "alice-secret")
+		if err == nil || errors.Is(err, errInvalidCredentials) {
+			t.Fatalf("want service account bind error, got %v", err)
+		}
+	})
+}
+
+func TestImmutableID(t *testing.T) {
+	guid := &goldap.Entry{
+		DN: "CN=Alice,OU=People,DC=example,DC=com",
+		Attributes: []*goldap.EntryAttribute{
+			{Name: "objectGUID", ByteValues: [][]byte{{0x0f, 0xa0, 0x01}}},
+			{Name: "entryUUID", Values: []string{"ignored"}, ByteValues: [][]byte{[]byte("ignored")}},
+		},
+	}
+	for _, tc := range []struct {
+		entry *goldap.Entry
+		want  string
+	}{
+		{entry: guid, want: "objectGUID:0fa001"},
+		{
+			entry: goldap.NewEntry("uid=alice,ou=people,dc=example,dc=com", map[string][]string{"entryUUID": {"5F3B6E44-0D7A-4A39-9E47-1B8E5E0C6C1A"}}),
+			want:  "entryUUID:5f3b6e44-0d7a-4a39-9e47-1b8e5e0c6c1a",
+		},
+		{
+			entry: goldap.NewEntry("UID=Alice, OU=People, DC=example, DC=com", map[string][]string{"uid": {"alice"}}),
+			want:  "dn:uid=alice,ou=people,dc=example,dc=com",
+		},
+	} {
+		if got := immutableID(tc.entry); got != tc.want {
+			t.Errorf("immutableID(%q) = %q, want %q", tc.entry.DN, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package ldap
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// lockout throttles sign-in attempts the same way the builtin password provider does: after
+// auth.lockout's failedAttemptThreshold consecutive failures, further attempts are refused for the
+// lockoutPeriod without contacting the directory.
+//
+// Unlike the builtin provider, attempts are keyed by the provider and the username entered on the
+// sign-in page rather than by Sourcegraph user ID, because the Sourcegraph user is only known once
+// the directory has accepted the password.
+type lockout interface {
+	isLockedOut(key string) bool
+	increaseFailedAttempt(key string)
+	reset(key string)
+}
+
+// getLockout returns the lockout for the current auth.lockout site configuration. It is a variable
+// so that tests can replace it.
+var getLockout = func() lockout {
+	return newRedisLockout(conf.AuthLockout())
+}
+
+type redisLockout struct {
+	failedThreshold int
+	lockouts        *rcache.Cache
+	failedAttempts  *rcache.Cache
+}
+
+func newRedisLockout(opts *schema.AuthLockout) *redisLockout {
+	return &redisLockout{
+		failedThreshold: opts.FailedAttemptThreshold,
+		lockouts:        rcache.NewWithTTL("ldap_account_lockout", opts.LockoutPeriod),
+		failedAttempts:  rcache.NewWithTTL("ldap_account_failed_attempts", opts.ConsecutivePeriod),
+	}
+}
+
+func (l *redisLockout) isLockedOut(key string) bool {
+	_, locked := l.lockouts.Get(key)
+	return locked
+}
+
+func (l *redisLockout) increaseFailedAttempt(key string) {
+	l.failedAttempts.Increase(key)
+
+	// Get right after Increase should make the key always exist
+	v, _ := l.failedAttempts.Get(key)
+	if count, _ := strconv.Atoi(string(v)); count >= l.failedThreshold {
+		l.lockouts.Set(key, []byte("too many failed attempts"))
+	}
+}
+
+func (l *redisLockout) reset(key string) {
+	l.lockouts.Delete(key)
+	l.failedAttempts.Delete(key)
+}
+
+// lockoutKey returns the key under which failed sign-in attempts for username are counted. Directory
+// lookups are case-insensitive, so the username is too.
+func lockoutKey(p *provider, username string) string {
+	return p.ConfigID().ID + ":" + strings.ToLower(strings.TrimSpace(username))
+}
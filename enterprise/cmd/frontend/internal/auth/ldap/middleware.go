@@ -0,0 +1,124 @@
+// Package ldap implements authentication against an LDAP directory.
+package ldap
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/session"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// All LDAP endpoints are under this path prefix.
+const authPrefix = auth.AuthURLPrefix + "/ldap"
+
+// Middleware is middleware for LDAP authentication, adding the sign-in endpoint under the auth
+// path prefix. Unlike SSO providers there is no redirect flow: the sign-in page posts the
+// username and password to the endpoint directly.
+//
+// 🚨 SECURITY
+func Middleware(db database.DB) *auth.Middleware {
+	return &auth.Middleware{
+		API: func(next http.Handler) http.Handler { return next },
+		App: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == authPrefix+"/login" {
+					loginHandler(db)(w, r)
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		},
+	}
+}
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func loginHandler(db database.DB) http.HandlerFunc {
+	logger := log.Scoped("ldap.login", "LDAP sign-in handler")
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "", http.StatusMethodNotAllowed)
+			return
+		}
+		// 🚨 SECURITY: Requiring a JSON body means browsers will only send this request from
+		// other origins after a CORS preflight, which protects against login CSRF.
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+			http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+			return
+		}
+
+		p := getProvider(r.URL.Query().Get("pc"))
+		if p == nil {
+			logger.Error("no LDAP auth provider found", log.String("id", r.URL.Query().Get("pc")))
+			http.Error(w, "Misconfigured LDAP auth provider.", http.StatusInternalServerError)
+			return
+		}
+
+		var req loginRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Could not decode request body.", http.StatusBadRequest)
+			return
+		}
+
+		// 🚨 SECURITY: Refuse to even contact the directory once too many attempts have failed, so
+		// that passwords cannot be brute-forced through Sourcegraph.
+		lockout, key := getLockout(), lockoutKey(p, req.Username)
+		if lockout.isLockedOut(key) {
+			http.Error(w, "Account has been locked out due to too many failed sign-in attempts. Try again later.", http.StatusUnprocessableEntity)
+			return
+		}
+
+		info, err := authenticate(&p.config, req.Username, req.Password)
+		if err != nil {
+			switch {
+			case errors.Is(err, errInvalidCredentials):
+				lockout.increaseFailedAttempt(key)
+				http.Error(w, "Authentication failed.", http.StatusUnauthorized)
+			case errors.Is(err, errNotInAllowedGroups):
+				logger.Warn("LDAP-authenticated user is not a member of an allowed group", log.String("username", req.Username), log.Strings("allowGroups", p.config.AllowGroups))
+				http.Error(w, "Error authorizing LDAP-authenticated user. The user does not belong to one of the configured groups.", http.StatusForbidden)
+			default:
+				logger.Error("error authenticating against LDAP directory", log.Error(err))
+				http.Error(w, "Error contacting the LDAP server. Ask a site admin to check the server \"frontend\" logs.", http.StatusInternalServerError)
+			}
+			return
+		}
+		lockout.reset(key)
+
+		actor, safeErrMsg, err := getOrCreateUser(r.Context(), db, p, info)
+		if err != nil {
+			logger.Error("error looking up LDAP-authenticated user", log.Error(err), log.String("userErr", safeErrMsg))
+			http.Error(w, safeErrMsg, http.StatusInternalServerError)
+			return
+		}
+
+		if err := syncGroupMemberships(r.Context(), logger, db, actor.UID, &p.config, info.groups); err != nil {
+			logger.Error("error syncing LDAP group memberships", log.Int32("userID", actor.UID), log.Error(err))
+			http.Error(w, "Error syncing organization memberships and roles from LDAP groups. Try signing in again.", http.StatusInternalServerError)
+			return
+		}
+
+		user, err := db.Users().GetByID(r.Context(), actor.UID)
+		if err != nil {
+			logger.Error("error retrieving LDAP-authenticated user from database", log.Error(err))
+			http.Error(w, "Failed to retrieve user.", http.StatusInternalServerError)
+			return
+		}
+
+		if err := session.SetActor(w, r, actor, 0, user.CreatedAt); err != nil {
+			logger.Error("error setting LDAP-authenticated actor in session", log.Error(err))
+			http.Error(w, "Error starting LDAP-authenticated session. Try signing in again.", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
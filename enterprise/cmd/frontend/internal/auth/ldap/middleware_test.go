@@ -0,0 +1,68 @@
+package ldap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// memoryLockout is an in-memory lockout with a threshold of 2 failed attempts.
+type memoryLockout struct {
+	failed map[string]int
+}
+
+func (l *memoryLockout) isLockedOut(key string) bool      { return l.failed[key] >= 2 }
+func (l *memoryLockout) increaseFailedAttempt(key string) { l.failed[key]++ }
+func (l *memoryLockout) reset(key string)                 { delete(l.failed, key) }
+
+func TestLoginHandlerLockout(t *testing.T) {
+	providers.MockProviders = []providers.Provider{&provider{config: *withConfigDefaults(&schema.LDAPAuthProvider{
+		Type:         "ldap",
+		Url:          "ldap://ldap.example.com",
+		BindDN:       "cn=sourcegraph,dc=example,dc=com",
+		BindPassword: "service-secret",
+		BaseDN:       "ou=people,dc=example,dc=com",
+	})}}
+	t.Cleanup(func() { providers.MockProviders = nil })
+
+	l := &memoryLockout{failed: map[string]int{}}
+	oldGetLockout := getLockout
+	getLockout = func() lockout { return l }
+	t.Cleanup(func() { getLockout = oldGetLockout })
+
+	d := newTestDirectory()
+	d.dial(t)
+
+	login := func(username, password string) int {
+		req := httptest.NewRequest(http.MethodPost, authPrefix+"/login", strings.NewReader(`{"username":"`+username+`","password":"`+password+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		loginHandler(nil)(rec, req)
+		return rec.Code
+	}
+
+	for i := 0; i < 2; i++ {
+		if code := login("alice", "wrong"); code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: got status %d, want %d", i, code, http.StatusUnauthorized)
+		}
+	}
+
+	// The correct password must not help once the account is locked, and the directory must not
+	// be contacted at all. Usernames are matched case-insensitively.
+	binds := len(d.binds)
+	if code := login("ALICE", "alice-secret"); code != http.StatusUnprocessableEntity {
+		t.Fatalf("got status %d, want %d", code, http.StatusUnprocessableEntity)
+	}
+	if len(d.binds) != binds {
+		t.Fatal("directory contacted while locked out")
+	}
+
+	// Other users are not affected.
+	if code := login("bob", "wrong"); code != http.StatusUnauthorized {
+		t.Fatalf("got status %d, want %d", code, http.StatusUnauthorized)
+	}
+}
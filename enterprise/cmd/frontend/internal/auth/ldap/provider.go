@@ -0,0 +1,104 @@
+package ldap
+
+import (
+	"context"
+	"net/url"
+	"path"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const providerType = "ldap"
+
+type provider struct {
+	config   schema.LDAPAuthProvider
+	multiple bool // whether there are multiple LDAP auth providers
+}
+
+// ConfigID implements providers.Provider.
+func (p *provider) ConfigID() providers.ConfigID {
+	return providers.ConfigID{
+		Type: providerType,
+		ID:   providerConfigID(&p.config, p.multiple),
+	}
+}
+
+// Config implements providers.Provider.
+func (p *provider) Config() schema.AuthProviders {
+	return schema.AuthProviders{Ldap: &p.config}
+}
+
+// Refresh implements providers.Provider. LDAP providers have no remote metadata to refresh; the
+// directory is contacted on every sign-in.
+func (p *provider) Refresh(context.Context) error { return nil }
+
+// CachedInfo implements providers.Provider.
+func (p *provider) CachedInfo() *providers.Info {
+	info := providers.Info{
+		ServiceID:   p.config.Url,
+		ClientID:    p.config.BaseDN,
+		DisplayName: p.config.DisplayName,
+		AuthenticationURL: (&url.URL{
+			Path:     path.Join(auth.AuthURLPrefix, "ldap", "login"),
+			RawQuery: providerIDQuery(&p.config, p.multiple).Encode(),
+		}).String(),
+	}
+	if info.DisplayName == "" {
+		info.DisplayName = "LDAP"
+		if u, err := url.Parse(p.config.Url); err == nil && u.Hostname() != "" {
+			info.DisplayName = "LDAP (" + u.Hostname() + ")"
+		}
+	}
+	return &info
+}
+
+// ExternalAccountInfo implements providers.Provider.
+func (p *provider) ExternalAccountInfo(ctx context.Context, account extsvc.Account) (*extsvc.PublicAccountData, error) {
+	data, err := GetExternalAccountData(ctx, &account.AccountData)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return &extsvc.PublicAccountData{DisplayName: &account.AccountID}, nil
+	}
+
+	displayName := data.DisplayName
+	if displayName == "" {
+		displayName = data.Username
+	}
+	return &extsvc.PublicAccountData{
+		DisplayName: &displayName,
+		Login:       &data.Username,
+	}, nil
+}
+
+func providerIDQuery(pc *schema.LDAPAuthProvider, multiple bool) url.Values {
+	if multiple {
+		return url.Values{"pc": []string{providerConfigID(pc, multiple)}}
+	}
+	return url.Values{}
+}
+
+// AccountData is the data stored for an LDAP external account.
+type AccountData struct {
+	DN          string   `json:"dn"`
+	Username    string   `json:"username"`
+	Email       string   `json:"email,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Groups      []string `json:"groups,omitempty"`
+}
+
+// GetExternalAccountData returns the deserialized JSON blob from user external accounts table.
+func GetExternalAccountData(ctx context.Context, data *extsvc.AccountData) (val *AccountData, err error) {
+	if data.Data != nil {
+		val, err = encryption.DecryptJSON[AccountData](ctx, data.Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return val, nil
+}
@@ -0,0 +1,239 @@
+package ldap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// getOrCreateUser gets or creates a user account for the authenticated LDAP user. It returns the
+// authenticated actor if successful; otherwise it returns a friendly error message (safeErrMsg)
+// that is safe to display to users, and a non-nil err with lower-level error details.
+func getOrCreateUser(ctx context.Context, db database.DB, p *provider, info *userInfo) (_ *actor.Actor, safeErrMsg string, err error) {
+	groups := make([]string, 0, len(info.groups))
+	for g := range info.groups {
+		groups = append(groups, g)
+	}
+	sort.Strings(groups)
+	serializedData, err := json.Marshal(AccountData{
+		DN:          info.dn,
+		Username:    info.username,
+		Email:       info.email,
+		DisplayName: info.displayName,
+		Groups:      groups,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	username, err := auth.NormalizeUsername(info.username)
+	if err != nil {
+		return nil, fmt.Sprintf("Error normalizing the username %q. See https://docs.sourcegraph.com/admin/auth/#username-normalization.", info.username), err
+	}
+
+	pi := p.CachedInfo()
+	allowSignup := p.config.AllowSignup == nil || *p.config.AllowSignup
+	userID, safeErrMsg, err := auth.GetAndSaveUser(ctx, db, auth.GetAndSaveUserOp{
+		UserProps: database.NewUser{
+			Username:        username,
+			Email:           info.email,
+			EmailIsVerified: info.email != "", // emails managed by the directory are assumed to be verified
+			DisplayName:     info.displayName,
+		},
+		ExternalAccount: extsvc.AccountSpec{
+			ServiceType: providerType,
+			ServiceID:   pi.ServiceID,
+			ClientID:    pi.ClientID,
+			// 🚨 SECURITY: Key the account on the entry's immutable identifier, not on the
+			// username, which directory admins can change and reassign to a different person.
+			AccountID: info.id,
+		},
+		ExternalAccountData: extsvc.AccountData{Data: extsvc.NewUnencryptedData(serializedData)},
+		CreateIfNotExist:    allowSignup,
+	})
+	if err != nil {
+		return nil, safeErrMsg, err
+	}
+	return actor.FromUser(userID), "", nil
+}
+
+// membershipChanges describes how a user's organization memberships and roles must change to
+// match their LDAP groups.
+type membershipChanges struct {
+	addOrgs, removeOrgs   []string
+	addRoles, removeRoles []string
+}
+
+// resolveGroupMappings computes the organizations and roles the user is entitled to through
+// their groups. Organizations and roles that appear in a mapping the user does not match are
+// removed, unless another matching mapping grants them. Organizations and roles that do not
+// appear in any mapping are left alone.
+func resolveGroupMappings(mappings []*schema.LDAPGroupMapping, groups map[string]bool) membershipChanges {
+	managedOrgs, entitledOrgs := map[string]bool{}, map[string]bool{}
+	managedRoles, entitledRoles := map[string]bool{}, map[string]bool{}
+	for _, m := range mappings {
+		member := groups[normalizeDN(m.Group)]
+		for _, o := range m.Orgs {
+			managedOrgs[o] = true
+			if member {
+				entitledOrgs[o] = true
+			}
+		}
+		for _, r := range m.Roles {
+			managedRoles[r] = true
+			if member {
+				entitledRoles[r] = true
+			}
+		}
+	}
+
+	split := func(managed, entitled map[string]bool) (add, remove []string) {
+		for name := range managed {
+			if entitled[name] {
+				add = append(add, name)
+			} else {
+				remove = append(remove, name)
+			}
+		}
+		sort.Strings(add)
+		sort.Strings(remove)
+		return add, remove
+	}
+
+	var c membershipChanges
+	c.addOrgs, c.removeOrgs = split(managedOrgs, entitledOrgs)
+	c.addRoles, c.removeRoles = split(managedRoles, entitledRoles)
+	return c
+}
+
+// syncGroupMemberships applies the provider's groupMappings to the user, adding them to the
+// organizations and roles of their groups and removing them from the mapped ones they no longer
+// qualify for. Organizations and roles that don't exist, as well as system roles, are skipped with
+// a warning.
+func syncGroupMemberships(ctx context.Context, logger log.Logger, db database.DB, userID int32, pc *schema.LDAPAuthProvider, groups map[string]bool) error {
+	if len(pc.GroupMappings) == 0 {
+		return nil
+	}
+	changes := resolveGroupMappings(pc.GroupMappings, groups)
+
+	return db.WithTransact(ctx, func(tx database.DB) error {
+		if err := syncOrgs(ctx, logger, tx, userID, changes.addOrgs, changes.removeOrgs); err != nil {
+			return errors.Wrap(err, "syncing organization memberships")
+		}
+		if err := syncRoles(ctx, logger, tx, userID, changes.addRoles, changes.removeRoles); err != nil {
+			return errors.Wrap(err, "syncing roles")
+		}
+		return nil
+	})
+}
+
+func syncOrgs(ctx context.Context, logger log.Logger, db database.DB, userID int32, add, remove []string) error {
+	memberships, err := db.OrgMembers().GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	current := make(map[int32]bool, len(memberships))
+	for _, m := range memberships {
+		current[m.OrgID] = true
+	}
+
+	lookup := func(name string) (int32, bool, error) {
+		org, err := db.Orgs().GetByName(ctx, name)
+		if errcode.IsNotFound(err) {
+			logger.Warn("organization in LDAP group mapping does not exist", log.String("org", name))
+			return 0, false, nil
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		return org.ID, true, nil
+	}
+
+	for _, name := range add {
+		orgID, ok, err := lookup(name)
+		if err != nil {
+			return err
+		}
+		if ok && !current[orgID] {
+			if _, err := db.OrgMembers().Create(ctx, orgID, userID); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range remove {
+		orgID, ok, err := lookup(name)
+		if err != nil {
+			return err
+		}
+		if ok && current[orgID] {
+			if err := db.OrgMembers().Remove(ctx, orgID, userID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func syncRoles(ctx context.Context, logger log.Logger, db database.DB, userID int32, add, remove []string) error {
+	userRoles, err := db.UserRoles().GetByUserID(ctx, database.GetUserRoleOpts{UserID: userID})
+	if err != nil {
+		return err
+	}
+	current := make(map[int32]bool, len(userRoles))
+	for _, ur := range userRoles {
+		current[ur.RoleID] = true
+	}
+
+	lookup := func(name string) (int32, bool, error) {
+		role, err := db.Roles().Get(ctx, database.GetRoleOpts{Name: name})
+		if errcode.IsNotFound(err) {
+			logger.Warn("role in LDAP group mapping does not exist", log.String("role", name))
+			return 0, false, nil
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		// System roles are assigned by Sourcegraph itself and must not be granted or revoked
+		// based on directory groups.
+		if role.System {
+			logger.Warn("ignoring system role in LDAP group mapping", log.String("role", name))
+			return 0, false, nil
+		}
+		return role.ID, true, nil
+	}
+
+	for _, name := range add {
+		roleID, ok, err := lookup(name)
+		if err != nil {
+			return err
+		}
+		if ok && !current[roleID] {
+			if _, err := db.UserRoles().Create(ctx, database.CreateUserRoleOpts{UserID: userID, RoleID: roleID}); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range remove {
+		roleID, ok, err := lookup(name)
+		if err != nil {
+			return err
+		}
+		if ok && current[roleID] {
+			if err := db.UserRoles().Delete(ctx, database.DeleteUserRoleOpts{UserID: userID, RoleID: roleID}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestResolveGroupMappings(t *testing.T) {
+	mappings := []*schema.LDAPGroupMapping{
+		{Group: "CN=Engineering,OU=Groups,DC=example,DC=com", Orgs: []string{"eng"}, Roles: []string{"developer"}},
+		{Group: "cn=admins,ou=groups,dc=example,dc=com", Orgs: []string{"eng", "ops"}, Roles: []string{"admin"}},
+		{Group: "cn=contractors,ou=groups,dc=example,dc=com", Orgs: []string{"contractors"}},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		groups map[string]bool
+		want   membershipChanges
+	}{
+		{
+			name:   "no groups",
+			groups: map[string]bool{},
+			want: membershipChanges{
+				removeOrgs:  []string{"contractors", "eng", "ops"},
+				removeRoles: []string{"admin", "developer"},
+			},
+		},
+		{
+			name:   "single group",
+			groups: map[string]bool{"cn=engineering,ou=groups,dc=example,dc=com": true},
+			want: membershipChanges{
+				addOrgs:     []string{"eng"},
+				removeOrgs:  []string{"contractors", "ops"},
+				addRoles:    []string{"developer"},
+				removeRoles: []string{"admin"},
+			},
+		},
+		{
+			name: "overlapping groups",
+			groups: map[string]bool{
+				"cn=admins,ou=groups,dc=example,dc=com":      true,
+				"cn=contractors,ou=groups,dc=example,dc=com": true,
+				"cn=unmapped,ou=groups,dc=example,dc=com":    true,
+			},
+			want: membershipChanges{
+				addOrgs:     []string{"contractors", "eng", "ops"},
+				addRoles:    []string{"admin"},
+				removeRoles: []string{"developer"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := resolveGroupMappings(mappings, tc.groups)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(membershipChanges{})); diff != "" {
+				t.Fatalf("unexpected changes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNormalizeDN(t *testing.T) {
+	for in, want := range map[string]string{
+		"CN=Engineering, OU=Groups,DC=Example,DC=com": "cn=engineering,ou=groups,dc=example,dc=com",
+		"uid=alice+cn=Alice,dc=example,dc=com":        "uid=alice+cn=alice,dc=example,dc=com",
+		"not a dn":                                    "not a dn",
+	} {
+		if got := normalizeDN(in); got != want {
+			t.Errorf("normalizeDN(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -207,5 +208,6 @@ func Init(
 	}()
 
 	enterpriseServices.AuthzResolver = resolvers.NewResolver(observationCtx, db, timeutil.Now)
+	graphqlbackend.RegisterDeadLetterStore(dbworkerstore.NewDeadLetterStore(observationCtx, db.Handle(), eiauthz.PermissionSyncJobWorkerStoreOptions, dbworkerstore.DeadLetterOptions{DeleteRows: true}))
 	return nil
 }
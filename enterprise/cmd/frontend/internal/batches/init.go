@@ -6,6 +6,7 @@ import (
 	sglog "github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/batches/httpapi"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/batches/resolvers"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/batches/webhooks"
@@ -58,5 +59,9 @@ func Init(
 	enterpriseServices.BatchesChangesFileExistsHandler = fileHandler.Exists()
 	enterpriseServices.BatchesChangesFileUploadHandler = fileHandler.Upload()
 
+	for _, deadLetterStore := range store.NewDeadLetterStores(observationCtx, db.Handle()) {
+		graphqlbackend.RegisterDeadLetterStore(deadLetterStore)
+	}
+
 	return nil
 }
@@ -335,6 +335,49 @@ func (r *changesetResolver) ReviewState(ctx context.Context) *string {
 	return &state
 }
 
+func (r *changesetResolver) RequestedReviewers() []string {
+	if r.changeset.RequestedReviewers == nil {
+		return []string{}
+	}
+	return r.changeset.RequestedReviewers
+}
+
+func (r *changesetResolver) BlockedByDependencies() bool {
+	return r.changeset.BlockedByDependencies
+}
+
+func (r *changesetResolver) DependsOn(ctx context.Context) ([]graphqlbackend.ChangesetResolver, error) {
+	deps, err := r.store.ListChangesetDependencies(ctx, store.ListChangesetDependenciesOpts{ChangesetID: r.changeset.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(deps) == 0 {
+		return []graphqlbackend.ChangesetResolver{}, nil
+	}
+
+	ids := make([]int64, 0, len(deps))
+	for _, dep := range deps {
+		ids = append(ids, dep.DependsOnChangesetID)
+	}
+	changesets, _, err := r.store.ListChangesets(ctx, store.ListChangesetsOpts{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: database.Repos.GetReposSetByIDs uses the authzFilter under the hood and
+	// filters out repositories that the user doesn't have access to.
+	reposByID, err := r.store.Repos().GetReposSetByIDs(ctx, changesets.RepoIDs()...)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.ChangesetResolver, 0, len(changesets))
+	for _, c := range changesets {
+		resolvers = append(resolvers, NewChangesetResolver(r.store, r.gitserverClient, c, reposByID[c.RepoID]))
+	}
+	return resolvers, nil
+}
+
 func (r *changesetResolver) CheckState() *string {
 	if !r.changeset.Published() {
 		return nil
@@ -352,6 +395,8 @@ func (r *changesetResolver) Error() *string { return r.changeset.FailureMessage
 
 func (r *changesetResolver) SyncerError() *string { return r.changeset.SyncErrorMessage }
 
+func (r *changesetResolver) MergeError() *string { return r.changeset.MergeFailureMessage }
+
 func (r *changesetResolver) ScheduleEstimateAt(ctx context.Context) (*gqlutil.DateTime, error) {
 	// We need to find out how deep in the queue this changeset is.
 	place, err := r.store.GetChangesetPlaceInSchedulerQueue(ctx, r.changeset.ID)
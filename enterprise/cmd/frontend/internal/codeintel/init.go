@@ -7,17 +7,21 @@ import (
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindexing"
 	autoindexinggraphql "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindexing/transport/graphql"
 	codenavgraphql "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/transport/graphql"
 	policiesgraphql "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/policies/transport/graphql"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/gitserver"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/lsifuploadstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads"
 	uploadgraphql "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/transport/graphql"
 	uploadshttp "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/transport/http"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
 func LoadConfig() {
@@ -87,9 +91,37 @@ func Init(
 	)
 	enterpriseServices.NewCodeIntelUploadHandler = newUploadHandler
 	enterpriseServices.RankingService = codeIntelServices.RankingService
+
+	// Uploads and indexes are deleted by their services, which clean up the data that refers to them.
+	graphqlbackend.RegisterDeadLetterStore(dbworkerstore.NewDeadLetterStore(observationCtx, db.Handle(), uploads.UploadWorkerStoreOptions, dbworkerstore.DeadLetterOptions{
+		DeleteRecords: deleteEach(codeIntelServices.UploadsService.DeleteUploadByID),
+	}))
+	graphqlbackend.RegisterDeadLetterStore(dbworkerstore.NewDeadLetterStore(observationCtx, db.Handle(), autoindexing.IndexWorkerStoreOptions, dbworkerstore.DeadLetterOptions{
+		DeleteRecords: deleteEach(codeIntelServices.AutoIndexingService.DeleteIndexByID),
+	}))
+	graphqlbackend.RegisterDeadLetterStore(dbworkerstore.NewDeadLetterStore(observationCtx, db.Handle(), autoindexing.DependencySyncingJobWorkerStoreOptions, dbworkerstore.DeadLetterOptions{DeleteRows: true}))
+	graphqlbackend.RegisterDeadLetterStore(dbworkerstore.NewDeadLetterStore(observationCtx, db.Handle(), autoindexing.DependencyIndexingJobWorkerStoreOptions, dbworkerstore.DeadLetterOptions{DeleteRows: true}))
 	return nil
 }
 
+// deleteEach adapts a function deleting a single record to a dead letter store hook deleting
+// the records with the given IDs.
+func deleteEach(deleteByID func(ctx context.Context, id int) (bool, error)) func(ctx context.Context, ids []int) (int, error) {
+	return func(ctx context.Context, ids []int) (int, error) {
+		count := 0
+		for _, id := range ids {
+			deleted, err := deleteByID(ctx, id)
+			if err != nil {
+				return count, err
+			}
+			if deleted {
+				count++
+			}
+		}
+		return count, nil
+	}
+}
+
 func scopedContext(name string) *observation.Context {
 	return observation.NewContext(log.Scoped(name+".transport.graphql", "codeintel "+name+" graphql transport"))
 }
@@ -112,6 +112,14 @@ func (r *Resolver) GitBlobLSIFData(ctx context.Context, args *resolverstubs.GitB
 	return r.codenavResolver.GitBlobLSIFData(ctx, args)
 }
 
+func (r *Resolver) GitBlobDocumentSymbols(ctx context.Context, args *resolverstubs.GitBlobLSIFDataArgs) (_ resolverstubs.DocumentSymbolsResolver, err error) {
+	return r.codenavResolver.GitBlobDocumentSymbols(ctx, args)
+}
+
+func (r *Resolver) CodeIntelAPIDiff(ctx context.Context, args *resolverstubs.CodeIntelAPIDiffArgs) (_ resolverstubs.CodeIntelAPIDiffResolver, err error) {
+	return r.codenavResolver.CodeIntelAPIDiff(ctx, args)
+}
+
 func (r *Resolver) GitBlobCodeIntelInfo(ctx context.Context, args *resolverstubs.GitTreeEntryCodeIntelInfoArgs) (_ resolverstubs.GitBlobCodeIntelSupportResolver, err error) {
 	return r.autoIndexingRootResolver.GitBlobCodeIntelInfo(ctx, args)
 }
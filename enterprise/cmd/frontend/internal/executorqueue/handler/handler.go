@@ -7,10 +7,12 @@ import (
 
 	"github.com/sourcegraph/log"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/logstream"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/executor"
 	metricsstore "github.com/sourcegraph/sourcegraph/internal/metrics/store"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
@@ -23,11 +25,15 @@ type ExecutorHandler interface {
 	handleDequeue(w http.ResponseWriter, r *http.Request)
 	handleAddExecutionLogEntry(w http.ResponseWriter, r *http.Request)
 	handleUpdateExecutionLogEntry(w http.ResponseWriter, r *http.Request)
+	handleStreamExecutionLogEntry(w http.ResponseWriter, r *http.Request)
 	handleMarkComplete(w http.ResponseWriter, r *http.Request)
 	handleMarkErrored(w http.ResponseWriter, r *http.Request)
 	handleMarkFailed(w http.ResponseWriter, r *http.Request)
 	handleHeartbeat(w http.ResponseWriter, r *http.Request)
 	handleCanceledJobs(w http.ResponseWriter, r *http.Request)
+
+	dequeue(ctx context.Context, metadata executorMetadata) (apiclient.Job, bool, error)
+	heartbeatJobs(ctx context.Context, executorName string, ids []int) (knownIDs, cancelIDs []int, err error)
 }
 
 var _ ExecutorHandler = &handler[workerutil.Record]{}
@@ -36,6 +42,7 @@ type handler[T workerutil.Record] struct {
 	QueueOptions[T]
 	executorStore database.ExecutorStore
 	metricsStore  metricsstore.DistributedStore
+	logStreams    logstream.Store
 	logger        log.Logger
 }
 
@@ -55,6 +62,7 @@ func NewHandler[T workerutil.Record](executorStore database.ExecutorStore, metri
 	return &handler[T]{
 		executorStore: executorStore,
 		metricsStore:  metricsStore,
+		logStreams:    logstream.NewStore(redispool.Store),
 		logger:        log.Scoped("executor-queue-handler", "The route handler for all executor dbworker API tunnel endpoints"),
 		QueueOptions:  queueOptions,
 	}
@@ -163,6 +171,27 @@ func (h *handler[T]) updateExecutionLogEntry(ctx context.Context, executorName s
 	return errors.Wrap(err, "dbworkerstore.UpdateExecutionLogEntry")
 }
 
+// streamExecutionLogEntry appends the given output of a log entry to the live log stream of
+// the given job. The stream is only used to follow the logs while the job is running, so
+// unlike addExecutionLogEntry, this does not check that the job is still owned by this
+// executor, which would add a database query to each chunk.
+func (h *handler[T]) streamExecutionLogEntry(ctx context.Context, executorName string, jobID, entryID, offset int, entry executor.ExecutionLogEntry) error {
+	if err := validateWorkerHostname(executorName); err != nil {
+		return err
+	}
+
+	chunk := logstream.Chunk{EntryID: entryID, Offset: offset, ExecutionLogEntry: entry}
+	return errors.Wrap(h.logStreams.Append(ctx, h.Name(), jobID, chunk), "logstream.Append")
+}
+
+// finishLogStream marks the live log stream of the given job as finished. Failures are only
+// logged, as the logs of the job have been persisted with the job record.
+func (h *handler[T]) finishLogStream(ctx context.Context, jobID int) {
+	if err := h.logStreams.Finish(ctx, h.Name(), jobID); err != nil {
+		h.logger.Warn("failed to finish executor log stream", log.String("queueName", h.Name()), log.Int("jobID", jobID), log.Error(err))
+	}
+}
+
 // markComplete calls MarkComplete for the given job.
 func (h *handler[T]) markComplete(ctx context.Context, executorName string, jobID int) error {
 	if err := validateWorkerHostname(executorName); err != nil {
@@ -239,11 +268,17 @@ func (h *handler[T]) heartbeat(ctx context.Context, executor types.Executor, ids
 		logger.Error("Failed to upsert executor heartbeat", log.Error(err))
 	}
 
+	return h.heartbeatJobs(ctx, executor.Hostname, ids)
+}
+
+// heartbeatJobs calls Heartbeat for the given jobs, without recording the heartbeat of the
+// executor itself.
+func (h *handler[T]) heartbeatJobs(ctx context.Context, executorName string, ids []int) (knownIDs, cancelIDs []int, err error) {
 	knownIDs, cancelIDs, err = h.Store.Heartbeat(ctx, ids, store.HeartbeatOptions{
 		// We pass the WorkerHostname, so the store enforces the record to be owned by this executor. When
 		// the previous executor didn't report heartbeats anymore, but is still alive and reporting state,
 		// both executors that ever got the job would be writing to the same record. This prevents it.
-		WorkerHostname: executor.Hostname,
+		WorkerHostname: executorName,
 	})
 	return knownIDs, cancelIDs, errors.Wrap(err, "dbworkerstore.UpsertHeartbeat")
 }
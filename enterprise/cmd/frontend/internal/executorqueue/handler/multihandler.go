@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/log"
+
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	metricsstore "github.com/sourcegraph/sourcegraph/internal/metrics/store"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// MultiHandler handles the requests of executors that process jobs from multiple
+// queues at once. Jobs are dequeued from the first queue in the order preferred by
+// the executor that has a job available.
+type MultiHandler struct {
+	executorStore database.ExecutorStore
+	metricsStore  metricsstore.DistributedStore
+	handlers      map[string]ExecutorHandler
+	logger        log.Logger
+}
+
+func NewMultiHandler(executorStore database.ExecutorStore, metricsStore metricsstore.DistributedStore, handlers []ExecutorHandler) *MultiHandler {
+	handlersByName := make(map[string]ExecutorHandler, len(handlers))
+	for _, h := range handlers {
+		handlersByName[h.Name()] = h
+	}
+
+	return &MultiHandler{
+		executorStore: executorStore,
+		metricsStore:  metricsStore,
+		handlers:      handlersByName,
+		logger:        log.Scoped("executor-multi-queue-handler", "The route handler for executors processing multiple queues"),
+	}
+}
+
+// POST /dequeue
+func (m *MultiHandler) handleDequeue(w http.ResponseWriter, r *http.Request) {
+	var payload apiclient.DequeueRequest
+
+	wrapHandler(w, r, &payload, func() (int, any, error) {
+		job, dequeued, err := m.dequeue(r.Context(), payload.Queues, executorMetadata{
+			Name:    payload.ExecutorName,
+			Version: payload.Version,
+			Resources: ResourceMetadata{
+				NumCPUs:   payload.NumCPUs,
+				Memory:    payload.Memory,
+				DiskSpace: payload.DiskSpace,
+			},
+		})
+		if !dequeued {
+			return http.StatusNoContent, nil, err
+		}
+
+		return http.StatusOK, job, err
+	})
+}
+
+// POST /heartbeat
+func (m *MultiHandler) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	var payload apiclient.HeartbeatRequest
+
+	wrapHandler(w, r, &payload, func() (int, any, error) {
+		queueNames := make([]string, 0, len(payload.JobIDsByQueue))
+		for queueName := range payload.JobIDsByQueue {
+			queueNames = append(queueNames, queueName)
+		}
+		sort.Strings(queueNames)
+
+		executor := types.Executor{
+			Hostname:        payload.ExecutorName,
+			QueueName:       strings.Join(queueNames, ","),
+			OS:              payload.OS,
+			Architecture:    payload.Architecture,
+			DockerVersion:   payload.DockerVersion,
+			ExecutorVersion: payload.ExecutorVersion,
+			GitVersion:      payload.GitVersion,
+			IgniteVersion:   payload.IgniteVersion,
+			SrcCliVersion:   payload.SrcCliVersion,
+		}
+
+		// Handle metrics in the background, this should not delay the heartbeat response being
+		// delivered. It is critical for keeping jobs alive.
+		go ingestMetrics(m.logger, m.metricsStore, payload.ExecutorName, payload.PrometheusMetrics)
+
+		knownIDs, cancelIDs, err := m.heartbeat(r.Context(), executor, payload.JobIDsByQueue)
+		return http.StatusOK, apiclient.HeartbeatResponse{KnownIDsByQueue: knownIDs, CancelIDsByQueue: cancelIDs}, err
+	})
+}
+
+// dequeue selects a job record from the given queues in order. The name of the queue
+// the job was dequeued from is set on the returned job. If no job is available for
+// processing in any of the queues, a false-valued flag is returned.
+func (m *MultiHandler) dequeue(ctx context.Context, queueNames []string, metadata executorMetadata) (_ apiclient.Job, dequeued bool, _ error) {
+	if len(queueNames) == 0 {
+		return apiclient.Job{}, false, errors.New("no queues to dequeue from")
+	}
+
+	for _, queueName := range queueNames {
+		h, ok := m.handlers[queueName]
+		if !ok {
+			return apiclient.Job{}, false, errors.Newf("unknown queue %q", queueName)
+		}
+
+		job, dequeued, err := h.dequeue(ctx, metadata)
+		if err != nil {
+			return apiclient.Job{}, false, err
+		}
+		if dequeued {
+			job.Queue = queueName
+			return job, true, nil
+		}
+	}
+
+	return apiclient.Job{}, false, nil
+}
+
+// heartbeat records the heartbeat of the executor and calls Heartbeat for its jobs in
+// each queue.
+func (m *MultiHandler) heartbeat(ctx context.Context, executor types.Executor, idsByQueue map[string][]int) (knownIDs, cancelIDs map[string][]int, err error) {
+	if err := validateWorkerHostname(executor.Hostname); err != nil {
+		return nil, nil, err
+	}
+
+	// Write this heartbeat to the database so that we can populate the UI with recent executor activity.
+	if err := m.executorStore.UpsertHeartbeat(ctx, executor); err != nil {
+		m.logger.Error("Failed to upsert executor heartbeat", log.Error(err))
+	}
+
+	knownIDs = make(map[string][]int, len(idsByQueue))
+	cancelIDs = make(map[string][]int, len(idsByQueue))
+	for queueName, ids := range idsByQueue {
+		h, ok := m.handlers[queueName]
+		if !ok {
+			return nil, nil, errors.Newf("unknown queue %q", queueName)
+		}
+
+		known, cancel, err := h.heartbeatJobs(ctx, executor.Hostname, ids)
+		if err != nil {
+			return nil, nil, err
+		}
+		knownIDs[queueName] = known
+		cancelIDs[queueName] = cancel
+	}
+
+	return knownIDs, cancelIDs, nil
+}
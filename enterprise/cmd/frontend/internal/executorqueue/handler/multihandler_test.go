@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	metricsstore "github.com/sourcegraph/sourcegraph/internal/metrics/store"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestMultiHandlerDequeue(t *testing.T) {
+	recordTransformer := func(ctx context.Context, _ string, record testRecord, _ ResourceMetadata) (apiclient.Job, error) {
+		return apiclient.Job{ID: record.RecordID()}, nil
+	}
+
+	batchesStore := workerstoremocks.NewMockStore[testRecord]()
+	codeintelStore := workerstoremocks.NewMockStore[testRecord]()
+	codeintelStore.DequeueFunc.SetDefaultReturn(testRecord{ID: 42}, true, nil)
+
+	executorStore := database.NewMockExecutorStore()
+	metricsStore := metricsstore.NewMockDistributedStore()
+
+	multiHandler := NewMultiHandler(executorStore, metricsStore, []ExecutorHandler{
+		NewHandler(executorStore, metricsStore, QueueOptions[testRecord]{Name: "batches", Store: batchesStore, RecordTransformer: recordTransformer}),
+		NewHandler(executorStore, metricsStore, QueueOptions[testRecord]{Name: "codeintel", Store: codeintelStore, RecordTransformer: recordTransformer}),
+	})
+
+	job, dequeued, err := multiHandler.dequeue(context.Background(), []string{"batches", "codeintel"}, executorMetadata{Name: "deadbeef"})
+	if err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	}
+	if !dequeued {
+		t.Fatalf("expected job to be dequeued")
+	}
+	if diff := cmp.Diff(apiclient.Job{ID: 42, Queue: "codeintel"}, job); diff != "" {
+		t.Errorf("unexpected job (-want +got):\n%s", diff)
+	}
+	if callCount := len(batchesStore.DequeueFunc.History()); callCount != 1 {
+		t.Errorf("unexpected batches dequeue count. want=%d have=%d", 1, callCount)
+	}
+
+	// Queues not in the request are not dequeued from.
+	if _, dequeued, err := multiHandler.dequeue(context.Background(), []string{"batches"}, executorMetadata{Name: "deadbeef"}); err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	} else if dequeued {
+		t.Fatalf("did not expect a job to be dequeued")
+	}
+
+	if _, _, err := multiHandler.dequeue(context.Background(), []string{"unknown"}, executorMetadata{Name: "deadbeef"}); err == nil {
+		t.Fatalf("expected an error for an unknown queue")
+	}
+}
+
+func TestMultiHandlerHeartbeat(t *testing.T) {
+	batchesStore := workerstoremocks.NewMockStore[testRecord]()
+	batchesStore.HeartbeatFunc.SetDefaultHook(func(ctx context.Context, ids []int, options store.HeartbeatOptions) ([]int, []int, error) {
+		return ids, nil, nil
+	})
+	codeintelStore := workerstoremocks.NewMockStore[testRecord]()
+	codeintelStore.HeartbeatFunc.SetDefaultHook(func(ctx context.Context, ids []int, options store.HeartbeatOptions) ([]int, []int, error) {
+		return ids[:1], ids[:1], nil
+	})
+
+	executorStore := database.NewMockExecutorStore()
+	metricsStore := metricsstore.NewMockDistributedStore()
+
+	multiHandler := NewMultiHandler(executorStore, metricsStore, []ExecutorHandler{
+		NewHandler(executorStore, metricsStore, QueueOptions[testRecord]{Name: "batches", Store: batchesStore}),
+		NewHandler(executorStore, metricsStore, QueueOptions[testRecord]{Name: "codeintel", Store: codeintelStore}),
+	})
+
+	executor := types.Executor{Hostname: "test-hostname", QueueName: "batches,codeintel"}
+	knownIDs, cancelIDs, err := multiHandler.heartbeat(context.Background(), executor, map[string][]int{
+		"batches":   {1, 2},
+		"codeintel": {1, 3},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error performing heartbeat: %s", err)
+	}
+	if diff := cmp.Diff(map[string][]int{"batches": {1, 2}, "codeintel": {1}}, knownIDs); diff != "" {
+		t.Errorf("unexpected known ids (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string][]int{"batches": nil, "codeintel": {1}}, cancelIDs); diff != "" {
+		t.Errorf("unexpected cancel ids (-want +got):\n%s", diff)
+	}
+
+	if callCount := len(executorStore.UpsertHeartbeatFunc.History()); callCount != 1 {
+		t.Errorf("unexpected heartbeat upsert count. want=%d have=%d", 1, callCount)
+	} else if have := executorStore.UpsertHeartbeatFunc.History()[0].Arg1; have != executor {
+		t.Errorf("unexpected executor. want=%v have=%v", executor, have)
+	}
+}
@@ -33,6 +33,7 @@ func SetupRoutes(executorStore database.ExecutorStore, metricsStore metricsstore
 			"dequeue":                 h.handleDequeue,
 			"addExecutionLogEntry":    h.handleAddExecutionLogEntry,
 			"updateExecutionLogEntry": h.handleUpdateExecutionLogEntry,
+			"streamExecutionLogEntry": h.handleStreamExecutionLogEntry,
 			"markComplete":            h.handleMarkComplete,
 			"markErrored":             h.handleMarkErrored,
 			"markFailed":              h.handleMarkFailed,
@@ -44,13 +45,19 @@ func SetupRoutes(executorStore database.ExecutorStore, metricsStore metricsstore
 			subRouter.Path(fmt.Sprintf("/%s", path)).Methods("POST").HandlerFunc(handler)
 		}
 	}
+
+	// Executors processing jobs from multiple queues at once dequeue and send heartbeats
+	// through the routes below. All other requests are sent to the queue of the job.
+	multiHandler := NewMultiHandler(executorStore, metricsStore, handlers)
+	router.Path("/dequeue").Methods("POST").HandlerFunc(multiHandler.handleDequeue)
+	router.Path("/heartbeat").Methods("POST").HandlerFunc(multiHandler.handleHeartbeat)
 }
 
 // POST /{queueName}/dequeue
 func (h *handler[T]) handleDequeue(w http.ResponseWriter, r *http.Request) {
 	var payload apiclient.DequeueRequest
 
-	h.wrapHandler(w, r, &payload, func() (int, any, error) {
+	wrapHandler(w, r, &payload, func() (int, any, error) {
 		job, dequeued, err := h.dequeue(r.Context(), executorMetadata{
 			Name:    payload.ExecutorName,
 			Version: payload.Version,
@@ -72,7 +79,7 @@ func (h *handler[T]) handleDequeue(w http.ResponseWriter, r *http.Request) {
 func (h *handler[T]) handleAddExecutionLogEntry(w http.ResponseWriter, r *http.Request) {
 	var payload apiclient.AddExecutionLogEntryRequest
 
-	h.wrapHandler(w, r, &payload, func() (int, any, error) {
+	wrapHandler(w, r, &payload, func() (int, any, error) {
 		id, err := h.addExecutionLogEntry(r.Context(), payload.ExecutorName, payload.JobID, payload.ExecutionLogEntry)
 		return http.StatusOK, id, err
 	})
@@ -82,21 +89,34 @@ func (h *handler[T]) handleAddExecutionLogEntry(w http.ResponseWriter, r *http.R
 func (h *handler[T]) handleUpdateExecutionLogEntry(w http.ResponseWriter, r *http.Request) {
 	var payload apiclient.UpdateExecutionLogEntryRequest
 
-	h.wrapHandler(w, r, &payload, func() (int, any, error) {
+	wrapHandler(w, r, &payload, func() (int, any, error) {
 		err := h.updateExecutionLogEntry(r.Context(), payload.ExecutorName, payload.JobID, payload.EntryID, payload.ExecutionLogEntry)
 		return http.StatusNoContent, nil, err
 	})
 }
 
+// POST /{queueName}/streamExecutionLogEntry
+func (h *handler[T]) handleStreamExecutionLogEntry(w http.ResponseWriter, r *http.Request) {
+	var payload apiclient.StreamExecutionLogEntryRequest
+
+	wrapHandler(w, r, &payload, func() (int, any, error) {
+		err := h.streamExecutionLogEntry(r.Context(), payload.ExecutorName, payload.JobID, payload.EntryID, payload.Offset, payload.ExecutionLogEntry)
+		return http.StatusNoContent, nil, err
+	})
+}
+
 // POST /{queueName}/markComplete
 func (h *handler[T]) handleMarkComplete(w http.ResponseWriter, r *http.Request) {
 	var payload apiclient.MarkCompleteRequest
 
-	h.wrapHandler(w, r, &payload, func() (int, any, error) {
+	wrapHandler(w, r, &payload, func() (int, any, error) {
 		err := h.markComplete(r.Context(), payload.ExecutorName, payload.JobID)
 		if err == ErrUnknownJob {
 			return http.StatusNotFound, nil, nil
 		}
+		if err == nil {
+			h.finishLogStream(r.Context(), payload.JobID)
+		}
 
 		return http.StatusNoContent, nil, err
 	})
@@ -106,11 +126,14 @@ func (h *handler[T]) handleMarkComplete(w http.ResponseWriter, r *http.Request)
 func (h *handler[T]) handleMarkErrored(w http.ResponseWriter, r *http.Request) {
 	var payload apiclient.MarkErroredRequest
 
-	h.wrapHandler(w, r, &payload, func() (int, any, error) {
+	wrapHandler(w, r, &payload, func() (int, any, error) {
 		err := h.markErrored(r.Context(), payload.ExecutorName, payload.JobID, payload.ErrorMessage)
 		if err == ErrUnknownJob {
 			return http.StatusNotFound, nil, nil
 		}
+		if err == nil {
+			h.finishLogStream(r.Context(), payload.JobID)
+		}
 
 		return http.StatusNoContent, nil, err
 	})
@@ -120,11 +143,14 @@ func (h *handler[T]) handleMarkErrored(w http.ResponseWriter, r *http.Request) {
 func (h *handler[T]) handleMarkFailed(w http.ResponseWriter, r *http.Request) {
 	var payload apiclient.MarkErroredRequest
 
-	h.wrapHandler(w, r, &payload, func() (int, any, error) {
+	wrapHandler(w, r, &payload, func() (int, any, error) {
 		err := h.markFailed(r.Context(), payload.ExecutorName, payload.JobID, payload.ErrorMessage)
 		if err == ErrUnknownJob {
 			return http.StatusNotFound, nil, nil
 		}
+		if err == nil {
+			h.finishLogStream(r.Context(), payload.JobID)
+		}
 
 		return http.StatusNoContent, nil, err
 	})
@@ -134,7 +160,7 @@ func (h *handler[T]) handleMarkFailed(w http.ResponseWriter, r *http.Request) {
 func (h *handler[T]) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
 	var payload apiclient.HeartbeatRequest
 
-	h.wrapHandler(w, r, &payload, func() (int, any, error) {
+	wrapHandler(w, r, &payload, func() (int, any, error) {
 		executor := types.Executor{
 			Hostname:        payload.ExecutorName,
 			QueueName:       h.QueueOptions.Name,
@@ -149,19 +175,7 @@ func (h *handler[T]) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
 
 		// Handle metrics in the background, this should not delay the heartbeat response being
 		// delivered. It is critical for keeping jobs alive.
-		go func() {
-			metrics, err := decodeAndLabelMetrics(payload.PrometheusMetrics, payload.ExecutorName)
-			if err != nil {
-				// Just log the error but don't panic. The heartbeat is more important.
-				h.logger.Error("failed to decode metrics and apply labels for executor heartbeat", log.Error(err))
-				return
-			}
-
-			if err := h.metricsStore.Ingest(payload.ExecutorName, metrics); err != nil {
-				// Just log the error but don't panic. The heartbeat is more important.
-				h.logger.Error("failed to ingest metrics for executor heartbeat", log.Error(err))
-			}
-		}()
+		go ingestMetrics(h.logger, h.metricsStore, payload.ExecutorName, payload.PrometheusMetrics)
 
 		knownIDs, cancelIDs, err := h.heartbeat(r.Context(), executor, payload.JobIDs)
 
@@ -179,12 +193,27 @@ func (h *handler[T]) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
 func (h *handler[T]) handleCanceledJobs(w http.ResponseWriter, r *http.Request) {
 	var payload apiclient.CanceledJobsRequest
 
-	h.wrapHandler(w, r, &payload, func() (int, any, error) {
+	wrapHandler(w, r, &payload, func() (int, any, error) {
 		canceledIDs, err := h.canceled(r.Context(), payload.ExecutorName, payload.KnownJobIDs)
 		return http.StatusOK, canceledIDs, err
 	})
 }
 
+// ingestMetrics decodes the metrics sent with an executor heartbeat and stores them.
+func ingestMetrics(logger log.Logger, metricsStore metricsstore.DistributedStore, executorName, encodedMetrics string) {
+	metrics, err := decodeAndLabelMetrics(encodedMetrics, executorName)
+	if err != nil {
+		// Just log the error but don't panic. The heartbeat is more important.
+		logger.Error("failed to decode metrics and apply labels for executor heartbeat", log.Error(err))
+		return
+	}
+
+	if err := metricsStore.Ingest(executorName, metrics); err != nil {
+		// Just log the error but don't panic. The heartbeat is more important.
+		logger.Error("failed to ingest metrics for executor heartbeat", log.Error(err))
+	}
+}
+
 type errorResponse struct {
 	Error string `json:"error"`
 }
@@ -195,7 +224,7 @@ type errorResponse struct {
 // is returned. Otherwise, the response status will match the status code value returned from the
 // handler, and the payload value returned from the handler is encoded and written to the
 // response body.
-func (h *handler[T]) wrapHandler(w http.ResponseWriter, r *http.Request, payload any, handler func() (int, any, error)) {
+func wrapHandler(w http.ResponseWriter, r *http.Request, payload any, handler func() (int, any, error)) {
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		http.Error(w, fmt.Sprintf("Failed to unmarshal payload: %s", err.Error()), http.StatusBadRequest)
 		return
@@ -11,9 +11,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	metricsstore "github.com/sourcegraph/sourcegraph/internal/metrics/store"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/logstream"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/queues/batches"
 	codeintelqueue "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/queues/codeintel"
 )
@@ -61,5 +63,11 @@ func Init(
 	)
 
 	enterpriseServices.NewExecutorProxyHandler = queueHandler
+
+	// Log streams are read by users, so each queue decides who may follow a job.
+	enterpriseServices.ExecutorLogStreamHandler = logstream.NewHandler(logger, logstream.NewStore(redispool.Store), map[string]logstream.Authorizer{
+		"codeintel": codeintelqueue.AuthorizeLogStream(db),
+		"batches":   batches.AuthorizeLogStream(observationCtx, db),
+	})
 	return nil
 }
@@ -0,0 +1,125 @@
+package logstream
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/log"
+
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+)
+
+// Authorizer returns an error if the user in the given context is not allowed to
+// follow the logs of the job with the given ID.
+type Authorizer func(ctx context.Context, jobID int) error
+
+// pollInterval is the interval in which the handler checks for new chunks.
+const pollInterval = 250 * time.Millisecond
+
+// NewHandler creates an HTTP handler that streams the output of a running job as
+// server-sent events. The route must define the variables queueName and jobID.
+// Each queue that supports following its logs needs an authorizer.
+//
+// The handler first sends all chunks streamed so far, then tails the stream until
+// the job finishes or the client disconnects:
+//
+//   - "chunk" events carry a Chunk along with its position in the stream.
+//   - A "done" event is sent once the job has finished.
+//
+// Clients reconnecting after a dropped connection can pass the position following
+// the last chunk they received as the position query parameter to resume the stream.
+func NewHandler(logger log.Logger, store Store, authorizers map[string]Authorizer) http.Handler {
+	return &handler{
+		logger:      logger,
+		store:       store,
+		authorizers: authorizers,
+	}
+}
+
+type handler struct {
+	logger      log.Logger
+	store       Store
+	authorizers map[string]Authorizer
+}
+
+type chunkEvent struct {
+	Position int `json:"position"`
+	Chunk
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	queueName := mux.Vars(r)["queueName"]
+	authorize, ok := h.authorizers[queueName]
+	if !ok {
+		http.Error(w, "unknown queue", http.StatusNotFound)
+		return
+	}
+
+	jobID, err := strconv.Atoi(mux.Vars(r)["jobID"])
+	if err != nil {
+		http.Error(w, "invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	position := 0
+	if value := r.URL.Query().Get("position"); value != "" {
+		if position, err = strconv.Atoi(value); err != nil || position < 0 {
+			http.Error(w, "invalid position", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// 🚨 SECURITY: Only users who can view the job may follow its logs. We don't
+	// distinguish between jobs that don't exist and jobs the user may not view, so
+	// that the existence of a job isn't leaked.
+	if err := authorize(ctx, jobID); err != nil {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+
+	eventWriter, err := streamhttp.NewWriter(w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Send the headers right away, so that clients following a job that hasn't
+	// written any output yet don't wait for a response.
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+
+	for {
+		chunks, finished, next, err := h.store.Read(ctx, queueName, jobID, position)
+		if err != nil {
+			if ctx.Err() == nil {
+				h.logger.Error("failed to read executor log stream", log.String("queueName", queueName), log.Int("jobID", jobID), log.Error(err))
+				_ = eventWriter.Event("error", map[string]string{"error": "failed to read log stream"})
+			}
+			return
+		}
+
+		for i, chunk := range chunks {
+			if err := eventWriter.Event("chunk", chunkEvent{Position: position + i, Chunk: chunk}); err != nil {
+				// The client disconnected.
+				return
+			}
+		}
+		position = next
+
+		if finished {
+			_ = eventWriter.Event("done", map[string]any{})
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
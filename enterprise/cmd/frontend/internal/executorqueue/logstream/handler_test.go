@@ -0,0 +1,170 @@
+package logstream
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/executor"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestHandler(t *testing.T) {
+	store := &memoryStore{}
+	store.chunks = []Chunk{
+		{EntryID: 1, Offset: 0, ExecutionLogEntry: executor.ExecutionLogEntry{Key: "step.0", Out: "hello"}},
+		{EntryID: 1, Offset: 5, ExecutionLogEntry: executor.ExecutionLogEntry{Key: "step.0", Out: " world"}},
+	}
+	store.finished = true
+
+	var authorizedJobIDs []int
+	authorizers := map[string]Authorizer{
+		"batches": func(_ context.Context, jobID int) error {
+			authorizedJobIDs = append(authorizedJobIDs, jobID)
+			if jobID != 42 {
+				return errors.New("not allowed")
+			}
+			return nil
+		},
+	}
+
+	router := mux.NewRouter()
+	router.Path("/{queueName}/jobs/{jobID}/logs/stream").Handler(NewHandler(logtest.Scoped(t), store, authorizers))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("unexpected error requesting %s: %s", path, err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading response: %s", err)
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		status, body := get("/batches/jobs/42/logs/stream")
+		if status != http.StatusOK {
+			t.Fatalf("unexpected status. want=%d have=%d", http.StatusOK, status)
+		}
+
+		want := strings.Join([]string{
+			`event: chunk`,
+			`data: {"position":0,"entryId":1,"offset":0,"key":"step.0","command":null,"startTime":"0001-01-01T00:00:00Z","out":"hello"}`,
+			``,
+			`event: chunk`,
+			`data: {"position":1,"entryId":1,"offset":5,"key":"step.0","command":null,"startTime":"0001-01-01T00:00:00Z","out":" world"}`,
+			``,
+			`event: done`,
+			`data: {}`,
+			``,
+			``,
+		}, "\n")
+		if body != want {
+			t.Errorf("unexpected response body.\nwant:\n%s\nhave:\n%s", want, body)
+		}
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		status, body := get("/batches/jobs/42/logs/stream?position=1")
+		if status != http.StatusOK {
+			t.Fatalf("unexpected status. want=%d have=%d", http.StatusOK, status)
+		}
+		if strings.Contains(body, `"position":0`) || !strings.Contains(body, `"position":1`) {
+			t.Errorf("expected stream to resume at position 1, have:\n%s", body)
+		}
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		if status, _ := get("/batches/jobs/43/logs/stream"); status != http.StatusNotFound {
+			t.Errorf("unexpected status. want=%d have=%d", http.StatusNotFound, status)
+		}
+	})
+
+	t.Run("unknown queue", func(t *testing.T) {
+		if status, _ := get("/codeintel/jobs/42/logs/stream"); status != http.StatusNotFound {
+			t.Errorf("unexpected status. want=%d have=%d", http.StatusNotFound, status)
+		}
+	})
+
+	t.Run("invalid position", func(t *testing.T) {
+		if status, _ := get("/batches/jobs/42/logs/stream?position=-1"); status != http.StatusBadRequest {
+			t.Errorf("unexpected status. want=%d have=%d", http.StatusBadRequest, status)
+		}
+	})
+}
+
+func TestHandlerTail(t *testing.T) {
+	store := &memoryStore{}
+	authorizers := map[string]Authorizer{
+		"codeintel": func(context.Context, int) error { return nil },
+	}
+
+	router := mux.NewRouter()
+	router.Path("/{queueName}/jobs/{jobID}/logs/stream").Handler(NewHandler(logtest.Scoped(t), store, authorizers))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/codeintel/jobs/1/logs/stream")
+	if err != nil {
+		t.Fatalf("unexpected error requesting stream: %s", err)
+	}
+	defer resp.Body.Close()
+
+	// Chunks appended while the client is connected are sent as well.
+	_ = store.Append(context.Background(), "codeintel", 1, Chunk{EntryID: 3, ExecutionLogEntry: executor.ExecutionLogEntry{Out: "indexing"}})
+	_ = store.Finish(context.Background(), "codeintel", 1)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error reading response: %s", err)
+	}
+	if !strings.Contains(string(body), `"out":"indexing"`) || !strings.HasSuffix(string(body), "event: done\ndata: {}\n\n") {
+		t.Errorf("unexpected response body:\n%s", body)
+	}
+}
+
+// memoryStore is a Store holding the stream of a single job.
+type memoryStore struct {
+	mu       sync.Mutex
+	chunks   []Chunk
+	finished bool
+}
+
+func (s *memoryStore) Append(_ context.Context, _ string, _ int, chunk Chunk) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chunks = append(s.chunks, chunk)
+	return nil
+}
+
+func (s *memoryStore) Finish(context.Context, string, int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.finished = true
+	return nil
+}
+
+func (s *memoryStore) Read(_ context.Context, _ string, _, position int) ([]Chunk, bool, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if position > len(s.chunks) {
+		position = len(s.chunks)
+	}
+	return s.chunks[position:], s.finished, len(s.chunks), nil
+}
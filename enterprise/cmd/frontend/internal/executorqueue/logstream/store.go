@@ -0,0 +1,141 @@
+package logstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Chunk is a piece of output of an execution log entry, streamed by an executor
+// while the command is running.
+type Chunk struct {
+	EntryID int `json:"entryId"`
+	// Offset is the position in the output of the log entry at which Out starts.
+	Offset int `json:"offset"`
+	executor.ExecutionLogEntry
+}
+
+// Store keeps the output streamed by executors for the jobs they are processing,
+// so that it can be followed by users through any frontend instance. The stream
+// of a job is temporary: the execution logs persisted with the job record remain
+// the source of truth.
+type Store interface {
+	// Append adds the given chunk to the stream of the given job.
+	Append(ctx context.Context, queueName string, jobID int, chunk Chunk) error
+
+	// Finish marks the stream of the given job as finished. No more chunks are
+	// expected afterwards.
+	Finish(ctx context.Context, queueName string, jobID int) error
+
+	// Read returns the chunks of the stream of the given job starting at the given
+	// position, whether the stream is finished, and the position to continue reading
+	// at.
+	Read(ctx context.Context, queueName string, jobID, position int) (chunks []Chunk, finished bool, next int, err error)
+}
+
+const (
+	// streamTTLSeconds is the time after the last chunk after which the stream of a
+	// job is discarded, e.g. when the executor processing it died.
+	streamTTLSeconds = 60 * 60
+	// finishedStreamTTLSeconds is the time after which the stream of a finished
+	// job is discarded. At that point, its logs are read from the job record.
+	finishedStreamTTLSeconds = 10 * 60
+	// maxChunksPerJob caps the size of a stream. Further chunks are dropped; the
+	// output is still persisted with the job record.
+	maxChunksPerJob = 20000
+)
+
+type store struct {
+	kv redispool.KeyValue
+}
+
+// NewStore creates a store keeping the streams in the given key-value store.
+func NewStore(kv redispool.KeyValue) Store {
+	return &store{kv: kv}
+}
+
+// record is a single element of a stream. Streams are stored as lists with the
+// newest record first.
+type record struct {
+	Chunk    *Chunk `json:"chunk,omitempty"`
+	Finished bool   `json:"finished,omitempty"`
+}
+
+func (s *store) Append(ctx context.Context, queueName string, jobID int, chunk Chunk) error {
+	kv := s.kv.WithContext(ctx)
+	key := streamKey(queueName, jobID)
+
+	n, err := kv.LLen(key)
+	if err != nil {
+		return err
+	}
+	if n >= maxChunksPerJob {
+		return nil
+	}
+
+	return s.push(kv, key, record{Chunk: &chunk}, streamTTLSeconds)
+}
+
+func (s *store) Finish(ctx context.Context, queueName string, jobID int) error {
+	kv := s.kv.WithContext(ctx)
+	key := streamKey(queueName, jobID)
+
+	// There is nothing to finish if nothing was streamed for the job, e.g. when the
+	// executor does not support streaming.
+	n, err := kv.LLen(key)
+	if err != nil || n == 0 {
+		return err
+	}
+
+	return s.push(kv, key, record{Finished: true}, finishedStreamTTLSeconds)
+}
+
+func (s *store) push(kv redispool.KeyValue, key string, r record, ttlSeconds int) error {
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := kv.LPush(key, payload); err != nil {
+		return err
+	}
+	return kv.Expire(key, ttlSeconds)
+}
+
+func (s *store) Read(ctx context.Context, queueName string, jobID, position int) ([]Chunk, bool, int, error) {
+	if position < 0 {
+		return nil, false, 0, errors.Newf("invalid position %d", position)
+	}
+
+	// The list holds the newest record first, so the record at a position counted
+	// from the oldest record is at the negative index -(position+1). Ranging from the
+	// head to that index includes records appended concurrently.
+	values, err := s.kv.WithContext(ctx).LRange(streamKey(queueName, jobID), 0, -(position + 1)).ByteSlices()
+	if err != nil {
+		return nil, false, 0, err
+	}
+
+	chunks := make([]Chunk, 0, len(values))
+	for i := len(values) - 1; i >= 0; i-- {
+		var r record
+		if err := json.Unmarshal(values[i], &r); err != nil {
+			return nil, false, 0, err
+		}
+		if r.Finished {
+			// Chunks of late requests after the job finished are ignored.
+			return chunks, true, position + len(chunks) + 1, nil
+		}
+		if r.Chunk != nil {
+			chunks = append(chunks, *r.Chunk)
+		}
+	}
+
+	return chunks, false, position + len(values), nil
+}
+
+func streamKey(queueName string, jobID int) string {
+	return fmt.Sprintf("executor-log-stream:%s:%d", queueName, jobID)
+}
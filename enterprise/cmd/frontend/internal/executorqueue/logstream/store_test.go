@@ -0,0 +1,132 @@
+package logstream
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore(redisKeyValueForTest(t))
+
+	chunks := []Chunk{
+		{EntryID: 1, Offset: 0, ExecutionLogEntry: executor.ExecutionLogEntry{Key: "step.0", Out: "hello "}},
+		{EntryID: 1, Offset: 6, ExecutionLogEntry: executor.ExecutionLogEntry{Key: "step.0", Out: "world"}},
+		{EntryID: 2, Offset: 0, ExecutionLogEntry: executor.ExecutionLogEntry{Key: "step.1", Out: "done"}},
+	}
+	for _, chunk := range chunks[:2] {
+		if err := s.Append(ctx, "batches", 42, chunk); err != nil {
+			t.Fatalf("unexpected error appending chunk: %s", err)
+		}
+	}
+
+	have, finished, next, err := s.Read(ctx, "batches", 42, 0)
+	if err != nil {
+		t.Fatalf("unexpected error reading stream: %s", err)
+	}
+	if diff := cmp.Diff(chunks[:2], have); diff != "" {
+		t.Errorf("unexpected chunks (-want +got):\n%s", diff)
+	}
+	if finished || next != 2 {
+		t.Errorf("unexpected stream state. want finished=false next=2, have finished=%v next=%d", finished, next)
+	}
+
+	if err := s.Append(ctx, "batches", 42, chunks[2]); err != nil {
+		t.Fatalf("unexpected error appending chunk: %s", err)
+	}
+	if err := s.Finish(ctx, "batches", 42); err != nil {
+		t.Fatalf("unexpected error finishing stream: %s", err)
+	}
+	// Chunks of late requests are ignored.
+	if err := s.Append(ctx, "batches", 42, chunks[0]); err != nil {
+		t.Fatalf("unexpected error appending chunk: %s", err)
+	}
+
+	have, finished, next, err = s.Read(ctx, "batches", 42, next)
+	if err != nil {
+		t.Fatalf("unexpected error reading stream: %s", err)
+	}
+	if diff := cmp.Diff(chunks[2:], have); diff != "" {
+		t.Errorf("unexpected chunks (-want +got):\n%s", diff)
+	}
+	if !finished || next != 4 {
+		t.Errorf("unexpected stream state. want finished=true next=4, have finished=%v next=%d", finished, next)
+	}
+
+	// Streams of other jobs are separate.
+	have, finished, _, err = s.Read(ctx, "codeintel", 42, 0)
+	if err != nil {
+		t.Fatalf("unexpected error reading stream: %s", err)
+	}
+	if len(have) != 0 || finished {
+		t.Errorf("expected empty stream, have %d chunks (finished=%v)", len(have), finished)
+	}
+}
+
+func TestStoreFinishWithoutChunks(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore(redisKeyValueForTest(t))
+
+	if err := s.Finish(ctx, "batches", 42); err != nil {
+		t.Fatalf("unexpected error finishing stream: %s", err)
+	}
+
+	chunks, finished, next, err := s.Read(ctx, "batches", 42, 0)
+	if err != nil {
+		t.Fatalf("unexpected error reading stream: %s", err)
+	}
+	if len(chunks) != 0 || finished || next != 0 {
+		t.Errorf("expected no stream to be created, have %d chunks (finished=%v, next=%d)", len(chunks), finished, next)
+	}
+}
+
+func redisKeyValueForTest(t *testing.T) redispool.KeyValue {
+	t.Helper()
+
+	pool := &redis.Pool{
+		MaxIdle:     3,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+		TestOnBorrow: func(c redis.Conn, t time.Time) error {
+			_, err := c.Do("PING")
+			return err
+		},
+	}
+
+	prefix := "__test__" + t.Name()
+	c := pool.Get()
+	defer c.Close()
+
+	// If we are not on CI, skip the test if our redis connection fails.
+	if os.Getenv("CI") == "" {
+		_, err := c.Do("PING")
+		if err != nil {
+			t.Skip("could not connect to redis", err)
+		}
+	}
+
+	keys, err := redis.Values(c.Do("KEYS", prefix+":*"))
+	if err != nil {
+		t.Fatalf("unexpected error listing test keys: %s", err)
+	}
+	if len(keys) > 0 {
+		if _, err := c.Do("DEL", keys...); err != nil {
+			t.Fatalf("unexpected error clearing test keys: %s", err)
+		}
+	}
+
+	kv := redispool.RedisKeyValue(pool).(interface {
+		WithPrefix(string) redispool.KeyValue
+	})
+	return kv.WithPrefix(prefix)
+}
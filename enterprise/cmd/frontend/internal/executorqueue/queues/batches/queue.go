@@ -6,9 +6,11 @@ import (
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/logstream"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 )
@@ -27,3 +29,19 @@ func QueueOptions(observationCtx *observation.Context, db database.DB, _ func()
 		RecordTransformer: recordTransformer,
 	}
 }
+
+// AuthorizeLogStream allows the user who created the batch spec of a workspace execution
+// job, and site admins, to follow the logs of the job.
+func AuthorizeLogStream(observationCtx *observation.Context, db database.DB) logstream.Authorizer {
+	return func(ctx context.Context, jobID int) error {
+		job, err := store.New(db, observationCtx, nil).GetBatchSpecWorkspaceExecutionJob(ctx, store.GetBatchSpecWorkspaceExecutionJobOpts{
+			ID:          int64(jobID),
+			ExcludeRank: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		return auth.CheckSiteAdminOrSameUser(ctx, db, job.UserID)
+	}
+}
@@ -3,13 +3,20 @@ package codeintel
 import (
 	"context"
 
+	"github.com/keegancsmith/sqlf"
+
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/logstream"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindexing"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func QueueOptions(observationCtx *observation.Context, db database.DB, accessToken func() string) handler.QueueOptions[types.Index] {
@@ -25,3 +32,30 @@ func QueueOptions(observationCtx *observation.Context, db database.DB, accessTok
 		RecordTransformer: recordTransformer,
 	}
 }
+
+// AuthorizeLogStream allows site admins to follow the logs of an index job. The execution
+// logs of index jobs can only be viewed by site admins in the API as well.
+func AuthorizeLogStream(db database.DB) logstream.Authorizer {
+	return func(ctx context.Context, jobID int) error {
+		// 🚨 SECURITY: Only site admins may view the output of index jobs.
+		if err := auth.CheckCurrentUserIsSiteAdmin(ctx, db); err != nil {
+			return err
+		}
+
+		repositoryID, ok, err := basestore.ScanFirstInt(basestore.NewWithHandle(db.Handle()).Query(ctx, sqlf.Sprintf(indexRepositoryIDQuery, jobID)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.Newf("unknown index %d", jobID)
+		}
+
+		// Indexes of deleted repositories are treated like unknown indexes.
+		_, err = db.Repos().Get(ctx, api.RepoID(repositoryID))
+		return err
+	}
+}
+
+const indexRepositoryIDQuery = `
+SELECT repository_id FROM lsif_indexes WHERE id = %s
+`
@@ -0,0 +1,43 @@
+package codeintel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/logstream"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestAuthorizeLogStreamNonSiteAdmin(t *testing.T) {
+	users := database.NewMockUserStore()
+	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1}, nil)
+
+	// The user can view the repository of the index.
+	repos := database.NewMockRepoStore()
+	repos.GetFunc.SetDefaultReturn(&types.Repo{ID: 1, Name: "github.com/sourcegraph/sourcegraph"}, nil)
+
+	db := database.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	router := mux.NewRouter()
+	router.Path("/{queueName}/jobs/{jobID}/logs/stream").Handler(logstream.NewHandler(logtest.Scoped(t), nil, map[string]logstream.Authorizer{
+		"codeintel": AuthorizeLogStream(db),
+	}))
+
+	ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+	req := httptest.NewRequest(http.MethodGet, "/codeintel/jobs/42/logs/stream", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unexpected status. want=%d have=%d", http.StatusNotFound, rec.Code)
+	}
+}
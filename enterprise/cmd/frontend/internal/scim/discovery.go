@@ -0,0 +1,212 @@
+package scim
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// The discovery endpoints describe what this service provider supports (RFC 7644 section 4).
+
+func (h *handler) getServiceProviderConfig(w http.ResponseWriter, r *http.Request) error {
+	type supported struct {
+		Supported bool `json:"supported"`
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"schemas":          []string{serviceProviderConfigSchemaURI},
+		"documentationUri": "https://docs.sourcegraph.com/admin/auth/scim",
+		"patch":            supported{true},
+		"bulk":             map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":           map[string]any{"supported": true, "maxResults": maxCount},
+		"changePassword":   supported{false},
+		"sort":             supported{false},
+		"etag":             supported{false},
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "Authentication with the token configured in the scim.authToken site configuration setting.",
+			"primary":     true,
+		}},
+		"meta": meta{ResourceType: "ServiceProviderConfig", Location: location("ServiceProviderConfig", "")},
+	})
+	return nil
+}
+
+type resourceType struct {
+	Schemas  []string `json:"schemas"`
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Endpoint string   `json:"endpoint"`
+	Schema   string   `json:"schema"`
+	Meta     meta     `json:"meta"`
+}
+
+var resourceTypes = []resourceType{
+	{
+		Schemas:  []string{resourceTypeSchemaURI},
+		ID:       "User",
+		Name:     "User",
+		Endpoint: "/Users",
+		Schema:   userSchemaURI,
+		Meta:     meta{ResourceType: "ResourceType"},
+	},
+	{
+		Schemas:  []string{resourceTypeSchemaURI},
+		ID:       "Group",
+		Name:     "Group",
+		Endpoint: "/Groups",
+		Schema:   groupSchemaURI,
+		Meta:     meta{ResourceType: "ResourceType"},
+	},
+}
+
+func (h *handler) listResourceTypes(w http.ResponseWriter, r *http.Request) error {
+	items := make([]any, 0, len(resourceTypes))
+	for _, rt := range resourceTypes {
+		rt.Meta.Location = location("ResourceTypes", rt.ID)
+		items = append(items, rt)
+	}
+	writeJSON(w, http.StatusOK, staticList(items))
+	return nil
+}
+
+func (h *handler) getResourceType(w http.ResponseWriter, r *http.Request) error {
+	id := mux.Vars(r)["id"]
+	for _, rt := range resourceTypes {
+		if rt.ID == id {
+			rt.Meta.Location = location("ResourceTypes", rt.ID)
+			writeJSON(w, http.StatusOK, rt)
+			return nil
+		}
+	}
+	return errNotFound("ResourceType", id)
+}
+
+type schemaAttribute struct {
+	Name          string            `json:"name"`
+	Type          string            `json:"type"`
+	MultiValued   bool              `json:"multiValued"`
+	Required      bool              `json:"required"`
+	CaseExact     bool              `json:"caseExact"`
+	Mutability    string            `json:"mutability"`
+	Returned      string            `json:"returned"`
+	Uniqueness    string            `json:"uniqueness"`
+	SubAttributes []schemaAttribute `json:"subAttributes,omitempty"`
+}
+
+type schemaDefinition struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Attributes  []schemaAttribute `json:"attributes"`
+	Meta        meta              `json:"meta"`
+}
+
+// attr returns a single-valued, optional, mutable attribute.
+func attr(name, typ string, subAttributes ...schemaAttribute) schemaAttribute {
+	return schemaAttribute{
+		Name:          name,
+		Type:          typ,
+		Mutability:    "readWrite",
+		Returned:      "default",
+		Uniqueness:    "none",
+		SubAttributes: subAttributes,
+	}
+}
+
+func multiValued(a schemaAttribute) schemaAttribute {
+	a.MultiValued = true
+	return a
+}
+
+func required(a schemaAttribute) schemaAttribute {
+	a.Required = true
+	return a
+}
+
+func unique(a schemaAttribute) schemaAttribute {
+	a.Uniqueness = "server"
+	return a
+}
+
+func withMutability(mutability string, a schemaAttribute) schemaAttribute {
+	a.Mutability = mutability
+	return a
+}
+
+var schemaDefinitions = []schemaDefinition{
+	{
+		Schemas:     []string{schemaSchemaURI},
+		ID:          userSchemaURI,
+		Name:        "User",
+		Description: "User Account",
+		Attributes: []schemaAttribute{
+			unique(required(attr("userName", "string"))),
+			attr("name", "complex",
+				attr("formatted", "string"),
+				attr("familyName", "string"),
+				attr("givenName", "string"),
+				attr("middleName", "string"),
+				attr("honorificPrefix", "string"),
+				attr("honorificSuffix", "string"),
+			),
+			attr("displayName", "string"),
+			multiValued(attr("emails", "complex",
+				attr("value", "string"),
+				attr("display", "string"),
+				attr("type", "string"),
+				attr("primary", "boolean"),
+			)),
+			attr("active", "boolean"),
+		},
+		Meta: meta{ResourceType: "Schema"},
+	},
+	{
+		Schemas:     []string{schemaSchemaURI},
+		ID:          groupSchemaURI,
+		Name:        "Group",
+		Description: "Group, provisioned as an organization or a role",
+		Attributes: []schemaAttribute{
+			required(attr("displayName", "string")),
+			multiValued(attr("members", "complex",
+				withMutability("immutable", attr("value", "string")),
+				withMutability("readOnly", attr("display", "string")),
+				withMutability("immutable", attr("$ref", "reference")),
+			)),
+		},
+		Meta: meta{ResourceType: "Schema"},
+	},
+}
+
+func (h *handler) listSchemas(w http.ResponseWriter, r *http.Request) error {
+	items := make([]any, 0, len(schemaDefinitions))
+	for _, s := range schemaDefinitions {
+		s.Meta.Location = location("Schemas", s.ID)
+		items = append(items, s)
+	}
+	writeJSON(w, http.StatusOK, staticList(items))
+	return nil
+}
+
+func (h *handler) getSchema(w http.ResponseWriter, r *http.Request) error {
+	id := mux.Vars(r)["id"]
+	for _, s := range schemaDefinitions {
+		if s.ID == id {
+			s.Meta.Location = location("Schemas", s.ID)
+			writeJSON(w, http.StatusOK, s)
+			return nil
+		}
+	}
+	return errNotFound("Schema", id)
+}
+
+func staticList(items []any) *listResponse {
+	return &listResponse{
+		Schemas:      []string{listResponseSchemaURI},
+		TotalResults: len(items),
+		StartIndex:   1,
+		ItemsPerPage: len(items),
+		Resources:    items,
+	}
+}
@@ -0,0 +1,440 @@
+package scim
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// A filter is a parsed SCIM filter expression (RFC 7644 section 3.4.2.2). Filters are evaluated
+// against the JSON representation of a resource, as decoded by encoding/json into a map.
+type filter interface {
+	match(resource map[string]any) bool
+}
+
+type logicalFilter struct {
+	and         bool // otherwise "or"
+	left, right filter
+}
+
+func (f *logicalFilter) match(r map[string]any) bool {
+	if f.and {
+		return f.left.match(r) && f.right.match(r)
+	}
+	return f.left.match(r) || f.right.match(r)
+}
+
+type notFilter struct{ filter filter }
+
+func (f *notFilter) match(r map[string]any) bool { return !f.filter.match(r) }
+
+type presentFilter struct{ path attrPath }
+
+func (f *presentFilter) match(r map[string]any) bool {
+	for _, v := range f.path.values(r) {
+		if !isEmpty(v) {
+			return true
+		}
+	}
+	return false
+}
+
+type compareFilter struct {
+	path  attrPath
+	op    string
+	value any // string, bool, float64 or nil
+}
+
+func (f *compareFilter) match(r map[string]any) bool {
+	values := f.path.values(r)
+	if f.value == nil {
+		// "eq null" and "ne null" test for (non-)presence.
+		present := (&presentFilter{path: f.path}).match(r)
+		return present == (f.op == "ne")
+	}
+	if f.op == "ne" {
+		eq := &compareFilter{path: f.path, op: "eq", value: f.value}
+		return !eq.match(r)
+	}
+	for _, v := range values {
+		if compareValue(f.op, v, f.value, f.path.caseExact()) {
+			return true
+		}
+	}
+	return false
+}
+
+// valuePathFilter matches if any element of a multi-valued complex attribute matches the inner
+// filter, e.g. emails[type eq "work" and value co "@example.com"].
+type valuePathFilter struct {
+	attr   string
+	filter filter
+}
+
+func (f *valuePathFilter) match(r map[string]any) bool {
+	for _, elem := range asList(lookup(r, f.attr)) {
+		if m, ok := elem.(map[string]any); ok && f.filter.match(m) {
+			return true
+		}
+	}
+	return false
+}
+
+// attrPath is an attribute reference such as "userName" or "name.givenName". Schema URI prefixes
+// are stripped, since every attribute we support belongs to the resource's core schema.
+type attrPath struct {
+	attr, sub string
+}
+
+func parseAttrPath(s string) (attrPath, error) {
+	if strings.HasPrefix(strings.ToLower(s), "urn:") {
+		i := strings.LastIndex(s, ":")
+		s = s[i+1:]
+	}
+	attr, sub, _ := strings.Cut(s, ".")
+	if !isAttrName(attr) || (sub != "" && !isAttrName(sub)) {
+		return attrPath{}, errors.Errorf("invalid attribute path %q", s)
+	}
+	return attrPath{attr: attr, sub: sub}, nil
+}
+
+func isAttrName(s string) bool {
+	if s == "" || !unicode.IsLetter(rune(s[0])) && s[0] != '$' {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '$' {
+			return false
+		}
+	}
+	return true
+}
+
+// values returns the values the path refers to. Multi-valued attributes contribute one value per
+// element, and elements of multi-valued complex attributes are represented by their "value"
+// sub-attribute when no sub-attribute is given.
+func (p attrPath) values(r map[string]any) []any {
+	var out []any
+	for _, v := range asList(lookup(r, p.attr)) {
+		sub := p.sub
+		if m, ok := v.(map[string]any); ok {
+			if sub == "" {
+				sub = "value"
+			}
+			v = lookup(m, sub)
+		} else if sub != "" {
+			continue
+		}
+		out = append(out, asList(v)...)
+	}
+	return out
+}
+
+func (p attrPath) caseExact() bool {
+	return p.sub == "" && (strings.EqualFold(p.attr, "id") || strings.EqualFold(p.attr, "externalId"))
+}
+
+// lookup returns the value of an attribute. Attribute names are case-insensitive.
+func lookup(m map[string]any, attr string) any {
+	if v, ok := m[attr]; ok {
+		return v
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, attr) {
+			return v
+		}
+	}
+	return nil
+}
+
+func asList(v any) []any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		return v
+	default:
+		return []any{v}
+	}
+}
+
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+func compareValue(op string, actual, expected any, caseExact bool) bool {
+	switch e := expected.(type) {
+	case string:
+		a, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		if !caseExact {
+			a, e = strings.ToLower(a), strings.ToLower(e)
+		}
+		switch op {
+		case "eq":
+			return a == e
+		case "co":
+			return strings.Contains(a, e)
+		case "sw":
+			return strings.HasPrefix(a, e)
+		case "ew":
+			return strings.HasSuffix(a, e)
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+	case bool:
+		a, ok := actual.(bool)
+		return ok && op == "eq" && a == e
+	case float64:
+		a, ok := actual.(float64)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "eq":
+			return a == e
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+	}
+	return false
+}
+
+// parseFilter parses a SCIM filter expression.
+func parseFilter(s string) (filter, error) {
+	p := &filterParser{tokens: tokenize(s)}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != "" {
+		return nil, errors.Errorf("unexpected %q in filter", tok)
+	}
+	return f, nil
+}
+
+type filterParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *filterParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *filterParser) next() string {
+	tok := p.peek()
+	if tok != "" {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *filterParser) expect(want string) error {
+	if tok := p.next(); tok != want {
+		if tok == "" {
+			return errors.Errorf("expected %q at end of filter", want)
+		}
+		return errors.Errorf("expected %q, got %q", want, tok)
+	}
+	return nil
+}
+
+func (p *filterParser) parseOr() (filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalFilter{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filter, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "and") {
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalFilter{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseTerm() (filter, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return nil, errors.New("unexpected end of filter")
+
+	case tok == "(":
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return f, p.expect(")")
+
+	case strings.EqualFold(tok, "not"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return &notFilter{filter: f}, p.expect(")")
+	}
+
+	if p.peek() == "[" {
+		p.next()
+		path, err := parseAttrPath(tok)
+		if err != nil || path.sub != "" {
+			return nil, errors.Errorf("invalid attribute %q in value filter", tok)
+		}
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return &valuePathFilter{attr: path.attr, filter: inner}, nil
+	}
+
+	path, err := parseAttrPath(tok)
+	if err != nil {
+		return nil, err
+	}
+	op := strings.ToLower(p.next())
+	switch op {
+	case "pr":
+		return &presentFilter{path: path}, nil
+	case "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le":
+	case "":
+		return nil, errors.Errorf("expected operator after %q", tok)
+	default:
+		return nil, errors.Errorf("unknown operator %q", op)
+	}
+
+	raw := p.next()
+	if raw == "" {
+		return nil, errors.Errorf("expected value after %q", op)
+	}
+	value, err := parseCompValue(raw)
+	if err != nil {
+		return nil, err
+	}
+	switch value.(type) {
+	case bool, nil:
+		if op != "eq" && op != "ne" {
+			return nil, errors.Errorf("operator %q cannot be used with %s", op, raw)
+		}
+	case float64:
+		if op == "co" || op == "sw" || op == "ew" {
+			return nil, errors.Errorf("operator %q cannot be used with a number", op)
+		}
+	}
+	return &compareFilter{path: path, op: op, value: value}, nil
+}
+
+func parseCompValue(raw string) (any, error) {
+	switch strings.ToLower(raw) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if strings.HasPrefix(raw, `"`) {
+		var s string
+		if err := json.Unmarshal([]byte(raw), &s); err != nil {
+			return nil, errors.Errorf("invalid string %s", raw)
+		}
+		return s, nil
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, errors.Errorf("invalid value %q", raw)
+	}
+	return f, nil
+}
+
+// tokenize splits a filter into parentheses, brackets, JSON string literals and words. Invalid
+// input such as an unterminated string is left for the parser to reject.
+func tokenize(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '[' || c == ']':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				tokens = append(tokens, s[i:])
+				return tokens
+			}
+			tokens = append(tokens, s[i:j+1])
+			i = j + 1
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t\r\n()[]\"", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
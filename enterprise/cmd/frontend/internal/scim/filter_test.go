@@ -0,0 +1,99 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func testUser(t *testing.T) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal([]byte(`{
+		"id": "42",
+		"externalId": "00u1ab2cd3",
+		"userName": "Alice@Example.com",
+		"name": {"givenName": "Alice", "familyName": "Liddell"},
+		"emails": [
+			{"value": "alice@example.com", "type": "work", "primary": true},
+			{"value": "alice@home.example", "type": "home"}
+		],
+		"active": true,
+		"meta": {"created": "2023-01-15T10:00:00Z", "lastModified": "2023-02-01T08:30:00Z"}
+	}`), &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestFilter(t *testing.T) {
+	user := testUser(t)
+
+	for filter, want := range map[string]bool{
+		`userName eq "alice@example.com"`:                                true, // case-insensitive
+		`USERNAME Eq "alice@example.com"`:                                true,
+		`userName ne "alice@example.com"`:                                false,
+		`userName sw "alice"`:                                            true,
+		`userName ew "@example.com"`:                                     true,
+		`userName co "@ex"`:                                              true,
+		`userName eq "bob@example.com"`:                                  false,
+		`externalId eq "00u1ab2cd3"`:                                     true,
+		`externalId eq "00U1AB2CD3"`:                                     false, // case-exact
+		`id eq "42"`:                                                     true,
+		`urn:ietf:params:scim:schemas:core:2.0:User:userName sw "Alice"`: true,
+		`name.givenName eq "Alice"`:                                      true,
+		`name.middleName pr`:                                             false,
+		`title pr`:                                                       false,
+		`emails pr`:                                                      true,
+		`emails co "home.example"`:                                       true,
+		`emails.type eq "home"`:                                          true,
+		`emails[type eq "work" and value co "@example.com"]`:             true,
+		`emails[type eq "home" and primary eq true]`:                     false,
+		`emails[not (type eq "work")]`:                                   true,
+		`active eq true`:                                                 true,
+		`active ne false`:                                                true,
+		`active eq false`:                                                false,
+		`title eq null`:                                                  true,
+		`userName eq null`:                                               false,
+		`meta.lastModified gt "2023-01-31T00:00:00Z"`:                    true,
+		`meta.created ge "2023-01-15T10:00:00Z"`:                         true,
+		`meta.created lt "2023-01-01T00:00:00Z"`:                         false,
+		`userName eq "bob" or name.givenName eq "Alice"`:                 true,
+		`userName eq "bob" or name.givenName eq "Bob"`:                   false,
+		`not (userName eq "bob") and active eq true`:                     true,
+		`userName eq "bob" and active eq true or id eq "42"`:             true, // and binds tighter than or
+		`userName eq "bob" and (active eq true or id eq "42")`:           false,
+		`displayName eq "Alice \"Al\" Liddell"`:                          false,
+	} {
+		f, err := parseFilter(filter)
+		if err != nil {
+			t.Errorf("parseFilter(%q): %s", filter, err)
+			continue
+		}
+		if got := f.match(user); got != want {
+			t.Errorf("%q: got %t, want %t", filter, got, want)
+		}
+	}
+}
+
+func TestFilter_Invalid(t *testing.T) {
+	for _, filter := range []string{
+		``,
+		`userName`,
+		`userName eq`,
+		`userName is "alice"`,
+		`userName eq "alice`,
+		`userName eq alice`,
+		`(userName eq "alice"`,
+		`userName eq "alice")`,
+		`active gt true`,
+		`userName co 3`,
+		`emails[type eq "work"`,
+		`not userName eq "alice"`,
+		`userName eq "alice" and`,
+		`1userName eq "alice"`,
+	} {
+		if _, err := parseFilter(filter); err == nil {
+			t.Errorf("parseFilter(%q): expected an error", filter)
+		}
+	}
+}
@@ -0,0 +1,493 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// group is a SCIM group, backed by an organization or a role.
+type group struct {
+	id           int32
+	displayName  string
+	created      time.Time
+	lastModified time.Time
+}
+
+// groupBackend stores groups. Which backend is used depends on the scim.groupsMapTo setting.
+type groupBackend interface {
+	list(ctx context.Context) ([]*group, error)
+	// get returns the group with the given ID, or nil if there is none.
+	get(ctx context.Context, id int32) (*group, error)
+	create(ctx context.Context, displayName string) (*group, error)
+	rename(ctx context.Context, id int32, displayName string) error
+	delete(ctx context.Context, id int32) error
+
+	members(ctx context.Context, id int32) ([]int32, error)
+	addMember(ctx context.Context, id, userID int32) error
+	removeMember(ctx context.Context, id, userID int32) error
+}
+
+func (h *handler) groups(db database.DB) groupBackend {
+	if h.config().GroupsMapTo == "roles" {
+		return roleGroups{db: db}
+	}
+	return orgGroups{db: db}
+}
+
+// orgGroups maps groups to organizations. The organization's name is derived from the group's
+// display name when the group is created, and its display name follows the group's afterwards.
+type orgGroups struct{ db database.DB }
+
+func (b orgGroups) toGroup(org *types.Org) *group {
+	g := &group{id: org.ID, displayName: org.Name, created: org.CreatedAt, lastModified: org.UpdatedAt}
+	if org.DisplayName != nil && *org.DisplayName != "" {
+		g.displayName = *org.DisplayName
+	}
+	return g
+}
+
+func (b orgGroups) list(ctx context.Context) ([]*group, error) {
+	orgs, err := b.db.Orgs().List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]*group, 0, len(orgs))
+	for _, org := range orgs {
+		groups = append(groups, b.toGroup(org))
+	}
+	return groups, nil
+}
+
+func (b orgGroups) get(ctx context.Context, id int32) (*group, error) {
+	org, err := b.db.Orgs().GetByID(ctx, id)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b.toGroup(org), nil
+}
+
+func (b orgGroups) create(ctx context.Context, displayName string) (*group, error) {
+	name, err := auth.NormalizeUsername(displayName)
+	if err != nil {
+		return nil, errInvalidValue(err.Error())
+	}
+	if err := checkNameAvailable(ctx, b.db, name, 0); err != nil {
+		return nil, err
+	}
+	org, err := b.db.Orgs().Create(ctx, name, &displayName)
+	if err != nil {
+		return nil, err
+	}
+	return b.toGroup(org), nil
+}
+
+func (b orgGroups) rename(ctx context.Context, id int32, displayName string) error {
+	_, err := b.db.Orgs().Update(ctx, id, &displayName)
+	return err
+}
+
+func (b orgGroups) delete(ctx context.Context, id int32) error {
+	return b.db.Orgs().Delete(ctx, id)
+}
+
+func (b orgGroups) members(ctx context.Context, id int32) ([]int32, error) {
+	memberships, err := b.db.OrgMembers().GetByOrgID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]int32, 0, len(memberships))
+	for _, m := range memberships {
+		userIDs = append(userIDs, m.UserID)
+	}
+	return userIDs, nil
+}
+
+func (b orgGroups) addMember(ctx context.Context, id, userID int32) error {
+	_, err := b.db.OrgMembers().Create(ctx, id, userID)
+	return err
+}
+
+func (b orgGroups) removeMember(ctx context.Context, id, userID int32) error {
+	return b.db.OrgMembers().Remove(ctx, id, userID)
+}
+
+// roleGroups maps groups to roles, named after the group's display name. System roles are not
+// groups, since they are assigned by Sourcegraph itself.
+type roleGroups struct{ db database.DB }
+
+func (b roleGroups) toGroup(role *types.Role) *group {
+	return &group{id: role.ID, displayName: role.Name, created: role.CreatedAt, lastModified: role.CreatedAt}
+}
+
+func (b roleGroups) list(ctx context.Context) ([]*group, error) {
+	roles, err := b.db.Roles().List(ctx, database.RolesListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]*group, 0, len(roles))
+	for _, role := range roles {
+		if !role.System {
+			groups = append(groups, b.toGroup(role))
+		}
+	}
+	return groups, nil
+}
+
+func (b roleGroups) get(ctx context.Context, id int32) (*group, error) {
+	role, err := b.db.Roles().Get(ctx, database.GetRoleOpts{ID: id})
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if role.System {
+		return nil, nil
+	}
+	return b.toGroup(role), nil
+}
+
+func (b roleGroups) checkNameAvailable(ctx context.Context, name string) error {
+	_, err := b.db.Roles().Get(ctx, database.GetRoleOpts{Name: name})
+	if errcode.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return errUniqueness("a role named " + strconv.Quote(name) + " already exists")
+}
+
+func (b roleGroups) create(ctx context.Context, displayName string) (*group, error) {
+	if err := b.checkNameAvailable(ctx, displayName); err != nil {
+		return nil, err
+	}
+	role, err := b.db.Roles().Create(ctx, displayName, false)
+	if err != nil {
+		return nil, err
+	}
+	return b.toGroup(role), nil
+}
+
+func (b roleGroups) rename(ctx context.Context, id int32, displayName string) error {
+	if err := b.checkNameAvailable(ctx, displayName); err != nil {
+		return err
+	}
+	_, err := b.db.Roles().Update(ctx, &types.Role{ID: id, Name: displayName})
+	return err
+}
+
+func (b roleGroups) delete(ctx context.Context, id int32) error {
+	return b.db.Roles().Delete(ctx, database.DeleteRoleOpts{ID: id})
+}
+
+func (b roleGroups) members(ctx context.Context, id int32) ([]int32, error) {
+	userRoles, err := b.db.UserRoles().GetByRoleID(ctx, database.GetUserRoleOpts{RoleID: id})
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]int32, 0, len(userRoles))
+	for _, ur := range userRoles {
+		userIDs = append(userIDs, ur.UserID)
+	}
+	return userIDs, nil
+}
+
+func (b roleGroups) addMember(ctx context.Context, id, userID int32) error {
+	_, err := b.db.UserRoles().Create(ctx, database.CreateUserRoleOpts{UserID: userID, RoleID: id})
+	return err
+}
+
+func (b roleGroups) removeMember(ctx context.Context, id, userID int32) error {
+	return b.db.UserRoles().Delete(ctx, database.DeleteUserRoleOpts{UserID: userID, RoleID: id})
+}
+
+// groupResource returns the SCIM representation of a group. members is nil if the members were not
+// requested.
+func (h *handler) groupResource(ctx context.Context, g *group, members []int32) (*groupResource, error) {
+	res := &groupResource{
+		Schemas:     []string{groupSchemaURI},
+		ID:          formatID(g.id),
+		DisplayName: g.displayName,
+		Meta: &meta{
+			ResourceType: "Group",
+			Created:      &g.created,
+			LastModified: &g.lastModified,
+			Location:     location("Groups", formatID(g.id)),
+		},
+	}
+	if len(members) == 0 {
+		return res, nil
+	}
+
+	users, err := h.db.Users().List(ctx, &database.UsersListOptions{UserIDs: members})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	for _, u := range users {
+		res.Members = append(res.Members, multiValue{
+			Value:   formatID(u.ID),
+			Display: u.Username,
+			Ref:     location("Users", formatID(u.ID)),
+		})
+	}
+	return res, nil
+}
+
+func (h *handler) listGroups(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	params, err := parseListParams(r)
+	if err != nil {
+		return err
+	}
+
+	backend := h.groups(h.db)
+	groups, err := backend.list(ctx)
+	if err != nil {
+		return err
+	}
+	// Identity providers usually exclude members when listing groups, since groups can be large.
+	withMembers := params.projection.includes("members")
+
+	resources := make([]map[string]any, 0, len(groups))
+	for _, g := range groups {
+		var members []int32
+		if withMembers {
+			if members, err = backend.members(ctx, g.id); err != nil {
+				return err
+			}
+		}
+		res, err := h.groupResource(ctx, g, members)
+		if err != nil {
+			return err
+		}
+		m, err := toMap(res)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, m)
+	}
+	writeJSON(w, http.StatusOK, params.page(resources))
+	return nil
+}
+
+// getGroupByURL returns the group with the ID in the URL.
+func getGroupByURL(ctx context.Context, backend groupBackend, r *http.Request) (*group, error) {
+	idStr := mux.Vars(r)["id"]
+	id, ok := parseID(idStr)
+	if !ok {
+		return nil, errNotFound("Group", idStr)
+	}
+	g, err := backend.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return nil, errNotFound("Group", idStr)
+	}
+	return g, nil
+}
+
+func (h *handler) getGroup(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	backend := h.groups(h.db)
+	g, err := getGroupByURL(ctx, backend, r)
+	if err != nil {
+		return err
+	}
+	return h.writeGroup(w, r, http.StatusOK, backend, g)
+}
+
+func (h *handler) writeGroup(w http.ResponseWriter, r *http.Request, status int, backend groupBackend, g *group) error {
+	ctx := r.Context()
+	proj := parseProjection(r)
+
+	var members []int32
+	if proj.includes("members") {
+		var err error
+		if members, err = backend.members(ctx, g.id); err != nil {
+			return err
+		}
+	}
+	res, err := h.groupResource(ctx, g, members)
+	if err != nil {
+		return err
+	}
+	m, err := toMap(res)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusCreated {
+		w.Header().Set("Location", res.Meta.Location)
+	}
+	writeJSON(w, status, proj.apply(m))
+	return nil
+}
+
+func (h *handler) createGroup(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	var req groupResource
+	if err := decodeBody(r, &req); err != nil {
+		return err
+	}
+	req.DisplayName = strings.TrimSpace(req.DisplayName)
+	if req.DisplayName == "" {
+		return errInvalidValue("displayName is required")
+	}
+
+	var g *group
+	err := h.db.WithTransact(ctx, func(tx database.DB) (err error) {
+		backend := h.groups(tx)
+		if g, err = backend.create(ctx, req.DisplayName); err != nil {
+			return err
+		}
+		return h.syncMembers(ctx, tx, backend, g.id, nil, req.Members)
+	})
+	if err != nil {
+		return err
+	}
+	return h.writeGroup(w, r, http.StatusCreated, h.groups(h.db), g)
+}
+
+func (h *handler) replaceGroup(w http.ResponseWriter, r *http.Request) error {
+	var req groupResource
+	if err := decodeBody(r, &req); err != nil {
+		return err
+	}
+	return h.updateGroup(w, r, func(*groupResource) (*groupResource, error) { return &req, nil })
+}
+
+func (h *handler) patchGroup(w http.ResponseWriter, r *http.Request) error {
+	var req patchRequest
+	if err := decodeBody(r, &req); err != nil {
+		return err
+	}
+	return h.updateGroup(w, r, func(old *groupResource) (*groupResource, error) {
+		m, err := toMap(old)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyPatch(m, req.Operations); err != nil {
+			return nil, err
+		}
+		var g groupResource
+		if err := fromMap(m, &g); err != nil {
+			return nil, err
+		}
+		return &g, nil
+	})
+}
+
+// updateGroup replaces the group with the ID in the URL by the result of update.
+func (h *handler) updateGroup(w http.ResponseWriter, r *http.Request, update func(old *groupResource) (*groupResource, error)) error {
+	ctx := r.Context()
+
+	var g *group
+	err := h.db.WithTransact(ctx, func(tx database.DB) (err error) {
+		backend := h.groups(tx)
+		if g, err = getGroupByURL(ctx, backend, r); err != nil {
+			return err
+		}
+		members, err := backend.members(ctx, g.id)
+		if err != nil {
+			return err
+		}
+		old := &groupResource{DisplayName: g.displayName}
+		for _, id := range members {
+			old.Members = append(old.Members, multiValue{Value: formatID(id)})
+		}
+
+		updated, err := update(old)
+		if err != nil {
+			return err
+		}
+		updated.DisplayName = strings.TrimSpace(updated.DisplayName)
+		if updated.DisplayName == "" {
+			return errInvalidValue("displayName is required")
+		}
+		if updated.DisplayName != g.displayName {
+			if err := backend.rename(ctx, g.id, updated.DisplayName); err != nil {
+				return err
+			}
+		}
+		return h.syncMembers(ctx, tx, backend, g.id, members, updated.Members)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Read the group again to return its new display name.
+	backend := h.groups(h.db)
+	if g, err = backend.get(ctx, g.id); err != nil {
+		return err
+	}
+	return h.writeGroup(w, r, http.StatusOK, backend, g)
+}
+
+// syncMembers makes the members of the group the users referenced by want.
+func (h *handler) syncMembers(ctx context.Context, tx database.DB, backend groupBackend, groupID int32, current []int32, want []multiValue) error {
+	wantIDs := make(map[int32]bool, len(want))
+	for _, m := range want {
+		id, ok := parseID(m.Value)
+		if !ok {
+			return errInvalidValue("invalid member " + strconv.Quote(m.Value))
+		}
+		wantIDs[id] = true
+	}
+	have := make(map[int32]bool, len(current))
+	for _, id := range current {
+		have[id] = true
+	}
+
+	for id := range wantIDs {
+		if have[id] {
+			continue
+		}
+		if _, err := tx.Users().GetByID(ctx, id); errcode.IsNotFound(err) {
+			return errInvalidValue("member " + formatID(id) + " is not a user")
+		} else if err != nil {
+			return err
+		}
+		if err := backend.addMember(ctx, groupID, id); err != nil {
+			return err
+		}
+	}
+	for id := range have {
+		if !wantIDs[id] {
+			if err := backend.removeMember(ctx, groupID, id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (h *handler) deleteGroup(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	backend := h.groups(h.db)
+	g, err := getGroupByURL(ctx, backend, r)
+	if err != nil {
+		return err
+	}
+	if err := backend.delete(ctx, g.id); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
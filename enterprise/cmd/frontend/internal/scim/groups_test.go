@@ -0,0 +1,147 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+func TestGroups(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		groupsMapTo string
+		// members returns the IDs of the users in the organization or role backing a group.
+		members func(t *testing.T, db database.DB, groupID int32) []int32
+	}{
+		{
+			groupsMapTo: "orgs",
+			members: func(t *testing.T, db database.DB, groupID int32) []int32 {
+				org, err := db.Orgs().GetByID(ctx, groupID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if org.Name != "Engineering" {
+					t.Errorf("unexpected organization name %q", org.Name)
+				}
+				memberships, err := db.OrgMembers().GetByOrgID(ctx, groupID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var ids []int32
+				for _, m := range memberships {
+					ids = append(ids, m.UserID)
+				}
+				return ids
+			},
+		},
+		{
+			groupsMapTo: "roles",
+			members: func(t *testing.T, db database.DB, groupID int32) []int32 {
+				userRoles, err := db.UserRoles().GetByRoleID(ctx, database.GetUserRoleOpts{RoleID: groupID})
+				if err != nil {
+					t.Fatal(err)
+				}
+				var ids []int32
+				for _, ur := range userRoles {
+					ids = append(ids, ur.UserID)
+				}
+				return ids
+			},
+		},
+	} {
+		t.Run(tc.groupsMapTo, func(t *testing.T) {
+			h, db := newTestHandler(t, tc.groupsMapTo)
+
+			aliceID := createTestUser(t, h, "alice@example.com", "alice@example.com")
+			bobID := createTestUser(t, h, "bob@example.com", "bob@example.com")
+			alice, _ := parseID(aliceID)
+			bob, _ := parseID(bobID)
+
+			wantMembers := func(t *testing.T, groupID string, want ...int32) {
+				t.Helper()
+				id, _ := parseID(groupID)
+				have := tc.members(t, db, id)
+				sort.Slice(have, func(i, j int) bool { return have[i] < have[j] })
+				if diff := cmp.Diff(want, have); diff != "" {
+					t.Errorf("unexpected members (-want +got):\n%s", diff)
+				}
+			}
+
+			status, res := do(t, h, "POST", "/Groups", `{
+				"schemas": ["urn:ietf:params:scim:schemas:core:2.0:Group"],
+				"displayName": "Engineering",
+				"members": [{"value": "`+aliceID+`"}]
+			}`)
+			if status != http.StatusCreated {
+				t.Fatalf("unexpected response creating group: %d %v", status, res)
+			}
+			groupID := res["id"].(string)
+			wantMembers(t, groupID, alice)
+
+			t.Run("create with taken name", func(t *testing.T) {
+				status, res := do(t, h, "POST", "/Groups", `{"displayName": "Engineering"}`)
+				if status != http.StatusConflict || res["scimType"] != "uniqueness" {
+					t.Errorf("unexpected response %d %v", status, res)
+				}
+			})
+
+			t.Run("invalid member", func(t *testing.T) {
+				status, res := do(t, h, "PATCH", "/Groups/"+groupID, `{"Operations": [{"op": "add", "path": "members", "value": [{"value": "999999"}]}]}`)
+				if status != http.StatusBadRequest || res["scimType"] != "invalidValue" {
+					t.Errorf("unexpected response %d %v", status, res)
+				}
+				wantMembers(t, groupID, alice)
+			})
+
+			t.Run("patch members", func(t *testing.T) {
+				status, res := do(t, h, "PATCH", "/Groups/"+groupID, `{"Operations": [
+					{"op": "add", "path": "members", "value": [{"value": "`+bobID+`"}]},
+					{"op": "remove", "path": "members[value eq \"`+aliceID+`\"]"}
+				]}`)
+				if status != http.StatusOK {
+					t.Fatalf("unexpected response %d %v", status, res)
+				}
+				wantMembers(t, groupID, bob)
+			})
+
+			t.Run("replace", func(t *testing.T) {
+				status, res := do(t, h, "PUT", "/Groups/"+groupID, `{"displayName": "Platform", "members": [{"value": "`+aliceID+`"}, {"value": "`+bobID+`"}]}`)
+				if status != http.StatusOK || res["displayName"] != "Platform" {
+					t.Fatalf("unexpected response %d %v", status, res)
+				}
+				wantMembers(t, groupID, alice, bob)
+			})
+
+			t.Run("list", func(t *testing.T) {
+				status, res := do(t, h, "GET", "/Groups?excludedAttributes=members&filter="+url.QueryEscape(`displayName eq "platform"`), "")
+				if status != http.StatusOK || res["totalResults"] != float64(1) {
+					t.Fatalf("unexpected response %d %v", status, res)
+				}
+				group := res["Resources"].([]any)[0].(map[string]any)
+				if group["id"] != groupID || group["members"] != nil {
+					t.Errorf("unexpected group %v", group)
+				}
+			})
+
+			t.Run("delete", func(t *testing.T) {
+				if status, res := do(t, h, "DELETE", "/Groups/"+groupID, ""); status != http.StatusNoContent {
+					t.Fatalf("unexpected response %d %v", status, res)
+				}
+				if status, _ := do(t, h, "GET", "/Groups/"+groupID, ""); status != http.StatusNotFound {
+					t.Errorf("unexpected status %d getting deleted group", status)
+				}
+			})
+		})
+	}
+}
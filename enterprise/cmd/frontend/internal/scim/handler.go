@@ -0,0 +1,200 @@
+package scim
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgconn"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// pathPrefix is where the SCIM endpoint is mounted.
+const pathPrefix = "/.api/scim/v2"
+
+// maxRequestSize caps request bodies. SCIM requests are small; group updates with many members
+// are the largest.
+const maxRequestSize = 10 << 20
+
+type handler struct {
+	logger log.Logger
+	db     database.DB
+}
+
+// NewHandler returns the handler of the SCIM 2.0 endpoint (RFC 7644).
+//
+// 🚨 SECURITY: The handler authenticates requests itself, with the token in the scim.authToken site
+// configuration setting, and acts as an internal actor.
+func NewHandler(logger log.Logger, db database.DB) http.Handler {
+	h := &handler{logger: logger, db: db}
+
+	r := mux.NewRouter().PathPrefix(pathPrefix).Subrouter()
+	r.StrictSlash(true)
+
+	r.Path("/ServiceProviderConfig").Methods("GET").Handler(h.serve(h.getServiceProviderConfig))
+	r.Path("/ResourceTypes").Methods("GET").Handler(h.serve(h.listResourceTypes))
+	r.Path("/ResourceTypes/{id}").Methods("GET").Handler(h.serve(h.getResourceType))
+	r.Path("/Schemas").Methods("GET").Handler(h.serve(h.listSchemas))
+	r.Path("/Schemas/{id}").Methods("GET").Handler(h.serve(h.getSchema))
+
+	r.Path("/Users").Methods("GET").Handler(h.serve(h.listUsers))
+	r.Path("/Users").Methods("POST").Handler(h.serve(h.createUser))
+	r.Path("/Users/{id}").Methods("GET").Handler(h.serve(h.getUser))
+	r.Path("/Users/{id}").Methods("PUT").Handler(h.serve(h.replaceUser))
+	r.Path("/Users/{id}").Methods("PATCH").Handler(h.serve(h.patchUser))
+	r.Path("/Users/{id}").Methods("DELETE").Handler(h.serve(h.deleteUser))
+
+	r.Path("/Groups").Methods("GET").Handler(h.serve(h.listGroups))
+	r.Path("/Groups").Methods("POST").Handler(h.serve(h.createGroup))
+	r.Path("/Groups/{id}").Methods("GET").Handler(h.serve(h.getGroup))
+	r.Path("/Groups/{id}").Methods("PUT").Handler(h.serve(h.replaceGroup))
+	r.Path("/Groups/{id}").Methods("PATCH").Handler(h.serve(h.patchGroup))
+	r.Path("/Groups/{id}").Methods("DELETE").Handler(h.serve(h.deleteGroup))
+
+	r.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusNotFound, newError(http.StatusNotFound, "", "no such endpoint"))
+	})
+	r.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusMethodNotAllowed, newError(http.StatusMethodNotAllowed, "", r.Method+" is not supported for this endpoint"))
+	})
+
+	return h.authenticate(r)
+}
+
+// authenticate rejects requests unless SCIM is configured and licensed and the request has the
+// configured bearer token.
+func (h *handler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cfg := conf.Get().Scim
+		if cfg == nil || cfg.AuthToken == "" {
+			writeJSON(w, http.StatusNotFound, newError(http.StatusNotFound, "", "SCIM provisioning is not enabled"))
+			return
+		}
+		if err := licensing.Check(licensing.FeatureSSO); err != nil {
+			writeJSON(w, http.StatusForbidden, newError(http.StatusForbidden, "", err.Error()))
+			return
+		}
+
+		scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(cfg.AuthToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="SCIM"`)
+			writeJSON(w, http.StatusUnauthorized, newError(http.StatusUnauthorized, "", "invalid or missing bearer token"))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(actor.WithInternalActor(r.Context())))
+	})
+}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// serve adapts a handler that returns an error. SCIM errors are returned to the client as is; any
+// other error is logged and reported as an internal server error.
+func (h *handler) serve(fn handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := fn(w, r)
+		if err == nil {
+			return
+		}
+		var e *scimError
+		if errors.As(err, &e) {
+			writeJSON(w, e.status, e)
+			return
+		}
+		h.logger.Error("SCIM request failed", log.String("method", r.Method), log.String("path", r.URL.Path), log.Error(err))
+		writeJSON(w, http.StatusInternalServerError, newError(http.StatusInternalServerError, "", "internal error"))
+	})
+}
+
+// config returns the current SCIM configuration. authenticate ensures it is set.
+func (h *handler) config() *schema.Scim {
+	if cfg := conf.Get().Scim; cfg != nil {
+		return cfg
+	}
+	return &schema.Scim{}
+}
+
+func decodeBody(r *http.Request, v any) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return newError(http.StatusBadRequest, "invalidSyntax", "invalid request body: "+err.Error())
+	}
+	return nil
+}
+
+// toMap returns the JSON representation of a resource, as used by filters and patches.
+func toMap(resource any) (map[string]any, error) {
+	b, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	return m, json.Unmarshal(b, &m)
+}
+
+// fromMap converts the JSON representation of a resource back to the resource.
+func fromMap(m map[string]any, resource any) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, resource); err != nil {
+		return errInvalidValue(err.Error())
+	}
+	return nil
+}
+
+func formatID(id int32) string {
+	return strconv.Itoa(int(id))
+}
+
+// parseID parses the ID of a user or group, as used in URLs and member references.
+func parseID(s string) (int32, bool) {
+	id, err := strconv.ParseInt(s, 10, 32)
+	return int32(id), err == nil && id > 0
+}
+
+// location returns the URL of a resource, or of an endpoint if id is empty.
+func location(endpoint, id string) string {
+	u := strings.TrimSuffix(conf.ExternalURL(), "/") + pathPrefix + "/" + endpoint
+	if id != "" {
+		u += "/" + id
+	}
+	return u
+}
+
+func isUniqueViolation(err error) bool {
+	var e *pgconn.PgError
+	return errors.As(err, &e) && e.Code == "23505"
+}
+
+// checkNameAvailable returns a uniqueness error if name is taken by another user than userID or by
+// an organization. Users and organizations share a namespace.
+func checkNameAvailable(ctx context.Context, db database.DB, name string, userID int32) error {
+	ns, err := db.Namespaces().GetByName(ctx, name)
+	if err == database.ErrNamespaceNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if userID != 0 && ns.User == userID {
+		return nil
+	}
+	return errUniqueness("the name " + strconv.Quote(name) + " is already taken by another user or organization")
+}
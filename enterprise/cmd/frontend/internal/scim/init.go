@@ -0,0 +1,28 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// Init registers the SCIM provisioning endpoint.
+func Init(
+	_ context.Context,
+	_ *observation.Context,
+	db database.DB,
+	_ codeintel.Services,
+	_ conftypes.UnifiedWatchable,
+	enterpriseServices *enterprise.Services,
+) error {
+	logger := log.Scoped("scim", "SCIM user and group provisioning")
+	enterpriseServices.NewSCIMHandler = func() http.Handler { return NewHandler(logger, db) }
+	return nil
+}
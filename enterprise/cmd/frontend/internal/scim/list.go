@@ -0,0 +1,154 @@
+package scim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultCount is the page size when the client doesn't ask for one.
+	defaultCount = 100
+	// maxCount is the largest page size, advertised as filter.maxResults.
+	maxCount = 1000
+)
+
+// listParams are the query parameters of list requests (RFC 7644 section 3.4.2).
+type listParams struct {
+	filter     filter // may be nil
+	startIndex int    // 1-based
+	count      int
+	projection projection
+}
+
+func parseListParams(r *http.Request) (*listParams, error) {
+	q := r.URL.Query()
+	p := &listParams{startIndex: 1, count: defaultCount, projection: parseProjection(r)}
+
+	if s := q.Get("filter"); s != "" {
+		f, err := parseFilter(s)
+		if err != nil {
+			return nil, newError(http.StatusBadRequest, "invalidFilter", err.Error())
+		}
+		p.filter = f
+	}
+
+	if s := q.Get("startIndex"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, errInvalidValue("startIndex must be an integer")
+		}
+		// Values less than 1 are interpreted as 1.
+		if n > 1 {
+			p.startIndex = n
+		}
+	}
+
+	if s := q.Get("count"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, errInvalidValue("count must be an integer")
+		}
+		// Negative values are interpreted as 0, which only returns totalResults.
+		switch {
+		case n < 0:
+			p.count = 0
+		case n > maxCount:
+			p.count = maxCount
+		default:
+			p.count = n
+		}
+	}
+
+	return p, nil
+}
+
+// page filters the resources and returns the requested page of them.
+func (p *listParams) page(resources []map[string]any) *listResponse {
+	matched := resources
+	if p.filter != nil {
+		matched = make([]map[string]any, 0, len(resources))
+		for _, r := range resources {
+			if p.filter.match(r) {
+				matched = append(matched, r)
+			}
+		}
+	}
+
+	start := p.startIndex - 1
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := start + p.count
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	return p.response(matched[start:end], len(matched))
+}
+
+// response returns the list response for a page of resources, out of total matching resources.
+func (p *listParams) response(page []map[string]any, total int) *listResponse {
+	items := make([]any, 0, len(page))
+	for _, r := range page {
+		items = append(items, p.projection.apply(r))
+	}
+	return &listResponse{
+		Schemas:      []string{listResponseSchemaURI},
+		TotalResults: total,
+		StartIndex:   p.startIndex,
+		ItemsPerPage: len(items),
+		Resources:    items,
+	}
+}
+
+// projection is the set of attributes to return, from the attributes and excludedAttributes query
+// parameters (RFC 7644 section 3.4.2.5). Only top-level attributes are considered; a sub-attribute
+// selects or excludes its parent attribute.
+type projection struct {
+	attributes map[string]bool
+	excluded   map[string]bool
+}
+
+func parseProjection(r *http.Request) projection {
+	parse := func(s string) map[string]bool {
+		if s == "" {
+			return nil
+		}
+		attrs := map[string]bool{}
+		for _, a := range strings.Split(s, ",") {
+			path, err := parseAttrPath(strings.TrimSpace(a))
+			if err == nil {
+				attrs[strings.ToLower(path.attr)] = true
+			}
+		}
+		return attrs
+	}
+	q := r.URL.Query()
+	return projection{attributes: parse(q.Get("attributes")), excluded: parse(q.Get("excludedAttributes"))}
+}
+
+// includes reports whether an attribute is returned.
+func (p projection) includes(attr string) bool {
+	attr = strings.ToLower(attr)
+	if attr == "id" || attr == "schemas" {
+		return true
+	}
+	if p.attributes != nil {
+		return p.attributes[attr]
+	}
+	return !p.excluded[attr]
+}
+
+func (p projection) apply(resource map[string]any) map[string]any {
+	if p.attributes == nil && p.excluded == nil {
+		return resource
+	}
+	out := make(map[string]any, len(resource))
+	for k, v := range resource {
+		if p.includes(k) {
+			out[k] = v
+		}
+	}
+	return out
+}
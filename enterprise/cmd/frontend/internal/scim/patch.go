@@ -0,0 +1,346 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// patchOperation is a single operation of a PATCH request (RFC 7644 section 3.5.2).
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// patchPath is the target of a patch operation: an attribute, optionally narrowed to the elements
+// of a multi-valued attribute that match a filter, optionally followed by a sub-attribute. For
+// example, `emails[type eq "work"].value`.
+type patchPath struct {
+	attr   string
+	filter filter // may be nil
+	sub    string
+}
+
+func parsePatchPath(s string) (*patchPath, error) {
+	open := strings.Index(s, "[")
+	if open == -1 {
+		p, err := parseAttrPath(s)
+		if err != nil {
+			return nil, newError(http.StatusBadRequest, "invalidPath", err.Error())
+		}
+		return &patchPath{attr: p.attr, sub: p.sub}, nil
+	}
+
+	end := strings.LastIndex(s, "]")
+	if end < open {
+		return nil, newError(http.StatusBadRequest, "invalidPath", "unterminated value filter in path "+s)
+	}
+	attr, err := parseAttrPath(s[:open])
+	if err != nil || attr.sub != "" {
+		return nil, newError(http.StatusBadRequest, "invalidPath", "invalid path "+s)
+	}
+	f, err := parseFilter(s[open+1 : end])
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "invalidFilter", err.Error())
+	}
+	p := &patchPath{attr: attr.attr, filter: f}
+	if rest := s[end+1:]; rest != "" {
+		if !strings.HasPrefix(rest, ".") || !isAttrName(rest[1:]) {
+			return nil, newError(http.StatusBadRequest, "invalidPath", "invalid path "+s)
+		}
+		p.sub = rest[1:]
+	}
+	return p, nil
+}
+
+// applyPatch applies the operations to the JSON representation of a resource, in order.
+func applyPatch(resource map[string]any, ops []patchOperation) error {
+	for _, op := range ops {
+		if err := applyOperation(resource, op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyOperation(resource map[string]any, op patchOperation) error {
+	kind := strings.ToLower(op.Op)
+	if kind != "add" && kind != "remove" && kind != "replace" {
+		return errInvalidValue("unsupported patch operation " + op.Op)
+	}
+
+	var value any
+	if len(op.Value) > 0 {
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return errInvalidValue("invalid patch value: " + err.Error())
+		}
+	}
+
+	if op.Path == "" {
+		if kind == "remove" {
+			return newError(http.StatusBadRequest, "noTarget", "remove operations require a path")
+		}
+		// Without a path, the value is an object whose attributes are added or replaced.
+		attrs, ok := value.(map[string]any)
+		if !ok {
+			return errInvalidValue("the value of a patch operation without a path must be an object")
+		}
+		for k, v := range attrs {
+			path, err := parsePatchPath(k)
+			if err != nil {
+				return err
+			}
+			if err := applyAtPath(resource, kind, path, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	path, err := parsePatchPath(op.Path)
+	if err != nil {
+		return err
+	}
+	return applyAtPath(resource, kind, path, value)
+}
+
+func applyAtPath(resource map[string]any, kind string, path *patchPath, value any) error {
+	if kind != "remove" && value == nil {
+		return errInvalidValue("a value is required for " + kind + " operations")
+	}
+
+	current := lookup(resource, path.attr)
+
+	if path.filter != nil {
+		return applyFiltered(resource, kind, path, value)
+	}
+
+	if path.sub != "" {
+		// A sub-attribute of a complex attribute (name.givenName), or of every element of a
+		// multi-valued one.
+		switch c := current.(type) {
+		case []any:
+			for _, elem := range c {
+				if m, ok := elem.(map[string]any); ok {
+					setOrDelete(m, kind, path.sub, value)
+				}
+			}
+		case map[string]any:
+			setOrDelete(c, kind, path.sub, value)
+		case nil:
+			if kind != "remove" {
+				setKey(resource, path.attr, map[string]any{path.sub: value})
+			}
+		default:
+			return newError(http.StatusBadRequest, "invalidPath", path.attr+" has no sub-attributes")
+		}
+		return nil
+	}
+
+	switch kind {
+	case "add":
+		// Adding to a multi-valued attribute appends the new values.
+		if list, ok := current.([]any); ok {
+			setKey(resource, path.attr, appendUnique(list, asList(value)...))
+			return nil
+		}
+		// Adding to a complex attribute merges the sub-attributes.
+		if m, ok := current.(map[string]any); ok {
+			if v, ok := value.(map[string]any); ok {
+				for k, sv := range v {
+					setKey(m, k, sv)
+				}
+				return nil
+			}
+		}
+		setKey(resource, path.attr, value)
+
+	case "replace":
+		setKey(resource, path.attr, value)
+
+	case "remove":
+		// Some clients remove elements of a multi-valued attribute by passing them as the value
+		// rather than with a filter, e.g. {"op": "remove", "path": "members", "value": [...]}.
+		if list, ok := current.([]any); ok && value != nil {
+			setKey(resource, path.attr, removeValues(list, asList(value)))
+			return nil
+		}
+		deleteKey(resource, path.attr)
+	}
+	return nil
+}
+
+func applyFiltered(resource map[string]any, kind string, path *patchPath, value any) error {
+	list := asList(lookup(resource, path.attr))
+
+	var matched int
+	out := make([]any, 0, len(list))
+	for _, elem := range list {
+		m, ok := elem.(map[string]any)
+		if !ok || !path.filter.match(m) {
+			out = append(out, elem)
+			continue
+		}
+		matched++
+
+		switch {
+		case kind == "remove" && path.sub == "":
+			continue // drop the element
+		case path.sub != "":
+			setOrDelete(m, kind, path.sub, value)
+		default:
+			v, ok := value.(map[string]any)
+			if !ok {
+				return errInvalidValue("the value for " + path.attr + " must be an object")
+			}
+			if kind == "replace" {
+				m = map[string]any{}
+			}
+			for k, sv := range v {
+				setKey(m, k, sv)
+			}
+		}
+		out = append(out, m)
+	}
+
+	if matched == 0 {
+		if kind == "remove" {
+			return nil
+		}
+		// Identity providers set e.g. `emails[type eq "work"].value` to add a work email when the
+		// user doesn't have one yet, so create the element the filter describes.
+		elem, ok := elementForFilter(path.filter)
+		if !ok {
+			return newError(http.StatusBadRequest, "noTarget", "no values of "+path.attr+" match the filter")
+		}
+		if path.sub != "" {
+			elem[path.sub] = value
+		} else if v, ok := value.(map[string]any); ok {
+			for k, sv := range v {
+				setKey(elem, k, sv)
+			}
+		} else {
+			return errInvalidValue("the value for " + path.attr + " must be an object")
+		}
+		out = append(out, elem)
+	}
+
+	setKey(resource, path.attr, out)
+	return nil
+}
+
+// elementForFilter returns the element described by a filter that consists only of equality
+// comparisons joined by "and", such as `type eq "work"`.
+func elementForFilter(f filter) (map[string]any, bool) {
+	switch f := f.(type) {
+	case *compareFilter:
+		if f.op != "eq" || f.path.sub != "" || f.value == nil {
+			return nil, false
+		}
+		return map[string]any{f.path.attr: f.value}, true
+	case *logicalFilter:
+		if !f.and {
+			return nil, false
+		}
+		left, ok := elementForFilter(f.left)
+		if !ok {
+			return nil, false
+		}
+		right, ok := elementForFilter(f.right)
+		if !ok {
+			return nil, false
+		}
+		for k, v := range right {
+			left[k] = v
+		}
+		return left, true
+	}
+	return nil, false
+}
+
+func setOrDelete(m map[string]any, kind, attr string, value any) {
+	if kind == "remove" {
+		deleteKey(m, attr)
+	} else {
+		setKey(m, attr, value)
+	}
+}
+
+// setKey sets an attribute, reusing the existing key if it only differs in case.
+func setKey(m map[string]any, attr string, value any) {
+	for k := range m {
+		if strings.EqualFold(k, attr) {
+			m[k] = value
+			return
+		}
+	}
+	m[attr] = value
+}
+
+func deleteKey(m map[string]any, attr string) {
+	for k := range m {
+		if strings.EqualFold(k, attr) {
+			delete(m, k)
+		}
+	}
+}
+
+// valueOf returns the "value" sub-attribute of an element of a multi-valued attribute, or the
+// element itself if it is not complex.
+func valueOf(elem any) any {
+	if m, ok := elem.(map[string]any); ok {
+		return lookup(m, "value")
+	}
+	return elem
+}
+
+func appendUnique(list []any, values ...any) []any {
+	for _, v := range values {
+		dup := false
+		for _, existing := range list {
+			if sameValue(valueOf(existing), valueOf(v)) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func removeValues(list, values []any) []any {
+	out := make([]any, 0, len(list))
+	for _, elem := range list {
+		remove := false
+		for _, v := range values {
+			if sameValue(valueOf(elem), valueOf(v)) {
+				remove = true
+				break
+			}
+		}
+		if !remove {
+			out = append(out, elem)
+		}
+	}
+	return out
+}
+
+// sameValue reports whether two simple JSON values are equal. Complex and missing values are never
+// equal to anything.
+func sameValue(a, b any) bool {
+	switch a := a.(type) {
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	case float64:
+		b, ok := b.(float64)
+		return ok && a == b
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApplyPatch(t *testing.T) {
+	const user = `{
+		"userName": "alice",
+		"name": {"givenName": "Alice", "familyName": "Liddell"},
+		"emails": [
+			{"value": "alice@example.com", "type": "work", "primary": true},
+			{"value": "alice@home.example", "type": "home"}
+		],
+		"active": true
+	}`
+	const group = `{
+		"displayName": "Engineering",
+		"members": [{"value": "1"}, {"value": "2"}]
+	}`
+
+	for _, tc := range []struct {
+		name     string
+		resource string
+		ops      string
+		want     string
+	}{
+		{
+			name:     "replace without path",
+			resource: user,
+			ops:      `[{"op": "replace", "value": {"active": false, "name.givenName": "Alicia"}}]`,
+			want: `{
+				"userName": "alice",
+				"name": {"givenName": "Alicia", "familyName": "Liddell"},
+				"emails": [
+					{"value": "alice@example.com", "type": "work", "primary": true},
+					{"value": "alice@home.example", "type": "home"}
+				],
+				"active": false
+			}`,
+		},
+		{
+			name:     "replace attribute with different case",
+			resource: user,
+			ops:      `[{"op": "Replace", "path": "USERNAME", "value": "alice2"}]`,
+			want: `{
+				"userName": "alice2",
+				"name": {"givenName": "Alice", "familyName": "Liddell"},
+				"emails": [
+					{"value": "alice@example.com", "type": "work", "primary": true},
+					{"value": "alice@home.example", "type": "home"}
+				],
+				"active": true
+			}`,
+		},
+		{
+			name:     "replace sub-attribute of filtered value",
+			resource: user,
+			ops:      `[{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "alice@corp.example"}]`,
+			want: `{
+				"userName": "alice",
+				"name": {"givenName": "Alice", "familyName": "Liddell"},
+				"emails": [
+					{"value": "alice@corp.example", "type": "work", "primary": true},
+					{"value": "alice@home.example", "type": "home"}
+				],
+				"active": true
+			}`,
+		},
+		{
+			name:     "add value that matches no element",
+			resource: `{"userName": "bob"}`,
+			ops:      `[{"op": "add", "path": "emails[type eq \"work\"].value", "value": "bob@example.com"}]`,
+			want:     `{"userName": "bob", "emails": [{"type": "work", "value": "bob@example.com"}]}`,
+		},
+		{
+			name:     "remove filtered value",
+			resource: user,
+			ops:      `[{"op": "remove", "path": "emails[type eq \"home\"]"}]`,
+			want: `{
+				"userName": "alice",
+				"name": {"givenName": "Alice", "familyName": "Liddell"},
+				"emails": [{"value": "alice@example.com", "type": "work", "primary": true}],
+				"active": true
+			}`,
+		},
+		{
+			name:     "remove attribute",
+			resource: user,
+			ops:      `[{"op": "remove", "path": "name"}, {"op": "remove", "path": "emails"}]`,
+			want:     `{"userName": "alice", "active": true}`,
+		},
+		{
+			name:     "add sub-attribute",
+			resource: `{"userName": "bob"}`,
+			ops:      `[{"op": "add", "path": "name.familyName", "value": "Builder"}]`,
+			want:     `{"userName": "bob", "name": {"familyName": "Builder"}}`,
+		},
+		{
+			name:     "add members",
+			resource: group,
+			ops:      `[{"op": "add", "path": "members", "value": [{"value": "2"}, {"value": "3"}]}]`,
+			want:     `{"displayName": "Engineering", "members": [{"value": "1"}, {"value": "2"}, {"value": "3"}]}`,
+		},
+		{
+			name:     "remove member with filter",
+			resource: group,
+			ops:      `[{"op": "remove", "path": "members[value eq \"1\"]"}]`,
+			want:     `{"displayName": "Engineering", "members": [{"value": "2"}]}`,
+		},
+		{
+			name:     "remove members by value",
+			resource: group,
+			ops:      `[{"op": "remove", "path": "members", "value": [{"value": "2"}, {"value": "9"}]}]`,
+			want:     `{"displayName": "Engineering", "members": [{"value": "1"}]}`,
+		},
+		{
+			name:     "replace members and rename",
+			resource: group,
+			ops: `[
+				{"op": "replace", "path": "members", "value": [{"value": "5"}]},
+				{"op": "replace", "value": {"displayName": "Platform"}}
+			]`,
+			want: `{"displayName": "Platform", "members": [{"value": "5"}]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var resource, want map[string]any
+			var ops []patchOperation
+			for raw, v := range map[string]any{tc.resource: &resource, tc.want: &want, tc.ops: &ops} {
+				if err := json.Unmarshal([]byte(raw), v); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := applyPatch(resource, ops); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, resource); diff != "" {
+				t.Fatalf("unexpected resource (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyPatch_Errors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		ops          string
+		wantScimType string
+	}{
+		"unknown operation":        {ops: `[{"op": "move", "path": "userName", "value": "x"}]`, wantScimType: "invalidValue"},
+		"remove without path":      {ops: `[{"op": "remove"}]`, wantScimType: "noTarget"},
+		"replace without value":    {ops: `[{"op": "replace", "path": "userName"}]`, wantScimType: "invalidValue"},
+		"non-object without path":  {ops: `[{"op": "add", "value": "x"}]`, wantScimType: "invalidValue"},
+		"invalid path":             {ops: `[{"op": "add", "path": "user name", "value": "x"}]`, wantScimType: "invalidPath"},
+		"invalid filter":           {ops: `[{"op": "remove", "path": "emails[type xx \"work\"]"}]`, wantScimType: "invalidFilter"},
+		"no match for complex one": {ops: `[{"op": "replace", "path": "emails[type eq \"work\" or type eq \"home\"].value", "value": "x"}]`, wantScimType: "noTarget"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var ops []patchOperation
+			if err := json.Unmarshal([]byte(tc.ops), &ops); err != nil {
+				t.Fatal(err)
+			}
+			err := applyPatch(map[string]any{"userName": "bob"}, ops)
+			e, ok := err.(*scimError)
+			if !ok {
+				t.Fatalf("want a SCIM error, got %v", err)
+			}
+			if e.ScimType != tc.wantScimType {
+				t.Fatalf("got scimType %q, want %q", e.ScimType, tc.wantScimType)
+			}
+		})
+	}
+}
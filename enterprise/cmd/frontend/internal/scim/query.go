@@ -0,0 +1,206 @@
+package scim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+)
+
+// A column of scim_users that filters on an attribute of users are evaluated against. Text columns
+// are citext unless the attribute is case-exact, so that comparisons follow the case sensitivity
+// of the attribute.
+type column struct {
+	expr string
+	kind columnKind
+	// cast is the type string values are cast to, to compare them with the column.
+	cast string
+}
+
+type columnKind int
+
+const (
+	textColumn columnKind = iota
+	boolColumn
+	// listColumn is an array of strings, used for multi-valued attributes. A filter matches if
+	// any element matches.
+	listColumn
+)
+
+// userColumn returns the column of an attribute of users. Attributes that are not in scim_users,
+// such as name, are only stored encrypted and can't be filtered on.
+func userColumn(path attrPath) (column, error) {
+	switch strings.ToLower(path.attr) {
+	case "id":
+		if path.sub == "" {
+			return column{expr: "s.user_id::text", kind: textColumn, cast: "text"}, nil
+		}
+	case "username":
+		if path.sub == "" {
+			return column{expr: "s.user_name", kind: textColumn, cast: "citext"}, nil
+		}
+	case "externalid":
+		if path.sub == "" {
+			return column{expr: "s.external_id", kind: textColumn, cast: "text"}, nil
+		}
+	case "displayname":
+		if path.sub == "" {
+			return column{expr: "s.display_name", kind: textColumn, cast: "citext"}, nil
+		}
+	case "active":
+		if path.sub == "" {
+			return column{expr: "s.active", kind: boolColumn}, nil
+		}
+	case "emails":
+		if path.sub == "" || strings.EqualFold(path.sub, "value") {
+			return column{expr: "s.emails", kind: listColumn, cast: "citext"}, nil
+		}
+	}
+	return column{}, errUnsupportedFilter(path)
+}
+
+// emailColumn returns the column of a sub-attribute of emails in a value filter such as
+// emails[value ew "@example.com"]. Only the value of emails is stored in scim_users.
+func emailColumn(path attrPath) (column, error) {
+	if strings.EqualFold(path.attr, "value") && path.sub == "" {
+		return column{expr: "e.value", kind: textColumn, cast: "citext"}, nil
+	}
+	return column{}, errUnsupportedFilter(attrPath{attr: "emails", sub: path.attr})
+}
+
+func errUnsupportedFilter(path attrPath) *scimError {
+	attr := path.attr
+	if path.sub != "" {
+		attr += "." + path.sub
+	}
+	return newError(http.StatusBadRequest, "invalidFilter", "filtering on "+strconv.Quote(attr)+" is not supported")
+}
+
+// userFilterQuery returns the condition on scim_users (aliased as s) that selects the users the
+// filter matches.
+func userFilterQuery(f filter) (*sqlf.Query, error) {
+	return filterQuery(f, userColumn)
+}
+
+func filterQuery(f filter, resolve func(attrPath) (column, error)) (*sqlf.Query, error) {
+	switch f := f.(type) {
+	case *logicalFilter:
+		left, err := filterQuery(f.left, resolve)
+		if err != nil {
+			return nil, err
+		}
+		right, err := filterQuery(f.right, resolve)
+		if err != nil {
+			return nil, err
+		}
+		if f.and {
+			return sqlf.Sprintf("(%s AND %s)", left, right), nil
+		}
+		return sqlf.Sprintf("(%s OR %s)", left, right), nil
+
+	case *notFilter:
+		inner, err := filterQuery(f.filter, resolve)
+		if err != nil {
+			return nil, err
+		}
+		return sqlf.Sprintf("NOT %s", inner), nil
+
+	case *presentFilter:
+		c, err := resolve(f.path)
+		if err != nil {
+			return nil, err
+		}
+		return presentQuery(c), nil
+
+	case *compareFilter:
+		c, err := resolve(f.path)
+		if err != nil {
+			return nil, err
+		}
+		if f.value == nil {
+			// "eq null" and "ne null" test for (non-)presence.
+			if f.op == "ne" {
+				return presentQuery(c), nil
+			}
+			return sqlf.Sprintf("NOT %s", presentQuery(c)), nil
+		}
+		if f.op == "ne" {
+			return sqlf.Sprintf("NOT %s", compareQuery(c, "eq", f.value)), nil
+		}
+		return compareQuery(c, f.op, f.value), nil
+
+	case *valuePathFilter:
+		if !strings.EqualFold(f.attr, "emails") {
+			return nil, errUnsupportedFilter(attrPath{attr: f.attr})
+		}
+		inner, err := filterQuery(f.filter, emailColumn)
+		if err != nil {
+			return nil, err
+		}
+		return sqlf.Sprintf("EXISTS (SELECT 1 FROM unnest(s.emails) AS e(value) WHERE %s)", inner), nil
+	}
+
+	return nil, newError(http.StatusBadRequest, "invalidFilter", "unsupported filter")
+}
+
+// presentQuery matches if the column has a non-empty value. Text columns are never null.
+func presentQuery(c column) *sqlf.Query {
+	switch c.kind {
+	case boolColumn:
+		return sqlf.Sprintf("TRUE")
+	case listColumn:
+		return sqlf.Sprintf("(cardinality(" + c.expr + ") > 0)")
+	}
+	return sqlf.Sprintf("(" + c.expr + " <> '')")
+}
+
+// compareOperators are the SQL operators of the SCIM comparison operators that map to one.
+var compareOperators = map[string]string{
+	"eq": "=",
+	"gt": ">",
+	"ge": ">=",
+	"lt": "<",
+	"le": "<=",
+}
+
+// compareQuery matches if the column compares to the value with the operator, which is not "ne".
+// As when evaluating filters in memory, values of another type than the column never match.
+func compareQuery(c column, op string, value any) *sqlf.Query {
+	if c.kind == listColumn {
+		elem := column{expr: "e.value", kind: textColumn, cast: c.cast}
+		return sqlf.Sprintf("EXISTS (SELECT 1 FROM unnest("+c.expr+") AS e(value) WHERE %s)", compareQuery(elem, op, value))
+	}
+
+	switch v := value.(type) {
+	case bool:
+		if c.kind != boolColumn || op != "eq" {
+			return sqlf.Sprintf("FALSE")
+		}
+		return sqlf.Sprintf("("+c.expr+" = %s)", v)
+
+	case string:
+		if c.kind != textColumn {
+			return sqlf.Sprintf("FALSE")
+		}
+		switch op {
+		case "co":
+			return sqlf.Sprintf("("+c.expr+" LIKE %s::"+c.cast+")", "%"+escapeLike(v)+"%")
+		case "sw":
+			return sqlf.Sprintf("("+c.expr+" LIKE %s::"+c.cast+")", escapeLike(v)+"%")
+		case "ew":
+			return sqlf.Sprintf("("+c.expr+" LIKE %s::"+c.cast+")", "%"+escapeLike(v))
+		}
+		if sqlOp, ok := compareOperators[op]; ok {
+			return sqlf.Sprintf("("+c.expr+" "+sqlOp+" %s::"+c.cast+")", v)
+		}
+	}
+
+	// Numbers, since no stored attribute is numeric.
+	return sqlf.Sprintf("FALSE")
+}
+
+// escapeLike escapes the wildcards of LIKE patterns, which use backslash as the escape character.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,100 @@
+package scim
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestUserFilterQuery(t *testing.T) {
+	for _, tc := range []struct {
+		filter    string
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			filter:    `userName eq "Alice@Example.com"`,
+			wantQuery: `(s.user_name = $1::citext)`,
+			wantArgs:  []any{"Alice@Example.com"},
+		},
+		{
+			filter:    `externalId eq "00u1ab2cd3" and active eq true`,
+			wantQuery: `((s.external_id = $1::text) AND (s.active = $2))`,
+			wantArgs:  []any{"00u1ab2cd3", true},
+		},
+		{
+			filter:    `userName sw "a_b%" or not (displayName pr)`,
+			wantQuery: `((s.user_name LIKE $1::citext) OR NOT (s.display_name <> ''))`,
+			wantArgs:  []any{`a\_b\%%`},
+		},
+		{
+			filter:    `userName ne "alice"`,
+			wantQuery: `NOT (s.user_name = $1::citext)`,
+			wantArgs:  []any{"alice"},
+		},
+		{
+			filter:    `externalId eq null`,
+			wantQuery: `NOT (s.external_id <> '')`,
+		},
+		{
+			filter:    `emails co "@example.com"`,
+			wantQuery: `EXISTS (SELECT 1 FROM unnest(s.emails) AS e(value) WHERE (e.value LIKE $1::citext))`,
+			wantArgs:  []any{"%@example.com%"},
+		},
+		{
+			filter:    `emails[value ew "@example.com"]`,
+			wantQuery: `EXISTS (SELECT 1 FROM unnest(s.emails) AS e(value) WHERE (e.value LIKE $1::citext))`,
+			wantArgs:  []any{"%@example.com"},
+		},
+		{
+			filter:    `emails pr`,
+			wantQuery: `(cardinality(s.emails) > 0)`,
+		},
+		{
+			// Values of another type than the attribute never match, as when matching in memory.
+			filter:    `userName eq true or active eq "true" or id gt 5`,
+			wantQuery: `((FALSE OR FALSE) OR FALSE)`,
+		},
+	} {
+		t.Run(tc.filter, func(t *testing.T) {
+			f, err := parseFilter(tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			q, err := userFilterQuery(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.wantQuery, q.Query(sqlf.PostgresBindVar)); diff != "" {
+				t.Errorf("unexpected query (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantArgs, q.Args(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("unexpected args (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	for _, filter := range []string{
+		`name.givenName eq "Alice"`,
+		`emails.type eq "work"`,
+		`emails[type eq "work"]`,
+		`meta.created gt "2023-01-01T00:00:00Z"`,
+	} {
+		t.Run(filter, func(t *testing.T) {
+			f, err := parseFilter(filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = userFilterQuery(f)
+			var e *scimError
+			if !errors.As(err, &e) || e.status != http.StatusBadRequest || e.ScimType != "invalidFilter" {
+				t.Errorf("expected invalidFilter error, got %v", err)
+			}
+		})
+	}
+}
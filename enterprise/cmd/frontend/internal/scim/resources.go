@@ -0,0 +1,146 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Schema URIs defined by RFC 7643 and RFC 7644.
+const (
+	userSchemaURI                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	groupSchemaURI                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	listResponseSchemaURI          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	patchOpSchemaURI               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	errorSchemaURI                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	serviceProviderConfigSchemaURI = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	resourceTypeSchemaURI          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	schemaSchemaURI                = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+)
+
+// contentType is the media type of SCIM requests and responses.
+const contentType = "application/scim+json"
+
+type meta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+}
+
+type name struct {
+	Formatted       string `json:"formatted,omitempty"`
+	FamilyName      string `json:"familyName,omitempty"`
+	GivenName       string `json:"givenName,omitempty"`
+	MiddleName      string `json:"middleName,omitempty"`
+	HonorificPrefix string `json:"honorificPrefix,omitempty"`
+	HonorificSuffix string `json:"honorificSuffix,omitempty"`
+}
+
+// multiValue is an element of a multi-valued attribute such as emails or members.
+type multiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// userResource is a SCIM User. The provisioned attributes (everything except id and meta) are
+// stored as the data of the user's SCIM external account.
+type userResource struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	UserName    string       `json:"userName"`
+	Name        *name        `json:"name,omitempty"`
+	DisplayName string       `json:"displayName,omitempty"`
+	Emails      []multiValue `json:"emails,omitempty"`
+	Active      *bool        `json:"active,omitempty"`
+	Meta        *meta        `json:"meta,omitempty"`
+}
+
+// active reports whether the user is active. Users are active unless provisioned otherwise.
+func (u *userResource) active() bool {
+	return u.Active == nil || *u.Active
+}
+
+// primaryEmail returns the email marked as primary, or the first email if none is.
+func (u *userResource) primaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary && e.Value != "" {
+			return e.Value
+		}
+	}
+	for _, e := range u.Emails {
+		if e.Value != "" {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+// groupResource is a SCIM Group, backed by an organization or a role.
+type groupResource struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []multiValue `json:"members,omitempty"`
+	Meta        *meta        `json:"meta,omitempty"`
+}
+
+type listResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+type patchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []patchOperation `json:"Operations"`
+}
+
+// scimError is an error response as defined in RFC 7644 section 3.12. It also implements error, so
+// that handlers can return it to choose the status code and scimType of the response.
+type scimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+
+	status int
+}
+
+func (e *scimError) Error() string { return e.Detail }
+
+// newError returns a SCIM error with the given HTTP status, scimType (which may be empty) and detail.
+func newError(status int, scimType, detail string) *scimError {
+	return &scimError{
+		Schemas:  []string{errorSchemaURI},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+		status:   status,
+	}
+}
+
+func errNotFound(resourceType, id string) *scimError {
+	return newError(http.StatusNotFound, "", resourceType+" "+strconv.Quote(id)+" not found")
+}
+
+func errInvalidValue(detail string) *scimError {
+	return newError(http.StatusBadRequest, "invalidValue", detail)
+}
+
+func errUniqueness(detail string) *scimError {
+	return newError(http.StatusConflict, "uniqueness", detail)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,264 @@
+package scim
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// The external account that links a user to their SCIM resource. There is one per provisioned
+// user, with the user ID as the account ID.
+const (
+	serviceType = "scim"
+	serviceID   = "scim"
+	clientID    = "scim"
+)
+
+func accountSpec(userID int32) extsvc.AccountSpec {
+	return extsvc.AccountSpec{
+		ServiceType: serviceType,
+		ServiceID:   serviceID,
+		ClientID:    clientID,
+		AccountID:   formatID(userID),
+	}
+}
+
+// account is a provisioned user.
+type account struct {
+	UserID    int32
+	Data      userResource
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// store reads and writes provisioned users. A provisioned user has a row in scim_users, which holds
+// the attributes that can be filtered on, and a SCIM external account, which stores the whole
+// resource encrypted. Unlike database.UserExternalAccountsStore, it also sees the accounts of
+// deactivated (soft-deleted) users, which are still SCIM resources.
+type store struct {
+	*basestore.Store
+}
+
+func newStore(db database.DB) *store {
+	return &store{Store: basestore.NewWithHandle(db.Handle())}
+}
+
+func (s *store) key() encryption.Key {
+	return keyring.Default().UserExternalAccountKey
+}
+
+// list returns the provisioned users matching cond, a condition on scim_users (aliased as s),
+// ordered by user ID, along with the total number of matching users.
+func (s *store) list(ctx context.Context, cond *sqlf.Query, offset, limit int) ([]*account, int, error) {
+	total, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf("SELECT COUNT(*) FROM scim_users s WHERE %s", cond)))
+	if err != nil {
+		return nil, 0, err
+	}
+	if limit == 0 || offset >= total {
+		return nil, total, nil
+	}
+	accounts, err := s.listWhere(ctx, cond, offset, limit)
+	return accounts, total, err
+}
+
+// get returns the provisioned user with the given ID, or nil if there is none.
+func (s *store) get(ctx context.Context, userID int32) (*account, error) {
+	accounts, err := s.listWhere(ctx, sqlf.Sprintf("s.user_id = %s", userID), 0, 1)
+	if err != nil || len(accounts) == 0 {
+		return nil, err
+	}
+	return accounts[0], nil
+}
+
+// The SCIM external account of a deactivated user is soft-deleted along with the user, so the
+// latest account is used whether or not it is deleted.
+const listAccountsQuery = `
+SELECT s.user_id, a.account_data, a.encryption_key_id, s.created_at, s.updated_at
+FROM scim_users s
+JOIN LATERAL (
+	SELECT account_data, encryption_key_id
+	FROM user_external_accounts
+	WHERE service_type = %s AND service_id = %s AND client_id = %s AND user_id = s.user_id
+	ORDER BY deleted_at IS NULL DESC, id DESC
+	LIMIT 1
+) a ON TRUE
+WHERE %s
+ORDER BY s.user_id
+OFFSET %s
+LIMIT %s
+`
+
+func (s *store) listWhere(ctx context.Context, cond *sqlf.Query, offset, limit int) (_ []*account, err error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(listAccountsQuery, serviceType, serviceID, clientID, cond, offset, limit))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var accounts []*account
+	for rows.Next() {
+		var a account
+		var data sql.NullString
+		var keyID string
+		if err := rows.Scan(&a.UserID, &data, &keyID, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if data.Valid {
+			decrypted, err := encryption.DecryptJSON[userResource](ctx, extsvc.NewEncryptedData(data.String, keyID, s.key()))
+			if err != nil {
+				return nil, errors.Wrapf(err, "decoding SCIM data of user %d", a.UserID)
+			}
+			a.Data = *decrypted
+		}
+		accounts = append(accounts, &a)
+	}
+	return accounts, rows.Err()
+}
+
+var (
+	errUserNameExists     = errors.New("userName is already taken")
+	errAlreadyProvisioned = errors.New("user is already provisioned")
+)
+
+// create stores the SCIM resource of a newly provisioned user. It returns errUserNameExists if
+// another provisioned user has the same userName, and errAlreadyProvisioned if the user has been
+// provisioned already, for example by a concurrent request.
+func (s *store) create(ctx context.Context, userID int32, data *userResource) error {
+	err := s.Exec(ctx, sqlf.Sprintf(`
+INSERT INTO scim_users (user_id, user_name, external_id, display_name, emails, active)
+VALUES (%s, %s, %s, %s, %s, %s)
+`, userID, data.UserName, data.ExternalID, data.DisplayName, pq.Array(emailValues(data)), data.active()))
+	if err := uniquenessError(err); err != nil {
+		return err
+	}
+	return s.saveAccount(ctx, userID, data)
+}
+
+// update stores the SCIM resource of a provisioned user, whether or not they are active. It
+// returns errUserNameExists if another provisioned user has the same userName.
+func (s *store) update(ctx context.Context, userID int32, data *userResource) error {
+	err := s.Exec(ctx, sqlf.Sprintf(`
+UPDATE scim_users
+SET user_name = %s, external_id = %s, display_name = %s, emails = %s, active = %s, updated_at = NOW()
+WHERE user_id = %s
+`, data.UserName, data.ExternalID, data.DisplayName, pq.Array(emailValues(data)), data.active(), userID))
+	if err := uniquenessError(err); err != nil {
+		return err
+	}
+	return s.saveAccount(ctx, userID, data)
+}
+
+// uniquenessError translates violations of the constraints of scim_users.
+func uniquenessError(err error) error {
+	var e *pgconn.PgError
+	if errors.As(err, &e) {
+		switch e.ConstraintName {
+		case "scim_users_user_name":
+			return errUserNameExists
+		case "scim_users_pkey":
+			return errAlreadyProvisioned
+		}
+	}
+	return err
+}
+
+func emailValues(u *userResource) []string {
+	emails := make([]string, 0, len(u.Emails))
+	for _, e := range u.Emails {
+		emails = append(emails, e.Value)
+	}
+	return emails
+}
+
+// delete removes the SCIM resource of a user. The user itself is left alone.
+func (s *store) delete(ctx context.Context, userID int32) error {
+	return s.Exec(ctx, sqlf.Sprintf("DELETE FROM scim_users WHERE user_id = %s", userID))
+}
+
+// saveAccount stores the SCIM resource in the user's SCIM external account.
+func (s *store) saveAccount(ctx context.Context, userID int32, data *userResource) error {
+	stored := *data
+	stored.Schemas, stored.ID, stored.Meta = nil, "", nil
+	raw, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	encrypted, keyID, err := extsvc.NewUnencryptedData(raw).Encrypt(ctx, s.key())
+	if err != nil {
+		return err
+	}
+
+	res, err := s.ExecResult(ctx, sqlf.Sprintf(`
+UPDATE user_external_accounts
+SET account_data = %s, encryption_key_id = %s, updated_at = NOW()
+WHERE service_type = %s AND service_id = %s AND client_id = %s AND user_id = %s
+`, encrypted, keyID, serviceType, serviceID, clientID, userID))
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+
+	spec := accountSpec(userID)
+	return s.Exec(ctx, sqlf.Sprintf(`
+INSERT INTO user_external_accounts (user_id, service_type, service_id, client_id, account_id, account_data, encryption_key_id)
+VALUES (%s, %s, %s, %s, %s, %s, %s)
+`, userID, spec.ServiceType, spec.ServiceID, spec.ClientID, spec.AccountID, encrypted, keyID))
+}
+
+var errUsernameTaken = errors.New("username is already taken")
+
+// restore reactivates a user that was deactivated through SCIM, undoing the soft delete of the user
+// and their SCIM external account. Emails, which are deleted along with the user, must be added
+// again by the caller. If the username has been taken in the meantime, errUsernameTaken is returned.
+func (s *store) restore(ctx context.Context, userID int32) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	res, err := tx.ExecResult(ctx, sqlf.Sprintf("UPDATE users SET deleted_at = NULL, updated_at = NOW() WHERE id = %s AND deleted_at IS NOT NULL", userID))
+	if err != nil {
+		var e *pgconn.PgError
+		if errors.As(err, &e) && e.ConstraintName == "users_username" {
+			return errUsernameTaken
+		}
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return err // not deleted, nothing to do
+	}
+
+	if err := tx.Exec(ctx, sqlf.Sprintf("INSERT INTO names (name, user_id) SELECT username, id FROM users WHERE id = %s", userID)); err != nil {
+		var e *pgconn.PgError
+		if errors.As(err, &e) && e.ConstraintName == "names_pkey" {
+			return errUsernameTaken
+		}
+		return err
+	}
+
+	return tx.Exec(ctx, sqlf.Sprintf(`
+UPDATE user_external_accounts
+SET deleted_at = NULL
+WHERE service_type = %s AND service_id = %s AND client_id = %s AND user_id = %s AND deleted_at IS NOT NULL
+`, serviceType, serviceID, clientID, userID))
+}
+
+func (s *store) Transact(ctx context.Context) (*store, error) {
+	tx, err := s.Store.Transact(ctx)
+	return &store{Store: tx}, err
+}
@@ -0,0 +1,419 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// Provisioned users are Sourcegraph users with a SCIM external account, which stores the SCIM
+// resource as last provisioned. Deactivating a user soft-deletes them, which signs them out and
+// revokes their access tokens; reactivating them restores the user. Deleting a user soft-deletes
+// them as well, and removes their SCIM resource.
+
+func (h *handler) userResource(a *account) *userResource {
+	u := a.Data
+	u.Schemas = []string{userSchemaURI}
+	u.ID = formatID(a.UserID)
+	active := a.Data.active()
+	u.Active = &active
+	created, modified := a.CreatedAt, a.UpdatedAt
+	u.Meta = &meta{
+		ResourceType: "User",
+		Created:      &created,
+		LastModified: &modified,
+		Location:     location("Users", u.ID),
+	}
+	return &u
+}
+
+func (h *handler) listUsers(w http.ResponseWriter, r *http.Request) error {
+	params, err := parseListParams(r)
+	if err != nil {
+		return err
+	}
+	cond := sqlf.Sprintf("TRUE")
+	if params.filter != nil {
+		if cond, err = userFilterQuery(params.filter); err != nil {
+			return err
+		}
+	}
+	accounts, total, err := newStore(h.db).list(r.Context(), cond, params.startIndex-1, params.count)
+	if err != nil {
+		return err
+	}
+	resources := make([]map[string]any, 0, len(accounts))
+	for _, a := range accounts {
+		m, err := toMap(h.userResource(a))
+		if err != nil {
+			return err
+		}
+		resources = append(resources, m)
+	}
+	writeJSON(w, http.StatusOK, params.response(resources, total))
+	return nil
+}
+
+// getAccount returns the provisioned user with the ID in the URL.
+func (h *handler) getAccount(ctx context.Context, s *store, r *http.Request) (*account, error) {
+	idStr := mux.Vars(r)["id"]
+	id, ok := parseID(idStr)
+	if !ok {
+		return nil, errNotFound("User", idStr)
+	}
+	a, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, errNotFound("User", idStr)
+	}
+	return a, nil
+}
+
+func (h *handler) getUser(w http.ResponseWriter, r *http.Request) error {
+	a, err := h.getAccount(r.Context(), newStore(h.db), r)
+	if err != nil {
+		return err
+	}
+	return h.writeUser(w, r, http.StatusOK, a)
+}
+
+func (h *handler) writeUser(w http.ResponseWriter, r *http.Request, status int, a *account) error {
+	u := h.userResource(a)
+	m, err := toMap(u)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusCreated {
+		w.Header().Set("Location", u.Meta.Location)
+	}
+	writeJSON(w, status, parseProjection(r).apply(m))
+	return nil
+}
+
+func (h *handler) createUser(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	var u userResource
+	if err := decodeBody(r, &u); err != nil {
+		return err
+	}
+	if err := validateUser(&u); err != nil {
+		return err
+	}
+
+	var userID int32
+	err := h.db.WithTransact(ctx, func(tx database.DB) error {
+		s := newStore(tx)
+
+		// Link an existing user with the same verified email, so that users who signed in before
+		// provisioning was set up keep their account.
+		if email := u.primaryEmail(); email != "" {
+			existing, err := tx.Users().GetByVerifiedEmail(ctx, email)
+			if err != nil && !errcode.IsNotFound(err) {
+				return err
+			}
+			if existing != nil {
+				if a, err := s.get(ctx, existing.ID); err != nil {
+					return err
+				} else if a != nil {
+					return errUniqueness("a provisioned user with the email " + strconv.Quote(email) + " already exists")
+				}
+				userID = existing.ID
+			}
+		}
+
+		if userID == 0 {
+			username, err := auth.NormalizeUsername(u.UserName)
+			if err != nil {
+				return errInvalidValue(err.Error())
+			}
+			email := u.primaryEmail()
+			user, err := tx.Users().Create(ctx, database.NewUser{
+				Username:        username,
+				Email:           email,
+				EmailIsVerified: email != "", // emails managed by the identity provider are trusted
+				DisplayName:     displayName(&u),
+			})
+			if database.IsUsernameExists(err) {
+				return errUniqueness("the username " + strconv.Quote(username) + " is already taken")
+			}
+			if database.IsEmailExists(err) {
+				return errUniqueness("the email " + strconv.Quote(email) + " is already in use")
+			}
+			if err != nil {
+				return err
+			}
+			userID = user.ID
+		}
+
+		if err := s.create(ctx, userID, &u); err != nil {
+			return provisioningError(err, &u)
+		}
+		return h.syncUser(ctx, tx, userID, nil, &u)
+	})
+	if err != nil {
+		return err
+	}
+
+	a, err := newStore(h.db).get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return h.writeUser(w, r, http.StatusCreated, a)
+}
+
+func (h *handler) replaceUser(w http.ResponseWriter, r *http.Request) error {
+	var u userResource
+	if err := decodeBody(r, &u); err != nil {
+		return err
+	}
+	return h.updateUser(w, r, func(*userResource) (*userResource, error) { return &u, nil })
+}
+
+func (h *handler) patchUser(w http.ResponseWriter, r *http.Request) error {
+	var req patchRequest
+	if err := decodeBody(r, &req); err != nil {
+		return err
+	}
+	return h.updateUser(w, r, func(old *userResource) (*userResource, error) {
+		m, err := toMap(old)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyPatch(m, req.Operations); err != nil {
+			return nil, err
+		}
+		// Some identity providers send booleans as strings, e.g. {"active": "False"}.
+		if s, ok := lookup(m, "active").(string); ok {
+			active, err := strconv.ParseBool(s)
+			if err != nil {
+				return nil, errInvalidValue("active must be a boolean")
+			}
+			setKey(m, "active", active)
+		}
+		var u userResource
+		if err := fromMap(m, &u); err != nil {
+			return nil, err
+		}
+		return &u, nil
+	})
+}
+
+// updateUser replaces the provisioned user with the ID in the URL by the result of update.
+func (h *handler) updateUser(w http.ResponseWriter, r *http.Request, update func(old *userResource) (*userResource, error)) error {
+	ctx := r.Context()
+
+	var userID int32
+	err := h.db.WithTransact(ctx, func(tx database.DB) error {
+		s := newStore(tx)
+		a, err := h.getAccount(ctx, s, r)
+		if err != nil {
+			return err
+		}
+		userID = a.UserID
+
+		u, err := update(&a.Data)
+		if err != nil {
+			return err
+		}
+		if err := validateUser(u); err != nil {
+			return err
+		}
+		if err := h.syncUser(ctx, tx, a.UserID, &a.Data, u); err != nil {
+			return err
+		}
+		return provisioningError(s.update(ctx, a.UserID, u), u)
+	})
+	if err != nil {
+		return err
+	}
+
+	a, err := newStore(h.db).get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return h.writeUser(w, r, http.StatusOK, a)
+}
+
+func (h *handler) deleteUser(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	err := h.db.WithTransact(ctx, func(tx database.DB) error {
+		s := newStore(tx)
+		a, err := h.getAccount(ctx, s, r)
+		if err != nil {
+			return err
+		}
+		// Deactivated users have been soft-deleted already.
+		if a.Data.active() {
+			if err := tx.Users().InvalidateSessionsByID(ctx, a.UserID); err != nil {
+				return err
+			}
+			if err := tx.Users().Delete(ctx, a.UserID); err != nil {
+				return err
+			}
+		}
+		return s.delete(ctx, a.UserID)
+	})
+	if err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// validateUser checks a user that is about to be provisioned. The uniqueness of userName is
+// enforced by the store.
+func validateUser(u *userResource) error {
+	u.UserName = strings.TrimSpace(u.UserName)
+	if u.UserName == "" {
+		return errInvalidValue("userName is required")
+	}
+	for _, e := range u.Emails {
+		if strings.TrimSpace(e.Value) == "" {
+			return errInvalidValue("emails must have a value")
+		}
+	}
+	return nil
+}
+
+// provisioningError translates the errors of storing a provisioned user.
+func provisioningError(err error, u *userResource) error {
+	switch err {
+	case errUserNameExists:
+		// userName is unique among provisioned users, case-insensitively.
+		return errUniqueness("a user with the userName " + strconv.Quote(u.UserName) + " already exists")
+	case errAlreadyProvisioned:
+		return errUniqueness("a provisioned user with the email " + strconv.Quote(u.primaryEmail()) + " already exists")
+	}
+	return err
+}
+
+// syncUser updates the Sourcegraph user to match their SCIM resource. old is the previously
+// provisioned resource, or nil if the user is being provisioned.
+func (h *handler) syncUser(ctx context.Context, tx database.DB, userID int32, old, u *userResource) error {
+	s := newStore(tx)
+	wasActive := old == nil || old.active()
+
+	if !u.active() {
+		if !wasActive {
+			return nil
+		}
+		if err := tx.Users().InvalidateSessionsByID(ctx, userID); err != nil {
+			return err
+		}
+		return tx.Users().Delete(ctx, userID)
+	}
+
+	var oldEmails []multiValue
+	if old != nil {
+		oldEmails = old.Emails
+	}
+	if !wasActive {
+		if err := s.restore(ctx, userID); err == errUsernameTaken {
+			return errUniqueness("the user's username has been taken since they were deactivated")
+		} else if err != nil {
+			return err
+		}
+		// The user's emails were removed when they were deactivated.
+		oldEmails = nil
+	}
+
+	var update database.UserUpdate
+	if old != nil && old.UserName != u.UserName {
+		username, err := auth.NormalizeUsername(u.UserName)
+		if err != nil {
+			return errInvalidValue(err.Error())
+		}
+		if err := checkNameAvailable(ctx, tx, username, userID); err != nil {
+			return err
+		}
+		update.Username = username
+	}
+	if name := displayName(u); old == nil || name != displayName(old) {
+		update.DisplayName = &name
+	}
+	if err := tx.Users().Update(ctx, userID, update); err != nil {
+		if database.IsUsernameExists(err) {
+			return errUniqueness("the username " + strconv.Quote(update.Username) + " is already taken")
+		}
+		return err
+	}
+
+	return syncEmails(ctx, tx, userID, oldEmails, u)
+}
+
+// syncEmails adds the user's provisioned emails as verified emails and makes the primary one the
+// user's primary email. Emails that were provisioned before but no longer are, are removed; emails
+// the user added themselves are left alone.
+func syncEmails(ctx context.Context, tx database.DB, userID int32, old []multiValue, u *userResource) error {
+	existing, err := tx.UserEmails().ListByUser(ctx, database.UserEmailsListOptions{UserID: userID})
+	if err != nil {
+		return err
+	}
+	have := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		have[strings.ToLower(e.Email)] = true
+	}
+
+	want := make(map[string]bool, len(u.Emails))
+	for _, e := range u.Emails {
+		want[strings.ToLower(e.Value)] = true
+		if have[strings.ToLower(e.Value)] {
+			continue
+		}
+		if err := tx.UserEmails().Add(ctx, userID, e.Value, nil); err != nil {
+			if isUniqueViolation(err) {
+				return errUniqueness("the email " + strconv.Quote(e.Value) + " is already in use")
+			}
+			return err
+		}
+		have[strings.ToLower(e.Value)] = true
+	}
+	for _, e := range u.Emails {
+		if err := tx.UserEmails().SetVerified(ctx, userID, e.Value, true); err != nil {
+			if isUniqueViolation(err) {
+				return errUniqueness("the email " + strconv.Quote(e.Value) + " is already in use")
+			}
+			return err
+		}
+	}
+
+	if primary := u.primaryEmail(); primary != "" {
+		if err := tx.UserEmails().SetPrimaryEmail(ctx, userID, primary); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range old {
+		if !want[strings.ToLower(e.Value)] && have[strings.ToLower(e.Value)] {
+			if err := tx.UserEmails().Remove(ctx, userID, e.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// displayName returns the display name of a user, falling back to their name.
+func displayName(u *userResource) string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.Name == nil {
+		return ""
+	}
+	if u.Name.Formatted != "" {
+		return u.Name.Formatted
+	}
+	return strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+}
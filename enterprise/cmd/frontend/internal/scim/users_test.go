@@ -0,0 +1,244 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const testToken = "s3cr3t"
+
+// newTestHandler returns a SCIM handler backed by a new database, with groups mapped to
+// groupsMapTo.
+func newTestHandler(t *testing.T, groupsMapTo string) (http.Handler, database.DB) {
+	t.Helper()
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		ExternalURL: "https://sourcegraph.example.com",
+		Scim:        &schema.Scim{AuthToken: testToken, GroupsMapTo: groupsMapTo},
+	}})
+	t.Cleanup(func() { conf.Mock(nil) })
+	licensing.MockCheckFeatureError("")
+	t.Cleanup(func() { licensing.MockCheckFeature = nil })
+
+	return NewHandler(logger, db), db
+}
+
+// do sends a SCIM request and decodes the JSON response into a map, if there is one.
+func do(t *testing.T, h http.Handler, method, path, body string) (int, map[string]any) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, pathPrefix+path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var res map[string]any
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatalf("invalid response body %q: %s", rec.Body.String(), err)
+		}
+	}
+	return rec.Code, res
+}
+
+func createTestUser(t *testing.T, h http.Handler, userName, email string) string {
+	t.Helper()
+
+	status, res := do(t, h, "POST", "/Users", `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"userName": "`+userName+`",
+		"externalId": "ext-`+userName+`",
+		"displayName": "User `+userName+`",
+		"emails": [{"value": "`+email+`", "primary": true}]
+	}`)
+	if status != http.StatusCreated {
+		t.Fatalf("unexpected status creating user %q: %d %v", userName, status, res)
+	}
+	return res["id"].(string)
+}
+
+func TestUsers(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	h, db := newTestHandler(t, "")
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		req := httptest.NewRequest("GET", pathPrefix+"/Users", nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("unexpected status %d", rec.Code)
+		}
+	})
+
+	aliceID := createTestUser(t, h, "alice@example.com", "alice@example.com")
+	bobID := createTestUser(t, h, "bob@example.com", "bob@example.com")
+	carolID := createTestUser(t, h, "carol@example.com", "carol@example.org")
+
+	t.Run("create", func(t *testing.T) {
+		id, _ := parseID(aliceID)
+		user, err := db.Users().GetByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Username != "alice" || user.DisplayName != "User alice@example.com" {
+			t.Errorf("unexpected user %+v", user)
+		}
+		email, verified, err := db.UserEmails().GetPrimaryEmail(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if email != "alice@example.com" || !verified {
+			t.Errorf("unexpected primary email %q (verified: %v)", email, verified)
+		}
+	})
+
+	t.Run("create with taken userName", func(t *testing.T) {
+		status, res := do(t, h, "POST", "/Users", `{"userName": "ALICE@example.com", "emails": [{"value": "alice2@example.com"}]}`)
+		if status != http.StatusConflict || res["scimType"] != "uniqueness" {
+			t.Errorf("unexpected response %d %v", status, res)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		status, res := do(t, h, "GET", "/Users?startIndex=2&count=1", "")
+		if status != http.StatusOK {
+			t.Fatalf("unexpected status %d %v", status, res)
+		}
+		if res["totalResults"] != float64(3) || res["itemsPerPage"] != float64(1) {
+			t.Errorf("unexpected page %v", res)
+		}
+		if resources := res["Resources"].([]any); resources[0].(map[string]any)["id"] != bobID {
+			t.Errorf("unexpected resources %v", resources)
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		for filter, want := range map[string][]string{
+			`userName eq "BOB@example.com"`:                            {bobID},
+			`externalId eq "ext-carol@example.com"`:                    {carolID},
+			`emails[value ew "@example.org"]`:                          {carolID},
+			`userName sw "a" or emails co "bob"`:                       {aliceID, bobID},
+			`not (userName eq "alice@example.com") and active eq true`: {bobID, carolID},
+		} {
+			status, res := do(t, h, "GET", "/Users?filter="+url.QueryEscape(filter), "")
+			if status != http.StatusOK {
+				t.Fatalf("unexpected status for %s: %d %v", filter, status, res)
+			}
+			var ids []string
+			for _, r := range res["Resources"].([]any) {
+				ids = append(ids, r.(map[string]any)["id"].(string))
+			}
+			if strings.Join(ids, ",") != strings.Join(want, ",") {
+				t.Errorf("unexpected users for %s: %v, want %v", filter, ids, want)
+			}
+		}
+	})
+
+	t.Run("unsupported filter", func(t *testing.T) {
+		status, res := do(t, h, "GET", "/Users?filter="+url.QueryEscape(`name.givenName eq "Alice"`), "")
+		if status != http.StatusBadRequest || res["scimType"] != "invalidFilter" {
+			t.Errorf("unexpected response %d %v", status, res)
+		}
+	})
+
+	t.Run("deactivate and reactivate", func(t *testing.T) {
+		id, _ := parseID(bobID)
+
+		status, res := do(t, h, "PATCH", "/Users/"+bobID, `{"Operations": [{"op": "replace", "path": "active", "value": "False"}]}`)
+		if status != http.StatusOK || res["active"] != false {
+			t.Fatalf("unexpected response %d %v", status, res)
+		}
+		if _, err := db.Users().GetByID(ctx, id); !errcode.IsNotFound(err) {
+			t.Errorf("expected deactivated user to be deleted, got %v", err)
+		}
+		if status, res := do(t, h, "GET", "/Users?filter="+url.QueryEscape(`active eq false`), ""); status != http.StatusOK || res["totalResults"] != float64(1) {
+			t.Errorf("unexpected response %d %v", status, res)
+		}
+
+		status, res = do(t, h, "PATCH", "/Users/"+bobID, `{"Operations": [{"op": "replace", "path": "active", "value": true}]}`)
+		if status != http.StatusOK || res["active"] != true {
+			t.Fatalf("unexpected response %d %v", status, res)
+		}
+		if user, err := db.Users().GetByID(ctx, id); err != nil || user.Username != "bob" {
+			t.Errorf("expected user to be restored, got %v %v", user, err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if status, res := do(t, h, "DELETE", "/Users/"+carolID, ""); status != http.StatusNoContent {
+			t.Fatalf("unexpected response %d %v", status, res)
+		}
+		if status, _ := do(t, h, "GET", "/Users/"+carolID, ""); status != http.StatusNotFound {
+			t.Errorf("unexpected status %d getting deleted user", status)
+		}
+
+		// The user is soft-deleted, like a deactivated user.
+		id, _ := parseID(carolID)
+		deleted, _, err := basestore.ScanFirstBool(db.Handle().QueryContext(ctx, "SELECT deleted_at IS NOT NULL FROM users WHERE id = $1", id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !deleted {
+			t.Error("expected user to be soft-deleted")
+		}
+
+		// The userName can be provisioned again.
+		createTestUser(t, h, "carol@example.com", "carol@example.net")
+	})
+
+	t.Run("concurrent creates", func(t *testing.T) {
+		// Both requests link the same existing user, so only one of them may succeed.
+		user, err := db.Users().Create(ctx, database.NewUser{Username: "dave", Email: "dave@example.com", EmailIsVerified: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		results := make(chan int, 2)
+		for _, userName := range []string{"dave", "dave.d"} {
+			go func(userName string) {
+				status, _ := do(t, h, "POST", "/Users", `{"userName": "`+userName+`", "emails": [{"value": "dave@example.com"}]}`)
+				results <- status
+			}(userName)
+		}
+		statuses := []int{<-results, <-results}
+		created := 0
+		for _, status := range statuses {
+			if status == http.StatusCreated {
+				created++
+			}
+		}
+		if created != 1 {
+			t.Errorf("expected exactly one request to succeed, got %v", statuses)
+		}
+		accounts, _, err := newStore(db).list(ctx, sqlf.Sprintf("s.user_id = %s", user.ID), 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(accounts) != 1 {
+			t.Errorf("expected one provisioned user, got %d", len(accounts))
+		}
+	})
+}
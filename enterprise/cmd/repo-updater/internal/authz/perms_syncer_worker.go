@@ -4,8 +4,9 @@ import (
 	"context"
 	"time"
 
-	"github.com/keegancsmith/sqlf"
 	"github.com/sourcegraph/log"
+
+	eiauthz "github.com/sourcegraph/sourcegraph/enterprise/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -85,19 +86,7 @@ func (h *permsSyncerWorker) Handle(_ context.Context, _ log.Logger, record *data
 }
 
 func MakeStore(observationCtx *observation.Context, dbHandle basestore.TransactableHandle) dbworkerstore.Store[*database.PermissionSyncJob] {
-	return dbworkerstore.New(observationCtx, dbHandle, dbworkerstore.Options[*database.PermissionSyncJob]{
-		Name:              "permission_sync_job_worker_store",
-		TableName:         "permission_sync_jobs",
-		ColumnExpressions: database.PermissionSyncJobColumns,
-		Scan:              dbworkerstore.BuildWorkerScan(database.ScanPermissionSyncJob),
-		// NOTE(naman): the priority order to process the queue is as follows:
-		// 1. priority: 10(high) > 5(medium) > 0(low)
-		// 2. process_after: null(scheduled for immediate processing) > 1 > 2(scheudled for processing at a later time than 1)
-		// 3. job_id: 1(old) > 2(enqueued after 1)
-		OrderByExpression: sqlf.Sprintf("permission_sync_jobs.priority DESC, permission_sync_jobs.process_after ASC NULLS FIRST, permission_sync_jobs.id ASC"),
-		MaxNumResets:      5,
-		StalledMaxAge:     time.Second * 30,
-	})
+	return dbworkerstore.New(observationCtx, dbHandle, eiauthz.PermissionSyncJobWorkerStoreOptions)
 }
 
 func MakeWorker(ctx context.Context, observationCtx *observation.Context, workerStore dbworkerstore.Store[*database.PermissionSyncJob], permsSyncer *PermsSyncer) *workerutil.Worker[*database.PermissionSyncJob] {
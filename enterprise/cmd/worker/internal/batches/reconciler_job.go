@@ -55,6 +55,8 @@ func (j *reconcilerJob) Routines(_ context.Context, observationCtx *observation.
 
 	routines := []goroutine.BackgroundRoutine{
 		reconcilerWorker,
+		workers.NewAutoMergeEnqueuer(workCtx, bstore),
+		workers.NewDependencyEnqueuer(workCtx, bstore),
 	}
 
 	return routines, nil
@@ -0,0 +1,40 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/global"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const autoMergeEnqueuerInterval = time.Minute
+
+// NewAutoMergeEnqueuer creates a goroutine.PeriodicGoroutine that enqueues the
+// changesets of batch changes with an auto-merge policy once they are ready to
+// be merged, so that the reconciler merges them. Changesets are enqueued in the
+// same way as when applying a batch change, so merges respect the rollout
+// windows.
+func NewAutoMergeEnqueuer(ctx context.Context, s *store.Store) goroutine.BackgroundRoutine {
+	logger := log.Scoped("auto-merge-enqueuer", "The background routine enqueuing changesets that are ready to be merged automatically")
+
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		"batchchanges.auto-merge-enqueuer", "enqueues changesets that are ready to be merged automatically",
+		autoMergeEnqueuerInterval,
+		goroutine.HandlerFunc(func(ctx context.Context) error {
+			enqueued, err := s.EnqueueAutoMergeableChangesets(ctx, global.DefaultReconcilerEnqueueState())
+			if err != nil {
+				return errors.Wrap(err, "enqueuing auto-mergeable changesets")
+			}
+			if enqueued > 0 {
+				logger.Debug("enqueued auto-mergeable changesets", log.Int("count", enqueued))
+			}
+			return nil
+		}),
+	)
+}
@@ -0,0 +1,40 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/global"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const dependencyEnqueuerInterval = time.Minute
+
+// NewDependencyEnqueuer creates a goroutine.PeriodicGoroutine that enqueues
+// changesets held back because of unmerged dependencies once all of their
+// dependencies have been merged, so that the reconciler publishes them.
+// Changesets are enqueued in the same way as when applying a batch change, so
+// publications respect the rollout windows.
+func NewDependencyEnqueuer(ctx context.Context, s *store.Store) goroutine.BackgroundRoutine {
+	logger := log.Scoped("dependency-enqueuer", "The background routine enqueuing changesets whose dependencies have been merged")
+
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		"batchchanges.dependency-enqueuer", "enqueues changesets whose dependencies have been merged",
+		dependencyEnqueuerInterval,
+		goroutine.HandlerFunc(func(ctx context.Context) error {
+			enqueued, err := s.EnqueueUnblockedChangesets(ctx, global.DefaultReconcilerEnqueueState())
+			if err != nil {
+				return errors.Wrap(err, "enqueuing unblocked changesets")
+			}
+			if enqueued > 0 {
+				logger.Debug("enqueued unblocked changesets", log.Int("count", enqueued))
+			}
+			return nil
+		}),
+	)
+}
@@ -0,0 +1,64 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/service"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const scheduledBatchChangeRunnerInterval = time.Minute
+
+// NewScheduledBatchChangeRunner creates a goroutine.PeriodicGoroutine that
+// starts and advances the runs of batch changes whose batch spec has a
+// schedule.
+func NewScheduledBatchChangeRunner(ctx context.Context, s *store.Store) goroutine.BackgroundRoutine {
+	r := &scheduledBatchChangeRunner{
+		store:  s,
+		logger: log.Scoped("scheduled-batch-change-runner", "The background routine re-running batch changes on a schedule"),
+	}
+
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		"batchchanges.scheduled-runner", "re-runs batch changes on a schedule",
+		scheduledBatchChangeRunnerInterval,
+		goroutine.HandlerFunc(r.handle),
+	)
+}
+
+type scheduledBatchChangeRunner struct {
+	store  *store.Store
+	logger log.Logger
+}
+
+func (r *scheduledBatchChangeRunner) handle(ctx context.Context) error {
+	batchChanges, _, err := r.store.ListBatchChanges(ctx, store.ListBatchChangesOpts{OnlyScheduled: true})
+	if err != nil {
+		return errors.Wrap(err, "listing scheduled batch changes")
+	}
+
+	svc := service.New(r.store)
+
+	var errs error
+	for _, batchChange := range batchChanges {
+		// The run is executed and applied in the name of the last applier of
+		// the batch change, so that it only sees what they have access to.
+		if batchChange.LastApplierID == 0 {
+			continue
+		}
+		userCtx := actor.WithActor(ctx, actor.FromUser(batchChange.LastApplierID))
+
+		if err := svc.RunScheduledBatchChange(userCtx, batchChange); err != nil {
+			r.logger.Warn("scheduled batch change run failed", log.Int64("batchChange", batchChange.ID), log.Error(err))
+			errs = errors.Append(errs, err)
+		}
+	}
+
+	return errs
+}
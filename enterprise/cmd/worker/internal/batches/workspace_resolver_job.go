@@ -48,6 +48,7 @@ func (j *workspaceResolverJob) Routines(_ context.Context, observationCtx *obser
 
 	routines := []goroutine.BackgroundRoutine{
 		resolverWorker,
+		workers.NewScheduledBatchChangeRunner(workCtx, bstore),
 	}
 
 	return routines, nil
@@ -0,0 +1,60 @@
+package azuredevops
+
+import (
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// NewAuthzProviders returns the set of Azure DevOps authz providers derived from the connections.
+//
+// It also returns any simple validation problems with the config, separating these into "serious problems"
+// and "warnings". "Serious problems" are those that should make Sourcegraph set authz.allowAccessByDefault
+// to false. "Warnings" are all other validation problems.
+//
+// This constructor does not and should not directly check connectivity to external services - if
+// desired, callers should use `(*Provider).ValidateConnection` directly to get warnings related
+// to connection issues.
+func NewAuthzProviders(conns []*types.AzureDevOpsConnection) (ps []authz.Provider, problems []string, warnings []string, invalidConnections []string) {
+	for _, c := range conns {
+		p, err := newAuthzProvider(c)
+		if err != nil {
+			invalidConnections = append(invalidConnections, extsvc.TypeAzureDevOps)
+			problems = append(problems, err.Error())
+		}
+		if p == nil {
+			continue
+		}
+		ps = append(ps, p)
+	}
+
+	return ps, problems, warnings, invalidConnections
+}
+
+func newAuthzProvider(c *types.AzureDevOpsConnection) (authz.Provider, error) {
+	// If authorization is not set for this connection, we do not need an
+	// authz provider.
+	if c.Authorization == nil {
+		return nil, nil
+	}
+	if err := licensing.Check(licensing.FeatureACLs); err != nil {
+		return nil, err
+	}
+
+	p, err := NewProvider(c, ProviderOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "creating Azure DevOps authz provider")
+	}
+	return p, nil
+}
+
+// ValidateAuthz validates the authorization fields of the given Azure DevOps
+// external service config.
+func ValidateAuthz(_ *schema.AzureDevOpsConnection) error {
+	// The authorization object has no fields, and the organizations and projects
+	// it needs are already validated with the rest of the connection.
+	return nil
+}
@@ -0,0 +1,388 @@
+// Package azuredevops contains an authorization provider for Azure DevOps.
+package azuredevops
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Provider is an implementation of AuthzProvider that provides repository and
+// user permissions as determined from Azure DevOps.
+//
+// Azure DevOps users are matched to Sourcegraph users by their verified email
+// addresses, and identified by their subject descriptor. A user can read the
+// private repositories for which the access control lists of the Git security
+// namespace effectively allow them to read, which accounts for permissions they
+// inherit from the project and their groups as well as for repository-level denies.
+// Users whose external account holds an OAuth token are instead granted exactly the
+// repositories that the token can list.
+//
+// Repository-centric permissions syncs check the members of the project of a
+// repository against its access control list.
+type Provider struct {
+	urn      string
+	codeHost *extsvc.CodeHost
+	client   *azuredevops.Client
+	orgs     []string
+	projects []string
+}
+
+type ProviderOptions struct {
+	HTTPClient httpcli.Doer
+}
+
+var _ authz.Provider = (*Provider)(nil)
+
+// NewProvider returns a new Azure DevOps authorization provider that uses the
+// token of the given connection to talk to the Azure DevOps API.
+func NewProvider(conn *types.AzureDevOpsConnection, opts ProviderOptions) (*Provider, error) {
+	baseURL, err := url.Parse(conn.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := azuredevops.NewClient(conn.URN, conn.AzureDevOpsConnection, opts.HTTPClient)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repositories can only be looked up by ID within an organization, so we
+	// collect every organization the connection syncs repositories from.
+	orgs := append([]string{}, conn.Orgs...)
+	seen := make(map[string]bool, len(orgs))
+	for _, org := range orgs {
+		seen[org] = true
+	}
+	for _, project := range conn.Projects {
+		org, _, _ := strings.Cut(project, "/")
+		if !seen[org] {
+			seen[org] = true
+			orgs = append(orgs, org)
+		}
+	}
+
+	return &Provider{
+		urn:      conn.URN,
+		codeHost: extsvc.NewCodeHost(baseURL, extsvc.TypeAzureDevOps),
+		client:   client,
+		orgs:     orgs,
+		projects: conn.Projects,
+	}, nil
+}
+
+// ValidateConnection validates that the Provider has access to the Azure DevOps
+// API with the credentials it was configured with.
+func (p *Provider) ValidateConnection(ctx context.Context) error {
+	for _, org := range p.orgs {
+		if _, err := p.client.ListRepositoriesByProjectOrOrg(ctx, azuredevops.ListRepositoriesByProjectOrOrgArgs{ProjectOrOrgName: org}); err != nil {
+			return errors.Wrapf(err, "listing repositories of organization %q", org)
+		}
+	}
+	return nil
+}
+
+func (p *Provider) URN() string {
+	return p.urn
+}
+
+// ServiceID returns the absolute URL that identifies the Azure DevOps instance
+// this provider is configured with.
+func (p *Provider) ServiceID() string { return p.codeHost.ServiceID }
+
+// ServiceType returns the type of this Provider, namely, "azuredevops".
+func (p *Provider) ServiceType() string { return p.codeHost.ServiceType }
+
+// FetchAccount looks up the Azure DevOps identity of the user by their verified
+// emails, in each organization until one is found.
+func (p *Provider) FetchAccount(ctx context.Context, user *types.User, _ []*extsvc.Account, verifiedEmails []string) (*extsvc.Account, error) {
+	for _, org := range p.orgs {
+		for _, email := range verifiedEmails {
+			identities, err := p.client.SearchIdentitiesByEmail(ctx, org, email)
+			if err != nil {
+				return nil, errors.Wrapf(err, "searching identities of organization %q", org)
+			}
+			for _, identity := range identities {
+				if !identity.IsActive || identity.SubjectDescriptor == "" {
+					continue
+				}
+				return p.newAccount(user, identity, email)
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (p *Provider) newAccount(user *types.User, identity azuredevops.Identity, email string) (*extsvc.Account, error) {
+	var data extsvc.AccountData
+	err := azuredevops.SetExternalAccountData(&data, &azuredevops.AccountData{
+		ID:                identity.ID,
+		Descriptor:        identity.Descriptor,
+		SubjectDescriptor: identity.SubjectDescriptor,
+		DisplayName:       identity.ProviderDisplayName,
+		Email:             email,
+	}, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "setting account data")
+	}
+	return &extsvc.Account{
+		UserID: user.ID,
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: p.codeHost.ServiceType,
+			ServiceID:   p.codeHost.ServiceID,
+			AccountID:   identity.SubjectDescriptor,
+		},
+		AccountData: data,
+	}, nil
+}
+
+// FetchUserPerms returns a list of repository IDs (on code host) that the given account
+// has read access on the code host. The repository ID has the same value as it would be
+// used as api.ExternalRepoSpec.ID. The returned list only includes private repository IDs.
+//
+// This method may return partial but valid results in case of error, and it is up to
+// callers to decide whether to discard.
+func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account, _ authz.FetchPermsOptions) (*authz.ExternalUserPermissions, error) {
+	switch {
+	case account == nil:
+		return nil, errors.New("no account provided")
+	case !extsvc.IsHostOfAccount(p.codeHost, account):
+		return nil, errors.Errorf("not a code host of the account: want %q but have %q",
+			p.codeHost.ServiceID, account.AccountSpec.ServiceID)
+	}
+
+	data, tok, err := azuredevops.GetExternalAccountData(ctx, &account.AccountData)
+	if err != nil {
+		return nil, err
+	}
+
+	// With a valid OAuth token, the repositories the user can list are exactly
+	// those they can read.
+	if tok != nil && tok.Valid() {
+		client, err := p.client.WithAuthenticator(&auth.OAuthBearerToken{Token: tok.AccessToken})
+		if err != nil {
+			return nil, err
+		}
+		repos, err := p.listRepos(ctx, client)
+		extIDs := make([]extsvc.RepoID, 0, len(repos))
+		for _, repo := range repos {
+			if isPrivate(repo) {
+				extIDs = append(extIDs, extsvc.RepoID(repo.ID))
+			}
+		}
+		return &authz.ExternalUserPermissions{Exacts: extIDs}, err
+	}
+
+	// Otherwise, the user can read the repositories whose access control lists
+	// effectively allow them to.
+	if data == nil || data.Descriptor == "" {
+		return nil, errors.New("account has no identity descriptor")
+	}
+
+	repos, err := p.listRepos(ctx, p.client)
+	if err != nil {
+		return nil, err
+	}
+
+	// The access control lists of all repositories of a project are fetched at once.
+	type project struct{ org, id string }
+	var projects []project
+	reposByProject := make(map[project][]azuredevops.Repository)
+	for _, repo := range repos {
+		if !isPrivate(repo) {
+			continue
+		}
+		org, err := p.orgOf(repo)
+		if err != nil {
+			return nil, err
+		}
+		key := project{org: org, id: repo.Project.ID}
+		if _, ok := reposByProject[key]; !ok {
+			projects = append(projects, key)
+		}
+		reposByProject[key] = append(reposByProject[key], repo)
+	}
+
+	extIDs := make([]extsvc.RepoID, 0, len(repos))
+	for _, proj := range projects {
+		acls, err := p.client.ListAccessControlLists(ctx, proj.org, azuredevops.GitNamespaceID, azuredevops.ListAccessControlListsArgs{
+			Token:       azuredevops.GitProjectToken(proj.id),
+			Recurse:     true,
+			Descriptors: []string{data.Descriptor},
+		})
+		if err != nil {
+			return &authz.ExternalUserPermissions{Exacts: extIDs}, errors.Wrapf(err, "getting permissions on project %q", proj.id)
+		}
+		acesByToken := make(map[string]*azuredevops.AccessControlEntry, len(acls))
+		for _, acl := range acls {
+			var ace *azuredevops.AccessControlEntry
+			if e, ok := acl.AcesDictionary[data.Descriptor]; ok {
+				ace = &e
+			}
+			acesByToken[acl.Token] = ace
+		}
+
+		for _, repo := range reposByProject[proj] {
+			ace, ok := acesByToken[azuredevops.GitRepositoryToken(repo.Project.ID, repo.ID)]
+			if !ok {
+				// Repositories without explicit permissions have no access control list
+				// and inherit the permissions of their project.
+				ace, ok = acesByToken[azuredevops.GitProjectToken(repo.Project.ID)]
+			}
+			if !ok {
+				// Neither has an access control list, e.g. because permissions are only
+				// set for the whole organization, so ask for the repository itself.
+				ace, err = p.client.GetAccessControlEntry(ctx, proj.org, azuredevops.GitNamespaceID, azuredevops.GitRepositoryToken(repo.Project.ID, repo.ID), data.Descriptor)
+				if err != nil {
+					return &authz.ExternalUserPermissions{Exacts: extIDs}, errors.Wrapf(err, "getting permissions on repository %q", repo.ID)
+				}
+			}
+			if ace.Allows(azuredevops.GitReadPermission) {
+				extIDs = append(extIDs, extsvc.RepoID(repo.ID))
+			}
+		}
+	}
+
+	return &authz.ExternalUserPermissions{Exacts: extIDs}, nil
+}
+
+// FetchRepoPerms returns a list of user IDs (on code host) who have read access to
+// the given repo on the code host. The user ID has the same value as it would be
+// used as extsvc.Account.AccountID. The returned list includes the members of the
+// project of the repository that its access control list effectively allows to
+// read it. Users that are granted access without being a member of the project are
+// only found by user-centric permissions syncs.
+//
+// This method may return partial but valid results in case of error, and it is up to
+// callers to decide whether to discard.
+func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository, _ authz.FetchPermsOptions) ([]extsvc.AccountID, error) {
+	for _, org := range p.orgs {
+		r, err := p.client.GetRepository(ctx, org, repo.ID)
+		if azuredevops.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return p.repoReaders(ctx, org, r)
+	}
+	return nil, errors.Errorf("repository %q not found in any organization of the connection", repo.ID)
+}
+
+// descriptorBatchSize is the number of identities looked up per request. Descriptors
+// are passed in the query string, which bounds how many fit into a single request.
+const descriptorBatchSize = 20
+
+// repoReaders returns the subject descriptors of the members of the project of repo
+// that effectively have the read permission on it.
+func (p *Provider) repoReaders(ctx context.Context, org string, repo azuredevops.Repository) ([]extsvc.AccountID, error) {
+	scope, err := p.client.GetProjectScopeDescriptor(ctx, org, repo.Project.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting scope descriptor of project %q", repo.Project.ID)
+	}
+	users, err := p.client.ListUsersInScope(ctx, org, scope)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing members of project %q", repo.Project.ID)
+	}
+
+	token := azuredevops.GitRepositoryToken(repo.Project.ID, repo.ID)
+	var ids []extsvc.AccountID
+	for start := 0; start < len(users); start += descriptorBatchSize {
+		end := start + descriptorBatchSize
+		if end > len(users) {
+			end = len(users)
+		}
+		subjects := make([]string, 0, end-start)
+		for _, u := range users[start:end] {
+			subjects = append(subjects, u.Descriptor)
+		}
+
+		// Access control lists refer to identity descriptors rather than to the subject
+		// descriptors of graph users.
+		identities, err := p.client.GetIdentitiesBySubjectDescriptors(ctx, org, subjects)
+		if err != nil {
+			return ids, errors.Wrapf(err, "getting identities of members of project %q", repo.Project.ID)
+		}
+		descriptors := make([]string, 0, len(identities))
+		subjectOf := make(map[string]string, len(identities))
+		for _, identity := range identities {
+			if !identity.IsActive || identity.Descriptor == "" || identity.SubjectDescriptor == "" {
+				continue
+			}
+			descriptors = append(descriptors, identity.Descriptor)
+			subjectOf[identity.Descriptor] = identity.SubjectDescriptor
+		}
+		if len(descriptors) == 0 {
+			continue
+		}
+
+		acls, err := p.client.ListAccessControlLists(ctx, org, azuredevops.GitNamespaceID, azuredevops.ListAccessControlListsArgs{
+			Token:       token,
+			Descriptors: descriptors,
+		})
+		if err != nil {
+			return ids, errors.Wrapf(err, "getting permissions on repository %q", repo.ID)
+		}
+		for _, acl := range acls {
+			if acl.Token != token {
+				continue
+			}
+			for _, descriptor := range descriptors {
+				if ace, ok := acl.AcesDictionary[descriptor]; ok && ace.Allows(azuredevops.GitReadPermission) {
+					ids = append(ids, extsvc.AccountID(subjectOf[descriptor]))
+				}
+			}
+		}
+	}
+	return ids, nil
+}
+
+// listRepos lists the repositories the connection syncs that the given client can see.
+func (p *Provider) listRepos(ctx context.Context, client *azuredevops.Client) ([]azuredevops.Repository, error) {
+	var repos []azuredevops.Repository
+	seen := make(map[string]bool)
+	for _, name := range append(append([]string{}, p.projects...), p.orgs...) {
+		rs, err := client.ListRepositoriesByProjectOrOrg(ctx, azuredevops.ListRepositoriesByProjectOrOrgArgs{ProjectOrOrgName: name})
+		if azuredevops.IsNotFound(err) {
+			// The client cannot see the project at all.
+			continue
+		}
+		if err != nil {
+			return repos, err
+		}
+		for _, r := range rs {
+			if !seen[r.ID] {
+				seen[r.ID] = true
+				repos = append(repos, r)
+			}
+		}
+	}
+	return repos, nil
+}
+
+func isPrivate(repo azuredevops.Repository) bool {
+	return repo.Project.Visibility == "private"
+}
+
+// orgOf returns the organization of a repository from its API URL, e.g.
+// https://dev.azure.com/org/{projectID}/_apis/git/repositories/{repoID}.
+func (p *Provider) orgOf(repo azuredevops.Repository) (string, error) {
+	u, err := url.Parse(repo.APIURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing URL of repository %q", repo.ID)
+	}
+	path := strings.TrimPrefix(u.Path, strings.TrimSuffix(p.client.URL.Path, "/"))
+	path = strings.TrimPrefix(path, "/")
+	if i := strings.Index(path, "/"+repo.Project.ID+"/"); i > 0 {
+		return path[:i], nil
+	}
+	return "", errors.Errorf("repository %q has an unexpected URL %q", repo.ID, repo.APIURL)
+}
@@ -0,0 +1,313 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/oauth2"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const oauthToken = "user-oauth-token"
+
+// createTestServer serves an organization "org" with two private repositories in
+// project "p1" and one public repository in project "p2". Identity "bob" can read
+// both private repositories through a group, identity "alice" is denied reading the
+// second one, and identity "carol" has no permissions. All three are members of "p1".
+// A user with the OAuth token can only see the first repository. The tokens of the
+// requested access control lists are recorded in aclRequests, unless it is nil.
+func createTestServer(t *testing.T, aclRequests *[]string) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repo := func(id, project, visibility string) azuredevops.Repository {
+			return azuredevops.Repository{
+				ID:      id,
+				Name:    id,
+				APIURL:  srv.URL + "/org/" + project + "/_apis/git/repositories/" + id,
+				Project: azuredevops.Project{ID: project, Visibility: visibility},
+			}
+		}
+		repos := []azuredevops.Repository{
+			repo("repo1", "p1", "private"),
+			repo("repo2", "p1", "private"),
+			repo("repo3", "p2", "public"),
+		}
+		if r.Header.Get("Authorization") == "Bearer "+oauthToken {
+			repos = repos[:1]
+		}
+
+		var resp any
+		switch path := r.URL.Path; {
+		case path == "/org/_apis/git/repositories":
+			resp = azuredevops.ListRepositoriesResponse{Value: repos, Count: len(repos)}
+		case strings.HasPrefix(path, "/org/_apis/git/repositories/"):
+			id := strings.TrimPrefix(path, "/org/_apis/git/repositories/")
+			for _, r := range repos {
+				if r.ID == id {
+					resp = r
+				}
+			}
+		case path == "/org/_apis/identities":
+			q := r.URL.Query()
+			if subjects := q.Get("subjectDescriptors"); subjects != "" {
+				var identities []azuredevops.Identity
+				for _, subject := range strings.Split(subjects, ",") {
+					identities = append(identities, azuredevops.Identity{Descriptor: "desc." + subject, SubjectDescriptor: subject, IsActive: true})
+				}
+				resp = azuredevops.ListIdentitiesResponse{Value: identities, Count: len(identities)}
+			} else if q.Get("filterValue") == "alice@example.com" {
+				resp = azuredevops.ListIdentitiesResponse{Value: []azuredevops.Identity{
+					{ID: "1", SubjectDescriptor: "inactive", IsActive: false},
+					{ID: "2", Descriptor: "desc.alice", SubjectDescriptor: "alice", ProviderDisplayName: "Alice", IsActive: true},
+				}}
+			} else {
+				resp = azuredevops.ListIdentitiesResponse{}
+			}
+		case path == "/org/_apis/graph/descriptors/p1":
+			resp = map[string]string{"value": "scp.p1"}
+		case path == "/org/_apis/graph/users" && r.URL.Query().Get("scopeDescriptor") == "scp.p1":
+			// Members are served in two pages.
+			if r.URL.Query().Get("continuationToken") == "" {
+				w.Header().Set("X-MS-ContinuationToken", "page2")
+				resp = azuredevops.ListGraphUsersResponse{Value: []azuredevops.GraphUser{{Descriptor: "alice"}, {Descriptor: "bob"}}}
+			} else {
+				resp = azuredevops.ListGraphUsersResponse{Value: []azuredevops.GraphUser{{Descriptor: "carol"}}}
+			}
+		case path == "/org/_apis/accesscontrollists/"+azuredevops.GitNamespaceID:
+			q := r.URL.Query()
+			if q.Get("includeExtendedInfo") != "true" {
+				t.Errorf("access control lists requested without extended info")
+			}
+			if aclRequests != nil {
+				*aclRequests = append(*aclRequests, q.Get("token"))
+			}
+			// Only the project and repo2 have explicit permissions; repo1 inherits those
+			// of the project.
+			effective := map[string]map[string]azuredevops.AccessControlEntryExtendedInfo{
+				"repoV2/p1": {
+					"desc.alice": {EffectiveAllow: 2},
+					"desc.bob":   {EffectiveAllow: 2 | 4},
+				},
+				"repoV2/p1/repo1": {
+					"desc.alice": {EffectiveAllow: 2, InheritedAllow: 2},
+					"desc.bob":   {EffectiveAllow: 2 | 4, InheritedAllow: 2 | 4},
+				},
+				"repoV2/p1/repo2": {
+					"desc.alice": {EffectiveAllow: 2, EffectiveDeny: 2, InheritedAllow: 2},
+					"desc.bob":   {EffectiveAllow: 2, InheritedAllow: 2},
+				},
+			}
+			explicit := map[string]bool{"repoV2/p1": true, "repoV2/p1/repo2": true}
+			tokens := []string{q.Get("token")}
+			if q.Get("recurse") == "true" {
+				tokens = nil
+				for _, token := range []string{"repoV2/p1", "repoV2/p1/repo1", "repoV2/p1/repo2"} {
+					if explicit[token] && (token == q.Get("token") || strings.HasPrefix(token, q.Get("token")+"/")) {
+						tokens = append(tokens, token)
+					}
+				}
+			}
+			var acls []azuredevops.AccessControlList
+			for _, token := range tokens {
+				acl := azuredevops.AccessControlList{Token: token, InheritPermissions: true, AcesDictionary: map[string]azuredevops.AccessControlEntry{}}
+				for _, descriptor := range strings.Split(q.Get("descriptors"), ",") {
+					if info, ok := effective[token][descriptor]; ok {
+						acl.AcesDictionary[descriptor] = azuredevops.AccessControlEntry{
+							Descriptor:   descriptor,
+							Deny:         info.EffectiveDeny,
+							ExtendedInfo: &info,
+						}
+					}
+				}
+				acls = append(acls, acl)
+			}
+			resp = azuredevops.ListAccessControlListsResponse{Value: acls, Count: len(acls)}
+		}
+		if resp == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestProvider(t *testing.T, url string) *Provider {
+	t.Helper()
+	p, err := NewProvider(&types.AzureDevOpsConnection{
+		URN: "extsvc:azuredevops:1",
+		AzureDevOpsConnection: &schema.AzureDevOpsConnection{
+			Url:           url,
+			Username:      "admin",
+			Token:         "admin-token",
+			Projects:      []string{"org/p1"},
+			Authorization: &schema.AzureDevOpsAuthorization{},
+		},
+	}, ProviderOptions{HTTPClient: http.DefaultClient})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestProvider_FetchAccount(t *testing.T) {
+	srv := createTestServer(t, nil)
+	p := newTestProvider(t, srv.URL)
+	user := &types.User{ID: 42}
+
+	t.Run("no matching identity", func(t *testing.T) {
+		acct, err := p.FetchAccount(context.Background(), user, nil, []string{"carol@example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if acct != nil {
+			t.Fatalf("want no account, got %+v", acct)
+		}
+	})
+
+	t.Run("matching identity", func(t *testing.T) {
+		acct, err := p.FetchAccount(context.Background(), user, nil, []string{"carol@example.com", "alice@example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := extsvc.AccountSpec{
+			ServiceType: extsvc.TypeAzureDevOps,
+			ServiceID:   srv.URL + "/",
+			AccountID:   "alice",
+		}
+		if diff := cmp.Diff(want, acct.AccountSpec); diff != "" {
+			t.Fatalf("unexpected account spec (-want +got):\n%s", diff)
+		}
+		data, _, err := azuredevops.GetExternalAccountData(context.Background(), &acct.AccountData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data.Email != "alice@example.com" || data.DisplayName != "Alice" || data.Descriptor != "desc.alice" {
+			t.Fatalf("unexpected account data: %+v", data)
+		}
+	})
+}
+
+func TestProvider_FetchUserPerms(t *testing.T) {
+	var aclRequests []string
+	srv := createTestServer(t, &aclRequests)
+	p := newTestProvider(t, srv.URL)
+
+	account := func(t *testing.T, id string, tok *oauth2.Token) *extsvc.Account {
+		acct := &extsvc.Account{
+			AccountSpec: extsvc.AccountSpec{
+				ServiceType: extsvc.TypeAzureDevOps,
+				ServiceID:   srv.URL + "/",
+				AccountID:   id,
+			},
+		}
+		if err := azuredevops.SetExternalAccountData(&acct.AccountData, &azuredevops.AccountData{Descriptor: "desc." + id, SubjectDescriptor: id}, tok); err != nil {
+			t.Fatal(err)
+		}
+		return acct
+	}
+
+	for _, tc := range []struct {
+		name    string
+		account *extsvc.Account
+		want    []extsvc.RepoID
+	}{
+		{
+			name:    "allowed through a group",
+			account: account(t, "bob", nil),
+			want:    []extsvc.RepoID{"repo1", "repo2"},
+		},
+		{
+			name:    "denied on a repository",
+			account: account(t, "alice", nil),
+			want:    []extsvc.RepoID{"repo1"},
+		},
+		{
+			name:    "no permissions",
+			account: account(t, "carol", nil),
+			want:    []extsvc.RepoID{},
+		},
+		{
+			name:    "OAuth token",
+			account: account(t, "carol", &oauth2.Token{AccessToken: oauthToken}),
+			want:    []extsvc.RepoID{"repo1"},
+		},
+		{
+			name:    "expired OAuth token",
+			account: account(t, "bob", &oauth2.Token{AccessToken: oauthToken, Expiry: time.Now().Add(-time.Hour)}),
+			want:    []extsvc.RepoID{"repo1", "repo2"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			aclRequests = nil
+			perms, err := p.FetchUserPerms(context.Background(), tc.account, authz.FetchPermsOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, perms.Exacts); diff != "" {
+				t.Fatalf("unexpected repositories (-want +got):\n%s", diff)
+			}
+			// The access control lists of a project are fetched in a single request.
+			if len(aclRequests) > 1 {
+				t.Fatalf("want at most one access control list request, got %q", aclRequests)
+			}
+		})
+	}
+
+	t.Run("account of another code host", func(t *testing.T) {
+		acct := account(t, "alice", nil)
+		acct.ServiceID = "https://dev.azure.com/"
+		if _, err := p.FetchUserPerms(context.Background(), acct, authz.FetchPermsOptions{}); err == nil {
+			t.Fatal("want an error")
+		}
+	})
+}
+
+func TestProvider_FetchRepoPerms(t *testing.T) {
+	srv := createTestServer(t, nil)
+	p := newTestProvider(t, srv.URL)
+
+	repo := func(id string) *extsvc.Repository {
+		return &extsvc.Repository{ExternalRepoSpec: api.ExternalRepoSpec{ID: id}}
+	}
+
+	for _, tc := range []struct {
+		repo string
+		want []extsvc.AccountID
+	}{
+		{repo: "repo1", want: []extsvc.AccountID{"alice", "bob"}},
+		// alice is denied reading repo2, carol has no permissions at all.
+		{repo: "repo2", want: []extsvc.AccountID{"bob"}},
+	} {
+		t.Run(tc.repo, func(t *testing.T) {
+			ids, err := p.FetchRepoPerms(context.Background(), repo(tc.repo), authz.FetchPermsOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, ids); diff != "" {
+				t.Fatalf("unexpected accounts (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("unknown repository", func(t *testing.T) {
+		if _, err := p.FetchRepoPerms(context.Background(), repo("missing"), authz.FetchPermsOptions{}); err == nil {
+			t.Fatal("want an error")
+		}
+	})
+}
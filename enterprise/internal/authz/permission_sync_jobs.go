@@ -0,0 +1,26 @@
+package authz
+
+import (
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// PermissionSyncJobWorkerStoreOptions are the options of the dbworker store
+// processing the permission_sync_jobs table.
+var PermissionSyncJobWorkerStoreOptions = dbworkerstore.Options[*database.PermissionSyncJob]{
+	Name:              "permission_sync_job_worker_store",
+	TableName:         "permission_sync_jobs",
+	ColumnExpressions: database.PermissionSyncJobColumns,
+	Scan:              dbworkerstore.BuildWorkerScan(database.ScanPermissionSyncJob),
+	// NOTE(naman): the priority order to process the queue is as follows:
+	// 1. priority: 10(high) > 5(medium) > 0(low)
+	// 2. process_after: null(scheduled for immediate processing) > 1 > 2(scheudled for processing at a later time than 1)
+	// 3. job_id: 1(old) > 2(enqueued after 1)
+	OrderByExpression: sqlf.Sprintf("permission_sync_jobs.priority DESC, permission_sync_jobs.process_after ASC NULLS FIRST, permission_sync_jobs.id ASC"),
+	MaxNumResets:      5,
+	StalledMaxAge:     time.Second * 30,
+}
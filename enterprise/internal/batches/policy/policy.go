@@ -0,0 +1,110 @@
+// Package policy evaluates the site-wide policies configured in
+// batchChanges.policies against the changes a changeset would publish.
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+	"github.com/sourcegraph/go-diff/diff"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Changeset is the part of a changeset spec that policies are evaluated
+// against.
+type Changeset struct {
+	Title   string
+	HeadRef string
+	Diff    []byte
+}
+
+// Check evaluates the given policies against the changeset and returns a
+// human-readable description of each violated policy. An error is only
+// returned if the policies or the diff are malformed.
+func Check(policies *schema.BatchChangesPolicies, cs Changeset) ([]string, error) {
+	if policies == nil {
+		return nil, nil
+	}
+
+	var violations []string
+
+	if policies.RequiredTitlePrefix != "" && !strings.HasPrefix(cs.Title, policies.RequiredTitlePrefix) {
+		violations = append(violations, fmt.Sprintf("title %q doesn't start with the required prefix %q", cs.Title, policies.RequiredTitlePrefix))
+	}
+
+	branch := strings.TrimPrefix(gitdomain.EnsureRefPrefix(cs.HeadRef), "refs/heads/")
+	for _, pattern := range policies.ProtectedBranches {
+		matched, err := doublestar.Match(pattern, branch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid protected branch pattern %q", pattern)
+		}
+		if matched {
+			violations = append(violations, fmt.Sprintf("branch %q is protected by pattern %q", branch, pattern))
+		}
+	}
+
+	if len(policies.ForbiddenPaths) == 0 && policies.MaxChangedLines == 0 {
+		return violations, nil
+	}
+
+	fileDiffs, err := diff.ParseMultiFileDiff(cs.Diff)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing diff")
+	}
+
+	changedLines := 0
+	for _, fd := range fileDiffs {
+		for _, hunk := range fd.Hunks {
+			for _, line := range strings.Split(string(hunk.Body), "\n") {
+				if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+					changedLines++
+				}
+			}
+		}
+
+		for _, path := range diffPaths(fd) {
+			for _, pattern := range policies.ForbiddenPaths {
+				// ** matches any number of directories, including none, so
+				// that **/*.lock also matches a lockfile at the root.
+				matched, err := doublestar.Match(pattern, path)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid forbidden path pattern %q", pattern)
+				}
+				if matched {
+					violations = append(violations, fmt.Sprintf("file %q matches forbidden path %q", path, pattern))
+					break
+				}
+			}
+		}
+	}
+
+	if policies.MaxChangedLines > 0 && changedLines > policies.MaxChangedLines {
+		violations = append(violations, fmt.Sprintf("diff changes %d lines, more than the maximum of %d", changedLines, policies.MaxChangedLines))
+	}
+
+	return violations, nil
+}
+
+// diffPaths returns the distinct paths of the files touched by the file diff,
+// without the a/ and b/ prefixes. /dev/null, which denotes a created or
+// deleted file, is skipped.
+func diffPaths(fd *diff.FileDiff) []string {
+	var paths []string
+	for _, name := range []string{fd.OrigName, fd.NewName} {
+		if name == "" || name == "/dev/null" {
+			continue
+		}
+		if strings.HasPrefix(name, "a/") || strings.HasPrefix(name, "b/") {
+			name = name[2:]
+		}
+		if len(paths) == 1 && paths[0] == name {
+			continue
+		}
+		paths = append(paths, name)
+	}
+	return paths
+}
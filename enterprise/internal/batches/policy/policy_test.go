@@ -0,0 +1,122 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const testDiff = `diff --git a/README.md b/README.md
+index 1234567..89abcde 100644
+--- a/README.md
++++ b/README.md
+@@ -1 +1 @@
+-Hello
++Hello World
+diff --git a/.github/workflows/ci.yml b/.github/workflows/ci.yml
+new file mode 100644
+index 0000000..89abcde
+--- /dev/null
++++ b/.github/workflows/ci.yml
+@@ -0,0 +1,2 @@
++on: push
++jobs: {}
+diff --git a/web/yarn.lock b/web/yarn.lock
+deleted file mode 100644
+index 1234567..0000000
+--- a/web/yarn.lock
++++ /dev/null
+@@ -1 +0,0 @@
+-# yarn lockfile v1
+`
+
+func TestCheck(t *testing.T) {
+	cs := Changeset{
+		Title:   "Update README",
+		HeadRef: "refs/heads/release/4.4",
+		Diff:    []byte(testDiff),
+	}
+
+	tests := map[string]struct {
+		policies *schema.BatchChangesPolicies
+		want     []string
+		wantErr  bool
+	}{
+		"no policies": {},
+		"all satisfied": {
+			policies: &schema.BatchChangesPolicies{
+				ForbiddenPaths:      []string{"**/*.go"},
+				MaxChangedLines:     5,
+				RequiredTitlePrefix: "Update",
+				ProtectedBranches:   []string{"main", "release/*/*"},
+			},
+		},
+		"title prefix": {
+			policies: &schema.BatchChangesPolicies{RequiredTitlePrefix: "[batch] "},
+			want:     []string{`title "Update README" doesn't start with the required prefix "[batch] "`},
+		},
+		"protected branch": {
+			policies: &schema.BatchChangesPolicies{ProtectedBranches: []string{"main", "release/*"}},
+			want:     []string{`branch "release/4.4" is protected by pattern "release/*"`},
+		},
+		"forbidden paths": {
+			policies: &schema.BatchChangesPolicies{ForbiddenPaths: []string{"**/*.lock", ".github/workflows/**", "*.md"}},
+			want: []string{
+				`file "README.md" matches forbidden path "*.md"`,
+				`file ".github/workflows/ci.yml" matches forbidden path ".github/workflows/**"`,
+				`file "web/yarn.lock" matches forbidden path "**/*.lock"`,
+			},
+		},
+		"max changed lines": {
+			policies: &schema.BatchChangesPolicies{MaxChangedLines: 4},
+			want:     []string{"diff changes 5 lines, more than the maximum of 4"},
+		},
+		"invalid pattern": {
+			policies: &schema.BatchChangesPolicies{ForbiddenPaths: []string{"[a-"}},
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have, err := Check(tc.policies, cs)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Errorf("wrong violations (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheck_RootFile(t *testing.T) {
+	cs := Changeset{
+		HeadRef: "refs/heads/update-deps",
+		Diff: []byte(`diff --git a/yarn.lock b/yarn.lock
+index 1234567..89abcde 100644
+--- a/yarn.lock
++++ b/yarn.lock
+@@ -1 +1 @@
+-# yarn lockfile v1
++# yarn lockfile v2
+`),
+	}
+
+	have, err := Check(&schema.BatchChangesPolicies{ForbiddenPaths: []string{"**/*.lock"}}, cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`file "yarn.lock" matches forbidden path "**/*.lock"`}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("wrong violations (-want +got):\n%s", diff)
+	}
+}
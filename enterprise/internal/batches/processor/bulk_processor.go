@@ -178,7 +178,11 @@ func (b *bulkProcessor) mergeChangeset(ctx context.Context, job *btypes.Changese
 		TargetRepo: b.repo,
 		RemoteRepo: remoteRepo,
 	}
-	if err := b.css.MergeChangeset(ctx, cs, typedPayload.Squash); err != nil {
+	strategy := btypes.ChangesetMergeStrategyDefault
+	if typedPayload.Squash {
+		strategy = btypes.ChangesetMergeStrategySquash
+	}
+	if err := b.css.MergeChangeset(ctx, cs, strategy); err != nil {
 		return err
 	}
 
@@ -13,12 +13,14 @@ import (
 
 	"github.com/sourcegraph/log"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/policy"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/state"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/webhooks"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -82,6 +84,10 @@ func (e *executor) Run(ctx context.Context, plan *Plan) (err error) {
 		return errors.Wrap(err, "failed to load repository")
 	}
 
+	if err := e.checkPolicies(plan); err != nil {
+		return err
+	}
+
 	for _, op := range plan.Ops.ExecutionOrder() {
 		switch op {
 		case btypes.ReconcilerOperationSync:
@@ -123,6 +129,9 @@ func (e *executor) Run(ctx context.Context, plan *Plan) (err error) {
 		case btypes.ReconcilerOperationReattach:
 			e.reattachChangeset()
 
+		case btypes.ReconcilerOperationMerge:
+			err = e.mergeChangeset(ctx, plan.MergeStrategy)
+
 		default:
 			err = errors.Errorf("executor operation %q not implemented", op)
 		}
@@ -288,6 +297,11 @@ func (e *executor) publishChangeset(ctx context.Context, asDraft bool) (err erro
 		}
 	}
 
+	// Request reviews, unless we already did so in a previous attempt.
+	if len(e.ch.RequestedReviewers) == 0 {
+		e.requestReviewers(ctx, css, cs)
+	}
+
 	// Set the changeset to published.
 	e.ch.PublicationState = btypes.ChangesetPublicationStatePublished
 
@@ -487,6 +501,43 @@ func (e *executor) closeChangeset(ctx context.Context) (err error) {
 	return nil
 }
 
+// mergeChangeset merges the changeset on its code host with the given
+// strategy. If the code host refuses to merge the changeset, the changeset is
+// left open and the refusal is recorded, so that merging it isn't retried
+// until the changeset changes.
+func (e *executor) mergeChangeset(ctx context.Context, strategy btypes.ChangesetMergeStrategy) (err error) {
+	css, err := e.changesetSource(ctx)
+	if err != nil {
+		return err
+	}
+
+	remoteRepo, err := e.remoteRepo(ctx)
+	if err != nil {
+		return err
+	}
+
+	cs := &sources.Changeset{
+		Changeset:  e.ch,
+		RemoteRepo: remoteRepo,
+		TargetRepo: e.targetRepo,
+	}
+
+	e.ch.LastMergeAttemptAt = e.tx.Clock()()
+	if err := css.MergeChangeset(ctx, cs, strategy); err != nil {
+		var notMergeable sources.ChangesetNotMergeableError
+		if errors.As(err, &notMergeable) {
+			e.logger.Debug("changeset not mergeable", log.Int64("changeset", e.ch.ID), log.Error(err))
+			msg := notMergeable.Error()
+			e.ch.MergeFailureMessage = &msg
+			return nil
+		}
+		return errors.Wrap(err, "merging changeset")
+	}
+
+	e.ch.MergeFailureMessage = nil
+	return nil
+}
+
 // undraftChangeset marks the given changeset on its code host as ready for review.
 func (e *executor) undraftChangeset(ctx context.Context) (err error) {
 	css, err := e.changesetSource(ctx)
@@ -747,6 +798,43 @@ func decorateChangesetBody(ctx context.Context, tx getBatchChanger, nsStore getN
 	return fmt.Sprintf("%s\n\n%s", body, bcl), nil
 }
 
+// checkPolicies returns errPolicyViolations if the plan pushes to, publishes or
+// updates the changeset on the code host, and the changeset spec violates the
+// batch changes policies configured on the site.
+func (e *executor) checkPolicies(plan *Plan) error {
+	if e.spec == nil {
+		return nil
+	}
+
+	writes := false
+	for _, op := range plan.Ops {
+		switch op {
+		case btypes.ReconcilerOperationPush,
+			btypes.ReconcilerOperationPublish,
+			btypes.ReconcilerOperationPublishDraft,
+			btypes.ReconcilerOperationUndraft,
+			btypes.ReconcilerOperationUpdate:
+			writes = true
+		}
+	}
+	if !writes {
+		return nil
+	}
+
+	violations, err := policy.Check(conf.Get().BatchChangesPolicies, policy.Changeset{
+		Title:   e.spec.Title,
+		HeadRef: e.spec.HeadRef,
+		Diff:    e.spec.Diff,
+	})
+	if err != nil {
+		return errors.Wrap(err, "checking batch changes policies")
+	}
+	if len(violations) > 0 {
+		return errPolicyViolations{violations: violations}
+	}
+	return nil
+}
+
 // errPublishSameBranch is returned by publish changeset if a changeset with
 // the same external branch already exists in the database and is owned by
 // another batch change.
@@ -760,6 +848,17 @@ func (e errPublishSameBranch) Error() string {
 
 func (e errPublishSameBranch) NonRetryable() bool { return true }
 
+// errPolicyViolations is returned if the changeset spec violates the batch
+// changes policies configured on the site. It is a terminal error, since the
+// changeset spec or the policies have to be changed first.
+type errPolicyViolations struct{ violations []string }
+
+func (e errPolicyViolations) Error() string {
+	return fmt.Sprintf("changeset violates the batch changes policies of this Sourcegraph instance:\n- %s", strings.Join(e.violations, "\n- "))
+}
+
+func (e errPolicyViolations) NonRetryable() bool { return true }
+
 // errNoSSHCredential is returned, if the  clone URL of the repository uses the
 // ssh:// scheme, but the authenticator doesn't support SSH pushes.
 type errNoSSHCredential struct{}
@@ -21,6 +21,7 @@ import (
 	bt "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	et "github.com/sourcegraph/sourcegraph/internal/encryption/testing"
@@ -36,6 +37,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/batches/git"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestExecutor_ExecutePlan(t *testing.T) {
@@ -790,6 +792,59 @@ func TestExecutor_ExecutePlan_PublishedChangesetDuplicateBranch(t *testing.T) {
 	}
 }
 
+func TestExecutor_ExecutePlan_PolicyViolations(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	ctx := context.Background()
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+
+	bstore := store.New(db, &observation.TestContext, et.TestKey{})
+
+	repo, _ := bt.CreateTestRepo(t, ctx, db)
+
+	bt.MockConfig(t, &conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		BatchChangesPolicies: &schema.BatchChangesPolicies{
+			RequiredTitlePrefix: "[batch] ",
+			ProtectedBranches:   []string{"main"},
+		},
+	}})
+
+	plan := &Plan{}
+	plan.AddOp(btypes.ReconcilerOperationPush)
+	plan.AddOp(btypes.ReconcilerOperationPublish)
+	plan.ChangesetSpec = bt.BuildChangesetSpec(t, bt.TestSpecOpts{
+		Repo:      repo.ID,
+		HeadRef:   "refs/heads/main",
+		Title:     "Update README",
+		Typ:       btypes.ChangesetSpecTypeBranch,
+		Published: true,
+	})
+	plan.Changeset = bt.BuildChangeset(bt.TestChangesetOpts{Repo: repo.ID})
+
+	fakeSource := &stesting.FakeChangesetSource{}
+	err := executePlan(ctx, logtest.Scoped(t), nil, stesting.NewFakeSourcer(nil, fakeSource), true, bstore, plan)
+	if err == nil {
+		t.Fatal("reconciler did not return error")
+	}
+
+	// We expect a non-retryable error listing all violations to be returned.
+	if !errcode.IsNonRetryable(err) {
+		t.Fatalf("error is not non-retryabe. have=%s", err)
+	}
+	for _, want := range []string{`prefix "[batch] "`, `branch "main" is protected`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q doesn't contain %q", err, want)
+		}
+	}
+
+	if fakeSource.CreateChangesetCalled {
+		t.Fatal("changeset was published despite violating policies")
+	}
+}
+
 func TestExecutor_ExecutePlan_AvoidLoadingChangesetSource(t *testing.T) {
 	logger := logtest.Scoped(t)
 	ctx := context.Background()
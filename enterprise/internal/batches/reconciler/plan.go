@@ -24,6 +24,7 @@ var operationPrecedence = map[btypes.ReconcilerOperation]int{
 	btypes.ReconcilerOperationUpdate:       4,
 	btypes.ReconcilerOperationSleep:        5,
 	btypes.ReconcilerOperationSync:         6,
+	btypes.ReconcilerOperationMerge:        7,
 }
 
 type Operations []btypes.ReconcilerOperation
@@ -98,11 +99,90 @@ type Plan struct {
 	// The Delta between a possible previous ChangesetSpec and the current
 	// ChangesetSpec.
 	Delta *ChangesetSpecDelta
+
+	// The strategy used to merge the changeset, if the plan merges it.
+	MergeStrategy btypes.ChangesetMergeStrategy
 }
 
 func (p *Plan) AddOp(op btypes.ReconcilerOperation) { p.Ops = append(p.Ops, op) }
 func (p *Plan) SetOp(op btypes.ReconcilerOperation) { p.Ops = Operations{op} }
 
+// AddAutoMerge adds a merge operation with the given strategy to the plan, if
+// the changeset is ready to be merged automatically. That is only the case if
+// nothing else needs to be done to it, since pushing to or updating the
+// changeset invalidates its checks and reviews.
+func (p *Plan) AddAutoMerge(strategy btypes.ChangesetMergeStrategy) {
+	if strategy == "" || !p.Ops.IsNone() || !readyToAutoMerge(p.Changeset) {
+		return
+	}
+
+	p.MergeStrategy = strategy
+	p.AddOp(btypes.ReconcilerOperationMerge)
+}
+
+// OpensForReview returns whether the plan publishes or undrafts the changeset,
+// which opens it for review on the code host.
+func (p *Plan) OpensForReview() bool {
+	for _, op := range p.Ops {
+		if op == btypes.ReconcilerOperationPublish || op == btypes.ReconcilerOperationUndraft {
+			return true
+		}
+	}
+	return false
+}
+
+// HoldForDependencies changes the plan so that the changeset isn't opened for
+// review while changesets it depends on haven't been merged yet. Instead of
+// publishing it, it's published as a draft if the code host supports drafts,
+// or not published at all otherwise. It is not undrafted either way.
+func (p *Plan) HoldForDependencies() {
+	supportsDraft := p.Changeset.SupportsDraft()
+	publish := false
+	for _, op := range p.Ops {
+		if op == btypes.ReconcilerOperationPublish {
+			publish = true
+		}
+	}
+
+	ops := Operations{}
+	for _, op := range p.Ops {
+		switch {
+		case op == btypes.ReconcilerOperationUndraft:
+			continue
+		case op == btypes.ReconcilerOperationPublish && supportsDraft:
+			ops = append(ops, btypes.ReconcilerOperationPublishDraft)
+		case publish && !supportsDraft && (op == btypes.ReconcilerOperationPublish || op == btypes.ReconcilerOperationPush):
+			continue
+		default:
+			ops = append(ops, op)
+		}
+	}
+	p.Ops = ops
+}
+
+// readyToAutoMerge returns whether the given changeset is an open changeset
+// that is still attached to the batch change owning it, all of whose checks
+// passed, which has been approved, and which the code host didn't report as
+// conflicting.
+func readyToAutoMerge(ch *btypes.Changeset) bool {
+	if ch.Conflicting() || ch.OwnedByBatchChangeID == 0 || ch.Closing {
+		return false
+	}
+
+	attached := false
+	for _, assoc := range ch.BatchChanges {
+		if assoc.BatchChangeID == ch.OwnedByBatchChangeID {
+			attached = !assoc.Detach && !assoc.Archive && !assoc.IsArchived
+		}
+	}
+
+	return attached &&
+		ch.PublicationState == btypes.ChangesetPublicationStatePublished &&
+		ch.ExternalState == btypes.ChangesetExternalStateOpen &&
+		ch.ExternalCheckState == btypes.ChangesetCheckStatePassed &&
+		ch.ExternalReviewState == btypes.ChangesetReviewStateApproved
+}
+
 // DeterminePlan looks at the given changeset to determine what action the
 // reconciler should take.
 // It consumes the current and the previous changeset spec, if they exist. If
@@ -6,6 +6,7 @@ import (
 	bt "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 )
 
 func TestDetermineReconcilerPlan(t *testing.T) {
@@ -463,3 +464,163 @@ func TestDetermineReconcilerPlan(t *testing.T) {
 func uiPublicationStatePtr(state btypes.ChangesetUiPublicationState) *btypes.ChangesetUiPublicationState {
 	return &state
 }
+
+func TestPlanAddAutoMerge(t *testing.T) {
+	t.Parallel()
+
+	readyChangeset := func() *btypes.Changeset {
+		return &btypes.Changeset{
+			OwnedByBatchChangeID: 1,
+			BatchChanges:         []btypes.BatchChangeAssoc{{BatchChangeID: 1}},
+			PublicationState:     btypes.ChangesetPublicationStatePublished,
+			ExternalState:        btypes.ChangesetExternalStateOpen,
+			ExternalCheckState:   btypes.ChangesetCheckStatePassed,
+			ExternalReviewState:  btypes.ChangesetReviewStateApproved,
+		}
+	}
+
+	tcs := []struct {
+		name      string
+		strategy  btypes.ChangesetMergeStrategy
+		ops       Operations
+		changeset func(*btypes.Changeset)
+		wantMerge bool
+	}{
+		{
+			name:      "ready",
+			strategy:  btypes.ChangesetMergeStrategySquash,
+			wantMerge: true,
+		},
+		{
+			name: "no auto-merge policy",
+		},
+		{
+			name:     "other operations planned",
+			strategy: btypes.ChangesetMergeStrategyMerge,
+			ops:      Operations{btypes.ReconcilerOperationPush, btypes.ReconcilerOperationSync},
+		},
+		{
+			name:      "checks pending",
+			strategy:  btypes.ChangesetMergeStrategyMerge,
+			changeset: func(ch *btypes.Changeset) { ch.ExternalCheckState = btypes.ChangesetCheckStatePending },
+		},
+		{
+			name:      "changes requested",
+			strategy:  btypes.ChangesetMergeStrategyMerge,
+			changeset: func(ch *btypes.Changeset) { ch.ExternalReviewState = btypes.ChangesetReviewStateChangesRequested },
+		},
+		{
+			name:      "draft",
+			strategy:  btypes.ChangesetMergeStrategyMerge,
+			changeset: func(ch *btypes.Changeset) { ch.ExternalState = btypes.ChangesetExternalStateDraft },
+		},
+		{
+			name:      "imported",
+			strategy:  btypes.ChangesetMergeStrategyMerge,
+			changeset: func(ch *btypes.Changeset) { ch.OwnedByBatchChangeID = 0 },
+		},
+		{
+			name:      "archived",
+			strategy:  btypes.ChangesetMergeStrategyMerge,
+			changeset: func(ch *btypes.Changeset) { ch.BatchChanges[0].IsArchived = true },
+		},
+		{
+			name:      "detached",
+			strategy:  btypes.ChangesetMergeStrategyMerge,
+			changeset: func(ch *btypes.Changeset) { ch.BatchChanges[0].Detach = true },
+		},
+		{
+			name:     "conflicting",
+			strategy: btypes.ChangesetMergeStrategyMerge,
+			changeset: func(ch *btypes.Changeset) {
+				ch.Metadata = &github.PullRequest{Mergeable: github.PullRequestMergeableStateConflicting}
+			},
+		},
+		{
+			name:     "mergeability unknown",
+			strategy: btypes.ChangesetMergeStrategyMerge,
+			changeset: func(ch *btypes.Changeset) {
+				ch.Metadata = &github.PullRequest{Mergeable: github.PullRequestMergeableStateUnknown}
+			},
+			wantMerge: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ch := readyChangeset()
+			if tc.changeset != nil {
+				tc.changeset(ch)
+			}
+
+			plan := &Plan{Changeset: ch, Ops: tc.ops}
+			plan.AddAutoMerge(tc.strategy)
+
+			merges := false
+			for _, op := range plan.Ops {
+				if op == btypes.ReconcilerOperationMerge {
+					merges = true
+				}
+			}
+			if merges != tc.wantMerge {
+				t.Fatalf("wrong merge operation. want=%t, have=%t (ops: %s)", tc.wantMerge, merges, plan.Ops)
+			}
+			if tc.wantMerge && plan.MergeStrategy != tc.strategy {
+				t.Fatalf("wrong merge strategy. want=%q, have=%q", tc.strategy, plan.MergeStrategy)
+			}
+		})
+	}
+}
+
+func TestPlanHoldForDependencies(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name        string
+		serviceType string
+		ops         Operations
+		wantOps     Operations
+	}{
+		{
+			name:        "publish as draft",
+			serviceType: extsvc.TypeGitHub,
+			ops:         Operations{btypes.ReconcilerOperationPush, btypes.ReconcilerOperationPublish},
+			wantOps:     Operations{btypes.ReconcilerOperationPush, btypes.ReconcilerOperationPublishDraft},
+		},
+		{
+			name:        "don't publish without draft support",
+			serviceType: extsvc.TypeBitbucketServer,
+			ops:         Operations{btypes.ReconcilerOperationPush, btypes.ReconcilerOperationPublish},
+			wantOps:     Operations{},
+		},
+		{
+			name:        "don't undraft",
+			serviceType: extsvc.TypeGitHub,
+			ops:         Operations{btypes.ReconcilerOperationUndraft, btypes.ReconcilerOperationUpdate},
+			wantOps:     Operations{btypes.ReconcilerOperationUpdate},
+		},
+		{
+			name:        "draft publication is kept",
+			serviceType: extsvc.TypeGitLab,
+			ops:         Operations{btypes.ReconcilerOperationPush, btypes.ReconcilerOperationPublishDraft},
+			wantOps:     Operations{btypes.ReconcilerOperationPush, btypes.ReconcilerOperationPublishDraft},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := &Plan{
+				Changeset: &btypes.Changeset{ExternalServiceType: tc.serviceType},
+				Ops:       tc.ops,
+			}
+			plan.HoldForDependencies()
+
+			if !plan.Ops.Equal(tc.wantOps) {
+				t.Fatalf("wrong operations. want=%s, have=%s", tc.wantOps, plan.Ops)
+			}
+			if plan.OpensForReview() {
+				t.Fatalf("plan still opens changeset for review: %s", plan.Ops)
+			}
+		})
+	}
+}
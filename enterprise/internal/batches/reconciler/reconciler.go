@@ -80,6 +80,41 @@ func (r *Reconciler) process(ctx context.Context, logger log.Logger, tx *store.S
 		return err
 	}
 
+	if plan.Ops.IsNone() && readyToAutoMerge(ch) {
+		// Changesets are never merged before the changesets they depend on.
+		blocked, err := blockedByDependencies(ctx, tx, ch)
+		if err != nil {
+			return err
+		}
+		if !blocked {
+			strategy, err := loadAutoMergeStrategy(ctx, tx, ch)
+			if err != nil {
+				return err
+			}
+			plan.AddAutoMerge(strategy)
+		}
+	}
+
+	if plan.OpensForReview() || ch.BlockedByDependencies {
+		blocked, err := blockedByDependencies(ctx, tx, ch)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			plan.HoldForDependencies()
+		}
+		if blocked != ch.BlockedByDependencies {
+			ch.BlockedByDependencies = blocked
+			// The executor only persists the changeset if there's something
+			// to do, so we need to record the changed flag ourselves.
+			if plan.Ops.IsNone() {
+				if err := tx.UpdateChangeset(ctx, ch); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
 	logger.Info("Reconciler processing changeset", log.Int64("changeset", ch.ID), log.String("operations", fmt.Sprintf("%+v", plan.Ops)))
 
 	return executePlan(
@@ -93,6 +128,42 @@ func (r *Reconciler) process(ctx context.Context, logger log.Logger, tx *store.S
 	)
 }
 
+// loadAutoMergeStrategy returns the strategy with which the batch change owning
+// the changeset merges its changesets automatically, or an empty strategy if it
+// doesn't.
+func loadAutoMergeStrategy(ctx context.Context, tx *store.Store, ch *btypes.Changeset) (btypes.ChangesetMergeStrategy, error) {
+	batchChange, err := tx.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: ch.OwnedByBatchChangeID})
+	if err != nil {
+		return "", err
+	}
+
+	batchSpec, err := tx.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: batchChange.BatchSpecID})
+	if err != nil {
+		return "", err
+	}
+
+	if batchSpec.Spec == nil || batchSpec.Spec.AutoMerge == nil {
+		return "", nil
+	}
+	return btypes.ChangesetMergeStrategy(batchSpec.Spec.AutoMerge.Strategy), nil
+}
+
+// blockedByDependencies returns whether any of the changesets the given
+// changeset depends on hasn't been merged yet.
+func blockedByDependencies(ctx context.Context, tx *store.Store, ch *btypes.Changeset) (bool, error) {
+	deps, err := tx.ListChangesetDependencies(ctx, store.ListChangesetDependenciesOpts{ChangesetID: ch.ID})
+	if err != nil {
+		return false, err
+	}
+
+	for _, dep := range deps {
+		if !dep.Satisfied {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func loadChangesetSpecs(ctx context.Context, tx *store.Store, ch *btypes.Changeset) (prev, curr *btypes.ChangesetSpec, err error) {
 	if ch.CurrentSpecID != 0 {
 		curr, err = tx.GetChangesetSpecByID(ctx, ch.CurrentSpecID)
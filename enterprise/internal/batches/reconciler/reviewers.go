@@ -0,0 +1,192 @@
+package reconciler
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/own/codeowners"
+	codeownerspb "github.com/sourcegraph/sourcegraph/internal/own/codeowners/proto"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// codeownersPaths are the locations a CODEOWNERS file is looked up at, in
+// order of precedence.
+var codeownersPaths = []string{
+	"CODEOWNERS",
+	".github/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// requestReviewers requests a review of the freshly published changeset from
+// the reviewers configured in the changeset spec, and records them on the
+// changeset. Teams are skipped on code hosts that can't request reviews from
+// teams. Failing to request reviews doesn't fail the publication, since the
+// changeset already exists on the code host at this point.
+func (e *executor) requestReviewers(ctx context.Context, css sources.ChangesetSource, cs *sources.Changeset) {
+	if e.spec.Reviewers == nil {
+		return
+	}
+
+	logger := e.logger.With(log.Int64("changeset", e.ch.ID))
+
+	users, teams, err := e.resolveReviewers(ctx)
+	if err != nil {
+		logger.Warn("failed to resolve reviewers from CODEOWNERS", log.Error(err))
+	}
+	if len(users) == 0 && len(teams) == 0 {
+		return
+	}
+
+	rcss, ok := css.(sources.ReviewerRequestingChangesetSource)
+	if !ok {
+		logger.Warn("code host doesn't support requesting reviewers")
+		return
+	}
+
+	tcss, ok := rcss.(sources.TeamReviewerRequestingChangesetSource)
+	if !ok && len(teams) > 0 {
+		logger.Warn("code host doesn't support requesting reviews from teams, skipping them", log.Strings("teams", teams))
+		teams = nil
+	}
+	if len(users) == 0 && len(teams) == 0 {
+		return
+	}
+
+	if len(teams) > 0 {
+		err = tcss.RequestTeamReviewers(ctx, cs, users, teams)
+	} else {
+		err = rcss.RequestReviewers(ctx, cs, users)
+	}
+	if err != nil {
+		logger.Warn("failed to request reviewers", log.Error(err))
+		return
+	}
+
+	e.ch.RequestedReviewers = append(append([]string{}, users...), teams...)
+}
+
+// resolveReviewers returns the users and teams configured in the changeset
+// spec, extended by the owners of the changed files if reviewers should be
+// taken from CODEOWNERS. The explicitly configured reviewers are always
+// returned, even if evaluating CODEOWNERS fails.
+func (e *executor) resolveReviewers(ctx context.Context) (users, teams []string, err error) {
+	seen := make(map[string]struct{})
+	add := func(handle string) {
+		if _, ok := seen[handle]; ok {
+			return
+		}
+		seen[handle] = struct{}{}
+		if strings.Contains(handle, "/") {
+			teams = append(teams, handle)
+		} else {
+			users = append(users, handle)
+		}
+	}
+
+	for _, user := range e.spec.Reviewers.Users {
+		add(user)
+	}
+	for _, team := range e.spec.Reviewers.Teams {
+		add(team)
+	}
+
+	if !e.spec.Reviewers.FromCodeOwners {
+		return users, teams, nil
+	}
+
+	file, err := loadCodeowners(ctx, e.client, e.targetRepo.Name, api.CommitID(e.spec.BaseRev))
+	if err != nil || file == nil {
+		return users, teams, err
+	}
+
+	owners, err := codeownersForDiff(file, e.spec.Diff)
+	if err != nil {
+		return users, teams, err
+	}
+	for _, owner := range owners {
+		add(owner)
+	}
+
+	return users, teams, nil
+}
+
+// loadCodeowners reads and parses the CODEOWNERS file of the given repository
+// at the given commit. If the repository doesn't have a CODEOWNERS file, nil
+// is returned.
+func loadCodeowners(ctx context.Context, client gitserver.Client, repo api.RepoName, commit api.CommitID) (*codeownerspb.File, error) {
+	for _, path := range codeownersPaths {
+		content, err := client.ReadFile(ctx, authz.DefaultSubRepoPermsChecker, repo, commit, path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "reading %s", path)
+		}
+
+		file, err := codeowners.Parse(bytes.NewReader(content))
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", path)
+		}
+		return file, nil
+	}
+
+	return nil, nil
+}
+
+// codeownersForDiff returns the handles of the owners of all files touched by
+// the given diff, in the order they are first encountered. Owners that are
+// only identified by an email address are skipped, since reviews can't be
+// requested from them on the code host.
+func codeownersForDiff(file *codeownerspb.File, rawDiff []byte) ([]string, error) {
+	fileDiffs, err := diff.ParseMultiFileDiff(rawDiff)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing diff")
+	}
+
+	var handles []string
+	seen := make(map[string]struct{})
+	for _, fd := range fileDiffs {
+		for _, name := range []string{fd.OrigName, fd.NewName} {
+			path, ok := diffFilePath(name)
+			if !ok {
+				continue
+			}
+			for _, owner := range file.FindOwners(path) {
+				handle := owner.GetHandle()
+				if handle == "" {
+					continue
+				}
+				if _, ok := seen[handle]; ok {
+					continue
+				}
+				seen[handle] = struct{}{}
+				handles = append(handles, handle)
+			}
+		}
+	}
+
+	return handles, nil
+}
+
+// diffFilePath converts a file name from a diff header into the rooted path
+// that CODEOWNERS rules are matched against. It returns false for
+// /dev/null, which denotes a created or deleted file.
+func diffFilePath(name string) (string, bool) {
+	if name == "" || name == "/dev/null" {
+		return "", false
+	}
+	if strings.HasPrefix(name, "a/") || strings.HasPrefix(name, "b/") {
+		name = name[2:]
+	}
+	return "/" + strings.TrimPrefix(name, "/"), true
+}
@@ -0,0 +1,164 @@
+package reconciler
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources"
+	stesting "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+const testReviewersDiff = `diff --git a/README.md b/README.md
+index 1234567..89abcde 100644
+--- a/README.md
++++ b/README.md
+@@ -1 +1 @@
+-Hello
++Hello World
+diff --git a/cmd/server/main.go b/cmd/server/main.go
+new file mode 100644
+index 0000000..89abcde
+--- /dev/null
++++ b/cmd/server/main.go
+@@ -0,0 +1 @@
++package main
+diff --git a/docs/index.md b/docs/index.md
+deleted file mode 100644
+index 1234567..0000000
+--- a/docs/index.md
++++ /dev/null
+@@ -1 +0,0 @@
+-# Docs
+`
+
+const testCodeowners = `
+*.md @docs-team owner@example.com
+/cmd/ @sourcegraph/backend @alice
+/docs/ @bob
+`
+
+func TestRequestReviewers(t *testing.T) {
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		reviewers  *batcheslib.ChangesetReviewers
+		codeowners map[string]string
+		sourceErr  error
+		// usersOnly hides the support for team reviewers of the source.
+		usersOnly bool
+
+		wantUsers     []string
+		wantTeams     []string
+		wantRequested []string
+	}{
+		"no reviewers": {},
+		"explicit reviewers": {
+			reviewers: &batcheslib.ChangesetReviewers{
+				Users: []string{"alice", "carol"},
+				Teams: []string{"sourcegraph/batchers"},
+			},
+			wantUsers:     []string{"alice", "carol"},
+			wantTeams:     []string{"sourcegraph/batchers"},
+			wantRequested: []string{"alice", "carol", "sourcegraph/batchers"},
+		},
+		"from CODEOWNERS": {
+			reviewers:     &batcheslib.ChangesetReviewers{FromCodeOwners: true},
+			codeowners:    map[string]string{".github/CODEOWNERS": testCodeowners},
+			wantUsers:     []string{"docs-team", "alice", "bob"},
+			wantTeams:     []string{"sourcegraph/backend"},
+			wantRequested: []string{"docs-team", "alice", "bob", "sourcegraph/backend"},
+		},
+		"explicit reviewers and CODEOWNERS are deduplicated": {
+			reviewers: &batcheslib.ChangesetReviewers{
+				Users:          []string{"alice"},
+				FromCodeOwners: true,
+			},
+			codeowners:    map[string]string{"CODEOWNERS": testCodeowners},
+			wantUsers:     []string{"alice", "docs-team", "bob"},
+			wantTeams:     []string{"sourcegraph/backend"},
+			wantRequested: []string{"alice", "docs-team", "bob", "sourcegraph/backend"},
+		},
+		"teams are skipped without team support": {
+			reviewers: &batcheslib.ChangesetReviewers{
+				Users: []string{"alice"},
+				Teams: []string{"sourcegraph/batchers"},
+			},
+			usersOnly:     true,
+			wantUsers:     []string{"alice"},
+			wantRequested: []string{"alice"},
+		},
+		"only teams without team support": {
+			reviewers: &batcheslib.ChangesetReviewers{
+				Teams: []string{"sourcegraph/batchers"},
+			},
+			usersOnly: true,
+		},
+		"no CODEOWNERS file": {
+			reviewers: &batcheslib.ChangesetReviewers{FromCodeOwners: true},
+		},
+		"code host error is not recorded": {
+			reviewers: &batcheslib.ChangesetReviewers{
+				Users: []string{"alice"},
+			},
+			sourceErr: os.ErrPermission,
+			wantUsers: []string{"alice"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := gitserver.NewMockClient()
+			client.ReadFileFunc.SetDefaultHook(func(_ context.Context, _ authz.SubRepoPermissionChecker, _ api.RepoName, commit api.CommitID, path string) ([]byte, error) {
+				if commit != "base-rev" {
+					t.Fatalf("unexpected commit %q", commit)
+				}
+				if content, ok := tc.codeowners[path]; ok {
+					return []byte(content), nil
+				}
+				return nil, os.ErrNotExist
+			})
+
+			css := &stesting.FakeChangesetSource{Err: tc.sourceErr}
+			ch := &btypes.Changeset{ID: 1}
+			e := &executor{
+				client:     client,
+				logger:     logtest.Scoped(t),
+				ch:         ch,
+				targetRepo: &types.Repo{Name: "github.com/sourcegraph/sourcegraph"},
+				spec: &btypes.ChangesetSpec{
+					BaseRev:   "base-rev",
+					Diff:      []byte(testReviewersDiff),
+					Reviewers: tc.reviewers,
+				},
+			}
+
+			var source sources.ChangesetSource = css
+			if tc.usersOnly {
+				source = struct {
+					sources.ReviewerRequestingChangesetSource
+				}{css}
+			}
+			e.requestReviewers(ctx, source, nil)
+
+			if diff := cmp.Diff(tc.wantUsers, css.RequestedReviewerUsers); diff != "" {
+				t.Errorf("wrong users requested (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantTeams, css.RequestedReviewerTeams); diff != "" {
+				t.Errorf("wrong teams requested (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantRequested, ch.RequestedReviewers); diff != "" {
+				t.Errorf("wrong requested reviewers recorded (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
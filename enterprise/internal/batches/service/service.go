@@ -13,6 +13,7 @@ import (
 	sglog "github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/global"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/policy"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
@@ -20,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	extsvcauth "github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
@@ -91,6 +93,7 @@ type operations struct {
 	applyBatchChange                     *observation.Operation
 	reconcileBatchChange                 *observation.Operation
 	validateChangesetSpecs               *observation.Operation
+	runScheduledBatchChange              *observation.Operation
 }
 
 var (
@@ -141,6 +144,7 @@ func newOperations(observationCtx *observation.Context) *operations {
 			applyBatchChange:                     op("ApplyBatchChange"),
 			reconcileBatchChange:                 op("ReconcileBatchChange"),
 			validateChangesetSpecs:               op("ValidateChangesetSpecs"),
+			runScheduledBatchChange:              op("RunScheduledBatchChange"),
 		}
 	})
 
@@ -1321,7 +1325,8 @@ func (s *Service) CreateChangesetJobs(ctx context.Context, batchChangeID int64,
 }
 
 // ValidateChangesetSpecs checks whether the given BachSpec has ChangesetSpecs
-// that would publish to the same branch in the same repository.
+// that would publish to the same branch in the same repository, or that
+// violate the batch changes policies in the site configuration.
 // If the return value is nil, then the BatchSpec is valid.
 func (s *Service) ValidateChangesetSpecs(ctx context.Context, batchSpecID int64) error {
 	// We don't use `err` here to distinguish between errors we want to trace
@@ -1336,14 +1341,22 @@ func (s *Service) ValidateChangesetSpecs(ctx context.Context, batchSpecID int64)
 		return nonValidationErr
 	}
 
-	if len(conflicts) == 0 {
+	violations, nonValidationErr := s.listChangesetSpecPolicyViolations(ctx, batchSpecID)
+	if nonValidationErr != nil {
+		return nonValidationErr
+	}
+
+	if len(conflicts) == 0 && len(violations) == 0 {
 		return nil
 	}
 
-	repoIDs := make([]api.RepoID, 0, len(conflicts))
+	repoIDs := make([]api.RepoID, 0, len(conflicts)+len(violations))
 	for _, c := range conflicts {
 		repoIDs = append(repoIDs, c.RepoID)
 	}
+	for _, v := range violations {
+		repoIDs = append(repoIDs, v.repoID)
+	}
 
 	// 🚨 SECURITY: database.Repos.GetRepoIDsSet uses the authzFilter under the hood and
 	// filters out repositories that the user doesn't have access to.
@@ -1352,7 +1365,7 @@ func (s *Service) ValidateChangesetSpecs(ctx context.Context, batchSpecID int64)
 		return nonValidationErr
 	}
 
-	var errs changesetSpecHeadRefConflictErrs
+	var errs changesetSpecValidationErrs
 	for _, c := range conflicts {
 		conflictErr := &changesetSpecHeadRefConflict{count: c.Count, headRef: c.HeadRef}
 
@@ -1362,9 +1375,59 @@ func (s *Service) ValidateChangesetSpecs(ctx context.Context, batchSpecID int64)
 		}
 		errs = append(errs, conflictErr)
 	}
+	for _, v := range violations {
+		if repo, ok := accessibleReposByID[v.repoID]; ok {
+			v.repo = repo
+		}
+		errs = append(errs, v)
+	}
 	return errs
 }
 
+// listChangesetSpecPolicyViolations checks the branch changeset specs of the
+// given batch spec that are published when the batch spec is applied against
+// the batch changes policies, so that a batch spec that the reconciler would
+// refuse to publish can't be applied in the first place. Other changeset specs
+// are only checked by the reconciler once they're published from the UI.
+func (s *Service) listChangesetSpecPolicyViolations(ctx context.Context, batchSpecID int64) ([]*changesetSpecPolicyViolation, error) {
+	policies := conf.Get().BatchChangesPolicies
+	if policies == nil {
+		return nil, nil
+	}
+
+	specs, _, err := s.store.ListChangesetSpecs(ctx, store.ListChangesetSpecsOpts{
+		BatchSpecID: batchSpecID,
+		Type:        batcheslib.ChangesetSpecDescriptionTypeBranch,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []*changesetSpecPolicyViolation
+	for _, spec := range specs {
+		if !spec.Published.True() && !spec.Published.Draft() {
+			continue
+		}
+
+		vs, err := policy.Check(policies, policy.Changeset{
+			Title:   spec.Title,
+			HeadRef: spec.HeadRef,
+			Diff:    spec.Diff,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "checking batch changes policies")
+		}
+		if len(vs) > 0 {
+			violations = append(violations, &changesetSpecPolicyViolation{
+				repoID:     spec.BaseRepoID,
+				headRef:    spec.HeadRef,
+				violations: vs,
+			})
+		}
+	}
+	return violations, nil
+}
+
 type changesetSpecHeadRefConflict struct {
 	repo    *types.Repo
 	count   int
@@ -1378,11 +1441,26 @@ func (c changesetSpecHeadRefConflict) Error() string {
 	return fmt.Sprintf("%d changeset specs in the same repository use the same branch: %s", c.count, c.headRef)
 }
 
-// changesetSpecHeadRefConflictErrs represents a set of changesetSpecHeadRefConflict and
-// implements `Error` to render the errors nicely.
-type changesetSpecHeadRefConflictErrs []*changesetSpecHeadRefConflict
+type changesetSpecPolicyViolation struct {
+	repoID     api.RepoID
+	repo       *types.Repo
+	headRef    string
+	violations []string
+}
+
+func (v changesetSpecPolicyViolation) Error() string {
+	if v.repo != nil {
+		return fmt.Sprintf("changeset spec in %s on branch %s violates the batch changes policies: %s", v.repo.Name, v.headRef, strings.Join(v.violations, "; "))
+	}
+	return fmt.Sprintf("changeset spec on branch %s violates the batch changes policies: %s", v.headRef, strings.Join(v.violations, "; "))
+}
+
+// changesetSpecValidationErrs represents a set of changesetSpecHeadRefConflict
+// and changesetSpecPolicyViolation errors and implements `Error` to render the
+// errors nicely.
+type changesetSpecValidationErrs []error
 
-func (es changesetSpecHeadRefConflictErrs) Error() string {
+func (es changesetSpecValidationErrs) Error() string {
 	if len(es) == 1 {
 		return fmt.Sprintf("Validating changeset specs resulted in an error:\n* %s\n", es[0])
 	}
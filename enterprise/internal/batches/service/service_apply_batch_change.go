@@ -213,5 +213,8 @@ func (s *Service) ReconcileBatchChange(
 	batchChange.LastApplierID = a.UID
 	batchChange.LastAppliedAt = s.clock()
 	batchChange.Description = batchSpec.Spec.Description
+	// Applying a batch spec supersedes a scheduled run that is still in
+	// progress, and restarts the schedule.
+	batchChange.ScheduledBatchSpecID = 0
 	return batchChange, previousSpecID, nil
 }
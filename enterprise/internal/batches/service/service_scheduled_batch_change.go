@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// RunScheduledBatchChange moves the scheduled run of the given batch change
+// forward by one step. It is called periodically for every batch change whose
+// batch spec has a schedule, and must be called with the last applier of the
+// batch change as the actor, in whose name the run is executed and applied.
+//
+// A run starts once the schedule interval has passed since the batch change
+// was last applied or run. It creates a copy of the applied batch spec, which
+// re-resolves the workspaces matched by the `on` property. Once resolution
+// completed, the workspaces without a cached result are executed, and once all
+// executions completed, the batch spec is applied to the batch change. A run
+// whose resolution or executions fail is abandoned without applying anything,
+// and retried after the next interval.
+func (s *Service) RunScheduledBatchChange(ctx context.Context, batchChange *btypes.BatchChange) (err error) {
+	ctx, _, endObservation := s.operations.runScheduledBatchChange.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("batchChangeID", int(batchChange.ID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if batchChange.ScheduledBatchSpecID != 0 {
+		return s.advanceScheduledBatchChangeRun(ctx, batchChange)
+	}
+
+	if batchChange.Closed() || batchChange.IsDraft() {
+		return nil
+	}
+
+	batchSpec, err := s.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: batchChange.BatchSpecID})
+	if err != nil {
+		return err
+	}
+
+	// Only batch changes that are executed server-side can be re-run.
+	if !batchSpec.CreatedFromRaw {
+		return nil
+	}
+
+	interval, err := batchSpec.Spec.ScheduleInterval()
+	if err != nil || interval == 0 {
+		return err
+	}
+
+	lastRun := batchChange.LastAppliedAt
+	if batchChange.LastScheduledAt.After(lastRun) {
+		lastRun = batchChange.LastScheduledAt
+	}
+	if s.clock().Sub(lastRun) < interval {
+		return nil
+	}
+
+	return s.startScheduledBatchChangeRun(ctx, batchChange, batchSpec)
+}
+
+// startScheduledBatchChangeRun creates a copy of the given batch spec for the
+// batch change and enqueues its workspace resolution.
+func (s *Service) startScheduledBatchChangeRun(ctx context.Context, batchChange *btypes.BatchChange, batchSpec *btypes.BatchSpec) (err error) {
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	spec := &btypes.BatchSpec{
+		RawSpec:         batchSpec.RawSpec,
+		Spec:            batchSpec.Spec,
+		NamespaceUserID: batchSpec.NamespaceUserID,
+		NamespaceOrgID:  batchSpec.NamespaceOrgID,
+		UserID:          batchChange.LastApplierID,
+		BatchChangeID:   batchChange.ID,
+	}
+	if err := s.createBatchSpecForExecution(ctx, tx, createBatchSpecForExecutionOpts{
+		spec:             spec,
+		allowIgnored:     batchSpec.AllowIgnored,
+		allowUnsupported: batchSpec.AllowUnsupported,
+	}); err != nil {
+		return err
+	}
+
+	batchChange.ScheduledBatchSpecID = spec.ID
+	batchChange.LastScheduledAt = s.clock()
+	return tx.UpdateBatchChange(ctx, batchChange)
+}
+
+// ErrScheduledBatchChangeRunFailed is returned by RunScheduledBatchChange when
+// the executions of a scheduled run failed or were canceled, and the run has
+// been abandoned.
+type ErrScheduledBatchChangeRunFailed struct {
+	State btypes.BatchSpecState
+}
+
+func (e ErrScheduledBatchChangeRunFailed) Error() string {
+	return "scheduled batch change run did not complete, batch spec is " + string(e.State)
+}
+
+// advanceScheduledBatchChangeRun executes or applies the batch spec of the
+// scheduled run of the batch change, depending on the state it is in.
+func (s *Service) advanceScheduledBatchChangeRun(ctx context.Context, batchChange *btypes.BatchChange) error {
+	if batchChange.Closed() {
+		return s.abandonScheduledBatchChangeRun(ctx, batchChange, nil)
+	}
+
+	batchSpec, err := s.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: batchChange.ScheduledBatchSpecID})
+	if err != nil {
+		return err
+	}
+
+	resolutionJob, err := s.store.GetBatchSpecResolutionJob(ctx, store.GetBatchSpecResolutionJobOpts{BatchSpecID: batchSpec.ID})
+	if err != nil {
+		return err
+	}
+
+	switch resolutionJob.State {
+	case btypes.BatchSpecResolutionJobStateErrored, btypes.BatchSpecResolutionJobStateFailed:
+		return s.abandonScheduledBatchChangeRun(ctx, batchChange, ErrBatchSpecResolutionErrored{resolutionJob.FailureMessage})
+
+	case btypes.BatchSpecResolutionJobStateCompleted:
+		// Continue below the switch statement.
+
+	default:
+		return nil
+	}
+
+	state, err := computeBatchSpecState(ctx, s.store, batchSpec)
+	if err != nil {
+		return err
+	}
+
+	switch state {
+	case btypes.BatchSpecStatePending:
+		_, err := s.ExecuteBatchSpec(ctx, ExecuteBatchSpecOpts{BatchSpecRandID: batchSpec.RandID})
+		return err
+
+	case btypes.BatchSpecStateCompleted:
+		// Applying the batch spec also resets the scheduled run of the batch
+		// change.
+		if _, err := s.ApplyBatchChange(ctx, ApplyBatchChangeOpts{
+			BatchSpecRandID:     batchSpec.RandID,
+			EnsureBatchChangeID: batchChange.ID,
+		}); err != nil {
+			return s.abandonScheduledBatchChangeRun(ctx, batchChange, err)
+		}
+		return nil
+
+	case btypes.BatchSpecStateFailed, btypes.BatchSpecStateCanceled:
+		return s.abandonScheduledBatchChangeRun(ctx, batchChange, ErrScheduledBatchChangeRunFailed{State: state})
+
+	default:
+		return nil
+	}
+}
+
+// abandonScheduledBatchChangeRun clears the scheduled run of the batch change,
+// so that the next run starts after the next interval, and returns the given
+// cause, if any.
+func (s *Service) abandonScheduledBatchChangeRun(ctx context.Context, batchChange *btypes.BatchChange, cause error) error {
+	batchChange.ScheduledBatchSpecID = 0
+	if err := s.store.UpdateBatchChange(ctx, batchChange); err != nil {
+		return errors.Append(cause, err)
+	}
+
+	return cause
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	bt "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+func TestServiceRunScheduledBatchChange(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	ctx := actor.WithInternalActor(context.Background())
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+
+	user := bt.CreateTestUser(t, db, false)
+	userCtx := actor.WithActor(context.Background(), actor.FromUser(user.ID))
+
+	now := timeutil.Now()
+	clock := func() time.Time { return now }
+
+	s := store.NewWithClock(db, &observation.TestContext, nil, clock)
+	svc := New(s)
+
+	createScheduledBatchChange := func(t *testing.T, name string, lastAppliedAt time.Time) *btypes.BatchChange {
+		t.Helper()
+
+		spec := testBatchSpec(user.ID)
+		spec.RawSpec = "name: " + name + "\nschedule: 24h\n"
+		spec.Spec = &batcheslib.BatchSpec{Name: name, Schedule: "24h"}
+		spec.CreatedFromRaw = true
+		require.NoError(t, s.CreateBatchSpec(ctx, spec))
+
+		batchChange := testBatchChange(user.ID, spec)
+		batchChange.Name = name
+		batchChange.LastAppliedAt = lastAppliedAt
+		require.NoError(t, s.CreateBatchChange(ctx, batchChange))
+
+		return batchChange
+	}
+
+	setResolutionJobState := func(t *testing.T, batchSpecID int64, state btypes.BatchSpecResolutionJobState) {
+		t.Helper()
+
+		require.NoError(t, s.Exec(ctx, sqlf.Sprintf("UPDATE batch_spec_resolution_jobs SET state = %s WHERE batch_spec_id = %s", state, batchSpecID)))
+	}
+
+	t.Run("not due", func(t *testing.T) {
+		batchChange := createScheduledBatchChange(t, "not-due", now.Add(-time.Hour))
+
+		require.NoError(t, svc.RunScheduledBatchChange(userCtx, batchChange))
+
+		reloaded, err := s.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChange.ID})
+		require.NoError(t, err)
+		assert.Zero(t, reloaded.ScheduledBatchSpecID)
+		assert.True(t, reloaded.LastScheduledAt.IsZero())
+	})
+
+	t.Run("run and apply", func(t *testing.T) {
+		batchChange := createScheduledBatchChange(t, "run-and-apply", now.Add(-25*time.Hour))
+		previousSpecID := batchChange.BatchSpecID
+
+		// The first run creates a new batch spec and enqueues its resolution.
+		require.NoError(t, svc.RunScheduledBatchChange(userCtx, batchChange))
+
+		reloaded, err := s.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChange.ID})
+		require.NoError(t, err)
+		require.NotZero(t, reloaded.ScheduledBatchSpecID)
+		assert.Equal(t, now, reloaded.LastScheduledAt)
+
+		scheduledSpec, err := s.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: reloaded.ScheduledBatchSpecID})
+		require.NoError(t, err)
+		assert.True(t, scheduledSpec.CreatedFromRaw)
+		assert.Equal(t, batchChange.ID, scheduledSpec.BatchChangeID)
+		assert.Equal(t, user.ID, scheduledSpec.UserID)
+
+		job, err := s.GetBatchSpecResolutionJob(ctx, store.GetBatchSpecResolutionJobOpts{BatchSpecID: scheduledSpec.ID})
+		require.NoError(t, err)
+		assert.Equal(t, btypes.BatchSpecResolutionJobStateQueued, job.State)
+
+		// While resolution is in progress, nothing happens.
+		require.NoError(t, svc.RunScheduledBatchChange(userCtx, reloaded))
+		reloaded, err = s.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChange.ID})
+		require.NoError(t, err)
+		assert.Equal(t, previousSpecID, reloaded.BatchSpecID)
+
+		// Once resolution completed without any workspaces, the batch spec is
+		// applied.
+		setResolutionJobState(t, scheduledSpec.ID, btypes.BatchSpecResolutionJobStateCompleted)
+		require.NoError(t, svc.RunScheduledBatchChange(userCtx, reloaded))
+
+		reloaded, err = s.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChange.ID})
+		require.NoError(t, err)
+		assert.Equal(t, scheduledSpec.ID, reloaded.BatchSpecID)
+		assert.Zero(t, reloaded.ScheduledBatchSpecID)
+	})
+
+	t.Run("resolution failed", func(t *testing.T) {
+		batchChange := createScheduledBatchChange(t, "resolution-failed", now.Add(-25*time.Hour))
+		previousSpecID := batchChange.BatchSpecID
+
+		require.NoError(t, svc.RunScheduledBatchChange(userCtx, batchChange))
+		reloaded, err := s.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChange.ID})
+		require.NoError(t, err)
+
+		setResolutionJobState(t, reloaded.ScheduledBatchSpecID, btypes.BatchSpecResolutionJobStateFailed)
+		err = svc.RunScheduledBatchChange(userCtx, reloaded)
+		assert.ErrorAs(t, err, &ErrBatchSpecResolutionErrored{})
+
+		reloaded, err = s.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChange.ID})
+		require.NoError(t, err)
+		assert.Equal(t, previousSpecID, reloaded.BatchSpecID)
+		assert.Zero(t, reloaded.ScheduledBatchSpecID)
+
+		// The next run only starts after the next interval.
+		require.NoError(t, svc.RunScheduledBatchChange(userCtx, reloaded))
+		reloaded, err = s.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChange.ID})
+		require.NoError(t, err)
+		assert.Zero(t, reloaded.ScheduledBatchSpecID)
+	})
+}
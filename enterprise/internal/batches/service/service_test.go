@@ -23,6 +23,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
@@ -34,6 +35,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
 	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestServicePermissionLevels(t *testing.T) {
@@ -2058,6 +2060,38 @@ changesetTemplate:
 		if diff := cmp.Diff(want, err.Error()); diff != "" {
 			t.Fatalf("wrong error message: %s", diff)
 		}
+
+		t.Run("policy violations", func(t *testing.T) {
+			bt.MockConfig(t, &conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				BatchChangesPolicies: &schema.BatchChangesPolicies{RequiredTitlePrefix: "[batch] "},
+			}})
+
+			batchSpec := bt.CreateBatchSpec(t, ctx, s, "policy-batch-spec", admin.ID, 0)
+			for _, opts := range []bt.TestSpecOpts{
+				{HeadRef: "refs/heads/published", Published: true, Title: "Fix it"},
+				{HeadRef: "refs/heads/draft", Published: "draft", Title: "[batch] Fix it"},
+				// Changesets that aren't published on apply aren't checked.
+				{HeadRef: "refs/heads/unpublished", Published: false, Title: "Fix it"},
+				{HeadRef: "refs/heads/ui-published", Title: "Fix it"},
+			} {
+				opts.Typ = btypes.ChangesetSpecTypeBranch
+				opts.Repo = rs[0].ID
+				opts.BatchSpec = batchSpec.ID
+				bt.CreateChangesetSpec(t, ctx, s, opts)
+			}
+
+			err := svc.ValidateChangesetSpecs(ctx, batchSpec.ID)
+			if err == nil {
+				t.Fatal("expected error, but got none")
+			}
+
+			want := `Validating changeset specs resulted in an error:
+* changeset spec in repo-1-1 on branch refs/heads/published violates the batch changes policies: title "Fix it" doesn't start with the required prefix "[batch] "
+`
+			if diff := cmp.Diff(want, err.Error()); diff != "" {
+				t.Fatalf("wrong error message: %s", diff)
+			}
+		})
 	})
 
 	t.Run("ComputeBatchSpecState", func(t *testing.T) {
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	bbcs "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources/bitbucketcloud"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
@@ -183,18 +184,25 @@ func (s BitbucketCloudSource) CreateComment(ctx context.Context, cs *Changeset,
 }
 
 // MergeChangeset merges a Changeset on the code host, if in a mergeable state.
-// If squash is true, and the code host supports squash merges, the source
-// must attempt a squash merge. Otherwise, it is expected to perform a regular
-// merge. If the changeset cannot be merged, because it is in an unmergeable
-// state, ChangesetNotMergeableError must be returned.
-func (s BitbucketCloudSource) MergeChangeset(ctx context.Context, cs *Changeset, squash bool) error {
+// The default strategy uses the default merge strategy of the repository.
+// Bitbucket Cloud can't rebase pull requests when merging them, so the rebase
+// strategy is rejected. If the changeset cannot be merged, because it is in an
+// unmergeable state, ChangesetNotMergeableError must be returned.
+func (s BitbucketCloudSource) MergeChangeset(ctx context.Context, cs *Changeset, strategy btypes.ChangesetMergeStrategy) error {
 	repo := cs.TargetRepo.Metadata.(*bitbucketcloud.Repo)
 	pr := cs.Metadata.(*bbcs.AnnotatedPullRequest)
 
 	var mergeStrategy *bitbucketcloud.MergeStrategy
-	if squash {
+	switch strategy {
+	case btypes.ChangesetMergeStrategyDefault:
+	case btypes.ChangesetMergeStrategyMerge:
+		ms := bitbucketcloud.MergeStrategyMergeCommit
+		mergeStrategy = &ms
+	case btypes.ChangesetMergeStrategySquash:
 		ms := bitbucketcloud.MergeStrategySquash
 		mergeStrategy = &ms
+	default:
+		return UnsupportedMergeStrategyError{CodeHost: "Bitbucket Cloud", Strategy: strategy}
 	}
 
 	updated, err := s.client.MergePullRequest(ctx, repo, pr.ID, bitbucketcloud.MergePullRequestOpts{
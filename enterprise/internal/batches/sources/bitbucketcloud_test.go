@@ -485,7 +485,7 @@ func TestBitbucketCloudSource_MergeChangeset(t *testing.T) {
 		})
 
 		annotateChangesetWithPullRequest(cs, pr)
-		err := s.MergeChangeset(ctx, cs, false)
+		err := s.MergeChangeset(ctx, cs, btypes.ChangesetMergeStrategyDefault)
 		assert.NotNil(t, err)
 		target := ChangesetNotMergeableError{}
 		assert.ErrorAs(t, err, &target)
@@ -506,7 +506,7 @@ func TestBitbucketCloudSource_MergeChangeset(t *testing.T) {
 		})
 
 		annotateChangesetWithPullRequest(cs, pr)
-		err := s.MergeChangeset(ctx, cs, false)
+		err := s.MergeChangeset(ctx, cs, btypes.ChangesetMergeStrategyDefault)
 		assert.NotNil(t, err)
 		assert.ErrorIs(t, err, want)
 	})
@@ -525,19 +525,30 @@ func TestBitbucketCloudSource_MergeChangeset(t *testing.T) {
 		})
 
 		annotateChangesetWithPullRequest(cs, pr)
-		err := s.MergeChangeset(ctx, cs, false)
+		err := s.MergeChangeset(ctx, cs, btypes.ChangesetMergeStrategyDefault)
 		assert.NotNil(t, err)
 		assert.ErrorIs(t, err, want)
 	})
 
+	t.Run("unsupported strategy", func(t *testing.T) {
+		cs, _, bbRepo := mockBitbucketCloudChangeset()
+		s, _ := mockBitbucketCloudSource()
+
+		annotateChangesetWithPullRequest(cs, mockBitbucketCloudPullRequest(bbRepo))
+		err := s.MergeChangeset(ctx, cs, btypes.ChangesetMergeStrategyRebase)
+		assert.ErrorAs(t, err, &UnsupportedMergeStrategyError{})
+	})
+
 	t.Run("success", func(t *testing.T) {
+		mergeCommit := bitbucketcloud.MergeStrategyMergeCommit
 		squash := bitbucketcloud.MergeStrategySquash
 		for name, tc := range map[string]struct {
-			squash bool
-			want   *bitbucketcloud.MergeStrategy
+			strategy btypes.ChangesetMergeStrategy
+			want     *bitbucketcloud.MergeStrategy
 		}{
-			"no squash": {false, nil},
-			"squash":    {true, &squash},
+			"default": {btypes.ChangesetMergeStrategyDefault, nil},
+			"merge":   {btypes.ChangesetMergeStrategyMerge, &mergeCommit},
+			"squash":  {btypes.ChangesetMergeStrategySquash, &squash},
 		} {
 			t.Run(name, func(t *testing.T) {
 				cs, _, bbRepo := mockBitbucketCloudChangeset()
@@ -553,7 +564,7 @@ func TestBitbucketCloudSource_MergeChangeset(t *testing.T) {
 				})
 
 				annotateChangesetWithPullRequest(cs, pr)
-				err := s.MergeChangeset(ctx, cs, tc.squash)
+				err := s.MergeChangeset(ctx, cs, tc.strategy)
 				assert.Nil(t, err)
 				assertChangesetMatchesPullRequest(t, cs, pr)
 			})
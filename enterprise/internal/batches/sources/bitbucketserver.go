@@ -7,6 +7,7 @@ import (
 
 	"github.com/inconshreveable/log15"
 
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
@@ -24,6 +25,7 @@ type BitbucketServerSource struct {
 }
 
 var _ ForkableChangesetSource = BitbucketServerSource{}
+var _ ReviewerRequestingChangesetSource = BitbucketServerSource{}
 
 // NewBitbucketServerSource returns a new BitbucketServerSource from the given external service.
 func NewBitbucketServerSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*BitbucketServerSource, error) {
@@ -262,13 +264,28 @@ func (s BitbucketServerSource) CreateComment(ctx context.Context, c *Changeset,
 }
 
 // MergeChangeset merges a Changeset on the code host, if in a mergeable state.
-// The squash parameter is ignored, as Bitbucket Server does not support
-// squash merges.
-func (s BitbucketServerSource) MergeChangeset(ctx context.Context, c *Changeset, squash bool) error {
-	merged, err := s.callAndRetryIfOutdated(ctx, c, s.client.MergePullRequest)
+// The default strategy uses the default merge strategy of the repository. Any
+// other strategy has to be enabled for the repository.
+func (s BitbucketServerSource) MergeChangeset(ctx context.Context, c *Changeset, strategy btypes.ChangesetMergeStrategy) error {
+	var strategyID string
+	switch strategy {
+	case btypes.ChangesetMergeStrategyDefault:
+	case btypes.ChangesetMergeStrategyMerge:
+		strategyID = bitbucketserver.MergeStrategyNoFastForward
+	case btypes.ChangesetMergeStrategySquash:
+		strategyID = bitbucketserver.MergeStrategySquash
+	case btypes.ChangesetMergeStrategyRebase:
+		strategyID = bitbucketserver.MergeStrategyRebaseFastForwardOnly
+	default:
+		return UnsupportedMergeStrategyError{CodeHost: "Bitbucket Server", Strategy: strategy}
+	}
+
+	merged, err := s.callAndRetryIfOutdated(ctx, c, func(ctx context.Context, pr *bitbucketserver.PullRequest) error {
+		return s.client.MergePullRequest(ctx, pr, strategyID)
+	})
 	if err != nil {
 		if bitbucketserver.IsMergePreconditionFailedException(err) {
-			return &ChangesetNotMergeableError{ErrorMsg: err.Error()}
+			return ChangesetNotMergeableError{ErrorMsg: err.Error()}
 		}
 		return err
 	}
@@ -276,6 +293,47 @@ func (s BitbucketServerSource) MergeChangeset(ctx context.Context, c *Changeset,
 	return c.Changeset.SetMetadata(merged)
 }
 
+// RequestReviewers adds the given users to the reviewers of the Changeset.
+func (s BitbucketServerSource) RequestReviewers(ctx context.Context, c *Changeset, users []string) error {
+	updated, err := s.callAndRetryIfOutdated(ctx, c, func(ctx context.Context, pr *bitbucketserver.PullRequest) error {
+		update := &bitbucketserver.UpdatePullRequestInput{
+			PullRequestID: strconv.Itoa(pr.ID),
+			Title:         pr.Title,
+			Description:   pr.Description,
+			Version:       pr.Version,
+			ToRef:         pr.ToRef,
+			Reviewers:     make([]bitbucketserver.Reviewer, 0, len(pr.Reviewers)+len(users)),
+		}
+
+		existing := make(map[string]struct{}, len(pr.Reviewers))
+		for _, reviewer := range pr.Reviewers {
+			if reviewer.User == nil {
+				continue
+			}
+			existing[reviewer.User.Name] = struct{}{}
+			update.Reviewers = append(update.Reviewers, bitbucketserver.Reviewer{User: &bitbucketserver.User{Name: reviewer.User.Name}})
+		}
+		for _, name := range users {
+			if _, ok := existing[name]; ok {
+				continue
+			}
+			update.Reviewers = append(update.Reviewers, bitbucketserver.Reviewer{User: &bitbucketserver.User{Name: name}})
+		}
+
+		updated, err := s.client.UpdatePullRequest(ctx, update)
+		if err != nil {
+			return err
+		}
+		*pr = *updated
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Changeset.SetMetadata(updated)
+}
+
 type bitbucketClientFunc func(context.Context, *bitbucketserver.PullRequest) error
 
 func (s BitbucketServerSource) callAndRetryIfOutdated(ctx context.Context, c *Changeset, fn bitbucketClientFunc) (*bitbucketserver.PullRequest, error) {
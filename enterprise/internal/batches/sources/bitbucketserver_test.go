@@ -612,7 +612,7 @@ func TestBitbucketServerSource_MergeChangeset(t *testing.T) {
 
 			tc.err = strings.ReplaceAll(tc.err, "${INSTANCEURL}", instanceURL)
 
-			err = bbsSrc.MergeChangeset(ctx, tc.cs, false)
+			err = bbsSrc.MergeChangeset(ctx, tc.cs, btypes.ChangesetMergeStrategyDefault)
 			if have, want := fmt.Sprint(err), tc.err; have != want {
 				t.Errorf("error:\nhave: %q\nwant: %q", have, want)
 			}
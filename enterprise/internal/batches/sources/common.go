@@ -61,6 +61,27 @@ type ForkableChangesetSource interface {
 	GetUserFork(ctx context.Context, targetRepo *types.Repo) (*types.Repo, error)
 }
 
+// A ReviewerRequestingChangesetSource can request reviews on changesets from
+// users on the code host.
+type ReviewerRequestingChangesetSource interface {
+	ChangesetSource
+
+	// RequestReviewers requests a review of the given Changeset from the
+	// given users, identified by their code host username.
+	RequestReviewers(ctx context.Context, cs *Changeset, users []string) error
+}
+
+// A TeamReviewerRequestingChangesetSource can additionally request reviews on
+// changesets from teams on the code host.
+type TeamReviewerRequestingChangesetSource interface {
+	ReviewerRequestingChangesetSource
+
+	// RequestTeamReviewers requests a review of the given Changeset from the
+	// given users and teams. Users are identified by their code host
+	// username, teams by "org/team".
+	RequestTeamReviewers(ctx context.Context, cs *Changeset, users, teams []string) error
+}
+
 // A ChangesetSource can load the latest state of a list of Changesets.
 type ChangesetSource interface {
 	// GitserverPushConfig returns an authenticated push config used for pushing
@@ -93,11 +114,13 @@ type ChangesetSource interface {
 	// CreateComment posts a comment on the Changeset.
 	CreateComment(context.Context, *Changeset, string) error
 	// MergeChangeset merges a Changeset on the code host, if in a mergeable state.
-	// If squash is true, and the code host supports squash merges, the source
-	// must attempt a squash merge. Otherwise, it is expected to perform a regular
-	// merge. If the changeset cannot be merged, because it is in an unmergeable
-	// state, ChangesetNotMergeableError must be returned.
-	MergeChangeset(ctx context.Context, ch *Changeset, squash bool) error
+	// The source must merge with the given strategy, or with the default of the
+	// repository if the strategy is ChangesetMergeStrategyDefault. If the code
+	// host can't merge with that strategy, UnsupportedMergeStrategyError must be
+	// returned.
+	// If the changeset cannot be merged, because it is in an unmergeable state,
+	// ChangesetNotMergeableError must be returned.
+	MergeChangeset(ctx context.Context, ch *Changeset, strategy btypes.ChangesetMergeStrategy) error
 }
 
 // ChangesetNotMergeableError is returned by MergeChangeset if the changeset
@@ -114,6 +137,19 @@ func (e ChangesetNotMergeableError) Error() string {
 
 func (e ChangesetNotMergeableError) NonRetryable() bool { return true }
 
+// UnsupportedMergeStrategyError is returned by MergeChangeset if the code host
+// can't merge changesets with the requested merge strategy.
+type UnsupportedMergeStrategyError struct {
+	CodeHost string
+	Strategy btypes.ChangesetMergeStrategy
+}
+
+func (e UnsupportedMergeStrategyError) Error() string {
+	return fmt.Sprintf("%s doesn't support the %q merge strategy", e.CodeHost, e.Strategy)
+}
+
+func (e UnsupportedMergeStrategyError) NonRetryable() bool { return true }
+
 // A Changeset of an existing Repo.
 type Changeset struct {
 	Title   string
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
@@ -25,6 +26,7 @@ type GithubSource struct {
 }
 
 var _ ForkableChangesetSource = GithubSource{}
+var _ ReviewerRequestingChangesetSource = GithubSource{}
 
 func NewGithubSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*GithubSource, error) {
 	rawConfig, err := svc.Config.Decrypt(ctx)
@@ -276,14 +278,28 @@ func (s GithubSource) CreateComment(ctx context.Context, c *Changeset, text stri
 }
 
 // MergeChangeset merges a Changeset on the code host, if in a mergeable state.
-// If squash is true, a squash-then-merge merge will be performed.
-func (s GithubSource) MergeChangeset(ctx context.Context, c *Changeset, squash bool) error {
+// The strategy maps directly to the merge method of the pull request. GitHub
+// has no per-repository default, so the default strategy creates a merge
+// commit.
+func (s GithubSource) MergeChangeset(ctx context.Context, c *Changeset, strategy btypes.ChangesetMergeStrategy) error {
 	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
 	if !ok {
 		return errors.New("Changeset is not a GitHub pull request")
 	}
 
-	if err := s.client.MergePullRequest(ctx, pr, squash); err != nil {
+	var method github.PullRequestMergeMethod
+	switch strategy {
+	case btypes.ChangesetMergeStrategyDefault, btypes.ChangesetMergeStrategyMerge:
+		method = github.PullRequestMergeMethodMerge
+	case btypes.ChangesetMergeStrategySquash:
+		method = github.PullRequestMergeMethodSquash
+	case btypes.ChangesetMergeStrategyRebase:
+		method = github.PullRequestMergeMethodRebase
+	default:
+		return UnsupportedMergeStrategyError{CodeHost: "GitHub", Strategy: strategy}
+	}
+
+	if err := s.client.MergePullRequest(ctx, pr, method); err != nil {
 		if github.IsNotMergeable(err) {
 			return ChangesetNotMergeableError{ErrorMsg: err.Error()}
 		}
@@ -293,6 +309,35 @@ func (s GithubSource) MergeChangeset(ctx context.Context, c *Changeset, squash b
 	return c.Changeset.SetMetadata(pr)
 }
 
+// RequestReviewers requests a review of the Changeset from the given users.
+func (s GithubSource) RequestReviewers(ctx context.Context, c *Changeset, users []string) error {
+	return s.RequestTeamReviewers(ctx, c, users, nil)
+}
+
+// RequestTeamReviewers requests a review of the Changeset from the given users
+// and teams. GitHub identifies teams by their slug within the organization
+// that owns the repository, so the organization is stripped from the team.
+func (s GithubSource) RequestTeamReviewers(ctx context.Context, c *Changeset, users, teams []string) error {
+	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitHub pull request")
+	}
+
+	if pr.RepoWithOwner == "" {
+		pr.RepoWithOwner = c.TargetRepo.Metadata.(*github.Repository).NameWithOwner
+	}
+
+	slugs := make([]string, 0, len(teams))
+	for _, team := range teams {
+		if _, slug, ok := strings.Cut(team, "/"); ok {
+			team = slug
+		}
+		slugs = append(slugs, team)
+	}
+
+	return s.client.RequestReviewers(ctx, pr, users, slugs)
+}
+
 // GetNamespaceFork returns a repo pointing to a fork of the given repo in
 // the given namespace, ensuring that the fork exists and is a fork of the
 // target repo.
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Masterminds/semver"
 
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -29,6 +30,7 @@ type GitLabSource struct {
 var _ ChangesetSource = &GitLabSource{}
 var _ DraftChangesetSource = &GitLabSource{}
 var _ ForkableChangesetSource = &GitLabSource{}
+var _ ReviewerRequestingChangesetSource = &GitLabSource{}
 
 // NewGitLabSource returns a new GitLabSource from the given external service.
 func NewGitLabSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*GitLabSource, error) {
@@ -520,14 +522,26 @@ func (s *GitLabSource) CreateComment(ctx context.Context, c *Changeset, text str
 }
 
 // MergeChangeset merges a Changeset on the code host, if in a mergeable state.
-// If squash is true, a squash-then-merge merge will be performed.
-func (s *GitLabSource) MergeChangeset(ctx context.Context, c *Changeset, squash bool) error {
+// If the strategy is squash, a squash-then-merge merge will be performed. The
+// default and merge strategies merge with the merge method configured for the
+// project. GitLab can't rebase a merge request when merging it, so the rebase
+// strategy is rejected.
+func (s *GitLabSource) MergeChangeset(ctx context.Context, c *Changeset, strategy btypes.ChangesetMergeStrategy) error {
 	mr, ok := c.Changeset.Metadata.(*gitlab.MergeRequest)
 	if !ok {
 		return errors.New("Changeset is not a GitLab merge request")
 	}
 	project := c.TargetRepo.Metadata.(*gitlab.Project)
 
+	var squash bool
+	switch strategy {
+	case btypes.ChangesetMergeStrategyDefault, btypes.ChangesetMergeStrategyMerge:
+	case btypes.ChangesetMergeStrategySquash:
+		squash = true
+	default:
+		return UnsupportedMergeStrategyError{CodeHost: "GitLab", Strategy: strategy}
+	}
+
 	updated, err := s.client.MergeMergeRequest(ctx, project, mr, squash)
 	if err != nil {
 		if errors.Is(err, gitlab.ErrNotMergeable) {
@@ -544,6 +558,41 @@ func (s *GitLabSource) MergeChangeset(ctx context.Context, c *Changeset, squash
 	return c.Changeset.SetMetadata(updated)
 }
 
+// RequestReviewers sets the given users as reviewers of the Changeset.
+func (s *GitLabSource) RequestReviewers(ctx context.Context, c *Changeset, users []string) error {
+	mr, ok := c.Changeset.Metadata.(*gitlab.MergeRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitLab merge request")
+	}
+	project := c.TargetRepo.Metadata.(*gitlab.Project)
+
+	reviewerIDs := make([]int32, 0, len(users))
+	for _, username := range users {
+		found, _, err := s.client.ListUsers(ctx, "users?username="+url.QueryEscape(username))
+		if err != nil {
+			return errors.Wrapf(err, "looking up GitLab user %q", username)
+		}
+		if len(found) == 0 {
+			return errors.Newf("GitLab user %q not found", username)
+		}
+		reviewerIDs = append(reviewerIDs, found[0].ID)
+	}
+
+	updated, err := s.client.UpdateMergeRequest(ctx, project, mr, gitlab.UpdateMergeRequestOpts{
+		ReviewerIDs: reviewerIDs,
+	})
+	if err != nil {
+		return errors.Wrap(err, "requesting reviewers on GitLab merge request")
+	}
+
+	// These additional API calls can go away once we can use the GraphQL API.
+	if err := s.decorateMergeRequestData(ctx, project, updated); err != nil {
+		return errors.Wrapf(err, "retrieving additional data for merge request %d", updated.IID)
+	}
+
+	return c.Changeset.SetMetadata(updated)
+}
+
 // GetNamespaceFork returns a repo pointing to a fork of the given repo in
 // the given namespace, ensuring that the fork exists and is a fork of the
 // target repo.
@@ -782,6 +782,48 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 		}
 	})
 
+	t.Run("RequestReviewers", func(t *testing.T) {
+		t.Run("user not found", func(t *testing.T) {
+			p := newGitLabChangesetSourceTestProvider(t)
+			p.changeset.Changeset.Metadata = &gitlab.MergeRequest{}
+			p.mockListUsers(map[string]int32{})
+
+			err := p.source.RequestReviewers(p.ctx, p.changeset, []string{"alice"})
+			if err == nil {
+				t.Error("unexpected nil error")
+			}
+		})
+
+		t.Run("success", func(t *testing.T) {
+			in := &gitlab.MergeRequest{IID: 2}
+			out := &gitlab.MergeRequest{IID: 2}
+
+			p := newGitLabChangesetSourceTestProvider(t)
+			p.changeset.Changeset.Metadata = in
+			p.mockListUsers(map[string]int32{"alice": 10, "bob": 11})
+			p.mockGetMergeRequestNotes(out.IID, nil, 20, nil)
+			p.mockGetMergeRequestResourceStateEvents(out.IID, nil, 20, nil)
+			p.mockGetMergeRequestPipelines(out.IID, nil, 20, nil)
+
+			var haveReviewerIDs []int32
+			gitlab.MockUpdateMergeRequest = func(client *gitlab.Client, ctx context.Context, project *gitlab.Project, mr *gitlab.MergeRequest, opts gitlab.UpdateMergeRequestOpts) (*gitlab.MergeRequest, error) {
+				p.testCommonParams(ctx, client, project)
+				haveReviewerIDs = opts.ReviewerIDs
+				return out, nil
+			}
+
+			if err := p.source.RequestReviewers(p.ctx, p.changeset, []string{"alice", "bob"}); err != nil {
+				t.Errorf("unexpected non-nil error: %+v", err)
+			}
+			if diff := cmp.Diff([]int32{10, 11}, haveReviewerIDs); diff != "" {
+				t.Errorf("unexpected reviewer IDs (-want +got):\n%s", diff)
+			}
+			if p.changeset.Changeset.Metadata != out {
+				t.Errorf("metadata not correctly updated: have %+v; want %+v", p.changeset.Changeset.Metadata, out)
+			}
+		})
+	})
+
 	t.Run("CreateComment", func(t *testing.T) {
 		commentBody := "test-comment"
 		t.Run("invalid metadata", func(t *testing.T) {
@@ -1114,6 +1156,22 @@ func (p *gitLabChangesetSourceTestProvider) mockUpdateMergeRequest(expectedMR, u
 	}
 }
 
+// mockListUsers mocks a gitlab.ListUsers call that looks up a single user by
+// their username.
+func (p *gitLabChangesetSourceTestProvider) mockListUsers(ids map[string]int32) {
+	gitlab.MockListUsers = func(client *gitlab.Client, ctx context.Context, urlStr string) ([]*gitlab.User, *string, error) {
+		u, err := url.Parse(urlStr)
+		if err != nil {
+			p.t.Fatalf("invalid URL %q: %+v", urlStr, err)
+		}
+		username := u.Query().Get("username")
+		if id, ok := ids[username]; ok {
+			return []*gitlab.User{{ID: id, Username: username}}, nil, nil
+		}
+		return []*gitlab.User{}, nil, nil
+	}
+}
+
 func (p *gitLabChangesetSourceTestProvider) mockCreateComment(expected string, err error) {
 	gitlab.MockCreateMergeRequestNote = func(client *gitlab.Client, ctx context.Context, project *gitlab.Project, mr *gitlab.MergeRequest, body string) error {
 		p.testCommonParams(ctx, client, project)
@@ -1167,6 +1225,7 @@ func (p *gitLabChangesetSourceTestProvider) unmock() {
 	gitlab.MockGetOpenMergeRequestByRefs = nil
 	gitlab.MockUpdateMergeRequest = nil
 	gitlab.MockCreateMergeRequestNote = nil
+	gitlab.MockListUsers = nil
 
 	versions.MockGetVersions = nil
 }
@@ -87,7 +87,7 @@ func NewMockChangesetSource() *MockChangesetSource {
 			},
 		},
 		MergeChangesetFunc: &ChangesetSourceMergeChangesetFunc{
-			defaultHook: func(context.Context, *Changeset, bool) (r0 error) {
+			defaultHook: func(context.Context, *Changeset, types1.ChangesetMergeStrategy) (r0 error) {
 				return
 			},
 		},
@@ -144,7 +144,7 @@ func NewStrictMockChangesetSource() *MockChangesetSource {
 			},
 		},
 		MergeChangesetFunc: &ChangesetSourceMergeChangesetFunc{
-			defaultHook: func(context.Context, *Changeset, bool) error {
+			defaultHook: func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error {
 				panic("unexpected invocation of MockChangesetSource.MergeChangeset")
 			},
 		},
@@ -753,15 +753,15 @@ func (c ChangesetSourceLoadChangesetFuncCall) Results() []interface{} {
 // MergeChangeset method of the parent MockChangesetSource instance is
 // invoked.
 type ChangesetSourceMergeChangesetFunc struct {
-	defaultHook func(context.Context, *Changeset, bool) error
-	hooks       []func(context.Context, *Changeset, bool) error
+	defaultHook func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error
+	hooks       []func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error
 	history     []ChangesetSourceMergeChangesetFuncCall
 	mutex       sync.Mutex
 }
 
 // MergeChangeset delegates to the next hook function in the queue and
 // stores the parameter and result values of this invocation.
-func (m *MockChangesetSource) MergeChangeset(v0 context.Context, v1 *Changeset, v2 bool) error {
+func (m *MockChangesetSource) MergeChangeset(v0 context.Context, v1 *Changeset, v2 types1.ChangesetMergeStrategy) error {
 	r0 := m.MergeChangesetFunc.nextHook()(v0, v1, v2)
 	m.MergeChangesetFunc.appendCall(ChangesetSourceMergeChangesetFuncCall{v0, v1, v2, r0})
 	return r0
@@ -770,7 +770,7 @@ func (m *MockChangesetSource) MergeChangeset(v0 context.Context, v1 *Changeset,
 // SetDefaultHook sets function that is called when the MergeChangeset
 // method of the parent MockChangesetSource instance is invoked and the hook
 // queue is empty.
-func (f *ChangesetSourceMergeChangesetFunc) SetDefaultHook(hook func(context.Context, *Changeset, bool) error) {
+func (f *ChangesetSourceMergeChangesetFunc) SetDefaultHook(hook func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error) {
 	f.defaultHook = hook
 }
 
@@ -778,7 +778,7 @@ func (f *ChangesetSourceMergeChangesetFunc) SetDefaultHook(hook func(context.Con
 // MergeChangeset method of the parent MockChangesetSource instance invokes
 // the hook at the front of the queue and discards it. After the queue is
 // empty, the default hook function is invoked for any future action.
-func (f *ChangesetSourceMergeChangesetFunc) PushHook(hook func(context.Context, *Changeset, bool) error) {
+func (f *ChangesetSourceMergeChangesetFunc) PushHook(hook func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -787,19 +787,19 @@ func (f *ChangesetSourceMergeChangesetFunc) PushHook(hook func(context.Context,
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
 func (f *ChangesetSourceMergeChangesetFunc) SetDefaultReturn(r0 error) {
-	f.SetDefaultHook(func(context.Context, *Changeset, bool) error {
+	f.SetDefaultHook(func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error {
 		return r0
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
 func (f *ChangesetSourceMergeChangesetFunc) PushReturn(r0 error) {
-	f.PushHook(func(context.Context, *Changeset, bool) error {
+	f.PushHook(func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error {
 		return r0
 	})
 }
 
-func (f *ChangesetSourceMergeChangesetFunc) nextHook() func(context.Context, *Changeset, bool) error {
+func (f *ChangesetSourceMergeChangesetFunc) nextHook() func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -841,7 +841,7 @@ type ChangesetSourceMergeChangesetFuncCall struct {
 	Arg1 *Changeset
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
-	Arg2 bool
+	Arg2 types1.ChangesetMergeStrategy
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 error
@@ -1371,7 +1371,7 @@ func NewMockForkableChangesetSource() *MockForkableChangesetSource {
 			},
 		},
 		MergeChangesetFunc: &ForkableChangesetSourceMergeChangesetFunc{
-			defaultHook: func(context.Context, *Changeset, bool) (r0 error) {
+			defaultHook: func(context.Context, *Changeset, types1.ChangesetMergeStrategy) (r0 error) {
 				return
 			},
 		},
@@ -1439,7 +1439,7 @@ func NewStrictMockForkableChangesetSource() *MockForkableChangesetSource {
 			},
 		},
 		MergeChangesetFunc: &ForkableChangesetSourceMergeChangesetFunc{
-			defaultHook: func(context.Context, *Changeset, bool) error {
+			defaultHook: func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error {
 				panic("unexpected invocation of MockForkableChangesetSource.MergeChangeset")
 			},
 		},
@@ -2291,15 +2291,15 @@ func (c ForkableChangesetSourceLoadChangesetFuncCall) Results() []interface{} {
 // MergeChangeset method of the parent MockForkableChangesetSource instance
 // is invoked.
 type ForkableChangesetSourceMergeChangesetFunc struct {
-	defaultHook func(context.Context, *Changeset, bool) error
-	hooks       []func(context.Context, *Changeset, bool) error
+	defaultHook func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error
+	hooks       []func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error
 	history     []ForkableChangesetSourceMergeChangesetFuncCall
 	mutex       sync.Mutex
 }
 
 // MergeChangeset delegates to the next hook function in the queue and
 // stores the parameter and result values of this invocation.
-func (m *MockForkableChangesetSource) MergeChangeset(v0 context.Context, v1 *Changeset, v2 bool) error {
+func (m *MockForkableChangesetSource) MergeChangeset(v0 context.Context, v1 *Changeset, v2 types1.ChangesetMergeStrategy) error {
 	r0 := m.MergeChangesetFunc.nextHook()(v0, v1, v2)
 	m.MergeChangesetFunc.appendCall(ForkableChangesetSourceMergeChangesetFuncCall{v0, v1, v2, r0})
 	return r0
@@ -2308,7 +2308,7 @@ func (m *MockForkableChangesetSource) MergeChangeset(v0 context.Context, v1 *Cha
 // SetDefaultHook sets function that is called when the MergeChangeset
 // method of the parent MockForkableChangesetSource instance is invoked and
 // the hook queue is empty.
-func (f *ForkableChangesetSourceMergeChangesetFunc) SetDefaultHook(hook func(context.Context, *Changeset, bool) error) {
+func (f *ForkableChangesetSourceMergeChangesetFunc) SetDefaultHook(hook func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error) {
 	f.defaultHook = hook
 }
 
@@ -2317,7 +2317,7 @@ func (f *ForkableChangesetSourceMergeChangesetFunc) SetDefaultHook(hook func(con
 // invokes the hook at the front of the queue and discards it. After the
 // queue is empty, the default hook function is invoked for any future
 // action.
-func (f *ForkableChangesetSourceMergeChangesetFunc) PushHook(hook func(context.Context, *Changeset, bool) error) {
+func (f *ForkableChangesetSourceMergeChangesetFunc) PushHook(hook func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -2326,19 +2326,19 @@ func (f *ForkableChangesetSourceMergeChangesetFunc) PushHook(hook func(context.C
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
 func (f *ForkableChangesetSourceMergeChangesetFunc) SetDefaultReturn(r0 error) {
-	f.SetDefaultHook(func(context.Context, *Changeset, bool) error {
+	f.SetDefaultHook(func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error {
 		return r0
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
 func (f *ForkableChangesetSourceMergeChangesetFunc) PushReturn(r0 error) {
-	f.PushHook(func(context.Context, *Changeset, bool) error {
+	f.PushHook(func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error {
 		return r0
 	})
 }
 
-func (f *ForkableChangesetSourceMergeChangesetFunc) nextHook() func(context.Context, *Changeset, bool) error {
+func (f *ForkableChangesetSourceMergeChangesetFunc) nextHook() func(context.Context, *Changeset, types1.ChangesetMergeStrategy) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -2381,7 +2381,7 @@ type ForkableChangesetSourceMergeChangesetFuncCall struct {
 	Arg1 *Changeset
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
-	Arg2 bool
+	Arg2 types1.ChangesetMergeStrategy
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 error
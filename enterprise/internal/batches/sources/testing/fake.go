@@ -58,6 +58,7 @@ type FakeChangesetSource struct {
 	ValidateAuthenticatorCalled bool
 	MergeChangesetCalled        bool
 	IsArchivedPushErrorCalled   bool
+	RequestReviewersCalled      bool
 
 	// The Changeset.HeadRef to be expected in CreateChangeset/UpdateChangeset calls.
 	WantHeadRef string
@@ -68,6 +69,13 @@ type FakeChangesetSource struct {
 	// Changeset with changeset.SetMetadata.
 	FakeMetadata any
 
+	// The strategy MergeChangeset was last called with.
+	MergeChangesetStrategy btypes.ChangesetMergeStrategy
+
+	// The users and teams RequestReviewers was last called with.
+	RequestedReviewerUsers []string
+	RequestedReviewerTeams []string
+
 	// Whether or not the changeset already ChangesetExists on the code host at the time
 	// when CreateChangeset is called.
 	ChangesetExists bool
@@ -311,8 +319,20 @@ func (s *FakeChangesetSource) AuthenticatedUsername(ctx context.Context) (string
 	return s.Username, nil
 }
 
-func (s *FakeChangesetSource) MergeChangeset(ctx context.Context, c *sources.Changeset, squash bool) error {
+func (s *FakeChangesetSource) MergeChangeset(ctx context.Context, c *sources.Changeset, strategy btypes.ChangesetMergeStrategy) error {
 	s.MergeChangesetCalled = true
+	s.MergeChangesetStrategy = strategy
+	return s.Err
+}
+
+func (s *FakeChangesetSource) RequestReviewers(ctx context.Context, c *sources.Changeset, users []string) error {
+	return s.RequestTeamReviewers(ctx, c, users, nil)
+}
+
+func (s *FakeChangesetSource) RequestTeamReviewers(ctx context.Context, c *sources.Changeset, users, teams []string) error {
+	s.RequestReviewersCalled = true
+	s.RequestedReviewerUsers = users
+	s.RequestedReviewerTeams = teams
 	return s.Err
 }
 
@@ -37,6 +37,8 @@ var batchChangeColumns = []*sqlf.Query{
 	sqlf.Sprintf("batch_changes.updated_at"),
 	sqlf.Sprintf("batch_changes.closed_at"),
 	sqlf.Sprintf("batch_changes.batch_spec_id"),
+	sqlf.Sprintf("batch_changes.last_scheduled_at"),
+	sqlf.Sprintf("batch_changes.scheduled_batch_spec_id"),
 }
 
 // batchChangeInsertColumns is the list of batch changes columns that are
@@ -54,6 +56,8 @@ var batchChangeInsertColumns = []*sqlf.Query{
 	sqlf.Sprintf("updated_at"),
 	sqlf.Sprintf("closed_at"),
 	sqlf.Sprintf("batch_spec_id"),
+	sqlf.Sprintf("last_scheduled_at"),
+	sqlf.Sprintf("scheduled_batch_spec_id"),
 }
 
 func (s *Store) UpsertBatchChange(ctx context.Context, c *btypes.BatchChange) (err error) {
@@ -76,10 +80,10 @@ func (s *Store) UpsertBatchChange(ctx context.Context, c *btypes.BatchChange) (e
 
 var upsertBatchChangeQueryFmtstr = `
 INSERT INTO batch_changes (%s)
-VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 ON CONFLICT (%s) WHERE %s
 DO UPDATE SET
-(%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+(%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING %s
 `
 
@@ -119,6 +123,8 @@ func (s *Store) upsertBatchChangeQuery(c *btypes.BatchChange) *sqlf.Query {
 		c.UpdatedAt,
 		dbutil.NullTimeColumn(c.ClosedAt),
 		c.BatchSpecID,
+		dbutil.NullTimeColumn(c.LastScheduledAt),
+		dbutil.NullInt64Column(c.ScheduledBatchSpecID),
 		sqlf.Join(conflictTarget, ", "),
 		predicate,
 		sqlf.Join(batchChangeInsertColumns, ", "),
@@ -133,6 +139,8 @@ func (s *Store) upsertBatchChangeQuery(c *btypes.BatchChange) *sqlf.Query {
 		c.UpdatedAt,
 		dbutil.NullTimeColumn(c.ClosedAt),
 		c.BatchSpecID,
+		dbutil.NullTimeColumn(c.LastScheduledAt),
+		dbutil.NullInt64Column(c.ScheduledBatchSpecID),
 		sqlf.Join(batchChangeColumns, ", "),
 	)
 }
@@ -158,7 +166,7 @@ func (s *Store) CreateBatchChange(ctx context.Context, c *btypes.BatchChange) (e
 
 var createBatchChangeQueryFmtstr = `
 INSERT INTO batch_changes (%s)
-VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING %s
 `
 
@@ -185,6 +193,8 @@ func (s *Store) createBatchChangeQuery(c *btypes.BatchChange) *sqlf.Query {
 		c.UpdatedAt,
 		dbutil.NullTimeColumn(c.ClosedAt),
 		c.BatchSpecID,
+		dbutil.NullTimeColumn(c.LastScheduledAt),
+		dbutil.NullInt64Column(c.ScheduledBatchSpecID),
 		sqlf.Join(batchChangeColumns, ", "),
 	)
 }
@@ -210,7 +220,7 @@ func (s *Store) UpdateBatchChange(ctx context.Context, c *btypes.BatchChange) (e
 
 var updateBatchChangeQueryFmtstr = `
 UPDATE batch_changes
-SET (%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+SET (%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 WHERE id = %s
 RETURNING %s
 `
@@ -232,6 +242,8 @@ func (s *Store) updateBatchChangeQuery(c *btypes.BatchChange) *sqlf.Query {
 		c.UpdatedAt,
 		dbutil.NullTimeColumn(c.ClosedAt),
 		c.BatchSpecID,
+		dbutil.NullTimeColumn(c.LastScheduledAt),
+		dbutil.NullInt64Column(c.ScheduledBatchSpecID),
 		c.ID,
 		sqlf.Join(batchChangeColumns, ", "),
 	)
@@ -571,6 +583,10 @@ type ListBatchChangesOpts struct {
 	RepoID api.RepoID
 
 	ExcludeDraftsNotOwnedByUserID int32
+
+	// OnlyScheduled restricts the list to batch changes whose current batch
+	// spec has a schedule.
+	OnlyScheduled bool
 }
 
 // ListBatchChanges lists batch changes with the given filters.
@@ -684,6 +700,10 @@ func listBatchChangesQuery(opts *ListBatchChangesOpts, repoAuthzConds *sqlf.Quer
 		)`, opts.RepoID, repoAuthzConds))
 	}
 
+	if opts.OnlyScheduled {
+		preds = append(preds, sqlf.Sprintf("EXISTS (SELECT 1 FROM batch_specs WHERE batch_specs.id = batch_changes.batch_spec_id AND batch_specs.spec->>'schedule' <> '')"))
+	}
+
 	if len(preds) == 0 {
 		preds = append(preds, sqlf.Sprintf("TRUE"))
 	}
@@ -710,6 +730,8 @@ func scanBatchChange(c *btypes.BatchChange, s dbutil.Scanner) error {
 		&c.UpdatedAt,
 		&dbutil.NullTime{Time: &c.ClosedAt},
 		&c.BatchSpecID,
+		&dbutil.NullTime{Time: &c.LastScheduledAt},
+		&dbutil.NullInt64{N: &c.ScheduledBatchSpecID},
 	)
 }
 
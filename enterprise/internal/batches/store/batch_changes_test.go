@@ -20,6 +20,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -657,6 +658,34 @@ func testStoreBatchChanges(t *testing.T, ctx context.Context, s *Store, clock bt
 			assert.NoError(t, err)
 			assert.Equal(t, want, have)
 		})
+
+		t.Run("ListBatchChanges OnlyScheduled", func(t *testing.T) {
+			tx, err := s.Transact(ctx)
+			require.NoError(t, err)
+			defer tx.Done(errors.New("always rollback"))
+
+			spec := &btypes.BatchSpec{
+				NamespaceUserID: adminUser.ID,
+				UserID:          adminUser.ID,
+				Spec:            &batcheslib.BatchSpec{Name: "scheduled", Schedule: "24h"},
+			}
+			require.NoError(t, tx.CreateBatchSpec(ctx, spec))
+
+			c := &btypes.BatchChange{
+				Name:            "scheduled",
+				BatchSpecID:     spec.ID,
+				NamespaceUserID: adminUser.ID,
+				CreatorID:       adminUser.ID,
+				LastApplierID:   adminUser.ID,
+				LastAppliedAt:   clock.Now(),
+				LastScheduledAt: clock.Now(),
+			}
+			require.NoError(t, tx.CreateBatchChange(ctx, c))
+
+			have, _, err := tx.ListBatchChanges(ctx, ListBatchChangesOpts{OnlyScheduled: true})
+			assert.NoError(t, err)
+			assert.Equal(t, []*btypes.BatchChange{c}, have)
+		})
 	})
 
 	t.Run("Update", func(t *testing.T) {
@@ -45,6 +45,7 @@ var changesetSpecInsertColumns = []string{
 	"commit_author_name",
 	"commit_author_email",
 	"type",
+	"reviewers",
 }
 
 // changesetSpecColumns are used by the changeset spec related Store methods to
@@ -72,6 +73,7 @@ var changesetSpecColumns = SQLColumns{
 	"changeset_specs.commit_author_name",
 	"changeset_specs.commit_author_email",
 	"changeset_specs.type",
+	"changeset_specs.reviewers",
 }
 
 var oneGigabyte = 1000000000
@@ -107,6 +109,14 @@ func (s *Store) CreateChangesetSpec(ctx context.Context, cs ...*btypes.Changeset
 				}
 			}
 
+			var reviewers []byte
+			if c.Reviewers != nil {
+				reviewers, err = json.Marshal(c.Reviewers)
+				if err != nil {
+					return err
+				}
+			}
+
 			// We check if the resulting diff is greater than 1GB, since the limit
 			// for the diff column (which is bytea) is 1GB
 			if len(c.Diff) > oneGigabyte {
@@ -137,6 +147,7 @@ func (s *Store) CreateChangesetSpec(ctx context.Context, cs ...*btypes.Changeset
 				dbutil.NewNullString(c.CommitAuthorName),
 				dbutil.NewNullString(c.CommitAuthorEmail),
 				c.Type,
+				reviewers,
 			); err != nil {
 				return err
 			}
@@ -539,6 +550,7 @@ func deleteChangesetSpecsQuery(opts *DeleteChangesetSpecsOpts) *sqlf.Query {
 func scanChangesetSpec(c *btypes.ChangesetSpec, s dbutil.Scanner) error {
 	var published []byte
 	var typ string
+	var reviewers dbutil.NullJSONRawMessage
 	err := s.Scan(
 		&c.ID,
 		&c.RandID,
@@ -562,6 +574,7 @@ func scanChangesetSpec(c *btypes.ChangesetSpec, s dbutil.Scanner) error {
 		&dbutil.NullString{S: &c.CommitAuthorName},
 		&dbutil.NullString{S: &c.CommitAuthorEmail},
 		&typ,
+		&reviewers,
 	)
 	if err != nil {
 		return errors.Wrap(err, "scanning changeset spec")
@@ -575,6 +588,12 @@ func scanChangesetSpec(c *btypes.ChangesetSpec, s dbutil.Scanner) error {
 		}
 	}
 
+	if len(reviewers.Raw) != 0 {
+		if err := json.Unmarshal(reviewers.Raw, &c.Reviewers); err != nil {
+			return errors.Wrap(err, "unmarshalling reviewers")
+		}
+	}
+
 	return nil
 }
 
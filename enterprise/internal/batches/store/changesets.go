@@ -68,6 +68,10 @@ var changesetStringColumns = SQLColumns{
 	"closing",
 	"syncer_error",
 	"detached_at",
+	"requested_reviewers",
+	"blocked_by_dependencies",
+	"last_merge_attempt_at",
+	"merge_failure_message",
 }
 
 // changesetColumns are used by the changeset related Store methods and by
@@ -110,6 +114,10 @@ var changesetColumns = []*sqlf.Query{
 	sqlf.Sprintf("changesets.closing"),
 	sqlf.Sprintf("changesets.syncer_error"),
 	sqlf.Sprintf("changesets.detached_at"),
+	sqlf.Sprintf("changesets.requested_reviewers"),
+	sqlf.Sprintf("changesets.blocked_by_dependencies"),
+	sqlf.Sprintf("changesets.last_merge_attempt_at"),
+	sqlf.Sprintf("changesets.merge_failure_message"),
 }
 
 // changesetInsertColumns is the list of changeset columns that are modified in
@@ -147,6 +155,10 @@ var changesetInsertColumns = []*sqlf.Query{
 	sqlf.Sprintf("num_failures"),
 	sqlf.Sprintf("closing"),
 	sqlf.Sprintf("syncer_error"),
+	sqlf.Sprintf("requested_reviewers"),
+	sqlf.Sprintf("blocked_by_dependencies"),
+	sqlf.Sprintf("last_merge_attempt_at"),
+	sqlf.Sprintf("merge_failure_message"),
 	// We additionally store the result of changeset.Title() in a column, so
 	// the business logic for determining it is in one place and the field is
 	// indexable for searching.
@@ -209,6 +221,10 @@ var changesetInsertStringColumns = []string{
 	"num_failures",
 	"closing",
 	"syncer_error",
+	"requested_reviewers",
+	"blocked_by_dependencies",
+	"last_merge_attempt_at",
+	"merge_failure_message",
 	"external_title",
 }
 
@@ -229,6 +245,7 @@ var temporaryChangesetInsertColumns = []string{
 	"num_failures",
 	"closing",
 	"syncer_error",
+	"merge_failure_message",
 }
 
 // CreateChangeset creates the given Changesets.
@@ -302,6 +319,10 @@ func (s *Store) CreateChangeset(ctx context.Context, cs ...*btypes.Changeset) (e
 				c.NumFailures,
 				c.Closing,
 				c.SyncErrorMessage,
+				requestedReviewersColumn(c),
+				c.BlockedByDependencies,
+				dbutil.NullTimeColumn(c.LastMergeAttemptAt),
+				c.MergeFailureMessage,
 				dbutil.NullStringColumn(title),
 			); err != nil {
 				return err
@@ -880,6 +901,10 @@ func (s *Store) changesetWriteQuery(q string, includeID bool, c *btypes.Changese
 		c.NumFailures,
 		c.Closing,
 		c.SyncErrorMessage,
+		requestedReviewersColumn(c),
+		c.BlockedByDependencies,
+		dbutil.NullTimeColumn(c.LastMergeAttemptAt),
+		c.MergeFailureMessage,
 		dbutil.NullStringColumn(title),
 	}
 
@@ -894,7 +919,7 @@ func (s *Store) changesetWriteQuery(q string, includeID bool, c *btypes.Changese
 
 var updateChangesetQueryFmtstr = `
 UPDATE changesets
-SET (%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+SET (%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 WHERE id = %s
 RETURNING
   %s
@@ -950,6 +975,7 @@ func (s *Store) UpdateChangesetsForApply(ctx context.Context, cs []*btypes.Chang
 				c.NumFailures,
 				c.Closing,
 				c.SyncErrorMessage,
+				c.MergeFailureMessage,
 			); err != nil {
 				return err
 			}
@@ -989,7 +1015,8 @@ CREATE TEMPORARY TABLE temp_changesets (
     num_resets integer DEFAULT 0 NOT NULL,
     num_failures integer DEFAULT 0 NOT NULL,
     closing boolean DEFAULT false NOT NULL,
-    syncer_error text
+    syncer_error text,
+    merge_failure_message text
 ) ON COMMIT DROP
 `
 
@@ -1000,7 +1027,7 @@ UPDATE changesets c SET batch_change_ids = source.batch_change_ids, updated_at =
                         previous_spec_id = source.previous_spec_id, ui_publication_state = source.ui_publication_state,
                         reconciler_state = source.reconciler_state, failure_message = source.failure_message,
                         num_resets = source.num_resets, num_failures = source.num_failures, closing = source.closing,
-                        syncer_error = source.syncer_error
+                        syncer_error = source.syncer_error, merge_failure_message = source.merge_failure_message
 FROM temp_changesets source
 WHERE c.id = source.id
 `
@@ -1314,6 +1341,230 @@ updated_records AS (
 SELECT COUNT(id) FROM all_matching WHERE all_matching.reconciler_state = %s
 `
 
+// EnqueueAutoMergeableChangesets enqueues all changesets that are ready to be
+// merged automatically with the given reconciler state, and returns how many
+// were enqueued. A changeset is ready to be merged automatically if the batch
+// spec of the open batch change owning it has an auto-merge policy, and the
+// changeset is open, all of its checks passed, and it has been approved.
+//
+// Only changesets that have been reconciled successfully are enqueued. If the
+// code host refused to merge a changeset, it isn't enqueued again until it has
+// been updated on the code host since, or until it has been re-enqueued by
+// applying a batch spec or retrying it. Changesets are never enqueued before
+// all of the changesets they depend on have been merged.
+func (s *Store) EnqueueAutoMergeableChangesets(ctx context.Context, state btypes.ReconcilerState) (enqueued int, err error) {
+	ctx, _, endObservation := s.operations.enqueueAutoMergeableChangesets.With(ctx, &err, observation.Args{})
+	defer func() {
+		endObservation(1, observation.Args{LogFields: []log.Field{log.Int("enqueued", enqueued)}})
+	}()
+
+	q := sqlf.Sprintf(
+		enqueueAutoMergeableChangesetsFmtstr,
+		state.ToDB(),
+		s.now(),
+		btypes.ChangesetPublicationStatePublished,
+		btypes.ChangesetExternalStateOpen,
+		btypes.ChangesetCheckStatePassed,
+		btypes.ChangesetReviewStateApproved,
+		btypes.ReconcilerStateCompleted.ToDB(),
+		listChangesetDependenciesQuery(ListChangesetDependenciesOpts{}),
+	)
+
+	enqueued, _, err = basestore.ScanFirstInt(s.Query(ctx, q))
+	return enqueued, err
+}
+
+const enqueueAutoMergeableChangesetsFmtstr = `
+WITH updated_records AS (
+	UPDATE
+		changesets
+	SET
+		reconciler_state = %s,
+		failure_message = NULL,
+		num_resets = 0,
+		num_failures = 0,
+		updated_at = %s
+	FROM
+		batch_changes
+	JOIN
+		batch_specs ON batch_specs.id = batch_changes.batch_spec_id
+	WHERE
+		changesets.owned_by_batch_change_id = batch_changes.id
+		AND
+		batch_changes.closed_at IS NULL
+		AND
+		batch_specs.spec ? 'autoMerge'
+		AND
+		changesets.publication_state = %s
+		AND
+		changesets.external_state = %s
+		AND
+		changesets.external_check_state = %s
+		AND
+		changesets.external_review_state = %s
+		AND
+		changesets.reconciler_state = %s
+		AND
+		NOT changesets.closing
+		AND
+		NOT changesets.blocked_by_dependencies
+		AND
+		(
+			changesets.merge_failure_message IS NULL
+			OR
+			changesets.external_updated_at > changesets.last_merge_attempt_at
+		)
+		AND
+		changesets.batch_change_ids ? batch_changes.id::TEXT
+		AND
+		NOT COALESCE((changesets.batch_change_ids->batch_changes.id::TEXT->>'detach')::bool, false)
+		AND
+		NOT COALESCE((changesets.batch_change_ids->batch_changes.id::TEXT->>'archive')::bool, false)
+		AND
+		NOT COALESCE((changesets.batch_change_ids->batch_changes.id::TEXT->>'isArchived')::bool, false)
+		AND
+		changesets.id NOT IN (
+			SELECT dependencies.changeset_id FROM (%s) AS dependencies WHERE NOT dependencies.satisfied
+		)
+	RETURNING
+		changesets.id
+)
+SELECT COUNT(id) FROM updated_records
+`
+
+// ListChangesetDependenciesOpts captures the query options needed for listing
+// the dependencies between changesets.
+type ListChangesetDependenciesOpts struct {
+	BatchChangeID int64
+	ChangesetID   int64
+}
+
+// ListChangesetDependencies returns the dependencies between the changesets
+// owned by batch changes, as declared by the dependencies in the batch spec
+// of each batch change. Dependencies that name a branch only apply to the
+// changeset of the workspace with that branch. Dependencies on repositories or
+// workspaces in which the batch change doesn't own a changeset are omitted.
+func (s *Store) ListChangesetDependencies(ctx context.Context, opts ListChangesetDependenciesOpts) (deps []*btypes.ChangesetDependency, err error) {
+	ctx, _, endObservation := s.operations.listChangesetDependencies.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("batchChangeID", int(opts.BatchChangeID)),
+		log.Int("changesetID", int(opts.ChangesetID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q := listChangesetDependenciesQuery(opts)
+	deps = make([]*btypes.ChangesetDependency, 0)
+	err = s.query(ctx, q, func(sc dbutil.Scanner) error {
+		var d btypes.ChangesetDependency
+		if err := sc.Scan(&d.ChangesetID, &d.DependsOnChangesetID, &d.Satisfied); err != nil {
+			return err
+		}
+		deps = append(deps, &d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+const listChangesetDependenciesQueryFmtstr = `
+SELECT DISTINCT
+	changesets.id AS changeset_id,
+	prerequisites.id AS depends_on_changeset_id,
+	COALESCE(prerequisites.external_state = %s, FALSE) AS satisfied
+FROM
+	batch_changes
+JOIN
+	batch_specs ON batch_specs.id = batch_changes.batch_spec_id
+CROSS JOIN LATERAL
+	jsonb_array_elements(COALESCE(batch_specs.spec->'dependencies', '[]'::jsonb)) AS dependency
+CROSS JOIN LATERAL
+	jsonb_array_elements(dependency->'dependsOn') AS depends_on
+JOIN
+	repo ON repo.name = dependency->>'repository' AND repo.deleted_at IS NULL
+JOIN
+	changesets ON changesets.repo_id = repo.id AND changesets.owned_by_batch_change_id = batch_changes.id
+JOIN
+	changeset_specs ON changeset_specs.id = changesets.current_spec_id
+JOIN
+	repo AS prerequisite_repo ON prerequisite_repo.name = depends_on->>'repository' AND prerequisite_repo.deleted_at IS NULL
+JOIN
+	changesets AS prerequisites ON prerequisites.repo_id = prerequisite_repo.id AND prerequisites.owned_by_batch_change_id = batch_changes.id
+JOIN
+	changeset_specs AS prerequisite_specs ON prerequisite_specs.id = prerequisites.current_spec_id
+WHERE %s
+ORDER BY changesets.id ASC, prerequisites.id ASC
+`
+
+func listChangesetDependenciesQuery(opts ListChangesetDependenciesOpts) *sqlf.Query {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("changesets.detached_at IS NULL"),
+		sqlf.Sprintf("prerequisites.detached_at IS NULL"),
+		// Each workspace of a repository has its own changeset branch, which
+		// is how dependencies refer to a single workspace.
+		sqlf.Sprintf(`(dependency->>'branch' IS NULL OR regexp_replace(changeset_specs.head_ref, '^refs/heads/', '') = regexp_replace(dependency->>'branch', '^refs/heads/', ''))`),
+		sqlf.Sprintf(`(depends_on->>'branch' IS NULL OR regexp_replace(prerequisite_specs.head_ref, '^refs/heads/', '') = regexp_replace(depends_on->>'branch', '^refs/heads/', ''))`),
+	}
+	if opts.BatchChangeID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_changes.id = %s", opts.BatchChangeID))
+	}
+	if opts.ChangesetID != 0 {
+		preds = append(preds, sqlf.Sprintf("changesets.id = %s", opts.ChangesetID))
+	}
+
+	return sqlf.Sprintf(
+		listChangesetDependenciesQueryFmtstr,
+		btypes.ChangesetExternalStateMerged,
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
+// EnqueueUnblockedChangesets enqueues all changesets that were held back by
+// the reconciler because of unmerged dependencies, and whose dependencies have
+// all been merged since, with the given reconciler state. It returns how many
+// changesets were enqueued.
+func (s *Store) EnqueueUnblockedChangesets(ctx context.Context, state btypes.ReconcilerState) (enqueued int, err error) {
+	ctx, _, endObservation := s.operations.enqueueUnblockedChangesets.With(ctx, &err, observation.Args{})
+	defer func() {
+		endObservation(1, observation.Args{LogFields: []log.Field{log.Int("enqueued", enqueued)}})
+	}()
+
+	q := sqlf.Sprintf(
+		enqueueUnblockedChangesetsFmtstr,
+		state.ToDB(),
+		s.now(),
+		btypes.ReconcilerStateCompleted.ToDB(),
+		listChangesetDependenciesQuery(ListChangesetDependenciesOpts{}),
+	)
+
+	enqueued, _, err = basestore.ScanFirstInt(s.Query(ctx, q))
+	return enqueued, err
+}
+
+const enqueueUnblockedChangesetsFmtstr = `
+WITH updated_records AS (
+	UPDATE
+		changesets
+	SET
+		reconciler_state = %s,
+		failure_message = NULL,
+		num_resets = 0,
+		num_failures = 0,
+		updated_at = %s
+	WHERE
+		changesets.blocked_by_dependencies
+		AND
+		changesets.reconciler_state = %s
+		AND
+		changesets.id NOT IN (
+			SELECT dependencies.changeset_id FROM (%s) AS dependencies WHERE NOT dependencies.satisfied
+		)
+	RETURNING
+		changesets.id
+)
+SELECT COUNT(id) FROM updated_records
+`
+
 // jsonBatchChangeChangesetSet represents a "join table" set as a JSONB object
 // where the keys are the ids and the values are json objects holding the properties.
 // It implements the sql.Scanner interface so it can be used as a scan destination,
@@ -1372,6 +1623,8 @@ func scanChangeset(t *btypes.Changeset, s dbutil.Scanner) error {
 		failureMessage      string
 		syncErrorMessage    string
 		reconcilerState     string
+		requestedReviewers  []string
+		mergeFailureMessage string
 	)
 	err := s.Scan(
 		&t.ID,
@@ -1408,6 +1661,10 @@ func scanChangeset(t *btypes.Changeset, s dbutil.Scanner) error {
 		&t.Closing,
 		&dbutil.NullString{S: &syncErrorMessage},
 		&dbutil.NullTime{Time: &t.DetachedAt},
+		pq.Array(&requestedReviewers),
+		&t.BlockedByDependencies,
+		&dbutil.NullTime{Time: &t.LastMergeAttemptAt},
+		&dbutil.NullString{S: &mergeFailureMessage},
 	)
 	if err != nil {
 		return errors.Wrap(err, "scanning changeset")
@@ -1423,6 +1680,12 @@ func scanChangeset(t *btypes.Changeset, s dbutil.Scanner) error {
 		t.SyncErrorMessage = &syncErrorMessage
 	}
 	t.ReconcilerState = btypes.ReconcilerState(strings.ToUpper(reconcilerState))
+	if len(requestedReviewers) != 0 {
+		t.RequestedReviewers = requestedReviewers
+	}
+	if mergeFailureMessage != "" {
+		t.MergeFailureMessage = &mergeFailureMessage
+	}
 
 	switch t.ExternalServiceType {
 	case extsvc.TypeGitHub:
@@ -1745,6 +2008,15 @@ func uiPublicationStateColumn(c *btypes.Changeset) *string {
 	return uiPublicationState
 }
 
+// requestedReviewersColumn returns the value for the non-nullable
+// requested_reviewers column, which defaults to an empty array.
+func requestedReviewersColumn(c *btypes.Changeset) any {
+	if c.RequestedReviewers == nil {
+		return pq.Array([]string{})
+	}
+	return pq.Array(c.RequestedReviewers)
+}
+
 // CleanDetachedChangesets deletes changesets that have been detached after duration specified.
 func (s *Store) CleanDetachedChangesets(ctx context.Context, retention time.Duration) (err error) {
 	ctx, _, endObservation := s.operations.cleanDetachedChangesets.With(ctx, &err, observation.Args{LogFields: []log.Field{
@@ -29,6 +29,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 )
 
 func testStoreChangesets(t *testing.T, ctx context.Context, s *Store, clock bt.Clock) {
@@ -1535,6 +1536,178 @@ func testStoreChangesets(t *testing.T, ctx context.Context, s *Store, clock bt.C
 		})
 	})
 
+	t.Run("EnqueueAutoMergeableChangesets", func(t *testing.T) {
+		autoMergeSpec := bt.CreateBatchSpec(t, ctx, s, "auto-merge", user.ID, 0)
+		autoMergeSpec.Spec.AutoMerge = &batcheslib.AutoMerge{Strategy: "squash"}
+		require.NoError(t, s.UpdateBatchSpec(ctx, autoMergeSpec))
+		autoMergeBatchChange := bt.CreateBatchChange(t, ctx, s, "auto-merge", user.ID, autoMergeSpec.ID)
+
+		manualSpec := bt.CreateBatchSpec(t, ctx, s, "manual-merge", user.ID, 0)
+		manualBatchChange := bt.CreateBatchChange(t, ctx, s, "manual-merge", user.ID, manualSpec.ID)
+
+		createChangeset := func(batchChangeID int64, checkState btypes.ChangesetCheckState, reviewState btypes.ChangesetReviewState, reconcilerState btypes.ReconcilerState) *btypes.Changeset {
+			return bt.CreateChangeset(t, ctx, s, bt.TestChangesetOpts{
+				Repo:                repo.ID,
+				BatchChange:         batchChangeID,
+				OwnedByBatchChange:  batchChangeID,
+				PublicationState:    btypes.ChangesetPublicationStatePublished,
+				ExternalState:       btypes.ChangesetExternalStateOpen,
+				ExternalCheckState:  checkState,
+				ExternalReviewState: reviewState,
+				ReconcilerState:     reconcilerState,
+			})
+		}
+
+		ready := createChangeset(autoMergeBatchChange.ID, btypes.ChangesetCheckStatePassed, btypes.ChangesetReviewStateApproved, btypes.ReconcilerStateCompleted)
+		pending := createChangeset(autoMergeBatchChange.ID, btypes.ChangesetCheckStatePending, btypes.ChangesetReviewStateApproved, btypes.ReconcilerStateCompleted)
+		unapproved := createChangeset(autoMergeBatchChange.ID, btypes.ChangesetCheckStatePassed, btypes.ChangesetReviewStatePending, btypes.ReconcilerStateCompleted)
+		failed := createChangeset(autoMergeBatchChange.ID, btypes.ChangesetCheckStatePassed, btypes.ChangesetReviewStateApproved, btypes.ReconcilerStateFailed)
+		manual := createChangeset(manualBatchChange.ID, btypes.ChangesetCheckStatePassed, btypes.ChangesetReviewStateApproved, btypes.ReconcilerStateCompleted)
+
+		// Changesets the code host refused to merge are only enqueued again
+		// once they have been updated on the code host since.
+		refuse := func(ch *btypes.Changeset, updatedAt time.Time) {
+			msg := "changeset cannot be merged"
+			ch.MergeFailureMessage = &msg
+			ch.LastMergeAttemptAt = clock.Now()
+			ch.ExternalUpdatedAt = updatedAt
+			require.NoError(t, s.UpdateChangeset(ctx, ch))
+		}
+		refused := createChangeset(autoMergeBatchChange.ID, btypes.ChangesetCheckStatePassed, btypes.ChangesetReviewStateApproved, btypes.ReconcilerStateCompleted)
+		refuse(refused, clock.Now().Add(-time.Minute))
+		refusedThenUpdated := createChangeset(autoMergeBatchChange.ID, btypes.ChangesetCheckStatePassed, btypes.ChangesetReviewStateApproved, btypes.ReconcilerStateCompleted)
+		refuse(refusedThenUpdated, clock.Now().Add(time.Minute))
+
+		// Changesets aren't merged before the changesets they depend on.
+		dependentSpec := bt.CreateBatchSpec(t, ctx, s, "auto-merge-dependencies", user.ID, 0)
+		dependentSpec.Spec.AutoMerge = &batcheslib.AutoMerge{Strategy: "squash"}
+		dependentSpec.Spec.Dependencies = []batcheslib.ChangesetDependency{
+			{Repository: string(repo.Name), DependsOn: []batcheslib.DependencyWorkspace{{Repository: string(otherRepo.Name)}}},
+		}
+		require.NoError(t, s.UpdateBatchSpec(ctx, dependentSpec))
+		dependentBatchChange := bt.CreateBatchChange(t, ctx, s, "auto-merge-dependencies", user.ID, dependentSpec.ID)
+		createDependencyChangeset := func(r *types.Repo, checkState btypes.ChangesetCheckState) *btypes.Changeset {
+			changesetSpec := bt.CreateChangesetSpec(t, ctx, s, bt.TestSpecOpts{
+				User:      user.ID,
+				Repo:      r.ID,
+				BatchSpec: dependentSpec.ID,
+				HeadRef:   "refs/heads/auto-merge-dependencies",
+				Typ:       btypes.ChangesetSpecTypeBranch,
+			})
+			return bt.CreateChangeset(t, ctx, s, bt.TestChangesetOpts{
+				Repo:                r.ID,
+				BatchChange:         dependentBatchChange.ID,
+				OwnedByBatchChange:  dependentBatchChange.ID,
+				CurrentSpec:         changesetSpec.ID,
+				PublicationState:    btypes.ChangesetPublicationStatePublished,
+				ExternalState:       btypes.ChangesetExternalStateOpen,
+				ExternalCheckState:  checkState,
+				ExternalReviewState: btypes.ChangesetReviewStateApproved,
+				ReconcilerState:     btypes.ReconcilerStateCompleted,
+			})
+		}
+		dependent := createDependencyChangeset(repo, btypes.ChangesetCheckStatePassed)
+		prerequisite := createDependencyChangeset(otherRepo, btypes.ChangesetCheckStatePending)
+
+		enqueued, err := s.EnqueueAutoMergeableChangesets(ctx, btypes.ReconcilerStateScheduled)
+		require.NoError(t, err)
+		assert.Equal(t, 2, enqueued)
+
+		for ch, want := range map[*btypes.Changeset]btypes.ReconcilerState{
+			ready:              btypes.ReconcilerStateScheduled,
+			pending:            btypes.ReconcilerStateCompleted,
+			unapproved:         btypes.ReconcilerStateCompleted,
+			failed:             btypes.ReconcilerStateFailed,
+			manual:             btypes.ReconcilerStateCompleted,
+			refused:            btypes.ReconcilerStateCompleted,
+			refusedThenUpdated: btypes.ReconcilerStateScheduled,
+			dependent:          btypes.ReconcilerStateCompleted,
+			prerequisite:       btypes.ReconcilerStateCompleted,
+		} {
+			have, err := s.GetChangesetByID(ctx, ch.ID)
+			require.NoError(t, err)
+			assert.Equal(t, want, have.ReconcilerState, "changeset %d", ch.ID)
+		}
+	})
+
+	t.Run("ChangesetDependencies", func(t *testing.T) {
+		spec := bt.CreateBatchSpec(t, ctx, s, "dependencies", user.ID, 0)
+		spec.Spec.Dependencies = []batcheslib.ChangesetDependency{
+			{Repository: string(repo.Name), DependsOn: []batcheslib.DependencyWorkspace{
+				{Repository: string(otherRepo.Name), Branch: "dependencies/shared"},
+				{Repository: string(gitlabRepo.Name)},
+			}},
+		}
+		require.NoError(t, s.UpdateBatchSpec(ctx, spec))
+		batchChange := bt.CreateBatchChange(t, ctx, s, "dependencies", user.ID, spec.ID)
+
+		createChangeset := func(r *types.Repo, headRef string, externalState btypes.ChangesetExternalState) *btypes.Changeset {
+			changesetSpec := bt.CreateChangesetSpec(t, ctx, s, bt.TestSpecOpts{
+				User:      user.ID,
+				Repo:      r.ID,
+				BatchSpec: spec.ID,
+				HeadRef:   headRef,
+				Typ:       btypes.ChangesetSpecTypeBranch,
+			})
+			return bt.CreateChangeset(t, ctx, s, bt.TestChangesetOpts{
+				Repo:               r.ID,
+				BatchChange:        batchChange.ID,
+				OwnedByBatchChange: batchChange.ID,
+				CurrentSpec:        changesetSpec.ID,
+				PublicationState:   btypes.ChangesetPublicationStatePublished,
+				ExternalState:      externalState,
+				ReconcilerState:    btypes.ReconcilerStateCompleted,
+			})
+		}
+
+		dependent := createChangeset(repo, "refs/heads/dependencies", "")
+		dependent.PublicationState = btypes.ChangesetPublicationStateUnpublished
+		dependent.BlockedByDependencies = true
+		require.NoError(t, s.UpdateChangeset(ctx, dependent))
+		merged := createChangeset(otherRepo, "refs/heads/dependencies/shared", btypes.ChangesetExternalStateMerged)
+		// Only the changeset of the workspace with the given branch is
+		// depended on.
+		createChangeset(otherRepo, "refs/heads/dependencies/web", btypes.ChangesetExternalStateOpen)
+		open := createChangeset(gitlabRepo, "refs/heads/dependencies", btypes.ChangesetExternalStateOpen)
+
+		t.Run("ListChangesetDependencies", func(t *testing.T) {
+			want := []*btypes.ChangesetDependency{
+				{ChangesetID: dependent.ID, DependsOnChangesetID: merged.ID, Satisfied: true},
+				{ChangesetID: dependent.ID, DependsOnChangesetID: open.ID, Satisfied: false},
+			}
+
+			for name, opts := range map[string]ListChangesetDependenciesOpts{
+				"by batch change": {BatchChangeID: batchChange.ID},
+				"by changeset":    {ChangesetID: dependent.ID},
+			} {
+				have, err := s.ListChangesetDependencies(ctx, opts)
+				require.NoError(t, err)
+				assert.Equal(t, want, have, name)
+			}
+
+			have, err := s.ListChangesetDependencies(ctx, ListChangesetDependenciesOpts{ChangesetID: merged.ID})
+			require.NoError(t, err)
+			assert.Empty(t, have)
+		})
+
+		t.Run("EnqueueUnblockedChangesets", func(t *testing.T) {
+			enqueued, err := s.EnqueueUnblockedChangesets(ctx, btypes.ReconcilerStateScheduled)
+			require.NoError(t, err)
+			assert.Equal(t, 0, enqueued)
+
+			open.ExternalState = btypes.ChangesetExternalStateMerged
+			require.NoError(t, s.UpdateChangeset(ctx, open))
+
+			enqueued, err = s.EnqueueUnblockedChangesets(ctx, btypes.ReconcilerStateScheduled)
+			require.NoError(t, err)
+			assert.Equal(t, 1, enqueued)
+
+			have, err := s.GetChangesetByID(ctx, dependent.ID)
+			require.NoError(t, err)
+			assert.Equal(t, btypes.ReconcilerStateScheduled, have.ReconcilerState)
+		})
+	})
+
 	t.Run("UpdateChangesetBatchChanges", func(t *testing.T) {
 		c1 := bt.CreateChangeset(t, ctx, s, bt.TestChangesetOpts{
 			ReconcilerState:  btypes.ReconcilerStateCompleted,
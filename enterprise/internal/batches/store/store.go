@@ -241,6 +241,9 @@ type operations struct {
 	getChangesetExternalIDs           *observation.Operation
 	cancelQueuedBatchChangeChangesets *observation.Operation
 	enqueueChangesetsToClose          *observation.Operation
+	enqueueAutoMergeableChangesets    *observation.Operation
+	listChangesetDependencies         *observation.Operation
+	enqueueUnblockedChangesets        *observation.Operation
 	getChangesetsStats                *observation.Operation
 	getRepoChangesetsStats            *observation.Operation
 	getGlobalChangesetsStats          *observation.Operation
@@ -385,6 +388,9 @@ func newOperations(observationCtx *observation.Context) *operations {
 			getChangesetExternalIDs:           op("GetChangesetExternalIDs"),
 			cancelQueuedBatchChangeChangesets: op("CancelQueuedBatchChangeChangesets"),
 			enqueueChangesetsToClose:          op("EnqueueChangesetsToClose"),
+			enqueueAutoMergeableChangesets:    op("EnqueueAutoMergeableChangesets"),
+			listChangesetDependencies:         op("ListChangesetDependencies"),
+			enqueueUnblockedChangesets:        op("EnqueueUnblockedChangesets"),
 			getChangesetsStats:                op("GetChangesetsStats"),
 			getRepoChangesetsStats:            op("GetRepoChangesetsStats"),
 			getGlobalChangesetsStats:          op("GetGlobalChangesetsStats"),
@@ -0,0 +1,20 @@
+package store
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// NewDeadLetterStores creates dead letter stores for the failed records of
+// all dbworker stores used by Batch Changes. Their records are changesets or
+// jobs that the state of changesets, bulk operations and batch specs is derived
+// from, so they can only be requeued, not deleted.
+func NewDeadLetterStores(observationCtx *observation.Context, handle basestore.TransactableHandle) []dbworkerstore.DeadLetterStore {
+	return []dbworkerstore.DeadLetterStore{
+		dbworkerstore.NewDeadLetterStore(observationCtx, handle, reconcilerWorkerStoreOpts, dbworkerstore.DeadLetterOptions{}),
+		dbworkerstore.NewDeadLetterStore(observationCtx, handle, bulkOperationWorkerStoreOpts, dbworkerstore.DeadLetterOptions{}),
+		dbworkerstore.NewDeadLetterStore(observationCtx, handle, batchSpecResolutionWorkerOpts, dbworkerstore.DeadLetterOptions{}),
+		dbworkerstore.NewDeadLetterStore(observationCtx, handle, batchSpecWorkspaceExecutionWorkerStoreOptions, dbworkerstore.DeadLetterOptions{}),
+	}
+}
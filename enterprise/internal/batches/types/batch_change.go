@@ -31,6 +31,13 @@ type BatchChange struct {
 	LastApplierID int32
 	LastAppliedAt time.Time
 
+	// LastScheduledAt is the time the last scheduled run of the batch change
+	// was started, and ScheduledBatchSpecID is the batch spec of the run that
+	// is currently in progress, if any. Both are only set for batch changes
+	// whose batch spec has a schedule.
+	LastScheduledAt      time.Time
+	ScheduledBatchSpecID int64
+
 	NamespaceUserID int32
 	NamespaceOrgID  int32
 
@@ -235,6 +235,30 @@ func (s ChangesetCheckState) Valid() bool {
 	}
 }
 
+// ChangesetMergeStrategy defines how a Changeset is merged on its code host.
+type ChangesetMergeStrategy string
+
+// ChangesetMergeStrategy constants. ChangesetMergeStrategyDefault merges with
+// the default merge strategy of the repository on the code host.
+const (
+	ChangesetMergeStrategyDefault ChangesetMergeStrategy = ""
+	ChangesetMergeStrategyMerge   ChangesetMergeStrategy = "merge"
+	ChangesetMergeStrategySquash  ChangesetMergeStrategy = "squash"
+	ChangesetMergeStrategyRebase  ChangesetMergeStrategy = "rebase"
+)
+
+// Valid returns true if the given Changeset merge strategy is valid.
+func (s ChangesetMergeStrategy) Valid() bool {
+	switch s {
+	case ChangesetMergeStrategyMerge,
+		ChangesetMergeStrategySquash,
+		ChangesetMergeStrategyRebase:
+		return true
+	default:
+		return false
+	}
+}
+
 // BatchChangeAssoc stores the details of a association to a BatchChange.
 type BatchChangeAssoc struct {
 	BatchChangeID int64 `json:"-"`
@@ -297,6 +321,21 @@ type Changeset struct {
 
 	// DetachedAt is the time when the changeset became "detached".
 	DetachedAt time.Time
+
+	// RequestedReviewers are the users and teams that review was requested
+	// from on the code host after the changeset was published.
+	RequestedReviewers []string
+
+	// BlockedByDependencies is set when the changeset is held back as a draft
+	// or unpublished until the changesets it depends on have been merged.
+	BlockedByDependencies bool
+
+	// LastMergeAttemptAt is the time the changeset was last merged
+	// automatically, whether or not the code host merged it.
+	LastMergeAttemptAt time.Time
+	// MergeFailureMessage is set when the code host refused to merge the
+	// changeset automatically. It's cleared by ResetReconcilerState.
+	MergeFailureMessage *string
 }
 
 // RecordID is needed to implement the workerutil.Record interface.
@@ -307,6 +346,10 @@ func (c *Changeset) Clone() *Changeset {
 	tt := *c
 	tt.BatchChanges = make([]BatchChangeAssoc, len(c.BatchChanges))
 	copy(tt.BatchChanges, c.BatchChanges)
+	if c.RequestedReviewers != nil {
+		tt.RequestedReviewers = make([]string, len(c.RequestedReviewers))
+		copy(tt.RequestedReviewers, c.RequestedReviewers)
+	}
 	return &tt
 }
 
@@ -532,6 +575,21 @@ func (c *Changeset) Body() (string, error) {
 	}
 }
 
+// Conflicting returns true if the code host reported that the Changeset can't
+// be merged, for example because of merge conflicts. Code hosts that don't
+// report it, or that haven't determined it yet, are never reported as
+// conflicting.
+func (c *Changeset) Conflicting() bool {
+	switch m := c.Metadata.(type) {
+	case *github.PullRequest:
+		return m.Mergeable == github.PullRequestMergeableStateConflicting
+	case *gitlab.MergeRequest:
+		return m.MergeStatus == gitlab.MergeStatusCannotBeMerged
+	default:
+		return false
+	}
+}
+
 // SetDeleted sets the internal state of a Changeset so that its State is
 // ChangesetStateDeleted.
 func (c *Changeset) SetDeleted() {
@@ -935,6 +993,9 @@ func (c *Changeset) ResetReconcilerState(state ReconcilerState) {
 	c.NumResets = 0
 	c.NumFailures = 0
 	c.FailureMessage = nil
+	// Merging the changeset automatically is retried once it has been
+	// reconciled again.
+	c.MergeFailureMessage = nil
 	// The reconciler syncs where needed, so we reset this message.
 	c.SyncErrorMessage = nil
 }
@@ -1252,3 +1313,13 @@ func NewChangesetEventMetadata(k ChangesetEventKind) (any, error) {
 	}
 	return nil, errors.Errorf("unknown changeset event kind %q", k)
 }
+
+// ChangesetDependency represents a dependency of a changeset on another
+// changeset in the same batch change, as declared by the dependencies of the
+// batch spec between the repositories of both changesets.
+type ChangesetDependency struct {
+	ChangesetID          int64
+	DependsOnChangesetID int64
+	// Satisfied is true when the changeset depended on has been merged.
+	Satisfied bool
+}
@@ -35,6 +35,7 @@ func NewChangesetSpecFromSpec(spec *batcheslib.ChangesetSpec) (*ChangesetSpec, e
 		Title:      spec.Title,
 		Body:       spec.Body,
 		Published:  spec.Published,
+		Reviewers:  spec.Reviewers,
 	}
 
 	if spec.IsImportingExisting() {
@@ -113,12 +114,20 @@ type ChangesetSpec struct {
 	CommitAuthorName  string
 	CommitAuthorEmail string
 
+	// Reviewers configures whom review is requested from once the changeset
+	// has been published. It is nil if no reviewers are configured.
+	Reviewers *batcheslib.ChangesetReviewers
+
 	ForkNamespace *string
 }
 
 // Clone returns a clone of a ChangesetSpec.
 func (cs *ChangesetSpec) Clone() *ChangesetSpec {
 	cc := *cs
+	if cs.Reviewers != nil {
+		reviewers := *cs.Reviewers
+		cc.Reviewers = &reviewers
+	}
 	return &cc
 }
 
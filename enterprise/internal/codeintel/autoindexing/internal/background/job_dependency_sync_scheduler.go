@@ -70,6 +70,8 @@ var schemeToExternalService = map[string]string{
 	dependencies.PythonPackagesScheme: extsvc.KindPythonPackages,
 	dependencies.RustPackagesScheme:   extsvc.KindRustPackages,
 	dependencies.RubyPackagesScheme:   extsvc.KindRubyPackages,
+	dependencies.DotnetPackagesScheme: extsvc.KindDotnetPackages,
+	dependencies.PHPPackagesScheme:    extsvc.KindPHPPackages,
 }
 
 func (h *dependencySyncSchedulerHandler) Handle(ctx context.Context, logger log.Logger, job shared.DependencySyncingJob) error {
@@ -253,6 +255,8 @@ func (h *dependencySyncSchedulerHandler) shouldIndexDependencies(ctx context.Con
 		upload.Indexer == "lsif-typescript" ||
 		upload.Indexer == "scip-python" ||
 		upload.Indexer == "scip-ruby" ||
+		upload.Indexer == "scip-dotnet" ||
+		upload.Indexer == "scip-php" ||
 		upload.Indexer == "rust-analyzer", nil
 }
 
@@ -20,6 +20,8 @@ func InferRepositoryAndRevision(pkg precise.Package) (repoName api.RepoName, git
 		inferRustRepositoryAndRevision,
 		inferPythonRepositoryAndRevision,
 		inferRubyRepositoryAndRevision,
+		inferDotnetRepositoryAndRevision,
+		inferPHPRepositoryAndRevision,
 	} {
 		if repoName, gitTagOrCommit, ok := fn(pkg); ok {
 			return repoName, gitTagOrCommit, true
@@ -116,3 +118,35 @@ func inferRubyRepositoryAndRevision(pkg precise.Package) (api.RepoName, string,
 
 	return rubyPkg.RepoName(), pkg.Version, true
 }
+
+func inferDotnetRepositoryAndRevision(pkg precise.Package) (api.RepoName, string, bool) {
+	if pkg.Scheme != dependencies.DotnetPackagesScheme {
+		return "", "", false
+	}
+
+	logger := log.Scoped("inferDotnetRepositoryAndRevision", "")
+	dotnetPkg, err := reposource.ParseDotnetPackageFromName(reposource.PackageName(pkg.Name))
+	if err != nil {
+		logger.Error("invalid dotnet package name in database", log.Error(err), log.String("pkg", pkg.Name))
+		return "", "", false
+	}
+
+	dotnetPkg.Version = strings.ToLower(pkg.Version)
+	return dotnetPkg.RepoName(), dotnetPkg.GitTagFromVersion(), true
+}
+
+func inferPHPRepositoryAndRevision(pkg precise.Package) (api.RepoName, string, bool) {
+	if pkg.Scheme != dependencies.PHPPackagesScheme {
+		return "", "", false
+	}
+
+	logger := log.Scoped("inferPHPRepositoryAndRevision", "")
+	phpPkg, err := reposource.ParsePHPPackageFromName(reposource.PackageName(pkg.Name))
+	if err != nil {
+		logger.Error("invalid php package name in database", log.Error(err), log.String("pkg", pkg.Name))
+		return "", "", false
+	}
+
+	phpPkg.Version = pkg.Version
+	return phpPkg.RepoName(), phpPkg.GitTagFromVersion(), true
+}
@@ -58,6 +58,24 @@ func TestInferRepositoryAndRevision(t *testing.T) {
 				repoName: "npm/myscope/mypackage",
 				revision: "v1.0.0",
 			},
+			{
+				pkg: precise.Package{
+					Scheme:  "scip-dotnet",
+					Name:    "Newtonsoft.Json",
+					Version: "13.0.1",
+				},
+				repoName: "nuget/newtonsoft.json",
+				revision: "v13.0.1",
+			},
+			{
+				pkg: precise.Package{
+					Scheme:  "scip-php",
+					Name:    "monolog/monolog",
+					Version: "v3.2.0",
+				},
+				repoName: "packagist/monolog/monolog",
+				revision: "v3.2.0",
+			},
 		}
 
 		for _, testCase := range testCases {
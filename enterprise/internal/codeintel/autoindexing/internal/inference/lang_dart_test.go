@@ -0,0 +1,70 @@
+package inference
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindexing/internal/inference/libs"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestDartGenerator(t *testing.T) {
+	// There is no pinned default image, so none is used unless one is configured.
+	if _, ok := libs.DefaultIndexerForLang("dart"); ok {
+		t.Fatal("want no default indexer")
+	}
+	testGenerators(t,
+		generatorTestCase{
+			description:        "no configured indexer",
+			repositoryContents: map[string]string{"pubspec.yaml": ""},
+			expected:           []config.IndexJob{},
+		},
+	)
+
+	expectedIndexerImage := "workiva/scip-dart@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	mockIndexerMap(t, map[string]string{"dart": expectedIndexerImage})
+
+	newJob := func(root, installCommand string) config.IndexJob {
+		return config.IndexJob{
+			Steps: []config.DockerStep{
+				{
+					Root:     root,
+					Image:    expectedIndexerImage,
+					Commands: []string{installCommand},
+				},
+			},
+			LocalSteps:  nil,
+			Root:        root,
+			Indexer:     expectedIndexerImage,
+			IndexerArgs: []string{"scip-dart", "./"},
+			Outfile:     "index.scip",
+		}
+	}
+
+	testGenerators(t,
+		generatorTestCase{
+			description: "dart and flutter packages",
+			repositoryContents: map[string]string{
+				"pubspec.yaml": `
+name: core
+environment:
+  sdk: '>=2.18.0 <3.0.0'
+`,
+				"app/pubspec.yaml": `
+name: app
+dependencies:
+  flutter:
+    sdk: flutter
+  core:
+    path: ../
+`,
+				"app/build/pubspec.yaml":      "",
+				"example/pubspec.yaml":        "",
+				".dart_tool/pub/pubspec.yaml": "",
+			},
+			expected: []config.IndexJob{
+				newJob("", "dart pub get"),
+				newJob("app", "flutter pub get"),
+			},
+		},
+	)
+}
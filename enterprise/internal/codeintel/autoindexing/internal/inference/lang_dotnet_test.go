@@ -0,0 +1,84 @@
+package inference
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindexing/internal/inference/libs"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestDotnetGenerator(t *testing.T) {
+	// There is no pinned default image, so none is used unless one is configured.
+	if _, ok := libs.DefaultIndexerForLang("dotnet"); ok {
+		t.Fatal("want no default indexer")
+	}
+	testGenerators(t,
+		generatorTestCase{
+			description:        "no configured indexer",
+			repositoryContents: map[string]string{"App.sln": ""},
+			expected:           []config.IndexJob{},
+		},
+	)
+
+	expectedIndexerImage := "sourcegraph/scip-dotnet@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	mockIndexerMap(t, map[string]string{"dotnet": expectedIndexerImage})
+
+	newJob := func(root, project string) config.IndexJob {
+		return config.IndexJob{
+			Steps: []config.DockerStep{
+				{
+					Root:     root,
+					Image:    expectedIndexerImage,
+					Commands: []string{"dotnet restore " + project},
+				},
+			},
+			LocalSteps:  nil,
+			Root:        root,
+			Indexer:     expectedIndexerImage,
+			IndexerArgs: []string{"scip-dotnet", "index", project},
+			Outfile:     "index.scip",
+		}
+	}
+
+	testGenerators(t,
+		generatorTestCase{
+			description: "solution",
+			repositoryContents: map[string]string{
+				"App.sln":            "",
+				"src/App/App.csproj": "",
+				"src/Lib/Lib.csproj": "",
+			},
+			expected: []config.IndexJob{
+				newJob("", "App.sln"),
+			},
+		},
+		generatorTestCase{
+			description: "projects without a solution",
+			repositoryContents: map[string]string{
+				"src/App/App.csproj":        "",
+				"src/Lib/Lib.csproj":        "",
+				"src/Lib/obj/Lib.csproj":    "",
+				"tests/App.Tests/T.csproj":  "",
+				"tools/Build/Build.csproj":  "",
+				"tools/Build/Build2.csproj": "",
+			},
+			expected: []config.IndexJob{
+				newJob("src/App", "App.csproj"),
+				newJob("src/Lib", "Lib.csproj"),
+				newJob("tools/Build", "Build.csproj"),
+			},
+		},
+		generatorTestCase{
+			description: "projects outside of a solution",
+			repositoryContents: map[string]string{
+				"server/Server.sln":               "",
+				"server/src/Server/Server.csproj": "",
+				"client/Client.csproj":            "",
+			},
+			expected: []config.IndexJob{
+				newJob("client", "Client.csproj"),
+				newJob("server", "Server.sln"),
+			},
+		},
+	)
+}
@@ -0,0 +1,58 @@
+package inference
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindexing/internal/inference/libs"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestPHPGenerator(t *testing.T) {
+	// There is no pinned default image, so none is used unless one is configured.
+	if _, ok := libs.DefaultIndexerForLang("php"); ok {
+		t.Fatal("want no default indexer")
+	}
+	testGenerators(t,
+		generatorTestCase{
+			description:        "no configured indexer",
+			repositoryContents: map[string]string{"composer.json": ""},
+			expected:           []config.IndexJob{},
+		},
+	)
+
+	expectedIndexerImage := "davidrjenni/scip-php@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	mockIndexerMap(t, map[string]string{"php": expectedIndexerImage})
+
+	testGenerators(t,
+		generatorTestCase{
+			description: "composer projects",
+			repositoryContents: map[string]string{
+				"composer.json":                        "",
+				"composer.lock":                        "",
+				"packages/a/composer.json":             "",
+				"vendor/monolog/monolog/composer.json": "",
+				"tests/fixtures/composer.json":         "",
+			},
+			expected: func() []config.IndexJob {
+				var out []config.IndexJob
+				for _, root := range []string{"", "packages/a"} {
+					out = append(out, config.IndexJob{
+						Steps: []config.DockerStep{
+							{
+								Root:     root,
+								Image:    expectedIndexerImage,
+								Commands: []string{"composer install --no-interaction --no-progress --ignore-platform-reqs"},
+							},
+						},
+						LocalSteps:  nil,
+						Root:        root,
+						Indexer:     expectedIndexerImage,
+						IndexerArgs: []string{"scip-php"},
+						Outfile:     "index.scip",
+					})
+				}
+				return out
+			}(),
+		},
+	)
+}
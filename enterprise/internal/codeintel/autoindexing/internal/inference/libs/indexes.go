@@ -17,15 +17,20 @@ type indexesAPI struct{}
 
 var defaultIndexers = map[string]string{
 	"clang":      "sourcegraph/lsif-clang",
+	"dart":       "workiva/scip-dart",
+	"dotnet":     "sourcegraph/scip-dotnet",
 	"go":         "sourcegraph/lsif-go",
 	"java":       "sourcegraph/scip-java",
+	"php":        "davidrjenni/scip-php",
 	"python":     "sourcegraph/scip-python",
 	"rust":       "sourcegraph/scip-rust",
 	"typescript": "sourcegraph/scip-typescript",
 	"ruby":       "sourcegraph/scip-ruby",
 }
 
-// To update, run `DOCKER_USER=... DOCKER_PASS=... ./update-shas.sh`
+// To update, run `DOCKER_USER=... DOCKER_PASS=... ./update-shas.sh`. Indexers with an
+// empty SHA have not been pinned yet and have no default image: jobs are only inferred
+// for them once an image is set in the codeIntelAutoIndexing.indexerMap site setting.
 var defaultIndexerSHAs = map[string]string{
 	"sourcegraph/lsif-clang":      "sha256:99ca372c61b7cc5e32d5aedf87a7eb93a23443bd46d1e937dfa21b9ba2c6acd6",
 	"sourcegraph/lsif-go":         "sha256:cba76f5b3edb5d9af43e1dc59e27ecdb4b8b2fafda6a5d55d7e37def3b502775",
@@ -35,8 +40,14 @@ var defaultIndexerSHAs = map[string]string{
 	"sourcegraph/scip-python":     "sha256:5049c4598d03af542bde5e1254a17fa6d1eb794c1bdd14d0162fb39c604581b4",
 	"sourcegraph/scip-typescript": "sha256:37546e04763d6d1853fb6f32285ad630fc0d8671d4f1d2db40c6df272120f2f8",
 	"sourcegraph/scip-ruby":       "sha256:1e7538eead787a9a220e54c442eaf10372f3f41d2be2871713e6ec367bd40f81",
+	"sourcegraph/scip-dotnet":     "",
+	"davidrjenni/scip-php":        "",
+	"workiva/scip-dart":           "",
 }
 
+// DefaultIndexerForLang returns the pinned image of the default indexer for the given
+// language. It returns false if there is no default indexer for the language, or if the
+// default indexer has not been pinned yet.
 func DefaultIndexerForLang(language string) (string, bool) {
 	indexer, ok := defaultIndexers[language]
 	if !ok {
@@ -47,26 +58,43 @@ func DefaultIndexerForLang(language string) (string, bool) {
 	if !ok {
 		panic(fmt.Sprintf("no SHA set for indexer %q", indexer))
 	}
+	if sha == "" {
+		// 🚨 SECURITY: Never fall back to a mutable tag, whoever can push it would
+		// control what runs on the executors.
+		return "", false
+	}
 
 	return fmt.Sprintf("%s@%s", indexer, sha), true
 }
 
+func indexerForLang(language string) (string, bool) {
+	if indexer, ok := conf.SiteConfig().CodeIntelAutoIndexingIndexerMap[language]; ok {
+		return indexer, true
+	}
+	return DefaultIndexerForLang(language)
+}
+
 func (api indexesAPI) LuaAPI() map[string]lua.LGFunction {
 	return map[string]lua.LGFunction{
 		"get": util.WrapLuaFunction(func(state *lua.LState) error {
 			language := state.CheckString(1)
 
-			if indexer, ok := conf.SiteConfig().CodeIntelAutoIndexingIndexerMap[language]; ok {
+			if indexer, ok := indexerForLang(language); ok {
 				state.Push(luar.New(state, indexer))
 				return nil
 			}
 
-			if indexer, ok := DefaultIndexerForLang(language); ok {
+			return errors.Newf("no indexer is registered for %q", language)
+		}),
+		// find is like get, but returns nil instead of failing when there is no
+		// indexer for the language.
+		"find": util.WrapLuaFunction(func(state *lua.LState) error {
+			if indexer, ok := indexerForLang(state.CheckString(1)); ok {
 				state.Push(luar.New(state, indexer))
-				return nil
+			} else {
+				state.Push(lua.LNil)
 			}
-
-			return errors.Newf("no indexer is registered for %q", language)
+			return nil
 		}),
 	}
 }
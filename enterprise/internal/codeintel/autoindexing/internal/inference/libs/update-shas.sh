@@ -5,19 +5,20 @@ set -u
 DOCKER_USER=${DOCKER_USER:?"No DOCKER_USER is set."}
 DOCKER_PASS=${DOCKER_PASS:?"No DOCKER_PASS is set."}
 
-for indexer in lsif-clang lsif-go lsif-rust scip-rust scip-java scip-python scip-typescript scip-ruby; do
+for image in sourcegraph/lsif-clang sourcegraph/lsif-go sourcegraph/lsif-rust sourcegraph/scip-rust sourcegraph/scip-java sourcegraph/scip-python sourcegraph/scip-typescript sourcegraph/scip-ruby sourcegraph/scip-dotnet davidrjenni/scip-php workiva/scip-dart; do
+  indexer=${image#*/}
   tag="latest"
   if [[ "${indexer}" = "scip-python" ]] || [[ "${indexer}" = "scip-typescript" || "${indexer}" = "scip-ruby" ]]; then
     tag="autoindex"
   fi
 
-  sha=$(docker buildx imagetools inspect sourcegraph/${indexer}:${tag} --raw | sha256sum | awk '{print "\"" "sha256:" $1 "\""}')
+  sha=$(docker buildx imagetools inspect ${image}:${tag} --raw | sha256sum | awk '{print "\"" "sha256:" $1 "\""}')
 
   sed -i.bak \
-    "s|\("'"'"sourcegraph/${indexer}"'"'":\).*|\1${sha},|g" \
+    "s|\("'"'"${image}"'"'":\).*|\1${sha},|g" \
     indexes.go
 
-  echo "Updated tag for ${indexer}"
+  echo "Updated tag for ${image}"
   rm indexes.go.bak
 done
 
@@ -0,0 +1,70 @@
+local path = require "path"
+local pattern = require "sg.autoindex.patterns"
+local recognizer = require "sg.autoindex.recognizer"
+
+local shared = require "sg.autoindex.shared"
+
+-- scip-dart has no pinned default image yet, so Dart jobs are only inferred once an
+-- image is set in the codeIntelAutoIndexing.indexerMap site setting.
+local indexer = require("sg.autoindex.indexes").find "dart"
+local outfile = "index.scip"
+
+local exclude_paths = pattern.new_path_combine(shared.exclude_paths, {
+  pattern.new_path_segment ".dart_tool",
+  pattern.new_path_segment ".pub-cache",
+  pattern.new_path_segment "build",
+})
+
+--- Flutter packages depend on the Flutter SDK:
+---
+--- dependencies:
+---   flutter:
+---     sdk: flutter
+local is_flutter_package = function(content)
+  return content ~= nil and string.find(content, "sdk:%s*flutter") ~= nil
+end
+
+return recognizer.new_path_recognizer {
+  patterns = {
+    pattern.new_path_basename "pubspec.yaml",
+    pattern.new_path_exclude(exclude_paths),
+  },
+
+  patterns_for_content = {
+    pattern.new_path_basename "pubspec.yaml",
+    pattern.new_path_exclude(exclude_paths),
+  },
+
+  -- Invoked when pubspec.yaml files exist
+  generate = function(_, paths, contents_by_path)
+    if not indexer then
+      return {}
+    end
+
+    local jobs = {}
+    for i = 1, #paths do
+      local root = path.dirname(paths[i])
+
+      local install_command = "dart pub get"
+      if is_flutter_package(contents_by_path[paths[i]]) then
+        install_command = "flutter pub get"
+      end
+
+      table.insert(jobs, {
+        steps = {
+          {
+            root = root,
+            image = indexer,
+            commands = { install_command },
+          },
+        },
+        root = root,
+        indexer = indexer,
+        indexer_args = { "scip-dart", "./" },
+        outfile = outfile,
+      })
+    end
+
+    return jobs
+  end,
+}
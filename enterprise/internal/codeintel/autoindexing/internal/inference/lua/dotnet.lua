@@ -0,0 +1,86 @@
+local path = require "path"
+local pattern = require "sg.autoindex.patterns"
+local recognizer = require "sg.autoindex.recognizer"
+
+local shared = require "sg.autoindex.shared"
+
+-- scip-dotnet has no pinned default image yet, so C# jobs are only inferred once an
+-- image is set in the codeIntelAutoIndexing.indexerMap site setting.
+local indexer = require("sg.autoindex.indexes").find "dotnet"
+local outfile = "index.scip"
+
+local exclude_paths = pattern.new_path_combine(shared.exclude_paths, {
+  pattern.new_path_segment "bin",
+  pattern.new_path_segment "obj",
+})
+
+local is_solution = function(filepath)
+  return string.sub(filepath, -4) == ".sln"
+end
+
+local make_job = function(root, project)
+  return {
+    steps = {
+      {
+        root = root,
+        image = indexer,
+        commands = { "dotnet restore " .. project },
+      },
+    },
+    root = root,
+    indexer = indexer,
+    indexer_args = { "scip-dotnet", "index", project },
+    outfile = outfile,
+  }
+end
+
+return recognizer.new_path_recognizer {
+  patterns = {
+    pattern.new_path_extension "sln",
+    pattern.new_path_extension "csproj",
+    pattern.new_path_exclude(exclude_paths),
+  },
+
+  -- Invoked when solution or C# project files exist. A solution builds the
+  -- projects beneath it, so we index each directory containing a solution
+  -- and only those projects that are not beneath one.
+  generate = function(_, paths)
+    if not indexer then
+      return {}
+    end
+
+    table.sort(paths)
+
+    -- The first file (in path order) of each directory
+    local solutions, projects = {}, {}
+    for i = 1, #paths do
+      local root = path.dirname(paths[i])
+      local files = is_solution(paths[i]) and solutions or projects
+      if not files[root] then
+        files[root] = path.basename(paths[i])
+      end
+    end
+
+    local jobs = {}
+    for root, solution in pairs(solutions) do
+      table.insert(jobs, make_job(root, solution))
+    end
+
+    for root, project in pairs(projects) do
+      local within_solution = false
+      local ancestors = path.ancestors(path.join(root, project))
+      for i = 1, #ancestors do
+        if solutions[ancestors[i]] then
+          within_solution = true
+          break
+        end
+      end
+
+      if not within_solution then
+        table.insert(jobs, make_job(root, project))
+      end
+    end
+
+    return jobs
+  end,
+}
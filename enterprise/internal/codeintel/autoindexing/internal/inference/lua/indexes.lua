@@ -2,4 +2,5 @@ local indexes = require "internal_indexes"
 
 return {
   get = indexes.get,
+  find = indexes.find,
 }
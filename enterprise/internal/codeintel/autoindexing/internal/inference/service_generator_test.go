@@ -8,7 +8,9 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestEmptyGenerators(t *testing.T) {
@@ -132,6 +134,13 @@ type generatorTestCase struct {
 	expected           []config.IndexJob
 }
 
+// mockIndexerMap sets the codeIntelAutoIndexing.indexerMap site setting for the duration
+// of the test.
+func mockIndexerMap(t *testing.T, indexers map[string]string) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{CodeIntelAutoIndexingIndexerMap: indexers}})
+	t.Cleanup(func() { conf.Mock(nil) })
+}
+
 func testGenerators(t *testing.T, testCases ...generatorTestCase) {
 	for _, testCase := range testCases {
 		testGenerator(t, testCase)
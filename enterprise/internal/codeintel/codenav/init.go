@@ -6,6 +6,7 @@ import (
 	codeintelshared "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/symbols"
 )
 
 func NewService(
@@ -17,6 +18,7 @@ func NewService(
 ) *Service {
 	store := store.New(scopedContext("store", observationCtx), db)
 	lsifStore := lsifstore.New(scopedContext("lsifstore", observationCtx), codeIntelDB)
+	symbolsClient := symbols.DefaultClient
 
 	return newService(
 		observationCtx,
@@ -24,6 +26,7 @@ func NewService(
 		lsifStore,
 		uploadSvc,
 		gitserver,
+		symbolsClient,
 	)
 }
 
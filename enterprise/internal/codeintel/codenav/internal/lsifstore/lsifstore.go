@@ -24,6 +24,19 @@ type LsifStore interface {
 	// Definition
 	GetDefinitionLocations(ctx context.Context, uploadID int, path string, line, character, limit, offset int) (_ []shared.Location, _ int, err error)
 
+	// Type definition
+	GetTypeDefinitionLocations(ctx context.Context, uploadID int, path string, line, character, limit, offset int) (_ []shared.Location, _ int, err error)
+
+	// Call hierarchy
+	GetCallers(ctx context.Context, bundleID int, path string, ranges []types.Range) (_ []shared.CallHierarchyCall, err error)
+	GetCallees(ctx context.Context, bundleID int, path string, line, character int) (_ []shared.CallHierarchyCall, err error)
+
+	// Document symbols
+	GetDocumentSymbols(ctx context.Context, bundleID int, path string) (_ []shared.DocumentSymbol, _ bool, err error)
+
+	// Exported symbols
+	GetExportedSymbols(ctx context.Context, uploadID int) (_ []shared.ExportedSymbol, _ bool, err error)
+
 	// Monikers
 	GetMonikersByPosition(ctx context.Context, uploadID int, path string, line, character int) (_ [][]precise.MonikerData, err error)
 	GetBulkMonikerLocations(ctx context.Context, tableName string, uploadIDs []int, monikers []precise.MonikerData, limit, offset int) (_ []shared.Location, totalCount int, err error)
@@ -0,0 +1,288 @@
+package lsifstore
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// GetCallers groups the given ranges of references within a document by the callable symbols whose
+// definitions enclose them, and returns a call from each of these callers. References outside of a
+// callable and references that are definitions themselves are omitted. Only SCIP indexes support
+// call hierarchies.
+func (s *store) GetCallers(ctx context.Context, bundleID int, path string, ranges []types.Range) (_ []shared.CallHierarchyCall, err error) {
+	ctx, trace, endObservation := s.operations.getCallers.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("bundleID", bundleID),
+		log.String("path", path),
+		log.Int("numRanges", len(ranges)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	documentData, exists, err := s.scanFirstDocumentData(s.db.Query(ctx, sqlf.Sprintf(
+		locationsDocumentQuery,
+		bundleID,
+		path,
+		bundleID,
+		path,
+	)))
+	if err != nil || !exists || documentData.SCIPData == nil {
+		return nil, err
+	}
+
+	calls := callersOfRanges(documentData.SCIPData, ranges, bundleID, path)
+	trace.AddEvent("callersOfRanges", attribute.Int("numCalls", len(calls)))
+
+	return calls, nil
+}
+
+// GetCallees returns a call to each callable symbol referenced by the callable symbol defined at the
+// given position. Callees defined within the same index have their definition set. Only SCIP indexes
+// support call hierarchies.
+func (s *store) GetCallees(ctx context.Context, bundleID int, path string, line, character int) (_ []shared.CallHierarchyCall, err error) {
+	ctx, trace, endObservation := s.operations.getCallees.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("bundleID", bundleID),
+		log.String("path", path),
+		log.Int("line", line),
+		log.Int("character", character),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	documentData, exists, err := s.scanFirstDocumentData(s.db.Query(ctx, sqlf.Sprintf(
+		locationsDocumentQuery,
+		bundleID,
+		path,
+		bundleID,
+		path,
+	)))
+	if err != nil || !exists || documentData.SCIPData == nil {
+		return nil, err
+	}
+
+	calls := calleesOfPosition(documentData.SCIPData, types.Position{Line: line, Character: character}, bundleID, path)
+	trace.AddEvent("calleesOfPosition", attribute.Int("numCalls", len(calls)))
+
+	// Callees not defined in this document may be defined in another document of the index
+	callsBySymbol := make(map[string]*shared.CallHierarchyCall, len(calls))
+	symbols := make([]string, 0, len(calls))
+	for i := range calls {
+		if calls[i].Definition.DumpID == 0 {
+			callsBySymbol[calls[i].Symbol] = &calls[i]
+			symbols = append(symbols, calls[i].Symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		return calls, nil
+	}
+
+	monikerLocations, err := s.scanQualifiedMonikerLocations(s.db.Query(ctx, sqlf.Sprintf(
+		calleeDefinitionsQuery,
+		pq.Array(symbols),
+		pq.Array([]int{bundleID}),
+		bundleID,
+	)))
+	if err != nil {
+		return nil, err
+	}
+	for _, monikerLocation := range monikerLocations {
+		call, ok := callsBySymbol[monikerLocation.Identifier]
+		if !ok || call.Definition.DumpID != 0 || len(monikerLocation.Locations) == 0 {
+			continue
+		}
+
+		row := monikerLocation.Locations[0]
+		call.Definition = shared.Location{
+			DumpID: monikerLocation.DumpID,
+			Path:   row.URI,
+			Range:  newRange(row.StartLine, row.StartCharacter, row.EndLine, row.EndCharacter),
+		}
+	}
+
+	return calls, nil
+}
+
+const calleeDefinitionsQuery = `
+WITH RECURSIVE
+` + symbolIDsCTEs + `
+SELECT
+	ss.upload_id,
+	'scip' AS scheme,
+	msn.symbol_name AS identifier,
+	NULL AS data,
+	ss.definition_ranges,
+	sid.document_path
+FROM codeintel_scip_symbols ss
+JOIN codeintel_scip_document_lookup sid ON sid.id = ss.document_lookup_id
+JOIN matching_symbol_names msn ON msn.id = ss.symbol_id
+WHERE
+	ss.upload_id = %s AND
+	ss.definition_ranges IS NOT NULL
+ORDER BY sid.document_path
+`
+
+// callableRange is the part of a document enclosed by the definition of a callable symbol.
+type callableRange struct {
+	symbol     string
+	definition types.Range
+	// end is the (exclusive) end of the enclosed part, or nil if it extends to the end of the document.
+	end *types.Position
+}
+
+// encloses returns true if the given position is within the callable.
+func (c callableRange) encloses(p types.Position) bool {
+	return !comparePositions(p, c.definition.Start) && (c.end == nil || comparePositions(p, *c.end))
+}
+
+// enclosingCallableRanges returns the ranges enclosed by the definitions of callable symbols in
+// the given document, ordered by their start. The SCIP documents we store do not record the
+// enclosing ranges of definitions, so we approximate them: each callable encloses the part of the
+// document from its definition up to the definition of the next callable.
+func enclosingCallableRanges(document *scip.Document) []callableRange {
+	var callables []callableRange
+	for _, occurrence := range document.Occurrences {
+		if scip.SymbolRole_Definition.Matches(occurrence) && isCallableSymbol(occurrence.Symbol) {
+			callables = append(callables, callableRange{
+				symbol:     occurrence.Symbol,
+				definition: translateRange(scip.NewRange(occurrence.Range)),
+			})
+		}
+	}
+
+	sort.Slice(callables, func(i, j int) bool {
+		return compareBundleRanges(callables[i].definition, callables[j].definition)
+	})
+	for i := 0; i+1 < len(callables); i++ {
+		end := callables[i+1].definition.Start
+		callables[i].end = &end
+	}
+
+	return callables
+}
+
+// isCallableSymbol returns true if the given symbol is a global function or method. SCIP describes
+// both with a method descriptor, which is the only descriptor ending in ").".
+func isCallableSymbol(symbol string) bool {
+	return symbol != "" && !scip.IsLocalSymbol(symbol) && strings.HasSuffix(symbol, ").")
+}
+
+// callersOfRanges groups the given ranges of the given document by the callable enclosing them.
+// The returned calls are ordered by their first call site.
+func callersOfRanges(document *scip.Document, ranges []types.Range, bundleID int, path string) []shared.CallHierarchyCall {
+	definitions := map[types.Range]struct{}{}
+	for _, occurrence := range document.Occurrences {
+		if scip.SymbolRole_Definition.Matches(occurrence) {
+			definitions[translateRange(scip.NewRange(occurrence.Range))] = struct{}{}
+		}
+	}
+
+	callables := enclosingCallableRanges(document)
+
+	var calls []shared.CallHierarchyCall
+	indexes := map[string]int{}
+	for _, r := range ranges {
+		if _, ok := definitions[r]; ok {
+			continue
+		}
+
+		for _, callable := range callables {
+			if !callable.encloses(r.Start) {
+				continue
+			}
+
+			i, ok := indexes[callable.symbol]
+			if !ok {
+				i = len(calls)
+				indexes[callable.symbol] = i
+				calls = append(calls, shared.CallHierarchyCall{
+					Symbol:     callable.symbol,
+					Definition: shared.Location{DumpID: bundleID, Path: path, Range: callable.definition},
+				})
+			}
+			calls[i].CallSites = append(calls[i].CallSites, shared.Location{DumpID: bundleID, Path: path, Range: r})
+			break
+		}
+	}
+
+	sortCalls(calls)
+	return calls
+}
+
+// calleesOfPosition groups the references to callables within the callable defined at the given
+// position by the referenced callable. Callees defined within the given document have their
+// definition set. The returned calls are ordered by their first call site.
+func calleesOfPosition(document *scip.Document, position types.Position, bundleID int, path string) []shared.CallHierarchyCall {
+	callables := enclosingCallableRanges(document)
+
+	var caller *callableRange
+	for i := range callables {
+		if rangeContainsPosition(callables[i].definition, position) {
+			caller = &callables[i]
+			break
+		}
+	}
+	if caller == nil {
+		return nil
+	}
+
+	var calls []shared.CallHierarchyCall
+	indexes := map[string]int{}
+	for _, occurrence := range document.Occurrences {
+		if scip.SymbolRole_Definition.Matches(occurrence) || !isCallableSymbol(occurrence.Symbol) {
+			continue
+		}
+		r := translateRange(scip.NewRange(occurrence.Range))
+		if !caller.encloses(r.Start) {
+			continue
+		}
+
+		i, ok := indexes[occurrence.Symbol]
+		if !ok {
+			i = len(calls)
+			indexes[occurrence.Symbol] = i
+			calls = append(calls, shared.CallHierarchyCall{Symbol: occurrence.Symbol})
+		}
+		calls[i].CallSites = append(calls[i].CallSites, shared.Location{DumpID: bundleID, Path: path, Range: r})
+	}
+
+	for _, callable := range callables {
+		if i, ok := indexes[callable.symbol]; ok && calls[i].Definition.DumpID == 0 {
+			calls[i].Definition = shared.Location{DumpID: bundleID, Path: path, Range: callable.definition}
+		}
+	}
+
+	sortCalls(calls)
+	return calls
+}
+
+// sortCalls sorts the call sites of each call, and then the calls by their first call site.
+func sortCalls(calls []shared.CallHierarchyCall) {
+	for _, call := range calls {
+		sortLocations(call.CallSites)
+	}
+	sort.SliceStable(calls, func(i, j int) bool {
+		return compareBundleRanges(calls[i].CallSites[0].Range, calls[j].CallSites[0].Range)
+	})
+}
+
+// comparePositions returns true if p1 occurs before p2.
+func comparePositions(p1, p2 types.Position) bool {
+	if p1.Line == p2.Line {
+		return p1.Character < p2.Character
+	}
+
+	return p1.Line < p2.Line
+}
+
+// rangeContainsPosition returns true if the given range contains the given position.
+func rangeContainsPosition(r types.Range, p types.Position) bool {
+	return !comparePositions(p, r.Start) && comparePositions(p, r.End)
+}
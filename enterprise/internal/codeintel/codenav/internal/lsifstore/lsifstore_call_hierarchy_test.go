@@ -0,0 +1,109 @@
+package lsifstore
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+)
+
+const (
+	testCallerSymbol = "scip-go gomod example v1 `example`/caller()."
+	testCalleeSymbol = "scip-go gomod example v1 `example`/callee()."
+	testRemoteSymbol = "scip-go gomod fmt v1 `fmt`/Println()."
+	testTypeSymbol   = "scip-go gomod example v1 `example`/T#"
+)
+
+// testCallHierarchyDocument models the following document:
+//
+//	func callee() {
+//		fmt.Println()
+//	}
+//
+//	func caller() {
+//		var t T
+//		callee()
+//		callee()
+//	}
+var testCallHierarchyDocument = &scip.Document{
+	Occurrences: []*scip.Occurrence{
+		{Range: []int32{0, 5, 11}, Symbol: testCalleeSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+		{Range: []int32{1, 5, 12}, Symbol: testRemoteSymbol},
+		{Range: []int32{4, 5, 11}, Symbol: testCallerSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+		{Range: []int32{5, 5, 6}, Symbol: "local 0", SymbolRoles: int32(scip.SymbolRole_Definition)},
+		{Range: []int32{5, 7, 8}, Symbol: testTypeSymbol},
+		{Range: []int32{7, 1, 7}, Symbol: testCalleeSymbol},
+		{Range: []int32{6, 1, 7}, Symbol: testCalleeSymbol},
+	},
+}
+
+func TestIsCallableSymbol(t *testing.T) {
+	for symbol, expected := range map[string]bool{
+		testCallerSymbol: true,
+		testRemoteSymbol: true,
+		testTypeSymbol:   false,
+		"local 0":        false,
+		"":               false,
+		"scip-go gomod example v1 `example`/caller().(x)": false,
+	} {
+		if isCallable := isCallableSymbol(symbol); isCallable != expected {
+			t.Errorf("unexpected result for %q. want=%v have=%v", symbol, expected, isCallable)
+		}
+	}
+}
+
+func TestCallersOfRanges(t *testing.T) {
+	ranges := []types.Range{
+		newRange(7, 1, 7, 7),
+		newRange(0, 5, 0, 11), // definition
+		newRange(6, 1, 6, 7),
+	}
+
+	expected := []shared.CallHierarchyCall{
+		{
+			Symbol:     testCallerSymbol,
+			Definition: shared.Location{DumpID: 42, Path: "main.go", Range: newRange(4, 5, 4, 11)},
+			CallSites: []shared.Location{
+				{DumpID: 42, Path: "main.go", Range: newRange(6, 1, 6, 7)},
+				{DumpID: 42, Path: "main.go", Range: newRange(7, 1, 7, 7)},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, callersOfRanges(testCallHierarchyDocument, ranges, 42, "main.go")); diff != "" {
+		t.Errorf("unexpected callers (-want +got):\n%s", diff)
+	}
+}
+
+func TestCalleesOfPosition(t *testing.T) {
+	expected := []shared.CallHierarchyCall{
+		{
+			Symbol:     testCalleeSymbol,
+			Definition: shared.Location{DumpID: 42, Path: "main.go", Range: newRange(0, 5, 0, 11)},
+			CallSites: []shared.Location{
+				{DumpID: 42, Path: "main.go", Range: newRange(6, 1, 6, 7)},
+				{DumpID: 42, Path: "main.go", Range: newRange(7, 1, 7, 7)},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, calleesOfPosition(testCallHierarchyDocument, types.Position{Line: 4, Character: 7}, 42, "main.go")); diff != "" {
+		t.Errorf("unexpected callees (-want +got):\n%s", diff)
+	}
+
+	// Callees defined elsewhere have no definition yet
+	expected = []shared.CallHierarchyCall{
+		{
+			Symbol:    testRemoteSymbol,
+			CallSites: []shared.Location{{DumpID: 42, Path: "main.go", Range: newRange(1, 5, 1, 12)}},
+		},
+	}
+	if diff := cmp.Diff(expected, calleesOfPosition(testCallHierarchyDocument, types.Position{Line: 0, Character: 5}, 42, "main.go")); diff != "" {
+		t.Errorf("unexpected callees (-want +got):\n%s", diff)
+	}
+
+	if callees := calleesOfPosition(testCallHierarchyDocument, types.Position{Line: 5, Character: 7}, 42, "main.go"); len(callees) != 0 {
+		t.Errorf("unexpected callees outside of a callable definition: %v", callees)
+	}
+}
@@ -0,0 +1,179 @@
+package lsifstore
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// GetDocumentSymbols returns the hierarchy of symbols defined in the given document. Only SCIP
+// indexes support document symbols; a nil slice is returned for documents of other indexes.
+func (s *store) GetDocumentSymbols(ctx context.Context, bundleID int, path string) (_ []shared.DocumentSymbol, _ bool, err error) {
+	ctx, trace, endObservation := s.operations.getDocumentSymbols.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("bundleID", bundleID),
+		log.String("path", path),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	documentData, exists, err := s.scanFirstDocumentData(s.db.Query(ctx, sqlf.Sprintf(
+		locationsDocumentQuery,
+		bundleID,
+		path,
+		bundleID,
+		path,
+	)))
+	if err != nil || !exists || documentData.SCIPData == nil {
+		return nil, false, err
+	}
+
+	symbols := documentSymbols(documentData.SCIPData)
+	trace.AddEvent("documentSymbols", attribute.Int("numSymbols", len(symbols)))
+
+	return symbols, true, nil
+}
+
+// documentSymbol is a symbol defined in a document along with the key of its descriptors.
+type documentSymbol struct {
+	shared.DocumentSymbol
+	key       string
+	parentKey string
+	children  []int
+}
+
+// documentSymbols returns the hierarchy of the global symbols defined in the given document. A
+// symbol is nested under the closest symbol defined in the document whose descriptors prefix its
+// own. The SCIP documents we store do not record the enclosing ranges of definitions, so the range
+// of each symbol is the range of its definition. Symbols at each level are ordered by range.
+func documentSymbols(document *scip.Document) []shared.DocumentSymbol {
+	var symbols []documentSymbol
+	indexes := map[string]int{}
+	for _, occurrence := range document.Occurrences {
+		if !scip.SymbolRole_Definition.Matches(occurrence) || occurrence.Symbol == "" || scip.IsLocalSymbol(occurrence.Symbol) {
+			continue
+		}
+
+		symbol, err := scip.ParseSymbol(occurrence.Symbol)
+		if err != nil || len(symbol.Descriptors) == 0 {
+			continue
+		}
+		kind, ok := documentSymbolKind(symbol.Descriptors)
+		if !ok {
+			continue
+		}
+
+		key := descriptorsKey(symbol.Package, symbol.Descriptors)
+		if _, ok := indexes[key]; ok {
+			continue
+		}
+		indexes[key] = len(symbols)
+
+		symbols = append(symbols, documentSymbol{
+			DocumentSymbol: shared.DocumentSymbol{
+				Name:   symbol.Descriptors[len(symbol.Descriptors)-1].Name,
+				Symbol: occurrence.Symbol,
+				Kind:   kind,
+				Range:  translateRange(scip.NewRange(occurrence.Range)),
+			},
+			key:       key,
+			parentKey: descriptorsKey(symbol.Package, symbol.Descriptors[:len(symbol.Descriptors)-1]),
+		})
+	}
+
+	var roots []int
+	for i := range symbols {
+		parent, ok := -1, false
+		for key := symbols[i].parentKey; ; key = parentDescriptorsKey(key) {
+			if parent, ok = indexes[key]; ok || !strings.Contains(key, descriptorsKeySeparator) {
+				break
+			}
+		}
+
+		if ok {
+			symbols[parent].children = append(symbols[parent].children, i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	return resolveDocumentSymbols(symbols, roots)
+}
+
+// resolveDocumentSymbols returns the symbols at the given indexes with their children attached,
+// ordered by range.
+func resolveDocumentSymbols(symbols []documentSymbol, indexes []int) []shared.DocumentSymbol {
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	resolved := make([]shared.DocumentSymbol, 0, len(indexes))
+	for _, i := range indexes {
+		symbol := symbols[i].DocumentSymbol
+		symbol.Children = resolveDocumentSymbols(symbols, symbols[i].children)
+		resolved = append(resolved, symbol)
+	}
+	sort.SliceStable(resolved, func(i, j int) bool {
+		return compareBundleRanges(resolved[i].Range, resolved[j].Range)
+	})
+
+	return resolved
+}
+
+// documentSymbolKind returns the name of the SymbolKind of a symbol with the given descriptors.
+// Parameters, meta descriptors, and locals do not appear in document outlines.
+func documentSymbolKind(descriptors []*scip.Descriptor) (string, bool) {
+	withinType := len(descriptors) > 1 && descriptors[len(descriptors)-2].Suffix == scip.Descriptor_Type
+
+	switch descriptors[len(descriptors)-1].Suffix {
+	case scip.Descriptor_Namespace:
+		return "NAMESPACE", true
+	case scip.Descriptor_Type:
+		return "CLASS", true
+	case scip.Descriptor_Method:
+		if withinType {
+			return "METHOD", true
+		}
+		return "FUNCTION", true
+	case scip.Descriptor_Term:
+		if withinType {
+			return "FIELD", true
+		}
+		return "VARIABLE", true
+	case scip.Descriptor_TypeParameter:
+		return "TYPEPARAMETER", true
+	case scip.Descriptor_Macro:
+		return "FUNCTION", true
+	}
+
+	return "", false
+}
+
+const descriptorsKeySeparator = "\x00"
+
+// descriptorsKey returns a key identifying the given descriptors within the given package. Keys
+// of nested descriptors are prefixed by the keys of their parents.
+func descriptorsKey(pkg *scip.Package, descriptors []*scip.Descriptor) string {
+	parts := make([]string, 0, len(descriptors)+1)
+	parts = append(parts, pkg.ID())
+	for _, descriptor := range descriptors {
+		parts = append(parts, descriptor.Suffix.String()+":"+descriptor.Name+":"+descriptor.Disambiguator)
+	}
+
+	return strings.Join(parts, descriptorsKeySeparator)
+}
+
+// parentDescriptorsKey returns the key of the parent of the descriptors with the given key.
+func parentDescriptorsKey(key string) string {
+	if i := strings.LastIndex(key, descriptorsKeySeparator); i >= 0 {
+		return key[:i]
+	}
+
+	return key
+}
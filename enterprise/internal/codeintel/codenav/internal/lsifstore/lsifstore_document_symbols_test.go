@@ -0,0 +1,99 @@
+package lsifstore
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+)
+
+func TestDocumentSymbols(t *testing.T) {
+	const (
+		typeSymbol      = "scip-typescript npm example 1.0.0 src/`index.ts`/Greeter#"
+		fieldSymbol     = "scip-typescript npm example 1.0.0 src/`index.ts`/Greeter#name."
+		methodSymbol    = "scip-typescript npm example 1.0.0 src/`index.ts`/Greeter#greet()."
+		parameterSymbol = "scip-typescript npm example 1.0.0 src/`index.ts`/Greeter#greet().(greeting)"
+		functionSymbol  = "scip-typescript npm example 1.0.0 src/`index.ts`/main()."
+		variableSymbol  = "scip-typescript npm example 1.0.0 src/`index.ts`/main().greeter."
+	)
+
+	// Models the following document:
+	//
+	//	class Greeter {
+	//		name: string
+	//		greet(greeting: string) {
+	//			return greeting + this.name
+	//		}
+	//	}
+	//
+	//	function main() {
+	//		const greeter = new Greeter()
+	//		let x = 1
+	//	}
+	document := &scip.Document{
+		Occurrences: []*scip.Occurrence{
+			{Range: []int32{8, 9, 13}, Symbol: functionSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{0, 6, 13}, Symbol: typeSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{2, 1, 6}, Symbol: methodSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{2, 7, 15}, Symbol: parameterSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{1, 1, 5}, Symbol: fieldSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{3, 26, 30}, Symbol: fieldSymbol},
+			{Range: []int32{9, 7, 14}, Symbol: variableSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{9, 21, 28}, Symbol: typeSymbol},
+			{Range: []int32{10, 5, 6}, Symbol: "local 0", SymbolRoles: int32(scip.SymbolRole_Definition)},
+		},
+	}
+
+	expected := []shared.DocumentSymbol{
+		{
+			Name:   "Greeter",
+			Symbol: typeSymbol,
+			Kind:   "CLASS",
+			Range:  newRange(0, 6, 0, 13),
+			Children: []shared.DocumentSymbol{
+				{Name: "name", Symbol: fieldSymbol, Kind: "FIELD", Range: newRange(1, 1, 1, 5)},
+				{Name: "greet", Symbol: methodSymbol, Kind: "METHOD", Range: newRange(2, 1, 2, 6)},
+			},
+		},
+		{
+			Name:   "main",
+			Symbol: functionSymbol,
+			Kind:   "FUNCTION",
+			Range:  newRange(8, 9, 8, 13),
+			Children: []shared.DocumentSymbol{
+				{Name: "greeter", Symbol: variableSymbol, Kind: "VARIABLE", Range: newRange(9, 7, 9, 14)},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, documentSymbols(document)); diff != "" {
+		t.Errorf("unexpected document symbols (-want +got):\n%s", diff)
+	}
+}
+
+func TestDocumentSymbolsSkipsUndefinedParents(t *testing.T) {
+	// The enclosing namespace is defined in another document
+	document := &scip.Document{
+		Occurrences: []*scip.Occurrence{
+			{Range: []int32{2, 13, 16}, Symbol: "scip-java maven example 1.0 com/example/Foo#", SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{3, 13, 16}, Symbol: "scip-java maven example 1.0 com/example/Foo#bar().", SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{3, 20, 23}, Symbol: "scip-java maven example 1.0 com/example/Foo#bar().(baz)", SymbolRoles: int32(scip.SymbolRole_Definition)},
+		},
+	}
+
+	expected := []shared.DocumentSymbol{
+		{
+			Name:   "Foo",
+			Symbol: "scip-java maven example 1.0 com/example/Foo#",
+			Kind:   "CLASS",
+			Range:  newRange(2, 13, 2, 16),
+			Children: []shared.DocumentSymbol{
+				{Name: "bar", Symbol: "scip-java maven example 1.0 com/example/Foo#bar().", Kind: "METHOD", Range: newRange(3, 13, 3, 16)},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, documentSymbols(document)); diff != "" {
+		t.Errorf("unexpected document symbols (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,148 @@
+package lsifstore
+
+import (
+	"context"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// GetExportedSymbols returns the exported symbols defined in the given upload, ordered by the path
+// of their defining document. Only SCIP indexes support exported symbols; a false-valued flag is
+// returned for uploads of other indexes.
+func (s *store) GetExportedSymbols(ctx context.Context, uploadID int) (_ []shared.ExportedSymbol, _ bool, err error) {
+	ctx, trace, endObservation := s.operations.getExportedSymbols.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("uploadID", uploadID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	rows, err := s.db.Query(ctx, sqlf.Sprintf(exportedSymbolsDocumentsQuery, uploadID))
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	// Documents are decoded one at a time so that we never hold the entire index in memory
+	var (
+		symbols      []shared.ExportedSymbol
+		numDocuments int
+	)
+	for rows.Next() {
+		documentData, err := s.scanSingleDocumentDataObject(rows)
+		if err != nil {
+			return nil, false, err
+		}
+		if documentData.SCIPData == nil {
+			continue
+		}
+
+		numDocuments++
+		symbols = append(symbols, exportedSymbols(uploadID, documentData.Path, documentData.SCIPData)...)
+	}
+	trace.AddEvent("exportedSymbols",
+		attribute.Int("numDocuments", numDocuments),
+		attribute.Int("numSymbols", len(symbols)))
+
+	return symbols, numDocuments > 0, nil
+}
+
+const exportedSymbolsDocumentsQuery = `
+SELECT
+	sid.upload_id,
+	sid.document_path,
+	NULL AS data,
+	NULL AS ranges,
+	NULL AS hovers,
+	NULL AS monikers,
+	NULL AS packages,
+	NULL AS diagnostics,
+	sd.raw_scip_payload AS scip_document
+FROM codeintel_scip_document_lookup sid
+JOIN codeintel_scip_documents sd ON sd.id = sid.document_id
+WHERE sid.upload_id = %s
+ORDER BY sid.document_path
+`
+
+// exportedSymbols returns the exported symbols defined in the given document, in the order of their
+// first definition.
+func exportedSymbols(uploadID int, path string, document *scip.Document) []shared.ExportedSymbol {
+	var symbols []shared.ExportedSymbol
+	seen := map[string]struct{}{}
+	for _, occurrence := range document.Occurrences {
+		if !scip.SymbolRole_Definition.Matches(occurrence) || occurrence.Symbol == "" || scip.IsLocalSymbol(occurrence.Symbol) {
+			continue
+		}
+		if _, ok := seen[occurrence.Symbol]; ok {
+			continue
+		}
+		seen[occurrence.Symbol] = struct{}{}
+
+		symbol, err := scip.ParseSymbol(occurrence.Symbol)
+		if err != nil || !isExportedSymbol(symbol.Descriptors) {
+			continue
+		}
+
+		var signature string
+		if symbolInformation := types.FindSymbol(document, occurrence.Symbol); symbolInformation != nil {
+			signature = symbolSignature(symbolInformation.Documentation)
+		}
+
+		symbols = append(symbols, shared.ExportedSymbol{
+			Symbol:    occurrence.Symbol,
+			Signature: signature,
+			Definition: shared.Location{
+				DumpID: uploadID,
+				Path:   path,
+				Range:  translateRange(scip.NewRange(occurrence.Range)),
+			},
+		})
+	}
+
+	return symbols
+}
+
+// isExportedSymbol returns true if a symbol with the given descriptors is part of the API surface of
+// its package: a type, term, method, or macro that is nested only within namespaces and types. SCIP
+// does not record visibility, so symbols private to their package are indistinguishable from public
+// ones here. Parameters and symbols declared within the bodies of functions are never exported.
+func isExportedSymbol(descriptors []*scip.Descriptor) bool {
+	if len(descriptors) == 0 {
+		return false
+	}
+
+	for _, descriptor := range descriptors[:len(descriptors)-1] {
+		if descriptor.Suffix != scip.Descriptor_Namespace && descriptor.Suffix != scip.Descriptor_Type {
+			return false
+		}
+	}
+
+	switch descriptors[len(descriptors)-1].Suffix {
+	case scip.Descriptor_Type, scip.Descriptor_Term, scip.Descriptor_Method, scip.Descriptor_Macro:
+		return true
+	}
+
+	return false
+}
+
+// symbolSignature returns the code block that leads the given hover documentation. By convention,
+// SCIP indexers emit the signature of a symbol as the first documentation entry, formatted as a
+// fenced code block.
+func symbolSignature(documentation []string) string {
+	if len(documentation) == 0 {
+		return ""
+	}
+
+	if signature := strings.TrimSpace(documentation[0]); strings.HasPrefix(signature, "```") {
+		return signature
+	}
+
+	return ""
+}
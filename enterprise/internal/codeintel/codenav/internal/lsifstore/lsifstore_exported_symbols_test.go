@@ -0,0 +1,65 @@
+package lsifstore
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+)
+
+func TestExportedSymbols(t *testing.T) {
+	const (
+		namespaceSymbol = "scip-go gomod github.com/example/greeter v1.0.0 `github.com/example/greeter`/"
+		typeSymbol      = "scip-go gomod github.com/example/greeter v1.0.0 `github.com/example/greeter`/Greeter#"
+		fieldSymbol     = "scip-go gomod github.com/example/greeter v1.0.0 `github.com/example/greeter`/Greeter#Name."
+		methodSymbol    = "scip-go gomod github.com/example/greeter v1.0.0 `github.com/example/greeter`/Greeter#Greet()."
+		parameterSymbol = "scip-go gomod github.com/example/greeter v1.0.0 `github.com/example/greeter`/Greeter#Greet().(greeting)"
+		functionSymbol  = "scip-go gomod github.com/example/greeter v1.0.0 `github.com/example/greeter`/New()."
+		variableSymbol  = "scip-go gomod github.com/example/greeter v1.0.0 `github.com/example/greeter`/New().greeter."
+	)
+
+	document := &scip.Document{
+		Occurrences: []*scip.Occurrence{
+			{Range: []int32{0, 8, 15}, Symbol: namespaceSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{2, 5, 12}, Symbol: typeSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{3, 1, 5}, Symbol: fieldSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{6, 19, 24}, Symbol: methodSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{6, 25, 33}, Symbol: parameterSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{7, 21, 25}, Symbol: fieldSymbol},
+			{Range: []int32{10, 5, 8}, Symbol: functionSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{11, 1, 8}, Symbol: variableSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{12, 1, 2}, Symbol: "local 0", SymbolRoles: int32(scip.SymbolRole_Definition)},
+		},
+		Symbols: []*scip.SymbolInformation{
+			{Symbol: typeSymbol, Documentation: []string{"```go\ntype Greeter struct\n```", "Greeter greets people."}},
+			{Symbol: methodSymbol, Documentation: []string{"```go\nfunc (g *Greeter) Greet(greeting string) string\n```"}},
+			{Symbol: functionSymbol, Documentation: []string{"New creates a greeter."}},
+		},
+	}
+
+	expected := []shared.ExportedSymbol{
+		{
+			Symbol:     typeSymbol,
+			Signature:  "```go\ntype Greeter struct\n```",
+			Definition: shared.Location{DumpID: 42, Path: "greeter.go", Range: newRange(2, 5, 2, 12)},
+		},
+		{
+			Symbol:     fieldSymbol,
+			Definition: shared.Location{DumpID: 42, Path: "greeter.go", Range: newRange(3, 1, 3, 5)},
+		},
+		{
+			Symbol:     methodSymbol,
+			Signature:  "```go\nfunc (g *Greeter) Greet(greeting string) string\n```",
+			Definition: shared.Location{DumpID: 42, Path: "greeter.go", Range: newRange(6, 19, 6, 24)},
+		},
+		{
+			Symbol:     functionSymbol,
+			Definition: shared.Location{DumpID: 42, Path: "greeter.go", Range: newRange(10, 5, 10, 8)},
+		},
+	}
+	if diff := cmp.Diff(expected, exportedSymbols(42, "greeter.go", document)); diff != "" {
+		t.Errorf("unexpected exported symbols (-want +got):\n%s", diff)
+	}
+}
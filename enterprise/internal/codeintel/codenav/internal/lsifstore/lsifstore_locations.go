@@ -38,6 +38,13 @@ func (s *store) GetImplementationLocations(ctx context.Context, bundleID int, pa
 	return s.getLocations(ctx, extractor, "implementation_ranges", extractImplementationRanges, s.operations.getImplementations, bundleID, path, line, character, limit, offset)
 }
 
+// GetTypeDefinitionLocations returns the set of locations defining the type of the symbol at the given position.
+func (s *store) GetTypeDefinitionLocations(ctx context.Context, bundleID int, path string, line, character, limit, offset int) (_ []shared.Location, _ int, err error) {
+	// LSIF indexes have no type definition results.
+	extractor := func(precise.RangeData) precise.ID { return "" }
+	return s.getLocationsForSymbols(ctx, extractor, "definition_ranges", extractTypeDefinitionRanges, extractTypeDefinitionSymbols, s.operations.getTypeDefinitions, bundleID, path, line, character, limit, offset)
+}
+
 func (s *store) getLocations(
 	ctx context.Context,
 	lsifExtractor func(precise.RangeData) precise.ID,
@@ -47,6 +54,22 @@ func (s *store) getLocations(
 	bundleID int,
 	path string,
 	line, character, limit, offset int,
+) (_ []shared.Location, _ int, err error) {
+	return s.getLocationsForSymbols(ctx, lsifExtractor, scipFieldName, scipExtractor, extractOccurrenceSymbol, operation, bundleID, path, line, character, limit, offset)
+}
+
+// getLocationsForSymbols is like getLocations, but searches the other documents of the index for the
+// symbols returned by scipSymbolExtractor rather than the symbol of the occurrence.
+func (s *store) getLocationsForSymbols(
+	ctx context.Context,
+	lsifExtractor func(precise.RangeData) precise.ID,
+	scipFieldName string,
+	scipExtractor func(*scip.Document, *scip.Occurrence) []*scip.Range,
+	scipSymbolExtractor func(*scip.Document, *scip.Occurrence) []string,
+	operation *observation.Operation,
+	bundleID int,
+	path string,
+	line, character, limit, offset int,
 ) (_ []shared.Location, _ int, err error) {
 	ctx, trace, endObservation := operation.With(ctx, &err, observation.Args{LogFields: []log.Field{
 		log.Int("bundleID", bundleID),
@@ -78,10 +101,10 @@ func (s *store) getLocations(
 				locations = append(locations, convertSCIPRangesToLocations(ranges, bundleID, path)...)
 			}
 
-			if occurrence.Symbol != "" && !scip.IsLocalSymbol(occurrence.Symbol) {
+			if symbols := scipSymbolExtractor(documentData.SCIPData, occurrence); len(symbols) != 0 {
 				monikerLocations, err := s.scanQualifiedMonikerLocations(s.db.Query(ctx, sqlf.Sprintf(
 					locationsSymbolSearchQuery,
-					pq.Array([]string{occurrence.Symbol}),
+					pq.Array(symbols),
 					pq.Array([]int{bundleID}),
 					sqlf.Sprintf(scipFieldName),
 					bundleID,
@@ -602,6 +625,7 @@ type extractedOccurrenceData struct {
 	definitions     []*scip.Range
 	references      []*scip.Range
 	implementations []*scip.Range
+	typeDefinitions []*scip.Range
 	hoverText       []string
 }
 
@@ -617,6 +641,39 @@ func extractImplementationRanges(document *scip.Document, occurrence *scip.Occur
 	return extractOccurrenceData(document, occurrence).implementations
 }
 
+func extractTypeDefinitionRanges(document *scip.Document, occurrence *scip.Occurrence) []*scip.Range {
+	return extractOccurrenceData(document, occurrence).typeDefinitions
+}
+
+// extractOccurrenceSymbol returns the symbol of the given occurrence, unless it is local to
+// the document.
+func extractOccurrenceSymbol(document *scip.Document, occurrence *scip.Occurrence) []string {
+	if occurrence.Symbol == "" || scip.IsLocalSymbol(occurrence.Symbol) {
+		return nil
+	}
+	return []string{occurrence.Symbol}
+}
+
+// extractTypeDefinitionSymbols returns the non-local symbols with a type definition relationship
+// to the symbol of the given occurrence.
+func extractTypeDefinitionSymbols(document *scip.Document, occurrence *scip.Occurrence) []string {
+	if occurrence.Symbol == "" {
+		return nil
+	}
+	symbol := types.FindSymbol(document, occurrence.Symbol)
+	if symbol == nil {
+		return nil
+	}
+
+	var symbols []string
+	for _, rel := range symbol.Relationships {
+		if rel.IsTypeDefinition && !scip.IsLocalSymbol(rel.Symbol) {
+			symbols = append(symbols, rel.Symbol)
+		}
+	}
+	return symbols
+}
+
 func extractHoverData(document *scip.Document, occurrence *scip.Occurrence) []string {
 	return extractOccurrenceData(document, occurrence).hoverText
 }
@@ -633,11 +690,12 @@ func extractOccurrenceData(document *scip.Document, occurrence *scip.Occurrence)
 		definitionSymbol        = occurrence.Symbol
 		referencesBySymbol      = map[string]struct{}{}
 		implementationsBySymbol = map[string]struct{}{}
+		typeDefinitionsBySymbol = map[string]struct{}{}
 	)
 
 	// Extract hover text and relationship data from the symbol information that
 	// matches the given occurrence. This will give us additional symbol names that
-	// we should include in reference and implementation searches.
+	// we should include in reference, implementation, and type definition searches.
 
 	if symbol := types.FindSymbol(document, occurrence.Symbol); symbol != nil {
 		hoverText = symbol.Documentation
@@ -652,18 +710,23 @@ func extractOccurrenceData(document *scip.Document, occurrence *scip.Occurrence)
 			if rel.IsImplementation {
 				implementationsBySymbol[rel.Symbol] = struct{}{}
 			}
+			if rel.IsTypeDefinition {
+				typeDefinitionsBySymbol[rel.Symbol] = struct{}{}
+			}
 		}
 	}
 
 	definitions := []*scip.Range{}
 	references := []*scip.Range{}
 	implementations := []*scip.Range{}
+	typeDefinitions := []*scip.Range{}
 
 	// Include original symbol names for reference search below
 	referencesBySymbol[occurrence.Symbol] = struct{}{}
 
-	// For each occurrence that references one of the definition, reference, or
-	// implementation symbol names, extract and aggregate their source positions.
+	// For each occurrence that references one of the definition, reference,
+	// implementation, or type definition symbol names, extract and aggregate their
+	// source positions.
 
 	for _, occ := range document.Occurrences {
 		isDefinition := scip.SymbolRole_Definition.Matches(occ)
@@ -682,6 +745,11 @@ func extractOccurrenceData(document *scip.Document, occurrence *scip.Occurrence)
 		if _, ok := implementationsBySymbol[occ.Symbol]; ok && isDefinition {
 			implementations = append(implementations, scip.NewRange(occ.Range))
 		}
+
+		// This occurrence is a definition of a symbol with a type definition relationship
+		if _, ok := typeDefinitionsBySymbol[occ.Symbol]; ok && isDefinition {
+			typeDefinitions = append(typeDefinitions, scip.NewRange(occ.Range))
+		}
 	}
 
 	// Override symbol documentation with occurrence documentation, if it exists
@@ -693,6 +761,7 @@ func extractOccurrenceData(document *scip.Document, occurrence *scip.Occurrence)
 		definitions:     definitions,
 		references:      references,
 		implementations: implementations,
+		typeDefinitions: typeDefinitions,
 		hoverText:       hoverText,
 	}
 }
@@ -12,6 +12,11 @@ type operations struct {
 	getImplementations     *observation.Operation
 	getHover               *observation.Operation
 	getDefinitions         *observation.Operation
+	getTypeDefinitions     *observation.Operation
+	getCallers             *observation.Operation
+	getCallees             *observation.Operation
+	getDocumentSymbols     *observation.Operation
+	getExportedSymbols     *observation.Operation
 	getDiagnostics         *observation.Operation
 	getRanges              *observation.Operation
 	getStencil             *observation.Operation
@@ -58,6 +63,11 @@ func newOperations(observationCtx *observation.Context) *operations {
 		getImplementations:     op("GetImplementations"),
 		getHover:               op("GetHover"),
 		getDefinitions:         op("GetDefinitions"),
+		getTypeDefinitions:     op("GetTypeDefinitions"),
+		getCallers:             op("GetCallers"),
+		getCallees:             op("GetCallees"),
+		getDocumentSymbols:     op("GetDocumentSymbols"),
+		getExportedSymbols:     op("GetExportedSymbols"),
 		getDiagnostics:         op("GetDiagnostics"),
 		getRanges:              op("GetRanges"),
 		getStencil:             op("GetStencil"),
@@ -19,6 +19,8 @@ import (
 	api "github.com/sourcegraph/sourcegraph/internal/api"
 	authz "github.com/sourcegraph/sourcegraph/internal/authz"
 	database "github.com/sourcegraph/sourcegraph/internal/database"
+	search "github.com/sourcegraph/sourcegraph/internal/search"
+	result "github.com/sourcegraph/sourcegraph/internal/search/result"
 	precise "github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 )
 
@@ -172,12 +174,24 @@ type MockLsifStore struct {
 	// GetBulkMonikerLocationsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetBulkMonikerLocations.
 	GetBulkMonikerLocationsFunc *LsifStoreGetBulkMonikerLocationsFunc
+	// GetCalleesFunc is an instance of a mock function object controlling
+	// the behavior of the method GetCallees.
+	GetCalleesFunc *LsifStoreGetCalleesFunc
+	// GetCallersFunc is an instance of a mock function object controlling
+	// the behavior of the method GetCallers.
+	GetCallersFunc *LsifStoreGetCallersFunc
 	// GetDefinitionLocationsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetDefinitionLocations.
 	GetDefinitionLocationsFunc *LsifStoreGetDefinitionLocationsFunc
 	// GetDiagnosticsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetDiagnostics.
 	GetDiagnosticsFunc *LsifStoreGetDiagnosticsFunc
+	// GetDocumentSymbolsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetDocumentSymbols.
+	GetDocumentSymbolsFunc *LsifStoreGetDocumentSymbolsFunc
+	// GetExportedSymbolsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetExportedSymbols.
+	GetExportedSymbolsFunc *LsifStoreGetExportedSymbolsFunc
 	// GetHoverFunc is an instance of a mock function object controlling the
 	// behavior of the method GetHover.
 	GetHoverFunc *LsifStoreGetHoverFunc
@@ -203,6 +217,10 @@ type MockLsifStore struct {
 	// GetStencilFunc is an instance of a mock function object controlling
 	// the behavior of the method GetStencil.
 	GetStencilFunc *LsifStoreGetStencilFunc
+	// GetTypeDefinitionLocationsFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// GetTypeDefinitionLocations.
+	GetTypeDefinitionLocationsFunc *LsifStoreGetTypeDefinitionLocationsFunc
 }
 
 // NewMockLsifStore creates a new mock of the LsifStore interface. All
@@ -214,6 +232,16 @@ func NewMockLsifStore() *MockLsifStore {
 				return
 			},
 		},
+		GetCalleesFunc: &LsifStoreGetCalleesFunc{
+			defaultHook: func(context.Context, int, string, int, int) (r0 []shared.CallHierarchyCall, r1 error) {
+				return
+			},
+		},
+		GetCallersFunc: &LsifStoreGetCallersFunc{
+			defaultHook: func(context.Context, int, string, []types.Range) (r0 []shared.CallHierarchyCall, r1 error) {
+				return
+			},
+		},
 		GetDefinitionLocationsFunc: &LsifStoreGetDefinitionLocationsFunc{
 			defaultHook: func(context.Context, int, string, int, int, int, int) (r0 []shared.Location, r1 int, r2 error) {
 				return
@@ -224,6 +252,16 @@ func NewMockLsifStore() *MockLsifStore {
 				return
 			},
 		},
+		GetDocumentSymbolsFunc: &LsifStoreGetDocumentSymbolsFunc{
+			defaultHook: func(context.Context, int, string) (r0 []shared.DocumentSymbol, r1 bool, r2 error) {
+				return
+			},
+		},
+		GetExportedSymbolsFunc: &LsifStoreGetExportedSymbolsFunc{
+			defaultHook: func(context.Context, int) (r0 []shared.ExportedSymbol, r1 bool, r2 error) {
+				return
+			},
+		},
 		GetHoverFunc: &LsifStoreGetHoverFunc{
 			defaultHook: func(context.Context, int, string, int, int) (r0 string, r1 types.Range, r2 bool, r3 error) {
 				return
@@ -264,6 +302,11 @@ func NewMockLsifStore() *MockLsifStore {
 				return
 			},
 		},
+		GetTypeDefinitionLocationsFunc: &LsifStoreGetTypeDefinitionLocationsFunc{
+			defaultHook: func(context.Context, int, string, int, int, int, int) (r0 []shared.Location, r1 int, r2 error) {
+				return
+			},
+		},
 	}
 }
 
@@ -276,6 +319,16 @@ func NewStrictMockLsifStore() *MockLsifStore {
 				panic("unexpected invocation of MockLsifStore.GetBulkMonikerLocations")
 			},
 		},
+		GetCalleesFunc: &LsifStoreGetCalleesFunc{
+			defaultHook: func(context.Context, int, string, int, int) ([]shared.CallHierarchyCall, error) {
+				panic("unexpected invocation of MockLsifStore.GetCallees")
+			},
+		},
+		GetCallersFunc: &LsifStoreGetCallersFunc{
+			defaultHook: func(context.Context, int, string, []types.Range) ([]shared.CallHierarchyCall, error) {
+				panic("unexpected invocation of MockLsifStore.GetCallers")
+			},
+		},
 		GetDefinitionLocationsFunc: &LsifStoreGetDefinitionLocationsFunc{
 			defaultHook: func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error) {
 				panic("unexpected invocation of MockLsifStore.GetDefinitionLocations")
@@ -286,6 +339,16 @@ func NewStrictMockLsifStore() *MockLsifStore {
 				panic("unexpected invocation of MockLsifStore.GetDiagnostics")
 			},
 		},
+		GetDocumentSymbolsFunc: &LsifStoreGetDocumentSymbolsFunc{
+			defaultHook: func(context.Context, int, string) ([]shared.DocumentSymbol, bool, error) {
+				panic("unexpected invocation of MockLsifStore.GetDocumentSymbols")
+			},
+		},
+		GetExportedSymbolsFunc: &LsifStoreGetExportedSymbolsFunc{
+			defaultHook: func(context.Context, int) ([]shared.ExportedSymbol, bool, error) {
+				panic("unexpected invocation of MockLsifStore.GetExportedSymbols")
+			},
+		},
 		GetHoverFunc: &LsifStoreGetHoverFunc{
 			defaultHook: func(context.Context, int, string, int, int) (string, types.Range, bool, error) {
 				panic("unexpected invocation of MockLsifStore.GetHover")
@@ -326,6 +389,11 @@ func NewStrictMockLsifStore() *MockLsifStore {
 				panic("unexpected invocation of MockLsifStore.GetStencil")
 			},
 		},
+		GetTypeDefinitionLocationsFunc: &LsifStoreGetTypeDefinitionLocationsFunc{
+			defaultHook: func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error) {
+				panic("unexpected invocation of MockLsifStore.GetTypeDefinitionLocations")
+			},
+		},
 	}
 }
 
@@ -336,12 +404,24 @@ func NewMockLsifStoreFrom(i lsifstore.LsifStore) *MockLsifStore {
 		GetBulkMonikerLocationsFunc: &LsifStoreGetBulkMonikerLocationsFunc{
 			defaultHook: i.GetBulkMonikerLocations,
 		},
+		GetCalleesFunc: &LsifStoreGetCalleesFunc{
+			defaultHook: i.GetCallees,
+		},
+		GetCallersFunc: &LsifStoreGetCallersFunc{
+			defaultHook: i.GetCallers,
+		},
 		GetDefinitionLocationsFunc: &LsifStoreGetDefinitionLocationsFunc{
 			defaultHook: i.GetDefinitionLocations,
 		},
 		GetDiagnosticsFunc: &LsifStoreGetDiagnosticsFunc{
 			defaultHook: i.GetDiagnostics,
 		},
+		GetDocumentSymbolsFunc: &LsifStoreGetDocumentSymbolsFunc{
+			defaultHook: i.GetDocumentSymbols,
+		},
+		GetExportedSymbolsFunc: &LsifStoreGetExportedSymbolsFunc{
+			defaultHook: i.GetExportedSymbols,
+		},
 		GetHoverFunc: &LsifStoreGetHoverFunc{
 			defaultHook: i.GetHover,
 		},
@@ -366,6 +446,9 @@ func NewMockLsifStoreFrom(i lsifstore.LsifStore) *MockLsifStore {
 		GetStencilFunc: &LsifStoreGetStencilFunc{
 			defaultHook: i.GetStencil,
 		},
+		GetTypeDefinitionLocationsFunc: &LsifStoreGetTypeDefinitionLocationsFunc{
+			defaultHook: i.GetTypeDefinitionLocations,
+		},
 	}
 }
 
@@ -495,6 +578,235 @@ func (c LsifStoreGetBulkMonikerLocationsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// LsifStoreGetCalleesFunc describes the behavior when the GetCallees method
+// of the parent MockLsifStore instance is invoked.
+type LsifStoreGetCalleesFunc struct {
+	defaultHook func(context.Context, int, string, int, int) ([]shared.CallHierarchyCall, error)
+	hooks       []func(context.Context, int, string, int, int) ([]shared.CallHierarchyCall, error)
+	history     []LsifStoreGetCalleesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetCallees delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockLsifStore) GetCallees(v0 context.Context, v1 int, v2 string, v3 int, v4 int) ([]shared.CallHierarchyCall, error) {
+	r0, r1 := m.GetCalleesFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.GetCalleesFunc.appendCall(LsifStoreGetCalleesFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetCallees method of
+// the parent MockLsifStore instance is invoked and the hook queue is empty.
+func (f *LsifStoreGetCalleesFunc) SetDefaultHook(hook func(context.Context, int, string, int, int) ([]shared.CallHierarchyCall, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetCallees method of the parent MockLsifStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *LsifStoreGetCalleesFunc) PushHook(hook func(context.Context, int, string, int, int) ([]shared.CallHierarchyCall, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LsifStoreGetCalleesFunc) SetDefaultReturn(r0 []shared.CallHierarchyCall, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, int, int) ([]shared.CallHierarchyCall, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LsifStoreGetCalleesFunc) PushReturn(r0 []shared.CallHierarchyCall, r1 error) {
+	f.PushHook(func(context.Context, int, string, int, int) ([]shared.CallHierarchyCall, error) {
+		return r0, r1
+	})
+}
+
+func (f *LsifStoreGetCalleesFunc) nextHook() func(context.Context, int, string, int, int) ([]shared.CallHierarchyCall, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LsifStoreGetCalleesFunc) appendCall(r0 LsifStoreGetCalleesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LsifStoreGetCalleesFuncCall objects
+// describing the invocations of this function.
+func (f *LsifStoreGetCalleesFunc) History() []LsifStoreGetCalleesFuncCall {
+	f.mutex.Lock()
+	history := make([]LsifStoreGetCalleesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LsifStoreGetCalleesFuncCall is an object that describes an invocation of
+// method GetCallees on an instance of MockLsifStore.
+type LsifStoreGetCalleesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.CallHierarchyCall
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LsifStoreGetCalleesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LsifStoreGetCalleesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// LsifStoreGetCallersFunc describes the behavior when the GetCallers method
+// of the parent MockLsifStore instance is invoked.
+type LsifStoreGetCallersFunc struct {
+	defaultHook func(context.Context, int, string, []types.Range) ([]shared.CallHierarchyCall, error)
+	hooks       []func(context.Context, int, string, []types.Range) ([]shared.CallHierarchyCall, error)
+	history     []LsifStoreGetCallersFuncCall
+	mutex       sync.Mutex
+}
+
+// GetCallers delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockLsifStore) GetCallers(v0 context.Context, v1 int, v2 string, v3 []types.Range) ([]shared.CallHierarchyCall, error) {
+	r0, r1 := m.GetCallersFunc.nextHook()(v0, v1, v2, v3)
+	m.GetCallersFunc.appendCall(LsifStoreGetCallersFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetCallers method of
+// the parent MockLsifStore instance is invoked and the hook queue is empty.
+func (f *LsifStoreGetCallersFunc) SetDefaultHook(hook func(context.Context, int, string, []types.Range) ([]shared.CallHierarchyCall, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetCallers method of the parent MockLsifStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *LsifStoreGetCallersFunc) PushHook(hook func(context.Context, int, string, []types.Range) ([]shared.CallHierarchyCall, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LsifStoreGetCallersFunc) SetDefaultReturn(r0 []shared.CallHierarchyCall, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, []types.Range) ([]shared.CallHierarchyCall, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LsifStoreGetCallersFunc) PushReturn(r0 []shared.CallHierarchyCall, r1 error) {
+	f.PushHook(func(context.Context, int, string, []types.Range) ([]shared.CallHierarchyCall, error) {
+		return r0, r1
+	})
+}
+
+func (f *LsifStoreGetCallersFunc) nextHook() func(context.Context, int, string, []types.Range) ([]shared.CallHierarchyCall, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LsifStoreGetCallersFunc) appendCall(r0 LsifStoreGetCallersFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LsifStoreGetCallersFuncCall objects
+// describing the invocations of this function.
+func (f *LsifStoreGetCallersFunc) History() []LsifStoreGetCallersFuncCall {
+	f.mutex.Lock()
+	history := make([]LsifStoreGetCallersFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LsifStoreGetCallersFuncCall is an object that describes an invocation of
+// method GetCallers on an instance of MockLsifStore.
+type LsifStoreGetCallersFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 []types.Range
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.CallHierarchyCall
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LsifStoreGetCallersFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LsifStoreGetCallersFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // LsifStoreGetDefinitionLocationsFunc describes the behavior when the
 // GetDefinitionLocations method of the parent MockLsifStore instance is
 // invoked.
@@ -614,45 +926,281 @@ type LsifStoreGetDefinitionLocationsFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c LsifStoreGetDefinitionLocationsFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5, c.Arg6}
+func (c LsifStoreGetDefinitionLocationsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5, c.Arg6}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LsifStoreGetDefinitionLocationsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// LsifStoreGetDiagnosticsFunc describes the behavior when the
+// GetDiagnostics method of the parent MockLsifStore instance is invoked.
+type LsifStoreGetDiagnosticsFunc struct {
+	defaultHook func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error)
+	hooks       []func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error)
+	history     []LsifStoreGetDiagnosticsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetDiagnostics delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLsifStore) GetDiagnostics(v0 context.Context, v1 int, v2 string, v3 int, v4 int) ([]shared.Diagnostic, int, error) {
+	r0, r1, r2 := m.GetDiagnosticsFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.GetDiagnosticsFunc.appendCall(LsifStoreGetDiagnosticsFuncCall{v0, v1, v2, v3, v4, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetDiagnostics
+// method of the parent MockLsifStore instance is invoked and the hook queue
+// is empty.
+func (f *LsifStoreGetDiagnosticsFunc) SetDefaultHook(hook func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetDiagnostics method of the parent MockLsifStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LsifStoreGetDiagnosticsFunc) PushHook(hook func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LsifStoreGetDiagnosticsFunc) SetDefaultReturn(r0 []shared.Diagnostic, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LsifStoreGetDiagnosticsFunc) PushReturn(r0 []shared.Diagnostic, r1 int, r2 error) {
+	f.PushHook(func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *LsifStoreGetDiagnosticsFunc) nextHook() func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LsifStoreGetDiagnosticsFunc) appendCall(r0 LsifStoreGetDiagnosticsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LsifStoreGetDiagnosticsFuncCall objects
+// describing the invocations of this function.
+func (f *LsifStoreGetDiagnosticsFunc) History() []LsifStoreGetDiagnosticsFuncCall {
+	f.mutex.Lock()
+	history := make([]LsifStoreGetDiagnosticsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LsifStoreGetDiagnosticsFuncCall is an object that describes an invocation
+// of method GetDiagnostics on an instance of MockLsifStore.
+type LsifStoreGetDiagnosticsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.Diagnostic
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LsifStoreGetDiagnosticsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LsifStoreGetDiagnosticsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// LsifStoreGetDocumentSymbolsFunc describes the behavior when the
+// GetDocumentSymbols method of the parent MockLsifStore instance is
+// invoked.
+type LsifStoreGetDocumentSymbolsFunc struct {
+	defaultHook func(context.Context, int, string) ([]shared.DocumentSymbol, bool, error)
+	hooks       []func(context.Context, int, string) ([]shared.DocumentSymbol, bool, error)
+	history     []LsifStoreGetDocumentSymbolsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetDocumentSymbols delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLsifStore) GetDocumentSymbols(v0 context.Context, v1 int, v2 string) ([]shared.DocumentSymbol, bool, error) {
+	r0, r1, r2 := m.GetDocumentSymbolsFunc.nextHook()(v0, v1, v2)
+	m.GetDocumentSymbolsFunc.appendCall(LsifStoreGetDocumentSymbolsFuncCall{v0, v1, v2, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetDocumentSymbols
+// method of the parent MockLsifStore instance is invoked and the hook queue
+// is empty.
+func (f *LsifStoreGetDocumentSymbolsFunc) SetDefaultHook(hook func(context.Context, int, string) ([]shared.DocumentSymbol, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetDocumentSymbols method of the parent MockLsifStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *LsifStoreGetDocumentSymbolsFunc) PushHook(hook func(context.Context, int, string) ([]shared.DocumentSymbol, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LsifStoreGetDocumentSymbolsFunc) SetDefaultReturn(r0 []shared.DocumentSymbol, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, string) ([]shared.DocumentSymbol, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LsifStoreGetDocumentSymbolsFunc) PushReturn(r0 []shared.DocumentSymbol, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int, string) ([]shared.DocumentSymbol, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *LsifStoreGetDocumentSymbolsFunc) nextHook() func(context.Context, int, string) ([]shared.DocumentSymbol, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LsifStoreGetDocumentSymbolsFunc) appendCall(r0 LsifStoreGetDocumentSymbolsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LsifStoreGetDocumentSymbolsFuncCall objects
+// describing the invocations of this function.
+func (f *LsifStoreGetDocumentSymbolsFunc) History() []LsifStoreGetDocumentSymbolsFuncCall {
+	f.mutex.Lock()
+	history := make([]LsifStoreGetDocumentSymbolsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LsifStoreGetDocumentSymbolsFuncCall is an object that describes an
+// invocation of method GetDocumentSymbols on an instance of MockLsifStore.
+type LsifStoreGetDocumentSymbolsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.DocumentSymbol
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LsifStoreGetDocumentSymbolsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c LsifStoreGetDefinitionLocationsFuncCall) Results() []interface{} {
+func (c LsifStoreGetDocumentSymbolsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
-// LsifStoreGetDiagnosticsFunc describes the behavior when the
-// GetDiagnostics method of the parent MockLsifStore instance is invoked.
-type LsifStoreGetDiagnosticsFunc struct {
-	defaultHook func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error)
-	hooks       []func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error)
-	history     []LsifStoreGetDiagnosticsFuncCall
+// LsifStoreGetExportedSymbolsFunc describes the behavior when the
+// GetExportedSymbols method of the parent MockLsifStore instance is
+// invoked.
+type LsifStoreGetExportedSymbolsFunc struct {
+	defaultHook func(context.Context, int) ([]shared.ExportedSymbol, bool, error)
+	hooks       []func(context.Context, int) ([]shared.ExportedSymbol, bool, error)
+	history     []LsifStoreGetExportedSymbolsFuncCall
 	mutex       sync.Mutex
 }
 
-// GetDiagnostics delegates to the next hook function in the queue and
+// GetExportedSymbols delegates to the next hook function in the queue and
 // stores the parameter and result values of this invocation.
-func (m *MockLsifStore) GetDiagnostics(v0 context.Context, v1 int, v2 string, v3 int, v4 int) ([]shared.Diagnostic, int, error) {
-	r0, r1, r2 := m.GetDiagnosticsFunc.nextHook()(v0, v1, v2, v3, v4)
-	m.GetDiagnosticsFunc.appendCall(LsifStoreGetDiagnosticsFuncCall{v0, v1, v2, v3, v4, r0, r1, r2})
+func (m *MockLsifStore) GetExportedSymbols(v0 context.Context, v1 int) ([]shared.ExportedSymbol, bool, error) {
+	r0, r1, r2 := m.GetExportedSymbolsFunc.nextHook()(v0, v1)
+	m.GetExportedSymbolsFunc.appendCall(LsifStoreGetExportedSymbolsFuncCall{v0, v1, r0, r1, r2})
 	return r0, r1, r2
 }
 
-// SetDefaultHook sets function that is called when the GetDiagnostics
+// SetDefaultHook sets function that is called when the GetExportedSymbols
 // method of the parent MockLsifStore instance is invoked and the hook queue
 // is empty.
-func (f *LsifStoreGetDiagnosticsFunc) SetDefaultHook(hook func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error)) {
+func (f *LsifStoreGetExportedSymbolsFunc) SetDefaultHook(hook func(context.Context, int) ([]shared.ExportedSymbol, bool, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetDiagnostics method of the parent MockLsifStore instance invokes the
-// hook at the front of the queue and discards it. After the queue is empty,
-// the default hook function is invoked for any future action.
-func (f *LsifStoreGetDiagnosticsFunc) PushHook(hook func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error)) {
+// GetExportedSymbols method of the parent MockLsifStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *LsifStoreGetExportedSymbolsFunc) PushHook(hook func(context.Context, int) ([]shared.ExportedSymbol, bool, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -660,20 +1208,20 @@ func (f *LsifStoreGetDiagnosticsFunc) PushHook(hook func(context.Context, int, s
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *LsifStoreGetDiagnosticsFunc) SetDefaultReturn(r0 []shared.Diagnostic, r1 int, r2 error) {
-	f.SetDefaultHook(func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error) {
+func (f *LsifStoreGetExportedSymbolsFunc) SetDefaultReturn(r0 []shared.ExportedSymbol, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int) ([]shared.ExportedSymbol, bool, error) {
 		return r0, r1, r2
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *LsifStoreGetDiagnosticsFunc) PushReturn(r0 []shared.Diagnostic, r1 int, r2 error) {
-	f.PushHook(func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error) {
+func (f *LsifStoreGetExportedSymbolsFunc) PushReturn(r0 []shared.ExportedSymbol, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int) ([]shared.ExportedSymbol, bool, error) {
 		return r0, r1, r2
 	})
 }
 
-func (f *LsifStoreGetDiagnosticsFunc) nextHook() func(context.Context, int, string, int, int) ([]shared.Diagnostic, int, error) {
+func (f *LsifStoreGetExportedSymbolsFunc) nextHook() func(context.Context, int) ([]shared.ExportedSymbol, bool, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -686,47 +1234,38 @@ func (f *LsifStoreGetDiagnosticsFunc) nextHook() func(context.Context, int, stri
 	return hook
 }
 
-func (f *LsifStoreGetDiagnosticsFunc) appendCall(r0 LsifStoreGetDiagnosticsFuncCall) {
+func (f *LsifStoreGetExportedSymbolsFunc) appendCall(r0 LsifStoreGetExportedSymbolsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of LsifStoreGetDiagnosticsFuncCall objects
+// History returns a sequence of LsifStoreGetExportedSymbolsFuncCall objects
 // describing the invocations of this function.
-func (f *LsifStoreGetDiagnosticsFunc) History() []LsifStoreGetDiagnosticsFuncCall {
+func (f *LsifStoreGetExportedSymbolsFunc) History() []LsifStoreGetExportedSymbolsFuncCall {
 	f.mutex.Lock()
-	history := make([]LsifStoreGetDiagnosticsFuncCall, len(f.history))
+	history := make([]LsifStoreGetExportedSymbolsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// LsifStoreGetDiagnosticsFuncCall is an object that describes an invocation
-// of method GetDiagnostics on an instance of MockLsifStore.
-type LsifStoreGetDiagnosticsFuncCall struct {
+// LsifStoreGetExportedSymbolsFuncCall is an object that describes an
+// invocation of method GetExportedSymbols on an instance of MockLsifStore.
+type LsifStoreGetExportedSymbolsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
 	Arg1 int
-	// Arg2 is the value of the 3rd argument passed to this method
-	// invocation.
-	Arg2 string
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 int
-	// Arg4 is the value of the 5th argument passed to this method
-	// invocation.
-	Arg4 int
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []shared.Diagnostic
+	Result0 []shared.ExportedSymbol
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
-	Result1 int
+	Result1 bool
 	// Result2 is the value of the 3rd result returned from this method
 	// invocation.
 	Result2 error
@@ -734,13 +1273,13 @@ type LsifStoreGetDiagnosticsFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c LsifStoreGetDiagnosticsFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+func (c LsifStoreGetExportedSymbolsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c LsifStoreGetDiagnosticsFuncCall) Results() []interface{} {
+func (c LsifStoreGetExportedSymbolsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
@@ -1698,6 +2237,135 @@ func (c LsifStoreGetStencilFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// LsifStoreGetTypeDefinitionLocationsFunc describes the behavior when the
+// GetTypeDefinitionLocations method of the parent MockLsifStore instance is
+// invoked.
+type LsifStoreGetTypeDefinitionLocationsFunc struct {
+	defaultHook func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error)
+	hooks       []func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error)
+	history     []LsifStoreGetTypeDefinitionLocationsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetTypeDefinitionLocations delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockLsifStore) GetTypeDefinitionLocations(v0 context.Context, v1 int, v2 string, v3 int, v4 int, v5 int, v6 int) ([]shared.Location, int, error) {
+	r0, r1, r2 := m.GetTypeDefinitionLocationsFunc.nextHook()(v0, v1, v2, v3, v4, v5, v6)
+	m.GetTypeDefinitionLocationsFunc.appendCall(LsifStoreGetTypeDefinitionLocationsFuncCall{v0, v1, v2, v3, v4, v5, v6, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the
+// GetTypeDefinitionLocations method of the parent MockLsifStore instance is
+// invoked and the hook queue is empty.
+func (f *LsifStoreGetTypeDefinitionLocationsFunc) SetDefaultHook(hook func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetTypeDefinitionLocations method of the parent MockLsifStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *LsifStoreGetTypeDefinitionLocationsFunc) PushHook(hook func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LsifStoreGetTypeDefinitionLocationsFunc) SetDefaultReturn(r0 []shared.Location, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LsifStoreGetTypeDefinitionLocationsFunc) PushReturn(r0 []shared.Location, r1 int, r2 error) {
+	f.PushHook(func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *LsifStoreGetTypeDefinitionLocationsFunc) nextHook() func(context.Context, int, string, int, int, int, int) ([]shared.Location, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LsifStoreGetTypeDefinitionLocationsFunc) appendCall(r0 LsifStoreGetTypeDefinitionLocationsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LsifStoreGetTypeDefinitionLocationsFuncCall
+// objects describing the invocations of this function.
+func (f *LsifStoreGetTypeDefinitionLocationsFunc) History() []LsifStoreGetTypeDefinitionLocationsFuncCall {
+	f.mutex.Lock()
+	history := make([]LsifStoreGetTypeDefinitionLocationsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LsifStoreGetTypeDefinitionLocationsFuncCall is an object that describes
+// an invocation of method GetTypeDefinitionLocations on an instance of
+// MockLsifStore.
+type LsifStoreGetTypeDefinitionLocationsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 int
+	// Arg6 is the value of the 7th argument passed to this method
+	// invocation.
+	Arg6 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.Location
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LsifStoreGetTypeDefinitionLocationsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5, c.Arg6}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LsifStoreGetTypeDefinitionLocationsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // MockGitTreeTranslator is a mock implementation of the GitTreeTranslator
 // interface (from the package
 // github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav)
@@ -2447,6 +3115,158 @@ func (c GitserverClientDiffPathFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// MockSymbolsClient is a mock implementation of the SymbolsClient interface
+// (from the package
+// github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav)
+// used for unit testing.
+type MockSymbolsClient struct {
+	// SearchFunc is an instance of a mock function object controlling the
+	// behavior of the method Search.
+	SearchFunc *SymbolsClientSearchFunc
+}
+
+// NewMockSymbolsClient creates a new mock of the SymbolsClient interface.
+// All methods return zero values for all results, unless overwritten.
+func NewMockSymbolsClient() *MockSymbolsClient {
+	return &MockSymbolsClient{
+		SearchFunc: &SymbolsClientSearchFunc{
+			defaultHook: func(context.Context, search.SymbolsParameters) (r0 []result.Symbol, r1 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockSymbolsClient creates a new mock of the SymbolsClient
+// interface. All methods panic on invocation, unless overwritten.
+func NewStrictMockSymbolsClient() *MockSymbolsClient {
+	return &MockSymbolsClient{
+		SearchFunc: &SymbolsClientSearchFunc{
+			defaultHook: func(context.Context, search.SymbolsParameters) ([]result.Symbol, error) {
+				panic("unexpected invocation of MockSymbolsClient.Search")
+			},
+		},
+	}
+}
+
+// NewMockSymbolsClientFrom creates a new mock of the MockSymbolsClient
+// interface. All methods delegate to the given implementation, unless
+// overwritten.
+func NewMockSymbolsClientFrom(i SymbolsClient) *MockSymbolsClient {
+	return &MockSymbolsClient{
+		SearchFunc: &SymbolsClientSearchFunc{
+			defaultHook: i.Search,
+		},
+	}
+}
+
+// SymbolsClientSearchFunc describes the behavior when the Search method of
+// the parent MockSymbolsClient instance is invoked.
+type SymbolsClientSearchFunc struct {
+	defaultHook func(context.Context, search.SymbolsParameters) ([]result.Symbol, error)
+	hooks       []func(context.Context, search.SymbolsParameters) ([]result.Symbol, error)
+	history     []SymbolsClientSearchFuncCall
+	mutex       sync.Mutex
+}
+
+// Search delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockSymbolsClient) Search(v0 context.Context, v1 search.SymbolsParameters) ([]result.Symbol, error) {
+	r0, r1 := m.SearchFunc.nextHook()(v0, v1)
+	m.SearchFunc.appendCall(SymbolsClientSearchFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Search method of the
+// parent MockSymbolsClient instance is invoked and the hook queue is empty.
+func (f *SymbolsClientSearchFunc) SetDefaultHook(hook func(context.Context, search.SymbolsParameters) ([]result.Symbol, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Search method of the parent MockSymbolsClient instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *SymbolsClientSearchFunc) PushHook(hook func(context.Context, search.SymbolsParameters) ([]result.Symbol, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *SymbolsClientSearchFunc) SetDefaultReturn(r0 []result.Symbol, r1 error) {
+	f.SetDefaultHook(func(context.Context, search.SymbolsParameters) ([]result.Symbol, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *SymbolsClientSearchFunc) PushReturn(r0 []result.Symbol, r1 error) {
+	f.PushHook(func(context.Context, search.SymbolsParameters) ([]result.Symbol, error) {
+		return r0, r1
+	})
+}
+
+func (f *SymbolsClientSearchFunc) nextHook() func(context.Context, search.SymbolsParameters) ([]result.Symbol, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *SymbolsClientSearchFunc) appendCall(r0 SymbolsClientSearchFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of SymbolsClientSearchFuncCall objects
+// describing the invocations of this function.
+func (f *SymbolsClientSearchFunc) History() []SymbolsClientSearchFuncCall {
+	f.mutex.Lock()
+	history := make([]SymbolsClientSearchFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// SymbolsClientSearchFuncCall is an object that describes an invocation of
+// method Search on an instance of MockSymbolsClient.
+type SymbolsClientSearchFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 search.SymbolsParameters
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []result.Symbol
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c SymbolsClientSearchFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c SymbolsClientSearchFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // MockUploadService is a mock implementation of the UploadService interface
 // (from the package
 // github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav)
@@ -12,15 +12,21 @@ import (
 )
 
 type operations struct {
-	getReferences          *observation.Operation
-	getImplementations     *observation.Operation
-	getDiagnostics         *observation.Operation
-	getHover               *observation.Operation
-	getDefinitions         *observation.Operation
-	getRanges              *observation.Operation
-	getStencil             *observation.Operation
-	getDumpsByIDs          *observation.Operation
-	getClosestDumpsForBlob *observation.Operation
+	getReferences           *observation.Operation
+	getImplementations      *observation.Operation
+	getDiagnostics          *observation.Operation
+	getHover                *observation.Operation
+	getDefinitions          *observation.Operation
+	getTypeDefinitions      *observation.Operation
+	getIncomingCalls        *observation.Operation
+	getOutgoingCalls        *observation.Operation
+	getRanges               *observation.Operation
+	getStencil              *observation.Operation
+	getDocumentSymbols      *observation.Operation
+	getAPIDiff              *observation.Operation
+	getDownstreamReferences *observation.Operation
+	getDumpsByIDs           *observation.Operation
+	getClosestDumpsForBlob  *observation.Operation
 }
 
 var m = new(metrics.SingletonREDMetrics)
@@ -44,15 +50,21 @@ func newOperations(observationCtx *observation.Context) *operations {
 	}
 
 	return &operations{
-		getReferences:          op("getReferences"),
-		getImplementations:     op("getImplementations"),
-		getDiagnostics:         op("getDiagnostics"),
-		getHover:               op("getHover"),
-		getDefinitions:         op("getDefinitions"),
-		getRanges:              op("getRanges"),
-		getStencil:             op("getStencil"),
-		getDumpsByIDs:          op("GetDumpsByIDs"),
-		getClosestDumpsForBlob: op("GetClosestDumpsForBlob"),
+		getReferences:           op("getReferences"),
+		getImplementations:      op("getImplementations"),
+		getDiagnostics:          op("getDiagnostics"),
+		getHover:                op("getHover"),
+		getDefinitions:          op("getDefinitions"),
+		getTypeDefinitions:      op("getTypeDefinitions"),
+		getIncomingCalls:        op("getIncomingCalls"),
+		getOutgoingCalls:        op("getOutgoingCalls"),
+		getRanges:               op("getRanges"),
+		getStencil:              op("getStencil"),
+		getDocumentSymbols:      op("getDocumentSymbols"),
+		getAPIDiff:              op("getAPIDiff"),
+		getDownstreamReferences: op("getDownstreamReferences"),
+		getDumpsByIDs:           op("GetDumpsByIDs"),
+		getClosestDumpsForBlob:  op("GetClosestDumpsForBlob"),
 	}
 }
 
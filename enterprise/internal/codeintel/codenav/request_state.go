@@ -23,9 +23,10 @@ type RequestState struct {
 
 	authChecker authz.SubRepoPermissionChecker
 
-	RepositoryID int
-	Commit       string
-	Path         string
+	RepositoryID   int
+	RepositoryName string
+	Commit         string
+	Path           string
 }
 
 func NewRequestState(
@@ -39,9 +40,10 @@ func NewRequestState(
 	hunkCache HunkCache,
 ) RequestState {
 	r := &RequestState{
-		RepositoryID: int(repo.ID),
-		Commit:       commit,
-		Path:         path,
+		RepositoryID:   int(repo.ID),
+		RepositoryName: string(repo.Name),
+		Commit:         commit,
+		Path:           path,
 	}
 	r.SetUploadsDataLoader(uploads)
 	r.SetAuthChecker(authChecker)
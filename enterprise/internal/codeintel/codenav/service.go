@@ -2,10 +2,13 @@ package codenav
 
 import (
 	"context"
+	"regexp"
+	"sort"
 	"strings"
 
 	traceLog "github.com/opentracing/opentracing-go/log"
 	"github.com/sourcegraph/log"
+	"github.com/sourcegraph/scip/bindings/go/scip"
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/internal/lsifstore"
@@ -18,17 +21,20 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 type Service struct {
-	store      store.Store
-	lsifstore  lsifstore.LsifStore
-	gitserver  GitserverClient
-	uploadSvc  UploadService
-	operations *operations
-	logger     log.Logger
+	store         store.Store
+	lsifstore     lsifstore.LsifStore
+	gitserver     GitserverClient
+	uploadSvc     UploadService
+	symbolsClient SymbolsClient
+	operations    *operations
+	logger        log.Logger
 }
 
 func newService(
@@ -37,14 +43,16 @@ func newService(
 	lsifstore lsifstore.LsifStore,
 	uploadSvc UploadService,
 	gitserver GitserverClient,
+	symbolsClient SymbolsClient,
 ) *Service {
 	return &Service{
-		store:      store,
-		lsifstore:  lsifstore,
-		gitserver:  gitserver,
-		uploadSvc:  uploadSvc,
-		operations: newOperations(observationCtx),
-		logger:     log.Scoped("codenav", ""),
+		store:         store,
+		lsifstore:     lsifstore,
+		gitserver:     gitserver,
+		uploadSvc:     uploadSvc,
+		symbolsClient: symbolsClient,
+		operations:    newOperations(observationCtx),
+		logger:        log.Scoped("codenav", ""),
 	}
 }
 
@@ -189,6 +197,26 @@ func (s *Service) GetReferences(ctx context.Context, args shared.RequestArgs, re
 	})
 	defer endObservation()
 
+	locations, cursor, err := s.getReferenceLocations(ctx, args, requestState, cursor, trace)
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	// Adjust the locations back to the appropriate range in the target commits. This adjusts
+	// locations within the repository the user is browsing so that it appears all references
+	// are occurring at the same commit they are looking at.
+	referenceLocations, err := s.getUploadLocations(ctx, args, requestState, locations, true)
+	if err != nil {
+		return nil, cursor, err
+	}
+	trace.AddEvent("TODO Domain Owner", attribute.Int("numReferenceLocations", len(referenceLocations)))
+
+	return referenceLocations, cursor, nil
+}
+
+// getReferenceLocations returns the page of locations (relative to their indexed commits) that reference
+// the symbol at the given position denoted by the given cursor, along with the cursor of the next page.
+func (s *Service) getReferenceLocations(ctx context.Context, args shared.RequestArgs, requestState RequestState, cursor shared.ReferencesCursor, trace observation.TraceLogger) ([]shared.Location, shared.ReferencesCursor, error) {
 	// Adjust the path and position for each visible upload based on its git difference to
 	// the target commit. This data may already be stashed in the cursor decoded above, in
 	// which case we don't need to hit the database.
@@ -279,16 +307,7 @@ func (s *Service) GetReferences(ctx context.Context, args shared.RequestArgs, re
 
 	trace.AddEvent("TODO Domain Owner", attribute.Int("numLocations", len(locations)))
 
-	// Adjust the locations back to the appropriate range in the target commits. This adjusts
-	// locations within the repository the user is browsing so that it appears all references
-	// are occurring at the same commit they are looking at.
-	referenceLocations, err := s.getUploadLocations(ctx, args, requestState, locations, true)
-	if err != nil {
-		return nil, cursor, err
-	}
-	trace.AddEvent("TODO Domain Owner", attribute.Int("numReferenceLocations", len(referenceLocations)))
-
-	return referenceLocations, cursor, nil
+	return locations, cursor, nil
 }
 
 // getUploadsWithDefinitionsForMonikers returns the set of uploads that provide any of the given monikers.
@@ -796,13 +815,71 @@ func (s *Service) GetDefinitions(ctx context.Context, args shared.RequestArgs, r
 		return nil, err
 	}
 
+	locations, err := s.getDefinitionLocations(ctx, s.lsifstore.GetDefinitionLocations, visibleUploads, precise.Import, requestState, trace)
+	if err != nil {
+		return nil, err
+	}
+
+	// Adjust the locations back to the appropriate range in the target commits. This adjusts
+	// locations within the repository the user is browsing so that it appears all definitions
+	// are occurring at the same commit they are looking at.
+
+	adjustedLocations, err := s.getUploadLocations(ctx, args, requestState, locations, true)
+	if err != nil {
+		return nil, err
+	}
+	trace.AddEvent("TODO Domain Owner", attribute.Int("numAdjustedLocations", len(adjustedLocations)))
+
+	return adjustedLocations, nil
+}
+
+// GetTypeDefinitions returns the set of locations defining the type of the symbol at the given position.
+func (s *Service) GetTypeDefinitions(ctx context.Context, args shared.RequestArgs, requestState RequestState) (_ []types.UploadLocation, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getTypeDefinitions, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", args.RepositoryID),
+			traceLog.String("commit", args.Commit),
+			traceLog.String("path", args.Path),
+			traceLog.Int("numUploads", len(requestState.GetCacheUploads())),
+			traceLog.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+			traceLog.Int("line", args.Line),
+			traceLog.Int("character", args.Character),
+		},
+	})
+	defer endObservation()
+
+	// Adjust the path and position for each visible upload based on its git difference to
+	// the target commit.
+	visibleUploads, err := s.getVisibleUploads(ctx, args.Line, args.Character, requestState)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := s.getDefinitionLocations(ctx, s.lsifstore.GetTypeDefinitionLocations, visibleUploads, precise.TypeDefinition, requestState, trace)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustedLocations, err := s.getUploadLocations(ctx, args, requestState, locations, true)
+	if err != nil {
+		return nil, err
+	}
+	trace.AddEvent("TODO Domain Owner", attribute.Int("numAdjustedLocations", len(adjustedLocations)))
+
+	return adjustedLocations, nil
+}
+
+// getDefinitionLocations returns the set of locations (relative to their indexed commits) returned by
+// the given function for one of the visible uploads. If none of the visible uploads have any, then the
+// definitions of the monikers of the given kind attached to the target position are returned instead.
+func (s *Service) getDefinitionLocations(ctx context.Context, getLocations getLocationsFn, visibleUploads []visibleUpload, monikerKind string, requestState RequestState, trace observation.TraceLogger) ([]shared.Location, error) {
 	// Gather the "local" reference locations that are reachable via a referenceResult vertex.
 	// If the definition exists within the index, it should be reachable via an LSIF graph
 	// traversal and should not require an additional moniker search in the same index.
 	for i := range visibleUploads {
 		trace.AddEvent("TODO Domain Owner", attribute.Int("uploadID", visibleUploads[i].Upload.ID))
 
-		locations, _, err := s.lsifstore.GetDefinitionLocations(
+		locations, _, err := getLocations(
 			ctx,
 			visibleUploads[i].Upload.ID,
 			visibleUploads[i].TargetPathWithoutRoot,
@@ -816,12 +893,12 @@ func (s *Service) GetDefinitions(ctx context.Context, args shared.RequestArgs, r
 		}
 		if len(locations) > 0 {
 			// If we have a local definition, we won't find a better one and can exit early
-			return s.getUploadLocations(ctx, args, requestState, locations, true)
+			return locations, nil
 		}
 	}
 
-	// Gather all import monikers attached to the ranges enclosing the requested position
-	orderedMonikers, err := s.getOrderedMonikers(ctx, visibleUploads, "import")
+	// Gather all monikers of the given kind attached to the ranges enclosing the requested position
+	orderedMonikers, err := s.getOrderedMonikers(ctx, visibleUploads, monikerKind)
 	if err != nil {
 		return nil, err
 	}
@@ -847,17 +924,193 @@ func (s *Service) GetDefinitions(ctx context.Context, args shared.RequestArgs, r
 	}
 	trace.AddEvent("TODO Domain Owner", attribute.Int("numXrepoLocations", len(locations)))
 
-	// Adjust the locations back to the appropriate range in the target commits. This adjusts
-	// locations within the repository the user is browsing so that it appears all definitions
-	// are occurring at the same commit they are looking at.
+	return locations, nil
+}
 
-	adjustedLocations, err := s.getUploadLocations(ctx, args, requestState, locations, true)
+// GetIncomingCalls returns the calls to the callable symbol at the given position from the callables
+// enclosing its references. Calls are resolved from the page of references denoted by the given cursor,
+// so calls from the same caller may be split over several pages.
+func (s *Service) GetIncomingCalls(ctx context.Context, args shared.RequestArgs, requestState RequestState, cursor shared.ReferencesCursor) (_ []shared.AdjustedCallHierarchyCall, _ shared.ReferencesCursor, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getIncomingCalls, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", args.RepositoryID),
+			traceLog.String("commit", args.Commit),
+			traceLog.String("path", args.Path),
+			traceLog.Int("numUploads", len(requestState.GetCacheUploads())),
+			traceLog.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+			traceLog.Int("line", args.Line),
+			traceLog.Int("character", args.Character),
+		},
+	})
+	defer endObservation()
+
+	locations, cursor, err := s.getReferenceLocations(ctx, args, requestState, cursor, trace)
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	// Group the references by document so that we only read each document once
+	type documentKey struct {
+		dumpID int
+		path   string
+	}
+	var documentKeys []documentKey
+	rangesByDocument := map[documentKey][]types.Range{}
+	for _, location := range locations {
+		key := documentKey{dumpID: location.DumpID, path: location.Path}
+		if _, ok := rangesByDocument[key]; !ok {
+			documentKeys = append(documentKeys, key)
+		}
+		rangesByDocument[key] = append(rangesByDocument[key], location.Range)
+	}
+
+	var calls []shared.CallHierarchyCall
+	for _, key := range documentKeys {
+		callers, err := s.lsifstore.GetCallers(ctx, key.dumpID, key.path, rangesByDocument[key])
+		if err != nil {
+			return nil, cursor, errors.Wrap(err, "lsifStore.GetCallers")
+		}
+		calls = append(calls, callers...)
+	}
+	trace.AddEvent("TODO Domain Owner", attribute.Int("numCalls", len(calls)))
+
+	adjustedCalls, err := s.getAdjustedCallHierarchyCalls(ctx, args, requestState, calls)
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	return adjustedCalls, cursor, nil
+}
+
+// GetOutgoingCalls returns the calls from the callable symbol at the given position to the callables
+// it references. Callees defined in other indexes are resolved via moniker search.
+func (s *Service) GetOutgoingCalls(ctx context.Context, args shared.RequestArgs, requestState RequestState) (_ []shared.AdjustedCallHierarchyCall, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getOutgoingCalls, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", args.RepositoryID),
+			traceLog.String("commit", args.Commit),
+			traceLog.String("path", args.Path),
+			traceLog.Int("numUploads", len(requestState.GetCacheUploads())),
+			traceLog.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+			traceLog.Int("line", args.Line),
+			traceLog.Int("character", args.Character),
+		},
+	})
+	defer endObservation()
+
+	visibleUploads, err := s.getVisibleUploads(ctx, args.Line, args.Character, requestState)
 	if err != nil {
 		return nil, err
 	}
-	trace.AddEvent("TODO Domain Owner", attribute.Int("numAdjustedXrepoLocations", len(adjustedLocations)))
 
-	return adjustedLocations, nil
+	// The callees are referenced within the definition of the callable, which may be in another index
+	definitions, err := s.getDefinitionLocations(ctx, s.lsifstore.GetDefinitionLocations, visibleUploads, precise.Import, requestState, trace)
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []shared.CallHierarchyCall
+	for _, definition := range definitions {
+		callees, err := s.lsifstore.GetCallees(ctx, definition.DumpID, definition.Path, definition.Range.Start.Line, definition.Range.Start.Character)
+		if err != nil {
+			return nil, errors.Wrap(err, "lsifStore.GetCallees")
+		}
+
+		for i := range callees {
+			if callees[i].Definition.DumpID != 0 {
+				continue
+			}
+
+			if callees[i].Definition, err = s.getRemoteCalleeDefinition(ctx, callees[i], requestState); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(callees) > 0 {
+			// Definitions in several indexes describe the same callable
+			calls = callees
+			break
+		}
+	}
+	trace.AddEvent("TODO Domain Owner", attribute.Int("numCalls", len(calls)))
+
+	return s.getAdjustedCallHierarchyCalls(ctx, args, requestState, calls)
+}
+
+// getRemoteCalleeDefinition returns the definition of the given callee from another index via moniker
+// search, or a zero-valued location if it is unknown.
+func (s *Service) getRemoteCalleeDefinition(ctx context.Context, call shared.CallHierarchyCall, requestState RequestState) (shared.Location, error) {
+	dump, ok := requestState.dataLoader.GetUploadFromCacheMap(call.CallSites[0].DumpID)
+	if !ok {
+		return shared.Location{}, nil
+	}
+
+	monikers, err := s.getOrderedMonikers(ctx, []visibleUpload{{
+		Upload:                dump,
+		TargetPath:            dump.Root + call.CallSites[0].Path,
+		TargetPosition:        call.CallSites[0].Range.Start,
+		TargetPathWithoutRoot: call.CallSites[0].Path,
+	}}, precise.Import, precise.Export)
+	if err != nil {
+		return shared.Location{}, err
+	}
+
+	// Other symbols may be attached to the call site as well
+	filtered := monikers[:0]
+	for _, moniker := range monikers {
+		if moniker.Identifier == call.Symbol {
+			filtered = append(filtered, moniker)
+		}
+	}
+	if len(filtered) == 0 {
+		return shared.Location{}, nil
+	}
+
+	uploads, err := s.getUploadsWithDefinitionsForMonikers(ctx, filtered, requestState)
+	if err != nil {
+		return shared.Location{}, err
+	}
+
+	locations, _, err := s.getBulkMonikerLocations(ctx, uploads, filtered, "definitions", 1, 0)
+	if err != nil || len(locations) == 0 {
+		return shared.Location{}, err
+	}
+
+	return locations[0], nil
+}
+
+// getAdjustedCallHierarchyCalls translates the locations of the given calls into equivalent locations
+// in the requested commit. Calls without any visible call sites are dropped.
+func (s *Service) getAdjustedCallHierarchyCalls(ctx context.Context, args shared.RequestArgs, requestState RequestState, calls []shared.CallHierarchyCall) ([]shared.AdjustedCallHierarchyCall, error) {
+	adjustedCalls := make([]shared.AdjustedCallHierarchyCall, 0, len(calls))
+	for _, call := range calls {
+		callSites, err := s.getUploadLocations(ctx, args, requestState, call.CallSites, true)
+		if err != nil {
+			return nil, err
+		}
+		if len(callSites) == 0 {
+			continue
+		}
+
+		var definition *types.UploadLocation
+		if call.Definition.DumpID != 0 {
+			definitions, err := s.getUploadLocations(ctx, args, requestState, []shared.Location{call.Definition}, true)
+			if err != nil {
+				return nil, err
+			}
+			if len(definitions) > 0 {
+				definition = &definitions[0]
+			}
+		}
+
+		adjustedCalls = append(adjustedCalls, shared.AdjustedCallHierarchyCall{
+			Symbol:     call.Symbol,
+			Definition: definition,
+			CallSites:  callSites,
+		})
+	}
+
+	return adjustedCalls, nil
 }
 
 func (s *Service) GetDiagnostics(ctx context.Context, args shared.RequestArgs, requestState RequestState) (diagnosticsAtUploads []shared.DiagnosticAtUpload, _ int, err error) {
@@ -1125,6 +1378,397 @@ func (s *Service) GetStencil(ctx context.Context, args shared.RequestArgs, reque
 	return dedupeRanges(sortedRanges), nil
 }
 
+// maximumSearchBasedDocumentSymbols is the maximum number of symbols requested from the symbols
+// service for a document not covered by a SCIP index.
+const maximumSearchBasedDocumentSymbols = 1000
+
+// GetDocumentSymbols returns the hierarchy of symbols defined in the given document. Symbols are read from
+// the first SCIP index covering the document and, if there is none, from the symbols service. The returned
+// flag is true if the symbols are precise.
+func (s *Service) GetDocumentSymbols(ctx context.Context, args shared.RequestArgs, requestState RequestState) (_ []shared.DocumentSymbol, _ bool, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getDocumentSymbols, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", args.RepositoryID),
+			traceLog.String("commit", args.Commit),
+			traceLog.String("path", args.Path),
+			traceLog.Int("numUploads", len(requestState.GetCacheUploads())),
+			traceLog.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+		},
+	})
+	defer endObservation()
+
+	adjustedUploads, err := s.getUploadPaths(ctx, args.Path, requestState)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for i := range adjustedUploads {
+		trace.AddEvent("TODO Domain Owner", attribute.Int("uploadID", adjustedUploads[i].Upload.ID))
+
+		symbols, ok, err := s.lsifstore.GetDocumentSymbols(
+			ctx,
+			adjustedUploads[i].Upload.ID,
+			adjustedUploads[i].TargetPathWithoutRoot,
+		)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "lsifStore.GetDocumentSymbols")
+		}
+		if !ok {
+			// Not a SCIP index
+			continue
+		}
+
+		adjustedSymbols, err := s.adjustDocumentSymbols(ctx, args, requestState, adjustedUploads[i].Upload, symbols)
+		if err != nil {
+			return nil, false, err
+		}
+		trace.AddEvent("TODO Domain Owner", attribute.Int("numSymbols", len(adjustedSymbols)))
+
+		return adjustedSymbols, true, nil
+	}
+
+	searchBasedSymbols, err := s.symbolsClient.Search(ctx, search.SymbolsParameters{
+		Repo:            api.RepoName(requestState.RepositoryName),
+		CommitID:        api.CommitID(args.Commit),
+		IsCaseSensitive: true,
+		IncludePatterns: []string{"^" + regexp.QuoteMeta(args.Path) + "$"},
+		First:           maximumSearchBasedDocumentSymbols,
+	})
+	if err != nil {
+		return nil, false, errors.Wrap(err, "symbolsClient.Search")
+	}
+	trace.AddEvent("TODO Domain Owner", attribute.Int("numSearchBasedSymbols", len(searchBasedSymbols)))
+
+	return searchBasedDocumentSymbols(searchBasedSymbols), false, nil
+}
+
+// adjustDocumentSymbols translates the ranges of the given symbols (relative to the indexed commit) into
+// equivalent ranges in the requested commit. Symbols whose ranges cannot be translated are dropped along
+// with their children.
+func (s *Service) adjustDocumentSymbols(ctx context.Context, args shared.RequestArgs, requestState RequestState, upload types.Dump, symbols []shared.DocumentSymbol) ([]shared.DocumentSymbol, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	adjustedSymbols := make([]shared.DocumentSymbol, 0, len(symbols))
+	for _, symbol := range symbols {
+		_, adjustedRange, ok, err := s.getSourceRange(ctx, args, requestState, upload.RepositoryID, upload.Commit, args.Path, symbol.Range)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		children, err := s.adjustDocumentSymbols(ctx, args, requestState, upload, symbol.Children)
+		if err != nil {
+			return nil, err
+		}
+
+		symbol.Range = adjustedRange
+		symbol.Children = children
+		adjustedSymbols = append(adjustedSymbols, symbol)
+	}
+
+	return adjustedSymbols, nil
+}
+
+// searchBasedDocumentSymbols returns the hierarchy of the given symbols found by the symbols service. A
+// symbol is nested under the symbol named by its parent, preferring a symbol whose qualified name matches
+// the parent. Symbols at each level are ordered by range.
+func searchBasedDocumentSymbols(symbols result.Symbols) []shared.DocumentSymbol {
+	qualifiedNames := make(map[string]int, len(symbols))
+	names := make(map[string]int, len(symbols))
+	for i, symbol := range symbols {
+		qualifiedName := symbol.Name
+		if symbol.Parent != "" {
+			qualifiedName = symbol.Parent + "." + symbol.Name
+		}
+		if _, ok := qualifiedNames[qualifiedName]; !ok {
+			qualifiedNames[qualifiedName] = i
+		}
+		if _, ok := names[symbol.Name]; !ok {
+			names[symbol.Name] = i
+		}
+	}
+
+	var roots []int
+	children := make([][]int, len(symbols))
+	for i, symbol := range symbols {
+		parent, ok := -1, false
+		if symbol.Parent != "" {
+			if parent, ok = qualifiedNames[symbol.Parent]; !ok {
+				parentName := symbol.Parent
+				if j := strings.LastIndex(parentName, "."); j >= 0 {
+					parentName = parentName[j+1:]
+				}
+				parent, ok = names[parentName]
+			}
+		}
+
+		if ok && parent != i {
+			children[parent] = append(children[parent], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	return resolveSearchBasedDocumentSymbols(symbols, children, roots)
+}
+
+// resolveSearchBasedDocumentSymbols converts the symbols at the given indexes, with their children attached,
+// ordered by range.
+func resolveSearchBasedDocumentSymbols(symbols result.Symbols, children [][]int, indexes []int) []shared.DocumentSymbol {
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	resolved := make([]shared.DocumentSymbol, 0, len(indexes))
+	for _, i := range indexes {
+		kind := "UNKNOWN"
+		if lspKind := symbols[i].LSPKind(); lspKind != 0 {
+			kind = strings.ToUpper(lspKind.String())
+		}
+		r := symbols[i].Range()
+
+		resolved = append(resolved, shared.DocumentSymbol{
+			Name: symbols[i].Name,
+			Kind: kind,
+			Range: types.Range{
+				Start: types.Position{Line: r.Start.Line, Character: r.Start.Character},
+				End:   types.Position{Line: r.End.Line, Character: r.End.Character},
+			},
+			Children: resolveSearchBasedDocumentSymbols(symbols, children, children[i]),
+		})
+	}
+	sort.SliceStable(resolved, func(i, j int) bool {
+		if resolved[i].Range.Start.Line == resolved[j].Range.Start.Line {
+			return resolved[i].Range.Start.Character < resolved[j].Range.Start.Character
+		}
+		return resolved[i].Range.Start.Line < resolved[j].Range.Start.Line
+	})
+
+	return resolved
+}
+
+// GetAPIDiff compares the exported symbols defined in the SCIP uploads of the given base and head commits.
+// Only uploads of exactly the given commits are compared, as an upload visible from an ancestor commit does
+// not describe the API at the commit of interest. The returned flag is false if either commit has no such
+// upload.
+func (s *Service) GetAPIDiff(ctx context.Context, repositoryID int, baseCommit, headCommit string) (_ shared.APIDiff, _ bool, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getAPIDiff, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", repositoryID),
+			traceLog.String("baseCommit", baseCommit),
+			traceLog.String("headCommit", headCommit),
+		},
+	})
+	defer endObservation()
+
+	baseUploads, baseSymbols, err := s.getExportedSymbols(ctx, repositoryID, baseCommit)
+	if err != nil || len(baseUploads) == 0 {
+		return shared.APIDiff{}, false, err
+	}
+	trace.AddEvent("TODO Domain Owner",
+		attribute.String("baseUploads", uploadIDsToString(baseUploads)),
+		attribute.Int("numBaseSymbols", len(baseSymbols)))
+
+	headUploads, headSymbols, err := s.getExportedSymbols(ctx, repositoryID, headCommit)
+	if err != nil || len(headUploads) == 0 {
+		return shared.APIDiff{}, false, err
+	}
+	trace.AddEvent("TODO Domain Owner",
+		attribute.String("headUploads", uploadIDsToString(headUploads)),
+		attribute.Int("numHeadSymbols", len(headSymbols)))
+
+	diff := diffAPISymbols(baseSymbols, headSymbols)
+	diff.BaseUploads = baseUploads
+	diff.HeadUploads = headUploads
+	trace.AddEvent("TODO Domain Owner",
+		attribute.Int("numAdded", len(diff.Added)),
+		attribute.Int("numRemoved", len(diff.Removed)),
+		attribute.Int("numChanged", len(diff.Changed)))
+
+	return diff, true, nil
+}
+
+// getExportedSymbols returns the SCIP uploads of exactly the given commit along with the exported symbols
+// defined within them, keyed by apiSymbolKey. A symbol defined in multiple uploads is attributed to the
+// first upload defining it.
+func (s *Service) getExportedSymbols(ctx context.Context, repositoryID int, commit string) ([]types.Dump, map[string]shared.APISymbol, error) {
+	candidates, err := s.uploadSvc.InferClosestUploads(ctx, repositoryID, commit, "", false, "")
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "uploadSvc.InferClosestUploads")
+	}
+
+	var uploads []types.Dump
+	symbols := map[string]shared.APISymbol{}
+	for _, upload := range candidates {
+		if upload.Commit != commit {
+			continue
+		}
+
+		exportedSymbols, ok, err := s.lsifstore.GetExportedSymbols(ctx, upload.ID)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "lsifStore.GetExportedSymbols")
+		}
+		if !ok {
+			// Not a SCIP index
+			continue
+		}
+		uploads = append(uploads, upload)
+
+		for _, symbol := range exportedSymbols {
+			key := apiSymbolKey(symbol.Symbol)
+			if _, ok := symbols[key]; ok {
+				continue
+			}
+
+			symbols[key] = shared.APISymbol{
+				Symbol:    symbol.Symbol,
+				Signature: symbol.Signature,
+				Definition: types.UploadLocation{
+					Dump:         upload,
+					Path:         upload.Root + symbol.Definition.Path,
+					TargetCommit: upload.Commit,
+					TargetRange:  symbol.Definition.Range,
+				},
+			}
+		}
+	}
+
+	return uploads, symbols, nil
+}
+
+// versionlessSymbolFormatter formats SCIP symbols without their package version, which commonly
+// changes between the commits of a package (e.g., Go pseudo-versions).
+var versionlessSymbolFormatter = scip.SymbolFormatter{
+	OnError:               func(err error) error { return err },
+	IncludeScheme:         func(_ string) bool { return true },
+	IncludePackageManager: func(_ string) bool { return true },
+	IncludePackageName:    func(_ string) bool { return true },
+	IncludePackageVersion: func(_ string) bool { return false },
+	IncludeDescriptor:     func(_ string) bool { return true },
+}
+
+// apiSymbolKey returns the key identifying the given symbol across versions of its package.
+func apiSymbolKey(symbol string) string {
+	key, err := versionlessSymbolFormatter.Format(symbol)
+	if err != nil {
+		return symbol
+	}
+
+	return key
+}
+
+// diffAPISymbols returns the symbols added to, removed from, and changed between the given sets of exported
+// symbols, each ordered by symbol. A symbol has changed if its signature differs between the two sets. Symbols
+// lacking a signature in either set are never considered changed.
+func diffAPISymbols(base, head map[string]shared.APISymbol) shared.APIDiff {
+	var diff shared.APIDiff
+	for key, baseSymbol := range base {
+		headSymbol, ok := head[key]
+		if !ok {
+			diff.Removed = append(diff.Removed, baseSymbol)
+			continue
+		}
+
+		if baseSymbol.Signature != "" && headSymbol.Signature != "" && baseSymbol.Signature != headSymbol.Signature {
+			diff.Changed = append(diff.Changed, shared.APISymbolChange{Base: baseSymbol, Head: headSymbol})
+		}
+	}
+	for key, headSymbol := range head {
+		if _, ok := base[key]; !ok {
+			diff.Added = append(diff.Added, headSymbol)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Symbol < diff.Added[j].Symbol })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Symbol < diff.Removed[j].Symbol })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Head.Symbol < diff.Changed[j].Head.Symbol })
+
+	return diff
+}
+
+// GetDownstreamReferences returns the page of locations in other repositories that reference the given symbol,
+// denoted by the given cursor, along with the cursor of the next page. The uploads of the given request state
+// should be those defining the symbol. The returned flag is false once there are no pages remaining.
+func (s *Service) GetDownstreamReferences(ctx context.Context, args shared.RequestArgs, requestState RequestState, symbol string, cursor shared.RemoteCursor) (_ []types.UploadLocation, _ shared.RemoteCursor, _ bool, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getDownstreamReferences, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", args.RepositoryID),
+			traceLog.String("commit", args.Commit),
+			traceLog.String("symbol", symbol),
+			traceLog.Int("numUploads", len(requestState.GetCacheUploads())),
+			traceLog.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+		},
+	})
+	defer endObservation()
+
+	moniker, err := symbolMoniker(symbol)
+	if err != nil {
+		return nil, cursor, false, err
+	}
+	orderedMonikers := []precise.QualifiedMonikerData{moniker}
+
+	// The defining uploads are excluded from the moniker search
+	definitionUploads := make([]visibleUpload, 0, len(requestState.GetCacheUploads()))
+	for _, upload := range requestState.GetCacheUploads() {
+		definitionUploads = append(definitionUploads, visibleUpload{Upload: upload})
+	}
+
+	var locations []shared.Location
+	hasMore := true
+	for hasMore && len(locations) < args.Limit {
+		remoteLocations, more, err := s.getPageRemoteLocations(ctx, "references", definitionUploads, orderedMonikers, &cursor, args.Limit-len(locations), trace, args, requestState)
+		if err != nil {
+			return nil, cursor, false, err
+		}
+		locations = append(locations, remoteLocations...)
+		hasMore = more
+	}
+
+	// References from the defining repository are not downstream of the symbol
+	filtered := locations[:0]
+	for _, location := range locations {
+		if upload, ok := requestState.dataLoader.GetUploadFromCacheMap(location.DumpID); ok && upload.RepositoryID != args.RepositoryID {
+			filtered = append(filtered, location)
+		}
+	}
+
+	referenceLocations, err := s.getUploadLocations(ctx, args, requestState, filtered, true)
+	if err != nil {
+		return nil, cursor, false, err
+	}
+	trace.AddEvent("TODO Domain Owner", attribute.Int("numReferenceLocations", len(referenceLocations)))
+
+	return referenceLocations, cursor, hasMore, nil
+}
+
+// symbolMoniker returns the moniker by which indexes of other repositories import the given SCIP symbol.
+func symbolMoniker(symbol string) (precise.QualifiedMonikerData, error) {
+	parsedSymbol, err := scip.ParseSymbol(symbol)
+	if err != nil {
+		return precise.QualifiedMonikerData{}, err
+	}
+	if parsedSymbol.Package == nil {
+		return precise.QualifiedMonikerData{}, errors.Newf("symbol %q has no package", symbol)
+	}
+
+	return precise.QualifiedMonikerData{
+		MonikerData: precise.MonikerData{
+			Scheme:     parsedSymbol.Scheme,
+			Kind:       "import",
+			Identifier: symbol,
+		},
+		PackageInformationData: precise.PackageInformationData{
+			Manager: parsedSymbol.Package.Manager,
+			Name:    parsedSymbol.Package.Name,
+			Version: parsedSymbol.Package.Version,
+		},
+	}, nil
+}
+
 func (s *Service) GetDumpsByIDs(ctx context.Context, ids []int) (_ []types.Dump, err error) {
 	ctx, _, endObservation := s.operations.getDumpsByIDs.With(ctx, &err, observation.Args{})
 	defer endObservation(1, observation.Args{})
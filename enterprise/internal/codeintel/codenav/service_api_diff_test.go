@@ -0,0 +1,210 @@
+package codenav
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	codeintelgitserver "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/gitserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	sgtypes "github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+func TestAPIDiff(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := NewMockGitserverClient()
+
+	// Init service
+	svc := newService(&observation.TestContext, mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil)
+
+	baseUploads := []types.Dump{
+		{ID: 50, RepositoryID: 42, Commit: "base", Root: "lib/"},
+		{ID: 51, RepositoryID: 42, Commit: "ancestor", Root: "cmd/"},
+	}
+	headUploads := []types.Dump{
+		{ID: 52, RepositoryID: 42, Commit: "head", Root: "lib/"},
+		{ID: 53, RepositoryID: 42, Commit: "head", Root: "web/"},
+	}
+	mockUploadSvc.InferClosestUploadsFunc.SetDefaultHook(func(_ context.Context, _ int, commit, _ string, _ bool, _ string) ([]types.Dump, error) {
+		if commit == "base" {
+			return baseUploads, nil
+		}
+		return headUploads, nil
+	})
+
+	const (
+		greeterSymbol = "scip-go gomod github.com/example/greeter %s `github.com/example/greeter`/Greeter#"
+		greetSymbol   = "scip-go gomod github.com/example/greeter %s `github.com/example/greeter`/Greeter#Greet()."
+		nameSymbol    = "scip-go gomod github.com/example/greeter %s `github.com/example/greeter`/Greeter#Name."
+		newSymbol     = "scip-go gomod github.com/example/greeter %s `github.com/example/greeter`/New()."
+	)
+	mockLsifStore.GetExportedSymbolsFunc.SetDefaultHook(func(_ context.Context, uploadID int) ([]shared.ExportedSymbol, bool, error) {
+		switch uploadID {
+		case 50:
+			return []shared.ExportedSymbol{
+				{Symbol: fmt.Sprintf(greeterSymbol, "v1.0.0"), Signature: "type Greeter struct", Definition: shared.Location{DumpID: 50, Path: "greeter.go", Range: testRange1}},
+				{Symbol: fmt.Sprintf(greetSymbol, "v1.0.0"), Signature: "func (g *Greeter) Greet()", Definition: shared.Location{DumpID: 50, Path: "greeter.go", Range: testRange2}},
+				{Symbol: fmt.Sprintf(nameSymbol, "v1.0.0"), Definition: shared.Location{DumpID: 50, Path: "greeter.go", Range: testRange3}},
+			}, true, nil
+		case 52:
+			return []shared.ExportedSymbol{
+				{Symbol: fmt.Sprintf(greeterSymbol, "v1.1.0"), Signature: "type Greeter struct", Definition: shared.Location{DumpID: 52, Path: "greeter.go", Range: testRange1}},
+				{Symbol: fmt.Sprintf(greetSymbol, "v1.1.0"), Signature: "func (g *Greeter) Greet(greeting string)", Definition: shared.Location{DumpID: 52, Path: "greeter.go", Range: testRange2}},
+				{Symbol: fmt.Sprintf(nameSymbol, "v1.1.0"), Signature: "field Name string", Definition: shared.Location{DumpID: 52, Path: "greeter.go", Range: testRange3}},
+				{Symbol: fmt.Sprintf(newSymbol, "v1.1.0"), Signature: "func New() *Greeter", Definition: shared.Location{DumpID: 52, Path: "new.go", Range: testRange4}},
+			}, true, nil
+		}
+
+		// Not a SCIP index
+		return nil, false, nil
+	})
+
+	baseGreetSymbol := shared.APISymbol{
+		Symbol:     fmt.Sprintf(greetSymbol, "v1.0.0"),
+		Signature:  "func (g *Greeter) Greet()",
+		Definition: types.UploadLocation{Dump: baseUploads[0], Path: "lib/greeter.go", TargetCommit: "base", TargetRange: testRange2},
+	}
+	headGreetSymbol := shared.APISymbol{
+		Symbol:     fmt.Sprintf(greetSymbol, "v1.1.0"),
+		Signature:  "func (g *Greeter) Greet(greeting string)",
+		Definition: types.UploadLocation{Dump: headUploads[0], Path: "lib/greeter.go", TargetCommit: "head", TargetRange: testRange2},
+	}
+
+	diff, ok, err := svc.GetAPIDiff(context.Background(), 42, "base", "head")
+	if err != nil {
+		t.Fatalf("unexpected error querying API diff: %s", err)
+	}
+	if !ok {
+		t.Fatalf("expected API diff")
+	}
+
+	expectedDiff := shared.APIDiff{
+		BaseUploads: baseUploads[:1],
+		HeadUploads: headUploads[:1],
+		Added: []shared.APISymbol{
+			{
+				Symbol:     fmt.Sprintf(newSymbol, "v1.1.0"),
+				Signature:  "func New() *Greeter",
+				Definition: types.UploadLocation{Dump: headUploads[0], Path: "lib/new.go", TargetCommit: "head", TargetRange: testRange4},
+			},
+		},
+		Changed: []shared.APISymbolChange{
+			{Base: baseGreetSymbol, Head: headGreetSymbol},
+		},
+	}
+	if diff := cmp.Diff(expectedDiff, diff); diff != "" {
+		t.Errorf("unexpected API diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestAPIDiffWithoutSCIPUploads(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := NewMockGitserverClient()
+
+	// Init service
+	svc := newService(&observation.TestContext, mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil)
+
+	mockUploadSvc.InferClosestUploadsFunc.PushReturn([]types.Dump{{ID: 50, RepositoryID: 42, Commit: "base"}}, nil)
+	mockUploadSvc.InferClosestUploadsFunc.PushReturn([]types.Dump{{ID: 51, RepositoryID: 42, Commit: "head"}}, nil)
+	mockLsifStore.GetExportedSymbolsFunc.PushReturn([]shared.ExportedSymbol{{Symbol: "scip-go gomod example v1 `example`/Foo#"}}, true, nil)
+	mockLsifStore.GetExportedSymbolsFunc.PushReturn(nil, false, nil)
+
+	if _, ok, err := svc.GetAPIDiff(context.Background(), 42, "base", "head"); err != nil {
+		t.Fatalf("unexpected error querying API diff: %s", err)
+	} else if ok {
+		t.Fatalf("expected no API diff")
+	}
+}
+
+func TestDownstreamReferences(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitServer := codeintelgitserver.New(&observation.TestContext, database.NewMockDB())
+	hunkCache, _ := NewHunkCache(50)
+
+	// Init service
+	svc := newService(&observation.TestContext, mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil)
+
+	// Set up request state with the upload defining the symbol
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &sgtypes.Repo{}, mockCommit, "", hunkCache)
+	uploads := []types.Dump{
+		{ID: 50, RepositoryID: 42, Commit: mockCommit, Root: "lib/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	referenceUploads := []types.Dump{
+		{ID: 250, RepositoryID: 43, Commit: "deadbeef1", Root: "sub1/"},
+		{ID: 251, RepositoryID: 42, Commit: "deadbeef2", Root: "cmd/"},
+	}
+	mockUploadSvc.GetUploadIDsWithReferencesFunc.PushReturn([]int{250, 251}, 2, 2, nil)
+	mockUploadSvc.GetDumpsByIDsFunc.PushReturn(referenceUploads, nil)
+	mockGitserverClient.CommitsExistFunc.SetDefaultHook(func(ctx context.Context, rcs []codeintelgitserver.RepositoryCommit) (exists []bool, _ error) {
+		for range rcs {
+			exists = append(exists, true)
+		}
+		return
+	})
+
+	monikerLocations := []shared.Location{
+		{DumpID: 250, Path: "a.go", Range: testRange1},
+		{DumpID: 251, Path: "b.go", Range: testRange2},
+		{DumpID: 250, Path: "c.go", Range: testRange3},
+	}
+	mockLsifStore.GetBulkMonikerLocationsFunc.PushReturn(monikerLocations, 3, nil)
+
+	const symbol = "scip-go gomod github.com/example/greeter v1.0.0 `github.com/example/greeter`/Greeter#Greet()."
+	mockRequest := shared.RequestArgs{
+		RepositoryID: 42,
+		Commit:       mockCommit,
+		Limit:        50,
+	}
+	locations, _, hasMore, err := svc.GetDownstreamReferences(context.Background(), mockRequest, mockRequestState, symbol, shared.RemoteCursor{})
+	if err != nil {
+		t.Fatalf("unexpected error querying downstream references: %s", err)
+	}
+	if hasMore {
+		t.Errorf("expected no more downstream references")
+	}
+
+	expectedLocations := []types.UploadLocation{
+		{Dump: referenceUploads[0], Path: "sub1/a.go", TargetCommit: "deadbeef1", TargetRange: testRange1},
+		{Dump: referenceUploads[0], Path: "sub1/c.go", TargetCommit: "deadbeef1", TargetRange: testRange3},
+	}
+	if diff := cmp.Diff(expectedLocations, locations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+
+	if history := mockUploadSvc.GetUploadIDsWithReferencesFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected call count for uploadSvc.GetUploadIDsWithReferences. want=%d have=%d", 1, len(history))
+	} else {
+		expectedMonikers := []precise.QualifiedMonikerData{
+			{
+				MonikerData:            precise.MonikerData{Kind: "import", Scheme: "scip-go", Identifier: symbol},
+				PackageInformationData: precise.PackageInformationData{Manager: "gomod", Name: "github.com/example/greeter", Version: "v1.0.0"},
+			},
+		}
+		if diff := cmp.Diff(expectedMonikers, history[0].Arg1); diff != "" {
+			t.Errorf("unexpected monikers (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]int{50}, history[0].Arg2); diff != "" {
+			t.Errorf("unexpected ignored ids (-want +got):\n%s", diff)
+		}
+	}
+}
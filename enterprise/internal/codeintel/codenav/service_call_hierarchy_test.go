@@ -0,0 +1,236 @@
+package codenav
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	codeintelgitserver "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/gitserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	sgtypes "github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+func TestTypeDefinitions(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitServer := codeintelgitserver.New(&observation.TestContext, database.NewMockDB())
+	hunkCache, _ := NewHunkCache(50)
+
+	// Init service
+	svc := newService(&observation.TestContext, mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &sgtypes.Repo{}, mockCommit, mockPath, hunkCache)
+	uploads := []types.Dump{
+		{ID: 50, Commit: mockCommit, Root: "sub1/"},
+		{ID: 51, Commit: mockCommit, Root: "sub2/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	locations := []shared.Location{
+		{DumpID: 51, Path: "a.go", Range: testRange1},
+		{DumpID: 51, Path: "b.go", Range: testRange2},
+	}
+	mockLsifStore.GetTypeDefinitionLocationsFunc.PushReturn(locations, len(locations), nil)
+
+	mockRequest := shared.RequestArgs{
+		RepositoryID: 51,
+		Commit:       mockCommit,
+		Path:         mockPath,
+		Line:         10,
+		Character:    20,
+	}
+	adjustedLocations, err := svc.GetTypeDefinitions(context.Background(), mockRequest, mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying type definitions: %s", err)
+	}
+	expectedLocations := []types.UploadLocation{
+		{Dump: uploads[1], Path: "sub2/a.go", TargetCommit: mockCommit, TargetRange: testRange1},
+		{Dump: uploads[1], Path: "sub2/b.go", TargetCommit: mockCommit, TargetRange: testRange2},
+	}
+	if diff := cmp.Diff(expectedLocations, adjustedLocations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+
+	if calls := mockLsifStore.GetDefinitionLocationsFunc.History(); len(calls) != 0 {
+		t.Errorf("unexpected definition lookups: %v", calls)
+	}
+}
+
+func TestIncomingCalls(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitServer := codeintelgitserver.New(&observation.TestContext, database.NewMockDB())
+	hunkCache, _ := NewHunkCache(50)
+
+	// Init service
+	svc := newService(&observation.TestContext, mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &sgtypes.Repo{}, mockCommit, mockPath, hunkCache)
+	uploads := []types.Dump{
+		{ID: 50, Commit: mockCommit, Root: "sub1/"},
+		{ID: 51, Commit: mockCommit, Root: "sub2/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	// Empty result set (prevents nil pointer as scanner is always non-nil)
+	mockUploadSvc.GetUploadIDsWithReferencesFunc.PushReturn([]int{}, 0, 0, nil)
+
+	locations := []shared.Location{
+		{DumpID: 51, Path: "a.go", Range: testRange1},
+		{DumpID: 51, Path: "b.go", Range: testRange2},
+		{DumpID: 51, Path: "a.go", Range: testRange3},
+	}
+	mockLsifStore.GetReferenceLocationsFunc.PushReturn(locations, len(locations), nil)
+
+	// Each document has a single caller enclosing all references
+	mockLsifStore.GetCallersFunc.SetDefaultHook(func(_ context.Context, bundleID int, path string, ranges []types.Range) ([]shared.CallHierarchyCall, error) {
+		call := shared.CallHierarchyCall{
+			Symbol:     "caller-" + path,
+			Definition: shared.Location{DumpID: bundleID, Path: path, Range: testRange6},
+		}
+		for _, r := range ranges {
+			call.CallSites = append(call.CallSites, shared.Location{DumpID: bundleID, Path: path, Range: r})
+		}
+		return []shared.CallHierarchyCall{call}, nil
+	})
+
+	mockCursor := shared.ReferencesCursor{Phase: "local"}
+	mockRequest := shared.RequestArgs{
+		RepositoryID: 42,
+		Commit:       mockCommit,
+		Path:         mockPath,
+		Line:         10,
+		Character:    20,
+		Limit:        50,
+	}
+	calls, _, err := svc.GetIncomingCalls(context.Background(), mockRequest, mockRequestState, mockCursor)
+	if err != nil {
+		t.Fatalf("unexpected error querying incoming calls: %s", err)
+	}
+
+	expectedCalls := []shared.AdjustedCallHierarchyCall{
+		{
+			Symbol:     "caller-a.go",
+			Definition: &types.UploadLocation{Dump: uploads[1], Path: "sub2/a.go", TargetCommit: mockCommit, TargetRange: testRange6},
+			CallSites: []types.UploadLocation{
+				{Dump: uploads[1], Path: "sub2/a.go", TargetCommit: mockCommit, TargetRange: testRange1},
+				{Dump: uploads[1], Path: "sub2/a.go", TargetCommit: mockCommit, TargetRange: testRange3},
+			},
+		},
+		{
+			Symbol:     "caller-b.go",
+			Definition: &types.UploadLocation{Dump: uploads[1], Path: "sub2/b.go", TargetCommit: mockCommit, TargetRange: testRange6},
+			CallSites: []types.UploadLocation{
+				{Dump: uploads[1], Path: "sub2/b.go", TargetCommit: mockCommit, TargetRange: testRange2},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedCalls, calls); diff != "" {
+		t.Errorf("unexpected calls (-want +got):\n%s", diff)
+	}
+}
+
+func TestOutgoingCalls(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitServer := codeintelgitserver.New(&observation.TestContext, database.NewMockDB())
+	hunkCache, _ := NewHunkCache(50)
+
+	// Init service
+	svc := newService(&observation.TestContext, mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &sgtypes.Repo{}, mockCommit, mockPath, hunkCache)
+	uploads := []types.Dump{
+		{ID: 50, Commit: mockCommit, Root: "sub1/"},
+		{ID: 51, Commit: mockCommit, Root: "sub2/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	mockLsifStore.GetDefinitionLocationsFunc.PushReturn([]shared.Location{{DumpID: 51, Path: "a.go", Range: testRange1}}, 1, nil)
+	mockLsifStore.GetCalleesFunc.PushReturn([]shared.CallHierarchyCall{
+		{
+			Symbol:     "local-callee",
+			Definition: shared.Location{DumpID: 51, Path: "b.go", Range: testRange2},
+			CallSites:  []shared.Location{{DumpID: 51, Path: "a.go", Range: testRange3}},
+		},
+		{
+			Symbol:    "remote-callee",
+			CallSites: []shared.Location{{DumpID: 51, Path: "a.go", Range: testRange4}},
+		},
+	}, nil)
+
+	// The remote callee is defined in another repository
+	remoteUpload := types.Dump{ID: 60, Commit: "cafebabe", Root: "lib/", RepositoryID: 60}
+	monikers := []precise.MonikerData{
+		{Kind: "import", Scheme: "tsc", Identifier: "remote-callee", PackageInformationID: "pid"},
+		{Kind: "import", Scheme: "tsc", Identifier: "unrelated", PackageInformationID: "pid"},
+	}
+	mockLsifStore.GetMonikersByPositionFunc.PushReturn([][]precise.MonikerData{monikers}, nil)
+	mockLsifStore.GetPackageInformationFunc.SetDefaultReturn(precise.PackageInformationData{Name: "lib", Version: "0.1.0"}, true, nil)
+	mockUploadSvc.GetDumpsWithDefinitionsForMonikersFunc.PushReturn([]types.Dump{remoteUpload}, nil)
+	mockGitserverClient.CommitsExistFunc.SetDefaultHook(func(_ context.Context, rcs []codeintelgitserver.RepositoryCommit) ([]bool, error) {
+		exists := make([]bool, len(rcs))
+		for i := range rcs {
+			exists[i] = true
+		}
+		return exists, nil
+	})
+	mockLsifStore.GetBulkMonikerLocationsFunc.PushReturn([]shared.Location{{DumpID: 60, Path: "lib.go", Range: testRange5}}, 1, nil)
+
+	mockRequest := shared.RequestArgs{
+		RepositoryID: 51,
+		Commit:       mockCommit,
+		Path:         mockPath,
+		Line:         10,
+		Character:    20,
+	}
+	calls, err := svc.GetOutgoingCalls(context.Background(), mockRequest, mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying outgoing calls: %s", err)
+	}
+
+	expectedCalls := []shared.AdjustedCallHierarchyCall{
+		{
+			Symbol:     "local-callee",
+			Definition: &types.UploadLocation{Dump: uploads[1], Path: "sub2/b.go", TargetCommit: mockCommit, TargetRange: testRange2},
+			CallSites:  []types.UploadLocation{{Dump: uploads[1], Path: "sub2/a.go", TargetCommit: mockCommit, TargetRange: testRange3}},
+		},
+		{
+			Symbol:     "remote-callee",
+			Definition: &types.UploadLocation{Dump: remoteUpload, Path: "lib/lib.go", TargetCommit: "cafebabe", TargetRange: testRange5},
+			CallSites:  []types.UploadLocation{{Dump: uploads[1], Path: "sub2/a.go", TargetCommit: mockCommit, TargetRange: testRange4}},
+		},
+	}
+	if diff := cmp.Diff(expectedCalls, calls); diff != "" {
+		t.Errorf("unexpected calls (-want +got):\n%s", diff)
+	}
+
+	if history := mockLsifStore.GetBulkMonikerLocationsFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of moniker searches. want=%d have=%d", 1, len(history))
+	} else if diff := cmp.Diff(monikers[:1], history[0].Arg3); diff != "" {
+		t.Errorf("unexpected monikers (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,147 @@
+package codenav
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	codeintelgitserver "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/gitserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	sgtypes "github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestDocumentSymbols(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := NewMockGitserverClient()
+	mockSymbolsClient := NewMockSymbolsClient()
+	mockGitServer := codeintelgitserver.New(&observation.TestContext, database.NewMockDB())
+	hunkCache, _ := NewHunkCache(50)
+
+	// Init service
+	svc := newService(&observation.TestContext, mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, mockSymbolsClient)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &sgtypes.Repo{}, mockCommit, mockPath, hunkCache)
+	uploads := []types.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub1/"},
+		{ID: 51, Commit: "deadbeef", Root: "sub2/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	expectedSymbols := []shared.DocumentSymbol{
+		{
+			Name:   "Greeter",
+			Symbol: "scip-typescript npm example 1.0.0 `index.ts`/Greeter#",
+			Kind:   "CLASS",
+			Range:  testRange1,
+			Children: []shared.DocumentSymbol{
+				{Name: "greet", Symbol: "scip-typescript npm example 1.0.0 `index.ts`/Greeter#greet().", Kind: "METHOD", Range: testRange2},
+			},
+		},
+	}
+
+	// The first upload is an LSIF index
+	mockLsifStore.GetDocumentSymbolsFunc.PushReturn(nil, false, nil)
+	mockLsifStore.GetDocumentSymbolsFunc.PushReturn(expectedSymbols, true, nil)
+
+	mockRequest := shared.RequestArgs{
+		RepositoryID: 42,
+		Commit:       mockCommit,
+		Path:         mockPath,
+	}
+	symbols, precise, err := svc.GetDocumentSymbols(context.Background(), mockRequest, mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying document symbols: %s", err)
+	}
+	if !precise {
+		t.Errorf("expected precise document symbols")
+	}
+	if diff := cmp.Diff(expectedSymbols, symbols); diff != "" {
+		t.Errorf("unexpected document symbols (-want +got):\n%s", diff)
+	}
+
+	if calls := mockSymbolsClient.SearchFunc.History(); len(calls) != 0 {
+		t.Errorf("unexpected symbols service searches: %v", calls)
+	}
+}
+
+func TestDocumentSymbolsSearchBased(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := NewMockGitserverClient()
+	mockSymbolsClient := NewMockSymbolsClient()
+	mockGitServer := codeintelgitserver.New(&observation.TestContext, database.NewMockDB())
+	hunkCache, _ := NewHunkCache(50)
+
+	// Init service
+	svc := newService(&observation.TestContext, mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, mockSymbolsClient)
+
+	// Set up request state with no uploads covering the document
+	mockRequestState := RequestState{RepositoryName: "github.com/sourcegraph/example"}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &sgtypes.Repo{}, mockCommit, mockPath, hunkCache)
+	mockRequestState.SetUploadsDataLoader(nil)
+
+	mockSymbolsClient.SearchFunc.PushReturn([]result.Symbol{
+		{Name: "greet", Path: mockPath, Line: 3, Character: 2, Kind: "method", Parent: "Greeter", ParentKind: "class"},
+		{Name: "Greeter", Path: mockPath, Line: 1, Character: 6, Kind: "class"},
+		{Name: "main", Path: mockPath, Line: 8, Character: 9, Kind: "function"},
+	}, nil)
+
+	mockRequest := shared.RequestArgs{
+		RepositoryID: 42,
+		Commit:       mockCommit,
+		Path:         mockPath,
+	}
+	symbols, precise, err := svc.GetDocumentSymbols(context.Background(), mockRequest, mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying document symbols: %s", err)
+	}
+	if precise {
+		t.Errorf("expected search-based document symbols")
+	}
+
+	expectedSymbols := []shared.DocumentSymbol{
+		{
+			Name:  "Greeter",
+			Kind:  "CLASS",
+			Range: types.Range{Start: types.Position{Line: 0, Character: 6}, End: types.Position{Line: 0, Character: 13}},
+			Children: []shared.DocumentSymbol{
+				{Name: "greet", Kind: "METHOD", Range: types.Range{Start: types.Position{Line: 2, Character: 2}, End: types.Position{Line: 2, Character: 7}}},
+			},
+		},
+		{
+			Name:  "main",
+			Kind:  "FUNCTION",
+			Range: types.Range{Start: types.Position{Line: 7, Character: 9}, End: types.Position{Line: 7, Character: 13}},
+		},
+	}
+	if diff := cmp.Diff(expectedSymbols, symbols); diff != "" {
+		t.Errorf("unexpected document symbols (-want +got):\n%s", diff)
+	}
+
+	if history := mockSymbolsClient.SearchFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of symbols service searches. want=%d have=%d", 1, len(history))
+	} else {
+		args := history[0].Arg1
+		if args.Repo != api.RepoName("github.com/sourcegraph/example") || args.CommitID != api.CommitID(mockCommit) {
+			t.Errorf("unexpected repository and commit. have=%s@%s", args.Repo, args.CommitID)
+		}
+		if diff := cmp.Diff([]string{"^s1/main\\.go$"}, args.IncludePatterns); diff != "" {
+			t.Errorf("unexpected include patterns (-want +got):\n%s", diff)
+		}
+	}
+}
@@ -59,6 +59,67 @@ type CodeIntelligenceRange struct {
 	HoverText       string
 }
 
+// CallHierarchyCall is a call between two callable symbols. For incoming calls, Symbol is the
+// caller, and for outgoing calls it is the callee. Definition is the location of the definition
+// of Symbol, which is zero-valued if it is unknown. CallSites are the ranges of the calls, which
+// are within the caller.
+type CallHierarchyCall struct {
+	Symbol     string
+	Definition Location
+	CallSites  []Location
+}
+
+// AdjustedCallHierarchyCall is a CallHierarchyCall whose locations have been adjusted to fit the
+// target (originally requested) commit. Definition is nil if it is unknown.
+type AdjustedCallHierarchyCall struct {
+	Symbol     string
+	Definition *types.UploadLocation
+	CallSites  []types.UploadLocation
+}
+
+// DocumentSymbol is a symbol defined in a document along with the symbols defined within it. Kind
+// is the name of the symbol's kind in the SymbolKind GraphQL enum. Symbol is the SCIP symbol, which
+// is empty for symbols not derived from precise data.
+type DocumentSymbol struct {
+	Name     string
+	Symbol   string
+	Kind     string
+	Range    types.Range
+	Children []DocumentSymbol
+}
+
+// ExportedSymbol is a global symbol defined in an upload. Signature is the code block that leads
+// the symbol's hover documentation, and is empty if the indexer did not emit one.
+type ExportedSymbol struct {
+	Symbol     string
+	Signature  string
+	Definition Location
+}
+
+// APISymbol is an exported symbol whose definition is located at the indexed commit.
+type APISymbol struct {
+	Symbol     string
+	Signature  string
+	Definition types.UploadLocation
+}
+
+// APISymbolChange pairs the base and head versions of an exported symbol whose signature differs
+// between two commits.
+type APISymbolChange struct {
+	Base APISymbol
+	Head APISymbol
+}
+
+// APIDiff describes the exported symbols added, removed, and changed between the SCIP uploads of
+// a base and a head commit.
+type APIDiff struct {
+	BaseUploads []types.Dump
+	HeadUploads []types.Dump
+	Added       []APISymbol
+	Removed     []APISymbol
+	Changed     []APISymbolChange
+}
+
 // referencesCursor stores (enough of) the state of a previous References request used to
 // calculate the offset into the result set to be returned by the current request.
 type ReferencesCursor struct {
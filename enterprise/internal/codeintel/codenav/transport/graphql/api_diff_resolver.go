@@ -0,0 +1,151 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	traceLog "github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	sharedresolvers "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/resolvers"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type apiDiffResolver struct {
+	svc              CodeNavService
+	diff             shared.APIDiff
+	baseRequestState codenav.RequestState
+	headRequestState codenav.RequestState
+	locationResolver *sharedresolvers.CachedLocationResolver
+	operations       *operations
+}
+
+func NewAPIDiffResolver(
+	svc CodeNavService,
+	diff shared.APIDiff,
+	baseRequestState codenav.RequestState,
+	headRequestState codenav.RequestState,
+	locationResolver *sharedresolvers.CachedLocationResolver,
+	operations *operations,
+) resolverstubs.CodeIntelAPIDiffResolver {
+	return &apiDiffResolver{
+		svc:              svc,
+		diff:             diff,
+		baseRequestState: baseRequestState,
+		headRequestState: headRequestState,
+		locationResolver: locationResolver,
+		operations:       operations,
+	}
+}
+
+func (r *apiDiffResolver) Added() []resolverstubs.CodeIntelAPISymbolResolver {
+	return r.newAPISymbolResolvers(r.diff.Added, r.headRequestState)
+}
+
+func (r *apiDiffResolver) Removed() []resolverstubs.CodeIntelAPISymbolResolver {
+	return r.newAPISymbolResolvers(r.diff.Removed, r.baseRequestState)
+}
+
+func (r *apiDiffResolver) Changed() []resolverstubs.CodeIntelAPISymbolChangeResolver {
+	resolvers := make([]resolverstubs.CodeIntelAPISymbolChangeResolver, 0, len(r.diff.Changed))
+	for _, change := range r.diff.Changed {
+		resolvers = append(resolvers, &apiSymbolChangeResolver{
+			base: r.newAPISymbolResolver(change.Base, r.baseRequestState),
+			head: r.newAPISymbolResolver(change.Head, r.headRequestState),
+		})
+	}
+
+	return resolvers
+}
+
+func (r *apiDiffResolver) newAPISymbolResolvers(symbols []shared.APISymbol, requestState codenav.RequestState) []resolverstubs.CodeIntelAPISymbolResolver {
+	resolvers := make([]resolverstubs.CodeIntelAPISymbolResolver, 0, len(symbols))
+	for _, symbol := range symbols {
+		resolvers = append(resolvers, r.newAPISymbolResolver(symbol, requestState))
+	}
+
+	return resolvers
+}
+
+func (r *apiDiffResolver) newAPISymbolResolver(symbol shared.APISymbol, requestState codenav.RequestState) *apiSymbolResolver {
+	return &apiSymbolResolver{
+		svc:              r.svc,
+		symbol:           symbol,
+		requestState:     requestState,
+		locationResolver: r.locationResolver,
+		operations:       r.operations,
+	}
+}
+
+type apiSymbolChangeResolver struct {
+	base *apiSymbolResolver
+	head *apiSymbolResolver
+}
+
+func (r *apiSymbolChangeResolver) Base() resolverstubs.CodeIntelAPISymbolResolver { return r.base }
+func (r *apiSymbolChangeResolver) Head() resolverstubs.CodeIntelAPISymbolResolver { return r.head }
+
+// apiSymbolResolver resolves an exported symbol of the uploads of the given request state, which
+// are the uploads of the commit at which the symbol is exported.
+type apiSymbolResolver struct {
+	svc              CodeNavService
+	symbol           shared.APISymbol
+	requestState     codenav.RequestState
+	locationResolver *sharedresolvers.CachedLocationResolver
+	operations       *operations
+}
+
+func (r *apiSymbolResolver) Symbol() string     { return r.symbol.Symbol }
+func (r *apiSymbolResolver) Signature() *string { return strPtr(r.symbol.Signature) }
+
+func (r *apiSymbolResolver) Definition(ctx context.Context) (resolverstubs.LocationResolver, error) {
+	return resolveLocation(ctx, r.locationResolver, r.symbol.Definition)
+}
+
+// DefaultDownstreamReferencesPageSize is the downstream reference result page size when no limit is supplied.
+const DefaultDownstreamReferencesPageSize = 100
+
+// DownstreamReferences returns the list of locations in other repositories that reference the symbol.
+func (r *apiSymbolResolver) DownstreamReferences(ctx context.Context, args *resolverstubs.CodeIntelDownstreamReferencesArgs) (_ resolverstubs.LocationConnectionResolver, err error) {
+	limit := derefInt32(args.First, DefaultDownstreamReferencesPageSize)
+	if limit <= 0 {
+		return nil, ErrIllegalLimit
+	}
+
+	rawCursor, err := DecodeCursor(args.After)
+	if err != nil {
+		return nil, err
+	}
+
+	requestArgs := shared.RequestArgs{RepositoryID: r.requestState.RepositoryID, Commit: r.requestState.Commit, Limit: limit, RawCursor: rawCursor}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.downstreamReferences, time.Second, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", requestArgs.RepositoryID),
+			traceLog.String("commit", requestArgs.Commit),
+			traceLog.String("symbol", r.symbol.Symbol),
+			traceLog.Int("limit", requestArgs.Limit),
+		},
+	})
+	defer endObservation()
+
+	cursor, err := decodeRemoteCursor(requestArgs.RawCursor)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("invalid cursor: %q", rawCursor))
+	}
+
+	refs, refCursor, hasMore, err := r.svc.GetDownstreamReferences(ctx, requestArgs, r.requestState, r.symbol.Symbol, cursor)
+	if err != nil {
+		return nil, errors.Wrap(err, "svc.GetDownstreamReferences")
+	}
+
+	var nextCursor string
+	if hasMore {
+		nextCursor = encodeRemoteCursor(refCursor)
+	}
+
+	return NewLocationConnectionResolver(refs, strPtr(nextCursor), r.locationResolver), nil
+}
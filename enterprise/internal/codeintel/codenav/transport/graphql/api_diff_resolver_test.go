@@ -0,0 +1,75 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestAPIDiffDownstreamReferences(t *testing.T) {
+	mockCodeNavService := NewMockCodeNavService()
+	mockBaseRequestState := codenav.RequestState{RepositoryID: 1, Commit: "deadbeef1"}
+	mockHeadRequestState := codenav.RequestState{RepositoryID: 1, Commit: "deadbeef2"}
+	mockOperations := newOperations(&observation.TestContext)
+
+	diff := shared.APIDiff{
+		Added:   []shared.APISymbol{{Symbol: "scip-go gomod example v2 `example`/Bar#"}},
+		Removed: []shared.APISymbol{{Symbol: "scip-go gomod example v1 `example`/Foo#", Signature: "type Foo struct"}},
+	}
+	resolver := NewAPIDiffResolver(mockCodeNavService, diff, mockBaseRequestState, mockHeadRequestState, nil, mockOperations)
+
+	removed := resolver.Removed()
+	if len(removed) != 1 {
+		t.Fatalf("unexpected number of removed symbols. want=%d have=%d", 1, len(removed))
+	}
+	if signature := removed[0].Signature(); signature == nil || *signature != "type Foo struct" {
+		t.Errorf("unexpected signature. have=%v", signature)
+	}
+	if added := resolver.Added(); len(added) != 1 || added[0].Signature() != nil {
+		t.Errorf("unexpected added symbols")
+	}
+
+	mockCodeNavService.GetDownstreamReferencesFunc.PushReturn(nil, shared.RemoteCursor{UploadOffset: 10}, true, nil)
+
+	offset := int32(25)
+	mockCursor := base64.StdEncoding.EncodeToString([]byte(encodeRemoteCursor(shared.RemoteCursor{UploadOffset: 5})))
+	args := &resolverstubs.CodeIntelDownstreamReferencesArgs{
+		ConnectionArgs: graphqlutil.ConnectionArgs{First: &offset},
+		After:          &mockCursor,
+	}
+	connection, err := removed[0].DownstreamReferences(context.Background(), args)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(mockCodeNavService.GetDownstreamReferencesFunc.History()) != 1 {
+		t.Fatalf("unexpected call count. want=%d have=%d", 1, len(mockCodeNavService.GetDownstreamReferencesFunc.History()))
+	}
+	call := mockCodeNavService.GetDownstreamReferencesFunc.History()[0]
+	if call.Arg1.Commit != "deadbeef1" {
+		t.Errorf("unexpected commit. want=%s have=%s", "deadbeef1", call.Arg1.Commit)
+	}
+	if call.Arg1.Limit != 25 {
+		t.Errorf("unexpected limit. want=%d have=%d", 25, call.Arg1.Limit)
+	}
+	if call.Arg3 != "scip-go gomod example v1 `example`/Foo#" {
+		t.Errorf("unexpected symbol. have=%s", call.Arg3)
+	}
+	if call.Arg4.UploadOffset != 5 {
+		t.Errorf("unexpected cursor. want=%d have=%d", 5, call.Arg4.UploadOffset)
+	}
+
+	pageInfo, err := connection.PageInfo(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !pageInfo.HasNextPage() {
+		t.Errorf("expected another page")
+	}
+}
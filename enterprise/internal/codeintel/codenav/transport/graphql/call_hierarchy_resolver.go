@@ -0,0 +1,65 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	sharedresolvers "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/resolvers"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+)
+
+type callHierarchyCallConnectionResolver struct {
+	calls            []shared.AdjustedCallHierarchyCall
+	cursor           *string
+	locationResolver *sharedresolvers.CachedLocationResolver
+}
+
+func NewCallHierarchyCallConnectionResolver(calls []shared.AdjustedCallHierarchyCall, cursor *string, locationResolver *sharedresolvers.CachedLocationResolver) resolverstubs.CallHierarchyCallConnectionResolver {
+	return &callHierarchyCallConnectionResolver{
+		calls:            calls,
+		cursor:           cursor,
+		locationResolver: locationResolver,
+	}
+}
+
+func (r *callHierarchyCallConnectionResolver) Nodes(ctx context.Context) ([]resolverstubs.CallHierarchyCallResolver, error) {
+	resolvers := make([]resolverstubs.CallHierarchyCallResolver, 0, len(r.calls))
+	for _, call := range r.calls {
+		callSites, err := resolveLocations(ctx, r.locationResolver, call.CallSites)
+		if err != nil {
+			return nil, err
+		}
+		if len(callSites) == 0 {
+			continue
+		}
+
+		var definition resolverstubs.LocationResolver
+		if call.Definition != nil {
+			if definition, err = resolveLocation(ctx, r.locationResolver, *call.Definition); err != nil {
+				return nil, err
+			}
+		}
+
+		resolvers = append(resolvers, &callHierarchyCallResolver{
+			symbol:     call.Symbol,
+			definition: definition,
+			callSites:  callSites,
+		})
+	}
+
+	return resolvers, nil
+}
+
+func (r *callHierarchyCallConnectionResolver) PageInfo(ctx context.Context) (resolverstubs.PageInfo, error) {
+	return EncodeCursor(r.cursor), nil
+}
+
+type callHierarchyCallResolver struct {
+	symbol     string
+	definition resolverstubs.LocationResolver
+	callSites  []resolverstubs.LocationResolver
+}
+
+func (r *callHierarchyCallResolver) Symbol() string                              { return r.symbol }
+func (r *callHierarchyCallResolver) Definition() resolverstubs.LocationResolver  { return r.definition }
+func (r *callHierarchyCallResolver) CallSites() []resolverstubs.LocationResolver { return r.callSites }
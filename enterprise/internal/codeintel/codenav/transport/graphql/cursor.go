@@ -52,3 +52,26 @@ func encodeReferencesCursor(cursor shared.ReferencesCursor) string {
 	rawEncoded, _ := json.Marshal(cursor)
 	return base64.RawURLEncoding.EncodeToString(rawEncoded)
 }
+
+// decodeRemoteCursor is the inverse of encodeRemoteCursor. If the given encoded string is empty, then
+// a fresh cursor is returned.
+func decodeRemoteCursor(rawEncoded string) (shared.RemoteCursor, error) {
+	if rawEncoded == "" {
+		return shared.RemoteCursor{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(rawEncoded)
+	if err != nil {
+		return shared.RemoteCursor{}, err
+	}
+
+	var cursor shared.RemoteCursor
+	err = json.Unmarshal(raw, &cursor)
+	return cursor, err
+}
+
+// encodeRemoteCursor returns an encoding of the given cursor suitable for a URL or a GraphQL token.
+func encodeRemoteCursor(cursor shared.RemoteCursor) string {
+	rawEncoded, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(rawEncoded)
+}
@@ -0,0 +1,58 @@
+package graphql
+
+import (
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+)
+
+type documentSymbolsResolver struct {
+	symbols []shared.DocumentSymbol
+	precise bool
+}
+
+func NewDocumentSymbolsResolver(symbols []shared.DocumentSymbol, precise bool) resolverstubs.DocumentSymbolsResolver {
+	return &documentSymbolsResolver{
+		symbols: symbols,
+		precise: precise,
+	}
+}
+
+func (r *documentSymbolsResolver) Nodes() []resolverstubs.DocumentSymbolResolver {
+	return newDocumentSymbolResolvers(r.symbols)
+}
+
+func (r *documentSymbolsResolver) Precise() bool { return r.precise }
+
+type documentSymbolResolver struct {
+	symbol shared.DocumentSymbol
+}
+
+func newDocumentSymbolResolvers(symbols []shared.DocumentSymbol) []resolverstubs.DocumentSymbolResolver {
+	resolvers := make([]resolverstubs.DocumentSymbolResolver, 0, len(symbols))
+	for _, symbol := range symbols {
+		resolvers = append(resolvers, &documentSymbolResolver{symbol: symbol})
+	}
+
+	return resolvers
+}
+
+func (r *documentSymbolResolver) Name() string { return r.symbol.Name }
+
+func (r *documentSymbolResolver) Symbol() *string {
+	if r.symbol.Symbol == "" {
+		return nil
+	}
+	return &r.symbol.Symbol
+}
+
+func (r *documentSymbolResolver) Kind() string /* enum SymbolKind */ {
+	return r.symbol.Kind
+}
+
+func (r *documentSymbolResolver) Range() resolverstubs.RangeResolver {
+	return NewRangeResolver(convertRange(r.symbol.Range))
+}
+
+func (r *documentSymbolResolver) Children() []resolverstubs.DocumentSymbolResolver {
+	return newDocumentSymbolResolvers(r.symbol.Children)
+}
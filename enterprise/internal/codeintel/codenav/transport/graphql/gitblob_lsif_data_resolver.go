@@ -151,6 +151,30 @@ func (r *gitBlobLSIFDataResolver) Definitions(ctx context.Context, args *resolve
 	return NewLocationConnectionResolver(def, nil, r.locationResolver), nil
 }
 
+// TypeDefinitions returns the list of source locations that define the type of the symbol at the given position.
+func (r *gitBlobLSIFDataResolver) TypeDefinitions(ctx context.Context, args *resolverstubs.LSIFQueryPositionArgs) (_ resolverstubs.LocationConnectionResolver, err error) {
+	requestArgs := shared.RequestArgs{RepositoryID: r.requestState.RepositoryID, Commit: r.requestState.Commit, Path: r.requestState.Path, Line: int(args.Line), Character: int(args.Character)}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.typeDefinitions, time.Second, getObservationArgs(requestArgs))
+	defer endObservation()
+
+	def, err := r.codeNavSvc.GetTypeDefinitions(ctx, requestArgs, r.requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetTypeDefinitions")
+	}
+
+	if args.Filter != nil && *args.Filter != "" {
+		filtered := def[:0]
+		for _, loc := range def {
+			if strings.Contains(loc.Path, *args.Filter) {
+				filtered = append(filtered, loc)
+			}
+		}
+		def = filtered
+	}
+
+	return NewLocationConnectionResolver(def, nil, r.locationResolver), nil
+}
+
 const DefaultReferencesPageSize = 100
 
 // References returns the list of source locations that reference the symbol at the given position.
@@ -254,6 +278,56 @@ func (r *gitBlobLSIFDataResolver) Implementations(ctx context.Context, args *res
 	return NewLocationConnectionResolver(impls, strPtr(nextCursor), r.locationResolver), nil
 }
 
+// IncomingCalls returns the calls to the function or method at the given position. Each page resolves
+// the calls of a page of references.
+func (r *gitBlobLSIFDataResolver) IncomingCalls(ctx context.Context, args *resolverstubs.LSIFPagedQueryPositionArgs) (_ resolverstubs.CallHierarchyCallConnectionResolver, err error) {
+	limit := derefInt32(args.First, DefaultReferencesPageSize)
+	if limit <= 0 {
+		return nil, ErrIllegalLimit
+	}
+
+	rawCursor, err := DecodeCursor(args.After)
+	if err != nil {
+		return nil, err
+	}
+
+	requestArgs := shared.RequestArgs{RepositoryID: r.requestState.RepositoryID, Commit: r.requestState.Commit, Path: r.requestState.Path, Line: int(args.Line), Character: int(args.Character), Limit: limit, RawCursor: rawCursor}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.incomingCalls, time.Second, getObservationArgs(requestArgs))
+	defer endObservation()
+
+	// Incoming calls are resolved from references, so we page through them with a references cursor.
+	var nextCursor string
+	cursor, err := decodeReferencesCursor(requestArgs.RawCursor)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("invalid cursor: %q", rawCursor))
+	}
+
+	calls, callsCursor, err := r.codeNavSvc.GetIncomingCalls(ctx, requestArgs, r.requestState, cursor)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetIncomingCalls")
+	}
+
+	if callsCursor.Phase != "done" {
+		nextCursor = encodeReferencesCursor(callsCursor)
+	}
+
+	return NewCallHierarchyCallConnectionResolver(calls, strPtr(nextCursor), r.locationResolver), nil
+}
+
+// OutgoingCalls returns the calls made by the function or method at the given position.
+func (r *gitBlobLSIFDataResolver) OutgoingCalls(ctx context.Context, args *resolverstubs.LSIFQueryPositionArgs) (_ resolverstubs.CallHierarchyCallConnectionResolver, err error) {
+	requestArgs := shared.RequestArgs{RepositoryID: r.requestState.RepositoryID, Commit: r.requestState.Commit, Path: r.requestState.Path, Line: int(args.Line), Character: int(args.Character)}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.outgoingCalls, time.Second, getObservationArgs(requestArgs))
+	defer endObservation()
+
+	calls, err := r.codeNavSvc.GetOutgoingCalls(ctx, requestArgs, r.requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetOutgoingCalls")
+	}
+
+	return NewCallHierarchyCallConnectionResolver(calls, nil, r.locationResolver), nil
+}
+
 // Hover returns the hover text and range for the symbol at the given position.
 func (r *gitBlobLSIFDataResolver) Hover(ctx context.Context, args *resolverstubs.LSIFQueryPositionArgs) (_ resolverstubs.HoverResolver, err error) {
 	requestArgs := shared.RequestArgs{RepositoryID: r.requestState.RepositoryID, Commit: r.requestState.Commit, Path: r.requestState.Path, Line: int(args.Line), Character: int(args.Character)}
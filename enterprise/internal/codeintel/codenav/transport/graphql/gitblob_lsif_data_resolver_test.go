@@ -225,6 +225,95 @@ func TestReferencesDefaultIllegalLimit(t *testing.T) {
 	}
 }
 
+func TestIncomingCalls(t *testing.T) {
+	mockAutoIndexingSvc := NewMockAutoIndexingService()
+	mockUploadsService := NewMockUploadsService()
+	mockPolicyService := NewMockPolicyService()
+	mockCodeNavService := NewMockCodeNavService()
+	mockRequestState := codenav.RequestState{
+		RepositoryID: 1,
+		Commit:       "deadbeef1",
+		Path:         "/src/main",
+	}
+	mockOperations := newOperations(&observation.TestContext)
+
+	resolver := NewGitBlobLSIFDataResolver(
+		mockCodeNavService,
+		mockAutoIndexingSvc,
+		mockUploadsService,
+		mockPolicyService,
+		mockRequestState,
+		observation.NewErrorCollector(),
+		mockOperations,
+	)
+
+	offset := int32(25)
+	mockRefCursor := shared.ReferencesCursor{Phase: "local"}
+	encodedCursor := encodeReferencesCursor(mockRefCursor)
+	mockCursor := base64.StdEncoding.EncodeToString([]byte(encodedCursor))
+
+	args := &resolverstubs.LSIFPagedQueryPositionArgs{
+		LSIFQueryPositionArgs: resolverstubs.LSIFQueryPositionArgs{
+			Line:      10,
+			Character: 15,
+		},
+		ConnectionArgs: graphqlutil.ConnectionArgs{First: &offset},
+		After:          &mockCursor,
+	}
+
+	if _, err := resolver.IncomingCalls(context.Background(), args); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(mockCodeNavService.GetIncomingCallsFunc.History()) != 1 {
+		t.Fatalf("unexpected call count. want=%d have=%d", 1, len(mockCodeNavService.GetIncomingCallsFunc.History()))
+	}
+	if val := mockCodeNavService.GetIncomingCallsFunc.History()[0].Arg1; val.Line != 10 || val.Character != 15 {
+		t.Fatalf("unexpected position. want=%v:%v have=%v:%v", 10, 15, val.Line, val.Character)
+	}
+	if val := mockCodeNavService.GetIncomingCallsFunc.History()[0].Arg1; val.Limit != 25 {
+		t.Fatalf("unexpected limit. want=%v have=%v", 25, val.Limit)
+	}
+	if val := mockCodeNavService.GetIncomingCallsFunc.History()[0].Arg3; val.Phase != "local" {
+		t.Fatalf("unexpected cursor phase. want=%v have=%v", "local", val.Phase)
+	}
+}
+
+func TestOutgoingCalls(t *testing.T) {
+	mockAutoIndexingSvc := NewMockAutoIndexingService()
+	mockUploadsService := NewMockUploadsService()
+	mockPolicyService := NewMockPolicyService()
+	mockCodeNavService := NewMockCodeNavService()
+	mockRequestState := codenav.RequestState{
+		RepositoryID: 1,
+		Commit:       "deadbeef1",
+		Path:         "/src/main",
+	}
+	mockOperations := newOperations(&observation.TestContext)
+
+	resolver := NewGitBlobLSIFDataResolver(
+		mockCodeNavService,
+		mockAutoIndexingSvc,
+		mockUploadsService,
+		mockPolicyService,
+		mockRequestState,
+		observation.NewErrorCollector(),
+		mockOperations,
+	)
+
+	args := &resolverstubs.LSIFQueryPositionArgs{Line: 10, Character: 15}
+	if _, err := resolver.OutgoingCalls(context.Background(), args); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(mockCodeNavService.GetOutgoingCallsFunc.History()) != 1 {
+		t.Fatalf("unexpected call count. want=%d have=%d", 1, len(mockCodeNavService.GetOutgoingCallsFunc.History()))
+	}
+	if val := mockCodeNavService.GetOutgoingCallsFunc.History()[0].Arg1; val.Line != 10 || val.Character != 15 {
+		t.Fatalf("unexpected position. want=%v:%v have=%v:%v", 10, 15, val.Line, val.Character)
+	}
+}
+
 func TestHover(t *testing.T) {
 	mockAutoIndexingSvc := NewMockAutoIndexingService()
 	mockUploadsService := NewMockUploadsService()
@@ -23,9 +23,15 @@ type CodeNavService interface {
 	GetReferences(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState, cursor shared.ReferencesCursor) (_ []types.UploadLocation, nextCursor shared.ReferencesCursor, err error)
 	GetImplementations(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState, cursor shared.ImplementationsCursor) (_ []types.UploadLocation, nextCursor shared.ImplementationsCursor, err error)
 	GetDefinitions(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState) (_ []types.UploadLocation, err error)
+	GetTypeDefinitions(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState) (_ []types.UploadLocation, err error)
+	GetIncomingCalls(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState, cursor shared.ReferencesCursor) (_ []shared.AdjustedCallHierarchyCall, nextCursor shared.ReferencesCursor, err error)
+	GetOutgoingCalls(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState) (_ []shared.AdjustedCallHierarchyCall, err error)
 	GetDiagnostics(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState) (diagnosticsAtUploads []shared.DiagnosticAtUpload, _ int, err error)
 	GetRanges(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState, startLine, endLine int) (adjustedRanges []shared.AdjustedCodeIntelligenceRange, err error)
 	GetStencil(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState) (adjustedRanges []types.Range, err error)
+	GetDocumentSymbols(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState) (_ []shared.DocumentSymbol, precise bool, err error)
+	GetAPIDiff(ctx context.Context, repositoryID int, baseCommit, headCommit string) (_ shared.APIDiff, _ bool, err error)
+	GetDownstreamReferences(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState, symbol string, cursor shared.RemoteCursor) (_ []types.UploadLocation, nextCursor shared.RemoteCursor, hasMore bool, err error)
 
 	// Uploads Service
 	GetDumpsByIDs(ctx context.Context, ids []int) (_ []types.Dump, err error)
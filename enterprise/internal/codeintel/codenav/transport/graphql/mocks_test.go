@@ -833,6 +833,9 @@ func (c AutoIndexingServiceQueueRepoRevFuncCall) Results() []interface{} {
 // github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/transport/graphql)
 // used for unit testing.
 type MockCodeNavService struct {
+	// GetAPIDiffFunc is an instance of a mock function object controlling
+	// the behavior of the method GetAPIDiff.
+	GetAPIDiffFunc *CodeNavServiceGetAPIDiffFunc
 	// GetClosestDumpsForBlobFunc is an instance of a mock function object
 	// controlling the behavior of the method GetClosestDumpsForBlob.
 	GetClosestDumpsForBlobFunc *CodeNavServiceGetClosestDumpsForBlobFunc
@@ -842,6 +845,12 @@ type MockCodeNavService struct {
 	// GetDiagnosticsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetDiagnostics.
 	GetDiagnosticsFunc *CodeNavServiceGetDiagnosticsFunc
+	// GetDocumentSymbolsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetDocumentSymbols.
+	GetDocumentSymbolsFunc *CodeNavServiceGetDocumentSymbolsFunc
+	// GetDownstreamReferencesFunc is an instance of a mock function object
+	// controlling the behavior of the method GetDownstreamReferences.
+	GetDownstreamReferencesFunc *CodeNavServiceGetDownstreamReferencesFunc
 	// GetDumpsByIDsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetDumpsByIDs.
 	GetDumpsByIDsFunc *CodeNavServiceGetDumpsByIDsFunc
@@ -851,6 +860,12 @@ type MockCodeNavService struct {
 	// GetImplementationsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetImplementations.
 	GetImplementationsFunc *CodeNavServiceGetImplementationsFunc
+	// GetIncomingCallsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetIncomingCalls.
+	GetIncomingCallsFunc *CodeNavServiceGetIncomingCallsFunc
+	// GetOutgoingCallsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetOutgoingCalls.
+	GetOutgoingCallsFunc *CodeNavServiceGetOutgoingCallsFunc
 	// GetRangesFunc is an instance of a mock function object controlling
 	// the behavior of the method GetRanges.
 	GetRangesFunc *CodeNavServiceGetRangesFunc
@@ -860,6 +875,9 @@ type MockCodeNavService struct {
 	// GetStencilFunc is an instance of a mock function object controlling
 	// the behavior of the method GetStencil.
 	GetStencilFunc *CodeNavServiceGetStencilFunc
+	// GetTypeDefinitionsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetTypeDefinitions.
+	GetTypeDefinitionsFunc *CodeNavServiceGetTypeDefinitionsFunc
 	// GetUnsafeDBFunc is an instance of a mock function object controlling
 	// the behavior of the method GetUnsafeDB.
 	GetUnsafeDBFunc *CodeNavServiceGetUnsafeDBFunc
@@ -869,6 +887,11 @@ type MockCodeNavService struct {
 // All methods return zero values for all results, unless overwritten.
 func NewMockCodeNavService() *MockCodeNavService {
 	return &MockCodeNavService{
+		GetAPIDiffFunc: &CodeNavServiceGetAPIDiffFunc{
+			defaultHook: func(context.Context, int, string, string) (r0 shared1.APIDiff, r1 bool, r2 error) {
+				return
+			},
+		},
 		GetClosestDumpsForBlobFunc: &CodeNavServiceGetClosestDumpsForBlobFunc{
 			defaultHook: func(context.Context, int, string, string, bool, string) (r0 []types.Dump, r1 error) {
 				return
@@ -884,6 +907,16 @@ func NewMockCodeNavService() *MockCodeNavService {
 				return
 			},
 		},
+		GetDocumentSymbolsFunc: &CodeNavServiceGetDocumentSymbolsFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState) (r0 []shared1.DocumentSymbol, r1 bool, r2 error) {
+				return
+			},
+		},
+		GetDownstreamReferencesFunc: &CodeNavServiceGetDownstreamReferencesFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState, string, shared1.RemoteCursor) (r0 []types.UploadLocation, r1 shared1.RemoteCursor, r2 bool, r3 error) {
+				return
+			},
+		},
 		GetDumpsByIDsFunc: &CodeNavServiceGetDumpsByIDsFunc{
 			defaultHook: func(context.Context, []int) (r0 []types.Dump, r1 error) {
 				return
@@ -899,6 +932,16 @@ func NewMockCodeNavService() *MockCodeNavService {
 				return
 			},
 		},
+		GetIncomingCallsFunc: &CodeNavServiceGetIncomingCallsFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) (r0 []shared1.AdjustedCallHierarchyCall, r1 shared1.ReferencesCursor, r2 error) {
+				return
+			},
+		},
+		GetOutgoingCallsFunc: &CodeNavServiceGetOutgoingCallsFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState) (r0 []shared1.AdjustedCallHierarchyCall, r1 error) {
+				return
+			},
+		},
 		GetRangesFunc: &CodeNavServiceGetRangesFunc{
 			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) (r0 []shared1.AdjustedCodeIntelligenceRange, r1 error) {
 				return
@@ -914,6 +957,11 @@ func NewMockCodeNavService() *MockCodeNavService {
 				return
 			},
 		},
+		GetTypeDefinitionsFunc: &CodeNavServiceGetTypeDefinitionsFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState) (r0 []types.UploadLocation, r1 error) {
+				return
+			},
+		},
 		GetUnsafeDBFunc: &CodeNavServiceGetUnsafeDBFunc{
 			defaultHook: func() (r0 database.DB) {
 				return
@@ -926,6 +974,11 @@ func NewMockCodeNavService() *MockCodeNavService {
 // interface. All methods panic on invocation, unless overwritten.
 func NewStrictMockCodeNavService() *MockCodeNavService {
 	return &MockCodeNavService{
+		GetAPIDiffFunc: &CodeNavServiceGetAPIDiffFunc{
+			defaultHook: func(context.Context, int, string, string) (shared1.APIDiff, bool, error) {
+				panic("unexpected invocation of MockCodeNavService.GetAPIDiff")
+			},
+		},
 		GetClosestDumpsForBlobFunc: &CodeNavServiceGetClosestDumpsForBlobFunc{
 			defaultHook: func(context.Context, int, string, string, bool, string) ([]types.Dump, error) {
 				panic("unexpected invocation of MockCodeNavService.GetClosestDumpsForBlob")
@@ -941,6 +994,16 @@ func NewStrictMockCodeNavService() *MockCodeNavService {
 				panic("unexpected invocation of MockCodeNavService.GetDiagnostics")
 			},
 		},
+		GetDocumentSymbolsFunc: &CodeNavServiceGetDocumentSymbolsFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, bool, error) {
+				panic("unexpected invocation of MockCodeNavService.GetDocumentSymbols")
+			},
+		},
+		GetDownstreamReferencesFunc: &CodeNavServiceGetDownstreamReferencesFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState, string, shared1.RemoteCursor) ([]types.UploadLocation, shared1.RemoteCursor, bool, error) {
+				panic("unexpected invocation of MockCodeNavService.GetDownstreamReferences")
+			},
+		},
 		GetDumpsByIDsFunc: &CodeNavServiceGetDumpsByIDsFunc{
 			defaultHook: func(context.Context, []int) ([]types.Dump, error) {
 				panic("unexpected invocation of MockCodeNavService.GetDumpsByIDs")
@@ -956,6 +1019,16 @@ func NewStrictMockCodeNavService() *MockCodeNavService {
 				panic("unexpected invocation of MockCodeNavService.GetImplementations")
 			},
 		},
+		GetIncomingCallsFunc: &CodeNavServiceGetIncomingCallsFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]shared1.AdjustedCallHierarchyCall, shared1.ReferencesCursor, error) {
+				panic("unexpected invocation of MockCodeNavService.GetIncomingCalls")
+			},
+		},
+		GetOutgoingCallsFunc: &CodeNavServiceGetOutgoingCallsFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.AdjustedCallHierarchyCall, error) {
+				panic("unexpected invocation of MockCodeNavService.GetOutgoingCalls")
+			},
+		},
 		GetRangesFunc: &CodeNavServiceGetRangesFunc{
 			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error) {
 				panic("unexpected invocation of MockCodeNavService.GetRanges")
@@ -971,6 +1044,11 @@ func NewStrictMockCodeNavService() *MockCodeNavService {
 				panic("unexpected invocation of MockCodeNavService.GetStencil")
 			},
 		},
+		GetTypeDefinitionsFunc: &CodeNavServiceGetTypeDefinitionsFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error) {
+				panic("unexpected invocation of MockCodeNavService.GetTypeDefinitions")
+			},
+		},
 		GetUnsafeDBFunc: &CodeNavServiceGetUnsafeDBFunc{
 			defaultHook: func() database.DB {
 				panic("unexpected invocation of MockCodeNavService.GetUnsafeDB")
@@ -984,6 +1062,9 @@ func NewStrictMockCodeNavService() *MockCodeNavService {
 // overwritten.
 func NewMockCodeNavServiceFrom(i CodeNavService) *MockCodeNavService {
 	return &MockCodeNavService{
+		GetAPIDiffFunc: &CodeNavServiceGetAPIDiffFunc{
+			defaultHook: i.GetAPIDiff,
+		},
 		GetClosestDumpsForBlobFunc: &CodeNavServiceGetClosestDumpsForBlobFunc{
 			defaultHook: i.GetClosestDumpsForBlob,
 		},
@@ -993,6 +1074,12 @@ func NewMockCodeNavServiceFrom(i CodeNavService) *MockCodeNavService {
 		GetDiagnosticsFunc: &CodeNavServiceGetDiagnosticsFunc{
 			defaultHook: i.GetDiagnostics,
 		},
+		GetDocumentSymbolsFunc: &CodeNavServiceGetDocumentSymbolsFunc{
+			defaultHook: i.GetDocumentSymbols,
+		},
+		GetDownstreamReferencesFunc: &CodeNavServiceGetDownstreamReferencesFunc{
+			defaultHook: i.GetDownstreamReferences,
+		},
 		GetDumpsByIDsFunc: &CodeNavServiceGetDumpsByIDsFunc{
 			defaultHook: i.GetDumpsByIDs,
 		},
@@ -1002,6 +1089,12 @@ func NewMockCodeNavServiceFrom(i CodeNavService) *MockCodeNavService {
 		GetImplementationsFunc: &CodeNavServiceGetImplementationsFunc{
 			defaultHook: i.GetImplementations,
 		},
+		GetIncomingCallsFunc: &CodeNavServiceGetIncomingCallsFunc{
+			defaultHook: i.GetIncomingCalls,
+		},
+		GetOutgoingCallsFunc: &CodeNavServiceGetOutgoingCallsFunc{
+			defaultHook: i.GetOutgoingCalls,
+		},
 		GetRangesFunc: &CodeNavServiceGetRangesFunc{
 			defaultHook: i.GetRanges,
 		},
@@ -1011,12 +1104,132 @@ func NewMockCodeNavServiceFrom(i CodeNavService) *MockCodeNavService {
 		GetStencilFunc: &CodeNavServiceGetStencilFunc{
 			defaultHook: i.GetStencil,
 		},
+		GetTypeDefinitionsFunc: &CodeNavServiceGetTypeDefinitionsFunc{
+			defaultHook: i.GetTypeDefinitions,
+		},
 		GetUnsafeDBFunc: &CodeNavServiceGetUnsafeDBFunc{
 			defaultHook: i.GetUnsafeDB,
 		},
 	}
 }
 
+// CodeNavServiceGetAPIDiffFunc describes the behavior when the GetAPIDiff
+// method of the parent MockCodeNavService instance is invoked.
+type CodeNavServiceGetAPIDiffFunc struct {
+	defaultHook func(context.Context, int, string, string) (shared1.APIDiff, bool, error)
+	hooks       []func(context.Context, int, string, string) (shared1.APIDiff, bool, error)
+	history     []CodeNavServiceGetAPIDiffFuncCall
+	mutex       sync.Mutex
+}
+
+// GetAPIDiff delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetAPIDiff(v0 context.Context, v1 int, v2 string, v3 string) (shared1.APIDiff, bool, error) {
+	r0, r1, r2 := m.GetAPIDiffFunc.nextHook()(v0, v1, v2, v3)
+	m.GetAPIDiffFunc.appendCall(CodeNavServiceGetAPIDiffFuncCall{v0, v1, v2, v3, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetAPIDiff method of
+// the parent MockCodeNavService instance is invoked and the hook queue is
+// empty.
+func (f *CodeNavServiceGetAPIDiffFunc) SetDefaultHook(hook func(context.Context, int, string, string) (shared1.APIDiff, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetAPIDiff method of the parent MockCodeNavService instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetAPIDiffFunc) PushHook(hook func(context.Context, int, string, string) (shared1.APIDiff, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetAPIDiffFunc) SetDefaultReturn(r0 shared1.APIDiff, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string) (shared1.APIDiff, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetAPIDiffFunc) PushReturn(r0 shared1.APIDiff, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int, string, string) (shared1.APIDiff, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *CodeNavServiceGetAPIDiffFunc) nextHook() func(context.Context, int, string, string) (shared1.APIDiff, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetAPIDiffFunc) appendCall(r0 CodeNavServiceGetAPIDiffFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetAPIDiffFuncCall objects
+// describing the invocations of this function.
+func (f *CodeNavServiceGetAPIDiffFunc) History() []CodeNavServiceGetAPIDiffFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetAPIDiffFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetAPIDiffFuncCall is an object that describes an
+// invocation of method GetAPIDiff on an instance of MockCodeNavService.
+type CodeNavServiceGetAPIDiffFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 shared1.APIDiff
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetAPIDiffFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetAPIDiffFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // CodeNavServiceGetClosestDumpsForBlobFunc describes the behavior when the
 // GetClosestDumpsForBlob method of the parent MockCodeNavService instance
 // is invoked.
@@ -1368,36 +1581,37 @@ func (c CodeNavServiceGetDiagnosticsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
-// CodeNavServiceGetDumpsByIDsFunc describes the behavior when the
-// GetDumpsByIDs method of the parent MockCodeNavService instance is
+// CodeNavServiceGetDocumentSymbolsFunc describes the behavior when the
+// GetDocumentSymbols method of the parent MockCodeNavService instance is
 // invoked.
-type CodeNavServiceGetDumpsByIDsFunc struct {
-	defaultHook func(context.Context, []int) ([]types.Dump, error)
-	hooks       []func(context.Context, []int) ([]types.Dump, error)
-	history     []CodeNavServiceGetDumpsByIDsFuncCall
+type CodeNavServiceGetDocumentSymbolsFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, bool, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, bool, error)
+	history     []CodeNavServiceGetDocumentSymbolsFuncCall
 	mutex       sync.Mutex
 }
 
-// GetDumpsByIDs delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockCodeNavService) GetDumpsByIDs(v0 context.Context, v1 []int) ([]types.Dump, error) {
-	r0, r1 := m.GetDumpsByIDsFunc.nextHook()(v0, v1)
-	m.GetDumpsByIDsFunc.appendCall(CodeNavServiceGetDumpsByIDsFuncCall{v0, v1, r0, r1})
-	return r0, r1
+// GetDocumentSymbols delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetDocumentSymbols(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState) ([]shared1.DocumentSymbol, bool, error) {
+	r0, r1, r2 := m.GetDocumentSymbolsFunc.nextHook()(v0, v1, v2)
+	m.GetDocumentSymbolsFunc.appendCall(CodeNavServiceGetDocumentSymbolsFuncCall{v0, v1, v2, r0, r1, r2})
+	return r0, r1, r2
 }
 
-// SetDefaultHook sets function that is called when the GetDumpsByIDs method
-// of the parent MockCodeNavService instance is invoked and the hook queue
-// is empty.
-func (f *CodeNavServiceGetDumpsByIDsFunc) SetDefaultHook(hook func(context.Context, []int) ([]types.Dump, error)) {
+// SetDefaultHook sets function that is called when the GetDocumentSymbols
+// method of the parent MockCodeNavService instance is invoked and the hook
+// queue is empty.
+func (f *CodeNavServiceGetDocumentSymbolsFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, bool, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetDumpsByIDs method of the parent MockCodeNavService instance invokes
-// the hook at the front of the queue and discards it. After the queue is
-// empty, the default hook function is invoked for any future action.
-func (f *CodeNavServiceGetDumpsByIDsFunc) PushHook(hook func(context.Context, []int) ([]types.Dump, error)) {
+// GetDocumentSymbols method of the parent MockCodeNavService instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeNavServiceGetDocumentSymbolsFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, bool, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1405,20 +1619,20 @@ func (f *CodeNavServiceGetDumpsByIDsFunc) PushHook(hook func(context.Context, []
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *CodeNavServiceGetDumpsByIDsFunc) SetDefaultReturn(r0 []types.Dump, r1 error) {
-	f.SetDefaultHook(func(context.Context, []int) ([]types.Dump, error) {
-		return r0, r1
+func (f *CodeNavServiceGetDocumentSymbolsFunc) SetDefaultReturn(r0 []shared1.DocumentSymbol, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, bool, error) {
+		return r0, r1, r2
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *CodeNavServiceGetDumpsByIDsFunc) PushReturn(r0 []types.Dump, r1 error) {
-	f.PushHook(func(context.Context, []int) ([]types.Dump, error) {
-		return r0, r1
+func (f *CodeNavServiceGetDocumentSymbolsFunc) PushReturn(r0 []shared1.DocumentSymbol, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, bool, error) {
+		return r0, r1, r2
 	})
 }
 
-func (f *CodeNavServiceGetDumpsByIDsFunc) nextHook() func(context.Context, []int) ([]types.Dump, error) {
+func (f *CodeNavServiceGetDocumentSymbolsFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, bool, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1431,81 +1645,90 @@ func (f *CodeNavServiceGetDumpsByIDsFunc) nextHook() func(context.Context, []int
 	return hook
 }
 
-func (f *CodeNavServiceGetDumpsByIDsFunc) appendCall(r0 CodeNavServiceGetDumpsByIDsFuncCall) {
+func (f *CodeNavServiceGetDocumentSymbolsFunc) appendCall(r0 CodeNavServiceGetDocumentSymbolsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of CodeNavServiceGetDumpsByIDsFuncCall objects
-// describing the invocations of this function.
-func (f *CodeNavServiceGetDumpsByIDsFunc) History() []CodeNavServiceGetDumpsByIDsFuncCall {
+// History returns a sequence of CodeNavServiceGetDocumentSymbolsFuncCall
+// objects describing the invocations of this function.
+func (f *CodeNavServiceGetDocumentSymbolsFunc) History() []CodeNavServiceGetDocumentSymbolsFuncCall {
 	f.mutex.Lock()
-	history := make([]CodeNavServiceGetDumpsByIDsFuncCall, len(f.history))
+	history := make([]CodeNavServiceGetDocumentSymbolsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// CodeNavServiceGetDumpsByIDsFuncCall is an object that describes an
-// invocation of method GetDumpsByIDs on an instance of MockCodeNavService.
-type CodeNavServiceGetDumpsByIDsFuncCall struct {
+// CodeNavServiceGetDocumentSymbolsFuncCall is an object that describes an
+// invocation of method GetDocumentSymbols on an instance of
+// MockCodeNavService.
+type CodeNavServiceGetDocumentSymbolsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 []int
+	Arg1 shared1.RequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []types.Dump
+	Result0 []shared1.DocumentSymbol
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
-	Result1 error
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c CodeNavServiceGetDumpsByIDsFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1}
+func (c CodeNavServiceGetDocumentSymbolsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c CodeNavServiceGetDumpsByIDsFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0, c.Result1}
+func (c CodeNavServiceGetDocumentSymbolsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
-// CodeNavServiceGetHoverFunc describes the behavior when the GetHover
-// method of the parent MockCodeNavService instance is invoked.
-type CodeNavServiceGetHoverFunc struct {
-	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState) (string, types.Range, bool, error)
-	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState) (string, types.Range, bool, error)
-	history     []CodeNavServiceGetHoverFuncCall
+// CodeNavServiceGetDownstreamReferencesFunc describes the behavior when the
+// GetDownstreamReferences method of the parent MockCodeNavService instance
+// is invoked.
+type CodeNavServiceGetDownstreamReferencesFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState, string, shared1.RemoteCursor) ([]types.UploadLocation, shared1.RemoteCursor, bool, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState, string, shared1.RemoteCursor) ([]types.UploadLocation, shared1.RemoteCursor, bool, error)
+	history     []CodeNavServiceGetDownstreamReferencesFuncCall
 	mutex       sync.Mutex
 }
 
-// GetHover delegates to the next hook function in the queue and stores the
-// parameter and result values of this invocation.
-func (m *MockCodeNavService) GetHover(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState) (string, types.Range, bool, error) {
-	r0, r1, r2, r3 := m.GetHoverFunc.nextHook()(v0, v1, v2)
-	m.GetHoverFunc.appendCall(CodeNavServiceGetHoverFuncCall{v0, v1, v2, r0, r1, r2, r3})
+// GetDownstreamReferences delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetDownstreamReferences(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState, v3 string, v4 shared1.RemoteCursor) ([]types.UploadLocation, shared1.RemoteCursor, bool, error) {
+	r0, r1, r2, r3 := m.GetDownstreamReferencesFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.GetDownstreamReferencesFunc.appendCall(CodeNavServiceGetDownstreamReferencesFuncCall{v0, v1, v2, v3, v4, r0, r1, r2, r3})
 	return r0, r1, r2, r3
 }
 
-// SetDefaultHook sets function that is called when the GetHover method of
-// the parent MockCodeNavService instance is invoked and the hook queue is
-// empty.
-func (f *CodeNavServiceGetHoverFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) (string, types.Range, bool, error)) {
+// SetDefaultHook sets function that is called when the
+// GetDownstreamReferences method of the parent MockCodeNavService instance
+// is invoked and the hook queue is empty.
+func (f *CodeNavServiceGetDownstreamReferencesFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, string, shared1.RemoteCursor) ([]types.UploadLocation, shared1.RemoteCursor, bool, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetHover method of the parent MockCodeNavService instance invokes the
-// hook at the front of the queue and discards it. After the queue is empty,
-// the default hook function is invoked for any future action.
-func (f *CodeNavServiceGetHoverFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) (string, types.Range, bool, error)) {
+// GetDownstreamReferences method of the parent MockCodeNavService instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeNavServiceGetDownstreamReferencesFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, string, shared1.RemoteCursor) ([]types.UploadLocation, shared1.RemoteCursor, bool, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1513,20 +1736,20 @@ func (f *CodeNavServiceGetHoverFunc) PushHook(hook func(context.Context, shared1
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *CodeNavServiceGetHoverFunc) SetDefaultReturn(r0 string, r1 types.Range, r2 bool, r3 error) {
-	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) (string, types.Range, bool, error) {
+func (f *CodeNavServiceGetDownstreamReferencesFunc) SetDefaultReturn(r0 []types.UploadLocation, r1 shared1.RemoteCursor, r2 bool, r3 error) {
+	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, string, shared1.RemoteCursor) ([]types.UploadLocation, shared1.RemoteCursor, bool, error) {
 		return r0, r1, r2, r3
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *CodeNavServiceGetHoverFunc) PushReturn(r0 string, r1 types.Range, r2 bool, r3 error) {
-	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) (string, types.Range, bool, error) {
+func (f *CodeNavServiceGetDownstreamReferencesFunc) PushReturn(r0 []types.UploadLocation, r1 shared1.RemoteCursor, r2 bool, r3 error) {
+	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, string, shared1.RemoteCursor) ([]types.UploadLocation, shared1.RemoteCursor, bool, error) {
 		return r0, r1, r2, r3
 	})
 }
 
-func (f *CodeNavServiceGetHoverFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState) (string, types.Range, bool, error) {
+func (f *CodeNavServiceGetDownstreamReferencesFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState, string, shared1.RemoteCursor) ([]types.UploadLocation, shared1.RemoteCursor, bool, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1539,26 +1762,28 @@ func (f *CodeNavServiceGetHoverFunc) nextHook() func(context.Context, shared1.Re
 	return hook
 }
 
-func (f *CodeNavServiceGetHoverFunc) appendCall(r0 CodeNavServiceGetHoverFuncCall) {
+func (f *CodeNavServiceGetDownstreamReferencesFunc) appendCall(r0 CodeNavServiceGetDownstreamReferencesFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of CodeNavServiceGetHoverFuncCall objects
-// describing the invocations of this function.
-func (f *CodeNavServiceGetHoverFunc) History() []CodeNavServiceGetHoverFuncCall {
+// History returns a sequence of
+// CodeNavServiceGetDownstreamReferencesFuncCall objects describing the
+// invocations of this function.
+func (f *CodeNavServiceGetDownstreamReferencesFunc) History() []CodeNavServiceGetDownstreamReferencesFuncCall {
 	f.mutex.Lock()
-	history := make([]CodeNavServiceGetHoverFuncCall, len(f.history))
+	history := make([]CodeNavServiceGetDownstreamReferencesFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// CodeNavServiceGetHoverFuncCall is an object that describes an invocation
-// of method GetHover on an instance of MockCodeNavService.
-type CodeNavServiceGetHoverFuncCall struct {
+// CodeNavServiceGetDownstreamReferencesFuncCall is an object that describes
+// an invocation of method GetDownstreamReferences on an instance of
+// MockCodeNavService.
+type CodeNavServiceGetDownstreamReferencesFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -1568,12 +1793,18 @@ type CodeNavServiceGetHoverFuncCall struct {
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
 	Arg2 codenav.RequestState
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 shared1.RemoteCursor
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 string
+	Result0 []types.UploadLocation
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
-	Result1 types.Range
+	Result1 shared1.RemoteCursor
 	// Result2 is the value of the 3rd result returned from this method
 	// invocation.
 	Result2 bool
@@ -1584,23 +1815,249 @@ type CodeNavServiceGetHoverFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c CodeNavServiceGetHoverFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+func (c CodeNavServiceGetDownstreamReferencesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c CodeNavServiceGetHoverFuncCall) Results() []interface{} {
+func (c CodeNavServiceGetDownstreamReferencesFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2, c.Result3}
 }
 
-// CodeNavServiceGetImplementationsFunc describes the behavior when the
-// GetImplementations method of the parent MockCodeNavService instance is
+// CodeNavServiceGetDumpsByIDsFunc describes the behavior when the
+// GetDumpsByIDs method of the parent MockCodeNavService instance is
 // invoked.
-type CodeNavServiceGetImplementationsFunc struct {
-	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ImplementationsCursor) ([]types.UploadLocation, shared1.ImplementationsCursor, error)
-	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ImplementationsCursor) ([]types.UploadLocation, shared1.ImplementationsCursor, error)
-	history     []CodeNavServiceGetImplementationsFuncCall
+type CodeNavServiceGetDumpsByIDsFunc struct {
+	defaultHook func(context.Context, []int) ([]types.Dump, error)
+	hooks       []func(context.Context, []int) ([]types.Dump, error)
+	history     []CodeNavServiceGetDumpsByIDsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetDumpsByIDs delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetDumpsByIDs(v0 context.Context, v1 []int) ([]types.Dump, error) {
+	r0, r1 := m.GetDumpsByIDsFunc.nextHook()(v0, v1)
+	m.GetDumpsByIDsFunc.appendCall(CodeNavServiceGetDumpsByIDsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetDumpsByIDs method
+// of the parent MockCodeNavService instance is invoked and the hook queue
+// is empty.
+func (f *CodeNavServiceGetDumpsByIDsFunc) SetDefaultHook(hook func(context.Context, []int) ([]types.Dump, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetDumpsByIDs method of the parent MockCodeNavService instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetDumpsByIDsFunc) PushHook(hook func(context.Context, []int) ([]types.Dump, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetDumpsByIDsFunc) SetDefaultReturn(r0 []types.Dump, r1 error) {
+	f.SetDefaultHook(func(context.Context, []int) ([]types.Dump, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetDumpsByIDsFunc) PushReturn(r0 []types.Dump, r1 error) {
+	f.PushHook(func(context.Context, []int) ([]types.Dump, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeNavServiceGetDumpsByIDsFunc) nextHook() func(context.Context, []int) ([]types.Dump, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetDumpsByIDsFunc) appendCall(r0 CodeNavServiceGetDumpsByIDsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetDumpsByIDsFuncCall objects
+// describing the invocations of this function.
+func (f *CodeNavServiceGetDumpsByIDsFunc) History() []CodeNavServiceGetDumpsByIDsFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetDumpsByIDsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetDumpsByIDsFuncCall is an object that describes an
+// invocation of method GetDumpsByIDs on an instance of MockCodeNavService.
+type CodeNavServiceGetDumpsByIDsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []types.Dump
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetDumpsByIDsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetDumpsByIDsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// CodeNavServiceGetHoverFunc describes the behavior when the GetHover
+// method of the parent MockCodeNavService instance is invoked.
+type CodeNavServiceGetHoverFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState) (string, types.Range, bool, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState) (string, types.Range, bool, error)
+	history     []CodeNavServiceGetHoverFuncCall
+	mutex       sync.Mutex
+}
+
+// GetHover delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockCodeNavService) GetHover(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState) (string, types.Range, bool, error) {
+	r0, r1, r2, r3 := m.GetHoverFunc.nextHook()(v0, v1, v2)
+	m.GetHoverFunc.appendCall(CodeNavServiceGetHoverFuncCall{v0, v1, v2, r0, r1, r2, r3})
+	return r0, r1, r2, r3
+}
+
+// SetDefaultHook sets function that is called when the GetHover method of
+// the parent MockCodeNavService instance is invoked and the hook queue is
+// empty.
+func (f *CodeNavServiceGetHoverFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) (string, types.Range, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetHover method of the parent MockCodeNavService instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetHoverFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) (string, types.Range, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetHoverFunc) SetDefaultReturn(r0 string, r1 types.Range, r2 bool, r3 error) {
+	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) (string, types.Range, bool, error) {
+		return r0, r1, r2, r3
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetHoverFunc) PushReturn(r0 string, r1 types.Range, r2 bool, r3 error) {
+	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) (string, types.Range, bool, error) {
+		return r0, r1, r2, r3
+	})
+}
+
+func (f *CodeNavServiceGetHoverFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState) (string, types.Range, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetHoverFunc) appendCall(r0 CodeNavServiceGetHoverFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetHoverFuncCall objects
+// describing the invocations of this function.
+func (f *CodeNavServiceGetHoverFunc) History() []CodeNavServiceGetHoverFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetHoverFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetHoverFuncCall is an object that describes an invocation
+// of method GetHover on an instance of MockCodeNavService.
+type CodeNavServiceGetHoverFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared1.RequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 string
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 types.Range
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 bool
+	// Result3 is the value of the 4th result returned from this method
+	// invocation.
+	Result3 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetHoverFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetHoverFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2, c.Result3}
+}
+
+// CodeNavServiceGetImplementationsFunc describes the behavior when the
+// GetImplementations method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGetImplementationsFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ImplementationsCursor) ([]types.UploadLocation, shared1.ImplementationsCursor, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ImplementationsCursor) ([]types.UploadLocation, shared1.ImplementationsCursor, error)
+	history     []CodeNavServiceGetImplementationsFuncCall
 	mutex       sync.Mutex
 }
 
@@ -1714,6 +2171,238 @@ func (c CodeNavServiceGetImplementationsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// CodeNavServiceGetIncomingCallsFunc describes the behavior when the
+// GetIncomingCalls method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGetIncomingCallsFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]shared1.AdjustedCallHierarchyCall, shared1.ReferencesCursor, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]shared1.AdjustedCallHierarchyCall, shared1.ReferencesCursor, error)
+	history     []CodeNavServiceGetIncomingCallsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetIncomingCalls delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetIncomingCalls(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState, v3 shared1.ReferencesCursor) ([]shared1.AdjustedCallHierarchyCall, shared1.ReferencesCursor, error) {
+	r0, r1, r2 := m.GetIncomingCallsFunc.nextHook()(v0, v1, v2, v3)
+	m.GetIncomingCallsFunc.appendCall(CodeNavServiceGetIncomingCallsFuncCall{v0, v1, v2, v3, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetIncomingCalls
+// method of the parent MockCodeNavService instance is invoked and the hook
+// queue is empty.
+func (f *CodeNavServiceGetIncomingCallsFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]shared1.AdjustedCallHierarchyCall, shared1.ReferencesCursor, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetIncomingCalls method of the parent MockCodeNavService instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetIncomingCallsFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]shared1.AdjustedCallHierarchyCall, shared1.ReferencesCursor, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetIncomingCallsFunc) SetDefaultReturn(r0 []shared1.AdjustedCallHierarchyCall, r1 shared1.ReferencesCursor, r2 error) {
+	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]shared1.AdjustedCallHierarchyCall, shared1.ReferencesCursor, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetIncomingCallsFunc) PushReturn(r0 []shared1.AdjustedCallHierarchyCall, r1 shared1.ReferencesCursor, r2 error) {
+	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]shared1.AdjustedCallHierarchyCall, shared1.ReferencesCursor, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *CodeNavServiceGetIncomingCallsFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]shared1.AdjustedCallHierarchyCall, shared1.ReferencesCursor, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetIncomingCallsFunc) appendCall(r0 CodeNavServiceGetIncomingCallsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetIncomingCallsFuncCall
+// objects describing the invocations of this function.
+func (f *CodeNavServiceGetIncomingCallsFunc) History() []CodeNavServiceGetIncomingCallsFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetIncomingCallsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetIncomingCallsFuncCall is an object that describes an
+// invocation of method GetIncomingCalls on an instance of
+// MockCodeNavService.
+type CodeNavServiceGetIncomingCallsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared1.RequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 shared1.ReferencesCursor
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared1.AdjustedCallHierarchyCall
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 shared1.ReferencesCursor
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetIncomingCallsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetIncomingCallsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// CodeNavServiceGetOutgoingCallsFunc describes the behavior when the
+// GetOutgoingCalls method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGetOutgoingCallsFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.AdjustedCallHierarchyCall, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.AdjustedCallHierarchyCall, error)
+	history     []CodeNavServiceGetOutgoingCallsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetOutgoingCalls delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetOutgoingCalls(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState) ([]shared1.AdjustedCallHierarchyCall, error) {
+	r0, r1 := m.GetOutgoingCallsFunc.nextHook()(v0, v1, v2)
+	m.GetOutgoingCallsFunc.appendCall(CodeNavServiceGetOutgoingCallsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetOutgoingCalls
+// method of the parent MockCodeNavService instance is invoked and the hook
+// queue is empty.
+func (f *CodeNavServiceGetOutgoingCallsFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.AdjustedCallHierarchyCall, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetOutgoingCalls method of the parent MockCodeNavService instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetOutgoingCallsFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.AdjustedCallHierarchyCall, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetOutgoingCallsFunc) SetDefaultReturn(r0 []shared1.AdjustedCallHierarchyCall, r1 error) {
+	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.AdjustedCallHierarchyCall, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetOutgoingCallsFunc) PushReturn(r0 []shared1.AdjustedCallHierarchyCall, r1 error) {
+	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.AdjustedCallHierarchyCall, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeNavServiceGetOutgoingCallsFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.AdjustedCallHierarchyCall, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetOutgoingCallsFunc) appendCall(r0 CodeNavServiceGetOutgoingCallsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetOutgoingCallsFuncCall
+// objects describing the invocations of this function.
+func (f *CodeNavServiceGetOutgoingCallsFunc) History() []CodeNavServiceGetOutgoingCallsFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetOutgoingCallsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetOutgoingCallsFuncCall is an object that describes an
+// invocation of method GetOutgoingCalls on an instance of
+// MockCodeNavService.
+type CodeNavServiceGetOutgoingCallsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared1.RequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared1.AdjustedCallHierarchyCall
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetOutgoingCallsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetOutgoingCallsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // CodeNavServiceGetRangesFunc describes the behavior when the GetRanges
 // method of the parent MockCodeNavService instance is invoked.
 type CodeNavServiceGetRangesFunc struct {
@@ -2060,6 +2749,120 @@ func (c CodeNavServiceGetStencilFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// CodeNavServiceGetTypeDefinitionsFunc describes the behavior when the
+// GetTypeDefinitions method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGetTypeDefinitionsFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error)
+	history     []CodeNavServiceGetTypeDefinitionsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetTypeDefinitions delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetTypeDefinitions(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState) ([]types.UploadLocation, error) {
+	r0, r1 := m.GetTypeDefinitionsFunc.nextHook()(v0, v1, v2)
+	m.GetTypeDefinitionsFunc.appendCall(CodeNavServiceGetTypeDefinitionsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetTypeDefinitions
+// method of the parent MockCodeNavService instance is invoked and the hook
+// queue is empty.
+func (f *CodeNavServiceGetTypeDefinitionsFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetTypeDefinitions method of the parent MockCodeNavService instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeNavServiceGetTypeDefinitionsFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetTypeDefinitionsFunc) SetDefaultReturn(r0 []types.UploadLocation, r1 error) {
+	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetTypeDefinitionsFunc) PushReturn(r0 []types.UploadLocation, r1 error) {
+	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeNavServiceGetTypeDefinitionsFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetTypeDefinitionsFunc) appendCall(r0 CodeNavServiceGetTypeDefinitionsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetTypeDefinitionsFuncCall
+// objects describing the invocations of this function.
+func (f *CodeNavServiceGetTypeDefinitionsFunc) History() []CodeNavServiceGetTypeDefinitionsFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetTypeDefinitionsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetTypeDefinitionsFuncCall is an object that describes an
+// invocation of method GetTypeDefinitions on an instance of
+// MockCodeNavService.
+type CodeNavServiceGetTypeDefinitionsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared1.RequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []types.UploadLocation
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetTypeDefinitionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetTypeDefinitionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // CodeNavServiceGetUnsafeDBFunc describes the behavior when the GetUnsafeDB
 // method of the parent MockCodeNavService instance is invoked.
 type CodeNavServiceGetUnsafeDBFunc struct {
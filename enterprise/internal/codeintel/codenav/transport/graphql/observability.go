@@ -18,11 +18,17 @@ type operations struct {
 	definitions     *observation.Operation
 	references      *observation.Operation
 	implementations *observation.Operation
+	typeDefinitions *observation.Operation
+	incomingCalls   *observation.Operation
+	outgoingCalls   *observation.Operation
 	diagnostics     *observation.Operation
 	stencil         *observation.Operation
 	ranges          *observation.Operation
 
-	gitBlobLsifData *observation.Operation
+	gitBlobLsifData        *observation.Operation
+	gitBlobDocumentSymbols *observation.Operation
+	codeIntelAPIDiff       *observation.Operation
+	downstreamReferences   *observation.Operation
 }
 
 func newOperations(observationCtx *observation.Context) *operations {
@@ -46,11 +52,17 @@ func newOperations(observationCtx *observation.Context) *operations {
 		definitions:     op("Definitions"),
 		references:      op("References"),
 		implementations: op("Implementations"),
+		typeDefinitions: op("TypeDefinitions"),
+		incomingCalls:   op("IncomingCalls"),
+		outgoingCalls:   op("OutgoingCalls"),
 		diagnostics:     op("Diagnostics"),
 		stencil:         op("Stencil"),
 		ranges:          op("Ranges"),
 
-		gitBlobLsifData: op("GitBlobLsifData"),
+		gitBlobLsifData:        op("GitBlobLsifData"),
+		gitBlobDocumentSymbols: op("GitBlobDocumentSymbols"),
+		codeIntelAPIDiff:       op("CodeIntelAPIDiff"),
+		downstreamReferences:   op("DownstreamReferences"),
 	}
 }
 
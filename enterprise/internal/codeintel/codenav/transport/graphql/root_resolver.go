@@ -3,12 +3,19 @@ package graphql
 import (
 	"context"
 	"strings"
+	"time"
+
+	traceLog "github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	sharedresolvers "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/resolvers"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 type rootResolver struct {
@@ -65,3 +72,63 @@ func (r *rootResolver) GitBlobLSIFData(ctx context.Context, args *resolverstubs.
 
 	return NewGitBlobLSIFDataResolver(r.svc, r.autoindexingSvc, r.uploadSvc, r.policiesSvc, reqState, errTracer, r.operations), nil
 }
+
+// GitBlobDocumentSymbols returns the hierarchy of symbols defined in the given blob. Unlike GitBlobLSIFData,
+// this never resolves to null: blobs without a covering SCIP upload fall back to search-based symbols.
+//
+// 🚨 SECURITY: dbstore layer handles authz for query resolution
+func (r *rootResolver) GitBlobDocumentSymbols(ctx context.Context, args *resolverstubs.GitBlobLSIFDataArgs) (_ resolverstubs.DocumentSymbolsResolver, err error) {
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.gitBlobDocumentSymbols, time.Second, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", int(args.Repo.ID)),
+			traceLog.String("commit", string(args.Commit)),
+			traceLog.String("path", args.Path),
+			traceLog.String("toolName", args.ToolName),
+		},
+	})
+	defer endObservation()
+
+	uploads, err := r.svc.GetClosestDumpsForBlob(ctx, int(args.Repo.ID), string(args.Commit), args.Path, args.ExactPath, args.ToolName)
+	if err != nil {
+		return nil, err
+	}
+
+	reqState := codenav.NewRequestState(uploads, authz.DefaultSubRepoPermsChecker, r.gitserver, args.Repo, string(args.Commit), args.Path, r.maximumIndexesPerMonikerSearch, r.hunkCache)
+	requestArgs := shared.RequestArgs{RepositoryID: reqState.RepositoryID, Commit: reqState.Commit, Path: reqState.Path}
+
+	symbols, precise, err := r.svc.GetDocumentSymbols(ctx, requestArgs, reqState)
+	if err != nil {
+		return nil, errors.Wrap(err, "svc.GetDocumentSymbols")
+	}
+
+	return NewDocumentSymbolsResolver(symbols, precise), nil
+}
+
+// CodeIntelAPIDiff compares the exported symbols of the SCIP uploads of the given base and head commits.
+// This resolves to null if either commit has no SCIP upload.
+//
+// 🚨 SECURITY: dbstore layer handles authz for query resolution
+func (r *rootResolver) CodeIntelAPIDiff(ctx context.Context, args *resolverstubs.CodeIntelAPIDiffArgs) (_ resolverstubs.CodeIntelAPIDiffResolver, err error) {
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.codeIntelAPIDiff, time.Second, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", int(args.Repo.ID)),
+			traceLog.String("base", string(args.Base)),
+			traceLog.String("head", string(args.Head)),
+		},
+	})
+	defer endObservation()
+
+	diff, ok, err := r.svc.GetAPIDiff(ctx, int(args.Repo.ID), string(args.Base), string(args.Head))
+	if err != nil {
+		return nil, errors.Wrap(err, "svc.GetAPIDiff")
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	baseRequestState := codenav.NewRequestState(diff.BaseUploads, authz.DefaultSubRepoPermsChecker, r.gitserver, args.Repo, string(args.Base), "", r.maximumIndexesPerMonikerSearch, r.hunkCache)
+	headRequestState := codenav.NewRequestState(diff.HeadUploads, authz.DefaultSubRepoPermsChecker, r.gitserver, args.Repo, string(args.Head), "", r.maximumIndexesPerMonikerSearch, r.hunkCache)
+	locationResolver := sharedresolvers.NewCachedLocationResolver(r.autoindexingSvc.GetUnsafeDB(), gitserver.NewClient())
+
+	return NewAPIDiffResolver(r.svc, diff, baseRequestState, headRequestState, locationResolver, r.operations), nil
+}
@@ -82,6 +82,18 @@ var (
 		Name: "scip-ruby",
 		URN:  "github.com/sourcegraph/scip-ruby",
 	}
+	scipDotnet = CodeIntelIndexer{
+		Name: "scip-dotnet",
+		URN:  "github.com/sourcegraph/scip-dotnet",
+	}
+	scipPHP = CodeIntelIndexer{
+		Name: "scip-php",
+		URN:  "github.com/davidrjenni/scip-php",
+	}
+	scipDart = CodeIntelIndexer{
+		Name: "scip-dart",
+		URN:  "github.com/Workiva/scip-dart",
+	}
 )
 
 var AllIndexers = []CodeIntelIndexer{
@@ -104,6 +116,9 @@ var AllIndexers = []CodeIntelIndexer{
 	lsifTerraform,
 	lsifDotnet,
 	scipRuby,
+	scipDotnet,
+	scipPHP,
+	scipDart,
 }
 
 // A map of file extension to a list of indexers in order of recommendation
@@ -117,7 +132,7 @@ var LanguageToIndexer = map[string][]CodeIntelIndexer{
 	".jsx":     {scipTypescript, lsifNode, msftNode},
 	".ts":      {scipTypescript, lsifNode, msftNode},
 	".tsx":     {scipTypescript, lsifNode, msftNode},
-	".dart":    {workivaDart, lsifDart},
+	".dart":    {scipDart, workivaDart, lsifDart},
 	".c":       {lsifClang, lsifCPP},
 	".cc":      {lsifClang, lsifCPP},
 	".cpp":     {lsifClang, lsifCPP},
@@ -129,9 +144,9 @@ var LanguageToIndexer = map[string][]CodeIntelIndexer{
 	".py":      {scipPython},
 	".ml":      {lsifOcaml},
 	".rs":      {rustAnalyzer},
-	".php":     {lsifPHP},
+	".php":     {scipPHP, lsifPHP},
 	".tf":      {lsifTerraform},
-	".cs":      {lsifDotnet},
+	".cs":      {scipDotnet, lsifDotnet},
 	".rb":      {scipRuby},
 }
 
@@ -146,6 +161,9 @@ var ImageToIndexer = map[string]CodeIntelIndexer{
 	"sourcegraph/scip-rust":       rustAnalyzer,
 	"sourcegraph/scip-python":     scipPython,
 	"sourcegraph/scip-ruby":       scipRuby,
+	"sourcegraph/scip-dotnet":     scipDotnet,
+	"davidrjenni/scip-php":        scipPHP,
+	"workiva/scip-dart":           scipDart,
 }
 
 var PreferredIndexers = map[string]CodeIntelIndexer{
@@ -169,4 +187,7 @@ var PreferredIndexers = map[string]CodeIntelIndexer{
 	"lsif-terraform":  lsifTerraform,
 	"lsif-dotnet":     lsifDotnet,
 	"scip-ruby":       scipRuby,
+	"scip-dotnet":     scipDotnet,
+	"scip-php":        scipPHP,
+	"scip-dart":       scipDart,
 }
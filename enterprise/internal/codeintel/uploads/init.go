@@ -28,6 +28,8 @@ import (
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
+var UploadWorkerStoreOptions = store.UploadWorkerStoreOptions
+
 func NewService(
 	observationCtx *observation.Context,
 	db database.DB,
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/authz/azuredevops"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/authz/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/authz/github"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/authz/gitlab"
@@ -14,4 +15,4 @@ var ValidateExternalServiceConfig = database.MakeValidateExternalServiceConfigFu
 	[]func(*schema.GitLabConnection, []schema.AuthProviders) error{gitlab.ValidateAuthz},
 	[]func(*schema.BitbucketServerConnection) error{bitbucketserver.ValidateAuthz},
 	[]func(connection *schema.PerforceConnection) error{perforce.ValidateAuthz},
-	[]func(connection *schema.AzureDevOpsConnection) error{func(_ *schema.AzureDevOpsConnection) error { return nil }}) // TODO: @varsanojidan switch this with actual authz once its implemented.
+	[]func(connection *schema.AzureDevOpsConnection) error{azuredevops.ValidateAuthz})
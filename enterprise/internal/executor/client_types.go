@@ -18,6 +18,10 @@ type Job struct {
 	// that different queues can share identifiers.
 	ID int `json:"id"`
 
+	// Queue is the name of the queue the job was dequeued from. It is only set
+	// for jobs dequeued from multiple queues at once.
+	Queue string `json:"queue,omitempty"`
+
 	// RepositoryName is the name of the repository to be cloned into the
 	// workspace prior to job execution.
 	RepositoryName string `json:"repositoryName"`
@@ -73,6 +77,7 @@ func (j Job) MarshalJSON() ([]byte, error) {
 		v2 := v2Job{
 			Version:             j.Version,
 			ID:                  j.ID,
+			Queue:               j.Queue,
 			RepositoryName:      j.RepositoryName,
 			RepositoryDirectory: j.RepositoryDirectory,
 			Commit:              j.Commit,
@@ -92,6 +97,7 @@ func (j Job) MarshalJSON() ([]byte, error) {
 	}
 	v1 := v1Job{
 		ID:                  j.ID,
+		Queue:               j.Queue,
 		RepositoryName:      j.RepositoryName,
 		RepositoryDirectory: j.RepositoryDirectory,
 		Commit:              j.Commit,
@@ -126,6 +132,7 @@ func (j *Job) UnmarshalJSON(data []byte) error {
 		}
 		j.Version = v2.Version
 		j.ID = v2.ID
+		j.Queue = v2.Queue
 		j.RepositoryName = v2.RepositoryName
 		j.RepositoryDirectory = v2.RepositoryDirectory
 		j.Commit = v2.Commit
@@ -147,6 +154,7 @@ func (j *Job) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	j.ID = v1.ID
+	j.Queue = v1.Queue
 	j.RepositoryName = v1.RepositoryName
 	j.RepositoryDirectory = v1.RepositoryDirectory
 	j.Commit = v1.Commit
@@ -175,6 +183,7 @@ type versionJob struct {
 type v2Job struct {
 	Version             int                             `json:"version,omitempty"`
 	ID                  int                             `json:"id"`
+	Queue               string                          `json:"queue,omitempty"`
 	RepositoryName      string                          `json:"repositoryName"`
 	RepositoryDirectory string                          `json:"repositoryDirectory"`
 	Commit              string                          `json:"commit"`
@@ -190,6 +199,7 @@ type v2Job struct {
 
 type v1Job struct {
 	ID                  int                             `json:"id"`
+	Queue               string                          `json:"queue,omitempty"`
 	RepositoryName      string                          `json:"repositoryName"`
 	RepositoryDirectory string                          `json:"repositoryDirectory"`
 	Commit              string                          `json:"commit"`
@@ -234,10 +244,28 @@ type v1VirtualMachineFile struct {
 	ModifiedAt time.Time `json:"modifiedAt,omitempty"`
 }
 
+// ValidQueueNames are the names of the queues executors can process jobs from.
+var ValidQueueNames = []string{"batches", "codeintel"}
+
+// RecordID returns the identifier of the job within the executor. Identifiers
+// of jobs dequeued from multiple queues at once also encode the queue, as
+// different queues can share identifiers. Use ParseRecordID to decode them.
 func (j Job) RecordID() int {
+	for i, name := range ValidQueueNames {
+		if j.Queue == name {
+			return j.ID*len(ValidQueueNames) + i
+		}
+	}
 	return j.ID
 }
 
+// ParseRecordID returns the queue name and the identifier within that queue of
+// the job with the given record ID, for jobs dequeued from multiple queues at
+// once.
+func ParseRecordID(recordID int) (queueName string, id int) {
+	return ValidQueueNames[recordID%len(ValidQueueNames)], recordID / len(ValidQueueNames)
+}
+
 type DockerStep struct {
 	// Key is a unique identifier of the step. It can be used to retrieve the
 	// associated log entry.
@@ -277,6 +305,10 @@ type DequeueRequest struct {
 	NumCPUs      int    `json:"numCPUs,omitempty"`
 	Memory       string `json:"memory,omitempty"`
 	DiskSpace    string `json:"diskSpace,omitempty"`
+
+	// Queues are the names of the queues to dequeue a job from, in order of
+	// preference. Only used for dequeueing from multiple queues at once.
+	Queues []string `json:"queues,omitempty"`
 }
 
 type AddExecutionLogEntryRequest struct {
@@ -292,6 +324,17 @@ type UpdateExecutionLogEntryRequest struct {
 	executor.ExecutionLogEntry
 }
 
+// StreamExecutionLogEntryRequest carries the output a command wrote since the last request, so
+// that users can follow the log entry live before it is persisted.
+type StreamExecutionLogEntryRequest struct {
+	ExecutorName string `json:"executorName"`
+	JobID        int    `json:"jobId"`
+	EntryID      int    `json:"entryId"`
+	// Offset is the position in the output of the log entry at which Out starts.
+	Offset int `json:"offset"`
+	executor.ExecutionLogEntry
+}
+
 type MarkCompleteRequest struct {
 	ExecutorName string `json:"executorName"`
 	JobID        int    `json:"jobId"`
@@ -316,6 +359,11 @@ type HeartbeatRequest struct {
 	ExecutorName string `json:"executorName"`
 	JobIDs       []int  `json:"jobIds"`
 
+	// JobIDsByQueue maps the names of the queues the executor processes jobs
+	// from to the IDs of its running jobs in that queue. Only used for
+	// heartbeats of executors processing multiple queues at once.
+	JobIDsByQueue map[string][]int `json:"jobIdsByQueue,omitempty"`
+
 	// Telemetry data.
 
 	OS              string `json:"os"`
@@ -332,6 +380,11 @@ type HeartbeatRequest struct {
 type HeartbeatResponse struct {
 	KnownIDs  []int `json:"knownIds"`
 	CancelIDs []int `json:"cancelIds"`
+
+	// KnownIDsByQueue and CancelIDsByQueue are set in response to heartbeats
+	// of executors processing multiple queues at once.
+	KnownIDsByQueue  map[string][]int `json:"knownIdsByQueue,omitempty"`
+	CancelIDsByQueue map[string][]int `json:"cancelIdsByQueue,omitempty"`
 }
 
 // TODO: Deprecated. Can be removed in Sourcegraph 4.4.
@@ -337,3 +337,22 @@ func TestJob_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestJob_RecordID(t *testing.T) {
+	if id := (Job{ID: 42}).RecordID(); id != 42 {
+		t.Errorf("unexpected record ID for single queue job. want=%d have=%d", 42, id)
+	}
+
+	batches := Job{ID: 42, Queue: "batches"}
+	codeintel := Job{ID: 42, Queue: "codeintel"}
+	if batches.RecordID() == codeintel.RecordID() {
+		t.Fatalf("expected record IDs of jobs from different queues to differ")
+	}
+
+	for _, job := range []Job{batches, codeintel} {
+		queueName, id := ParseRecordID(job.RecordID())
+		if queueName != job.Queue || id != job.ID {
+			t.Errorf("unexpected parsed record ID. want=%s/%d have=%s/%d", job.Queue, job.ID, queueName, id)
+		}
+	}
+}
@@ -9,4 +9,6 @@ const (
 	PythonPackagesScheme = shared.PythonPackagesScheme
 	RustPackagesScheme   = shared.RustPackagesScheme
 	RubyPackagesScheme   = shared.RubyPackagesScheme
+	DotnetPackagesScheme = shared.DotnetPackagesScheme
+	PHPPackagesScheme    = shared.PHPPackagesScheme
 )
@@ -7,4 +7,6 @@ const (
 	PythonPackagesScheme = "python"
 	RustPackagesScheme   = "rust-analyzer"
 	RubyPackagesScheme   = "scip-ruby"
+	DotnetPackagesScheme = "scip-dotnet"
+	PHPPackagesScheme    = "scip-php"
 )
@@ -26,6 +26,8 @@ type RootResolver interface {
 
 type CodeNavServiceResolver interface {
 	GitBlobLSIFData(ctx context.Context, args *GitBlobLSIFDataArgs) (GitBlobLSIFDataResolver, error)
+	GitBlobDocumentSymbols(ctx context.Context, args *GitBlobLSIFDataArgs) (DocumentSymbolsResolver, error)
+	CodeIntelAPIDiff(ctx context.Context, args *CodeIntelAPIDiffArgs) (CodeIntelAPIDiffResolver, error)
 }
 
 type AutoindexingServiceResolver interface {
@@ -213,6 +215,9 @@ type GitBlobLSIFDataResolver interface {
 	Definitions(ctx context.Context, args *LSIFQueryPositionArgs) (LocationConnectionResolver, error)
 	References(ctx context.Context, args *LSIFPagedQueryPositionArgs) (LocationConnectionResolver, error)
 	Implementations(ctx context.Context, args *LSIFPagedQueryPositionArgs) (LocationConnectionResolver, error)
+	TypeDefinitions(ctx context.Context, args *LSIFQueryPositionArgs) (LocationConnectionResolver, error)
+	IncomingCalls(ctx context.Context, args *LSIFPagedQueryPositionArgs) (CallHierarchyCallConnectionResolver, error)
+	OutgoingCalls(ctx context.Context, args *LSIFQueryPositionArgs) (CallHierarchyCallConnectionResolver, error)
 	Hover(ctx context.Context, args *LSIFQueryPositionArgs) (HoverResolver, error)
 }
 
@@ -230,6 +235,53 @@ type LocationConnectionResolver interface {
 	PageInfo(ctx context.Context) (PageInfo, error)
 }
 
+type CallHierarchyCallConnectionResolver interface {
+	Nodes(ctx context.Context) ([]CallHierarchyCallResolver, error)
+	PageInfo(ctx context.Context) (PageInfo, error)
+}
+
+type CallHierarchyCallResolver interface {
+	Symbol() string
+	Definition() LocationResolver
+	CallSites() []LocationResolver
+}
+
+type DocumentSymbolsResolver interface {
+	Nodes() []DocumentSymbolResolver
+	Precise() bool
+}
+
+type DocumentSymbolResolver interface {
+	Name() string
+	Symbol() *string
+	Kind() string
+	Range() RangeResolver
+	Children() []DocumentSymbolResolver
+}
+
+type CodeIntelAPIDiffResolver interface {
+	Added() []CodeIntelAPISymbolResolver
+	Removed() []CodeIntelAPISymbolResolver
+	Changed() []CodeIntelAPISymbolChangeResolver
+}
+
+type CodeIntelAPISymbolResolver interface {
+	Symbol() string
+	Signature() *string
+	Definition(ctx context.Context) (LocationResolver, error)
+	DownstreamReferences(ctx context.Context, args *CodeIntelDownstreamReferencesArgs) (LocationConnectionResolver, error)
+}
+
+type CodeIntelAPISymbolChangeResolver interface {
+	Base() CodeIntelAPISymbolResolver
+	Head() CodeIntelAPISymbolResolver
+}
+
+type CodeIntelDownstreamReferencesArgs struct {
+	graphqlutil.ConnectionArgs
+	After *string
+}
+
 type LSIFDiagnosticsArgs struct {
 	graphqlutil.ConnectionArgs
 }
@@ -520,6 +572,12 @@ type GitBlobLSIFDataArgs struct {
 	ToolName  string
 }
 
+type CodeIntelAPIDiffArgs struct {
+	Repo *types.Repo
+	Base api.CommitID
+	Head api.CommitID
+}
+
 type GitTreeEntryCodeIntelInfoArgs struct {
 	Repo   *types.Repo
 	Path   string
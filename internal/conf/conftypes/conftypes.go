@@ -14,6 +14,11 @@ type ServiceConnections struct {
 	// talked to.
 	GitServers []string `json:"gitServers"`
 
+	// GitServerRouting is the shard layout that requests to gitserver should be
+	// routed with. It is nil until the layout of GitServers has been recorded, in
+	// which case all of GitServers are used with modulo sharding.
+	GitServerRouting *GitServerRouting `json:"gitServerRouting"`
+
 	// PostgresDSN is the PostgreSQL DB data source name.
 	// eg: "postgres://sg@pgsql/sourcegraph?sslmode=false"
 	PostgresDSN string `json:"postgresDSN"`
@@ -40,6 +45,19 @@ type ServiceConnections struct {
 	ZoektListTTL time.Duration `json:"zoektListTTL"`
 }
 
+// GitServerRouting describes how repositories are assigned to gitserver shards.
+type GitServerRouting struct {
+	// Addresses is the ordered list of gitserver addresses that own repositories.
+	Addresses []string `json:"addresses"`
+	// Sharding is the hashing scheme used to assign repositories to Addresses,
+	// either "modulo" or "rendezvous".
+	Sharding string `json:"sharding"`
+	// Next is the layout that repositories are being moved to, if a rebalance
+	// is in progress. Requests are still routed with this layout until the
+	// rebalance finishes, but shards must not delete repositories they own in Next.
+	Next *GitServerRouting `json:"next,omitempty"`
+}
+
 // RawUnified is the unparsed variant of conf.Unified.
 type RawUnified struct {
 	ID                 int32
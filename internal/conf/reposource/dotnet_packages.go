@@ -0,0 +1,92 @@
+package reposource
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const dotnetPackagesPrefix = "nuget/"
+
+// DotnetVersionedPackage is a NuGet package. NuGet package IDs and versions
+// are case-insensitive, so both are normalized to lower case, which is also
+// the casing used by the NuGet package content API.
+type DotnetVersionedPackage struct {
+	Name    PackageName
+	Version string
+}
+
+func NewDotnetVersionedPackage(name PackageName, version string) *DotnetVersionedPackage {
+	return &DotnetVersionedPackage{
+		Name:    PackageName(strings.ToLower(string(name))),
+		Version: strings.ToLower(version),
+	}
+}
+
+// ParseDotnetVersionedPackage parses a string in a '<name>(@<version>)?' format into a
+// DotnetVersionedPackage.
+func ParseDotnetVersionedPackage(dependency string) (*DotnetVersionedPackage, error) {
+	name, version := dependency, ""
+	if i := strings.LastIndex(dependency, "@"); i != -1 {
+		name, version = strings.TrimSpace(dependency[:i]), strings.TrimSpace(dependency[i+1:])
+	}
+	if name == "" {
+		return nil, errors.Newf("invalid NuGet dependency %q, missing package name", dependency)
+	}
+	return NewDotnetVersionedPackage(PackageName(name), version), nil
+}
+
+func ParseDotnetPackageFromName(name PackageName) (*DotnetVersionedPackage, error) {
+	return ParseDotnetVersionedPackage(string(name))
+}
+
+// ParseDotnetPackageFromRepoName is a convenience function to parse a repo name in a
+// 'nuget/<name>(@<version>)?' format into a DotnetVersionedPackage.
+func ParseDotnetPackageFromRepoName(name api.RepoName) (*DotnetVersionedPackage, error) {
+	dependency := strings.TrimPrefix(string(name), dotnetPackagesPrefix)
+	if len(dependency) == len(name) {
+		return nil, errors.Newf("invalid NuGet dependency repo name, missing %s prefix '%s'", dotnetPackagesPrefix, name)
+	}
+	return ParseDotnetVersionedPackage(dependency)
+}
+
+func (p *DotnetVersionedPackage) Scheme() string {
+	return "scip-dotnet"
+}
+
+func (p *DotnetVersionedPackage) PackageSyntax() PackageName {
+	return p.Name
+}
+
+func (p *DotnetVersionedPackage) VersionedPackageSyntax() string {
+	if p.Version == "" {
+		return string(p.Name)
+	}
+	return string(p.Name) + "@" + p.Version
+}
+
+func (p *DotnetVersionedPackage) PackageVersion() string {
+	return p.Version
+}
+
+func (p *DotnetVersionedPackage) Description() string { return "" }
+
+func (p *DotnetVersionedPackage) RepoName() api.RepoName {
+	return api.RepoName(dotnetPackagesPrefix + p.Name)
+}
+
+func (p *DotnetVersionedPackage) GitTagFromVersion() string {
+	version := strings.TrimPrefix(p.Version, "v")
+	return "v" + version
+}
+
+func (p *DotnetVersionedPackage) Less(other VersionedPackage) bool {
+	o := other.(*DotnetVersionedPackage)
+
+	if p.Name == o.Name {
+		return versionGreaterThan(p.Version, o.Version)
+	}
+
+	return p.Name > o.Name
+}
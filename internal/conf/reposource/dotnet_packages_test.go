@@ -0,0 +1,44 @@
+package reposource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestParseDotnetVersionedPackage(t *testing.T) {
+	tests := []struct {
+		dep         string
+		wantName    PackageName
+		wantVersion string
+		wantRepo    api.RepoName
+	}{
+		{"Newtonsoft.Json@13.0.1", "newtonsoft.json", "13.0.1", "nuget/newtonsoft.json"},
+		{"Serilog@3.0.0-DEV-01", "serilog", "3.0.0-dev-01", "nuget/serilog"},
+		{"xunit", "xunit", "", "nuget/xunit"},
+	}
+	for _, test := range tests {
+		t.Run(test.dep, func(t *testing.T) {
+			pkg, err := ParseDotnetVersionedPackage(test.dep)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantName, pkg.Name)
+			assert.Equal(t, test.wantVersion, pkg.Version)
+			assert.Equal(t, test.wantRepo, pkg.RepoName())
+		})
+	}
+
+	_, err := ParseDotnetVersionedPackage("@1.0.0")
+	assert.Error(t, err)
+}
+
+func TestParseDotnetPackageFromRepoName(t *testing.T) {
+	pkg, err := ParseDotnetPackageFromRepoName("nuget/newtonsoft.json")
+	require.NoError(t, err)
+	assert.Equal(t, PackageName("newtonsoft.json"), pkg.PackageSyntax())
+
+	_, err = ParseDotnetPackageFromRepoName("npm/newtonsoft.json")
+	assert.Error(t, err)
+}
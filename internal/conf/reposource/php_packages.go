@@ -0,0 +1,92 @@
+package reposource
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const phpPackagesPrefix = "packagist/"
+
+// PHPVersionedPackage is a Composer package, named '<vendor>/<package>'.
+// Composer package names are case-insensitive and normalized to lower case.
+type PHPVersionedPackage struct {
+	Name    PackageName
+	Version string
+}
+
+func NewPHPVersionedPackage(name PackageName, version string) *PHPVersionedPackage {
+	return &PHPVersionedPackage{
+		Name:    PackageName(strings.ToLower(string(name))),
+		Version: version,
+	}
+}
+
+// ParsePHPVersionedPackage parses a string in a '<vendor>/<package>(:<version>)?' format
+// into a PHPVersionedPackage.
+func ParsePHPVersionedPackage(dependency string) (*PHPVersionedPackage, error) {
+	name, version := dependency, ""
+	if i := strings.LastIndex(dependency, ":"); i != -1 {
+		name, version = strings.TrimSpace(dependency[:i]), strings.TrimSpace(dependency[i+1:])
+	}
+	vendor, pkg, ok := strings.Cut(name, "/")
+	if !ok || vendor == "" || pkg == "" || strings.Contains(pkg, "/") {
+		return nil, errors.Newf("invalid Composer dependency %q, expected format <vendor>/<package>(:<version>)?", dependency)
+	}
+	return NewPHPVersionedPackage(PackageName(name), version), nil
+}
+
+func ParsePHPPackageFromName(name PackageName) (*PHPVersionedPackage, error) {
+	return ParsePHPVersionedPackage(string(name))
+}
+
+// ParsePHPPackageFromRepoName is a convenience function to parse a repo name in a
+// 'packagist/<vendor>/<package>(:<version>)?' format into a PHPVersionedPackage.
+func ParsePHPPackageFromRepoName(name api.RepoName) (*PHPVersionedPackage, error) {
+	dependency := strings.TrimPrefix(string(name), phpPackagesPrefix)
+	if len(dependency) == len(name) {
+		return nil, errors.Newf("invalid Composer dependency repo name, missing %s prefix '%s'", phpPackagesPrefix, name)
+	}
+	return ParsePHPVersionedPackage(dependency)
+}
+
+func (p *PHPVersionedPackage) Scheme() string {
+	return "scip-php"
+}
+
+func (p *PHPVersionedPackage) PackageSyntax() PackageName {
+	return p.Name
+}
+
+func (p *PHPVersionedPackage) VersionedPackageSyntax() string {
+	if p.Version == "" {
+		return string(p.Name)
+	}
+	return string(p.Name) + ":" + p.Version
+}
+
+func (p *PHPVersionedPackage) PackageVersion() string {
+	return p.Version
+}
+
+func (p *PHPVersionedPackage) Description() string { return "" }
+
+func (p *PHPVersionedPackage) RepoName() api.RepoName {
+	return api.RepoName(phpPackagesPrefix + p.Name)
+}
+
+func (p *PHPVersionedPackage) GitTagFromVersion() string {
+	version := strings.TrimPrefix(p.Version, "v")
+	return "v" + version
+}
+
+func (p *PHPVersionedPackage) Less(other VersionedPackage) bool {
+	o := other.(*PHPVersionedPackage)
+
+	if p.Name == o.Name {
+		return versionGreaterThan(p.Version, o.Version)
+	}
+
+	return p.Name > o.Name
+}
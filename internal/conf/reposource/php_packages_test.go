@@ -0,0 +1,51 @@
+package reposource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestParsePHPVersionedPackage(t *testing.T) {
+	tests := []struct {
+		dep         string
+		wantName    PackageName
+		wantVersion string
+		wantRepo    api.RepoName
+		wantTag     string
+		wantErr     bool
+	}{
+		{dep: "monolog/monolog:3.2.0", wantName: "monolog/monolog", wantVersion: "3.2.0", wantRepo: "packagist/monolog/monolog", wantTag: "v3.2.0"},
+		{dep: "Symfony/Console:v6.2.5", wantName: "symfony/console", wantVersion: "v6.2.5", wantRepo: "packagist/symfony/console", wantTag: "v6.2.5"},
+		{dep: "guzzlehttp/guzzle", wantName: "guzzlehttp/guzzle", wantRepo: "packagist/guzzlehttp/guzzle", wantTag: "v"},
+		{dep: "monolog:3.2.0", wantErr: true},
+		{dep: "/monolog:3.2.0", wantErr: true},
+		{dep: "a/b/c:1.0.0", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.dep, func(t *testing.T) {
+			pkg, err := ParsePHPVersionedPackage(test.dep)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantName, pkg.Name)
+			assert.Equal(t, test.wantVersion, pkg.Version)
+			assert.Equal(t, test.wantRepo, pkg.RepoName())
+			assert.Equal(t, test.wantTag, pkg.GitTagFromVersion())
+		})
+	}
+}
+
+func TestParsePHPPackageFromRepoName(t *testing.T) {
+	pkg, err := ParsePHPPackageFromRepoName("packagist/monolog/monolog")
+	require.NoError(t, err)
+	assert.Equal(t, PackageName("monolog/monolog"), pkg.PackageSyntax())
+
+	_, err = ParsePHPPackageFromRepoName("monolog/monolog")
+	assert.Error(t, err)
+}
@@ -0,0 +1,20 @@
+package reposource
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// SubversionRepoName returns the Sourcegraph repository name for the
+// Subversion repository at the given path on host.
+func SubversionRepoName(repositoryPathPattern, host, repo string) api.RepoName {
+	if repositoryPathPattern == "" {
+		repositoryPathPattern = "{host}/{repo}"
+	}
+
+	return api.RepoName(strings.NewReplacer(
+		"{host}", otherRepoNameReplacer.Replace(host),
+		"{repo}", otherRepoNameReplacer.Replace(strings.Trim(repo, "/")),
+	).Replace(repositoryPathPattern))
+}
@@ -219,6 +219,7 @@ var siteConfigSecrets = []struct {
 	{readPath: `auth\.unlockAccountLinkSigningKey`, editPaths: []string{"auth.unlockAccountLinkSigningKey"}},
 	{readPath: `dotcom.srcCliVersionCache.github.token`, editPaths: []string{"dotcom", "srcCliVersionCache", "github", "token"}},
 	{readPath: `dotcom.srcCliVersionCache.github.webhookSecret`, editPaths: []string{"dotcom", "srcCliVersionCache", "github", "webhookSecret"}},
+	{readPath: `scim.authToken`, editPaths: []string{"scim", "authToken"}},
 }
 
 // UnredactSecrets unredacts unchanged secrets back to their original value for
@@ -242,6 +243,9 @@ func UnredactSecrets(input string, raw conftypes.RawUnified) (string, error) {
 		if ap.Gitlab != nil {
 			oldSecrets[ap.Gitlab.ClientID] = ap.Gitlab.ClientSecret
 		}
+		if ap.Ldap != nil {
+			oldSecrets[ldapSecretKey(ap.Ldap)] = ap.Ldap.BindPassword
+		}
 	}
 
 	newCfg, err := ParseConfig(conftypes.RawUnified{
@@ -260,6 +264,9 @@ func UnredactSecrets(input string, raw conftypes.RawUnified) (string, error) {
 		if ap.Gitlab != nil && ap.Gitlab.ClientSecret == redactedSecret {
 			ap.Gitlab.ClientSecret = oldSecrets[ap.Gitlab.ClientID]
 		}
+		if ap.Ldap != nil && ap.Ldap.BindPassword == redactedSecret {
+			ap.Ldap.BindPassword = oldSecrets[ldapSecretKey(ap.Ldap)]
+		}
 	}
 	unredactedSite, err := jsonc.Edit(input, newCfg.AuthProviders, "auth.providers")
 	if err != nil {
@@ -286,6 +293,13 @@ func UnredactSecrets(input string, raw conftypes.RawUnified) (string, error) {
 	return formattedSite, err
 }
 
+// ldapSecretKey identifies the bind password of an LDAP auth provider across
+// config edits. LDAP providers have no client ID, so the server URL and bind DN
+// are used instead.
+func ldapSecretKey(p *schema.LDAPAuthProvider) string {
+	return "ldap:" + p.Url + "|" + p.BindDN
+}
+
 func RedactSecrets(raw conftypes.RawUnified) (empty conftypes.RawUnified, err error) {
 	return redactConfSecrets(raw, false)
 }
@@ -324,6 +338,9 @@ func redactConfSecrets(raw conftypes.RawUnified, hashSecrets bool) (empty confty
 		if ap.Gitlab != nil {
 			ap.Gitlab.ClientSecret = getRedactedSecret(ap.Gitlab.ClientSecret)
 		}
+		if ap.Ldap != nil && ap.Ldap.BindPassword != "" {
+			ap.Ldap.BindPassword = getRedactedSecret(ap.Ldap.BindPassword)
+		}
 	}
 	redactedSite := raw.Site
 	if len(cfg.AuthProviders) > 0 {
@@ -26,6 +26,7 @@ const (
 	authUnlockAccountLinkSigningKey             = "authUnlockAccountLinkSigningKey"
 	dotcomSrcCliVersionCacheGitHubToken         = "dotcomSrcCliVersionCacheGitHubToken"
 	dotcomSrcCliVersionCacheGitHubWebhookSecret = "dotcomSrcCliVersionCacheGitHubWebhookSecret"
+	scimAuthToken                               = "scimAuthToken"
 )
 
 func TestValidate(t *testing.T) {
@@ -226,6 +227,7 @@ func TestRedactSecrets(t *testing.T) {
 				dotcomSrcCliVersionCacheGitHubToken,
 				dotcomSrcCliVersionCacheGitHubWebhookSecret,
 				authUnlockAccountLinkSigningKey,
+				scimAuthToken,
 			),
 		},
 	)
@@ -305,6 +307,29 @@ func TestRedactSecrets_AuthProvidersSectionNotAdded(t *testing.T) {
 	assert.Equal(t, want, redacted.Site)
 }
 
+func TestRedactSecrets_LDAPBindPassword(t *testing.T) {
+	const cfg = `{
+  "auth.providers": [
+    {
+      "baseDN": "ou=people,dc=example,dc=com",
+      "bindDN": "cn=sourcegraph,dc=example,dc=com",
+      "bindPassword": "%s",
+      "type": "ldap",
+      "url": "ldap://ldap.example.com"
+    }
+  ]
+}`
+	previousSite := fmt.Sprintf(cfg, "bindsecret")
+
+	redacted, err := RedactSecrets(conftypes.RawUnified{Site: previousSite})
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(cfg, redactedSecret), redacted.Site)
+
+	unredacted, err := UnredactSecrets(redacted.Site, conftypes.RawUnified{Site: previousSite})
+	require.NoError(t, err)
+	assert.Equal(t, previousSite, unredacted)
+}
+
 func TestUnredactSecrets(t *testing.T) {
 	previousSite := getTestSiteWithSecrets(
 		executorsAccessToken,
@@ -317,6 +342,7 @@ func TestUnredactSecrets(t *testing.T) {
 		dotcomSrcCliVersionCacheGitHubToken,
 		dotcomSrcCliVersionCacheGitHubWebhookSecret,
 		authUnlockAccountLinkSigningKey,
+		scimAuthToken,
 	)
 
 	t.Run("replaces REDACTED with corresponding secret", func(t *testing.T) {
@@ -339,6 +365,7 @@ func TestUnredactSecrets(t *testing.T) {
 			redactedSecret,
 			redactedSecret,
 			redactedSecret,
+			redactedSecret,
 		)
 		unredactedSite, err := UnredactSecrets(input, conftypes.RawUnified{Site: previousSite})
 		require.NoError(t, err)
@@ -355,6 +382,7 @@ func TestUnredactSecrets(t *testing.T) {
 			dotcomSrcCliVersionCacheGitHubToken,
 			dotcomSrcCliVersionCacheGitHubWebhookSecret,
 			authUnlockAccountLinkSigningKey,
+			scimAuthToken,
 		)
 		assert.Equal(t, want, unredactedSite)
 	})
@@ -372,6 +400,7 @@ func TestUnredactSecrets(t *testing.T) {
 			redactedSecret,
 			redactedSecret,
 			redactedSecret,
+			redactedSecret,
 			newEmail,
 		)
 		unredactedSite, err := UnredactSecrets(input, conftypes.RawUnified{Site: previousSite})
@@ -389,6 +418,7 @@ func TestUnredactSecrets(t *testing.T) {
 			dotcomSrcCliVersionCacheGitHubToken,
 			dotcomSrcCliVersionCacheGitHubWebhookSecret,
 			authUnlockAccountLinkSigningKey,
+			scimAuthToken,
 			newEmail,
 		)
 		assert.Equal(t, want, unredactedSite)
@@ -396,7 +426,7 @@ func TestUnredactSecrets(t *testing.T) {
 }
 
 func getTestSiteWithRedactedSecrets() string {
-	return getTestSiteWithSecrets(redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret)
+	return getTestSiteWithSecrets(redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret, redactedSecret)
 }
 
 func getTestSiteWithSecrets(
@@ -407,7 +437,8 @@ func getTestSiteWithSecrets(
 	githubClientSecret,
 	dotcomGitHubAppCloudClientSecret, dotcomGitHubAppCloudPrivateKey,
 	dotcomSrcCliVersionCacheGitHubToken, dotcomSrcCliVersionCacheGitHubWebhookSecret,
-	authUnlockAccountLinkSigningKey string,
+	authUnlockAccountLinkSigningKey,
+	scimAuthToken string,
 	optionalEdit ...string,
 ) string {
 	email := "noreply+dev@sourcegraph.com"
@@ -472,6 +503,9 @@ func getTestSiteWithSecrets(
     }
   },
   "auth.unlockAccountLinkSigningKey": "%s",
+  "scim": {
+    "authToken": "%s"
+  },
 }`,
 		email,
 		executorsAccessToken,
@@ -483,6 +517,7 @@ func getTestSiteWithSecrets(
 		dotcomGitHubAppCloudClientSecret, dotcomGitHubAppCloudPrivateKey,
 		dotcomSrcCliVersionCacheGitHubToken, dotcomSrcCliVersionCacheGitHubWebhookSecret,
 		authUnlockAccountLinkSigningKey,
+		scimAuthToken,
 	)
 
 }
@@ -115,6 +115,12 @@ type ExternalServiceStore interface {
 	// 🚨 SECURITY: The caller must ensure that the actor is a site admin or owner of the external service.
 	RepoCount(ctx context.Context, id int64) (int32, error)
 
+	// DiskUsage returns the total size in bytes of the cloned repos synced by the
+	// external service with the given id, as last recorded by gitserver.
+	//
+	// 🚨 SECURITY: The caller must ensure that the actor is a site admin or owner of the external service.
+	DiskUsage(ctx context.Context, id int64) (int64, error)
+
 	// SyncDue returns true if any of the supplied external services are due to sync
 	// now or within given duration from now.
 	SyncDue(ctx context.Context, intIDs []int64, d time.Duration) (bool, error)
@@ -218,6 +224,9 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	extsvc.KindPythonPackages:  {CodeHost: true, JSONSchema: schema.PythonPackagesSchemaJSON},
 	extsvc.KindRustPackages:    {CodeHost: true, JSONSchema: schema.RustPackagesSchemaJSON},
 	extsvc.KindRubyPackages:    {CodeHost: true, JSONSchema: schema.RubyPackagesSchemaJSON},
+	extsvc.KindDotnetPackages:  {CodeHost: true, JSONSchema: schema.DotnetPackagesSchemaJSON},
+	extsvc.KindPHPPackages:     {CodeHost: true, JSONSchema: schema.PHPPackagesSchemaJSON},
+	extsvc.KindSubversion:      {CodeHost: true, JSONSchema: schema.SubversionSchemaJSON},
 }
 
 // ExternalServiceKind describes a kind of external service.
@@ -393,6 +402,12 @@ func MakeValidateExternalServiceConfigFunc(gitHubValidators []func(*types.GitHub
 				return nil, err
 			}
 			err = validateAzureDevOpsConnection(azureDevOpsValidators, opt.ExternalServiceID, &c)
+		case extsvc.KindSubversion:
+			var c schema.SubversionConnection
+			if err = jsoniter.Unmarshal(normalized, &c); err != nil {
+				return nil, err
+			}
+			err = validateSubversionConnection(&c)
 		case extsvc.KindOther:
 			var c schema.OtherExternalServiceConnection
 			if err = jsoniter.Unmarshal(normalized, &c); err != nil {
@@ -434,6 +449,23 @@ func validateOtherExternalServiceConnection(c *schema.OtherExternalServiceConnec
 	return nil
 }
 
+// validateSubversionConnection checks that the trunk, branches and tags paths
+// are only set for the custom layout, since git svn would silently ignore them
+// otherwise.
+func validateSubversionConnection(c *schema.SubversionConnection) error {
+	if c.Layout == "custom" {
+		if c.Trunk == "" && c.Branches == "" && c.Tags == "" {
+			return errors.New(`at least one of trunk, branches or tags must be set when layout is "custom"`)
+		}
+		return nil
+	}
+
+	if c.Trunk != "" || c.Branches != "" || c.Tags != "" {
+		return errors.New(`trunk, branches and tags can only be set when layout is "custom"`)
+	}
+	return nil
+}
+
 func validateGitHubConnection(githubValidators []func(*types.GitHubConnection) error, id int64, c *schema.GitHubConnection) error {
 	var err error
 	for _, validate := range githubValidators {
@@ -1556,6 +1588,28 @@ func (e *externalServiceStore) RepoCount(ctx context.Context, id int64) (int32,
 	return count, nil
 }
 
+func (e *externalServiceStore) DiskUsage(ctx context.Context, id int64) (int64, error) {
+	q := sqlf.Sprintf(diskUsageQueryFmtstr, id, types.CloneStatusCloned)
+	var usage int64
+
+	if err := e.QueryRow(ctx, q).Scan(&usage); err != nil {
+		return 0, err
+	}
+
+	return usage, nil
+}
+
+const diskUsageQueryFmtstr = `
+SELECT COALESCE(SUM(gr.repo_size_bytes), 0)
+FROM external_service_repos esr
+JOIN repo r ON r.id = esr.repo_id
+JOIN gitserver_repos gr ON gr.repo_id = esr.repo_id
+WHERE
+	esr.external_service_id = %s
+	AND r.deleted_at IS NULL
+	AND gr.clone_status = %s
+`
+
 func (e *externalServiceStore) SyncDue(ctx context.Context, intIDs []int64, d time.Duration) (bool, error) {
 	if len(intIDs) == 0 {
 		return false, nil
@@ -195,6 +195,18 @@ func TestExternalServicesStore_ValidateConfig(t *testing.T) {
 			config:  `{"url": "https://dev.azure.com", "token": "token", "username": "username", "orgs":[]}`,
 			wantErr: "<nil>",
 		},
+		{
+			name:    "0 errors - Subversion",
+			kind:    extsvc.KindSubversion,
+			config:  `{"url": "https://svn.sourcegraph.com/repos", "repos": ["firmware"], "layout": "custom", "trunk": "main"}`,
+			wantErr: "<nil>",
+		},
+		{
+			name:    "1 error - Subversion",
+			kind:    extsvc.KindSubversion,
+			config:  `{"url": "https://svn.sourcegraph.com/repos", "repos": ["firmware"], "trunk": "main"}`,
+			wantErr: `trunk, branches and tags can only be set when layout is "custom"`,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -788,6 +800,70 @@ VALUES (%d, 1, '')
 	}
 }
 
+func TestExternalServicesStore_DiskUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := actor.WithInternalActor(context.Background())
+
+	confGet := func() *conf.Unified {
+		return &conf.Unified{}
+	}
+	es := &types.ExternalService{
+		Kind:        extsvc.KindGitHub,
+		DisplayName: "GITHUB #1",
+		Config:      extsvc.NewUnencryptedConfig(`{"url": "https://github.com", "repositoryQuery": ["none"], "token": "abc"}`),
+	}
+	if err := db.ExternalServices().Create(ctx, confGet, es); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := db.ExecContext(ctx, `
+INSERT INTO repo (id, name, description, fork)
+VALUES
+	(1, 'github.com/user/cloned', '', FALSE),
+	(2, 'github.com/user/not-cloned', '', FALSE),
+	(3, 'github.com/user/other', '', FALSE);
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := sqlf.Sprintf(`
+INSERT INTO external_service_repos (external_service_id, repo_id, clone_url)
+VALUES (%d, 1, ''), (%d, 2, '')
+`, es.ID, es.ID)
+	if _, err := db.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, size := range map[api.RepoName]int64{
+		"github.com/user/cloned":     100,
+		"github.com/user/not-cloned": 200,
+		"github.com/user/other":      400,
+	} {
+		if err := db.GitserverRepos().SetRepoSize(ctx, name, size, "gitserver"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []api.RepoName{"github.com/user/cloned", "github.com/user/other"} {
+		if err := db.GitserverRepos().SetCloneStatus(ctx, name, types.CloneStatusCloned, "gitserver"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	usage, err := db.ExternalServices().DiskUsage(ctx, es.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage != 100 {
+		t.Fatalf("Expected 100, got %d", usage)
+	}
+}
+
 func TestExternalServicesStore_Delete(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
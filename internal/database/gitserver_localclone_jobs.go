@@ -2,10 +2,13 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/keegancsmith/sqlf"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 )
 
 // GitserverLocalCloneStore is used to migrate repos from one gitserver to another asynchronously.
@@ -13,6 +16,73 @@ type GitserverLocalCloneStore interface {
 	basestore.ShareableStore
 	With(other basestore.ShareableStore) GitserverLocalCloneStore
 	Enqueue(ctx context.Context, repoID int, sourceHostname, destHostname string, deleteSource bool) (int, error)
+	// LatestJobs returns the most recent job of each repository that was queued at
+	// or after the given time.
+	LatestJobs(ctx context.Context, since time.Time) ([]*GitserverRelocatorJob, error)
+}
+
+// GitserverRelocatorJob is a request to move a repository from one gitserver to
+// another.
+type GitserverRelocatorJob struct {
+	ID             int
+	State          string
+	FailureMessage *string
+	QueuedAt       time.Time
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+	ProcessAfter   *time.Time
+	NumResets      int
+	NumFailures    int
+	RepoID         api.RepoID
+	RepoName       api.RepoName
+	SourceHostname string
+	DestHostname   string
+	DeleteSource   bool
+}
+
+func (j *GitserverRelocatorJob) RecordID() int {
+	return j.ID
+}
+
+// GitserverRelocatorJobColumns are the columns of gitserver_relocator_jobs_with_repo_name
+// that are scanned by ScanGitserverRelocatorJob.
+var GitserverRelocatorJobColumns = []*sqlf.Query{
+	sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.id"),
+	sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.state"),
+	sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.failure_message"),
+	sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.queued_at"),
+	sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.started_at"),
+	sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.finished_at"),
+	sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.process_after"),
+	sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.num_resets"),
+	sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.num_failures"),
+	sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.repo_id"),
+	sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.repo_name"),
+	sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.source_hostname"),
+	sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.dest_hostname"),
+	sqlf.Sprintf("gitserver_relocator_jobs_with_repo_name.delete_source"),
+}
+
+// ScanGitserverRelocatorJob scans a job selected with GitserverRelocatorJobColumns.
+func ScanGitserverRelocatorJob(sc dbutil.Scanner) (*GitserverRelocatorJob, error) {
+	var j GitserverRelocatorJob
+	err := sc.Scan(
+		&j.ID,
+		&j.State,
+		&j.FailureMessage,
+		&j.QueuedAt,
+		&j.StartedAt,
+		&j.FinishedAt,
+		&j.ProcessAfter,
+		&j.NumResets,
+		&j.NumFailures,
+		&j.RepoID,
+		&j.RepoName,
+		&j.SourceHostname,
+		&j.DestHostname,
+		&j.DeleteSource,
+	)
+	return &j, err
 }
 
 type gitserverLocalCloneStore struct {
@@ -48,3 +118,14 @@ VALUES (%s, %s, %s, %s) RETURNING id
 
 	return jobId, nil
 }
+
+var scanGitserverRelocatorJobs = basestore.NewSliceScanner(ScanGitserverRelocatorJob)
+
+func (s *gitserverLocalCloneStore) LatestJobs(ctx context.Context, since time.Time) ([]*GitserverRelocatorJob, error) {
+	return scanGitserverRelocatorJobs(s.Query(ctx, sqlf.Sprintf(`
+SELECT DISTINCT ON (repo_id) %s
+FROM gitserver_relocator_jobs_with_repo_name
+WHERE queued_at >= %s
+ORDER BY repo_id, id DESC
+	`, sqlf.Join(GitserverRelocatorJobColumns, ", "), since)))
+}
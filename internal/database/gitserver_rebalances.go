@@ -0,0 +1,136 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// GitserverRebalance is a change of the gitserver shard layout. Until it is
+// finished, requests are routed with the source layout while the repositories that
+// move are cloned on their new shard.
+type GitserverRebalance struct {
+	ID             int
+	SourceAddrs    []string
+	SourceSharding string
+	DestAddrs      []string
+	DestSharding   string
+	CreatedAt      time.Time
+	FinishedAt     *time.Time
+}
+
+// Routing returns the routing that gitserver clients should use while r is the
+// latest rebalance.
+func (r *GitserverRebalance) Routing() *conftypes.GitServerRouting {
+	dest := &conftypes.GitServerRouting{
+		Addresses: r.DestAddrs,
+		Sharding:  r.DestSharding,
+	}
+	if r.FinishedAt != nil {
+		return dest
+	}
+	return &conftypes.GitServerRouting{
+		Addresses: r.SourceAddrs,
+		Sharding:  r.SourceSharding,
+		Next:      dest,
+	}
+}
+
+// GitserverRebalanceVersionKey is the key of a counter in the Redis store that the
+// rebalancer increments whenever it changes the gitserver_rebalances table, so that
+// the frontend only reloads the gitserver routing when it changed.
+const GitserverRebalanceVersionKey = "gitserver_rebalance_version"
+
+// GitserverRebalanceStore records changes of the gitserver shard layout.
+type GitserverRebalanceStore interface {
+	basestore.ShareableStore
+	// Latest returns the most recently created rebalance, or nil if there is none.
+	Latest(ctx context.Context) (*GitserverRebalance, error)
+	// Create inserts the given rebalance and sets its ID and creation time.
+	Create(ctx context.Context, r *GitserverRebalance) error
+	// UpdateDest changes the destination layout of an unfinished rebalance.
+	UpdateDest(ctx context.Context, id int, addrs []string, sharding string) error
+	// Finish marks a rebalance as finished, after which requests are routed with
+	// its destination layout.
+	Finish(ctx context.Context, id int) error
+}
+
+type gitserverRebalanceStore struct {
+	*basestore.Store
+}
+
+// GitserverRebalancesWith instantiates and returns a new GitserverRebalanceStore
+// using the other store handle.
+func GitserverRebalancesWith(other basestore.ShareableStore) GitserverRebalanceStore {
+	return &gitserverRebalanceStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+var gitserverRebalanceColumns = []*sqlf.Query{
+	sqlf.Sprintf("id"),
+	sqlf.Sprintf("source_addrs"),
+	sqlf.Sprintf("source_sharding"),
+	sqlf.Sprintf("dest_addrs"),
+	sqlf.Sprintf("dest_sharding"),
+	sqlf.Sprintf("created_at"),
+	sqlf.Sprintf("finished_at"),
+}
+
+func scanGitserverRebalance(sc dbutil.Scanner) (*GitserverRebalance, error) {
+	var r GitserverRebalance
+	err := sc.Scan(
+		&r.ID,
+		pq.Array(&r.SourceAddrs),
+		&r.SourceSharding,
+		pq.Array(&r.DestAddrs),
+		&r.DestSharding,
+		&r.CreatedAt,
+		&r.FinishedAt,
+	)
+	return &r, err
+}
+
+func (s *gitserverRebalanceStore) Latest(ctx context.Context) (*GitserverRebalance, error) {
+	r, err := scanGitserverRebalance(s.QueryRow(ctx, sqlf.Sprintf(
+		"SELECT %s FROM gitserver_rebalances ORDER BY id DESC LIMIT 1",
+		sqlf.Join(gitserverRebalanceColumns, ", "),
+	)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return r, err
+}
+
+func (s *gitserverRebalanceStore) Create(ctx context.Context, r *GitserverRebalance) error {
+	return s.QueryRow(ctx, sqlf.Sprintf(`
+INSERT INTO gitserver_rebalances (source_addrs, source_sharding, dest_addrs, dest_sharding, finished_at)
+VALUES (%s, %s, %s, %s, %s)
+RETURNING id, created_at
+	`,
+		pq.Array(r.SourceAddrs),
+		r.SourceSharding,
+		pq.Array(r.DestAddrs),
+		r.DestSharding,
+		r.FinishedAt,
+	)).Scan(&r.ID, &r.CreatedAt)
+}
+
+func (s *gitserverRebalanceStore) UpdateDest(ctx context.Context, id int, addrs []string, sharding string) error {
+	return s.Exec(ctx, sqlf.Sprintf(
+		"UPDATE gitserver_rebalances SET dest_addrs = %s, dest_sharding = %s WHERE id = %s AND finished_at IS NULL",
+		pq.Array(addrs), sharding, id,
+	))
+}
+
+func (s *gitserverRebalanceStore) Finish(ctx context.Context, id int) error {
+	return s.Exec(ctx, sqlf.Sprintf(
+		"UPDATE gitserver_rebalances SET finished_at = NOW() WHERE id = %s AND finished_at IS NULL",
+		id,
+	))
+}
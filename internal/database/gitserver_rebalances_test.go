@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestGitserverRebalances(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+	store := GitserverRebalancesWith(db)
+
+	latest, err := store.Latest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != nil {
+		t.Fatalf("want no rebalance, got %+v", latest)
+	}
+
+	r := &GitserverRebalance{
+		SourceAddrs:    []string{"gitserver-0"},
+		SourceSharding: "modulo",
+		DestAddrs:      []string{"gitserver-0", "gitserver-1"},
+		DestSharding:   "rendezvous",
+	}
+	if err := store.Create(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.UpdateDest(ctx, r.ID, []string{"gitserver-0", "gitserver-1", "gitserver-2"}, "rendezvous"); err != nil {
+		t.Fatal(err)
+	}
+	latest, err = store.Latest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &conftypes.GitServerRouting{
+		Addresses: []string{"gitserver-0"},
+		Sharding:  "modulo",
+		Next: &conftypes.GitServerRouting{
+			Addresses: []string{"gitserver-0", "gitserver-1", "gitserver-2"},
+			Sharding:  "rendezvous",
+		},
+	}
+	if diff := cmp.Diff(want, latest.Routing()); diff != "" {
+		t.Fatalf("unexpected routing of unfinished rebalance (-want +got):\n%s", diff)
+	}
+
+	if err := store.Finish(ctx, r.ID); err != nil {
+		t.Fatal(err)
+	}
+	latest, err = store.Latest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.FinishedAt == nil {
+		t.Fatal("want rebalance to be finished")
+	}
+	if diff := cmp.Diff(want.Next, latest.Routing()); diff != "" {
+		t.Fatalf("unexpected routing of finished rebalance (-want +got):\n%s", diff)
+	}
+
+	// The destination of a finished rebalance cannot change anymore.
+	if err := store.UpdateDest(ctx, r.ID, []string{"gitserver-0"}, "modulo"); err != nil {
+		t.Fatal(err)
+	}
+	latest, err = store.Latest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want.Next, latest.Routing()); diff != "" {
+		t.Fatalf("unexpected routing after updating finished rebalance (-want +got):\n%s", diff)
+	}
+}
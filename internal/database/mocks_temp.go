@@ -17993,6 +17993,9 @@ type MockExternalServiceStore struct {
 	// DeleteFunc is an instance of a mock function object controlling the
 	// behavior of the method Delete.
 	DeleteFunc *ExternalServiceStoreDeleteFunc
+	// DiskUsageFunc is an instance of a mock function object controlling
+	// the behavior of the method DiskUsage.
+	DiskUsageFunc *ExternalServiceStoreDiskUsageFunc
 	// DistinctKindsFunc is an instance of a mock function object
 	// controlling the behavior of the method DistinctKinds.
 	DistinctKindsFunc *ExternalServiceStoreDistinctKindsFunc
@@ -18079,6 +18082,11 @@ func NewMockExternalServiceStore() *MockExternalServiceStore {
 				return
 			},
 		},
+		DiskUsageFunc: &ExternalServiceStoreDiskUsageFunc{
+			defaultHook: func(context.Context, int64) (r0 int64, r1 error) {
+				return
+			},
+		},
 		DistinctKindsFunc: &ExternalServiceStoreDistinctKindsFunc{
 			defaultHook: func(context.Context) (r0 []string, r1 error) {
 				return
@@ -18202,6 +18210,11 @@ func NewStrictMockExternalServiceStore() *MockExternalServiceStore {
 				panic("unexpected invocation of MockExternalServiceStore.Delete")
 			},
 		},
+		DiskUsageFunc: &ExternalServiceStoreDiskUsageFunc{
+			defaultHook: func(context.Context, int64) (int64, error) {
+				panic("unexpected invocation of MockExternalServiceStore.DiskUsage")
+			},
+		},
 		DistinctKindsFunc: &ExternalServiceStoreDistinctKindsFunc{
 			defaultHook: func(context.Context) ([]string, error) {
 				panic("unexpected invocation of MockExternalServiceStore.DistinctKinds")
@@ -18315,6 +18328,9 @@ func NewMockExternalServiceStoreFrom(i ExternalServiceStore) *MockExternalServic
 		DeleteFunc: &ExternalServiceStoreDeleteFunc{
 			defaultHook: i.Delete,
 		},
+		DiskUsageFunc: &ExternalServiceStoreDiskUsageFunc{
+			defaultHook: i.DiskUsage,
+		},
 		DistinctKindsFunc: &ExternalServiceStoreDistinctKindsFunc{
 			defaultHook: i.DistinctKinds,
 		},
@@ -18912,6 +18928,116 @@ func (c ExternalServiceStoreDeleteFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// ExternalServiceStoreDiskUsageFunc describes the behavior when the
+// DiskUsage method of the parent MockExternalServiceStore instance is
+// invoked.
+type ExternalServiceStoreDiskUsageFunc struct {
+	defaultHook func(context.Context, int64) (int64, error)
+	hooks       []func(context.Context, int64) (int64, error)
+	history     []ExternalServiceStoreDiskUsageFuncCall
+	mutex       sync.Mutex
+}
+
+// DiskUsage delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockExternalServiceStore) DiskUsage(v0 context.Context, v1 int64) (int64, error) {
+	r0, r1 := m.DiskUsageFunc.nextHook()(v0, v1)
+	m.DiskUsageFunc.appendCall(ExternalServiceStoreDiskUsageFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the DiskUsage method of
+// the parent MockExternalServiceStore instance is invoked and the hook
+// queue is empty.
+func (f *ExternalServiceStoreDiskUsageFunc) SetDefaultHook(hook func(context.Context, int64) (int64, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DiskUsage method of the parent MockExternalServiceStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *ExternalServiceStoreDiskUsageFunc) PushHook(hook func(context.Context, int64) (int64, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ExternalServiceStoreDiskUsageFunc) SetDefaultReturn(r0 int64, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64) (int64, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ExternalServiceStoreDiskUsageFunc) PushReturn(r0 int64, r1 error) {
+	f.PushHook(func(context.Context, int64) (int64, error) {
+		return r0, r1
+	})
+}
+
+func (f *ExternalServiceStoreDiskUsageFunc) nextHook() func(context.Context, int64) (int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ExternalServiceStoreDiskUsageFunc) appendCall(r0 ExternalServiceStoreDiskUsageFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ExternalServiceStoreDiskUsageFuncCall
+// objects describing the invocations of this function.
+func (f *ExternalServiceStoreDiskUsageFunc) History() []ExternalServiceStoreDiskUsageFuncCall {
+	f.mutex.Lock()
+	history := make([]ExternalServiceStoreDiskUsageFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ExternalServiceStoreDiskUsageFuncCall is an object that describes an
+// invocation of method DiskUsage on an instance of
+// MockExternalServiceStore.
+type ExternalServiceStoreDiskUsageFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int64
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ExternalServiceStoreDiskUsageFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ExternalServiceStoreDiskUsageFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ExternalServiceStoreDistinctKindsFunc describes the behavior when the
 // DistinctKinds method of the parent MockExternalServiceStore instance is
 // invoked.
@@ -23546,6 +23672,9 @@ type MockGitserverLocalCloneStore struct {
 	// HandleFunc is an instance of a mock function object controlling the
 	// behavior of the method Handle.
 	HandleFunc *GitserverLocalCloneStoreHandleFunc
+	// LatestJobsFunc is an instance of a mock function object controlling
+	// the behavior of the method LatestJobs.
+	LatestJobsFunc *GitserverLocalCloneStoreLatestJobsFunc
 	// WithFunc is an instance of a mock function object controlling the
 	// behavior of the method With.
 	WithFunc *GitserverLocalCloneStoreWithFunc
@@ -23566,6 +23695,11 @@ func NewMockGitserverLocalCloneStore() *MockGitserverLocalCloneStore {
 				return
 			},
 		},
+		LatestJobsFunc: &GitserverLocalCloneStoreLatestJobsFunc{
+			defaultHook: func(context.Context, time.Time) (r0 []*GitserverRelocatorJob, r1 error) {
+				return
+			},
+		},
 		WithFunc: &GitserverLocalCloneStoreWithFunc{
 			defaultHook: func(basestore.ShareableStore) (r0 GitserverLocalCloneStore) {
 				return
@@ -23589,6 +23723,11 @@ func NewStrictMockGitserverLocalCloneStore() *MockGitserverLocalCloneStore {
 				panic("unexpected invocation of MockGitserverLocalCloneStore.Handle")
 			},
 		},
+		LatestJobsFunc: &GitserverLocalCloneStoreLatestJobsFunc{
+			defaultHook: func(context.Context, time.Time) ([]*GitserverRelocatorJob, error) {
+				panic("unexpected invocation of MockGitserverLocalCloneStore.LatestJobs")
+			},
+		},
 		WithFunc: &GitserverLocalCloneStoreWithFunc{
 			defaultHook: func(basestore.ShareableStore) GitserverLocalCloneStore {
 				panic("unexpected invocation of MockGitserverLocalCloneStore.With")
@@ -23608,6 +23747,9 @@ func NewMockGitserverLocalCloneStoreFrom(i GitserverLocalCloneStore) *MockGitser
 		HandleFunc: &GitserverLocalCloneStoreHandleFunc{
 			defaultHook: i.Handle,
 		},
+		LatestJobsFunc: &GitserverLocalCloneStoreLatestJobsFunc{
+			defaultHook: i.LatestJobs,
+		},
 		WithFunc: &GitserverLocalCloneStoreWithFunc{
 			defaultHook: i.With,
 		},
@@ -23834,6 +23976,117 @@ func (c GitserverLocalCloneStoreHandleFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// GitserverLocalCloneStoreLatestJobsFunc describes the behavior when the
+// LatestJobs method of the parent MockGitserverLocalCloneStore instance is
+// invoked.
+type GitserverLocalCloneStoreLatestJobsFunc struct {
+	defaultHook func(context.Context, time.Time) ([]*GitserverRelocatorJob, error)
+	hooks       []func(context.Context, time.Time) ([]*GitserverRelocatorJob, error)
+	history     []GitserverLocalCloneStoreLatestJobsFuncCall
+	mutex       sync.Mutex
+}
+
+// LatestJobs delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGitserverLocalCloneStore) LatestJobs(v0 context.Context, v1 time.Time) ([]*GitserverRelocatorJob, error) {
+	r0, r1 := m.LatestJobsFunc.nextHook()(v0, v1)
+	m.LatestJobsFunc.appendCall(GitserverLocalCloneStoreLatestJobsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the LatestJobs method of
+// the parent MockGitserverLocalCloneStore instance is invoked and the hook
+// queue is empty.
+func (f *GitserverLocalCloneStoreLatestJobsFunc) SetDefaultHook(hook func(context.Context, time.Time) ([]*GitserverRelocatorJob, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// LatestJobs method of the parent MockGitserverLocalCloneStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *GitserverLocalCloneStoreLatestJobsFunc) PushHook(hook func(context.Context, time.Time) ([]*GitserverRelocatorJob, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverLocalCloneStoreLatestJobsFunc) SetDefaultReturn(r0 []*GitserverRelocatorJob, r1 error) {
+	f.SetDefaultHook(func(context.Context, time.Time) ([]*GitserverRelocatorJob, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverLocalCloneStoreLatestJobsFunc) PushReturn(r0 []*GitserverRelocatorJob, r1 error) {
+	f.PushHook(func(context.Context, time.Time) ([]*GitserverRelocatorJob, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverLocalCloneStoreLatestJobsFunc) nextHook() func(context.Context, time.Time) ([]*GitserverRelocatorJob, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverLocalCloneStoreLatestJobsFunc) appendCall(r0 GitserverLocalCloneStoreLatestJobsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverLocalCloneStoreLatestJobsFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverLocalCloneStoreLatestJobsFunc) History() []GitserverLocalCloneStoreLatestJobsFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverLocalCloneStoreLatestJobsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverLocalCloneStoreLatestJobsFuncCall is an object that describes an
+// invocation of method LatestJobs on an instance of
+// MockGitserverLocalCloneStore.
+type GitserverLocalCloneStoreLatestJobsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 time.Time
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*GitserverRelocatorJob
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverLocalCloneStoreLatestJobsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverLocalCloneStoreLatestJobsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // GitserverLocalCloneStoreWithFunc describes the behavior when the With
 // method of the parent MockGitserverLocalCloneStore instance is invoked.
 type GitserverLocalCloneStoreWithFunc struct {
@@ -0,0 +1,61 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+
+	"golang.org/x/oauth2"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+// AccountData stores information of an Azure DevOps identity. Access control
+// entries refer to the identity by its Descriptor.
+type AccountData struct {
+	ID                string `json:"id"`
+	Descriptor        string `json:"descriptor"`
+	SubjectDescriptor string `json:"subjectDescriptor"`
+	DisplayName       string `json:"displayName"`
+	Email             string `json:"email"`
+}
+
+// GetExternalAccountData returns the deserialized identity and, if the account was
+// connected through OAuth, the token from the external account data JSON blob in a
+// typesafe way.
+func GetExternalAccountData(ctx context.Context, data *extsvc.AccountData) (usr *AccountData, tok *oauth2.Token, err error) {
+	if data.Data != nil {
+		usr, err = encryption.DecryptJSON[AccountData](ctx, data.Data)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if data.AuthData != nil {
+		tok, err = encryption.DecryptJSON[oauth2.Token](ctx, data.AuthData)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return usr, tok, nil
+}
+
+// SetExternalAccountData sets the identity and, if not nil, the token into the external
+// account data blob.
+func SetExternalAccountData(data *extsvc.AccountData, user *AccountData, token *oauth2.Token) error {
+	serializedUser, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+	data.Data = extsvc.NewUnencryptedData(serializedUser)
+
+	if token != nil {
+		serializedToken, err := json.Marshal(token)
+		if err != nil {
+			return err
+		}
+		data.AuthData = extsvc.NewUnencryptedData(serializedToken)
+	}
+	return nil
+}
@@ -85,7 +85,26 @@ func (c *Client) ListRepositoriesByProjectOrOrg(ctx context.Context, opts ListRe
 	return repos.Value, nil
 }
 
-//nolint:unparam // http.Response is never used, but it makes sense API wise.
+// GetRepository returns the repository with the given ID in the given organization.
+func (c *Client) GetRepository(ctx context.Context, org, id string) (Repository, error) {
+	qs := make(url.Values)
+	qs.Set("api-version", "7.0")
+
+	urlRepository := url.URL{Path: fmt.Sprintf("%s/_apis/git/repositories/%s", org, id), RawQuery: qs.Encode()}
+
+	req, err := http.NewRequest("GET", urlRepository.String(), nil)
+	if err != nil {
+		return Repository{}, err
+	}
+
+	var repo Repository
+	if _, err = c.do(ctx, req, &repo); err != nil {
+		return Repository{}, err
+	}
+
+	return repo, nil
+}
+
 func (c *Client) do(ctx context.Context, req *http.Request, result any) (*http.Response, error) {
 	req.URL = c.URL.ResolveReference(req.URL)
 
@@ -124,15 +143,18 @@ func (c *Client) do(ctx context.Context, req *http.Request, result any) (*http.R
 // the given authenticator instance.
 //
 // Note that using an unsupported Authenticator implementation may result in
-// unexpected behaviour, or (more likely) errors. At present, only BasicAuth is
-// supported.
+// unexpected behaviour, or (more likely) errors. At present, only BasicAuth and
+// OAuthBearerToken are supported.
 func (c *Client) WithAuthenticator(a auth.Authenticator) (*Client, error) {
-	if _, ok := a.(*auth.BasicAuth); !ok {
+	switch a.(type) {
+	case *auth.BasicAuth, *auth.OAuthBearerToken:
+	default:
 		return nil, errors.Errorf("authenticator type unsupported for Azure DevOps clients: %s", a)
 	}
 
 	return &Client{
 		httpClient: c.httpClient,
+		Config:     c.Config,
 		URL:        c.URL,
 		auth:       a,
 		rateLimit:  c.rateLimit,
@@ -172,3 +194,13 @@ type httpError struct {
 func (e *httpError) Error() string {
 	return fmt.Sprintf("Azure DevOps API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
 }
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsNotFound reports whether err is an Azure DevOps API response with a 404 status code.
+func IsNotFound(err error) bool {
+	var e *httpError
+	return errors.As(err, &e) && e.NotFound()
+}
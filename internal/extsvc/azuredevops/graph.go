@@ -0,0 +1,153 @@
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// The identity and graph APIs are served from a separate host on Azure DevOps Services,
+// e.g. https://vssps.dev.azure.com. Azure DevOps Server serves them from the instance URL.
+const servicesHost = "dev.azure.com"
+
+func (c *Client) identityURL(path string, qs url.Values) *url.URL {
+	u := *c.URL
+	if u.Host == servicesHost {
+		u.Host = "vssps." + servicesHost
+	}
+	u.Path = path
+	u.RawQuery = qs.Encode()
+	return &u
+}
+
+// SearchIdentitiesByEmail returns the identities in the given organization whose email
+// matches the given one.
+//
+// API docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/ims/identities/read-identities
+func (c *Client) SearchIdentitiesByEmail(ctx context.Context, org, email string) ([]Identity, error) {
+	qs := make(url.Values)
+	qs.Set("api-version", "7.0")
+	qs.Set("searchFilter", "MailAddress")
+	qs.Set("filterValue", email)
+	qs.Set("queryMembership", "None")
+
+	req, err := http.NewRequest("GET", c.identityURL(fmt.Sprintf("%s/_apis/identities", org), qs).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var identities ListIdentitiesResponse
+	if _, err = c.do(ctx, req, &identities); err != nil {
+		return nil, err
+	}
+
+	return identities.Value, nil
+}
+
+// GetIdentitiesBySubjectDescriptors returns the identities in the given organization with
+// the given subject descriptors, e.g. those of graph users.
+//
+// API docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/ims/identities/read-identities
+func (c *Client) GetIdentitiesBySubjectDescriptors(ctx context.Context, org string, subjectDescriptors []string) ([]Identity, error) {
+	qs := make(url.Values)
+	qs.Set("api-version", "7.0")
+	qs.Set("subjectDescriptors", strings.Join(subjectDescriptors, ","))
+	qs.Set("queryMembership", "None")
+
+	req, err := http.NewRequest("GET", c.identityURL(fmt.Sprintf("%s/_apis/identities", org), qs).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var identities ListIdentitiesResponse
+	if _, err = c.do(ctx, req, &identities); err != nil {
+		return nil, err
+	}
+
+	return identities.Value, nil
+}
+
+// GetProjectScopeDescriptor returns the graph descriptor of the project with the given ID,
+// which scopes graph queries to the project.
+//
+// API docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/graph/descriptors/get
+func (c *Client) GetProjectScopeDescriptor(ctx context.Context, org, projectID string) (string, error) {
+	qs := make(url.Values)
+	qs.Set("api-version", "7.0-preview.1")
+
+	req, err := http.NewRequest("GET", c.identityURL(fmt.Sprintf("%s/_apis/graph/descriptors/%s", org, projectID), qs).String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	var descriptor struct {
+		Value string `json:"value"`
+	}
+	if _, err = c.do(ctx, req, &descriptor); err != nil {
+		return "", err
+	}
+
+	return descriptor.Value, nil
+}
+
+// ListUsersInScope returns all users that are members of the given scope, e.g. a project.
+//
+// API docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/graph/users/list
+func (c *Client) ListUsersInScope(ctx context.Context, org, scopeDescriptor string) ([]GraphUser, error) {
+	var users []GraphUser
+	continuationToken := ""
+	for {
+		qs := make(url.Values)
+		qs.Set("api-version", "7.0-preview.1")
+		qs.Set("scopeDescriptor", scopeDescriptor)
+		if continuationToken != "" {
+			qs.Set("continuationToken", continuationToken)
+		}
+
+		req, err := http.NewRequest("GET", c.identityURL(fmt.Sprintf("%s/_apis/graph/users", org), qs).String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page ListGraphUsersResponse
+		resp, err := c.do(ctx, req, &page)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, page.Value...)
+
+		continuationToken = resp.Header.Get("X-MS-ContinuationToken")
+		if continuationToken == "" {
+			return users, nil
+		}
+	}
+}
+
+type ListIdentitiesResponse struct {
+	Value []Identity `json:"value"`
+	Count int        `json:"count"`
+}
+
+type Identity struct {
+	ID                  string `json:"id"`
+	Descriptor          string `json:"descriptor"`
+	SubjectDescriptor   string `json:"subjectDescriptor"`
+	ProviderDisplayName string `json:"providerDisplayName"`
+	IsActive            bool   `json:"isActive"`
+}
+
+type ListGraphUsersResponse struct {
+	Value []GraphUser `json:"value"`
+	Count int         `json:"count"`
+}
+
+type GraphUser struct {
+	Descriptor    string `json:"descriptor"`
+	DisplayName   string `json:"displayName"`
+	MailAddress   string `json:"mailAddress"`
+	PrincipalName string `json:"principalName"`
+	Origin        string `json:"origin"`
+	OriginID      string `json:"originId"`
+}
@@ -0,0 +1,130 @@
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GitNamespaceID is the ID of the security namespace that holds the permissions of Git
+// repositories. Its tokens have the form "repoV2/{projectID}/{repositoryID}".
+const GitNamespaceID = "2e9eb7ed-3c0a-47d4-87c1-0ffdd275fd87"
+
+// GitReadPermission is the bit of the "Read" permission in the Git security namespace.
+const GitReadPermission = 2
+
+// GitRepositoryToken returns the token of a repository in the Git security namespace.
+func GitRepositoryToken(projectID, repositoryID string) string {
+	return fmt.Sprintf("repoV2/%s/%s", projectID, repositoryID)
+}
+
+// GitProjectToken returns the token of a project in the Git security namespace. It is
+// the parent of the tokens of the repositories in the project.
+func GitProjectToken(projectID string) string {
+	return fmt.Sprintf("repoV2/%s", projectID)
+}
+
+// GetAccessControlEntry returns the access control entry of the identity with the given
+// descriptor on the given token of a security namespace, along with the permissions the
+// identity effectively has there through inheritance and the groups it is a member of.
+// It returns nil if there is no entry for the identity.
+func (c *Client) GetAccessControlEntry(ctx context.Context, org, namespaceID, token, descriptor string) (*AccessControlEntry, error) {
+	acls, err := c.ListAccessControlLists(ctx, org, namespaceID, ListAccessControlListsArgs{
+		Token:       token,
+		Descriptors: []string{descriptor},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, acl := range acls {
+		if acl.Token != token {
+			continue
+		}
+		if ace, ok := acl.AcesDictionary[descriptor]; ok {
+			return &ace, nil
+		}
+	}
+	return nil, nil
+}
+
+type ListAccessControlListsArgs struct {
+	// Token is the token of the access control list to return.
+	Token string
+	// Recurse also returns the access control lists of the tokens below Token, e.g. those
+	// of the repositories of a project. Tokens without explicit permissions have no access
+	// control list and are omitted.
+	Recurse bool
+	// Descriptors restricts the returned entries to the identities with these descriptors.
+	Descriptors []string
+}
+
+// ListAccessControlLists returns the access control lists of a security namespace, with
+// the permissions the requested identities effectively have on each token.
+//
+// API docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/security/access-control-lists/query
+func (c *Client) ListAccessControlLists(ctx context.Context, org, namespaceID string, args ListAccessControlListsArgs) ([]AccessControlList, error) {
+	qs := make(url.Values)
+	qs.Set("api-version", "7.0")
+	qs.Set("token", args.Token)
+	qs.Set("descriptors", strings.Join(args.Descriptors, ","))
+	qs.Set("includeExtendedInfo", "true")
+	if args.Recurse {
+		qs.Set("recurse", "true")
+	}
+
+	urlACLs := url.URL{Path: fmt.Sprintf("%s/_apis/accesscontrollists/%s", org, namespaceID), RawQuery: qs.Encode()}
+
+	req, err := http.NewRequest("GET", urlACLs.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var acls ListAccessControlListsResponse
+	if _, err = c.do(ctx, req, &acls); err != nil {
+		return nil, err
+	}
+	return acls.Value, nil
+}
+
+type ListAccessControlListsResponse struct {
+	Value []AccessControlList `json:"value"`
+	Count int                 `json:"count"`
+}
+
+type AccessControlList struct {
+	Token              string                        `json:"token"`
+	InheritPermissions bool                          `json:"inheritPermissions"`
+	AcesDictionary     map[string]AccessControlEntry `json:"acesDictionary"`
+}
+
+type AccessControlEntry struct {
+	Descriptor   string                          `json:"descriptor"`
+	Allow        int                             `json:"allow"`
+	Deny         int                             `json:"deny"`
+	ExtendedInfo *AccessControlEntryExtendedInfo `json:"extendedInfo,omitempty"`
+}
+
+// AccessControlEntryExtendedInfo holds the permissions an identity effectively has, which
+// combine its explicit permissions with those inherited from parent tokens and groups.
+type AccessControlEntryExtendedInfo struct {
+	EffectiveAllow int `json:"effectiveAllow"`
+	EffectiveDeny  int `json:"effectiveDeny"`
+	InheritedAllow int `json:"inheritedAllow"`
+	InheritedDeny  int `json:"inheritedDeny"`
+}
+
+// Allows returns whether the entry effectively allows the given permission. A permission
+// that is denied anywhere is not allowed.
+func (e *AccessControlEntry) Allows(permission int) bool {
+	if e == nil {
+		return false
+	}
+	allow, deny := e.Allow, e.Deny
+	if e.ExtendedInfo != nil {
+		allow, deny = e.ExtendedInfo.EffectiveAllow, e.ExtendedInfo.EffectiveDeny
+	}
+	return allow&permission != 0 && deny&permission == 0
+}
@@ -477,6 +477,9 @@ type UpdatePullRequestInput struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	ToRef       Ref    `json:"toRef"`
+
+	// Reviewers replaces the reviewers of the pull request, if set.
+	Reviewers []Reviewer `json:"reviewers,omitempty"`
 }
 
 func (c *Client) UpdatePullRequest(ctx context.Context, in *UpdatePullRequestInput) (*PullRequest, error) {
@@ -1567,7 +1570,18 @@ func (c *Client) CreatePullRequestComment(ctx context.Context, pr *PullRequest,
 	return err
 }
 
-func (c *Client) MergePullRequest(ctx context.Context, pr *PullRequest) error {
+// Merge strategies that can be passed to MergePullRequest. They have to be
+// enabled for the repository of the pull request.
+const (
+	MergeStrategyNoFastForward         = "no-ff"
+	MergeStrategySquash                = "squash"
+	MergeStrategyRebaseFastForwardOnly = "rebase-ff-only"
+)
+
+// MergePullRequest merges the given pull request with the given merge strategy
+// ID. If the strategy ID is empty, the default strategy of the repository is
+// used.
+func (c *Client) MergePullRequest(ctx context.Context, pr *PullRequest, strategyID string) error {
 	if pr.ToRef.Repository.Slug == "" {
 		return errors.New("repository slug empty")
 	}
@@ -1584,6 +1598,9 @@ func (c *Client) MergePullRequest(ctx context.Context, pr *PullRequest) error {
 	)
 
 	qry := url.Values{"version": {strconv.Itoa(pr.Version)}}
+	if strategyID != "" {
+		qry.Set("strategyId", strategyID)
+	}
 
 	_, err := c.send(ctx, "POST", path, qry, nil, pr)
 	if err != nil {
@@ -1016,7 +1016,7 @@ func TestClient_MergePullRequest(t *testing.T) {
 			tc.err = strings.ReplaceAll(tc.err, "${INSTANCEURL}", instanceURL)
 
 			pr := tc.pr()
-			err := cli.MergePullRequest(tc.ctx, pr)
+			err := cli.MergePullRequest(tc.ctx, pr, "")
 			if have, want := fmt.Sprint(err), tc.err; !strings.Contains(have, want) {
 				t.Fatalf("error:\nhave: %q\nwant: %q", have, want)
 			}
@@ -15,7 +15,7 @@ type CodeHost struct {
 
 func (c *CodeHost) IsPackageHost() bool {
 	switch c.ServiceType {
-	case TypeNpmPackages, TypeJVMPackages, TypeGoModules, TypePythonPackages, TypeRustPackages, TypeRubyPackages:
+	case TypeNpmPackages, TypeJVMPackages, TypeGoModules, TypePythonPackages, TypeRustPackages, TypeRubyPackages, TypeDotnetPackages, TypePHPPackages:
 		return true
 	}
 	return false
@@ -49,6 +49,12 @@ var (
 	RubyURL      = &url.URL{Host: "rubygems"}
 	RubyPackages = NewCodeHost(RubyURL, TypeRubyPackages)
 
+	DotnetURL      = &url.URL{Host: "nuget"}
+	DotnetPackages = NewCodeHost(DotnetURL, TypeDotnetPackages)
+
+	PHPURL      = &url.URL{Host: "packagist"}
+	PHPPackages = NewCodeHost(PHPURL, TypePHPPackages)
+
 	PublicCodeHosts = []*CodeHost{
 		GitHubDotCom,
 		GitLabDotCom,
@@ -58,6 +64,8 @@ var (
 		PythonPackages,
 		RustPackages,
 		RubyPackages,
+		DotnetPackages,
+		PHPPackages,
 	}
 )
 
@@ -184,6 +184,7 @@ type PullRequest struct {
 	TimelineItems  []TimelineItem
 	Commits        struct{ Nodes []CommitWithChecks }
 	IsDraft        bool
+	Mergeable      PullRequestMergeableState
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
@@ -966,8 +967,29 @@ mutation MergePullRequest($input: MergePullRequestInput!) {
 }
 `
 
-// MergePullRequest tries to merge the PullRequest on Github.
-func (c *V4Client) MergePullRequest(ctx context.Context, pr *PullRequest, squash bool) error {
+// PullRequestMergeableState is whether a PullRequest can be merged, as
+// determined by GitHub.
+type PullRequestMergeableState string
+
+// PullRequestMergeableState constants.
+const (
+	PullRequestMergeableStateMergeable   PullRequestMergeableState = "MERGEABLE"
+	PullRequestMergeableStateConflicting PullRequestMergeableState = "CONFLICTING"
+	PullRequestMergeableStateUnknown     PullRequestMergeableState = "UNKNOWN"
+)
+
+// PullRequestMergeMethod is the method used to merge a PullRequest.
+type PullRequestMergeMethod string
+
+const (
+	PullRequestMergeMethodMerge  PullRequestMergeMethod = "MERGE"
+	PullRequestMergeMethodSquash PullRequestMergeMethod = "SQUASH"
+	PullRequestMergeMethodRebase PullRequestMergeMethod = "REBASE"
+)
+
+// MergePullRequest tries to merge the PullRequest on Github with the given
+// merge method.
+func (c *V4Client) MergePullRequest(ctx context.Context, pr *PullRequest, method PullRequestMergeMethod) error {
 	version := c.determineGitHubVersion(ctx)
 	prFragment, err := pullRequestFragments(version)
 	if err != nil {
@@ -984,16 +1006,12 @@ func (c *V4Client) MergePullRequest(ctx context.Context, pr *PullRequest, squash
 		} `json:"mergePullRequest"`
 	}
 
-	mergeMethod := "MERGE"
-	if squash {
-		mergeMethod = "SQUASH"
-	}
 	input := map[string]any{"input": struct {
-		PullRequestID string `json:"pullRequestId"`
-		MergeMethod   string `json:"mergeMethod,omitempty"`
+		PullRequestID string                 `json:"pullRequestId"`
+		MergeMethod   PullRequestMergeMethod `json:"mergeMethod,omitempty"`
 	}{
 		PullRequestID: pr.ID,
-		MergeMethod:   mergeMethod,
+		MergeMethod:   method,
 	}}
 	if err := c.requestGraphQL(ctx, prFragment+"\n"+mergePullRequestMutation, input, &result); err != nil {
 		return err
@@ -1398,6 +1416,7 @@ fragment pr on PullRequest {
   baseRefOid
   headRefName
   baseRefName
+  mergeable
   %s
   author {
     ...actor
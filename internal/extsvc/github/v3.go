@@ -728,6 +728,26 @@ func (c *V3Client) Fork(ctx context.Context, owner, repo string, org *string, fo
 	return convertRestRepo(restRepo), nil
 }
 
+// RequestReviewers requests a review of the given pull request from the
+// given users and teams. Teams are identified by their slug within the
+// organization that owns the repository.
+//
+// API docs: https://docs.github.com/en/rest/pulls/review-requests#request-reviewers-for-a-pull-request
+func (c *V3Client) RequestReviewers(ctx context.Context, pr *PullRequest, reviewers, teamReviewers []string) error {
+	owner, repo, err := SplitRepositoryNameWithOwner(pr.RepoWithOwner)
+	if err != nil {
+		return err
+	}
+
+	payload := struct {
+		Reviewers     []string `json:"reviewers,omitempty"`
+		TeamReviewers []string `json:"team_reviewers,omitempty"`
+	}{Reviewers: reviewers, TeamReviewers: teamReviewers}
+
+	_, err = c.post(ctx, fmt.Sprintf("repos/%s/%s/pulls/%d/requested_reviewers", owner, repo, pr.Number), payload, &struct{}{})
+	return err
+}
+
 // GetAppInstallation gets information of a GitHub App installation.
 //
 // API docs: https://docs.github.com/en/rest/reference/apps#get-an-installation-for-the-authenticated-app
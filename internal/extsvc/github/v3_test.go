@@ -767,6 +767,39 @@ func TestV3Client_Fork(t *testing.T) {
 	})
 }
 
+func TestV3Client_RequestReviewers(t *testing.T) {
+	var (
+		gotMethod string
+		gotPath   string
+		gotBody   map[string][]string
+	)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(testServer.Close)
+
+	uri, _ := url.Parse(testServer.URL)
+	client := NewV3Client(logtest.Scoped(t), "Test", uri, gheToken, testServer.Client())
+
+	pr := &PullRequest{RepoWithOwner: "sourcegraph/sourcegraph", Number: 42}
+	if err := client.RequestReviewers(context.Background(), pr, []string{"alice"}, []string{"batch-changes"}); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "POST", gotMethod)
+	assert.Equal(t, "/repos/sourcegraph/sourcegraph/pulls/42/requested_reviewers", gotPath)
+	assert.Equal(t, map[string][]string{
+		"reviewers":      {"alice"},
+		"team_reviewers": {"batch-changes"},
+	}, gotBody)
+}
+
 func newV3TestClient(t testing.TB, name string) (*V3Client, func()) {
 	t.Helper()
 
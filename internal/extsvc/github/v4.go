@@ -603,6 +603,15 @@ func (c *V4Client) Fork(ctx context.Context, owner, repo string, org *string, fo
 	return NewV3Client(logger, c.urn, c.apiURL, c.auth, c.httpClient).Fork(ctx, owner, repo, org, forkName)
 }
 
+// RequestReviewers requests a review of the given pull request from the
+// given users and teams.
+func (c *V4Client) RequestReviewers(ctx context.Context, pr *PullRequest, reviewers, teamReviewers []string) error {
+	// The GraphQL mutation requires node IDs for users and teams, so we fall
+	// back to the REST API, which accepts logins and team slugs.
+	logger := c.log.Scoped("RequestReviewers", "temporary client for requesting pull request reviewers")
+	return NewV3Client(logger, c.urn, c.apiURL, c.auth, c.httpClient).RequestReviewers(ctx, pr, reviewers, teamReviewers)
+}
+
 type RecentCommittersParams struct {
 	// Repository name
 	Name string
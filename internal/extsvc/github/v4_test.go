@@ -504,7 +504,7 @@ func TestMergePullRequest(t *testing.T) {
 			ID: "PR_kwDODS5xec4waLb5",
 		}
 
-		err := cli.MergePullRequest(context.Background(), pr, true)
+		err := cli.MergePullRequest(context.Background(), pr, PullRequestMergeMethodSquash)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -522,7 +522,7 @@ func TestMergePullRequest(t *testing.T) {
 			ID: "MDExOlB1bGxSZXF1ZXN0NTY1Mzk1NTc3",
 		}
 
-		err := cli.MergePullRequest(context.Background(), pr, true)
+		err := cli.MergePullRequest(context.Background(), pr, PullRequestMergeMethodSquash)
 		if err == nil {
 			t.Fatal("invalid nil error")
 		}
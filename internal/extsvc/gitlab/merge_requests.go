@@ -26,6 +26,14 @@ const (
 	MergeRequestStateMerged MergeRequestState = "merged"
 )
 
+// MergeStatus is whether a MergeRequest can be merged, as determined by GitLab.
+type MergeStatus string
+
+const (
+	MergeStatusCanBeMerged    MergeStatus = "can_be_merged"
+	MergeStatusCannotBeMerged MergeStatus = "cannot_be_merged"
+)
+
 type MergeRequest struct {
 	ID                     ID `json:"id"`
 	IID                    ID `json:"iid"`
@@ -46,6 +54,7 @@ type MergeRequest struct {
 	WebURL                 string            `json:"web_url"`
 	WorkInProgress         bool              `json:"work_in_progress"`
 	Draft                  bool              `json:"draft"`
+	MergeStatus            MergeStatus       `json:"merge_status"`
 	Author                 User              `json:"author"`
 
 	DiffRefs DiffRefs `json:"diff_refs"`
@@ -221,6 +230,7 @@ type UpdateMergeRequestOpts struct {
 	Title        string                       `json:"title,omitempty"`
 	Description  string                       `json:"description,omitempty"`
 	StateEvent   UpdateMergeRequestStateEvent `json:"state_event,omitempty"`
+	ReviewerIDs  []int32                      `json:"reviewer_ids,omitempty"`
 }
 
 type UpdateMergeRequestStateEvent string
@@ -0,0 +1,169 @@
+// Package nuget is a client for the NuGet V3 API as described in
+// https://learn.microsoft.com/en-us/nuget/api/overview.
+//
+// Only the subset of the API needed to download package contents is
+// implemented: the service index, which lists the resources provided by a
+// feed, and the package content resource (PackageBaseAddress), which serves
+// .nupkg archives.
+package nuget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DefaultServiceIndexURL is the service index of nuget.org.
+const DefaultServiceIndexURL = "https://api.nuget.org/v3/index.json"
+
+// packageBaseAddressType is the service index resource type of the package
+// content API.
+const packageBaseAddressType = "PackageBaseAddress/3.0.0"
+
+type Client struct {
+	serviceIndexURL string
+	user            *url.Userinfo
+
+	cli httpcli.Doer
+
+	// Self-imposed rate-limiter.
+	limiter *ratelimit.InstrumentedLimiter
+
+	mu                 sync.Mutex
+	packageBaseAddress string
+}
+
+// NewClient returns a client for the feed at serviceIndexURL. Credentials
+// included in the URL are sent as basic auth with every request.
+func NewClient(urn string, serviceIndexURL string, cli httpcli.Doer) *Client {
+	if serviceIndexURL == "" {
+		serviceIndexURL = DefaultServiceIndexURL
+	}
+
+	var user *url.Userinfo
+	if u, err := url.Parse(serviceIndexURL); err == nil && u.User != nil {
+		user = u.User
+		u.User = nil
+		serviceIndexURL = u.String()
+	}
+
+	return &Client{
+		serviceIndexURL: serviceIndexURL,
+		user:            user,
+		cli:             cli,
+		limiter:         ratelimit.DefaultRegistry.Get(urn),
+	}
+}
+
+// GetPackageContents downloads the .nupkg archive of the given package version.
+func (c *Client) GetPackageContents(ctx context.Context, dep reposource.VersionedPackage) (body io.ReadCloser, url string, err error) {
+	baseAddress, err := c.getPackageBaseAddress(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The package content API requires lower case IDs and versions.
+	id, version := strings.ToLower(string(dep.PackageSyntax())), strings.ToLower(dep.PackageVersion())
+	url = fmt.Sprintf("%s/%s/%s/%s.%s.nupkg", strings.TrimSuffix(baseAddress, "/"), id, version, id, version)
+
+	body, err = c.get(ctx, url)
+	if err != nil {
+		return nil, url, err
+	}
+	return body, url, nil
+}
+
+type serviceIndex struct {
+	Resources []struct {
+		ID   string `json:"@id"`
+		Type string `json:"@type"`
+	} `json:"resources"`
+}
+
+// getPackageBaseAddress returns the URL of the package content API of the
+// feed, reading it from the service index the first time it is called.
+func (c *Client) getPackageBaseAddress(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.packageBaseAddress != "" {
+		return c.packageBaseAddress, nil
+	}
+
+	body, err := c.get(ctx, c.serviceIndexURL)
+	if err != nil {
+		return "", errors.Wrap(err, "fetching NuGet service index")
+	}
+	defer body.Close()
+
+	var index serviceIndex
+	if err := json.NewDecoder(body).Decode(&index); err != nil {
+		return "", errors.Wrap(err, "decoding NuGet service index")
+	}
+
+	for _, r := range index.Resources {
+		if r.Type == packageBaseAddressType {
+			c.packageBaseAddress = r.ID
+			return r.ID, nil
+		}
+	}
+	return "", errors.Newf("NuGet service index %s has no %s resource", c.serviceIndexURL, packageBaseAddressType)
+}
+
+func (c *Client) get(ctx context.Context, url string) (io.ReadCloser, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("User-Agent", "sourcegraph-nuget-syncer (sourcegraph.com)")
+	if c.user != nil {
+		password, _ := c.user.Password()
+		req.SetBasicAuth(c.user.Username(), password)
+	}
+
+	return c.do(req)
+}
+
+type Error struct {
+	path    string
+	code    int
+	message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("bad response with status code %d for %s: %s", e.code, e.path, e.message)
+}
+
+func (e *Error) NotFound() bool {
+	return e.code == http.StatusNotFound
+}
+
+func (c *Client) do(req *http.Request) (io.ReadCloser, error) {
+	resp, err := c.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		bs, err := io.ReadAll(resp.Body)
+		if err != nil {
+			bs = []byte(errors.Wrap(err, "failed to read body").Error())
+		}
+		return nil, &Error{path: req.URL.Path, code: resp.StatusCode, message: string(bs)}
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,61 @@
+package nuget
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func TestGetPackageContents(t *testing.T) {
+	var indexRequests int
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/v3/index.json", func(w http.ResponseWriter, r *http.Request) {
+		indexRequests++
+		if user, password, ok := r.BasicAuth(); !ok || user != "alice" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"version":"3.0.0","resources":[
+			{"@id":"%[1]s/query","@type":"SearchQueryService"},
+			{"@id":"%[1]s/flat/","@type":"PackageBaseAddress/3.0.0"}
+		]}`, srv.URL)
+	})
+	mux.HandleFunc("/flat/newtonsoft.json/13.0.1/newtonsoft.json.13.0.1.nupkg", func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "nupkg")
+	})
+
+	indexURL := "http://alice:secret@" + srv.Listener.Addr().String() + "/v3/index.json"
+	client := NewClient("nuget_urn", indexURL, http.DefaultClient)
+	ctx := context.Background()
+
+	body, url, err := client.GetPackageContents(ctx, reposource.NewDotnetVersionedPackage("Newtonsoft.Json", "13.0.1"))
+	require.NoError(t, err)
+	defer body.Close()
+	assert.Equal(t, srv.URL+"/flat/newtonsoft.json/13.0.1/newtonsoft.json.13.0.1.nupkg", url)
+
+	contents, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "nupkg", string(contents))
+
+	_, _, err = client.GetPackageContents(ctx, reposource.NewDotnetVersionedPackage("Newtonsoft.Json", "0.0.1"))
+	assert.True(t, errcode.IsNotFound(err), "expected not found error, got %v", err)
+
+	// The service index is only fetched once.
+	assert.Equal(t, 1, indexRequests)
+}
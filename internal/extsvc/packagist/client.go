@@ -0,0 +1,255 @@
+// Package packagist is a client for Composer repositories such as
+// https://repo.packagist.org, using the Composer v2 metadata API described in
+// https://getcomposer.org/doc/05-repositories.md#composer.
+package packagist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DefaultRepositoryURL is the URL of the Packagist Composer repository.
+const DefaultRepositoryURL = "https://repo.packagist.org"
+
+type Client struct {
+	repositoryURL string
+	user          *url.Userinfo
+
+	cli httpcli.Doer
+
+	// Self-imposed rate-limiter.
+	limiter *ratelimit.InstrumentedLimiter
+
+	mu          sync.Mutex
+	metadataURL string
+}
+
+// NewClient returns a client for the Composer repository at repositoryURL.
+// Credentials included in the URL are sent as basic auth with every request
+// to the repository.
+func NewClient(urn string, repositoryURL string, cli httpcli.Doer) *Client {
+	if repositoryURL == "" {
+		repositoryURL = DefaultRepositoryURL
+	}
+
+	var user *url.Userinfo
+	if u, err := url.Parse(repositoryURL); err == nil && u.User != nil {
+		user = u.User
+		u.User = nil
+		repositoryURL = u.String()
+	}
+
+	return &Client{
+		repositoryURL: strings.TrimSuffix(repositoryURL, "/"),
+		user:          user,
+		cli:           cli,
+		limiter:       ratelimit.DefaultRegistry.Get(urn),
+	}
+}
+
+// Dist is the distribution archive of a package version.
+type Dist struct {
+	// Type is the archive type, usually "zip".
+	Type      string `json:"type"`
+	URL       string `json:"url"`
+	Reference string `json:"reference"`
+	Shasum    string `json:"shasum"`
+}
+
+// Version is a single version of a package.
+type Version struct {
+	Version           string `json:"version"`
+	VersionNormalized string `json:"version_normalized"`
+	Dist              *Dist  `json:"dist"`
+}
+
+// Versions returns all tagged versions of the given package.
+func (c *Client) Versions(ctx context.Context, name reposource.PackageName) ([]Version, error) {
+	metadataURL, err := c.getMetadataURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.get(ctx, strings.ReplaceAll(metadataURL, "%package%", string(name)), true)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var resp struct {
+		Packages map[string][]map[string]json.RawMessage `json:"packages"`
+	}
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, errors.Wrapf(err, "decoding metadata of Composer package %q", name)
+	}
+
+	return expandVersions(resp.Packages[string(name)])
+}
+
+// Version returns the given version of a package. Versions are matched
+// regardless of a "v" prefix, as tags of Composer packages commonly have one.
+func (c *Client) Version(ctx context.Context, name reposource.PackageName, version string) (Version, error) {
+	versions, err := c.Versions(ctx, name)
+	if err != nil {
+		return Version{}, err
+	}
+
+	for _, v := range versions {
+		if strings.TrimPrefix(v.Version, "v") == strings.TrimPrefix(version, "v") {
+			if v.Dist == nil {
+				return Version{}, errors.Newf("Composer package %s:%s has no dist archive", name, version)
+			}
+			return v, nil
+		}
+	}
+	return Version{}, &Error{path: string(name), code: http.StatusNotFound, message: fmt.Sprintf("version %q not found", version)}
+}
+
+// Download downloads the dist archive at the given URL. Dist archives are
+// commonly hosted elsewhere than the repository (e.g. on GitHub), so the
+// repository credentials are only sent along to URLs on the repository host.
+func (c *Client) Download(ctx context.Context, distURL string) (io.ReadCloser, error) {
+	return c.get(ctx, distURL, c.isRepositoryURL(distURL))
+}
+
+// expandVersions expands the minified version list of the Composer v2
+// metadata format, in which each version only lists the fields that differ
+// from the previous version, and "__unset" removes a field.
+func expandVersions(minified []map[string]json.RawMessage) ([]Version, error) {
+	versions := make([]Version, 0, len(minified))
+	expanded := make(map[string]json.RawMessage)
+	for _, fields := range minified {
+		for k, v := range fields {
+			if string(v) == `"__unset"` {
+				delete(expanded, k)
+				continue
+			}
+			expanded[k] = v
+		}
+
+		b, err := json.Marshal(expanded)
+		if err != nil {
+			return nil, err
+		}
+		var v Version
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+// getMetadataURL returns the metadata-url template of the repository, reading
+// it from its packages.json the first time it is called.
+func (c *Client) getMetadataURL(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.metadataURL != "" {
+		return c.metadataURL, nil
+	}
+
+	body, err := c.get(ctx, c.repositoryURL+"/packages.json", true)
+	if err != nil {
+		return "", errors.Wrap(err, "fetching Composer repository packages.json")
+	}
+	defer body.Close()
+
+	var packages struct {
+		MetadataURL string `json:"metadata-url"`
+	}
+	if err := json.NewDecoder(body).Decode(&packages); err != nil {
+		return "", errors.Wrap(err, "decoding Composer repository packages.json")
+	}
+	if packages.MetadataURL == "" {
+		return "", errors.Newf("Composer repository %s does not support the Composer v2 metadata API", c.repositoryURL)
+	}
+
+	// The template contains a %package% placeholder, which isn't a valid URL
+	// escape, so it is resolved against the repository URL by hand.
+	switch {
+	case strings.Contains(packages.MetadataURL, "://"):
+		c.metadataURL = packages.MetadataURL
+	case strings.HasPrefix(packages.MetadataURL, "/"):
+		repo, err := url.Parse(c.repositoryURL)
+		if err != nil {
+			return "", err
+		}
+		c.metadataURL = repo.Scheme + "://" + repo.Host + packages.MetadataURL
+	default:
+		c.metadataURL = c.repositoryURL + "/" + packages.MetadataURL
+	}
+	return c.metadataURL, nil
+}
+
+func (c *Client) isRepositoryURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	repo, err := url.Parse(c.repositoryURL)
+	if err != nil {
+		return false
+	}
+	return u.Host == repo.Host
+}
+
+func (c *Client) get(ctx context.Context, url string, authenticate bool) (io.ReadCloser, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("User-Agent", "sourcegraph-packagist-syncer (sourcegraph.com)")
+	if authenticate && c.user != nil {
+		password, _ := c.user.Password()
+		req.SetBasicAuth(c.user.Username(), password)
+	}
+
+	return c.do(req)
+}
+
+type Error struct {
+	path    string
+	code    int
+	message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("bad response with status code %d for %s: %s", e.code, e.path, e.message)
+}
+
+func (e *Error) NotFound() bool {
+	return e.code == http.StatusNotFound
+}
+
+func (c *Client) do(req *http.Request) (io.ReadCloser, error) {
+	resp, err := c.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		bs, err := io.ReadAll(resp.Body)
+		if err != nil {
+			bs = []byte(errors.Wrap(err, "failed to read body").Error())
+		}
+		return nil, &Error{path: req.URL.Path, code: resp.StatusCode, message: string(bs)}
+	}
+	return resp.Body, nil
+}
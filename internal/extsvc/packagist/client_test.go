@@ -0,0 +1,89 @@
+package packagist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func TestExpandVersions(t *testing.T) {
+	var minified []map[string]json.RawMessage
+	err := json.Unmarshal([]byte(`[
+		{"name":"monolog/monolog","version":"3.2.0","version_normalized":"3.2.0.0","dist":{"type":"zip","url":"https://example.org/320.zip","reference":"abc","shasum":""},"license":["MIT"]},
+		{"version":"3.1.0","version_normalized":"3.1.0.0","dist":{"type":"zip","url":"https://example.org/310.zip","reference":"def","shasum":""}},
+		{"version":"3.0.0","version_normalized":"3.0.0.0","dist":"__unset"}
+	]`), &minified)
+	require.NoError(t, err)
+
+	versions, err := expandVersions(minified)
+	require.NoError(t, err)
+
+	want := []Version{
+		{Version: "3.2.0", VersionNormalized: "3.2.0.0", Dist: &Dist{Type: "zip", URL: "https://example.org/320.zip", Reference: "abc"}},
+		{Version: "3.1.0", VersionNormalized: "3.1.0.0", Dist: &Dist{Type: "zip", URL: "https://example.org/310.zip", Reference: "def"}},
+		{Version: "3.0.0", VersionNormalized: "3.0.0.0"},
+	}
+	if diff := cmp.Diff(want, versions); diff != "" {
+		t.Errorf("unexpected versions (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient(t *testing.T) {
+	var packagesRequests int
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/composer/packages.json", func(w http.ResponseWriter, r *http.Request) {
+		packagesRequests++
+		_, _ = io.WriteString(w, `{"packages":[],"metadata-url":"/composer/p2/%package%.json"}`)
+	})
+	mux.HandleFunc("/composer/p2/monolog/monolog.json", func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "alice" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"packages":{"monolog/monolog":[
+			{"name":"monolog/monolog","version":"v3.2.0","dist":{"type":"zip","url":"%[1]s/dist/320.zip"}},
+			{"version":"v3.1.0","dist":{"type":"zip","url":"%[1]s/dist/310.zip"}}
+		]}}`, srv.URL)
+	})
+	mux.HandleFunc("/dist/310.zip", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "zip")
+	})
+
+	repositoryURL := "http://alice:secret@" + srv.Listener.Addr().String() + "/composer/"
+	client := NewClient("packagist_urn", repositoryURL, http.DefaultClient)
+	ctx := context.Background()
+
+	version, err := client.Version(ctx, "monolog/monolog", "3.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "v3.1.0", version.Version)
+	assert.Equal(t, srv.URL+"/dist/310.zip", version.Dist.URL)
+
+	body, err := client.Download(ctx, version.Dist.URL)
+	require.NoError(t, err)
+	defer body.Close()
+	contents, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "zip", string(contents))
+
+	_, err = client.Version(ctx, "monolog/monolog", "1.0.0")
+	assert.True(t, errcode.IsNotFound(err), "expected not found error, got %v", err)
+
+	_, err = client.Version(ctx, "monolog/missing", "1.0.0")
+	assert.True(t, errcode.IsNotFound(err), "expected not found error, got %v", err)
+
+	// packages.json is only fetched once.
+	assert.Equal(t, 1, packagesRequests)
+}
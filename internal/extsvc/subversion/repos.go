@@ -0,0 +1,8 @@
+package subversion
+
+// Repository contains information of a Subversion repository.
+type Repository struct {
+	// Path is the path of the repository relative to the base URL of the
+	// Subversion server.
+	Path string `json:"path"`
+}
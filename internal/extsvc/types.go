@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/url"
 	"strconv"
 	"strings"
@@ -136,9 +137,12 @@ const (
 	KindPythonPackages  = "PYTHONPACKAGES"
 	KindRustPackages    = "RUSTPACKAGES"
 	KindRubyPackages    = "RUBYPACKAGES"
+	KindDotnetPackages  = "DOTNETPACKAGES"
+	KindPHPPackages     = "PHPPACKAGES"
 	KindNpmPackages     = "NPMPACKAGES"
 	KindPagure          = "PAGURE"
 	KindAzureDevOps     = "AZUREDEVOPS"
+	KindSubversion      = "SUBVERSION"
 	KindOther           = "OTHER"
 )
 
@@ -187,6 +191,10 @@ const (
 	// TypeAzureDevOps is the (api.ExternalRepoSpec).ServiceType value for ADO projects.
 	TypeAzureDevOps = "azuredevops"
 
+	// TypeSubversion is the (api.ExternalRepoSpec).ServiceType value for Subversion repositories. The
+	// ServiceID value is the base URL of the Subversion server.
+	TypeSubversion = "subversion"
+
 	// TypeNpmPackages is the (api.ExternalRepoSpec).ServiceType value for Npm packages (JavaScript/TypeScript ecosystem libraries).
 	TypeNpmPackages = "npmPackages"
 
@@ -202,6 +210,12 @@ const (
 	// TypeRubyPackages is the (api.ExternalRepoSpec).ServiceType value for Ruby packages.
 	TypeRubyPackages = "rubyPackages"
 
+	// TypeDotnetPackages is the (api.ExternalRepoSpec).ServiceType value for .NET packages hosted on NuGet feeds.
+	TypeDotnetPackages = "dotnetPackages"
+
+	// TypePHPPackages is the (api.ExternalRepoSpec).ServiceType value for PHP packages hosted on Composer repositories.
+	TypePHPPackages = "phpPackages"
+
 	// TypeOther is the (api.ExternalRepoSpec).ServiceType value for other projects.
 	TypeOther = "other"
 )
@@ -236,6 +250,10 @@ func KindToType(kind string) string {
 		return TypeRustPackages
 	case KindRubyPackages:
 		return TypeRubyPackages
+	case KindDotnetPackages:
+		return TypeDotnetPackages
+	case KindPHPPackages:
+		return TypePHPPackages
 	case KindNpmPackages:
 		return TypeNpmPackages
 	case KindGoPackages:
@@ -244,6 +262,8 @@ func KindToType(kind string) string {
 		return TypePagure
 	case KindAzureDevOps:
 		return TypeAzureDevOps
+	case KindSubversion:
+		return TypeSubversion
 	case KindOther:
 		return TypeOther
 	default:
@@ -283,12 +303,18 @@ func TypeToKind(t string) string {
 		return KindRustPackages
 	case TypeRubyPackages:
 		return KindRubyPackages
+	case TypeDotnetPackages:
+		return KindDotnetPackages
+	case TypePHPPackages:
+		return KindPHPPackages
 	case TypeGoModules:
 		return KindGoPackages
 	case TypePagure:
 		return KindPagure
 	case TypeAzureDevOps:
 		return KindAzureDevOps
+	case TypeSubversion:
+		return KindSubversion
 	case TypeOther:
 		return KindOther
 	default:
@@ -306,6 +332,8 @@ var (
 	pythonLower = strings.ToLower(TypePythonPackages)
 	rustLower   = strings.ToLower(TypeRustPackages)
 	rubyLower   = strings.ToLower(TypeRubyPackages)
+	dotnetLower = strings.ToLower(TypeDotnetPackages)
+	phpLower    = strings.ToLower(TypePHPPackages)
 )
 
 // ParseServiceType will return a ServiceType constant after doing a case insensitive match on s.
@@ -342,10 +370,16 @@ func ParseServiceType(s string) (string, bool) {
 		return TypeRustPackages, true
 	case rubyLower:
 		return TypeRubyPackages, true
+	case dotnetLower:
+		return TypeDotnetPackages, true
+	case phpLower:
+		return TypePHPPackages, true
 	case TypePagure:
 		return TypePagure, true
 	case TypeAzureDevOps:
 		return TypeAzureDevOps, true
+	case TypeSubversion:
+		return TypeSubversion, true
 	case TypeOther:
 		return TypeOther, true
 	default:
@@ -385,10 +419,16 @@ func ParseServiceKind(s string) (string, bool) {
 		return KindRustPackages, true
 	case KindRubyPackages:
 		return KindRubyPackages, true
+	case KindDotnetPackages:
+		return KindDotnetPackages, true
+	case KindPHPPackages:
+		return KindPHPPackages, true
 	case KindPagure:
 		return KindPagure, true
 	case KindAzureDevOps:
 		return KindAzureDevOps, true
+	case KindSubversion:
+		return KindSubversion, true
 	case KindOther:
 		return KindOther, true
 	default:
@@ -488,6 +528,12 @@ func getConfigPrototype(kind string) (any, error) {
 		return &schema.RustPackagesConnection{}, nil
 	case KindRubyPackages:
 		return &schema.RubyPackagesConnection{}, nil
+	case KindDotnetPackages:
+		return &schema.DotnetPackagesConnection{}, nil
+	case KindPHPPackages:
+		return &schema.PHPPackagesConnection{}, nil
+	case KindSubversion:
+		return &schema.SubversionConnection{}, nil
 	case KindOther:
 		return &schema.OtherExternalServiceConnection{}, nil
 	default:
@@ -676,6 +722,18 @@ func GetLimitFromConfig(kind string, config any) (rate.Limit, error) {
 		if c != nil && c.RateLimit != nil {
 			limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
 		}
+	case *schema.DotnetPackagesConnection:
+		// nuget.org doesn't document an enforced rate limit for the package content API.
+		limit = rate.Limit(36000.0 / 3600.0) // Same as default in dotnet-packages.schema.json
+		if c != nil && c.RateLimit != nil {
+			limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+		}
+	case *schema.PHPPackagesConnection:
+		// repo.packagist.org is served from a CDN and doesn't document an enforced rate limit.
+		limit = rate.Limit(36000.0 / 3600.0) // Same as default in php-packages.schema.json
+		if c != nil && c.RateLimit != nil {
+			limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+		}
 	default:
 		return limit, ErrRateLimitUnsupported{codehostKind: kind}
 	}
@@ -698,6 +756,65 @@ func (e ErrRateLimitUnsupported) Error() string {
 	return fmt.Sprintf("internal rate limiting not supported for %s", e.codehostKind)
 }
 
+// DiskQuotas are the limits on the disk space gitserver uses for the
+// repositories of an external service. Zero means no limit.
+type DiskQuotas struct {
+	// Total is the limit for all repositories of the external service together.
+	Total int64
+	// PerRepo is the limit for each repository of the external service.
+	PerRepo int64
+}
+
+// ExtractDiskQuotas returns the disk quotas of the external service with the
+// given ID in quotas, the codeHostDiskQuotas site configuration setting.
+func ExtractDiskQuotas(quotas map[string]schema.DiskQuota, id int64) (DiskQuotas, error) {
+	q, ok := quotas[strconv.FormatInt(id, 10)]
+	if !ok {
+		return DiskQuotas{}, nil
+	}
+
+	var (
+		dq  DiskQuotas
+		err error
+	)
+	if dq.Total, err = ParseDiskSize(q.Total); err != nil {
+		return DiskQuotas{}, errors.Wrap(err, "total")
+	}
+	if dq.PerRepo, err = ParseDiskSize(q.PerRepo); err != nil {
+		return DiskQuotas{}, errors.Wrap(err, "perRepo")
+	}
+	return dq, nil
+}
+
+// ParseDiskSize parses a size in bytes with an optional k, m, g or t suffix, as
+// used by disk quotas. The empty string is parsed as zero.
+func ParseDiskSize(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	digits, shift := s, uint(0)
+	switch s[len(s)-1] {
+	case 'k':
+		shift = 10
+	case 'm':
+		shift = 20
+	case 'g':
+		shift = 30
+	case 't':
+		shift = 40
+	}
+	if shift > 0 {
+		digits = s[:len(s)-1]
+	}
+
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || n < 0 || n > math.MaxInt64>>shift {
+		return 0, errors.Errorf("invalid size %q", s)
+	}
+	return n << shift, nil
+}
+
 const (
 	URNGitHubApp   = "GitHubApp"
 	URNGitHubOAuth = "GitHubOAuth"
@@ -779,6 +896,8 @@ func uniqueCodeHostIdentifier(kind string, cfg any) (string, error) {
 		rawURL = c.Url
 	case *schema.OtherExternalServiceConnection:
 		rawURL = c.Url
+	case *schema.SubversionConnection:
+		rawURL = c.Url
 	case *schema.GitoliteConnection:
 		rawURL = c.Host
 	case *schema.AWSCodeCommitConnection:
@@ -800,6 +919,10 @@ func uniqueCodeHostIdentifier(kind string, cfg any) (string, error) {
 		return KindRustPackages, nil
 	case *schema.RubyPackagesConnection:
 		return KindRubyPackages, nil
+	case *schema.DotnetPackagesConnection:
+		return KindDotnetPackages, nil
+	case *schema.PHPPackagesConnection:
+		return KindPHPPackages, nil
 	case *schema.PagureConnection:
 		rawURL = c.Url
 	default:
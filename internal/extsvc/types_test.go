@@ -166,6 +166,53 @@ func TestExtractRateLimitConfig(t *testing.T) {
 	}
 }
 
+func TestExtractDiskQuotas(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		quotas  map[string]schema.DiskQuota
+		want    DiskQuotas
+		wantErr bool
+	}{
+		{
+			name: "no quotas",
+		},
+		{
+			name:   "quotas of another connection",
+			quotas: map[string]schema.DiskQuota{"2": {Total: "1g"}},
+		},
+		{
+			name:   "quotas",
+			quotas: map[string]schema.DiskQuota{"1": {Total: "2t", PerRepo: "512m"}},
+			want:   DiskQuotas{Total: 2 << 40, PerRepo: 512 << 20},
+		},
+		{
+			name:   "bytes",
+			quotas: map[string]schema.DiskQuota{"1": {PerRepo: "1000"}},
+			want:   DiskQuotas{PerRepo: 1000},
+		},
+		{
+			name:    "invalid",
+			quotas:  map[string]schema.DiskQuota{"1": {Total: "10 GB"}},
+			wantErr: true,
+		},
+		{
+			name:    "overflow",
+			quotas:  map[string]schema.DiskQuota{"1": {Total: "99999999999t"}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ExtractDiskQuotas(tc.quotas, 1)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestEncodeURN(t *testing.T) {
 	tests := []struct {
 		desc    string
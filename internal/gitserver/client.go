@@ -10,13 +10,17 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/gobwas/glob"
 	"github.com/grafana/regexp"
 	"github.com/neelance/parallel"
 	"github.com/opentracing-contrib/go-stdlib/nethttp"
@@ -36,7 +40,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/proto"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
@@ -75,7 +81,11 @@ func NewClient() Client {
 		addrs: func() []string {
 			return conf.Get().ServiceConnections().GitServers
 		},
-		pinned:      pinnedReposFromConfig,
+		pinned:     pinnedReposFromConfig,
+		replicated: replicatedReposFromConfig,
+		routing: func() *conftypes.GitServerRouting {
+			return conf.Get().ServiceConnections().GitServerRouting
+		},
 		httpClient:  defaultDoer,
 		HTTPLimiter: defaultLimiter,
 		// Use the binary name for userAgent. This should effectively identify
@@ -83,6 +93,7 @@ func NewClient() Client {
 		// frontend internal API)
 		userAgent:  filepath.Base(os.Args[0]),
 		operations: getOperations(),
+		useGRPC:    grpcEnabledFromConfig,
 	}
 }
 
@@ -95,7 +106,11 @@ func NewTestClient(cli httpcli.Doer, addrs []string) Client {
 		addrs: func() []string {
 			return addrs
 		},
-		pinned:      pinnedReposFromConfig,
+		pinned:     pinnedReposFromConfig,
+		replicated: replicatedReposFromConfig,
+		routing: func() *conftypes.GitServerRouting {
+			return nil
+		},
 		httpClient:  cli,
 		HTTPLimiter: parallel.NewRun(500),
 		// Use the binary name for userAgent. This should effectively identify
@@ -202,8 +217,20 @@ type clientImplementor struct {
 	// and sync the pinned map.
 	pinned func() map[string]string
 
+	// replicated returns the replication factors of repositories, keyed by
+	// repository name or glob pattern. Like pinned, it should query the conf.
+	replicated func() map[string]int
+
+	// routing returns the shard layout recorded by the frontend, or nil if all
+	// addresses should be used with modulo sharding.
+	routing func() *conftypes.GitServerRouting
+
 	// operations are used for internal observability
 	operations *operations
+
+	// useGRPC reports whether requests should be sent over gRPC instead of
+	// HTTP. If nil, HTTP is used.
+	useGRPC func() bool
 }
 
 type RawBatchLogResult struct {
@@ -306,6 +333,12 @@ type Client interface {
 	// update won't happen.
 	RequestRepoUpdate(context.Context, api.RepoName, time.Duration) (*protocol.RepoUpdateResponse, error)
 
+	// RequestRepoMigrate requests that the gitserver at address to clone the
+	// repository from the gitserver at address from, instead of from the code
+	// host. It blocks until the clone has finished. If the repository is already
+	// cloned on to, it is updated from the code host instead.
+	RequestRepoMigrate(ctx context.Context, repo api.RepoName, from, to string) (*protocol.RepoUpdateResponse, error)
+
 	// RequestRepoClone is an asynchronous request to clone a repository.
 	RequestRepoClone(context.Context, api.RepoName) (*protocol.RepoCloneResponse, error)
 
@@ -445,14 +478,51 @@ func (c *clientImplementor) Addrs() []string {
 }
 
 func (c *clientImplementor) AddrForRepo(ctx context.Context, repo api.RepoName) (string, error) {
+	return AddrForRepo(ctx, c.userAgent, repo, c.gitServerAddresses())
+}
+
+func (c *clientImplementor) gitServerAddresses() GitServerAddresses {
 	addrs := c.Addrs()
 	if len(addrs) == 0 {
 		panic("unexpected state: no gitserver addresses")
 	}
-	return AddrForRepo(ctx, c.userAgent, repo, GitServerAddresses{
-		Addresses:     addrs,
-		PinnedServers: c.pinned(),
-	})
+	addresses := NewGitServerAddresses(addrs, c.routing(), c.pinned())
+	if c.replicated != nil {
+		addresses.ReplicatedRepos = c.replicated()
+	}
+	return addresses
+}
+
+// readAddrsForRepo returns the addresses that a read-only request for repo
+// should be sent to, in order. If repo is replicated, the first address is a
+// randomly chosen replica, followed by the owner of the repo as a fallback in
+// case the replica has not cloned it yet or lags behind the owner. Replicas
+// answer requests for revisions they lack as if they had not cloned repo.
+// Otherwise, only the owner is returned.
+func (c *clientImplementor) readAddrsForRepo(ctx context.Context, repo api.RepoName) ([]string, error) {
+	replicas, err := ReplicaAddrsForRepo(ctx, c.userAgent, repo, c.gitServerAddresses())
+	if err != nil {
+		return nil, err
+	}
+	i := rand.Intn(len(replicas))
+	if i == 0 {
+		return replicas[:1], nil
+	}
+	return []string{replicas[i], replicas[0]}, nil
+}
+
+// addrsForRequest returns the addresses that a request for repo should be sent
+// to, in order. Read-only requests may be served by a replica of repo, see
+// readAddrsForRepo, while all other requests go to the owner of repo.
+func (c *clientImplementor) addrsForRequest(ctx context.Context, repo api.RepoName, readOnly bool) ([]string, error) {
+	if readOnly {
+		return c.readAddrsForRepo(ctx, repo)
+	}
+	addr, err := c.AddrForRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	return []string{addr}, nil
 }
 
 var addrForRepoInvoked = promauto.NewCounterVec(prometheus.CounterOpts{
@@ -471,12 +541,144 @@ func AddrForRepo(ctx context.Context, userAgent string, repo api.RepoName, addre
 		return addr, nil
 	}
 
+	if addresses.Sharding == ShardingRendezvous {
+		return rendezvousAddrForKey(rs, addresses.Addresses), nil
+	}
 	return addrForKey(rs, addresses.Addresses), nil
 }
 
+// ReplicaAddrsForRepo returns the addresses of the gitservers holding a copy of
+// the given repo. The first address is the owner of the repo as returned by
+// AddrForRepo, followed by the addresses of its replicas, if any.
+func ReplicaAddrsForRepo(ctx context.Context, userAgent string, repo api.RepoName, addresses GitServerAddresses) ([]string, error) {
+	primary, err := AddrForRepo(ctx, userAgent, repo, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	repo = protocol.NormalizeRepo(repo)
+	if repoPinned, _ := getPinnedRepoAddr(string(repo), addresses.PinnedServers); repoPinned {
+		return []string{primary}, nil
+	}
+
+	factor := ReplicationFactor(repo, addresses.ReplicatedRepos)
+	if factor <= 1 {
+		return []string{primary}, nil
+	}
+
+	// Replicas are the remaining addresses with the highest rendezvous hashes, so
+	// that adding or removing an address moves as few replicas as possible.
+	others := make([]string, 0, len(addresses.Addresses))
+	for _, addr := range addresses.Addresses {
+		if addr != primary {
+			others = append(others, addr)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool {
+		return rendezvousScore(others[i], string(repo)) > rendezvousScore(others[j], string(repo))
+	})
+	if len(others) > factor-1 {
+		others = others[:factor-1]
+	}
+	return append([]string{primary}, others...), nil
+}
+
+// ReplicationFactor returns the number of gitservers that should hold a copy of
+// repo according to replicated, which maps repository names or glob patterns to
+// replication factors. An exact match of the name wins over patterns, and the
+// highest factor of all matching patterns is used otherwise.
+func ReplicationFactor(repo api.RepoName, replicated map[string]int) int {
+	if factor, ok := replicated[string(repo)]; ok {
+		return factor
+	}
+
+	factor := 1
+	for pattern, f := range replicated {
+		if f > factor && MatchRepoPattern(pattern, repo) {
+			factor = f
+		}
+	}
+	return factor
+}
+
+// repoPatterns caches the compiled glob patterns passed to MatchRepoPattern.
+var repoPatterns sync.Map // map[string]glob.Glob
+
+// MatchRepoPattern reports whether repo matches pattern, a glob pattern in which
+// '*' does not match '/'. Invalid patterns match no repository.
+func MatchRepoPattern(pattern string, repo api.RepoName) bool {
+	g, ok := repoPatterns.Load(pattern)
+	if !ok {
+		compiled, err := glob.Compile(pattern, '/')
+		if err != nil {
+			return false
+		}
+		g, _ = repoPatterns.LoadOrStore(pattern, compiled)
+	}
+	return g.(glob.Glob).Match(string(repo))
+}
+
+const (
+	// ShardingModulo assigns a repository to the address at the index of its hash
+	// modulo the number of addresses. Changing the number of addresses moves almost
+	// every repository to another address.
+	ShardingModulo = "modulo"
+	// ShardingRendezvous assigns a repository to the address with the highest hash
+	// of the address and the repository. Adding an address only moves the
+	// repositories it now owns, and removing one only moves those it owned.
+	ShardingRendezvous = "rendezvous"
+)
+
 type GitServerAddresses struct {
 	Addresses     []string
 	PinnedServers map[string]string
+	// Sharding is the hashing scheme used to assign repositories to Addresses. The
+	// zero value is the same as ShardingModulo.
+	Sharding string
+	// Next is the layout repositories are being moved to by a rebalance in
+	// progress, if any. It is not used by AddrForRepo.
+	Next *GitServerAddresses
+	// ReplicatedRepos maps repository names or glob patterns to the number of
+	// addresses that hold a copy of matching repositories. It is only used by
+	// ReplicaAddrsForRepo.
+	ReplicatedRepos map[string]int
+}
+
+// NewGitServerAddresses returns the addresses that requests for a repository should
+// be routed with, given the live gitserver addresses and the routing recorded by the
+// frontend. Addresses of the routing that are no longer live are dropped, and all
+// live addresses are used with modulo sharding if routing is nil.
+func NewGitServerAddresses(live []string, routing *conftypes.GitServerRouting, pinned map[string]string) GitServerAddresses {
+	addrs := GitServerAddresses{
+		Addresses:     live,
+		PinnedServers: pinned,
+		Sharding:      ShardingModulo,
+	}
+	if routing == nil {
+		return addrs
+	}
+
+	isLive := make(map[string]bool, len(live))
+	for _, addr := range live {
+		isLive[addr] = true
+	}
+	var routed []string
+	for _, addr := range routing.Addresses {
+		if isLive[addr] {
+			routed = append(routed, addr)
+		}
+	}
+	if len(routed) > 0 {
+		addrs.Addresses = routed
+	}
+	if routing.Sharding != "" {
+		addrs.Sharding = routing.Sharding
+	}
+	if routing.Next != nil {
+		next := NewGitServerAddresses(live, routing.Next, pinned)
+		addrs.Next = &next
+	}
+	return addrs
 }
 
 // addrForKey returns the gitserver address to use for the given string key,
@@ -487,6 +689,27 @@ func addrForKey(key string, addrs []string) string {
 	return addrs[serverIndex]
 }
 
+// rendezvousAddrForKey returns the gitserver address to use for the given string
+// key using rendezvous hashing: the address with the highest hash of the address
+// and the key wins.
+func rendezvousAddrForKey(key string, addrs []string) string {
+	var (
+		best      string
+		bestScore uint64
+	)
+	for i, addr := range addrs {
+		if score := rendezvousScore(addr, key); i == 0 || score > bestScore {
+			best, bestScore = addr, score
+		}
+	}
+	return best
+}
+
+func rendezvousScore(addr, key string) uint64 {
+	sum := md5.Sum([]byte(addr + "/" + key))
+	return binary.BigEndian.Uint64(sum[:])
+}
+
 // ArchiveOptions contains options for the Archive func.
 type ArchiveOptions struct {
 	Treeish   string               // the tree or commit to produce an archive for
@@ -529,8 +752,8 @@ func (a *archiveReader) Close() error {
 }
 
 // archiveURL returns a URL from which an archive of the given Git repository can
-// be downloaded from.
-func (c *clientImplementor) archiveURL(ctx context.Context, repo api.RepoName, opt ArchiveOptions) (*url.URL, error) {
+// be downloaded from the gitserver at addr.
+func archiveURL(addr string, repo api.RepoName, opt ArchiveOptions) *url.URL {
 	q := url.Values{
 		"repo":    {string(repo)},
 		"treeish": {opt.Treeish},
@@ -541,16 +764,12 @@ func (c *clientImplementor) archiveURL(ctx context.Context, repo api.RepoName, o
 		q.Add("path", string(pathspec))
 	}
 
-	addrForRepo, err := c.AddrForRepo(ctx, repo)
-	if err != nil {
-		return nil, err
-	}
 	return &url.URL{
 		Scheme:   "http",
-		Host:     addrForRepo,
+		Host:     addr,
 		Path:     "/archive",
 		RawQuery: q.Encode(),
-	}, nil
+	}
 }
 
 type badRequestError struct{ error }
@@ -585,6 +804,15 @@ func (c *RemoteGitCommand) sendExec(ctx context.Context) (_ io.ReadCloser, _ htt
 		Stdin:          c.stdin,
 		NoTimeout:      c.noTimeout,
 	}
+
+	if c.execGRPCFn != nil {
+		r, err := c.execGRPCFn(ctx, repoName, req.ToProto())
+		if err != nil {
+			return nil, nil, err
+		}
+		return r, r.trailer, nil
+	}
+
 	resp, err := c.execFn(ctx, repoName, "exec", req)
 	if err != nil {
 		return nil, nil, err
@@ -625,6 +853,33 @@ func (c *clientImplementor) Search(ctx context.Context, args *protocol.SearchReq
 
 	repoName := protocol.NormalizeRepo(args.Repo)
 
+	addrs, err := c.readAddrsForRepo(ctx, repoName)
+	if err != nil {
+		return false, err
+	}
+
+	search := c.searchHTTP
+	if c.grpcEnabled() {
+		search = c.searchGRPC
+	}
+
+	// If a replica has not cloned the repo yet or lacks a searched revision, it
+	// fails before sending any matches and we retry on the next address.
+	for _, addr := range addrs[:len(addrs)-1] {
+		matched := false
+		limitHit, err := search(ctx, addr, repoName, args, func(matches []protocol.CommitMatch) {
+			matched = true
+			onMatches(matches)
+		})
+		var notExist *gitdomain.RepoNotExistError
+		if matched || !errors.As(err, &notExist) {
+			return limitHit, err
+		}
+	}
+	return search(ctx, addrs[len(addrs)-1], repoName, args, onMatches)
+}
+
+func (c *clientImplementor) searchHTTP(ctx context.Context, addr string, repoName api.RepoName, args *protocol.SearchRequest, onMatches func([]protocol.CommitMatch)) (limitHit bool, err error) {
 	protocol.RegisterGob()
 	var buf bytes.Buffer
 	enc := gob.NewEncoder(&buf)
@@ -632,12 +887,7 @@ func (c *clientImplementor) Search(ctx context.Context, args *protocol.SearchReq
 		return false, err
 	}
 
-	addrForRepo, err := c.AddrForRepo(ctx, repoName)
-	if err != nil {
-		return false, err
-	}
-
-	uri := "http://" + addrForRepo + "/search"
+	uri := "http://" + addr + "/search"
 	resp, err := c.do(ctx, repoName, "POST", uri, buf.Bytes())
 	if err != nil {
 		return false, err
@@ -671,6 +921,47 @@ func (c *clientImplementor) Search(ctx context.Context, args *protocol.SearchReq
 	return eventDone.LimitHit, eventDone.Err()
 }
 
+func (c *clientImplementor) searchGRPC(ctx context.Context, addr string, _ api.RepoName, args *protocol.SearchRequest, onMatches func([]protocol.CommitMatch)) (limitHit bool, err error) {
+	conn, err := grpcConnForAddr(addr)
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := proto.NewGitserverServiceClient(conn).Search(ctx, args.ToProto())
+	if err != nil {
+		return false, err
+	}
+
+	var eventDone protocol.SearchEventDone
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false, err
+		}
+
+		switch m := resp.GetMessage().(type) {
+		case *proto.SearchResponse_Matches:
+			matches := make([]protocol.CommitMatch, len(m.Matches.GetMatches()))
+			for i, match := range m.Matches.GetMatches() {
+				matches[i].FromProto(match)
+			}
+			onMatches(matches)
+		case *proto.SearchResponse_Done:
+			eventDone.FromProto(m.Done)
+		default:
+			return false, errors.Errorf("unknown search response %T", m)
+		}
+	}
+
+	return eventDone.LimitHit, eventDone.Err()
+}
+
 func (c *clientImplementor) P4Exec(ctx context.Context, host, user, password string, args ...string) (_ io.ReadCloser, _ http.Header, errRes error) {
 	span, ctx := ot.StartSpanFromContext(ctx, "Client.P4Exec") //nolint:staticcheck // OT is deprecated
 	defer func() {
@@ -743,6 +1034,48 @@ func (c *clientImplementor) BatchLog(ctx context.Context, opts BatchLogOptions,
 			})
 		}()
 
+		if c.grpcEnabled() {
+			conn, err := grpcConnForAddr(addr)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			request := protocol.BatchLogRequest{
+				RepoCommits: repoCommits,
+				Format:      opts.Format,
+			}
+			stream, err := proto.NewGitserverServiceClient(conn).BatchLog(ctx, request.ToProto())
+			if err != nil {
+				return err
+			}
+
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+
+				var result protocol.BatchLogResult
+				result.FromProto(resp)
+
+				var cmdErr error
+				if result.CommandError != "" {
+					cmdErr = errors.New(result.CommandError)
+				}
+				if err := callback(result.RepoCommit, RawBatchLogResult{Stdout: result.CommandOutput, Error: cmdErr}); err != nil {
+					return errors.Wrap(err, "commitLogCallback")
+				}
+
+				numProcessed++
+			}
+		}
+
 		uri := "http://" + addr + "/batch-log"
 		repoName := api.RepoName(strings.Join(repoNames, ",")) // only used to label spans
 
@@ -876,11 +1209,21 @@ func (c *clientImplementor) gitCommand(repo api.RepoName, arg ...string) GitComm
 		}
 		return cmd
 	}
-	return &RemoteGitCommand{
+	readOnly := gitdomain.IsReadOnlyGitCmd(arg)
+	cmd := &RemoteGitCommand{
 		repo:   repo,
 		execFn: c.httpPost,
 		args:   append([]string{git}, arg...),
 	}
+	if readOnly {
+		cmd.execFn = c.httpPostRead
+	}
+	if c.grpcEnabled() {
+		cmd.execGRPCFn = func(ctx context.Context, repo api.RepoName, req *proto.ExecRequest) (*execStreamReader, error) {
+			return c.execGRPC(ctx, repo, req, readOnly)
+		}
+	}
+	return cmd
 }
 
 func (c *clientImplementor) RequestRepoUpdate(ctx context.Context, repo api.RepoName, since time.Duration) (*protocol.RepoUpdateResponse, error) {
@@ -906,6 +1249,32 @@ func (c *clientImplementor) RequestRepoUpdate(ctx context.Context, repo api.Repo
 	return info, err
 }
 
+func (c *clientImplementor) RequestRepoMigrate(ctx context.Context, repo api.RepoName, from, to string) (*protocol.RepoUpdateResponse, error) {
+	b, err := json.Marshal(&protocol.RepoUpdateRequest{
+		Repo:           repo,
+		CloneFromShard: "http://" + from,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(ctx, repo, "POST", "http://"+to+"/repo-update", b)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &url.Error{
+			URL: resp.Request.URL.String(),
+			Op:  "RepoMigrate",
+			Err: errors.Errorf("RepoMigrate: http status %d: %s", resp.StatusCode, readResponseBody(io.LimitReader(resp.Body, 200))),
+		}
+	}
+
+	var info *protocol.RepoUpdateResponse
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	return info, err
+}
+
 // RequestRepoClone requests that the gitserver does an asynchronous clone of the repository.
 func (c *clientImplementor) RequestRepoClone(ctx context.Context, repo api.RepoName) (*protocol.RepoCloneResponse, error) {
 	req := &protocol.RepoCloneRequest{
@@ -1147,6 +1516,33 @@ func (c *clientImplementor) httpPost(ctx context.Context, repo api.RepoName, op
 	return c.do(ctx, repo, "POST", uri, b)
 }
 
+// httpPostRead is like httpPost, but for read-only requests which may be served
+// by a replica of repo. If the replica responds that it has not cloned repo yet,
+// or lacks the revision the request ensures, the request is sent to the owner of
+// repo instead.
+func (c *clientImplementor) httpPostRead(ctx context.Context, repo api.RepoName, op string, payload any) (resp *http.Response, err error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := c.readAddrsForRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs[:len(addrs)-1] {
+		resp, err := c.do(ctx, repo, "POST", "http://"+addr+"/"+op, b)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusNotFound {
+			return resp, nil
+		}
+		resp.Body.Close()
+	}
+	return c.do(ctx, repo, "POST", "http://"+addrs[len(addrs)-1]+"/"+op, b)
+}
+
 // do performs a request to a gitserver instance based on the address in the uri
 // argument.
 //
@@ -1356,6 +1752,14 @@ func readResponseBody(body io.Reader) string {
 	return strings.TrimSpace(string(content))
 }
 
+func replicatedReposFromConfig() map[string]int {
+	cfg := conf.Get()
+	if cfg.ExperimentalFeatures != nil {
+		return cfg.ExperimentalFeatures.GitServerReplicatedRepos
+	}
+	return nil
+}
+
 func pinnedReposFromConfig() map[string]string {
 	cfg := conf.Get()
 	if cfg.ExperimentalFeatures != nil && cfg.ExperimentalFeatures.GitServerPinnedRepos != nil {
@@ -30,6 +30,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
@@ -324,6 +325,125 @@ func TestAddrForRepo(t *testing.T) {
 	}
 }
 
+func TestAddrForRepo_Rendezvous(t *testing.T) {
+	addrs := []string{"gitserver-1", "gitserver-2", "gitserver-3"}
+	pinned := map[string]string{
+		"repo2": "gitserver-1",
+	}
+
+	testCases := []struct {
+		name string
+		repo api.RepoName
+		want string
+	}{
+		{
+			name: "repo1",
+			repo: api.RepoName("repo1"),
+			want: "gitserver-1",
+		},
+		{
+			name: "repo3",
+			repo: api.RepoName("repo3"),
+			want: "gitserver-3",
+		},
+		{
+			name: "pinned repo", // different server address that the hashing function would normally yield
+			repo: api.RepoName("repo2"),
+			want: "gitserver-1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := gitserver.AddrForRepo(context.Background(), "gitserver", tc.repo, gitserver.GitServerAddresses{
+				Addresses:     addrs,
+				PinnedServers: pinned,
+				Sharding:      gitserver.ShardingRendezvous,
+			})
+			if err != nil {
+				t.Fatal("Error during getting gitserver address")
+			}
+			if got != tc.want {
+				t.Fatalf("Want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestNewGitServerAddresses(t *testing.T) {
+	live := []string{"gitserver-1", "gitserver-2", "gitserver-3"}
+	pinned := map[string]string{"repo": "gitserver-1"}
+
+	testCases := []struct {
+		name    string
+		routing *conftypes.GitServerRouting
+		want    gitserver.GitServerAddresses
+	}{
+		{
+			name: "no routing",
+			want: gitserver.GitServerAddresses{
+				Addresses:     live,
+				PinnedServers: pinned,
+				Sharding:      gitserver.ShardingModulo,
+			},
+		},
+		{
+			name: "routing drops addresses that are not live",
+			routing: &conftypes.GitServerRouting{
+				Addresses: []string{"gitserver-2", "gitserver-0", "gitserver-1"},
+				Sharding:  gitserver.ShardingRendezvous,
+			},
+			want: gitserver.GitServerAddresses{
+				Addresses:     []string{"gitserver-2", "gitserver-1"},
+				PinnedServers: pinned,
+				Sharding:      gitserver.ShardingRendezvous,
+			},
+		},
+		{
+			name: "no live addresses in routing",
+			routing: &conftypes.GitServerRouting{
+				Addresses: []string{"gitserver-0"},
+				Sharding:  gitserver.ShardingModulo,
+			},
+			want: gitserver.GitServerAddresses{
+				Addresses:     live,
+				PinnedServers: pinned,
+				Sharding:      gitserver.ShardingModulo,
+			},
+		},
+		{
+			name: "rebalance in progress",
+			routing: &conftypes.GitServerRouting{
+				Addresses: []string{"gitserver-1", "gitserver-2"},
+				Sharding:  gitserver.ShardingModulo,
+				Next: &conftypes.GitServerRouting{
+					Addresses: live,
+					Sharding:  gitserver.ShardingRendezvous,
+				},
+			},
+			want: gitserver.GitServerAddresses{
+				Addresses:     []string{"gitserver-1", "gitserver-2"},
+				PinnedServers: pinned,
+				Sharding:      gitserver.ShardingModulo,
+				Next: &gitserver.GitServerAddresses{
+					Addresses:     live,
+					PinnedServers: pinned,
+					Sharding:      gitserver.ShardingRendezvous,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := gitserver.NewGitServerAddresses(live, tc.routing, pinned)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected addresses (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestClient_P4Exec(t *testing.T) {
 	_ = gitserver.CreateRepoDir(t)
 	tests := []struct {
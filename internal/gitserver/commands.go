@@ -34,6 +34,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/fileutil"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/proto"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
@@ -2444,16 +2445,28 @@ func (c *clientImplementor) ArchiveReader(
 		return nil, err
 	}
 
-	u, err := c.archiveURL(ctx, repo, options)
-	if err != nil {
-		return nil, err
+	if c.grpcEnabled() {
+		return c.archiveReaderGRPC(ctx, repo, options)
 	}
 
-	resp, err := c.do(ctx, repo, "POST", u.String(), nil)
+	addrs, err := c.readAddrsForRepo(ctx, repo)
 	if err != nil {
 		return nil, err
 	}
 
+	// Fall back to the next address if a replica has not cloned the repo yet.
+	var resp *http.Response
+	for i, addr := range addrs {
+		resp, err = c.do(ctx, repo, "POST", archiveURL(addr, repo, options).String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusNotFound || i == len(addrs)-1 {
+			break
+		}
+		resp.Body.Close()
+	}
+
 	switch resp.StatusCode {
 	case http.StatusOK:
 		return &archiveReader{
@@ -2484,6 +2497,48 @@ func (c *clientImplementor) ArchiveReader(
 	}
 }
 
+func (c *clientImplementor) archiveReaderGRPC(ctx context.Context, repo api.RepoName, options ArchiveOptions) (io.ReadCloser, error) {
+	addrs, err := c.readAddrsForRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	pathspecs := make([]string, len(options.Pathspecs))
+	for i, p := range options.Pathspecs {
+		pathspecs[i] = string(p)
+	}
+	req := &proto.ArchiveRequest{
+		Repo:      string(repo),
+		Treeish:   options.Treeish,
+		Format:    string(options.Format),
+		Pathspecs: pathspecs,
+	}
+
+	r, err := openExecStream(ctx, addrs, repo, func(ctx context.Context, client proto.GitserverServiceClient) (func() (*proto.ExecResponse, error), error) {
+		stream, err := client.Archive(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return stream.Recv, nil
+	})
+	if err != nil {
+		var notExist *gitdomain.RepoNotExistError
+		if errors.As(err, &notExist) {
+			return nil, &badRequestError{error: err}
+		}
+		return nil, err
+	}
+
+	return &archiveReader{
+		base: &cmdReader{
+			rc:      r,
+			trailer: r.trailer,
+		},
+		repo: repo,
+		spec: options.Treeish,
+	}, nil
+}
+
 func addNameOnly(opt CommitsOptions, checker authz.SubRepoPermissionChecker) CommitsOptions {
 	if authz.SubRepoEnabled(checker) {
 		// If sub-repo permissions enabled, must fetch files modified w/ commits to determine if user has access to view this commit
@@ -16,6 +16,7 @@ import (
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/proto"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -169,6 +170,9 @@ type RemoteGitCommand struct {
 	noTimeout      bool
 	exitStatus     int
 	execFn         func(ctx context.Context, repo api.RepoName, op string, payload any) (resp *http.Response, err error)
+	// execGRPCFn, if set, is used to run the command over gRPC instead of
+	// execFn.
+	execGRPCFn func(ctx context.Context, repo api.RepoName, req *proto.ExecRequest) (*execStreamReader, error)
 }
 
 // DividedOutput runs the command and returns its standard output and standard error.
@@ -75,6 +75,16 @@ var (
 	}
 )
 
+// gitWriteCmds are the allowed commands that modify the repository they run in.
+var gitWriteCmds = map[string]struct{}{
+	"apply":      {},
+	"commit":     {},
+	"init":       {},
+	"push":       {},
+	"reset":      {},
+	"update-ref": {},
+}
+
 var gitObjectHashRegex = regexp.MustCompile(`^[a-fA-F\d]*$`)
 
 // common revs used with diff
@@ -178,3 +188,13 @@ func IsAllowedGitCmd(logger log.Logger, args []string) bool {
 	}
 	return true
 }
+
+// IsReadOnlyGitCmd returns true if the git command with the given args does not
+// modify the repository, so that it can be run against a replica of it.
+func IsReadOnlyGitCmd(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	_, ok := gitWriteCmds[args[0]]
+	return !ok
+}
@@ -62,3 +62,21 @@ func TestIsAllowedDiffGitCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestIsReadOnlyGitCmd(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want bool
+	}{
+		{args: []string{"log", "-n1"}, want: true},
+		{args: []string{"rev-parse", "HEAD"}, want: true},
+		{args: []string{"archive", "--format=zip", "HEAD"}, want: true},
+		{args: []string{"commit", "-m", "message"}, want: false},
+		{args: []string{"update-ref", "--"}, want: false},
+		{args: nil, want: false},
+	} {
+		if got := IsReadOnlyGitCmd(tc.args); got != tc.want {
+			t.Errorf("IsReadOnlyGitCmd(%q) = %v, want %v", tc.args, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package gitserver
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/proto"
+	"github.com/sourcegraph/sourcegraph/internal/grpc/defaults"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// grpcEnabledFromConfig reports whether the site configuration switches the
+// gitserver client over to gRPC.
+func grpcEnabledFromConfig() bool {
+	return conf.ExperimentalFeatures().EnableGitServerGRPC
+}
+
+// grpcEnabled reports whether c talks to gitserver over gRPC instead of HTTP.
+func (c *clientImplementor) grpcEnabled() bool {
+	return c.useGRPC != nil && c.useGRPC()
+}
+
+// grpcConns caches a client connection per gitserver address. Connections are
+// safe for concurrent use and multiplex requests, so they are shared by all
+// clients in the process.
+var grpcConns = struct {
+	sync.Mutex
+	conns map[string]*grpc.ClientConn
+}{conns: map[string]*grpc.ClientConn{}}
+
+// grpcConnForAddr returns the client connection to the gitserver at addr.
+func grpcConnForAddr(addr string) (*grpc.ClientConn, error) {
+	grpcConns.Lock()
+	defer grpcConns.Unlock()
+
+	if conn, ok := grpcConns.conns[addr]; ok {
+		return conn, nil
+	}
+
+	opts := []grpc.DialOption{
+		// 🚨 SECURITY: We use insecure connections to gitserver, the same way
+		// the HTTP API is served over plain HTTP.
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		// Use the binary name for the user agent, like the HTTP client does.
+		grpc.WithUserAgent(filepath.Base(os.Args[0])),
+		// Exec output is streamed in chunks, but a single search or batch log
+		// message can still be large.
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(math.MaxInt32)),
+	}
+	opts = append(opts, defaults.DialOptions()...)
+
+	// Dial does not block, the connection is established on first use.
+	conn, err := grpc.Dial(addr, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "dialing gitserver %q", addr)
+	}
+	grpcConns.conns[addr] = conn
+	return conn, nil
+}
+
+// openExecStream opens an Exec or Archive stream with open on the first of
+// addrs. If that gitserver has not cloned repo, the next address is tried.
+func openExecStream(
+	ctx context.Context,
+	addrs []string,
+	repo api.RepoName,
+	open func(context.Context, proto.GitserverServiceClient) (func() (*proto.ExecResponse, error), error),
+) (*execStreamReader, error) {
+	for i, addr := range addrs {
+		conn, err := grpcConnForAddr(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		streamCtx, cancel := context.WithCancel(ctx)
+		recv, err := open(streamCtx, proto.NewGitserverServiceClient(conn))
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		r, err := newExecStreamReader(recv, cancel, repo)
+		var notExist *gitdomain.RepoNotExistError
+		if errors.As(err, &notExist) && i < len(addrs)-1 {
+			continue
+		}
+		return r, err
+	}
+	return nil, errors.New("no gitserver address")
+}
+
+// execGRPC runs the command of req on gitserver. Read-only commands may be run
+// on a replica of repo.
+func (c *clientImplementor) execGRPC(ctx context.Context, repo api.RepoName, req *proto.ExecRequest, readOnly bool) (*execStreamReader, error) {
+	addrs, err := c.addrsForRequest(ctx, repo, readOnly)
+	if err != nil {
+		return nil, err
+	}
+	return openExecStream(ctx, addrs, repo, func(ctx context.Context, client proto.GitserverServiceClient) (func() (*proto.ExecResponse, error), error) {
+		stream, err := client.Exec(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return stream.Recv, nil
+	})
+}
+
+// execStreamReader reads the output of an Exec or Archive stream. Once the
+// final status message has been received, trailer holds the same X-Exec-*
+// values the HTTP API sends as trailers.
+type execStreamReader struct {
+	recv    func() (*proto.ExecResponse, error)
+	cancel  context.CancelFunc
+	trailer http.Header
+
+	buf  []byte
+	done bool
+}
+
+// newExecStreamReader returns a reader for the messages returned by recv. It receives
+// the first message eagerly, so that errors which happen before the command
+// runs, like the repository not being cloned, are returned here.
+func newExecStreamReader(recv func() (*proto.ExecResponse, error), cancel context.CancelFunc, repo api.RepoName) (*execStreamReader, error) {
+	r := &execStreamReader{
+		recv:    recv,
+		cancel:  cancel,
+		trailer: http.Header{},
+	}
+
+	first, err := r.recv()
+	if err != nil {
+		cancel()
+		return nil, convertExecError(err, repo)
+	}
+	r.handle(first)
+	return r, nil
+}
+
+func (r *execStreamReader) handle(msg *proto.ExecResponse) {
+	switch m := msg.GetMessage().(type) {
+	case *proto.ExecResponse_Data:
+		r.buf = m.Data
+	case *proto.ExecResponse_Status:
+		r.trailer.Set("X-Exec-Error", m.Status.GetError())
+		r.trailer.Set("X-Exec-Exit-Status", strconv.Itoa(int(m.Status.GetExitStatus())))
+		r.trailer.Set("X-Exec-Stderr", m.Status.GetStderr())
+		r.done = true
+	}
+}
+
+func (r *execStreamReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		msg, err := r.recv()
+		if err == io.EOF {
+			return 0, errors.New("exec stream ended without a status")
+		}
+		if err != nil {
+			return 0, err
+		}
+		r.handle(msg)
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *execStreamReader) Close() error {
+	r.cancel()
+	return nil
+}
+
+// convertExecError converts the NotFound status returned by Exec and Archive
+// to a gitdomain.RepoNotExistError.
+func convertExecError(err error, repo api.RepoName) error {
+	s, ok := status.FromError(err)
+	if !ok || s.Code() != codes.NotFound {
+		return err
+	}
+	for _, d := range s.Details() {
+		if payload, ok := d.(*proto.NotFoundPayload); ok {
+			return &gitdomain.RepoNotExistError{
+				Repo:            repo,
+				CloneInProgress: payload.GetCloneInProgress(),
+				CloneProgress:   payload.GetCloneProgress(),
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,95 @@
+package gitserver
+
+import (
+	"io"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/proto"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func recvFrom(msgs []*proto.ExecResponse, err error) func() (*proto.ExecResponse, error) {
+	return func() (*proto.ExecResponse, error) {
+		if len(msgs) == 0 {
+			return nil, err
+		}
+		msg := msgs[0]
+		msgs = msgs[1:]
+		return msg, nil
+	}
+}
+
+func TestExecStreamReader(t *testing.T) {
+	data := func(s string) *proto.ExecResponse {
+		return &proto.ExecResponse{Message: &proto.ExecResponse_Data{Data: []byte(s)}}
+	}
+	execStatus := func(exitStatus int32, stderr string) *proto.ExecResponse {
+		return &proto.ExecResponse{Message: &proto.ExecResponse_Status{Status: &proto.ExecStatus{ExitStatus: exitStatus, Stderr: stderr}}}
+	}
+
+	t.Run("output and status", func(t *testing.T) {
+		r, err := newExecStreamReader(recvFrom([]*proto.ExecResponse{data("foo"), data(""), data("bar"), execStatus(0, "")}, io.EOF), func() {}, "repo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := io.ReadAll(&cmdReader{rc: r, trailer: r.trailer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != "foobar" {
+			t.Fatalf("got output %q, want %q", out, "foobar")
+		}
+	})
+
+	t.Run("non-zero exit status", func(t *testing.T) {
+		r, err := newExecStreamReader(recvFrom([]*proto.ExecResponse{data("foo"), execStatus(1, "oops")}, io.EOF), func() {}, "repo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = io.ReadAll(&cmdReader{rc: r, trailer: r.trailer})
+		if err == nil || err.Error() != `non-zero exit status: 1 (stderr: "oops")` {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("missing status", func(t *testing.T) {
+		r, err := newExecStreamReader(recvFrom([]*proto.ExecResponse{data("foo")}, io.EOF), func() {}, "repo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = io.ReadAll(r); err == nil {
+			t.Fatal("want error for stream without status")
+		}
+	})
+
+	t.Run("repo not found", func(t *testing.T) {
+		s, err := status.New(codes.NotFound, "repo not found").WithDetails(&proto.NotFoundPayload{
+			Repo:            "repo",
+			CloneInProgress: true,
+			CloneProgress:   "cloning",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		canceled := false
+		_, err = newExecStreamReader(recvFrom(nil, s.Err()), func() { canceled = true }, "repo")
+		var notExist *gitdomain.RepoNotExistError
+		if !errors.As(err, &notExist) {
+			t.Fatalf("want RepoNotExistError, got %v", err)
+		}
+		if !notExist.CloneInProgress || notExist.CloneProgress != "cloning" {
+			t.Fatalf("unexpected error %+v", notExist)
+		}
+		if !canceled {
+			t.Fatal("want stream to be canceled")
+		}
+	})
+}
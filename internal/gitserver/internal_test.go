@@ -2,10 +2,13 @@ package gitserver
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
 )
 
 func BenchmarkAddrForKey(b *testing.B) {
@@ -23,6 +26,125 @@ func BenchmarkAddrForKey(b *testing.B) {
 	}
 }
 
+func BenchmarkRendezvousAddrForKey(b *testing.B) {
+	for _, count := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("Count-%d", count), func(b *testing.B) {
+			var nodes []string
+			for i := 0; i < count; i++ {
+				nodes = append(nodes, fmt.Sprintf("Node%d", i))
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rendezvousAddrForKey("foo", nodes)
+			}
+		})
+	}
+}
+
+func TestRendezvousAddrForKey(t *testing.T) {
+	before := []string{"gitserver-0", "gitserver-1", "gitserver-2"}
+	after := append(append([]string{}, before...), "gitserver-3")
+
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("github.com/sourcegraph/repo-%d", i)
+		from, to := rendezvousAddrForKey(key, before), rendezvousAddrForKey(key, after)
+		if from == to {
+			continue
+		}
+		if to != "gitserver-3" {
+			t.Fatalf("repo %q moved from %q to %q, want only moves to the new shard", key, from, to)
+		}
+		moved++
+	}
+
+	// The new shard should own roughly a quarter of the repositories.
+	if moved < 200 || moved > 300 {
+		t.Fatalf("%d of 1000 repositories moved, want about 250", moved)
+	}
+
+	// The order of addresses does not matter.
+	reversed := []string{"gitserver-3", "gitserver-2", "gitserver-1", "gitserver-0"}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("github.com/sourcegraph/repo-%d", i)
+		if a, b := rendezvousAddrForKey(key, after), rendezvousAddrForKey(key, reversed); a != b {
+			t.Fatalf("repo %q is assigned to %q and %q depending on the order of addresses", key, a, b)
+		}
+	}
+}
+
+func TestReplicationFactor(t *testing.T) {
+	replicated := map[string]int{
+		"github.com/sourcegraph/monorepo": 3,
+		"github.com/sourcegraph/*":        2,
+		"github.com/sourcegraph/mono*":    4,
+	}
+	for repo, want := range map[string]int{
+		"github.com/sourcegraph/monorepo":      3,
+		"github.com/sourcegraph/sourcegraph":   2,
+		"github.com/sourcegraph/monolith":      4,
+		"github.com/sourcegraph/nested/repo":   1,
+		"github.com/gorilla/mux":               1,
+		"gitlab.com/sourcegraph/sourcegraph":   1,
+		"github.com/sourcegraph/monorepo-fork": 4,
+	} {
+		if got := ReplicationFactor(api.RepoName(repo), replicated); got != want {
+			t.Errorf("ReplicationFactor(%q) = %d, want %d", repo, got, want)
+		}
+	}
+}
+
+func TestReplicaAddrsForRepo(t *testing.T) {
+	ctx := context.Background()
+	addresses := GitServerAddresses{
+		Addresses:       []string{"gitserver-0", "gitserver-1", "gitserver-2", "gitserver-3"},
+		PinnedServers:   map[string]string{"github.com/sourcegraph/pinned": "gitserver-1"},
+		Sharding:        ShardingRendezvous,
+		ReplicatedRepos: map[string]int{"github.com/sourcegraph/*": 3, "github.com/sourcegraph/all": 10},
+	}
+
+	for i := 0; i < 100; i++ {
+		repo := api.RepoName(fmt.Sprintf("github.com/sourcegraph/repo-%d", i))
+		replicas, err := ReplicaAddrsForRepo(ctx, "test", repo, addresses)
+		if err != nil {
+			t.Fatal(err)
+		}
+		primary, _ := AddrForRepo(ctx, "test", repo, addresses)
+		if len(replicas) != 3 || replicas[0] != primary {
+			t.Fatalf("unexpected replicas %q of repo %q owned by %q", replicas, repo, primary)
+		}
+		seen := map[string]bool{}
+		for _, addr := range replicas {
+			if seen[addr] {
+				t.Fatalf("duplicate replica %q of repo %q", addr, repo)
+			}
+			seen[addr] = true
+		}
+	}
+
+	for repo, want := range map[api.RepoName][]string{
+		"github.com/sourcegraph/pinned": {"gitserver-1"},
+		"github.com/gorilla/mux":        {rendezvousAddrForKey("github.com/gorilla/mux", addresses.Addresses)},
+	} {
+		replicas, err := ReplicaAddrsForRepo(ctx, "test", repo, addresses)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, replicas); diff != "" {
+			t.Fatalf("unexpected replicas of %q (-want +got):\n%s", repo, diff)
+		}
+	}
+
+	// The replication factor is capped by the number of addresses.
+	replicas, err := ReplicaAddrsForRepo(ctx, "test", "github.com/sourcegraph/all", addresses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replicas) != len(addresses.Addresses) {
+		t.Fatalf("got %d replicas, want %d", len(replicas), len(addresses.Addresses))
+	}
+}
+
 func Test_readResponseBody(t *testing.T) {
 	// The \n in the end is important to test that readResponseBody correctly removes it from the returned string.
 	reader := bytes.NewReader([]byte("A test string that is more than 40 bytes long. Lorem ipsum whatever whatever\n"))
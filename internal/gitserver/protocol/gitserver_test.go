@@ -0,0 +1,24 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestSearchEventDoneErr(t *testing.T) {
+	notExist := &gitdomain.RepoNotExistError{Repo: "github.com/sourcegraph/sourcegraph", CloneInProgress: true}
+	var got *gitdomain.RepoNotExistError
+	if err := NewSearchEventDone(false, notExist).Err(); !errors.As(err, &got) || *got != *notExist {
+		t.Fatalf("want RepoNotExistError, got %v", err)
+	}
+
+	if err := NewSearchEventDone(false, errors.New("boom")).Err(); err == nil || errors.As(err, &got) || err.Error() != "boom" {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err := NewSearchEventDone(true, nil).Err(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
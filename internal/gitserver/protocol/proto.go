@@ -0,0 +1,353 @@
+package protocol
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/proto"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func (r *ExecRequest) ToProto() *proto.ExecRequest {
+	return &proto.ExecRequest{
+		Repo:           string(r.Repo),
+		EnsureRevision: r.EnsureRevision,
+		Args:           r.Args,
+		Stdin:          r.Stdin,
+		NoTimeout:      r.NoTimeout,
+	}
+}
+
+func (r *ExecRequest) FromProto(p *proto.ExecRequest) {
+	*r = ExecRequest{
+		Repo:           api.RepoName(p.GetRepo()),
+		EnsureRevision: p.GetEnsureRevision(),
+		Args:           p.GetArgs(),
+		Stdin:          p.GetStdin(),
+		NoTimeout:      p.GetNoTimeout(),
+	}
+}
+
+func (r *SearchRequest) ToProto() *proto.SearchRequest {
+	revisions := make([]*proto.RevisionSpecifier, len(r.Revisions))
+	for i, rev := range r.Revisions {
+		revisions[i] = &proto.RevisionSpecifier{
+			RevSpec:        rev.RevSpec,
+			RefGlob:        rev.RefGlob,
+			ExcludeRefGlob: rev.ExcludeRefGlob,
+		}
+	}
+	return &proto.SearchRequest{
+		Repo:                 string(r.Repo),
+		Revisions:            revisions,
+		Query:                NodeToProto(r.Query),
+		IncludeDiff:          r.IncludeDiff,
+		Limit:                int64(r.Limit),
+		IncludeModifiedFiles: r.IncludeModifiedFiles,
+	}
+}
+
+func (r *SearchRequest) FromProto(p *proto.SearchRequest) error {
+	query, err := NodeFromProto(p.GetQuery())
+	if err != nil {
+		return err
+	}
+
+	revisions := make([]RevisionSpecifier, len(p.GetRevisions()))
+	for i, rev := range p.GetRevisions() {
+		revisions[i] = RevisionSpecifier{
+			RevSpec:        rev.GetRevSpec(),
+			RefGlob:        rev.GetRefGlob(),
+			ExcludeRefGlob: rev.GetExcludeRefGlob(),
+		}
+	}
+
+	*r = SearchRequest{
+		Repo:                 api.RepoName(p.GetRepo()),
+		Revisions:            revisions,
+		Query:                query,
+		IncludeDiff:          p.GetIncludeDiff(),
+		Limit:                int(p.GetLimit()),
+		IncludeModifiedFiles: p.GetIncludeModifiedFiles(),
+	}
+	return nil
+}
+
+// NodeToProto converts a search query to its protobuf representation.
+func NodeToProto(n Node) *proto.QueryNode {
+	switch v := n.(type) {
+	case *AuthorMatches:
+		return &proto.QueryNode{Value: &proto.QueryNode_AuthorMatches{
+			AuthorMatches: &proto.PatternNode{Expr: v.Expr, IgnoreCase: v.IgnoreCase},
+		}}
+	case *CommitterMatches:
+		return &proto.QueryNode{Value: &proto.QueryNode_CommitterMatches{
+			CommitterMatches: &proto.PatternNode{Expr: v.Expr, IgnoreCase: v.IgnoreCase},
+		}}
+	case *CommitBefore:
+		return &proto.QueryNode{Value: &proto.QueryNode_CommitBefore{
+			CommitBefore: timestamppb.New(v.Time),
+		}}
+	case *CommitAfter:
+		return &proto.QueryNode{Value: &proto.QueryNode_CommitAfter{
+			CommitAfter: timestamppb.New(v.Time),
+		}}
+	case *MessageMatches:
+		return &proto.QueryNode{Value: &proto.QueryNode_MessageMatches{
+			MessageMatches: &proto.PatternNode{Expr: v.Expr, IgnoreCase: v.IgnoreCase},
+		}}
+	case *DiffMatches:
+		return &proto.QueryNode{Value: &proto.QueryNode_DiffMatches{
+			DiffMatches: &proto.PatternNode{Expr: v.Expr, IgnoreCase: v.IgnoreCase},
+		}}
+	case *DiffModifiesFile:
+		return &proto.QueryNode{Value: &proto.QueryNode_DiffModifiesFile{
+			DiffModifiesFile: &proto.PatternNode{Expr: v.Expr, IgnoreCase: v.IgnoreCase},
+		}}
+	case *Boolean:
+		return &proto.QueryNode{Value: &proto.QueryNode_Boolean{Boolean: v.Value}}
+	case Boolean:
+		return &proto.QueryNode{Value: &proto.QueryNode_Boolean{Boolean: v.Value}}
+	case *Operator:
+		operands := make([]*proto.QueryNode, len(v.Operands))
+		for i, operand := range v.Operands {
+			operands[i] = NodeToProto(operand)
+		}
+		return &proto.QueryNode{Value: &proto.QueryNode_Operator{
+			Operator: &proto.OperatorNode{
+				Kind:     proto.OperatorKind(v.Kind),
+				Operands: operands,
+			},
+		}}
+	default:
+		return nil
+	}
+}
+
+// NodeFromProto converts the protobuf representation of a search query back to
+// the query.
+func NodeFromProto(p *proto.QueryNode) (Node, error) {
+	switch v := p.GetValue().(type) {
+	case *proto.QueryNode_AuthorMatches:
+		return &AuthorMatches{Expr: v.AuthorMatches.GetExpr(), IgnoreCase: v.AuthorMatches.GetIgnoreCase()}, nil
+	case *proto.QueryNode_CommitterMatches:
+		return &CommitterMatches{Expr: v.CommitterMatches.GetExpr(), IgnoreCase: v.CommitterMatches.GetIgnoreCase()}, nil
+	case *proto.QueryNode_CommitBefore:
+		return &CommitBefore{Time: v.CommitBefore.AsTime()}, nil
+	case *proto.QueryNode_CommitAfter:
+		return &CommitAfter{Time: v.CommitAfter.AsTime()}, nil
+	case *proto.QueryNode_MessageMatches:
+		return &MessageMatches{Expr: v.MessageMatches.GetExpr(), IgnoreCase: v.MessageMatches.GetIgnoreCase()}, nil
+	case *proto.QueryNode_DiffMatches:
+		return &DiffMatches{Expr: v.DiffMatches.GetExpr(), IgnoreCase: v.DiffMatches.GetIgnoreCase()}, nil
+	case *proto.QueryNode_DiffModifiesFile:
+		return &DiffModifiesFile{Expr: v.DiffModifiesFile.GetExpr(), IgnoreCase: v.DiffModifiesFile.GetIgnoreCase()}, nil
+	case *proto.QueryNode_Boolean:
+		return &Boolean{Value: v.Boolean}, nil
+	case *proto.QueryNode_Operator:
+		operands := make([]Node, len(v.Operator.GetOperands()))
+		for i, operand := range v.Operator.GetOperands() {
+			node, err := NodeFromProto(operand)
+			if err != nil {
+				return nil, err
+			}
+			operands[i] = node
+		}
+		return &Operator{
+			Kind:     OperatorKind(v.Operator.GetKind()),
+			Operands: operands,
+		}, nil
+	default:
+		return nil, errors.Newf("unknown query node type %T", v)
+	}
+}
+
+func (m *CommitMatch) ToProto() *proto.CommitMatch {
+	return &proto.CommitMatch{
+		Oid:           string(m.Oid),
+		Author:        m.Author.ToProto(),
+		Committer:     m.Committer.ToProto(),
+		Parents:       commitIDsToStrings(m.Parents),
+		Refs:          m.Refs,
+		SourceRefs:    m.SourceRefs,
+		Message:       matchedStringToProto(m.Message),
+		Diff:          matchedStringToProto(m.Diff),
+		ModifiedFiles: m.ModifiedFiles,
+	}
+}
+
+func (m *CommitMatch) FromProto(p *proto.CommitMatch) {
+	var author, committer Signature
+	author.FromProto(p.GetAuthor())
+	committer.FromProto(p.GetCommitter())
+
+	*m = CommitMatch{
+		Oid:           api.CommitID(p.GetOid()),
+		Author:        author,
+		Committer:     committer,
+		Parents:       stringsToCommitIDs(p.GetParents()),
+		Refs:          p.GetRefs(),
+		SourceRefs:    p.GetSourceRefs(),
+		Message:       matchedStringFromProto(p.GetMessage()),
+		Diff:          matchedStringFromProto(p.GetDiff()),
+		ModifiedFiles: p.GetModifiedFiles(),
+	}
+}
+
+func (s *Signature) ToProto() *proto.Signature {
+	return &proto.Signature{
+		Name:  s.Name,
+		Email: s.Email,
+		Date:  timestamppb.New(s.Date),
+	}
+}
+
+func (s *Signature) FromProto(p *proto.Signature) {
+	var date time.Time
+	if p.GetDate() != nil {
+		date = p.GetDate().AsTime()
+	}
+	*s = Signature{
+		Name:  p.GetName(),
+		Email: p.GetEmail(),
+		Date:  date,
+	}
+}
+
+func matchedStringToProto(s result.MatchedString) *proto.MatchedString {
+	ranges := make([]*proto.Range, len(s.MatchedRanges))
+	for i, r := range s.MatchedRanges {
+		ranges[i] = &proto.Range{
+			Start: locationToProto(r.Start),
+			End:   locationToProto(r.End),
+		}
+	}
+	return &proto.MatchedString{
+		Content:       s.Content,
+		MatchedRanges: ranges,
+	}
+}
+
+func matchedStringFromProto(p *proto.MatchedString) result.MatchedString {
+	var ranges result.Ranges
+	if len(p.GetMatchedRanges()) > 0 {
+		ranges = make(result.Ranges, len(p.GetMatchedRanges()))
+		for i, r := range p.GetMatchedRanges() {
+			ranges[i] = result.Range{
+				Start: locationFromProto(r.GetStart()),
+				End:   locationFromProto(r.GetEnd()),
+			}
+		}
+	}
+	return result.MatchedString{
+		Content:       p.GetContent(),
+		MatchedRanges: ranges,
+	}
+}
+
+func locationToProto(l result.Location) *proto.Location {
+	return &proto.Location{
+		Offset: uint32(l.Offset),
+		Line:   uint32(l.Line),
+		Column: uint32(l.Column),
+	}
+}
+
+func locationFromProto(p *proto.Location) result.Location {
+	return result.Location{
+		Offset: int(p.GetOffset()),
+		Line:   int(p.GetLine()),
+		Column: int(p.GetColumn()),
+	}
+}
+
+func (e SearchEventDone) ToProto() *proto.SearchDone {
+	return &proto.SearchDone{
+		LimitHit: e.LimitHit,
+		Error:    e.Error,
+	}
+}
+
+func (e *SearchEventDone) FromProto(p *proto.SearchDone) {
+	*e = SearchEventDone{
+		LimitHit: p.GetLimitHit(),
+		Error:    p.GetError(),
+	}
+}
+
+func (r *BatchLogRequest) ToProto() *proto.BatchLogRequest {
+	repoCommits := make([]*proto.RepoCommit, len(r.RepoCommits))
+	for i, rc := range r.RepoCommits {
+		repoCommits[i] = repoCommitToProto(rc)
+	}
+	return &proto.BatchLogRequest{
+		RepoCommits: repoCommits,
+		Format:      r.Format,
+	}
+}
+
+func (r *BatchLogRequest) FromProto(p *proto.BatchLogRequest) {
+	repoCommits := make([]api.RepoCommit, len(p.GetRepoCommits()))
+	for i, rc := range p.GetRepoCommits() {
+		repoCommits[i] = repoCommitFromProto(rc)
+	}
+	*r = BatchLogRequest{
+		RepoCommits: repoCommits,
+		Format:      p.GetFormat(),
+	}
+}
+
+func (r *BatchLogResult) ToProto() *proto.BatchLogResponse {
+	return &proto.BatchLogResponse{
+		RepoCommit:    repoCommitToProto(r.RepoCommit),
+		CommandOutput: r.CommandOutput,
+		CommandError:  r.CommandError,
+	}
+}
+
+func (r *BatchLogResult) FromProto(p *proto.BatchLogResponse) {
+	*r = BatchLogResult{
+		RepoCommit:    repoCommitFromProto(p.GetRepoCommit()),
+		CommandOutput: p.GetCommandOutput(),
+		CommandError:  p.GetCommandError(),
+	}
+}
+
+func repoCommitToProto(rc api.RepoCommit) *proto.RepoCommit {
+	return &proto.RepoCommit{
+		Repo:   string(rc.Repo),
+		Commit: string(rc.CommitID),
+	}
+}
+
+func repoCommitFromProto(p *proto.RepoCommit) api.RepoCommit {
+	return api.RepoCommit{
+		Repo:     api.RepoName(p.GetRepo()),
+		CommitID: api.CommitID(p.GetCommit()),
+	}
+}
+
+func commitIDsToStrings(ids []api.CommitID) []string {
+	if ids == nil {
+		return nil
+	}
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = string(id)
+	}
+	return s
+}
+
+func stringsToCommitIDs(s []string) []api.CommitID {
+	if len(s) == 0 {
+		return nil
+	}
+	ids := make([]api.CommitID, len(s))
+	for i, id := range s {
+		ids[i] = api.CommitID(id)
+	}
+	return ids
+}
@@ -0,0 +1,88 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+func TestSearchRequestProtoRoundTrip(t *testing.T) {
+	date := time.Date(2023, 1, 26, 12, 0, 0, 0, time.UTC)
+	original := &SearchRequest{
+		Repo: "github.com/sourcegraph/sourcegraph",
+		Revisions: []RevisionSpecifier{
+			{RevSpec: "HEAD"},
+			{RefGlob: "refs/heads/*", ExcludeRefGlob: "refs/heads/wip-*"},
+		},
+		Query: NewAnd(
+			&AuthorMatches{Expr: "camden", IgnoreCase: true},
+			&CommitterMatches{Expr: "keegan"},
+			&CommitBefore{Time: date},
+			&CommitAfter{Time: date.Add(-time.Hour)},
+			NewOr(
+				&MessageMatches{Expr: "fix"},
+				&DiffMatches{Expr: "TODO", IgnoreCase: true},
+			),
+			NewNot(&DiffModifiesFile{Expr: `\.go$`}),
+			&Boolean{Value: true},
+		),
+		IncludeDiff:          true,
+		Limit:                100,
+		IncludeModifiedFiles: true,
+	}
+
+	var converted SearchRequest
+	require.NoError(t, converted.FromProto(original.ToProto()))
+	require.Equal(t, original, &converted)
+}
+
+func TestCommitMatchProtoRoundTrip(t *testing.T) {
+	date := time.Date(2023, 1, 26, 12, 0, 0, 0, time.UTC)
+	original := &CommitMatch{
+		Oid:        "deadbeef",
+		Author:     Signature{Name: "Alice", Email: "alice@example.com", Date: date},
+		Committer:  Signature{Name: "Bob", Email: "bob@example.com", Date: date.Add(time.Minute)},
+		Parents:    []api.CommitID{"c0ffee"},
+		Refs:       []string{"refs/heads/main"},
+		SourceRefs: []string{"HEAD"},
+		Message: result.MatchedString{
+			Content: "fix the thing",
+			MatchedRanges: result.Ranges{{
+				Start: result.Location{Offset: 0, Line: 0, Column: 0},
+				End:   result.Location{Offset: 3, Line: 0, Column: 3},
+			}},
+		},
+		Diff:          result.MatchedString{Content: "diff"},
+		ModifiedFiles: []string{"main.go"},
+	}
+
+	var converted CommitMatch
+	converted.FromProto(original.ToProto())
+	require.Equal(t, original, &converted)
+}
+
+func TestBatchLogProtoRoundTrip(t *testing.T) {
+	request := &BatchLogRequest{
+		RepoCommits: []api.RepoCommit{
+			{Repo: "github.com/sourcegraph/sourcegraph", CommitID: "deadbeef"},
+			{Repo: "github.com/sourcegraph/zoekt", CommitID: "c0ffee"},
+		},
+		Format: "--format=%H",
+	}
+	var convertedRequest BatchLogRequest
+	convertedRequest.FromProto(request.ToProto())
+	require.Equal(t, request, &convertedRequest)
+
+	res := &BatchLogResult{
+		RepoCommit:    api.RepoCommit{Repo: "github.com/sourcegraph/sourcegraph", CommitID: "deadbeef"},
+		CommandOutput: "deadbeef\n",
+		CommandError:  "exit status 1",
+	}
+	var convertedResult BatchLogResult
+	convertedResult.FromProto(res.ToProto())
+	require.Equal(t, res, &convertedResult)
+}
@@ -15,6 +15,7 @@ import (
 // started with StartDiffFetcher
 type DiffFetcher struct {
 	dir string
+	env []string
 
 	startOnce sync.Once
 	stdin     io.Writer
@@ -25,10 +26,11 @@ type DiffFetcher struct {
 }
 
 // NewDiffFetcher starts a git diff-tree subprocess that waits, listening on stdin
-// for comimt hashes to generate patches for.
-func NewDiffFetcher(dir string) (*DiffFetcher, error) {
+// for comimt hashes to generate patches for. If env is not nil, it is used as
+// the environment of the subprocess.
+func NewDiffFetcher(dir string, env []string) (*DiffFetcher, error) {
 
-	return &DiffFetcher{dir: dir}, nil
+	return &DiffFetcher{dir: dir, env: env}, nil
 }
 
 func (d *DiffFetcher) Stop() {
@@ -51,6 +53,7 @@ func (d *DiffFetcher) start() (err error) {
 			"--root",           // Treat the root commit as a big creation event (otherwise the diff would be empty)
 		)
 		d.cmd.Dir = d.dir
+		d.cmd.Env = d.env
 
 		var stdoutReader io.ReadCloser
 		stdoutReader, err = d.cmd.StdoutPipe()
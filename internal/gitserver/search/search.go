@@ -89,6 +89,10 @@ type CommitSearcher struct {
 	IncludeDiff          bool
 	IncludeModifiedFiles bool
 	RepoName             api.RepoName
+
+	// GitEnv, if not nil, is the environment of the git commands run by the
+	// searcher. It lets partial clones fetch missing blobs.
+	GitEnv []string
 }
 
 // Search runs a search for commits matching the given predicate across the revisions passed in as revisionArgs.
@@ -146,6 +150,7 @@ func (cs *CommitSearcher) gitArgs() []string {
 func (cs *CommitSearcher) feedBatches(ctx context.Context, jobs chan job, resultChans chan chan *protocol.CommitMatch) (err error) {
 	cmd := exec.CommandContext(ctx, "git", cs.gitArgs()...)
 	cmd.Dir = cs.RepoDir
+	cmd.Env = cs.GitEnv
 	stdoutReader, err := cmd.StdoutPipe()
 	if err != nil {
 		return err
@@ -219,7 +224,7 @@ func getSubRepoFilterFunc(ctx context.Context, checker authz.SubRepoPermissionCh
 
 func (cs *CommitSearcher) runJobs(ctx context.Context, jobs chan job) error {
 	// Create a new diff fetcher subprocess for each worker
-	diffFetcher, err := NewDiffFetcher(cs.RepoDir)
+	diffFetcher, err := NewDiffFetcher(cs.RepoDir, cs.GitEnv)
 	if err != nil {
 		return err
 	}
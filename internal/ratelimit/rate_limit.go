@@ -4,6 +4,7 @@ import (
 	"context"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -11,12 +12,15 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 // DefaultRegistry is the default global rate limit registry, which holds rate
-// limit mappings for each instance of our services.
-var DefaultRegistry = NewRegistry()
+// limit mappings for each instance of our services. Its rate limiters share
+// their tokens through Redis, so that all instances together stay within the
+// rate limit of an external service.
+var DefaultRegistry = NewRedisRegistry(redispool.Cache)
 
 const defaultBurst = 10
 
@@ -28,12 +32,28 @@ func NewRegistry() *Registry {
 	}
 }
 
+// NewRedisRegistry creates and returns an empty rate limit registry whose rate
+// limiters take their tokens from token buckets in the Redis of kv, which are
+// shared with every other registry using the same Redis. If Redis fails, the
+// rate limiters fall back to limiting the rate of this process only. If kv is
+// not backed by Redis, it is the same as NewRegistry.
+func NewRedisRegistry(kv redispool.KeyValue) *Registry {
+	r := NewRegistry()
+	if pool, ok := kv.Pool(); ok {
+		r.buckets = newRedisBuckets(pool)
+	}
+	return r
+}
+
 // Registry manages rate limiters for external services.
 type Registry struct {
 	mu sync.Mutex
 	// rateLimiters contains mappings of external service to its *rate.Limiter. The
 	// key should be the URN of the external service.
 	rateLimiters map[string]*InstrumentedLimiter
+	// buckets are the token buckets in Redis shared by the rate limiters, or nil
+	// if they only limit this process.
+	buckets *redisBuckets
 }
 
 // Get returns the rate limiter configured for the given URN of an external
@@ -67,6 +87,7 @@ func (r *Registry) getOrSet(urn string, fallback *InstrumentedLimiter) *Instrume
 			fallbackRateLimit = rate.Inf
 		}
 		fallback = NewInstrumentedLimiter(urn, rate.NewLimiter(fallbackRateLimit, defaultBurst))
+		fallback.buckets = r.buckets
 	}
 	r.rateLimiters[urn] = fallback
 	return fallback
@@ -116,6 +137,15 @@ func (r *Registry) LimitInfo() map[string]LimitInfo {
 type InstrumentedLimiter struct {
 	urn string
 	*rate.Limiter
+	// buckets, if not nil, are the token buckets in Redis that WaitN takes
+	// tokens from instead of Limiter. The limit and burst are those stored with
+	// the bucket by SetConfiguredLimit, or else those of Limiter, which is also
+	// used if Redis fails.
+	buckets *redisBuckets
+	// configured is true once SetConfiguredLimit was called, after which the
+	// limit and burst of Limiter are stored with the bucket whenever WaitN takes
+	// tokens from it, in case Redis lost them.
+	configured atomic.Bool
 }
 
 // NewInstrumentedLimiter creates new InstrumentedLimiter with given URN and rate.Limiter
@@ -143,7 +173,7 @@ func (i *InstrumentedLimiter) WaitN(ctx context.Context, n int) error {
 	}
 
 	start := time.Now()
-	err := i.Limiter.WaitN(ctx, n)
+	err := i.waitN(ctx, n)
 	d := time.Since(start)
 	failedLabel := "false"
 	if err != nil {
@@ -154,6 +184,61 @@ func (i *InstrumentedLimiter) WaitN(ctx context.Context, n int) error {
 	return err
 }
 
+func (i *InstrumentedLimiter) waitN(ctx context.Context, n int) error {
+	if i.buckets == nil {
+		return i.Limiter.WaitN(ctx, n)
+	}
+	// We go to Redis even if our own limit is infinite, since the limit
+	// configured for the external service may be stored with its bucket by a
+	// process that syncs rate limits.
+	limit, burst := i.Limit(), i.Burst()
+	if limit != rate.Inf && n > burst {
+		return errors.Newf("rate: Wait(n=%d) exceeds limiter's burst %d", n, burst)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	maxWait := time.Duration(-1)
+	if deadline, ok := ctx.Deadline(); ok {
+		maxWait = time.Until(deadline)
+	}
+	wait, ok, err := i.buckets.reserve(ctx, i.urn, limit, burst, n, maxWait, i.configured.Load())
+	if err != nil {
+		return i.Limiter.WaitN(ctx, n)
+	}
+	if !ok {
+		return errors.Newf("rate: Wait(n=%d) would exceed context deadline", n)
+	}
+	if wait == 0 {
+		return nil
+	}
+
+	// Unlike rate.Limiter, we can't give the tokens back if ctx is done before
+	// we waited, because other processes may already be waiting after us.
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetConfiguredLimit sets the limit configured for the external service of the
+// limiter, like SetLimit. If the limiter shares its tokens through Redis, the
+// limit and burst are stored with its bucket, so that the limiters of all other
+// processes apply them too, even if they don't sync rate limits themselves.
+func (i *InstrumentedLimiter) SetConfiguredLimit(ctx context.Context, newLimit rate.Limit) error {
+	i.SetLimit(newLimit)
+	i.configured.Store(true)
+	if i.buckets == nil {
+		return nil
+	}
+	return i.buckets.setConfig(ctx, i.urn, newLimit, i.Burst())
+}
+
 // SetBurst is calling SetBurstAt(time.Now(), newBurst) method of the wrapped *rate.Limiter.
 func (i *InstrumentedLimiter) SetBurst(newBurst int) {
 	i.Limiter.SetBurstAt(time.Now(), newBurst)
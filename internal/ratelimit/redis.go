@@ -0,0 +1,219 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// tokenBucketScript takes ARGV[3] tokens from the token bucket at KEYS[1],
+// which fills up with ARGV[1] tokens per second to at most ARGV[2] tokens, and
+// returns the number of microseconds until the tokens are available. Tokens can
+// be taken before they are available, which leaves the bucket in debt. If the
+// wait exceeds ARGV[4] microseconds and ARGV[4] is not negative, or if the
+// bucket never fills up, no tokens are taken and -1 is returned.
+//
+// If ARGV[5] is 1, ARGV[1] and ARGV[2] are the configured limit and burst,
+// which are stored in the hash at KEYS[2]. Otherwise, the limit and burst
+// stored there, if any, are used instead of ARGV[1] and ARGV[2], so that every
+// process applies the configured limit regardless of its own configuration. A
+// negative limit is infinite.
+//
+// The clock of the Redis server is used so that callers don't need synchronized
+// clocks.
+const tokenBucketScript = `
+local limit = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local max_wait = tonumber(ARGV[4])
+
+if ARGV[5] == '1' then
+  redis.call('HSET', KEYS[2], 'limit', ARGV[1], 'burst', ARGV[2])
+else
+  local config = redis.call('HMGET', KEYS[2], 'limit', 'burst')
+  if config[1] and config[2] then
+    limit = tonumber(config[1])
+    burst = tonumber(config[2])
+  end
+end
+if limit < 0 then
+  return 0
+end
+
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000000 + tonumber(clock[2])
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'at')
+local tokens = tonumber(bucket[1])
+local at = tonumber(bucket[2])
+if tokens == nil or at == nil then
+  tokens = burst
+  at = now
+end
+if now > at then
+  tokens = math.min(burst, tokens + (now - at) * limit / 1000000)
+  at = now
+end
+
+tokens = tokens - n
+local wait = 0
+if tokens < 0 then
+  if limit == 0 then
+    return -1
+  end
+  wait = math.ceil(-tokens * 1000000 / limit)
+end
+if max_wait >= 0 and wait > max_wait then
+  return -1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'at', at)
+-- A bucket that expired is full, so it may expire once it filled up again.
+local ttl = 1000
+if limit > 0 then
+  ttl = ttl + math.ceil((burst - tokens) * 1000 / limit)
+end
+redis.call('PEXPIRE', KEYS[1], ttl)
+return wait
+`
+
+var tokenBucketScriptHash = redis.NewScript(2, tokenBucketScript).Hash()
+
+const (
+	// redisTimeout is how long we wait for Redis to connect and to take tokens
+	// from a bucket before we fall back to the local limiter.
+	redisTimeout = time.Second
+
+	// redisBackoff is how long we use the local limiters after Redis failed, so
+	// that callers don't wait for Redis to time out on every request.
+	redisBackoff = 30 * time.Second
+)
+
+// redisBuckets are the token buckets of a Registry in Redis. All processes
+// using the same Redis share the tokens of a bucket.
+type redisBuckets struct {
+	pool *redis.Pool
+
+	mu               sync.Mutex
+	unavailableUntil time.Time
+}
+
+func newRedisBuckets(pool *redis.Pool) *redisBuckets {
+	return &redisBuckets{pool: pool}
+}
+
+// errRedisUnavailable is returned while we back off from Redis after it failed.
+var errRedisUnavailable = errors.New("redis is unavailable")
+
+// reserve takes n tokens from the bucket of the limiter with the given URN,
+// which fills up with limit tokens per second to at most burst tokens. Unless
+// configured is true, a limit stored with setConfig or by a configured
+// reservation takes precedence. It returns how long the caller has to wait
+// until the tokens are available, or false if that would be longer than
+// maxWait. A negative maxWait means the caller can wait indefinitely.
+func (b *redisBuckets) reserve(ctx context.Context, urn string, limit rate.Limit, burst, n int, maxWait time.Duration, configured bool) (_ time.Duration, ok bool, err error) {
+	c, err := b.conn(ctx, urn)
+	if err != nil {
+		return 0, false, err
+	}
+	defer c.Close()
+	defer b.markUnavailable(urn, &err)
+
+	maxWaitMicros := int64(-1)
+	if maxWait >= 0 {
+		maxWaitMicros = maxWait.Microseconds()
+	}
+	configuredArg := 0
+	if configured {
+		configuredArg = 1
+	}
+	wait, err := redis.Int64(evalTokenBucketScript(c, bucketKey(urn), configKey(urn), redisLimit(limit), burst, n, maxWaitMicros, configuredArg))
+	if err != nil {
+		return 0, false, errors.Wrap(err, "take tokens")
+	}
+	if wait < 0 {
+		return 0, false, nil
+	}
+	return time.Duration(wait) * time.Microsecond, true, nil
+}
+
+// setConfig stores the configured limit and burst of the limiter with the
+// given URN, which every process taking tokens from its bucket uses from then
+// on.
+func (b *redisBuckets) setConfig(ctx context.Context, urn string, limit rate.Limit, burst int) (err error) {
+	c, err := b.conn(ctx, urn)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	defer b.markUnavailable(urn, &err)
+
+	_, err = redis.DoWithTimeout(c, redisTimeout, "HSET", configKey(urn), "limit", redisLimit(limit), "burst", burst)
+	return errors.Wrap(err, "store rate limit")
+}
+
+// conn returns a connection to Redis, or errRedisUnavailable while we back
+// off from Redis after it failed.
+func (b *redisBuckets) conn(ctx context.Context, urn string) (_ redis.Conn, err error) {
+	b.mu.Lock()
+	unavailable := time.Now().Before(b.unavailableUntil)
+	b.mu.Unlock()
+	if unavailable {
+		return nil, errRedisUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+	c, err := b.pool.GetContext(ctx)
+	if err != nil {
+		b.markUnavailable(urn, &err)
+		return nil, errors.Wrap(err, "get redis connection")
+	}
+	return c, nil
+}
+
+// markUnavailable backs off from Redis if *err is not nil.
+func (b *redisBuckets) markUnavailable(urn string, err *error) {
+	if *err == nil {
+		return
+	}
+	b.mu.Lock()
+	b.unavailableUntil = time.Now().Add(redisBackoff)
+	b.mu.Unlock()
+	metricRedisFallback.WithLabelValues(urn).Inc()
+}
+
+func bucketKey(urn string) string { return "ratelimit:" + urn }
+func configKey(urn string) string { return "ratelimit:config:" + urn }
+
+// redisLimit returns limit as stored in Redis, where infinity is negative.
+func redisLimit(limit rate.Limit) float64 {
+	if limit == rate.Inf {
+		return -1
+	}
+	return float64(limit)
+}
+
+// evalTokenBucketScript is like (*redis.Script).Do, but times out after
+// redisTimeout.
+func evalTokenBucketScript(c redis.Conn, bucket, config string, args ...any) (any, error) {
+	reply, err := redis.DoWithTimeout(c, redisTimeout, "EVALSHA", append([]any{tokenBucketScriptHash, 2, bucket, config}, args...)...)
+	var e redis.Error
+	if errors.As(err, &e) && strings.HasPrefix(string(e), "NOSCRIPT ") {
+		reply, err = redis.DoWithTimeout(c, redisTimeout, "EVAL", append([]any{tokenBucketScript, 2, bucket, config}, args...)...)
+	}
+	return reply, err
+}
+
+var metricRedisFallback = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_internal_rate_limit_redis_fallback_total",
+	Help: "Number of times our internal rate limiter failed to reach Redis and fell back to local limiting",
+}, []string{"urn"})
@@ -0,0 +1,263 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	lua "github.com/yuin/gopher-lua"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+)
+
+func TestRedisRegistry(t *testing.T) {
+	ctx := context.Background()
+	r := newFakeRedis(t)
+	urn := "extsvc:github:1"
+
+	// Two registries using the same Redis, like two services would.
+	a := NewRedisRegistry(r.kv).Get(urn)
+	b := NewRedisRegistry(r.kv).Get(urn)
+	for _, l := range []*InstrumentedLimiter{a, b} {
+		l.SetLimit(2)
+		l.SetBurst(4)
+	}
+
+	// The burst is shared.
+	for _, l := range []*InstrumentedLimiter{a, b, a, b} {
+		require.NoError(t, l.Wait(ctx))
+	}
+	wait, ok, err := a.buckets.reserve(ctx, urn, 2, 4, 1, -1, false)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// The bucket is in debt now, so we'd have to wait a second.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	assert.Error(t, b.Wait(timeoutCtx))
+
+	// It fills up at the limit.
+	r.now = r.now.Add(3 * time.Second)
+	for _, l := range []*InstrumentedLimiter{a, b, a, b} {
+		require.NoError(t, l.Wait(timeoutCtx))
+	}
+	assert.Error(t, a.Wait(timeoutCtx))
+
+	// Other URNs have their own buckets.
+	c := NewRedisRegistry(r.kv).Get("extsvc:github:2")
+	c.SetLimit(2)
+	require.NoError(t, c.Wait(timeoutCtx))
+}
+
+func TestRedisRegistry_Fallback(t *testing.T) {
+	ctx := context.Background()
+	r := newFakeRedis(t)
+	r.down = true
+
+	l := NewRedisRegistry(r.kv).Get("extsvc:github:1")
+	l.SetLimit(1)
+	l.SetBurst(2)
+
+	// We use the local limiter while Redis is down.
+	require.NoError(t, l.Wait(ctx))
+	require.NoError(t, l.Wait(ctx))
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	assert.Error(t, l.Wait(timeoutCtx))
+
+	// We don't retry Redis until we backed off.
+	assert.Equal(t, 1, r.calls)
+
+	l.buckets.unavailableUntil = time.Time{}
+	r.down = false
+	require.NoError(t, l.Wait(ctx))
+	assert.Equal(t, 2, r.calls)
+}
+
+func TestRedisRegistry_ConfiguredLimit(t *testing.T) {
+	ctx := context.Background()
+	r := newFakeRedis(t)
+	urn := "extsvc:github:1"
+
+	// A service that doesn't sync rate limits has an infinite limit.
+	unsynced := NewRedisRegistry(r.kv).Get(urn)
+	require.Equal(t, rate.Inf, unsynced.Limit())
+	require.NoError(t, unsynced.Wait(ctx))
+
+	// Once a service that syncs rate limits stored the configured limit, it
+	// applies to the other service too.
+	synced := NewRedisRegistry(r.kv).Get(urn)
+	synced.SetBurst(2)
+	require.NoError(t, synced.SetConfiguredLimit(ctx, 1))
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	require.NoError(t, unsynced.Wait(timeoutCtx))
+	require.NoError(t, synced.Wait(timeoutCtx))
+	assert.Error(t, unsynced.Wait(timeoutCtx))
+
+	// If Redis loses the configured limit, the syncing service stores it again
+	// the next time it takes tokens.
+	r.hashes = map[string]map[string]string{}
+	r.now = r.now.Add(time.Minute)
+	require.NoError(t, synced.Wait(timeoutCtx))
+	require.NoError(t, unsynced.Wait(timeoutCtx))
+	assert.Error(t, unsynced.Wait(timeoutCtx))
+
+	// An infinite configured limit lifts the limit of the other service.
+	require.NoError(t, synced.SetConfiguredLimit(ctx, rate.Inf))
+	for i := 0; i < 10; i++ {
+		require.NoError(t, unsynced.Wait(timeoutCtx))
+	}
+}
+
+func TestInstrumentedLimiter_InfiniteRedis(t *testing.T) {
+	r := newFakeRedis(t)
+	l := NewRedisRegistry(r.kv).Get("extsvc:github:1")
+	l.SetLimit(rate.Inf)
+
+	// Without a configured limit in Redis, an infinite limit doesn't wait.
+	for i := 0; i < 20; i++ {
+		require.NoError(t, l.Wait(context.Background()))
+	}
+	assert.Empty(t, r.hashes["ratelimit:extsvc:github:1"])
+}
+
+// fakeRedis is an in-process stand-in for Redis that evaluates Lua scripts
+// with the subset of commands they use.
+type fakeRedis struct {
+	kv     redispool.KeyValue
+	now    time.Time
+	down   bool
+	calls  int
+	hashes map[string]map[string]string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	r := &fakeRedis{
+		now:    time.Unix(1674000000, 0),
+		hashes: map[string]map[string]string{},
+	}
+
+	conn := redigomock.NewConn()
+	t.Cleanup(func() { conn.Clear(); conn.Close() })
+	conn.GenericCommand("EVALSHA").Handle(func(args []any) (any, error) {
+		r.calls++
+		if r.down {
+			return nil, redis.Error("LOADING Redis is loading the dataset in memory")
+		}
+		return nil, redis.Error("NOSCRIPT No matching script")
+	})
+	conn.GenericCommand("EVAL").Handle(func(args []any) (any, error) {
+		return r.eval(args)
+	})
+	conn.GenericCommand("HSET").Handle(func(args []any) (any, error) {
+		r.calls++
+		if r.down {
+			return nil, redis.Error("LOADING Redis is loading the dataset in memory")
+		}
+		key := redisString(args[0])
+		if r.hashes[key] == nil {
+			r.hashes[key] = map[string]string{}
+		}
+		for i := 1; i+1 < len(args); i += 2 {
+			r.hashes[key][redisString(args[i])] = redisString(args[i+1])
+		}
+		return int64(len(args) / 2), nil
+	})
+
+	r.kv = redispool.RedisKeyValue(&redis.Pool{Dial: func() (redis.Conn, error) { return conn, nil }, MaxIdle: 10})
+	return r
+}
+
+func (r *fakeRedis) eval(args []any) (any, error) {
+	L := lua.NewState()
+	defer L.Close()
+
+	numKeys := args[1].(int)
+	keys, argv := L.NewTable(), L.NewTable()
+	for i, arg := range args[2:] {
+		if i < numKeys {
+			keys.Append(lua.LString(redisString(arg)))
+		} else {
+			argv.Append(lua.LString(redisString(arg)))
+		}
+	}
+	L.SetGlobal("KEYS", keys)
+	L.SetGlobal("ARGV", argv)
+
+	api := L.NewTable()
+	L.SetField(api, "call", L.NewFunction(r.call))
+	L.SetGlobal("redis", api)
+
+	if err := L.DoString(args[0].(string)); err != nil {
+		return nil, err
+	}
+	// Redis converts Lua numbers to integer replies.
+	return int64(L.Get(-1).(lua.LNumber)), nil
+}
+
+func (r *fakeRedis) call(L *lua.LState) int {
+	cmd := L.CheckString(1)
+	args := make([]string, 0, L.GetTop()-1)
+	for i := 2; i <= L.GetTop(); i++ {
+		switch v := L.Get(i).(type) {
+		case lua.LNumber:
+			args = append(args, strconv.FormatFloat(float64(v), 'g', 17, 64))
+		default:
+			args = append(args, v.String())
+		}
+	}
+
+	ret := L.NewTable()
+	switch cmd {
+	case "TIME":
+		ret.Append(lua.LString(strconv.FormatInt(r.now.Unix(), 10)))
+		ret.Append(lua.LString(strconv.Itoa(r.now.Nanosecond() / 1000)))
+	case "HMGET":
+		for _, f := range args[1:] {
+			if v, ok := r.hashes[args[0]][f]; ok {
+				ret.Append(lua.LString(v))
+			} else {
+				ret.Append(lua.LFalse)
+			}
+		}
+	case "HSET":
+		if r.hashes[args[0]] == nil {
+			r.hashes[args[0]] = map[string]string{}
+		}
+		for i := 1; i+1 < len(args); i += 2 {
+			r.hashes[args[0]][args[i]] = args[i+1]
+		}
+		L.Push(lua.LNumber(len(args) / 2))
+		return 1
+	case "PEXPIRE":
+		L.Push(lua.LNumber(1))
+		return 1
+	default:
+		L.RaiseError("unsupported command %s", cmd)
+	}
+	L.Push(ret)
+	return 1
+}
+
+func redisString(arg any) string {
+	switch v := arg.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	panic("unsupported argument")
+}
@@ -144,8 +144,9 @@ func (s *AzureDevOpsSource) makeRepo(p azuredevops.Repository) (*types.Repo, err
 
 	name := path.Join(fullURL.Host, fullURL.Path)
 	return &types.Repo{
-		Name: api.RepoName(name),
-		URI:  name,
+		Name:    api.RepoName(name),
+		URI:     name,
+		Private: p.Project.Visibility == "private",
 		ExternalRepo: api.ExternalRepoSpec{
 			ID:          p.ID,
 			ServiceType: extsvc.TypeAzureDevOps,
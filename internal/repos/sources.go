@@ -80,6 +80,12 @@ func NewSource(ctx context.Context, logger log.Logger, db database.DB, svc *type
 		return NewRustPackagesSource(ctx, svc, cf)
 	case extsvc.KindRubyPackages:
 		return NewRubyPackagesSource(ctx, svc, cf)
+	case extsvc.KindDotnetPackages:
+		return NewDotnetPackagesSource(ctx, svc, cf)
+	case extsvc.KindPHPPackages:
+		return NewPHPPackagesSource(ctx, svc, cf)
+	case extsvc.KindSubversion:
+		return NewSubversionSource(ctx, svc)
 	case extsvc.KindOther:
 		return NewOtherSource(ctx, svc, cf, logger.Scoped("OtherSource", ""))
 	default:
@@ -0,0 +1,121 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/subversion"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A SubversionSource yields repositories from a single Subversion connection
+// configured in Sourcegraph via the external services configuration.
+type SubversionSource struct {
+	svc    *types.ExternalService
+	config *schema.SubversionConnection
+	base   *url.URL
+}
+
+// NewSubversionSource returns a new SubversionSource from the given external
+// service.
+func NewSubversionSource(ctx context.Context, svc *types.ExternalService) (*SubversionSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.SubversionConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newSubversionSource(svc, &c)
+}
+
+func newSubversionSource(svc *types.ExternalService, c *schema.SubversionConnection) (*SubversionSource, error) {
+	base, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse url")
+	}
+	// Credentials are added to the clone URL by the clone URL builder, never
+	// persisted as part of the repository's sources.
+	base.User = nil
+
+	return &SubversionSource{
+		svc:    svc,
+		config: c,
+		base:   extsvc.NormalizeBaseURL(base),
+	}, nil
+}
+
+// CheckConnection at this point assumes availability and relies on errors returned
+// from the subsequent calls. This is going to be expanded as part of issue #44683
+// to actually only return true if the source can serve requests.
+func (s SubversionSource) CheckConnection(ctx context.Context) error {
+	return nil
+}
+
+// ListRepos returns all Subversion repositories listed in the connection
+// configured in Sourcegraph via the external services configuration.
+func (s SubversionSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	for _, path := range s.config.Repos {
+		// Tiny optimization: exit early if context has been canceled.
+		if err := ctx.Err(); err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+
+		results <- SourceResult{Source: s, Repo: s.makeRepo(path)}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s SubversionSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s SubversionSource) makeRepo(path string) *types.Repo {
+	path = strings.Trim(path, "/")
+	urn := s.svc.URN()
+
+	return &types.Repo{
+		Name: reposource.SubversionRepoName(
+			s.config.RepositoryPathPattern,
+			s.base.Host,
+			path,
+		),
+		URI: string(reposource.SubversionRepoName(
+			"",
+			s.base.Host,
+			path,
+		)),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          path,
+			ServiceType: extsvc.TypeSubversion,
+			ServiceID:   s.base.String(),
+		},
+		Private: true,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: subversionRepoURL(s.base, path).String(),
+			},
+		},
+		Metadata: &subversion.Repository{
+			Path: path,
+		},
+	}
+}
+
+// subversionRepoURL returns the URL of the repository at the given path,
+// relative to the base URL of the Subversion server.
+func subversionRepoURL(base *url.URL, path string) *url.URL {
+	u := *base
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + strings.Trim(path, "/")
+	return &u
+}
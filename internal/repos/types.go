@@ -103,8 +103,11 @@ func (r *RateLimitSyncer) SyncLimitersSince(ctx context.Context, updateAfter tim
 }
 
 // SyncServices syncs a know slice of services without fetching them from the
-// database.
+// database. The rate limits are shared with the services that don't sync them
+// through Redis, which is best effort: failing to store a rate limit doesn't
+// stop the other rate limits from being synced.
 func (r *RateLimitSyncer) SyncServices(ctx context.Context, services []*types.ExternalService) error {
+	var errs error
 	for _, svc := range services {
 		limit, err := extsvc.ExtractEncryptableRateLimit(ctx, svc.Config, svc.Kind)
 		if err != nil {
@@ -115,7 +118,9 @@ func (r *RateLimitSyncer) SyncServices(ctx context.Context, services []*types.Ex
 		}
 
 		l := r.registry.Get(svc.URN())
-		l.SetLimit(limit)
+		if err := l.SetConfiguredLimit(ctx, limit); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "sharing rate limit of external service %d", svc.ID))
+		}
 	}
-	return nil
+	return errs
 }
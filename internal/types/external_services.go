@@ -39,3 +39,9 @@ type GerritConnection struct {
 	URN string
 	*schema.GerritConnection
 }
+
+type AzureDevOpsConnection struct {
+	// The unique resource identifier of the external service.
+	URN string
+	*schema.AzureDevOpsConnection
+}
@@ -73,6 +73,8 @@ func (e *ExternalService) RedactedConfig(ctx context.Context) (string, error) {
 		es.redactString(c.Token, "token")
 	case *schema.PerforceConnection:
 		es.redactString(c.P4Passwd, "p4.passwd")
+	case *schema.SubversionConnection:
+		es.redactString(c.Password, "password")
 	case *schema.GitoliteConnection:
 		// Nothing to redact
 	case *schema.GoModulesConnection:
@@ -93,6 +95,10 @@ func (e *ExternalService) RedactedConfig(ctx context.Context) (string, error) {
 		// Nothing to redact
 	case *schema.RubyPackagesConnection:
 		es.redactString(c.Repository, "repository")
+	case *schema.DotnetPackagesConnection:
+		es.redactString(c.Repository, "repository")
+	case *schema.PHPPackagesConnection:
+		es.redactString(c.Repository, "repository")
 	case *schema.JVMPackagesConnection:
 		if c.Maven != nil {
 			es.redactString(c.Maven.Credentials, "maven", "credentials")
@@ -204,6 +210,12 @@ func (e *ExternalService) UnredactConfig(ctx context.Context, old *ExternalServi
 			return errCodeHostIdentityChanged{"p4.port", "p4.passwd"}
 		}
 		es.unredactString(c.P4Passwd, o.P4Passwd, "p4.passwd")
+	case *schema.SubversionConnection:
+		o := oldCfg.(*schema.SubversionConnection)
+		if c.Password == RedactedSecret && c.Url != o.Url {
+			return errCodeHostIdentityChanged{"url", "password"}
+		}
+		es.unredactString(c.Password, o.Password, "password")
 	case *schema.GitoliteConnection:
 		// Nothing to redact
 	case *schema.GoModulesConnection:
@@ -221,6 +233,12 @@ func (e *ExternalService) UnredactConfig(ctx context.Context, old *ExternalServi
 	case *schema.RubyPackagesConnection:
 		o := oldCfg.(*schema.RubyPackagesConnection)
 		es.unredactString(c.Repository, o.Repository, "repository")
+	case *schema.DotnetPackagesConnection:
+		o := oldCfg.(*schema.DotnetPackagesConnection)
+		es.unredactString(c.Repository, o.Repository, "repository")
+	case *schema.PHPPackagesConnection:
+		o := oldCfg.(*schema.PHPPackagesConnection)
+		es.unredactString(c.Repository, o.Repository, "repository")
 	case *schema.JVMPackagesConnection:
 		o := oldCfg.(*schema.JVMPackagesConnection)
 		if c.Maven != nil && o.Maven != nil {
@@ -80,6 +80,11 @@ func TestExternalService_RedactedConfig(t *testing.T) {
 			in:   schema.PerforceConnection{P4User: "foo", P4Passwd: "bar"},
 			out:  schema.PerforceConnection{P4User: "foo", P4Passwd: RedactedSecret},
 		},
+		{
+			kind: extsvc.KindSubversion,
+			in:   schema.SubversionConnection{Url: "https://svn.example.org", Username: "foo", Password: "bar"},
+			out:  schema.SubversionConnection{Url: "https://svn.example.org", Username: "foo", Password: RedactedSecret},
+		},
 		{
 			kind: extsvc.KindPagure,
 			in:   schema.PagureConnection{Url: "https://src.fedoraproject.org", Token: "bar"},
@@ -281,6 +286,19 @@ func TestExternalService_UnredactConfig(t *testing.T) {
 			out:     schema.PerforceConnection{P4User: "baz", P4Passwd: "bar"},
 			wantErr: errCodeHostIdentityChanged{"p4.port", "p4.passwd"},
 		},
+		{
+			kind: extsvc.KindSubversion,
+			old:  schema.SubversionConnection{Url: "https://svn.example.org", Username: "foo", Password: "bar"},
+			in:   schema.SubversionConnection{Url: "https://svn.example.org", Username: "baz", Password: RedactedSecret},
+			out:  schema.SubversionConnection{Url: "https://svn.example.org", Username: "baz", Password: "bar"},
+		},
+		{
+			kind:    extsvc.KindSubversion,
+			old:     schema.SubversionConnection{Url: "https://svn.example.org", Username: "foo", Password: "bar"},
+			in:      schema.SubversionConnection{Url: "https://svn.corp.example.org", Username: "foo", Password: RedactedSecret},
+			out:     schema.SubversionConnection{Url: "https://svn.corp.example.org", Username: "foo", Password: "bar"},
+			wantErr: errCodeHostIdentityChanged{"url", "password"},
+		},
 		{
 			// Tests that we can remove a secret field and that it won't appear redacted in the output
 			kind: extsvc.KindPagure,
@@ -0,0 +1,351 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	otlog "github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DeadLetterStore inspects and recovers the records of a dbworker store that ended up in the
+// failed state, either because they failed explicitly or because they exhausted their retries
+// or resets. Unlike Store, it does not depend on the record type, so the failed records of any
+// dbworker store can be handled uniformly.
+type DeadLetterStore interface {
+	// Name returns the name of the underlying store.
+	Name() string
+
+	// FailureGroups returns the failed records matching the given filter, grouped by their
+	// normalized failure message. Groups are ordered by their number of records, largest first.
+	FailureGroups(ctx context.Context, filter DeadLetterFilter) ([]FailureGroup, error)
+
+	// FailedRecords returns a page of the failed records matching the given filter, most recently
+	// failed first, along with the total number of matching records.
+	FailedRecords(ctx context.Context, filter DeadLetterFilter, limit, offset int) ([]FailedRecord, int, error)
+
+	// RequeueFailed moves the failed records matching the given filter back into the queued state
+	// and resets their failure and reset counters. The number of requeued records is returned.
+	RequeueFailed(ctx context.Context, filter DeadLetterFilter) (int, error)
+
+	// CanDelete returns whether the failed records of the underlying store can be deleted.
+	CanDelete() bool
+
+	// DeleteFailed deletes the failed records matching the given filter. The number of deleted
+	// records is returned. If the failed records of the store can't be deleted, an error is
+	// returned instead.
+	DeleteFailed(ctx context.Context, filter DeadLetterFilter) (int, error)
+}
+
+// ErrDeadLetterDeleteUnsupported is returned by DeleteFailed if the failed records of a store
+// can't be deleted.
+var ErrDeadLetterDeleteUnsupported = errors.New("the failed records of this store can't be deleted")
+
+// DeadLetterOptions configure how a DeadLetterStore deletes failed records. Without any of
+// these options, failed records can only be requeued.
+type DeadLetterOptions struct {
+	// DeleteRows allows deleting failed records by deleting their rows. This must only be set
+	// for tables that hold nothing but jobs, as the rows of tables that also hold domain data,
+	// such as uploads or changesets, are referenced elsewhere and have to be deleted by their
+	// owner.
+	DeleteRows bool
+
+	// DeleteRecords deletes the records with the given IDs, which were failed when they were
+	// selected, the way the owner of the table deletes them. It returns the number of deleted
+	// records. If supplied, it takes precedence over DeleteRows.
+	DeleteRecords func(ctx context.Context, ids []int) (int, error)
+}
+
+// NewDeadLetterStore creates a store handling the failed records of the dbworker store with the
+// given options.
+func NewDeadLetterStore[T workerutil.Record](observationCtx *observation.Context, handle basestore.TransactableHandle, options Options[T], deadLetterOptions DeadLetterOptions) DeadLetterStore {
+	return &deadLetterStore[T]{
+		store:             newStore(observationCtx, handle, options),
+		deadLetterOptions: deadLetterOptions,
+	}
+}
+
+type deadLetterStore[T workerutil.Record] struct {
+	*store[T]
+	deadLetterOptions DeadLetterOptions
+}
+
+// DeadLetterFilter restricts the failed records a DeadLetterStore operates on. Empty fields
+// match all failed records.
+type DeadLetterFilter struct {
+	// FailureMessage matches records whose normalized failure message equals this value, as
+	// reported by FailureGroups.
+	FailureMessage *string
+
+	// IDs matches records with one of the given identifiers.
+	IDs []int
+
+	// FinishedAfter and FinishedBefore match records that failed within the given time range.
+	FinishedAfter  *time.Time
+	FinishedBefore *time.Time
+}
+
+// FailureGroup is a set of failed records sharing the same normalized failure message.
+type FailureGroup struct {
+	FailureMessage string
+	Count          int
+	FirstFailedAt  *time.Time
+	LastFailedAt   *time.Time
+}
+
+// FailedRecord describes a record in the failed state.
+type FailedRecord struct {
+	ID             int
+	FailureMessage *string
+	NumFailures    int
+	NumResets      int
+	FinishedAt     *time.Time
+	ExecutionLogs  []executor.ExecutionLogEntry
+}
+
+// Name returns the name of the store.
+func (s *store[T]) Name() string {
+	return s.options.Name
+}
+
+// FailureGroups returns the failed records matching the given filter, grouped by their
+// normalized failure message.
+func (s *store[T]) FailureGroups(ctx context.Context, filter DeadLetterFilter) (_ []FailureGroup, err error) {
+	ctx, _, endObservation := s.operations.failureGroups.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	rows, err := s.Query(ctx, s.formatQuery(
+		failureGroupsQuery,
+		s.normalizedFailureMessage(),
+		quote(s.options.TableName),
+		sqlf.Join(s.makeDeadLetterConditions(filter), " AND "),
+	))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var groups []FailureGroup
+	for rows.Next() {
+		var group FailureGroup
+		if err := rows.Scan(&group.FailureMessage, &group.Count, &group.FirstFailedAt, &group.LastFailedAt); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+const failureGroupsQuery = `
+SELECT
+	%s AS normalized_failure_message,
+	COUNT(*),
+	MIN({finished_at}),
+	MAX({finished_at})
+FROM %s
+WHERE %s
+GROUP BY normalized_failure_message
+ORDER BY COUNT(*) DESC, normalized_failure_message
+`
+
+// FailedRecords returns a page of the failed records matching the given filter, most recently
+// failed first, along with the total number of matching records.
+func (s *store[T]) FailedRecords(ctx context.Context, filter DeadLetterFilter, limit, offset int) (_ []FailedRecord, _ int, err error) {
+	ctx, _, endObservation := s.operations.failedRecords.With(ctx, &err, observation.Args{LogFields: []otlog.Field{
+		otlog.Int("limit", limit),
+		otlog.Int("offset", offset),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	conds := sqlf.Join(s.makeDeadLetterConditions(filter), " AND ")
+
+	totalCount, _, err := basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(
+		failedRecordsCountQuery,
+		quote(s.options.TableName),
+		conds,
+	)))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.Query(ctx, s.formatQuery(
+		failedRecordsQuery,
+		quote(s.options.TableName),
+		conds,
+		limit,
+		offset,
+	))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var records []FailedRecord
+	for rows.Next() {
+		var record FailedRecord
+		if err := rows.Scan(
+			&record.ID,
+			&record.FailureMessage,
+			&record.NumFailures,
+			&record.NumResets,
+			&record.FinishedAt,
+			pq.Array(&record.ExecutionLogs),
+		); err != nil {
+			return nil, 0, err
+		}
+		records = append(records, record)
+	}
+
+	return records, totalCount, nil
+}
+
+const failedRecordsCountQuery = `
+SELECT COUNT(*) FROM %s WHERE %s
+`
+
+const failedRecordsQuery = `
+SELECT
+	{id},
+	{failure_message},
+	{num_failures},
+	{num_resets},
+	{finished_at},
+	{execution_logs}
+FROM %s
+WHERE %s
+ORDER BY {finished_at} DESC NULLS LAST, {id} DESC
+LIMIT %s OFFSET %s
+`
+
+// RequeueFailed moves the failed records matching the given filter back into the queued state.
+func (s *store[T]) RequeueFailed(ctx context.Context, filter DeadLetterFilter) (_ int, err error) {
+	ctx, _, endObservation := s.operations.requeueFailed.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(
+		requeueFailedQuery,
+		quote(s.options.TableName),
+		sqlf.Join(s.makeDeadLetterConditions(filter), " AND "),
+	)))
+	return count, err
+}
+
+const requeueFailedQuery = `
+WITH requeued AS (
+	UPDATE %s
+	SET
+		{state} = 'queued',
+		{queued_at} = clock_timestamp(),
+		{started_at} = NULL,
+		{finished_at} = NULL,
+		{process_after} = NULL,
+		{failure_message} = NULL,
+		{num_failures} = 0,
+		{num_resets} = 0,
+		{cancel} = false
+	WHERE %s
+	RETURNING 1
+)
+SELECT COUNT(*) FROM requeued
+`
+
+// CanDelete returns whether the failed records of the store can be deleted.
+func (s *deadLetterStore[T]) CanDelete() bool {
+	return s.deadLetterOptions.DeleteRecords != nil || s.deadLetterOptions.DeleteRows
+}
+
+// DeleteFailed deletes the failed records matching the given filter.
+func (s *deadLetterStore[T]) DeleteFailed(ctx context.Context, filter DeadLetterFilter) (_ int, err error) {
+	ctx, _, endObservation := s.operations.deleteFailed.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	conds := sqlf.Join(s.makeDeadLetterConditions(filter), " AND ")
+
+	if s.deadLetterOptions.DeleteRecords != nil {
+		ids, err := basestore.ScanInts(s.Query(ctx, s.formatQuery(
+			failedRecordIDsQuery,
+			quote(s.options.TableName),
+			conds,
+		)))
+		if err != nil || len(ids) == 0 {
+			return 0, err
+		}
+
+		return s.deadLetterOptions.DeleteRecords(ctx, ids)
+	}
+
+	if !s.deadLetterOptions.DeleteRows {
+		return 0, ErrDeadLetterDeleteUnsupported
+	}
+
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(
+		deleteFailedQuery,
+		quote(s.options.TableName),
+		conds,
+	)))
+	return count, err
+}
+
+const failedRecordIDsQuery = `
+SELECT {id} FROM %s WHERE %s ORDER BY {id}
+`
+
+const deleteFailedQuery = `
+WITH deleted AS (
+	DELETE FROM %s
+	WHERE %s
+	RETURNING 1
+)
+SELECT COUNT(*) FROM deleted
+`
+
+// makeDeadLetterConditions returns the conditions selecting the failed records matching the
+// given filter.
+func (s *store[T]) makeDeadLetterConditions(filter DeadLetterFilter) []*sqlf.Query {
+	conds := []*sqlf.Query{s.formatQuery("{state} = 'failed'")}
+	if filter.FailureMessage != nil {
+		conds = append(conds, sqlf.Sprintf("%s = %s", s.normalizedFailureMessage(), *filter.FailureMessage))
+	}
+	if len(filter.IDs) > 0 {
+		conds = append(conds, s.formatQuery("{id} = ANY(%s)", pq.Array(filter.IDs)))
+	}
+	if filter.FinishedAfter != nil {
+		conds = append(conds, s.formatQuery("{finished_at} >= %s", *filter.FinishedAfter))
+	}
+	if filter.FinishedBefore != nil {
+		conds = append(conds, s.formatQuery("{finished_at} < %s", *filter.FinishedBefore))
+	}
+
+	return conds
+}
+
+// normalizedFailureMessage returns an expression evaluating to the failure message of a record
+// with the identifiers that commonly vary between otherwise identical failures (UUIDs, commit
+// hashes and numbers such as record IDs, ports or line numbers) replaced by placeholders, so that
+// records failing for the same reason can be grouped.
+func (s *store[T]) normalizedFailureMessage() *sqlf.Query {
+	return s.formatQuery(
+		normalizedFailureMessageQuery,
+		`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`,
+		`\m[0-9a-f]{40}\M`,
+		`[0-9]+`,
+	)
+}
+
+const normalizedFailureMessageQuery = `
+regexp_replace(
+	regexp_replace(
+		regexp_replace(COALESCE({failure_message}, ''), %s, '<uuid>', 'gi'),
+		%s, '<sha>', 'gi'
+	),
+	%s, '<n>', 'g'
+)
+`
@@ -0,0 +1,244 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestStoreFailureGroups(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, failure_message, finished_at)
+		VALUES
+			(1, 'failed', 'failed to clone repository 42', NOW() - '5 minute'::interval),
+			(2, 'failed', 'failed to clone repository 1337', NOW() - '4 minute'::interval),
+			(3, 'failed', 'commit 9c1d1d1c1ba1c2bfcbd7dc8d4a0cb1ba67e10aa5 not found', NOW() - '3 minute'::interval),
+			(4, 'errored', 'failed to clone repository 7', NOW() - '2 minute'::interval),
+			(5, 'failed', 'failed to clone repository 8', NOW() - '1 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	groups, err := testStore(db, defaultTestStoreOptions(nil, testScanRecord)).FailureGroups(context.Background(), DeadLetterFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error getting failure groups: %s", err)
+	}
+
+	var messages []string
+	var counts []int
+	for _, group := range groups {
+		messages = append(messages, group.FailureMessage)
+		counts = append(counts, group.Count)
+	}
+	if diff := cmp.Diff([]string{"failed to clone repository <n>", "commit <sha> not found"}, messages); diff != "" {
+		t.Errorf("unexpected failure messages (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{3, 1}, counts); diff != "" {
+		t.Errorf("unexpected counts (-want +got):\n%s", diff)
+	}
+}
+
+func TestStoreFailedRecords(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, failure_message, num_failures, finished_at, execution_logs)
+		VALUES
+			(1, 'failed', 'failed to clone repository 42', 3, NOW() - '5 minute'::interval, E'{"{\\"key\\": \\"step.0\\"}"}'),
+			(2, 'failed', 'failed to clone repository 1337', 3, NOW() - '4 minute'::interval, NULL),
+			(3, 'failed', 'out of memory', 1, NOW() - '3 minute'::interval, NULL),
+			(4, 'completed', NULL, 0, NOW() - '2 minute'::interval, NULL)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	store := testStore(db, defaultTestStoreOptions(nil, testScanRecord))
+
+	failureMessage := "failed to clone repository <n>"
+	records, totalCount, err := store.FailedRecords(context.Background(), DeadLetterFilter{FailureMessage: &failureMessage}, 1, 0)
+	if err != nil {
+		t.Fatalf("unexpected error listing failed records: %s", err)
+	}
+	if totalCount != 2 {
+		t.Errorf("unexpected total count. want=%d have=%d", 2, totalCount)
+	}
+	if len(records) != 1 || records[0].ID != 2 {
+		t.Fatalf("expected record 2, have %v", records)
+	}
+
+	records, _, err = store.FailedRecords(context.Background(), DeadLetterFilter{FailureMessage: &failureMessage}, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing failed records: %s", err)
+	}
+	if len(records) != 1 || records[0].ID != 1 {
+		t.Fatalf("expected record 1, have %v", records)
+	}
+	if records[0].NumFailures != 3 {
+		t.Errorf("unexpected number of failures. want=%d have=%d", 3, records[0].NumFailures)
+	}
+	if len(records[0].ExecutionLogs) != 1 || records[0].ExecutionLogs[0].Key != "step.0" {
+		t.Errorf("unexpected execution logs %v", records[0].ExecutionLogs)
+	}
+}
+
+func TestStoreRequeueFailed(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, failure_message, num_failures, num_resets, finished_at)
+		VALUES
+			(1, 'failed', 'failed to clone repository 42', 3, 1, NOW() - '5 minute'::interval),
+			(2, 'failed', 'failed to clone repository 1337', 3, 0, NOW() - '4 minute'::interval),
+			(3, 'failed', 'out of memory', 1, 0, NOW() - '3 minute'::interval),
+			(4, 'errored', 'failed to clone repository 7', 1, 0, NOW() - '2 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	failureMessage := "failed to clone repository <n>"
+	count, err := testStore(db, defaultTestStoreOptions(nil, testScanRecord)).RequeueFailed(context.Background(), DeadLetterFilter{FailureMessage: &failureMessage})
+	if err != nil {
+		t.Fatalf("unexpected error requeueing records: %s", err)
+	}
+	if count != 2 {
+		t.Errorf("unexpected count. want=%d have=%d", 2, count)
+	}
+
+	rows, err := db.QueryContext(context.Background(), `SELECT id, state, failure_message IS NULL, num_failures, num_resets, finished_at IS NULL FROM workerutil_test ORDER BY id`)
+	if err != nil {
+		t.Fatalf("unexpected error querying records: %s", err)
+	}
+	defer rows.Close()
+
+	type result struct {
+		ID                     int
+		State                  string
+		FailureMessageCleared  bool
+		NumFailures, NumResets int
+		FinishedAtCleared      bool
+	}
+	var results []result
+	for rows.Next() {
+		var r result
+		if err := rows.Scan(&r.ID, &r.State, &r.FailureMessageCleared, &r.NumFailures, &r.NumResets, &r.FinishedAtCleared); err != nil {
+			t.Fatalf("unexpected error scanning record: %s", err)
+		}
+		results = append(results, r)
+	}
+
+	expected := []result{
+		{ID: 1, State: "queued", FailureMessageCleared: true, FinishedAtCleared: true},
+		{ID: 2, State: "queued", FailureMessageCleared: true, FinishedAtCleared: true},
+		{ID: 3, State: "failed", NumFailures: 1},
+		{ID: 4, State: "errored", NumFailures: 1},
+	}
+	if diff := cmp.Diff(expected, results); diff != "" {
+		t.Errorf("unexpected records (-want +got):\n%s", diff)
+	}
+}
+
+func TestStoreDeleteFailed(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, failure_message, finished_at)
+		VALUES
+			(1, 'failed', 'out of memory', NOW() - '5 minute'::interval),
+			(2, 'failed', 'out of memory', NOW() - '4 minute'::interval),
+			(3, 'completed', NULL, NOW() - '3 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	count, err := testDeadLetterStore(db, defaultTestStoreOptions(nil, testScanRecord), DeadLetterOptions{DeleteRows: true}).DeleteFailed(context.Background(), DeadLetterFilter{IDs: []int{2, 3}})
+	if err != nil {
+		t.Fatalf("unexpected error deleting records: %s", err)
+	}
+	if count != 1 {
+		t.Errorf("unexpected count. want=%d have=%d", 1, count)
+	}
+
+	var ids []int
+	rows, err := db.QueryContext(context.Background(), `SELECT id FROM workerutil_test ORDER BY id`)
+	if err != nil {
+		t.Fatalf("unexpected error querying records: %s", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("unexpected error scanning record: %s", err)
+		}
+		ids = append(ids, id)
+	}
+	if diff := cmp.Diff([]int{1, 3}, ids); diff != "" {
+		t.Errorf("unexpected remaining records (-want +got):\n%s", diff)
+	}
+}
+
+func TestStoreDeleteFailedHook(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, failure_message, finished_at)
+		VALUES
+			(1, 'failed', 'out of memory', NOW() - '5 minute'::interval),
+			(2, 'failed', 'out of memory', NOW() - '4 minute'::interval),
+			(3, 'completed', NULL, NOW() - '3 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	var deletedIDs []int
+	store := testDeadLetterStore(db, defaultTestStoreOptions(nil, testScanRecord), DeadLetterOptions{
+		DeleteRows: true,
+		DeleteRecords: func(ctx context.Context, ids []int) (int, error) {
+			deletedIDs = append(deletedIDs, ids...)
+			return len(ids), nil
+		},
+	})
+
+	count, err := store.DeleteFailed(context.Background(), DeadLetterFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error deleting records: %s", err)
+	}
+	if count != 2 {
+		t.Errorf("unexpected count. want=%d have=%d", 2, count)
+	}
+	if diff := cmp.Diff([]int{1, 2}, deletedIDs); diff != "" {
+		t.Errorf("unexpected deleted records (-want +got):\n%s", diff)
+	}
+
+	// The hook takes precedence, so the rows are left alone.
+	var numRecords int
+	if err := db.QueryRowContext(context.Background(), `SELECT COUNT(*) FROM workerutil_test`).Scan(&numRecords); err != nil {
+		t.Fatalf("unexpected error counting records: %s", err)
+	}
+	if numRecords != 3 {
+		t.Errorf("unexpected number of records. want=%d have=%d", 3, numRecords)
+	}
+}
+
+func TestStoreDeleteFailedUnsupported(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, failure_message, finished_at)
+		VALUES (1, 'failed', 'out of memory', NOW() - '5 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	store := testDeadLetterStore(db, defaultTestStoreOptions(nil, testScanRecord), DeadLetterOptions{})
+	if store.CanDelete() {
+		t.Errorf("expected store not to support deleting records")
+	}
+	if _, err := store.DeleteFailed(context.Background(), DeadLetterFilter{}); !errors.Is(err, ErrDeadLetterDeleteUnsupported) {
+		t.Errorf("unexpected error. want=%q have=%q", ErrDeadLetterDeleteUnsupported, err)
+	}
+}
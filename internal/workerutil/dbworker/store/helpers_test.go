@@ -24,6 +24,10 @@ func testStore[T workerutil.Record](db *sql.DB, options Options[T]) *store[T] {
 	return newStore(&observation.TestContext, basestore.NewHandleWithDB(log.NoOp(), db, sql.TxOptions{}), options)
 }
 
+func testDeadLetterStore[T workerutil.Record](db *sql.DB, options Options[T], deadLetterOptions DeadLetterOptions) DeadLetterStore {
+	return NewDeadLetterStore(&observation.TestContext, basestore.NewHandleWithDB(log.NoOp(), db, sql.TxOptions{}), options, deadLetterOptions)
+}
+
 type TestRecord struct {
 	ID            int
 	State         string
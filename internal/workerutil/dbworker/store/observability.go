@@ -21,6 +21,10 @@ type operations struct {
 	resetStalled            *observation.Operation
 	updateExecutionLogEntry *observation.Operation
 	canceledJobs            *observation.Operation
+	failureGroups           *observation.Operation
+	failedRecords           *observation.Operation
+	requeueFailed           *observation.Operation
+	deleteFailed            *observation.Operation
 }
 
 // as newOperations changes based on the store name passed in, and a dbworker store
@@ -70,5 +74,9 @@ func newOperations(observationCtx *observation.Context, storeName string) *opera
 		resetStalled:            op("ResetStalled"),
 		updateExecutionLogEntry: op("UpdateExecutionLogEntry"),
 		canceledJobs:            op("CanceledJobs"),
+		failureGroups:           op("FailureGroups"),
+		failedRecords:           op("FailedRecords"),
+		requeueFailed:           op("RequeueFailed"),
+		deleteFailed:            op("DeleteFailed"),
 	}
 }
@@ -209,6 +209,25 @@ type Options[T workerutil.Record] struct {
 	// expressions may use the alias provided in `ViewName`, if one was supplied.
 	ColumnExpressions []*sqlf.Query
 
+	// FairnessKeyExpression is an optional SQL expression grouping records by the entity that enqueued
+	// them, such as a repository or a namespace. When supplied, `Dequeue` alternates between groups
+	// instead of strictly following `OrderByExpression`, preferring the groups with the fewest records
+	// currently processing, so that a single group enqueueing a large number of records does not starve
+	// all others. Records within a group are still dequeued in the order of `OrderByExpression`. This
+	// expression may use the alias provided in `ViewName`, if one was supplied.
+	//
+	// Only the first few dequeueable records of each group are ranked, but finding the groups with
+	// dequeueable records reads the fairness key of all of them, so an index on the state column and
+	// one on the columns referenced by this expression are strongly recommended.
+	FairnessKeyExpression *sqlf.Query
+
+	// MaxProcessingPerKey is the maximum number of records sharing the same fairness key that can be
+	// processing at once. Records of groups at this limit are skipped by `Dequeue`. Setting this value
+	// to zero disables the limit. This value is ignored if `FairnessKeyExpression` is not supplied.
+	// Enforcing the limit takes an advisory lock on the group of the dequeued record, so concurrent
+	// dequeues of records of the same group are serialized.
+	MaxProcessingPerKey int
+
 	// StalledMaxAge is the maximum allowed duration between heartbeat updates of a job's last_heartbeat_at
 	// field. An unmodified row that is marked as processing likely indicates that the worker that dequeued
 	// the record has died.
@@ -492,16 +511,34 @@ func (s *store[T]) Dequeue(ctx context.Context, workerHostname string, condition
 		s.columnReplacer.Replace("{worker_hostname}"):   workerHostnameExpr,
 	}
 
-	records, err := s.options.Scan(s.Query(ctx, s.formatQuery(
+	potentialCandidates := s.makePotentialCandidatesQuery(now, retryAfter, conditions)
+
+	var records []T
+	if s.options.FairnessKeyExpression != nil && s.options.MaxProcessingPerKey > 0 {
+		records, err = s.dequeueWithinProcessingLimit(ctx, potentialCandidates, updatedColumns)
+	} else {
+		records, err = s.dequeueCandidate(ctx, s.Store, potentialCandidates, updatedColumns)
+	}
+	if err != nil {
+		return ret, false, err
+	}
+	if len(records) > 1 {
+		return ret, false, errors.Newf("more than one record dequeued: %d", len(records))
+	}
+	if len(records) == 0 {
+		return ret, false, nil
+	}
+	trace.AddEvent("TODO Domain Owner", attribute.Int("recordID", records[0].RecordID()))
+
+	return records[0], true, nil
+}
+
+// dequeueCandidate marks the first unlocked record of the given potential candidates as processing
+// and returns it.
+func (s *store[T]) dequeueCandidate(ctx context.Context, db *basestore.Store, potentialCandidates *sqlf.Query, updatedColumns map[string]*sqlf.Query) ([]T, error) {
+	return s.options.Scan(db.Query(ctx, s.formatQuery(
 		dequeueQuery,
-		s.options.OrderByExpression,
-		quote(s.options.ViewName),
-		now,
-		retryAfter,
-		now,
-		retryAfter,
-		makeConditionSuffix(conditions),
-		s.options.OrderByExpression,
+		potentialCandidates,
 		quote(s.options.TableName),
 		quote(s.options.TableName),
 		quote(s.options.TableName),
@@ -509,41 +546,92 @@ func (s *store[T]) Dequeue(ctx context.Context, workerHostname string, condition
 		sqlf.Join(s.makeDequeueSelectExpressions(updatedColumns), ", "),
 		quote(s.options.ViewName),
 	)))
+}
+
+// dequeueWithinProcessingLimit dequeues a record without exceeding `MaxProcessingPerKey`. The
+// processing records of a group counted by concurrent dequeues can't include the records the
+// others are about to dequeue, so both could exceed the limit. Instead, the candidate and an
+// advisory lock on its group are taken in a transaction, and the limit is checked again by a
+// statement that starts after the lock was acquired, which sees the records dequeued by the
+// previous holders of the lock. If another dequeue took the group's last slot in the meantime, no
+// record is dequeued and the next dequeue tries again.
+func (s *store[T]) dequeueWithinProcessingLimit(ctx context.Context, potentialCandidates *sqlf.Query, updatedColumns map[string]*sqlf.Query) (_ []T, err error) {
+	tx, err := s.Store.Transact(ctx)
 	if err != nil {
-		return ret, false, err
+		return nil, err
 	}
-	if len(records) > 1 {
-		return ret, false, errors.Newf("more than one record dequeued: %d", len(records))
+	defer func() { err = tx.Done(err) }()
+
+	candidateID, ok, err := basestore.ScanFirstInt(tx.Query(ctx, s.formatQuery(
+		lockCandidateQuery,
+		potentialCandidates,
+		quote(s.options.TableName),
+		quote(s.options.TableName),
+	)))
+	if err != nil || !ok {
+		return nil, err
 	}
-	if len(records) == 0 {
-		return ret, false, nil
+
+	if err := tx.Exec(ctx, s.formatQuery(
+		lockFairnessKeyQuery,
+		s.options.TableName,
+		s.options.FairnessKeyExpression,
+		quote(s.options.ViewName),
+		candidateID,
+	)); err != nil {
+		return nil, err
 	}
-	trace.AddEvent("TODO Domain Owner", attribute.Int("recordID", records[0].RecordID()))
 
-	return records[0], true, nil
+	return s.dequeueCandidate(ctx, tx, s.formatQuery(
+		recheckedCandidateQuery,
+		s.options.FairnessKeyExpression,
+		quote(s.options.ViewName),
+		candidateID,
+		quote(s.options.ViewName),
+		s.options.FairnessKeyExpression,
+		s.options.MaxProcessingPerKey,
+	), updatedColumns)
 }
 
-const dequeueQuery = `
-WITH potential_candidates AS (
+const lockCandidateQuery = `
+WITH %s
+SELECT
+	{id} FROM %s
+JOIN potential_candidates pc ON pc.candidate_id = {id}
+WHERE
+	-- Recheck state.
+	{state} IN ('queued', 'errored')
+ORDER BY pc.order
+FOR UPDATE OF %s SKIP LOCKED
+LIMIT 1
+`
+
+const lockFairnessKeyQuery = `
+SELECT pg_advisory_xact_lock(hashtext(%s), hashtext(COALESCE((%s)::text, '')))
+FROM %s
+WHERE {id} = %s
+`
+
+const recheckedCandidateQuery = `
+potential_candidates AS (
 	SELECT
-		{id} AS candidate_id,
-		ROW_NUMBER() OVER (ORDER BY %s) AS order
-	FROM %s
-	WHERE
-		(
-			(
-				{state} = 'queued' AND
-				({process_after} IS NULL OR {process_after} <= %s)
-			) OR (
-				%s > 0 AND
-				{state} = 'errored' AND
-				%s - {finished_at} > (%s * '1 second'::interval)
-			)
-		)
-		%s
-	ORDER BY %s
-	LIMIT 50
-),
+		candidate.id AS candidate_id,
+		1 AS order
+	FROM (
+		SELECT {id} AS id, %s AS fairness_key
+		FROM %s
+		WHERE {id} = %s
+	) candidate
+	WHERE (
+		SELECT COUNT(*)
+		FROM %s
+		WHERE {state} = 'processing' AND %s IS NOT DISTINCT FROM candidate.fairness_key
+	) < %s
+)
+`
+
+const dequeueQuery = `
+WITH %s,
 candidate AS (
 	SELECT
 		{id} FROM %s
@@ -571,6 +659,131 @@ WHERE
 	{id} IN (SELECT {id} FROM candidate)
 `
 
+// makePotentialCandidatesQuery constructs the common table expressions selecting the set of
+// records the dequeue query picks a candidate from, in order of preference. If a fairness key
+// is configured, records are ranked within their group and groups are interleaved.
+func (s *store[T]) makePotentialCandidatesQuery(now time.Time, retryAfter int, conditions []*sqlf.Query) *sqlf.Query {
+	dequeueableCondition := s.formatQuery(dequeueableConditionQuery, now, retryAfter, now, retryAfter)
+
+	if s.options.FairnessKeyExpression == nil {
+		return s.formatQuery(
+			potentialCandidatesQuery,
+			s.options.OrderByExpression,
+			quote(s.options.ViewName),
+			dequeueableCondition,
+			makeConditionSuffix(conditions),
+			s.options.OrderByExpression,
+		)
+	}
+
+	return s.formatQuery(
+		fairPotentialCandidatesQuery,
+		s.options.FairnessKeyExpression,
+		quote(s.options.ViewName),
+		s.options.FairnessKeyExpression,
+		quote(s.options.ViewName),
+		dequeueableCondition,
+		makeConditionSuffix(conditions),
+		s.options.OrderByExpression,
+		quote(s.options.ViewName),
+		s.options.FairnessKeyExpression,
+		s.options.FairnessKeyExpression,
+		dequeueableCondition,
+		makeConditionSuffix(conditions),
+		s.options.OrderByExpression,
+		fairCandidatesPerKey,
+		s.options.MaxProcessingPerKey,
+		s.options.MaxProcessingPerKey,
+		s.options.OrderByExpression,
+		quote(s.options.ViewName),
+		s.options.OrderByExpression,
+	)
+}
+
+// fairCandidatesPerKey is the number of dequeueable records of each group that are ranked by
+// the fair dequeue query. Only the first records of a group can be preferred over the records
+// of other groups, so the remaining ones don't need to be ranked.
+const fairCandidatesPerKey = 10
+
+const dequeueableConditionQuery = `
+(
+	(
+		{state} = 'queued' AND
+		({process_after} IS NULL OR {process_after} <= %s)
+	) OR (
+		%s > 0 AND
+		{state} = 'errored' AND
+		%s - {finished_at} > (%s * '1 second'::interval)
+	)
+)
+`
+
+const potentialCandidatesQuery = `
+potential_candidates AS (
+	SELECT
+		{id} AS candidate_id,
+		ROW_NUMBER() OVER (ORDER BY %s) AS order
+	FROM %s
+	WHERE
+		%s
+		%s
+	ORDER BY %s
+	LIMIT 50
+)
+`
+
+// fairPotentialCandidatesQuery ranks the first dequeueable records of each group by the number
+// of records of the group that are processing plus the record's position within the group.
+// Dequeueing by this rank evens out the number of processing records across groups, which
+// round-robins between groups with queued records. Ties are broken by the configured order.
+// Records whose rank exceeds the per-group limit would exceed it once dequeued, and are skipped.
+const fairPotentialCandidatesQuery = `
+processing_counts AS (
+	SELECT
+		%s AS fairness_key,
+		COUNT(*) AS count
+	FROM %s
+	WHERE {state} = 'processing'
+	GROUP BY 1
+),
+fairness_keys AS (
+	SELECT DISTINCT %s AS fairness_key
+	FROM %s
+	WHERE
+		%s
+		%s
+),
+ranked_candidates AS (
+	SELECT
+		c.candidate_id,
+		COALESCE(processing_counts.count, 0) + c.key_order AS fair_order
+	FROM fairness_keys
+	LEFT JOIN processing_counts ON processing_counts.fairness_key IS NOT DISTINCT FROM fairness_keys.fairness_key
+	JOIN LATERAL (
+		SELECT
+			{id} AS candidate_id,
+			ROW_NUMBER() OVER (ORDER BY %s) AS key_order
+		FROM %s
+		WHERE
+			(%s = fairness_keys.fairness_key OR (%s IS NULL AND fairness_keys.fairness_key IS NULL)) AND
+			%s
+			%s
+		ORDER BY %s
+		LIMIT %s
+	) c ON TRUE
+	WHERE %s = 0 OR COALESCE(processing_counts.count, 0) + c.key_order <= %s
+),
+potential_candidates AS (
+	SELECT
+		rc.candidate_id,
+		ROW_NUMBER() OVER (ORDER BY rc.fair_order, %s) AS order
+	FROM ranked_candidates rc
+	JOIN %s ON {id} = rc.candidate_id
+	ORDER BY rc.fair_order, %s
+	LIMIT 50
+)
+`
+
 // makeDequeueSelectExpressions constructs the ordered set of SQL expressions that are returned
 // from the dequeue query. This method returns a copy of the configured column expressions slice
 // where expressions referencing one of the column updated by dequeue are replaced by the updated
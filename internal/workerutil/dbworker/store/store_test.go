@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -349,6 +350,122 @@ func TestStoreDequeueConcurrent(t *testing.T) {
 	}
 }
 
+func TestStoreDequeueFairness(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, created_at)
+		VALUES
+			(11, 'queued', NOW() - '5 minute'::interval),
+			(12, 'queued', NOW() - '4 minute'::interval),
+			(13, 'queued', NOW() - '3 minute'::interval),
+			(21, 'queued', NOW() - '2 minute'::interval),
+			(31, 'queued', NOW() - '1 minute'::interval),
+			(32, 'processing', NOW() - '6 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil, testScanRecord)
+	// Records 11-13, 21 and 31-32 belong to three different groups.
+	options.FairnessKeyExpression = sqlf.Sprintf("workerutil_test.id / 10")
+	store := testStore(db, options)
+
+	// Groups take turns, starting with groups without processing records. Within a group,
+	// the oldest record is dequeued first.
+	for _, expectedID := range []int{11, 21, 12, 31, 13} {
+		record, ok, err := store.Dequeue(context.Background(), "test", nil)
+		assertDequeueRecordResult(t, expectedID, record, ok, err)
+	}
+
+	if _, ok, _ := store.Dequeue(context.Background(), "test", nil); ok {
+		t.Fatalf("did not expect another dequeueable record")
+	}
+}
+
+func TestStoreDequeueFairnessMaxProcessingPerKey(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, created_at)
+		VALUES
+			(11, 'queued', NOW() - '5 minute'::interval),
+			(12, 'queued', NOW() - '4 minute'::interval),
+			(21, 'queued', NOW() - '3 minute'::interval),
+			(22, 'queued', NOW() - '2 minute'::interval),
+			(31, 'queued', NOW() - '1 minute'::interval),
+			(32, 'processing', NOW() - '6 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil, testScanRecord)
+	options.FairnessKeyExpression = sqlf.Sprintf("workerutil_test.id / 10")
+	options.MaxProcessingPerKey = 1
+	store := testStore(db, options)
+
+	// The group of record 31 is already at the limit.
+	for _, expectedID := range []int{11, 21} {
+		record, ok, err := store.Dequeue(context.Background(), "test", nil)
+		assertDequeueRecordResult(t, expectedID, record, ok, err)
+	}
+
+	if _, ok, _ := store.Dequeue(context.Background(), "test", nil); ok {
+		t.Fatalf("did not expect another dequeueable record")
+	}
+
+	if _, err := db.ExecContext(context.Background(), `UPDATE workerutil_test SET state = 'completed' WHERE id = 11`); err != nil {
+		t.Fatalf("unexpected error updating record: %s", err)
+	}
+
+	record, ok, err := store.Dequeue(context.Background(), "test", nil)
+	assertDequeueRecordResult(t, 12, record, ok, err)
+}
+
+func TestStoreDequeueFairnessMaxProcessingPerKeyConcurrent(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, created_at)
+		SELECT id, 'queued', NOW() - (id || ' minute')::interval
+		FROM generate_series(10, 19) AS id
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil, testScanRecord)
+	options.FairnessKeyExpression = sqlf.Sprintf("workerutil_test.id / 10")
+	options.MaxProcessingPerKey = 1
+	store := testStore(db, options)
+
+	// All records belong to the same group, so only one of the concurrent dequeues may succeed.
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		dequeued int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, ok, err := store.Dequeue(context.Background(), "test", nil)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if ok {
+				mu.Lock()
+				dequeued++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if dequeued != 1 {
+		t.Errorf("unexpected number of dequeued records. want=%d have=%d", 1, dequeued)
+	}
+}
+
 func TestStoreDequeueRetryAfter(t *testing.T) {
 	db := setupStoreTest(t)
 
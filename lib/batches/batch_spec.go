@@ -3,6 +3,7 @@ package batches
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/lib/batches/env"
 	"github.com/sourcegraph/sourcegraph/lib/batches/overridable"
@@ -31,6 +32,9 @@ import (
 type BatchSpec struct {
 	Name              string                   `json:"name,omitempty" yaml:"name"`
 	Description       string                   `json:"description,omitempty" yaml:"description"`
+	Schedule          string                   `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	AutoMerge         *AutoMerge               `json:"autoMerge,omitempty" yaml:"autoMerge,omitempty"`
+	Dependencies      []ChangesetDependency    `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
 	On                []OnQueryOrRepository    `json:"on,omitempty" yaml:"on"`
 	Workspaces        []WorkspaceConfiguration `json:"workspaces,omitempty"  yaml:"workspaces"`
 	Steps             []Step                   `json:"steps,omitempty" yaml:"steps"`
@@ -44,9 +48,54 @@ type ChangesetTemplate struct {
 	Body      string                       `json:"body,omitempty" yaml:"body"`
 	Branch    string                       `json:"branch,omitempty" yaml:"branch"`
 	Commit    ExpandedGitCommitDescription `json:"commit,omitempty" yaml:"commit"`
+	Reviewers *ChangesetReviewers          `json:"reviewers,omitempty" yaml:"reviewers,omitempty"`
 	Published *overridable.BoolOrString    `json:"published" yaml:"published"`
 }
 
+// ChangesetReviewers are the reviewers a review is requested from once a
+// changeset is published.
+type ChangesetReviewers struct {
+	// Users are the usernames of users on the code host.
+	Users []string `json:"users,omitempty" yaml:"users,omitempty"`
+	// Teams are teams on the code host, as organization/team.
+	Teams []string `json:"teams,omitempty" yaml:"teams,omitempty"`
+	// FromCodeOwners requests a review from the owners of the changed files,
+	// as defined in the CODEOWNERS file of the repository.
+	FromCodeOwners bool `json:"fromCodeOwners,omitempty" yaml:"fromCodeOwners,omitempty"`
+}
+
+// AutoMerge configures the automatic merge of the changesets of a batch change
+// once their checks passed, they have been approved and they can be merged.
+type AutoMerge struct {
+	Strategy string `json:"strategy,omitempty" yaml:"strategy"`
+}
+
+// ChangesetDependency declares that the changesets in a repository, or the
+// changeset of a single workspace in it, must not be published until the
+// changesets they depend on have been merged.
+type ChangesetDependency struct {
+	Repository string `json:"repository" yaml:"repository"`
+	// Branch is the branch of the changeset of the workspace that depends on
+	// others, since each workspace of a repository has its own changeset
+	// branch. If it's empty, all changesets in the repository depend on others.
+	Branch    string                `json:"branch,omitempty" yaml:"branch,omitempty"`
+	DependsOn []DependencyWorkspace `json:"dependsOn" yaml:"dependsOn"`
+}
+
+// DependencyWorkspace identifies the changesets of a batch change in a
+// repository, or the changeset of a single workspace in it if Branch is set.
+type DependencyWorkspace struct {
+	Repository string `json:"repository" yaml:"repository"`
+	Branch     string `json:"branch,omitempty" yaml:"branch,omitempty"`
+}
+
+func (w DependencyWorkspace) String() string {
+	if w.Branch == "" {
+		return w.Repository
+	}
+	return w.Repository + "@" + w.Branch
+}
+
 type GitCommitAuthor struct {
 	Name  string `json:"name" yaml:"name"`
 	Email string `json:"email" yaml:"email"`
@@ -165,6 +214,16 @@ func parseBatchSpec(schema string, data []byte) (*BatchSpec, error) {
 		errs = errors.Append(errs, NewValidationError(errors.New("batch spec includes steps but no changesetTemplate")))
 	}
 
+	if spec.Schedule != "" {
+		if _, err := spec.ScheduleInterval(); err != nil {
+			errs = errors.Append(errs, NewValidationError(err))
+		}
+	}
+
+	if err := validateDependencies(spec.Dependencies); err != nil {
+		errs = errors.Append(errs, NewValidationError(err))
+	}
+
 	for i, step := range spec.Steps {
 		for _, mount := range step.Mount {
 			if strings.Contains(mount.Path, invalidMountCharacters) {
@@ -181,6 +240,84 @@ func parseBatchSpec(schema string, data []byte) (*BatchSpec, error) {
 
 const invalidMountCharacters = ","
 
+// MinScheduleInterval is the shortest interval at which a batch change can be
+// scheduled to re-run.
+const MinScheduleInterval = time.Hour
+
+// ScheduleInterval returns the interval at which the batch change is re-run
+// after it has been applied, or zero if the batch spec has no schedule.
+func (s *BatchSpec) ScheduleInterval() (time.Duration, error) {
+	if s.Schedule == "" {
+		return 0, nil
+	}
+
+	interval, err := time.ParseDuration(s.Schedule)
+	if err != nil {
+		return 0, errors.Wrap(err, "parsing schedule")
+	}
+	if interval < MinScheduleInterval {
+		return 0, errors.Newf("schedule %q is shorter than the minimum interval of %s", s.Schedule, MinScheduleInterval)
+	}
+
+	return interval, nil
+}
+
+// validateDependencies returns an error if a repository or workspace depends
+// on itself, directly or through others, since its changesets could never be
+// published.
+func validateDependencies(deps []ChangesetDependency) error {
+	// A workspace depends on what's declared for it and for its whole
+	// repository, while a whole repository depends on what's declared for any
+	// of its workspaces.
+	declared := make(map[DependencyWorkspace][]DependencyWorkspace, len(deps))
+	byRepo := make(map[string][]DependencyWorkspace, len(deps))
+	for _, dep := range deps {
+		w := DependencyWorkspace{Repository: dep.Repository, Branch: dep.Branch}
+		declared[w] = append(declared[w], dep.DependsOn...)
+		byRepo[dep.Repository] = append(byRepo[dep.Repository], dep.DependsOn...)
+	}
+	dependsOn := func(w DependencyWorkspace) []DependencyWorkspace {
+		if w.Branch == "" {
+			return byRepo[w.Repository]
+		}
+		return append(append([]DependencyWorkspace{}, declared[w]...), declared[DependencyWorkspace{Repository: w.Repository}]...)
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[DependencyWorkspace]int, len(declared))
+	var path []string
+	var visit func(w DependencyWorkspace) error
+	visit = func(w DependencyWorkspace) error {
+		switch state[w] {
+		case visiting:
+			return errors.Newf("dependencies contain a cycle: %s -> %s", strings.Join(path, " -> "), w)
+		case visited:
+			return nil
+		}
+
+		state[w] = visiting
+		path = append(path, w.String())
+		for _, next := range dependsOn(w) {
+			if err := visit(next); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[w] = visited
+		return nil
+	}
+
+	for _, dep := range deps {
+		if err := visit(DependencyWorkspace{Repository: dep.Repository, Branch: dep.Branch}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (on *OnQueryOrRepository) String() string {
 	if on.RepositoriesMatchingQuery != "" {
 		return on.RepositoriesMatchingQuery
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
@@ -169,6 +170,200 @@ changesetTemplate:
 		}
 	})
 
+	t.Run("schedule", func(t *testing.T) {
+		const specTemplate = `
+name: hello-world
+description: Add Hello World to READMEs
+schedule: %s
+on:
+  - repositoriesMatchingQuery: file:README.md
+steps:
+  - run: echo Hello World | tee -a $(find -name README.md)
+    container: alpine:3
+changesetTemplate:
+  title: Hello World
+  body: My first batch change!
+  branch: hello-world
+  commit:
+    message: Append Hello World to all README.md files
+`
+
+		batchSpec, err := ParseBatchSpec([]byte(fmt.Sprintf(specTemplate, "24h")))
+		if err != nil {
+			t.Fatalf("parsing valid spec returned error: %s", err)
+		}
+		interval, err := batchSpec.ScheduleInterval()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 24*time.Hour, interval)
+
+		_, err = ParseBatchSpec([]byte(fmt.Sprintf(specTemplate, "30m")))
+		assert.Equal(t, `schedule "30m" is shorter than the minimum interval of 1h0m0s`, err.Error())
+
+		_, err = ParseBatchSpec([]byte(fmt.Sprintf(specTemplate, "daily")))
+		assert.Equal(t, "schedule: Does not match pattern '^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$'", err.Error())
+	})
+
+	t.Run("autoMerge", func(t *testing.T) {
+		const specTemplate = `
+name: hello-world
+description: Add Hello World to READMEs
+autoMerge:
+  strategy: %s
+on:
+  - repositoriesMatchingQuery: file:README.md
+steps:
+  - run: echo Hello World | tee -a $(find -name README.md)
+    container: alpine:3
+changesetTemplate:
+  title: Hello World
+  body: My first batch change!
+  branch: hello-world
+  commit:
+    message: Append Hello World to all README.md files
+`
+
+		batchSpec, err := ParseBatchSpec([]byte(fmt.Sprintf(specTemplate, "squash")))
+		if err != nil {
+			t.Fatalf("parsing valid spec returned error: %s", err)
+		}
+		assert.Equal(t, &AutoMerge{Strategy: "squash"}, batchSpec.AutoMerge)
+
+		_, err = ParseBatchSpec([]byte(fmt.Sprintf(specTemplate, "octopus")))
+		assert.Error(t, err)
+	})
+
+	t.Run("changesetTemplate reviewers", func(t *testing.T) {
+		const specTemplate = `
+name: hello-world
+description: Add Hello World to READMEs
+on:
+  - repositoriesMatchingQuery: file:README.md
+steps:
+  - run: echo Hello World | tee -a $(find -name README.md)
+    container: alpine:3
+changesetTemplate:
+  title: Hello World
+  body: My first batch change!
+  branch: hello-world
+  commit:
+    message: Append Hello World to all README.md files
+  reviewers:
+%s
+`
+
+		batchSpec, err := ParseBatchSpec([]byte(fmt.Sprintf(specTemplate, `    users: [alice]
+    teams: [sourcegraph/batchers]
+    fromCodeOwners: true`)))
+		if err != nil {
+			t.Fatalf("parsing valid spec returned error: %s", err)
+		}
+		assert.Equal(t, &ChangesetReviewers{
+			Users:          []string{"alice"},
+			Teams:          []string{"sourcegraph/batchers"},
+			FromCodeOwners: true,
+		}, batchSpec.ChangesetTemplate.Reviewers)
+
+		_, err = ParseBatchSpec([]byte(fmt.Sprintf(specTemplate, "    teams: [batchers]")))
+		assert.Error(t, err)
+	})
+
+	t.Run("dependencies", func(t *testing.T) {
+		const specTemplate = `
+name: hello-world
+description: Add Hello World to READMEs
+on:
+  - repositoriesMatchingQuery: file:README.md
+steps:
+  - run: echo Hello World | tee -a $(find -name README.md)
+    container: alpine:3
+changesetTemplate:
+  title: Hello World
+  body: My first batch change!
+  branch: hello-world
+  commit:
+    message: Append Hello World to all README.md files
+dependencies:
+%s
+`
+
+		batchSpec, err := ParseBatchSpec([]byte(fmt.Sprintf(specTemplate, `  - repository: github.com/sourcegraph/sourcegraph
+    dependsOn:
+      - repository: github.com/sourcegraph/log
+      - repository: github.com/sourcegraph/go-diff
+  - repository: github.com/sourcegraph/log
+    dependsOn:
+      - repository: github.com/sourcegraph/go-diff
+  - repository: github.com/sourcegraph/sourcegraph
+    branch: hello-world/client/web
+    dependsOn:
+      - repository: github.com/sourcegraph/sourcegraph
+        branch: hello-world/client/shared`)))
+		if err != nil {
+			t.Fatalf("parsing valid spec returned error: %s", err)
+		}
+		assert.Equal(t, []ChangesetDependency{
+			{Repository: "github.com/sourcegraph/sourcegraph", DependsOn: []DependencyWorkspace{{Repository: "github.com/sourcegraph/log"}, {Repository: "github.com/sourcegraph/go-diff"}}},
+			{Repository: "github.com/sourcegraph/log", DependsOn: []DependencyWorkspace{{Repository: "github.com/sourcegraph/go-diff"}}},
+			{Repository: "github.com/sourcegraph/sourcegraph", Branch: "hello-world/client/web", DependsOn: []DependencyWorkspace{{Repository: "github.com/sourcegraph/sourcegraph", Branch: "hello-world/client/shared"}}},
+		}, batchSpec.Dependencies)
+
+		for name, tc := range map[string]struct {
+			dependencies string
+			wantErr      string
+		}{
+			"repository depends on itself": {
+				dependencies: `  - repository: github.com/sourcegraph/sourcegraph
+    dependsOn:
+      - repository: github.com/sourcegraph/sourcegraph`,
+				wantErr: "dependencies contain a cycle: github.com/sourcegraph/sourcegraph -> github.com/sourcegraph/sourcegraph",
+			},
+			"repositories depend on each other": {
+				dependencies: `  - repository: github.com/sourcegraph/sourcegraph
+    dependsOn:
+      - repository: github.com/sourcegraph/log
+  - repository: github.com/sourcegraph/log
+    dependsOn:
+      - repository: github.com/sourcegraph/sourcegraph`,
+				wantErr: "dependencies contain a cycle: github.com/sourcegraph/sourcegraph -> github.com/sourcegraph/log -> github.com/sourcegraph/sourcegraph",
+			},
+			"workspaces depend on each other": {
+				dependencies: `  - repository: github.com/sourcegraph/sourcegraph
+    branch: hello-world/client/web
+    dependsOn:
+      - repository: github.com/sourcegraph/sourcegraph
+        branch: hello-world/client/shared
+  - repository: github.com/sourcegraph/sourcegraph
+    branch: hello-world/client/shared
+    dependsOn:
+      - repository: github.com/sourcegraph/sourcegraph
+        branch: hello-world/client/web`,
+				wantErr: "dependencies contain a cycle: github.com/sourcegraph/sourcegraph@hello-world/client/web -> github.com/sourcegraph/sourcegraph@hello-world/client/shared -> github.com/sourcegraph/sourcegraph@hello-world/client/web",
+			},
+			"workspace depends on its repository": {
+				dependencies: `  - repository: github.com/sourcegraph/log
+    dependsOn:
+      - repository: github.com/sourcegraph/sourcegraph
+  - repository: github.com/sourcegraph/sourcegraph
+    branch: hello-world/client/web
+    dependsOn:
+      - repository: github.com/sourcegraph/log`,
+				wantErr: "dependencies contain a cycle: github.com/sourcegraph/log -> github.com/sourcegraph/sourcegraph -> github.com/sourcegraph/log",
+			},
+			"no dependencies": {
+				dependencies: `  - repository: github.com/sourcegraph/sourcegraph
+    dependsOn: []`,
+				wantErr: "dependencies.0.dependsOn: Array must have at least 1 items",
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := ParseBatchSpec([]byte(fmt.Sprintf(specTemplate, tc.dependencies)))
+				assert.EqualError(t, err, tc.wantErr)
+			})
+		}
+	})
+
 	t.Run("mount path contains comma", func(t *testing.T) {
 		const spec = `
 name: test-spec
@@ -79,6 +79,8 @@ type ChangesetSpec struct {
 
 	Commits []GitCommitDescription `json:"commits,omitempty"`
 
+	Reviewers *ChangesetReviewers `json:"reviewers,omitempty"`
+
 	Published PublishedValue `json:"published,omitempty"`
 }
 
@@ -101,6 +103,7 @@ func (c *ChangesetSpec) MarshalJSON() ([]byte, error) {
 		Title          string                 `json:"title,omitempty"`
 		Body           string                 `json:"body,omitempty"`
 		Commits        []GitCommitDescription `json:"commits,omitempty"`
+		Reviewers      *ChangesetReviewers    `json:"reviewers,omitempty"`
 		Published      *PublishedValue        `json:"published,omitempty"`
 	}{
 		BaseRepository: c.BaseRepository,
@@ -112,6 +115,7 @@ func (c *ChangesetSpec) MarshalJSON() ([]byte, error) {
 		Title:          c.Title,
 		Body:           c.Body,
 		Commits:        c.Commits,
+		Reviewers:      c.Reviewers,
 	}
 	if !c.Published.Nil() {
 		v.Published = &c.Published
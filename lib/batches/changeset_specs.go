@@ -131,6 +131,7 @@ func BuildChangesetSpecs(input *ChangesetSpecInput, binaryDiffs bool, fallbackAu
 					Diff:        diff,
 				},
 			},
+			Reviewers: input.Template.Reviewers,
 			Published: PublishedValue{Val: published},
 		}
 	}
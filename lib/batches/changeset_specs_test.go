@@ -158,6 +158,27 @@ func TestCreateChangesetSpecs(t *testing.T) {
 			},
 			wantErr: "",
 		},
+		{
+			name: "reviewers",
+			input: inputWith(defaultInput, func(input *ChangesetSpecInput) {
+				input.Template.Reviewers = &ChangesetReviewers{
+					Users:          []string{"alice"},
+					Teams:          []string{"sourcegraph/batchers"},
+					FromCodeOwners: true,
+				}
+				input.Template.Published = parsePublishedFieldString(t, "false")
+			}),
+			want: []*ChangesetSpec{
+				specWith(defaultChangesetSpec, func(s *ChangesetSpec) {
+					s.Reviewers = &ChangesetReviewers{
+						Users:          []string{"alice"},
+						Teams:          []string{"sourcegraph/batchers"},
+						FromCodeOwners: true,
+					}
+				}),
+			},
+			wantErr: "",
+		},
 	}
 
 	for _, tt := range tests {
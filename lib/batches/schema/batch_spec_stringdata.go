@@ -21,6 +21,70 @@ const BatchSpecJSON = `{
       "type": "string",
       "description": "The description of the batch change."
     },
+    "schedule": {
+      "type": "string",
+      "description": "How often the batch change is re-run after it has been applied, as a duration such as 24h. Each run re-resolves the workspaces matched by the ` + "`" + `on` + "`" + ` property, executes the steps server-side in new or changed workspaces only, and applies the result, so that changesets are created for newly matching repositories and closed for repositories that no longer match. The minimum interval is 1h. Requires server-side execution.",
+      "pattern": "^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$",
+      "examples": ["24h", "168h"]
+    },
+    "autoMerge": {
+      "type": "object",
+      "description": "Automatically merge the changesets of the batch change once all of their checks passed, they have been approved and the code host considers them mergeable. Merges are subject to the rollout windows configured on the instance.",
+      "additionalProperties": false,
+      "required": ["strategy"],
+      "properties": {
+        "strategy": {
+          "type": "string",
+          "description": "The strategy used to merge the changesets. On GitLab, merge uses the merge method configured for the project. GitLab and Bitbucket Cloud don't support rebase. On Bitbucket Server, the strategy has to be enabled for the repository.",
+          "enum": ["merge", "squash", "rebase"]
+        }
+      }
+    },
+    "dependencies": {
+      "type": "array",
+      "description": "Dependencies between the changesets of the batch change. The changesets of a repository, or of a single workspace in it, are kept unpublished, or published as drafts on code hosts that support them, until the changesets they depend on have been merged.",
+      "items": {
+        "title": "ChangesetDependency",
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["repository", "dependsOn"],
+        "properties": {
+          "repository": {
+            "type": "string",
+            "description": "The name of the repository whose changesets depend on others.",
+            "examples": ["github.com/sourcegraph/sourcegraph"]
+          },
+          "branch": {
+            "type": "string",
+            "description": "The branch of the changeset of a single workspace in the repository that depends on others. Each workspace of a repository has its own changeset branch. If omitted, all changesets in the repository depend on others.",
+            "examples": ["batch-changes/client/web"]
+          },
+          "dependsOn": {
+            "type": "array",
+            "description": "The changesets that have to be merged first.",
+            "minItems": 1,
+            "items": {
+              "title": "DependencyWorkspace",
+              "type": "object",
+              "additionalProperties": false,
+              "required": ["repository"],
+              "properties": {
+                "repository": {
+                  "type": "string",
+                  "description": "The name of the repository whose changesets have to be merged first.",
+                  "examples": ["github.com/sourcegraph/log"]
+                },
+                "branch": {
+                  "type": "string",
+                  "description": "The branch of the changeset of a single workspace in the repository that has to be merged first. If omitted, all changesets in the repository have to be merged first.",
+                  "examples": ["batch-changes/client/shared"]
+                }
+              }
+            }
+          }
+        }
+      }
+    },
     "on": {
       "type": ["array", "null"],
       "description": "The set of repositories (and branches) to run the batch change on, specified as a list of search queries (that match repositories) and/or specific repositories.",
@@ -355,6 +419,30 @@ const BatchSpecJSON = `{
             }
           }
         },
+        "reviewers": {
+          "title": "ChangesetTemplateReviewers",
+          "type": "object",
+          "description": "The reviewers to request a review from once the changeset is published.",
+          "additionalProperties": false,
+          "properties": {
+            "users": {
+              "type": "array",
+              "description": "The usernames of the users on the code host to request a review from.",
+              "items": { "type": "string" },
+              "examples": [["alice", "bob"]]
+            },
+            "teams": {
+              "type": "array",
+              "description": "The teams on the code host to request a review from, as organization/team.",
+              "items": { "type": "string", "pattern": "^[^/]+/[^/]+$" },
+              "examples": [["sourcegraph/batch-changes"]]
+            },
+            "fromCodeOwners": {
+              "type": "boolean",
+              "description": "Whether to request a review from the owners of the changed files, as defined in the CODEOWNERS file of the repository."
+            }
+          }
+        },
         "published": {
           "description": "Whether to publish the changeset. An unpublished changeset can be previewed on Sourcegraph by any person who can view the batch change, but its commit, branch, and pull request aren't created on the code host. A published changeset results in a commit, branch, and pull request being created on the code host. If omitted, the publication state is controlled from the Batch Changes UI.",
           "oneOf": [
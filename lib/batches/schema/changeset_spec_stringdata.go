@@ -104,6 +104,30 @@ const ChangesetSpecJSON = `{
             }
           }
         },
+        "reviewers": {
+          "title": "ChangesetReviewers",
+          "type": "object",
+          "description": "The reviewers to request a review from once the changeset is published.",
+          "additionalProperties": false,
+          "properties": {
+            "users": {
+              "type": "array",
+              "description": "The usernames of the users on the code host to request a review from.",
+              "items": { "type": "string" },
+              "examples": [["alice", "bob"]]
+            },
+            "teams": {
+              "type": "array",
+              "description": "The teams on the code host to request a review from, as organization/team.",
+              "items": { "type": "string", "pattern": "^[^/]+/[^/]+$" },
+              "examples": [["sourcegraph/batch-changes"]]
+            },
+            "fromCodeOwners": {
+              "type": "boolean",
+              "description": "Whether to request a review from the owners of the changed files, as defined in the CODEOWNERS file of the repository."
+            }
+          }
+        },
         "published": {
           "oneOf": [{ "type": "boolean" }, { "type": "string", "pattern": "^draft$" }, { "type": "null" }],
           "description": "Whether to publish the changeset. An unpublished changeset can be previewed on Sourcegraph by any person who can view the batch change, but its commit, branch, and pull request aren't created on the code host. A published changeset results in a commit, branch, and pull request being created on the code host."
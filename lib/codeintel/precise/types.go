@@ -43,11 +43,12 @@ const (
 	Import         = "import"
 	Export         = "export"
 	Implementation = "implementation"
+	TypeDefinition = "typeDefinition"
 )
 
 // MonikerData represent a unique name (eventually) attached to a range.
 type MonikerData struct {
-	Kind                 string // local, import, export, implementation
+	Kind                 string // local, import, export, implementation, typeDefinition
 	Scheme               string // name of the package manager type
 	Identifier           string // unique identifier
 	PackageInformationID ID     // possibly empty
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS gitserver_rebalances (
+    id              SERIAL PRIMARY KEY,
+    source_addrs    TEXT[] NOT NULL,
+    source_sharding TEXT NOT NULL,
+    dest_addrs      TEXT[] NOT NULL,
+    dest_sharding   TEXT NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at     TIMESTAMP WITH TIME ZONE
+);
+
+COMMENT ON TABLE gitserver_rebalances IS 'Tracks changes of the gitserver shard layout. Requests are routed with the source layout until the repositories that move to another shard have been cloned there.';
+COMMENT ON COLUMN gitserver_rebalances.source_sharding IS 'The hashing scheme of the source layout, either modulo or rendezvous.';
+COMMENT ON COLUMN gitserver_rebalances.finished_at IS 'Set once all moved repositories are cloned on their new shard, after which requests are routed with the destination layout.';
@@ -0,0 +1,2 @@
+ALTER TABLE batch_changes DROP COLUMN IF EXISTS scheduled_batch_spec_id;
+ALTER TABLE batch_changes DROP COLUMN IF EXISTS last_scheduled_at;
@@ -0,0 +1,5 @@
+ALTER TABLE batch_changes ADD COLUMN IF NOT EXISTS last_scheduled_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE batch_changes ADD COLUMN IF NOT EXISTS scheduled_batch_spec_id BIGINT REFERENCES batch_specs(id) ON DELETE SET NULL DEFERRABLE;
+
+COMMENT ON COLUMN batch_changes.last_scheduled_at IS 'The time the last scheduled run of the batch change was started. Only set for batch changes whose batch spec has a schedule.';
+COMMENT ON COLUMN batch_changes.scheduled_batch_spec_id IS 'The batch spec of the scheduled run that is currently being resolved and executed. It is applied to the batch change once its execution completes.';
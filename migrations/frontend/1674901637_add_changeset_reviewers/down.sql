@@ -0,0 +1,2 @@
+ALTER TABLE changesets DROP COLUMN IF EXISTS requested_reviewers;
+ALTER TABLE changeset_specs DROP COLUMN IF EXISTS reviewers;
@@ -0,0 +1,5 @@
+ALTER TABLE changeset_specs ADD COLUMN IF NOT EXISTS reviewers JSONB;
+ALTER TABLE changesets ADD COLUMN IF NOT EXISTS requested_reviewers TEXT[] NOT NULL DEFAULT '{}'::TEXT[];
+
+COMMENT ON COLUMN changeset_specs.reviewers IS 'The reviewers configuration from the changeset template: explicit users and teams, and whether reviewers should be derived from the repository''s CODEOWNERS file.';
+COMMENT ON COLUMN changesets.requested_reviewers IS 'The users and teams that review was requested from on the code host after the changeset was published.';
@@ -0,0 +1 @@
+ALTER TABLE changesets DROP COLUMN IF EXISTS blocked_by_dependencies;
@@ -0,0 +1,3 @@
+ALTER TABLE changesets ADD COLUMN IF NOT EXISTS blocked_by_dependencies BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN changesets.blocked_by_dependencies IS 'Whether the changeset is held back as a draft or unpublished until the changesets it depends on have been merged.';
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS scim_users (
+    user_id      INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    user_name    CITEXT NOT NULL,
+    external_id  TEXT NOT NULL DEFAULT '',
+    display_name CITEXT NOT NULL DEFAULT '',
+    emails       CITEXT[] NOT NULL DEFAULT '{}',
+    active       BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS scim_users_user_name ON scim_users (user_name);
+
+COMMENT ON TABLE scim_users IS 'The users provisioned through SCIM. The full SCIM resource is stored encrypted in the SCIM external account of the user; this table holds the attributes that SCIM filters can be evaluated against.';
+COMMENT ON COLUMN scim_users.user_name IS 'The SCIM userName, which is unique among provisioned users. It can differ from the username of the user, which is normalized.';
@@ -0,0 +1,2 @@
+ALTER TABLE changesets DROP COLUMN IF EXISTS last_merge_attempt_at;
+ALTER TABLE changesets DROP COLUMN IF EXISTS merge_failure_message;
@@ -0,0 +1,5 @@
+ALTER TABLE changesets ADD COLUMN IF NOT EXISTS last_merge_attempt_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE changesets ADD COLUMN IF NOT EXISTS merge_failure_message TEXT;
+
+COMMENT ON COLUMN changesets.last_merge_attempt_at IS 'The time the changeset was last merged automatically, whether or not the code host merged it.';
+COMMENT ON COLUMN changesets.merge_failure_message IS 'The reason the code host refused to merge the changeset automatically, if it did.';
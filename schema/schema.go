@@ -119,6 +119,7 @@ type AuthProviders struct {
 	Github         *GitHubAuthProvider
 	Gitlab         *GitLabAuthProvider
 	Bitbucketcloud *BitbucketCloudAuthProvider
+	Ldap           *LDAPAuthProvider
 }
 
 func (v AuthProviders) MarshalJSON() ([]byte, error) {
@@ -143,6 +144,9 @@ func (v AuthProviders) MarshalJSON() ([]byte, error) {
 	if v.Bitbucketcloud != nil {
 		return json.Marshal(v.Bitbucketcloud)
 	}
+	if v.Ldap != nil {
+		return json.Marshal(v.Ldap)
+	}
 	return nil, errors.New("tagged union type must have exactly 1 non-nil field value")
 }
 func (v *AuthProviders) UnmarshalJSON(data []byte) error {
@@ -163,16 +167,30 @@ func (v *AuthProviders) UnmarshalJSON(data []byte) error {
 		return json.Unmarshal(data, &v.Gitlab)
 	case "http-header":
 		return json.Unmarshal(data, &v.HttpHeader)
+	case "ldap":
+		return json.Unmarshal(data, &v.Ldap)
 	case "openidconnect":
 		return json.Unmarshal(data, &v.Openidconnect)
 	case "saml":
 		return json.Unmarshal(data, &v.Saml)
 	}
-	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab", "bitbucketcloud"})
+	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab", "bitbucketcloud", "ldap"})
+}
+
+// AutoMerge description: Automatically merge the changesets of the batch change once all of their checks passed, they have been approved and the code host considers them mergeable. Merges are subject to the rollout windows configured on the instance.
+type AutoMerge struct {
+	// Strategy description: The strategy used to merge the changesets. On GitLab, merge uses the merge method configured for the project. GitLab and Bitbucket Cloud don't support rebase. On Bitbucket Server, the strategy has to be enabled for the repository.
+	Strategy string `json:"strategy"`
+}
+
+// AzureDevOpsAuthorization description: If non-null, enforces Azure DevOps repository permissions. Users are matched to Azure DevOps identities by their verified email addresses. Permissions are synced with the user's OAuth token if their account has one, and with the token of this connection otherwise, which must have the "Identity (Read)" and "Security (Manage)" scopes.
+type AzureDevOpsAuthorization struct {
 }
 
 // AzureDevOpsConnection description: Configuration for a connection to Azure DevOps.
 type AzureDevOpsConnection struct {
+	// Authorization description: If non-null, enforces Azure DevOps repository permissions. Users are matched to Azure DevOps identities by their verified email addresses. Permissions are synced with the user's OAuth token if their account has one, and with the token of this connection otherwise, which must have the "Identity (Read)" and "Security (Manage)" scopes.
+	Authorization *AzureDevOpsAuthorization `json:"authorization,omitempty"`
 	// Exclude description: A list of repositories to never mirror from this Azure DevOps Services/Server instance.
 	Exclude []*ExcludedAzureDevOpsServerRepo `json:"exclude,omitempty"`
 	// Orgs description: An array of organization names identifying Azure DevOps organizations whose repositories should be mirrored on Sourcegraph.
@@ -214,10 +232,26 @@ type BatchChangeRolloutWindow struct {
 	Start string `json:"start,omitempty"`
 }
 
+// BatchChangesPolicies description: Policies that the changesets of all batch changes have to satisfy before they are published or updated on the code host. Changesets violating a policy aren't published, and the violations are reported on the changeset.
+type BatchChangesPolicies struct {
+	// ForbiddenPaths description: Glob patterns of file paths that changesets must not touch, matched against the full path of each file in the diff. `*` matches within a single directory, `**` matches across directories.
+	ForbiddenPaths []string `json:"forbiddenPaths,omitempty"`
+	// MaxChangedLines description: The maximum number of lines a changeset may add and delete in total.
+	MaxChangedLines int `json:"maxChangedLines,omitempty"`
+	// ProtectedBranches description: Glob patterns of branch names that changesets must not be pushed to. Batch changes force-push the changeset branch, so this prevents overwriting important branches.
+	ProtectedBranches []string `json:"protectedBranches,omitempty"`
+	// RequiredTitlePrefix description: A prefix the title of every changeset has to start with.
+	RequiredTitlePrefix string `json:"requiredTitlePrefix,omitempty"`
+}
+
 // BatchSpec description: A batch specification, which describes the batch change and what kinds of changes to make (or what existing changesets to track).
 type BatchSpec struct {
+	// AutoMerge description: Automatically merge the changesets of the batch change once all of their checks passed, they have been approved and the code host considers them mergeable. Merges are subject to the rollout windows configured on the instance.
+	AutoMerge *AutoMerge `json:"autoMerge,omitempty"`
 	// ChangesetTemplate description: A template describing how to create (and update) changesets with the file changes produced by the command steps.
 	ChangesetTemplate *ChangesetTemplate `json:"changesetTemplate,omitempty"`
+	// Dependencies description: Dependencies between the changesets of the batch change. The changesets of a repository, or of a single workspace in it, are kept unpublished, or published as drafts on code hosts that support them, until the changesets they depend on have been merged.
+	Dependencies []*ChangesetDependency `json:"dependencies,omitempty"`
 	// Description description: The description of the batch change.
 	Description string `json:"description,omitempty"`
 	// ImportChangesets description: Import existing changesets on code hosts.
@@ -226,6 +260,8 @@ type BatchSpec struct {
 	Name string `json:"name"`
 	// On description: The set of repositories (and branches) to run the batch change on, specified as a list of search queries (that match repositories) and/or specific repositories.
 	On []any `json:"on,omitempty"`
+	// Schedule description: How often the batch change is re-run after it has been applied, as a duration such as 24h. Each run re-resolves the workspaces matched by the `on` property, executes the steps server-side in new or changed workspaces only, and applies the result, so that changesets are created for newly matching repositories and closed for repositories that no longer match. The minimum interval is 1h. Requires server-side execution.
+	Schedule string `json:"schedule,omitempty"`
 	// Steps description: The sequence of commands to run (for each repository branch matched in the `on` property) to produce the workspace changes that will be included in the batch change.
 	Steps []*Step `json:"steps,omitempty"`
 	// TransformChanges description: Optional transformations to apply to the changes produced in each repository.
@@ -441,6 +477,8 @@ type BranchChangesetSpec struct {
 	HeadRepository string `json:"headRepository"`
 	// Published description: Whether to publish the changeset. An unpublished changeset can be previewed on Sourcegraph by any person who can view the batch change, but its commit, branch, and pull request aren't created on the code host. A published changeset results in a commit, branch, and pull request being created on the code host.
 	Published any `json:"published,omitempty"`
+	// Reviewers description: The reviewers to request a review from once the changeset is published.
+	Reviewers *ChangesetReviewers `json:"reviewers,omitempty"`
 	// Title description: The title of the changeset on the code host.
 	Title string `json:"title"`
 	// Version description: A field for versioning the payload.
@@ -475,6 +513,24 @@ type BuiltinAuthProvider struct {
 	AllowSignup bool   `json:"allowSignup,omitempty"`
 	Type        string `json:"type"`
 }
+type ChangesetDependency struct {
+	// Branch description: The branch of the changeset of a single workspace in the repository that depends on others. Each workspace of a repository has its own changeset branch. If omitted, all changesets in the repository depend on others.
+	Branch string `json:"branch,omitempty"`
+	// DependsOn description: The changesets that have to be merged first.
+	DependsOn []*DependencyWorkspace `json:"dependsOn"`
+	// Repository description: The name of the repository whose changesets depend on others.
+	Repository string `json:"repository"`
+}
+
+// ChangesetReviewers description: The reviewers to request a review from once the changeset is published.
+type ChangesetReviewers struct {
+	// FromCodeOwners description: Whether to request a review from the owners of the changed files, as defined in the CODEOWNERS file of the repository.
+	FromCodeOwners bool `json:"fromCodeOwners,omitempty"`
+	// Teams description: The teams on the code host to request a review from, as organization/team.
+	Teams []string `json:"teams,omitempty"`
+	// Users description: The usernames of the users on the code host to request a review from.
+	Users []string `json:"users,omitempty"`
+}
 
 // ChangesetTemplate description: A template describing how to create (and update) changesets with the file changes produced by the command steps.
 type ChangesetTemplate struct {
@@ -486,10 +542,22 @@ type ChangesetTemplate struct {
 	Commit ExpandedGitCommitDescription `json:"commit"`
 	// Published description: Whether to publish the changeset. An unpublished changeset can be previewed on Sourcegraph by any person who can view the batch change, but its commit, branch, and pull request aren't created on the code host. A published changeset results in a commit, branch, and pull request being created on the code host. If omitted, the publication state is controlled from the Batch Changes UI.
 	Published any `json:"published,omitempty"`
+	// Reviewers description: The reviewers to request a review from once the changeset is published.
+	Reviewers *ChangesetTemplateReviewers `json:"reviewers,omitempty"`
 	// Title description: The title of the changeset.
 	Title string `json:"title"`
 }
 
+// ChangesetTemplateReviewers description: The reviewers to request a review from once the changeset is published.
+type ChangesetTemplateReviewers struct {
+	// FromCodeOwners description: Whether to request a review from the owners of the changed files, as defined in the CODEOWNERS file of the repository.
+	FromCodeOwners bool `json:"fromCodeOwners,omitempty"`
+	// Teams description: The teams on the code host to request a review from, as organization/team.
+	Teams []string `json:"teams,omitempty"`
+	// Users description: The usernames of the users on the code host to request a review from.
+	Users []string `json:"users,omitempty"`
+}
+
 // CloneURLToRepositoryName description: Describes a mapping from clone URL to repository name. The `from` field contains a regular expression with named capturing groups. The `to` field contains a template string that references capturing group names. For instance, if `from` is "^../(?P<name>\w+)$" and `to` is "github.com/user/{name}", the clone URL "../myRepository" would be mapped to the repository name "github.com/user/myRepository".
 type CloneURLToRepositoryName struct {
 	// From description: A regular expression that matches a set of clone URLs. The regular expression should use the Go regular expression syntax (https://golang.org/pkg/regexp/) and contain at least one named capturing group. The regular expression matches partially by default, so use "^...$" if whole-string matching is desired.
@@ -524,6 +592,20 @@ type DebugLog struct {
 	// ExtsvcGitlab description: Log GitLab API requests.
 	ExtsvcGitlab bool `json:"extsvc.gitlab,omitempty"`
 }
+type DependencyWorkspace struct {
+	// Branch description: The branch of the changeset of a single workspace in the repository that has to be merged first. If omitted, all changesets in the repository have to be merged first.
+	Branch string `json:"branch,omitempty"`
+	// Repository description: The name of the repository whose changesets have to be merged first.
+	Repository string `json:"repository"`
+}
+
+// DiskQuota description: Limits on the disk space gitserver uses for the repositories of a code host connection.
+type DiskQuota struct {
+	// PerRepo description: The maximum size on disk of each repository synced by the connection, in bytes with an optional k, m, g or t suffix. Repositories that are larger are not cloned, and are no longer fetched once they outgrow it.
+	PerRepo string `json:"perRepo,omitempty"`
+	// Total description: The maximum total size on disk of the repositories synced by the connection, in bytes with an optional k, m, g or t suffix. Once it is reached, gitserver stops cloning and fetching the repositories of the connection and reports an error on them.
+	Total string `json:"total,omitempty"`
+}
 
 // Dotcom description: Configuration options for Sourcegraph.com only.
 type Dotcom struct {
@@ -532,6 +614,24 @@ type Dotcom struct {
 	// SrcCliVersionCache description: Configuration related to the src-cli version cache. This should only be used on sourcegraph.com.
 	SrcCliVersionCache *SrcCliVersionCache `json:"srcCliVersionCache,omitempty"`
 }
+
+// DotnetPackagesConnection description: Configuration for a connection to .NET packages hosted on a NuGet feed
+type DotnetPackagesConnection struct {
+	// Dependencies description: An array of strings specifying NuGet packages to mirror in Sourcegraph.
+	Dependencies []string `json:"dependencies,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to the configured NuGet feed.
+	RateLimit *DotnetRateLimit `json:"rateLimit,omitempty"`
+	// Repository description: The URL of the NuGet V3 service index of the feed. Credentials for private feeds can be included in the URL.
+	Repository string `json:"repository,omitempty"`
+}
+
+// DotnetRateLimit description: Rate limit applied when making background API requests to the configured NuGet feed.
+type DotnetRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 100, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 100 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
 type EmailTemplate struct {
 	// Html description: Template for HTML body
 	Html string `json:"html"`
@@ -685,6 +785,10 @@ type ExperimentalFeatures struct {
 	CustomGitFetch []*CustomGitFetchMapping `json:"customGitFetch,omitempty"`
 	// DebugLog description: Turns on debug logging for specific debugging scenarios.
 	DebugLog *DebugLog `json:"debug.log,omitempty"`
+	// DotnetPackages description: Allow adding .NET (NuGet) package host connections
+	DotnetPackages string `json:"dotnetPackages,omitempty"`
+	// EnableGitServerGRPC description: Use gRPC instead of HTTP for exec, archive, search and batch log requests to gitserver.
+	EnableGitServerGRPC bool `json:"enableGitServerGRPC,omitempty"`
 	// EnableGithubInternalRepoVisibility description: Enable support for visibility of internal Github repositories
 	EnableGithubInternalRepoVisibility bool `json:"enableGithubInternalRepoVisibility,omitempty"`
 	// EnableLegacyExtensions description: Enable the extension registry and the use of extensions (doesn't affect code intel and git extras).
@@ -697,8 +801,14 @@ type ExperimentalFeatures struct {
 	EventLogging string `json:"eventLogging,omitempty"`
 	// Gerrit description: Allow adding Gerrit code host connections
 	Gerrit string `json:"gerrit,omitempty"`
+	// GitServerPartialClones description: Repositories that gitserver clones as partial clones, which skip blobs larger than a size limit until they are needed. Keys are repository names or glob patterns such as "github.com/foo/*", values are the size limit in bytes with an optional k, m or g suffix. Missing blobs are fetched from the code host on demand, so reading them is slower the first time. Use this for very large repositories whose history contains big binary files.
+	GitServerPartialClones map[string]string `json:"gitServerPartialClones,omitempty"`
 	// GitServerPinnedRepos description: List of repositories pinned to specific gitserver instances. The specified repositories will remain at their pinned servers on scaling the cluster. If the specified pinned server differs from the current server that stores the repository, then it must be re-cloned to the specified server.
 	GitServerPinnedRepos map[string]string `json:"gitServerPinnedRepos,omitempty"`
+	// GitServerReplicatedRepos description: Replication factors of repositories that should be served by more than one gitserver instance. Keys are repository names or glob patterns such as "github.com/foo/*", values are the total number of gitserver instances holding a copy of the repository. Replicas fetch from the gitserver instance that owns the repository and serve read requests such as archives, searches and read-only git commands. Writes are always sent to the owning instance.
+	GitServerReplicatedRepos map[string]int `json:"gitServerReplicatedRepos,omitempty"`
+	// GitServerSharding description: The hashing scheme used to assign repositories to gitserver instances. With "modulo", adding or removing an instance moves almost every repository to another instance. With "rendezvous", only the repositories that the added or removed instance owns are moved. Changing the scheme or the set of instances starts a rebalance in the worker: moved repositories are cloned from their current instance to their new one, which keeps serving them until all clones are done.
+	GitServerSharding string `json:"gitServerSharding,omitempty"`
 	// GoPackages description: Allow adding Go package host connections
 	GoPackages string `json:"goPackages,omitempty"`
 	// InsightsAlternateLoadingStrategy description: Use an in-memory strategy of loading Code Insights. Should only be used for benchmarking on large instances, not for customer use currently.
@@ -717,6 +827,8 @@ type ExperimentalFeatures struct {
 	PasswordPolicy *PasswordPolicy `json:"passwordPolicy,omitempty"`
 	// Perforce description: Allow adding Perforce code host connections
 	Perforce string `json:"perforce,omitempty"`
+	// PhpPackages description: Allow adding PHP (Composer) package host connections
+	PhpPackages string `json:"phpPackages,omitempty"`
 	// PythonPackages description: Allow adding Python package code host connections
 	PythonPackages string `json:"pythonPackages,omitempty"`
 	// Ranking description: Experimental search result ranking options.
@@ -738,6 +850,8 @@ type ExperimentalFeatures struct {
 	// StructuralSearch description: Enables structural search.
 	StructuralSearch   string              `json:"structuralSearch,omitempty"`
 	SubRepoPermissions *SubRepoPermissions `json:"subRepoPermissions,omitempty"`
+	// Subversion description: Allow adding Subversion code host connections
+	Subversion string `json:"subversion,omitempty"`
 	// TlsExternal description: Global TLS/SSL settings for Sourcegraph to use when communicating with code hosts.
 	TlsExternal *TlsExternal   `json:"tls.external,omitempty"`
 	Additional  map[string]any `json:"-"` // additionalProperties not explicitly defined in the schema
@@ -777,13 +891,18 @@ func (v *ExperimentalFeatures) UnmarshalJSON(data []byte) error {
 	delete(m, "bitbucketServerFastPerm")
 	delete(m, "customGitFetch")
 	delete(m, "debug.log")
+	delete(m, "dotnetPackages")
+	delete(m, "enableGitServerGRPC")
 	delete(m, "enableGithubInternalRepoVisibility")
 	delete(m, "enableLegacyExtensions")
 	delete(m, "enablePermissionsWebhooks")
 	delete(m, "enablePostSignupFlow")
 	delete(m, "eventLogging")
 	delete(m, "gerrit")
+	delete(m, "gitServerPartialClones")
 	delete(m, "gitServerPinnedRepos")
+	delete(m, "gitServerReplicatedRepos")
+	delete(m, "gitServerSharding")
 	delete(m, "goPackages")
 	delete(m, "insightsAlternateLoadingStrategy")
 	delete(m, "insightsBackfillerV2")
@@ -793,6 +912,7 @@ func (v *ExperimentalFeatures) UnmarshalJSON(data []byte) error {
 	delete(m, "pagure")
 	delete(m, "passwordPolicy")
 	delete(m, "perforce")
+	delete(m, "phpPackages")
 	delete(m, "pythonPackages")
 	delete(m, "ranking")
 	delete(m, "rateLimitAnonymous")
@@ -804,6 +924,7 @@ func (v *ExperimentalFeatures) UnmarshalJSON(data []byte) error {
 	delete(m, "search.sanitization")
 	delete(m, "structuralSearch")
 	delete(m, "subRepoPermissions")
+	delete(m, "subversion")
 	delete(m, "tls.external")
 	if len(m) > 0 {
 		v.Additional = make(map[string]any, len(m))
@@ -1271,6 +1392,56 @@ type JVMPackagesConnection struct {
 	Maven *Maven `json:"maven,omitempty"`
 }
 
+// LDAPAttributes description: The LDAP attributes that are mapped to Sourcegraph user properties.
+type LDAPAttributes struct {
+	// DisplayName description: The attribute holding the display name.
+	DisplayName string `json:"displayName,omitempty"`
+	// Email description: The attribute holding the email address.
+	Email string `json:"email,omitempty"`
+	// Username description: The attribute holding the username.
+	Username string `json:"username,omitempty"`
+}
+
+// LDAPAuthProvider description: Configures the LDAP authentication provider, which signs in users by binding to an LDAP directory (such as OpenLDAP or Active Directory) with the username and password they enter on the sign-in page.
+type LDAPAuthProvider struct {
+	// AllowGroups description: Restrict login to members of these groups (group DNs). If empty, all users found by `userFilter` can sign in.
+	AllowGroups []string `json:"allowGroups,omitempty"`
+	// AllowSignup description: Allows new visitors to sign up for accounts via LDAP authentication. If false, users signing in via LDAP must have an existing Sourcegraph account, which will be linked to their LDAP identity after sign-in.
+	AllowSignup *bool `json:"allowSignup,omitempty"`
+	// Attributes description: The LDAP attributes that are mapped to Sourcegraph user properties.
+	Attributes *LDAPAttributes `json:"attributes,omitempty"`
+	// BaseDN description: The DN under which users are searched for.
+	BaseDN string `json:"baseDN"`
+	// BindDN description: The DN of the service account used to search for users. If empty, the search is performed anonymously.
+	BindDN string `json:"bindDN,omitempty"`
+	// BindPassword description: The password of the service account specified in `bindDN`.
+	BindPassword string `json:"bindPassword,omitempty"`
+	// ConfigID description: An identifier that can be used to reference this authentication provider in other parts of the config. For example, in configuration for a code host, you may want to designate this authentication provider as the identity provider for the code host.
+	ConfigID    string `json:"configID,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	// GroupAttribute description: The attribute on the user entry that lists the DNs of the groups the user belongs to.
+	GroupAttribute string `json:"groupAttribute,omitempty"`
+	// GroupMappings description: Maps LDAP groups to Sourcegraph organizations and roles. On every sign-in, the user is added to the organizations and roles of the groups they belong to, and removed from the mapped organizations and roles of the groups they no longer belong to. Organizations and roles must already exist.
+	GroupMappings []*LDAPGroupMapping `json:"groupMappings,omitempty"`
+	// InsecureSkipVerify description: Skip verification of the LDAP server's TLS certificate. Only use this for testing.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// StartTLS description: Upgrade the connection to TLS with the StartTLS extended operation before binding. Only applies to ldap:// URLs.
+	StartTLS bool   `json:"startTLS,omitempty"`
+	Type     string `json:"type"`
+	// Url description: The URL of the LDAP server. Use the ldaps:// scheme for LDAP over TLS, or the ldap:// scheme together with `startTLS`.
+	Url string `json:"url"`
+	// UserFilter description: The LDAP filter used to find the user signing in. The placeholder {username} is replaced with the (escaped) username entered on the sign-in page.
+	UserFilter string `json:"userFilter,omitempty"`
+}
+type LDAPGroupMapping struct {
+	// Group description: The DN of the LDAP group (case-insensitive).
+	Group string `json:"group"`
+	// Orgs description: The names of the Sourcegraph organizations that members of the group belong to.
+	Orgs []string `json:"orgs,omitempty"`
+	// Roles description: The names of the Sourcegraph roles that members of the group are assigned.
+	Roles []string `json:"roles,omitempty"`
+}
+
 // Log description: Configuration for logging and alerting, including to external services.
 type Log struct {
 	// AuditLog description: EXPERIMENTAL: Configuration for audit logging (specially formatted log entries for tracking sensitive events)
@@ -1561,6 +1732,24 @@ type Overrides struct {
 	Limit any `json:"limit,omitempty"`
 }
 
+// PHPPackagesConnection description: Configuration for a connection to PHP packages hosted on a Composer repository such as Packagist
+type PHPPackagesConnection struct {
+	// Dependencies description: An array of strings specifying Composer packages to mirror in Sourcegraph, in the form `vendor/package:version`.
+	Dependencies []string `json:"dependencies,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to the configured Composer repository.
+	RateLimit *PHPRateLimit `json:"rateLimit,omitempty"`
+	// Repository description: The URL of the Composer repository. Credentials for private repositories can be included in the URL.
+	Repository string `json:"repository,omitempty"`
+}
+
+// PHPRateLimit description: Rate limit applied when making background API requests to the configured Composer repository.
+type PHPRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 100, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 100 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+
 // PagureConnection description: Configuration for a connection to Pagure.
 type PagureConnection struct {
 	// Forks description: If true, it includes forks in the returned projects.
@@ -1832,6 +2021,14 @@ type SMTPServerConfig struct {
 	// Username description: The username to use when communicating with the SMTP server.
 	Username string `json:"username,omitempty"`
 }
+
+// Scim description: Configures the SCIM 2.0 provisioning endpoint at /.api/scim/v2, which lets an identity provider (such as Okta or Azure AD) create, update and deactivate users and manage their group memberships. The endpoint is disabled when this is not set.
+type Scim struct {
+	// AuthToken description: The bearer token the identity provider must send in the Authorization header of every SCIM request. Generate a long random value and configure the same value in the identity provider.
+	AuthToken string `json:"authToken"`
+	// GroupsMapTo description: What SCIM groups are provisioned as. With "orgs", each group is an organization and its members are the organization's members. With "roles", each group is a (non-system) role and its members are the users assigned to the role.
+	GroupsMapTo string `json:"groupsMapTo,omitempty"`
+}
 type SearchIndexRevisionsRule struct {
 	// Name description: Regular expression which matches against the name of a repository (e.g. "^github\.com/owner/name$").
 	Name string `json:"name,omitempty"`
@@ -2322,6 +2519,8 @@ type SiteConfiguration struct {
 	BatchChangesEnabled *bool `json:"batchChanges.enabled,omitempty"`
 	// BatchChangesEnforceForks description: When enabled, all branches created by batch changes will be pushed to forks of the original repository.
 	BatchChangesEnforceForks bool `json:"batchChanges.enforceForks,omitempty"`
+	// BatchChangesPolicies description: Policies that the changesets of all batch changes have to satisfy before they are published or updated on the code host. Changesets violating a policy aren't published, and the violations are reported on the changeset.
+	BatchChangesPolicies *BatchChangesPolicies `json:"batchChanges.policies,omitempty"`
 	// BatchChangesRestrictToAdmins description: When enabled, only site admins can create and apply batch changes.
 	BatchChangesRestrictToAdmins *bool `json:"batchChanges.restrictToAdmins,omitempty"`
 	// BatchChangesRolloutWindows description: Specifies specific windows, which can have associated rate limits, to be used when publishing changesets. All days and times are handled in UTC.
@@ -2332,6 +2531,8 @@ type SiteConfiguration struct {
 	Branding *Branding `json:"branding,omitempty"`
 	// CloneProgressLog description: Whether clone progress should be logged to a file. If enabled, logs are written to files in the OS default path for temporary files.
 	CloneProgressLog bool `json:"cloneProgress.log,omitempty"`
+	// CodeHostDiskQuotas description: Limits on the disk space gitserver uses for the repositories of code host connections. Keys are the numeric IDs of code host connections, which appear as `externalServiceID` in their webhook URLs. Repositories over a limit are not cloned or fetched and report an error.
+	CodeHostDiskQuotas map[string]DiskQuota `json:"codeHostDiskQuotas,omitempty"`
 	// CodeIntelAutoIndexingAllowGlobalPolicies description: Whether auto-indexing policies may apply to all repositories on the Sourcegraph instance. Default is false. The policyRepositoryMatchLimit setting still applies to such auto-indexing policies.
 	CodeIntelAutoIndexingAllowGlobalPolicies *bool `json:"codeIntelAutoIndexing.allowGlobalPolicies,omitempty"`
 	// CodeIntelAutoIndexingEnabled description: Enables/disables the code intel auto-indexing feature. Currently experimental.
@@ -2483,6 +2684,8 @@ type SiteConfiguration struct {
 	RepoListUpdateInterval int `json:"repoListUpdateInterval,omitempty"`
 	// RepoPurgeWorker description: Configuration for repository purge worker.
 	RepoPurgeWorker *RepoPurgeWorker `json:"repoPurgeWorker,omitempty"`
+	// Scim description: Configures the SCIM 2.0 provisioning endpoint at /.api/scim/v2, which lets an identity provider (such as Okta or Azure AD) create, update and deactivate users and manage their group memberships. The endpoint is disabled when this is not set.
+	Scim *Scim `json:"scim,omitempty"`
 	// SearchIndexSymbolsEnabled description: Whether indexed symbol search is enabled. This is contingent on the indexed search configuration, and is true by default for instances with indexed search enabled. Enabling this will cause every repository to re-index, which is a time consuming (several hours) operation. Additionally, it requires more storage and ram to accommodate the added symbols information in the search index.
 	SearchIndexSymbolsEnabled *bool `json:"search.index.symbols.enabled,omitempty"`
 	// SearchLargeFiles description: A list of file glob patterns where matching files will be indexed and searched regardless of their size. Files still need to be valid utf-8 to be indexed. The glob pattern syntax can be found here: https://github.com/bmatcuk/doublestar#patterns.
@@ -2549,10 +2752,12 @@ func (v *SiteConfiguration) UnmarshalJSON(data []byte) error {
 	delete(m, "batchChanges.disableWebhooksWarning")
 	delete(m, "batchChanges.enabled")
 	delete(m, "batchChanges.enforceForks")
+	delete(m, "batchChanges.policies")
 	delete(m, "batchChanges.restrictToAdmins")
 	delete(m, "batchChanges.rolloutWindows")
 	delete(m, "branding")
 	delete(m, "cloneProgress.log")
+	delete(m, "codeHostDiskQuotas")
 	delete(m, "codeIntelAutoIndexing.allowGlobalPolicies")
 	delete(m, "codeIntelAutoIndexing.enabled")
 	delete(m, "codeIntelAutoIndexing.indexerMap")
@@ -2628,6 +2833,7 @@ func (v *SiteConfiguration) UnmarshalJSON(data []byte) error {
 	delete(m, "repoConcurrentExternalServiceSyncers")
 	delete(m, "repoListUpdateInterval")
 	delete(m, "repoPurgeWorker")
+	delete(m, "scim")
 	delete(m, "search.index.symbols.enabled")
 	delete(m, "search.largeFiles")
 	delete(m, "search.limits")
@@ -2679,6 +2885,34 @@ type SubRepoPermissions struct {
 	UserCacheTTLSeconds int `json:"userCacheTTLSeconds,omitempty"`
 }
 
+// SubversionConnection description: Configuration for a connection to a Subversion server. Subversion repositories are mirrored into Git using `git svn`.
+type SubversionConnection struct {
+	// AuthorsFile description: Contents of a git svn authors file mapping Subversion usernames to Git authors, one mapping per line in the form `svnuser = Full Name <email@example.org>`.
+	AuthorsFile string `json:"authorsFile,omitempty"`
+	// Branches description: Path of the branches directory relative to the repository root. Only used when layout is "custom".
+	Branches string `json:"branches,omitempty"`
+	// Layout description: The layout of the Subversion repositories. "standard" imports the trunk, branches and tags directories as Git branches and tags (git svn --stdlayout). "custom" uses the trunk, branches and tags paths configured below. "none" imports the repository root as a single branch.
+	Layout string `json:"layout,omitempty"`
+	// Password description: The password used to authenticate against the Subversion server.
+	Password string `json:"password,omitempty"`
+	// Repos description: The list of repository paths, relative to `url`, to be mirrored.
+	Repos []string `json:"repos"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Subversion repository. In the pattern, the variable "{host}" is replaced with the Subversion server's hostname, and "{repo}" is replaced with the repository path taken from the `repos` field.
+	//
+	// For example, if your Subversion server is https://svn.example.org/repos and `repos` contains the value "firmware", then a repositoryPathPattern of "svn/{repo}" would mean that the repository is available on Sourcegraph at https://sourcegraph.example.com/svn/firmware.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this Subversion server. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Tags description: Path of the tags directory relative to the repository root. Only used when layout is "custom".
+	Tags string `json:"tags,omitempty"`
+	// Trunk description: Path of the trunk directory relative to the repository root. Only used when layout is "custom".
+	Trunk string `json:"trunk,omitempty"`
+	// Url description: The base URL of the Subversion server. Repository paths listed in `repos` are resolved relative to this URL.
+	Url string `json:"url"`
+	// Username description: The username used to authenticate against the Subversion server.
+	Username string `json:"username,omitempty"`
+}
+
 // SyntaxHighlighting description: Syntax highlighting configuration
 type SyntaxHighlighting struct {
 	Engine    SyntaxHighlightingEngine   `json:"engine"`
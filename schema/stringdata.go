@@ -70,6 +70,12 @@ var RustPackagesSchemaJSON string
 //go:embed ruby-packages.schema.json
 var RubyPackagesSchemaJSON string
 
+//go:embed dotnet-packages.schema.json
+var DotnetPackagesSchemaJSON string
+
+//go:embed php-packages.schema.json
+var PHPPackagesSchemaJSON string
+
 // OtherExternalServiceSchemaJSON is the content of the file "other_external_service.schema.json".
 //
 //go:embed other_external_service.schema.json
@@ -90,6 +96,11 @@ var PhabricatorSchemaJSON string
 //go:embed pagure.schema.json
 var PagureSchemaJSON string
 
+// SubversionSchemaJSON is the content of the file "subversion.schema.json".
+//
+//go:embed subversion.schema.json
+var SubversionSchemaJSON string
+
 // SettingsSchemaJSON is the content of the file "settings.schema.json".
 //
 //go:embed settings.schema.json